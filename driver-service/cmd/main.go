@@ -2,56 +2,488 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	// Earnings days follow a time zone, and the runtime image has no zone
+	// database
+	_ "time/tzdata"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/redis/go-redis/v9"
 
+	"github.com/taxihub/driver-service/internal/adminui"
+	"github.com/taxihub/driver-service/internal/alerting"
+	"github.com/taxihub/driver-service/internal/anomaly"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/chaos"
 	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/deprecation"
+	"github.com/taxihub/driver-service/internal/dispatch"
+	"github.com/taxihub/driver-service/internal/drain"
+	"github.com/taxihub/driver-service/internal/events"
+	"github.com/taxihub/driver-service/internal/facedetect"
+	"github.com/taxihub/driver-service/internal/geocoding"
+	"github.com/taxihub/driver-service/internal/geoprivacy"
+	"github.com/taxihub/driver-service/internal/gpsquality"
 	"github.com/taxihub/driver-service/internal/handlers"
+	"github.com/taxihub/driver-service/internal/jobs"
+	"github.com/taxihub/driver-service/internal/live"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/mail"
+	"github.com/taxihub/driver-service/internal/metrics"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/ocr"
 	"github.com/taxihub/driver-service/internal/repository"
+	"github.com/taxihub/driver-service/internal/selftest"
 	"github.com/taxihub/driver-service/internal/service"
+	"github.com/taxihub/driver-service/internal/slo"
+	"github.com/taxihub/driver-service/internal/storage"
+	"github.com/taxihub/driver-service/internal/validationstats"
+	"github.com/taxihub/driver-service/internal/watchdog"
 )
 
 func main() {
 	// Load configuration from environment
 	cfg := config.LoadConfig()
-	log.Printf("Configuration loaded:")
-	log.Printf("  MongoDB URI: %s", cfg.MongoDBURI)
-	log.Printf("  MongoDB Database: %s", cfg.MongoDBDatabase)
-	log.Printf("  Server Port: %s", cfg.ServerPort)
+
+	// Log structured lines at LOG_LEVEL; the standard log package goes
+	// through the same handler
+	logger, err := logging.New(os.Stdout, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		log.Fatalf("Failed to configure logging: %v", err)
+	}
+	slog.SetDefault(logger)
+
+	slog.Info("Configuration loaded",
+		"mongodb_uri", cfg.MongoDBURI,
+		"mongodb_database", cfg.MongoDBDatabase,
+		"server_port", cfg.ServerPort,
+		"sandbox_api_keys", len(cfg.SandboxAPIKeys),
+		"geocoding_provider", cfg.GeocodingProvider,
+		"environment", cfg.Environment,
+	)
 
 	// Initialize database manager
 	dbManager := config.NewDatabaseManager(cfg)
 
+	// Time MongoDB commands and follow the connection pool for /metrics
+	mongoMetrics := metrics.NewMongo()
+	dbManager.AddCommandMonitor(mongoMetrics.CommandMonitor())
+	dbManager.SetPoolMonitor(mongoMetrics.PoolMonitor())
+
+	// Fault injection is only available outside production
+	var chaosInjector *chaos.Injector
+	if cfg.ChaosEnabled {
+		chaosInjector = chaos.NewInjector()
+		dbManager.AddCommandMonitor(chaosInjector.CommandMonitor())
+		slog.Warn("Chaos fault injection enabled", "environment", cfg.Environment)
+	}
+
 	// Connect to MongoDB
-	log.Println("Connecting to MongoDB...")
+	slog.Info("Connecting to MongoDB")
 	if err := dbManager.Initialize(); err != nil {
-		log.Fatalf("Failed to connect to MongoDB: %v", err)
+		fatal("Failed to connect to MongoDB", "error", err)
 	}
 	defer func() {
 		if err := dbManager.Close(); err != nil {
-			log.Printf("Error closing database connection: %v", err)
+			slog.Error("Error closing database connection", "error", err)
 		}
 	}()
-	log.Println("Successfully connected to MongoDB")
-
-	// Set up graceful shutdown for database
-	dbManager.SetupGracefulShutdown()
+	slog.Info("Successfully connected to MongoDB")
 
 	// Initialize dependencies
 	mongoDB := dbManager.GetMongoDB()
-	driverRepo := repository.NewMongoDriverRepository(mongoDB)
-	driverService := service.NewDriverService(driverRepo)
-	driverHandler := handlers.NewDriverHandler(driverService)
+	mongoDriverRepo := repository.NewMongoDriverRepository(mongoDB)
+	repositoryMetrics := metrics.NewRepository()
+	locationRejections := metrics.NewLocationRejections()
+	var strictLocations bool
+	switch cfg.LocationValidation {
+	case "strict":
+		strictLocations = true
+	case "permissive":
+		slog.Info("Location validation is permissive: failing fixes are counted, not rejected")
+	default:
+		fatal("Unknown LOCATION_VALIDATION; use strict or permissive", "location_validation", cfg.LocationValidation)
+	}
+	var redisClient *redis.Client
+	if cfg.NearbyBackend == "redis" || cfg.LiveBackplane == "redis" {
+		redisOptions, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			fatal("Invalid REDIS_URL", "error", err)
+		}
+		redisClient = redis.NewClient(redisOptions)
+		defer redisClient.Close()
+		slog.Info("Using Redis", "addr", redisOptions.Addr)
+	}
+	var driverStore repository.DriverRepository = mongoDriverRepo
+	switch cfg.NearbyBackend {
+	case "mongo":
+	case "redis":
+		driverStore = repository.NewRedisGeoDriverRepository(mongoDriverRepo, redisClient, mongoDB, repositoryMetrics)
+		slog.Info("Nearby searches use the Redis GEO set")
+	default:
+		fatal("Unknown NEARBY_BACKEND; use mongo or redis", "nearby_backend", cfg.NearbyBackend)
+	}
+	var driverRepo repository.DriverRepository = repository.NewInstrumentedDriverRepository(driverStore, repositoryMetrics)
+	driverRepo = repository.NewCoalescingDriverRepository(driverRepo, cfg.NearbyCoalesceWindow, cfg.NearbyCoalescePrecision, cfg.NearbyMaxLimit)
+	maintenanceRepo := repository.NewMongoMaintenanceRepository(mongoDB)
+	changeRequestRepo := repository.NewMongoChangeRequestRepository(mongoDB)
+	licenseRepo := repository.NewMongoLicenseRepository(mongoDB)
+	anomalyRepo := repository.NewMongoAnomalyRepository(mongoDB)
+	deviceTokenRepo := repository.NewMongoDeviceTokenRepository(mongoDB)
+	photoRepo := repository.NewMongoPhotoRepository(mongoDB)
+	licenseOverrideRepo := repository.NewMongoLicenseOverrideRepository(mongoDB)
+	dispatchPauseRepo := repository.NewMongoDispatchPauseRepository(mongoDB)
+	plateReservationRepo := repository.NewMongoPlateReservationRepository(mongoDB)
+	boostRepo := repository.NewMongoBoostRepository(mongoDB)
+	locationHistoryRepo := repository.NewMongoLocationHistoryRepository(mongoDB, cfg.LocationHistoryTTL)
+	credentialRepo := repository.NewMongoCredentialRepository(mongoDB)
+	fleetAccountRepo := repository.NewMongoFleetAccountRepository(mongoDB)
+	customFieldRepo := repository.NewMongoCustomFieldRepository(mongoDB)
+	tripRepo := repository.NewMongoTripRepository(mongoDB)
+	emailRepo := repository.NewMongoEmailRepository(mongoDB)
+	ratingRepo := repository.NewMongoRatingRepository(mongoDB)
+	ratingPromptRepo := repository.NewMongoRatingPromptRepository(mongoDB)
+	onboardingRepo := repository.NewMongoOnboardingRepository(mongoDB)
+	churnAlertRepo := repository.NewMongoChurnAlertRepository(mongoDB)
+	riderPreferencesRepo := repository.NewMongoRiderPreferencesRepository(mongoDB)
+	driverImportRepo := repository.NewMongoDriverImportRepository(mongoDB)
+	earningsRepo := repository.NewMongoEarningsRepository(mongoDB)
+	deletionCoordinator := repository.NewDeletionCoordinator(mongoDB, maintenanceRepo, changeRequestRepo, licenseRepo, anomalyRepo, deviceTokenRepo, photoRepo, licenseOverrideRepo, boostRepo, locationHistoryRepo, credentialRepo, emailRepo, ratingRepo, earningsRepo)
+
+	alertNotifier := alerting.NewNotifier(cfg.AlertWebhookURL)
+	if chaosInjector != nil {
+		alertNotifier = chaosInjector.Notifier(alertNotifier)
+	}
+	anomalyAnalyzer := anomaly.NewAnalyzer(anomaly.Config{
+		MaxSpeedKmh: cfg.AnomalyMaxSpeedKmh,
+		TeleportKm:  cfg.AnomalyTeleportKm,
+		OnlineLimit: cfg.AnomalyOnlineLimit,
+		OnlineGap:   cfg.AnomalyOnlineGap,
+		Cooldown:    cfg.AnomalyCooldown,
+		QueueSize:   cfg.AnomalyQueueSize,
+	}, anomalyRepo, alertNotifier)
+	gpsQualityTracker := gpsquality.NewTracker(gpsquality.Config{
+		Window:     cfg.GPSQualityWindow,
+		MinSamples: cfg.GPSQualityMinSamples,
+		MaxGap:     cfg.GPSQualityMaxGap,
+		PoorScore:  cfg.GPSQualityPoorScore,
+	})
+	licenseClassRequirements, err := models.NewLicenseClassRequirements(cfg.LicenseClassRequirements)
+	if err != nil {
+		fatal("Failed to configure license class requirements", "error", err)
+	}
+	licensePolicy := service.NewLicenseClassPolicy(licenseClassRequirements, licenseOverrideRepo)
+	tariffs, err := models.ParseTariffs(cfg.TaxiTariffs, cfg.TariffCurrency)
+	if err != nil {
+		fatal("Failed to configure tariffs", "error", err)
+	}
+	deprecationRules, err := deprecation.ParseRules(cfg.DeprecatedRoutes)
+	if err != nil {
+		fatal("Failed to configure deprecated routes", "error", err)
+	}
+	deprecations := deprecation.NewRegistry(deprecationRules)
+	deprecationHandler := handlers.NewDeprecationHandler(deprecations)
+	validationStats := validationstats.NewTracker()
+	validationStatsHandler := handlers.NewValidationStatsHandler(validationStats)
+	httpMetrics := metrics.NewHTTP()
+	metricsRegistry := metrics.NewRegistry()
+	metricsRegistry.Register(httpMetrics, repositoryMetrics, mongoMetrics, validationStats, locationRejections)
+	metricsHandler := handlers.NewMetricsHandler(metricsRegistry)
+	referenceHandler := handlers.NewReferenceHandler(models.NewReferenceData(licenseClassRequirements, tariffs), cfg.ReferenceMaxAge)
+	plateReservationService := service.NewPlateReservationService(plateReservationRepo, driverRepo, cfg.PlateReservationTTL)
+	liveHub := live.NewHub(cfg.LiveSubscriberBuffer)
+	var liveSessions live.SessionStore = live.NewMemorySessionStore(cfg.LiveSessionTTL)
+	var liveBackplane *live.RedisBackplane
+	switch cfg.LiveBackplane {
+	case "none":
+	case "redis":
+		liveBackplane = live.NewRedisBackplane(redisClient, liveHub, cfg.LivePresenceTTL, cfg.LiveSessionTTL)
+		liveSessions = liveBackplane
+		slog.Info("Live updates are shared between replicas through Redis", "replica_id", liveBackplane.ReplicaID())
+	default:
+		fatal("Unknown LIVE_BACKPLANE; use none or redis", "live_backplane", cfg.LiveBackplane)
+	}
+	eventProducer, err := events.NewProducer(cfg.EventProducer, cfg.KafkaBrokers, cfg.KafkaTopicPrefix)
+	if err != nil {
+		fatal("Failed to configure event producer", "error", err)
+	}
+	if eventProducer != nil {
+		defer func() {
+			if err := eventProducer.Close(); err != nil {
+				slog.Error("Error flushing events", "error", err)
+			}
+		}()
+	}
+	mailProvider, err := mail.NewProvider(cfg.MailProvider, mail.ProviderConfig{
+		From:           mail.Sender{Address: cfg.MailFrom, Name: cfg.MailFromName},
+		SMTPHost:       cfg.MailSMTPHost,
+		SMTPPort:       cfg.MailSMTPPort,
+		SMTPUsername:   cfg.MailSMTPUsername,
+		SMTPPassword:   cfg.MailSMTPPassword,
+		SendGridAPIKey: cfg.MailSendGridAPIKey,
+		SendGridURL:    cfg.MailSendGridURL,
+	})
+	if err != nil {
+		fatal("Failed to configure mail", "error", err)
+	}
+	mailTemplates, err := mail.NewTemplates()
+	if err != nil {
+		fatal("Failed to parse email templates", "error", err)
+	}
+	emailService := service.NewEmailService(emailRepo, driverRepo, mailTemplates, mailProvider, service.EmailConfig{
+		MaxAttempts:  cfg.MailMaxAttempts,
+		RetryBackoff: cfg.MailRetryBackoff,
+	})
+	emailHandler := handlers.NewEmailHandler(emailService)
+	locationPolicy := service.NewLocationPolicy(service.LocationPolicyConfig{
+		Strict:       strictLocations,
+		MaxAccuracyM: cfg.LocationMaxAccuracyM,
+		MaxAge:       cfg.LocationMaxAge,
+	}, locationRejections)
+	customFieldService := service.NewCustomFieldService(customFieldRepo)
+	onboardingService := service.NewOnboardingService(onboardingRepo)
+	onboardingHandler := handlers.NewOnboardingHandler(onboardingService)
+	driverService := service.NewDriverService(driverRepo, deletionCoordinator, service.LocationObservers{anomalyAnalyzer, liveHub, gpsQualityTracker}, licensePolicy, plateReservationService, eventProducer, locationHistoryRepo, emailService, locationPolicy, customFieldService, onboardingService)
+	earningsLocation, err := time.LoadLocation(cfg.EarningsTimezone)
+	if err != nil {
+		fatal("Failed to configure earnings time zone", "error", err)
+	}
+	earningsService := service.NewEarningsService(earningsRepo, driverRepo, liveHub, service.EarningsConfig{
+		CommissionRate: cfg.EarningsCommissionRate,
+		Currency:       cfg.TariffCurrency,
+		Location:       earningsLocation,
+	})
+	earningsHandler := handlers.NewEarningsHandler(earningsService)
+	plateReservationHandler := handlers.NewPlateReservationHandler(plateReservationService)
+	driverImportHandler := handlers.NewDriverImportHandler(service.NewDriverImportService(driverImportRepo, driverService, cfg.ImportMaxRows, cfg.ImportStageTTL))
+	// Routes limited to roles need a token, so without a secret to sign
+	// them the API could not be used
+	if cfg.AuthJWTSecret == "" {
+		fatal("AUTH_JWT_SECRET is required")
+	}
+	authSigner, err := auth.NewSigner(cfg.AuthJWTSecret, cfg.AuthIssuer)
+	if err != nil {
+		fatal("Failed to configure authentication", "error", err)
+	}
+	authService := service.NewAuthService(authSigner, credentialRepo, fleetAccountRepo, driverService, service.AuthConfig{
+		AccessTokenTTL:  cfg.AuthAccessTokenTTL,
+		RefreshTokenTTL: cfg.AuthRefreshTokenTTL,
+		Admins:          cfg.AuthAdmins,
+		Dispatchers:     cfg.AuthDispatchers,
+	})
+	authHandler := handlers.NewAuthHandler(authService)
+	fleetHandler := handlers.NewFleetHandler(service.NewFleetAccountService(fleetAccountRepo), customFieldService, driverService, earningsService)
+	sandboxServices := service.NewSandboxServices(cfg.SandboxSeed)
+
+	geocoder, err := geocoding.NewProvider(cfg.GeocodingProvider, cfg.GeocodingAPIKey, cfg.GeocodingURL, cfg.GeocodingUserAgent)
+	if err != nil {
+		fatal("Failed to configure geocoding", "error", err)
+	}
+	if geocoder != nil {
+		geocoder = geocoding.NewCachedProvider(geocoder, cfg.GeocodingCacheTTL, 10000)
+	}
+
+	geoPolicy, err := geoprivacy.NewPolicy(cfg.GeoPrecisionRules)
+	if err != nil {
+		fatal("Failed to configure geo precision", "error", err)
+	}
+
+	driverHandler := handlers.NewDriverHandler(driverService, sandboxServices, geocoder, geoPolicy, handlers.NearbyConfig{
+		MinRadiusKm:      cfg.NearbyMinRadiusKm,
+		MaxRadiusKm:      cfg.NearbyMaxRadiusKm,
+		MaxLimit:         cfg.NearbyMaxLimit,
+		BatchMaxPoints:   cfg.NearbyBatchMaxPoints,
+		BatchConcurrency: cfg.NearbyBatchConcurrency,
+	}, cfg.LocationBatchMaxPoints)
+	liveHandler := handlers.NewLiveHandler(driverService, sandboxServices, liveHub, geoPolicy, liveSessions)
+	maintenanceService := service.NewMaintenanceService(maintenanceRepo, driverRepo)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceService)
+	ratingPromptService := service.NewRatingPromptService(ratingPromptRepo, ratingRepo, eventProducer, service.RatingPromptConfig{
+		Delay:      cfg.RatingPromptDelay,
+		Reminder:   cfg.RatingPromptReminder,
+		MaxPrompts: cfg.RatingPromptMaxPrompts,
+	})
+	ratingHandler := handlers.NewRatingHandler(service.NewRatingService(ratingRepo, tripRepo, ratingPromptService))
+	ratingPromptHandler := handlers.NewRatingPromptHandler(ratingPromptService)
+	requestLogRepo := repository.NewMongoRequestLogRepository(mongoDB)
+	requestLogHandler := handlers.NewRequestLogHandler(requestLogRepo)
+	featureFlagRepo := repository.NewMongoFeatureFlagRepository(mongoDB)
+	featureService := service.NewFeatureService(featureFlagRepo, cfg.FeatureFlagCacheTTL)
+	featureHandler := handlers.NewFeatureHandler(featureService)
+	changeRequestService := service.NewChangeRequestService(changeRequestRepo, driverRepo, licensePolicy)
+	changeRequestHandler := handlers.NewChangeRequestHandler(changeRequestService)
+	publicHandler := handlers.NewPublicHandler(driverService, cfg.PublicRateLimit, cfg.PublicRateWindow)
+
+	dispatcher, err := dispatch.NewDispatcher(cfg.DispatchDefaultStrategy, cfg.DispatchFleetStrategies)
+	if err != nil {
+		fatal("Failed to configure dispatch", "error", err)
+	}
+	dispatchPauseService := service.NewDispatchPauseService(dispatchPauseRepo)
+	dispatchHandler := handlers.NewDispatchHandler(service.NewDispatchService(driverService, dispatcher, dispatchPauseService, service.NewColdStartBoost(service.ColdStartBoostConfig{
+		Duration:   cfg.DispatchBoostDuration,
+		Cap:        cfg.DispatchBoostCap,
+		MaxExtraKm: cfg.DispatchBoostMaxExtraKm,
+	}, boostRepo), gpsQualityTracker, liveHub), geoPolicy)
+	dispatchPauseHandler := handlers.NewDispatchPauseHandler(dispatchPauseService)
+	riderPreferencesService := service.NewRiderPreferencesService(riderPreferencesRepo)
+	tripService := service.NewTripService(tripRepo, driverRepo, driverService, service.NewTripMatcher(driverService, dispatcher, dispatchPauseService, liveHub, eventProducer, service.TripMatcherConfig{
+		OfferTimeout: cfg.DispatchOfferTimeout,
+		MaxAttempts:  cfg.DispatchOfferMaxAttempts,
+	}), ratingPromptService, onboardingService, riderPreferencesService, earningsService)
+	tripHandler := handlers.NewTripHandler(tripService)
+	riderHandler := handlers.NewRiderHandler(riderPreferencesService)
+
+	// Demo seeding wipes the database, so it is only available outside
+	// production
+	var demoService service.DemoService
+	if cfg.DemoEnabled {
+		demoService = service.NewDemoService(repository.NewMongoDemoRepository(mongoDB), driverService)
+		slog.Warn("Demo seeding enabled", "environment", cfg.Environment)
+	}
+
+	ocrProvider, err := ocr.NewProvider(cfg.OCRProvider, cfg.OCRURL, cfg.OCRAPIKey)
+	if err != nil {
+		fatal("Failed to configure OCR", "error", err)
+	}
+	licenseHandler := handlers.NewLicenseHandler(service.NewLicenseService(licenseRepo, driverRepo, ocrProvider, cfg.OCRConfidenceThreshold, onboardingService))
+	faceDetector, err := facedetect.NewDetector(cfg.FaceDetectionProvider, cfg.FaceDetectionURL, cfg.FaceDetectionAPIKey)
+	if err != nil {
+		fatal("Failed to configure face detection", "error", err)
+	}
+	objectStore, err := storage.New(storage.Config{
+		Backend:   cfg.StorageBackend,
+		LocalDir:  cfg.StorageLocalDir,
+		Endpoint:  cfg.StorageEndpoint,
+		Region:    cfg.StorageRegion,
+		Bucket:    cfg.StorageBucket,
+		AccessKey: cfg.StorageAccessKey,
+		SecretKey: cfg.StorageSecretKey,
+		PathStyle: cfg.StoragePathStyle,
+	})
+	if err != nil {
+		fatal("Failed to configure object storage", "error", err)
+	}
+	photoHandler := handlers.NewPhotoHandler(service.NewPhotoService(photoRepo, driverRepo, faceDetector, objectStore, cfg.PhotoMinDimension, onboardingService))
+	anomalyHandler := handlers.NewAnomalyHandler(service.NewAnomalyService(anomalyRepo))
+	gpsQualityHandler := handlers.NewGPSQualityHandler(gpsQualityTracker)
+	analyticsHandler := handlers.NewAnalyticsHandler(service.NewAvailabilityForecastService(mongoDriverRepo, locationHistoryRepo, cfg.LocationHistoryTTL))
+	if cfg.ChurnZonePrecision < models.MinChurnZonePrecision || cfg.ChurnZonePrecision > models.MaxChurnZonePrecision {
+		fatal("CHURN_ZONE_PRECISION out of range", "min", models.MinChurnZonePrecision, "max", models.MaxChurnZonePrecision)
+	}
+	churnService := service.NewChurnService(churnAlertRepo, locationHistoryRepo, mongoDriverRepo, licenseRepo, alertNotifier, service.ChurnConfig{
+		Threshold:     cfg.ChurnAlertThreshold,
+		MinDrivers:    cfg.ChurnMinDrivers,
+		ZonePrecision: cfg.ChurnZonePrecision,
+	})
+	churnHandler := handlers.NewChurnHandler(churnService)
+	driverSchemaService := service.NewDriverSchemaService(mongoDriverRepo, cfg.DriverSchemaBackfillBatchSize)
+	driverSchemaHandler := handlers.NewDriverSchemaHandler(driverSchemaService)
+	eventRoundTripNote := "the service publishes no events"
+	if eventProducer != nil {
+		eventRoundTripNote = "events are published asynchronously and not read back"
+	}
+	selfTestHandler := handlers.NewSelfTestHandler(func() *selftest.Suite {
+		// Bypass nearby coalescing so the geo query really hits MongoDB
+		checks := selftest.DriverStoreChecks(mongoDB, mongoDriverRepo)
+		checks = append(checks,
+			selftest.GeocodingCheck(geocoder),
+			selftest.Unsupported("event_round_trip", eventRoundTripNote),
+			selftest.Unsupported("cache", "the service has no external cache"),
+		)
+		return selftest.NewSuite(10*time.Second, checks...)
+	})
+	deviceHandler := handlers.NewDeviceHandler(service.NewDeviceTokenService(deviceTokenRepo, driverRepo), driverService)
+
+	sloTracker := slo.NewTracker(cfg.SLOWindow, []slo.Objective{
+		{
+			Name:   "availability",
+			Kind:   slo.KindAvailability,
+			Target: cfg.SLOAvailabilityTarget,
+		},
+		{
+			Name:             "match_latency",
+			Kind:             slo.KindLatency,
+			Target:           cfg.SLOLatencyTarget,
+			Route:            cfg.SLOLatencyRoute,
+			LatencyThreshold: cfg.SLOLatencyThreshold,
+		},
+	})
+	sloHandler := handlers.NewSLOHandler(sloTracker)
+
+	// Start background jobs under the watchdog
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	supervisor := watchdog.New(alertNotifier, cfg.WatchdogCheckInterval)
+	supervisor.Register("maintenance-reminders", cfg.MaintenanceReminderInterval+cfg.WatchdogStallTimeout,
+		jobs.Periodic("maintenance-reminders", cfg.MaintenanceReminderInterval,
+			jobs.ForEachTenant(mongoDB.TenantIDs(), jobs.MaintenanceReminders(maintenanceService))))
+	supervisor.Register("email-delivery", cfg.MailDeliveryInterval+cfg.WatchdogStallTimeout,
+		jobs.Periodic("email-delivery", cfg.MailDeliveryInterval,
+			jobs.ForEachTenant(mongoDB.TenantIDs(), jobs.DeliverEmails(emailService))))
+	supervisor.Register("trip-offers", cfg.DispatchOfferSweepInterval+cfg.WatchdogStallTimeout,
+		jobs.Periodic("trip-offers", cfg.DispatchOfferSweepInterval,
+			jobs.ForEachTenant(mongoDB.TenantIDs(), jobs.ExpireTripOffers(tripService))))
+	supervisor.Register("rating-prompts", cfg.RatingPromptSendInterval+cfg.WatchdogStallTimeout,
+		jobs.Periodic("rating-prompts", cfg.RatingPromptSendInterval,
+			jobs.ForEachTenant(mongoDB.TenantIDs(), jobs.SendRatingPrompts(ratingPromptService))))
+	supervisor.Register("driver-churn", cfg.ChurnCheckInterval+cfg.WatchdogStallTimeout,
+		jobs.Periodic("driver-churn", cfg.ChurnCheckInterval,
+			jobs.ForEachTenant(mongoDB.TenantIDs(), jobs.CheckDriverChurn(churnService))))
+	if cfg.DriverSchemaBackfillInterval > 0 {
+		supervisor.Register("driver-schema-backfill", cfg.DriverSchemaBackfillInterval+cfg.WatchdogStallTimeout,
+			jobs.Periodic("driver-schema-backfill", cfg.DriverSchemaBackfillInterval,
+				jobs.ForEachTenant(mongoDB.TenantIDs(), jobs.BackfillDriverSchema(driverSchemaService))))
+	}
+	if demoService != nil {
+		supervisor.Register("demo-drivers", cfg.DemoMoveInterval+cfg.WatchdogStallTimeout,
+			jobs.Periodic("demo-drivers", cfg.DemoMoveInterval,
+				jobs.ForEachTenant(mongoDB.TenantIDs(), jobs.MoveDemoDrivers(demoService))))
+	}
+	supervisor.Register("slo-alerts", cfg.SLOEvaluationInterval+cfg.WatchdogStallTimeout,
+		jobs.Periodic("slo-alerts", cfg.SLOEvaluationInterval, func(ctx context.Context) error {
+			return sloTracker.EvaluateAlerts(ctx, alertNotifier, cfg.SLOAlertHorizon)
+		}))
+	supervisor.Register("anomaly-analyzer", cfg.WatchdogStallTimeout, anomalyAnalyzer.Run)
+	if liveBackplane != nil {
+		supervisor.Register("live-backplane", cfg.WatchdogStallTimeout, liveBackplane.Run)
+	}
+	go supervisor.Run(jobsCtx)
+
+	// Ping MongoDB in the background; health endpoints serve the cached result
+	go dbManager.RunHealthChecks(jobsCtx, cfg.HealthCheckInterval, cfg.HealthCheckMaxBackoff)
+
+	// Ping the event broker the same way for /readyz
+	var brokerHealth *config.HealthMonitor
+	if eventProducer != nil {
+		brokerHealth = config.NewHealthMonitor(eventProducer.Ping)
+		go brokerHealth.Run(jobsCtx, cfg.HealthCheckInterval, cfg.HealthCheckMaxBackoff)
+	}
+
+	// Verify required indexes in the background; /health/ready stays 503 until done
+	indexManager := repository.NewIndexManager(mongoDB, mongoDriverRepo, maintenanceRepo, requestLogRepo, changeRequestRepo, licenseRepo, anomalyRepo, deviceTokenRepo, photoRepo, licenseOverrideRepo, dispatchPauseRepo, plateReservationRepo, locationHistoryRepo, credentialRepo, fleetAccountRepo, emailRepo, driverImportRepo, ratingRepo, customFieldRepo, tripRepo, ratingPromptRepo, onboardingRepo, churnAlertRepo, earningsRepo)
+	go indexManager.Run(jobsCtx, cfg.IndexCheckInterval)
+
+	// Each isolated tenant database gets the same per-driver indexes
+	tenantIndexes := make(map[string]*repository.IndexManager)
+	for _, tenantID := range mongoDB.TenantIDs() {
+		tenantDB, _ := mongoDB.Tenant(tenantID)
+		manager := repository.NewIndexManager(tenantDB, mongoDriverRepo, maintenanceRepo, changeRequestRepo, licenseRepo, anomalyRepo, deviceTokenRepo, photoRepo, licenseOverrideRepo, dispatchPauseRepo, plateReservationRepo, locationHistoryRepo, credentialRepo, fleetAccountRepo, emailRepo, driverImportRepo, ratingRepo, customFieldRepo, tripRepo, ratingPromptRepo, onboardingRepo, churnAlertRepo, earningsRepo)
+		tenantIndexes[tenantID] = manager
+		go manager.Run(jobsCtx, cfg.IndexCheckInterval)
+	}
+	tenantHandler := handlers.NewTenantHandler(mongoDB, tenantIndexes)
+	watchdogHandler := handlers.NewWatchdogHandler(supervisor)
 
 	// Initialize Fiber app with middleware
 	app := fiber.New(fiber.Config{
@@ -59,96 +491,887 @@ func main() {
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  60 * time.Second,
+		// Room for 5MB image uploads plus multipart overhead
+		BodyLimit:    6 << 20,
 		ErrorHandler: defaultErrorHandler,
 	})
 
 	// Add middleware
-	app.Use(recover.New()) // Recover from panics
-	app.Use(requestid.New()) // Add request ID for tracing
-	app.Use(logger.New(logger.Config{
-		Format:     "[${time}] [${id}] ${status} - ${method} ${path} ${latency}\n",
-		TimeFormat: "2006-01-02 15:04:05",
-	}))
+	drainTracker := drain.NewTracker()
+	app.Use(middleware.DrainTracking(drainTracker)) // Count in-flight requests for the shutdown report
+	app.Use(middleware.SLORecorder(sloTracker))     // Track availability and latency SLOs, including panics
+	app.Use(middleware.HTTPMetrics(httpMetrics))    // Count requests and latency per route for /metrics
+	app.Use(recover.New())                          // Recover from panics
+	app.Use(requestid.New())                        // Add request ID for tracing
+	app.Use(middleware.RequestLogger(logger))       // Log each request with a request-scoped logger
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
+		AllowMethods: "GET,POST,PUT,PATCH,DELETE,OPTIONS",
+		AllowHeaders: "Origin, Content-Type, Accept, Authorization, X-API-Key, X-Driver-ID, X-Fleet-ID, X-Tenant-ID, X-City, X-App-Version",
 	}))
+	app.Use(middleware.Sandbox(cfg.SandboxAPIKeys)) // Route sandbox API keys to synthetic data
+	app.Use(middleware.Tenant(mongoDB.TenantIDs())) // Resolve isolated tenant databases from X-Tenant-ID
+	app.Use(middleware.Auth(authService, middleware.AuthConfig{
+		Required:    cfg.AuthRequired,
+		PublicPaths: cfg.AuthPublicPaths,
+	})) // Check access tokens and keep drivers to their own record
+	app.Use(middleware.Deprecation(deprecations))        // Flag deprecated routes and record who still calls them
+	app.Use(middleware.ValidationStats(validationStats)) // Count rejected fields per route and validation rule
+	app.Use(middleware.BodyLogger(middleware.BodyLogConfig{
+		Routes:    cfg.BodyLogRoutes,
+		Fleets:    cfg.BodyLogFleets,
+		Retention: cfg.BodyLogRetention,
+	}, requestLogRepo)) // Capture redacted request bodies for debugging
+	app.Use("/api/v1/drivers/nearby", middleware.PollingGuard(middleware.PollingGuardConfig{
+		Window:          cfg.NearbyPollWindow,
+		SoftLimit:       cfg.NearbyPollSoftLimit,
+		Step:            cfg.NearbyPollStep,
+		MaxInterval:     cfg.NearbyPollMaxInterval,
+		SubscriptionURL: cfg.NearbySubscriptionURL,
+	})) // Steer map-screen pollers towards live subscriptions
+	if chaosInjector != nil {
+		app.Use(middleware.Chaos(chaosInjector)) // Roll injected dependency faults per request
+	}
 
-	// Health check endpoint with database status
+	// Health check endpoint with the cached database status
 	app.Get("/health", func(c *fiber.Ctx) error {
-		// Check database health
-		dbStatus := "healthy"
-		if err := dbManager.HealthCheck(); err != nil {
-			dbStatus = fmt.Sprintf("unhealthy: %v", err)
+		health := dbManager.Health()
+		dbStatus := health.Status
+		if health.Error != "" {
+			dbStatus = fmt.Sprintf("%s: %s", health.Status, health.Error)
 		}
 
+		return c.JSON(fiber.Map{
+			"status":                        "ok",
+			"service":                       "driver-service",
+			"timestamp":                     time.Now().UTC(),
+			"database":                      dbStatus,
+			"database_checked_at":           health.CheckedAt,
+			"database_last_success_at":      health.LastSuccessAt,
+			"database_consecutive_failures": health.ConsecutiveFailures,
+			"version":                       "1.0.0",
+		})
+	})
+
+	// Readiness endpoint, gated on required indexes
+	app.Get("/health/ready", func(c *fiber.Ctx) error {
+		status := "ready"
+		code := fiber.StatusOK
+		ready := indexManager.Ready()
+		for _, manager := range tenantIndexes {
+			ready = ready && manager.Ready()
+		}
+		if !dbManager.Health().Healthy() || !ready {
+			status = "not_ready"
+			code = fiber.StatusServiceUnavailable
+		}
+
+		return c.Status(code).JSON(fiber.Map{
+			"status":  status,
+			"indexes": indexManager.Statuses(),
+		})
+	})
+
+	// Liveness probe: answers as long as the process serves requests and
+	// checks no dependency, so an outage de-routes pods instead of restarting them
+	app.Get("/livez", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
 			"status":    "ok",
-			"service":   "driver-service",
 			"timestamp": time.Now().UTC(),
-			"database":  dbStatus,
-			"version":   "1.0.0",
+		})
+	})
+
+	// Readiness probe with per-dependency status; 503 until every enabled
+	// dependency is healthy
+	app.Get("/readyz", func(c *fiber.Ctx) error {
+		database := dbManager.Health()
+
+		indexesReady := indexManager.Ready()
+		tenants := make(map[string]bool, len(tenantIndexes))
+		for tenantID, manager := range tenantIndexes {
+			tenants[tenantID] = manager.Ready()
+			indexesReady = indexesReady && tenants[tenantID]
+		}
+		indexes := fiber.Map{
+			"status":  config.HealthStatusHealthy,
+			"indexes": indexManager.Statuses(),
+			"tenants": tenants,
+		}
+		if !indexesReady {
+			indexes["status"] = config.HealthStatusUnhealthy
+		}
+
+		broker := config.HealthStatus{Status: config.HealthStatusDisabled}
+		brokerReady := true
+		if brokerHealth != nil {
+			broker = brokerHealth.Health()
+			brokerReady = broker.Healthy()
+		}
+
+		status := "ready"
+		code := fiber.StatusOK
+		if !database.Healthy() || !indexesReady || !brokerReady {
+			status = "not_ready"
+			code = fiber.StatusServiceUnavailable
+		}
+
+		return c.Status(code).JSON(fiber.Map{
+			"status":    status,
+			"timestamp": time.Now().UTC(),
+			"checks": fiber.Map{
+				"mongodb":      database,
+				"indexes":      indexes,
+				"event_broker": broker,
+			},
 		})
 	})
 
 	// Register driver routes
 	driverHandler.RegisterRoutes(app)
+	maintenanceHandler.RegisterRoutes(app)
+	earningsHandler.RegisterRoutes(app)
+	ratingHandler.RegisterRoutes(app)
+	ratingPromptHandler.RegisterRoutes(app)
+	onboardingHandler.RegisterRoutes(app)
+	tripHandler.RegisterRoutes(app)
+	riderHandler.RegisterRoutes(app)
+	requestLogHandler.RegisterRoutes(app)
+	sloHandler.RegisterRoutes(app)
+	watchdogHandler.RegisterRoutes(app)
+	featureHandler.RegisterRoutes(app)
+	changeRequestHandler.RegisterRoutes(app)
+	publicHandler.RegisterRoutes(app)
+	dispatchHandler.RegisterRoutes(app)
+	dispatchPauseHandler.RegisterRoutes(app)
+	licenseHandler.RegisterRoutes(app)
+	anomalyHandler.RegisterRoutes(app)
+	emailHandler.RegisterRoutes(app)
+	driverImportHandler.RegisterRoutes(app)
+	gpsQualityHandler.RegisterRoutes(app)
+	analyticsHandler.RegisterRoutes(app)
+	churnHandler.RegisterRoutes(app)
+	driverSchemaHandler.RegisterRoutes(app)
+	tenantHandler.RegisterRoutes(app)
+	deviceHandler.RegisterRoutes(app)
+	photoHandler.RegisterRoutes(app)
+	selfTestHandler.RegisterRoutes(app)
+	referenceHandler.RegisterRoutes(app)
+	plateReservationHandler.RegisterRoutes(app)
+	deprecationHandler.RegisterRoutes(app)
+	validationStatsHandler.RegisterRoutes(app)
+	metricsHandler.RegisterRoutes(app)
+	authHandler.RegisterRoutes(app)
+	fleetHandler.RegisterRoutes(app)
+	liveHandler.RegisterRoutes(app)
+	if chaosInjector != nil {
+		handlers.NewChaosHandler(chaosInjector).RegisterRoutes(app)
+	}
+	if demoService != nil {
+		handlers.NewDemoHandler(demoService).RegisterRoutes(app)
+	}
+	if cfg.AdminUIEnabled {
+		adminui.Register(app)
+	}
 
 	// Log registered routes
 	app.Get("/routes", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
 			"routes": []fiber.Map{
 				{
-					"method": "GET",
-					"path":   "/",
+					"method":  "GET",
+					"path":    "/",
 					"handler": "Root endpoint",
 				},
 				{
-					"method": "GET",
-					"path":   "/health",
+					"method":  "GET",
+					"path":    "/admin/",
+					"handler": "Embedded admin web UI (ADMIN_UI_ENABLED)",
+				},
+				{
+					"method":  "GET",
+					"path":    "/health",
 					"handler": "Health check",
 				},
 				{
-					"method": "GET",
-					"path":   "/routes",
+					"method":  "GET",
+					"path":    "/health/ready",
+					"handler": "Readiness check (required indexes)",
+				},
+				{
+					"method":  "GET",
+					"path":    "/livez",
+					"handler": "Liveness probe (process up)",
+				},
+				{
+					"method":  "GET",
+					"path":    "/readyz",
+					"handler": "Readiness probe (MongoDB, indexes, event broker)",
+				},
+				{
+					"method":  "GET",
+					"path":    "/routes",
 					"handler": "List all registered routes",
 				},
 				{
-					"method": "POST",
-					"path":   "/api/v1/drivers",
+					"method":  "POST",
+					"path":    "/api/v1/drivers",
 					"handler": "Create driver",
 				},
 				{
-					"method": "GET",
-					"path":   "/api/v1/drivers",
-					"handler": "List drivers with pagination",
+					"method":  "GET",
+					"path":    "/api/v1/drivers",
+					"handler": "List drivers with pagination, filters and sort",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/drivers/search",
+					"handler": "Search drivers by name or plate, best matches first",
 				},
 				{
-					"method": "GET",
-					"path":   "/api/v1/drivers/:id",
+					"method":  "GET",
+					"path":    "/api/v1/drivers/by-plate/:plate",
+					"handler": "Get driver by plate (spacing/case insensitive)",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/drivers/:id",
 					"handler": "Get driver by ID",
 				},
 				{
-					"method": "PUT",
-					"path":   "/api/v1/drivers/:id",
+					"method":  "GET",
+					"path":    "/api/v1/drivers/:id/card",
+					"handler": "Get localized rider-facing driver card",
+				},
+				{
+					"method":  "PUT",
+					"path":    "/api/v1/drivers/:id",
 					"handler": "Update driver",
 				},
 				{
-					"method": "DELETE",
-					"path":   "/api/v1/drivers/:id",
+					"method":  "DELETE",
+					"path":    "/api/v1/drivers/:id",
 					"handler": "Delete driver",
 				},
 				{
-					"method": "GET",
-					"path":   "/api/v1/drivers/nearby",
+					"method":  "POST",
+					"path":    "/api/v1/plate-reservations",
+					"handler": "Reserve a plate for onboarding",
+				},
+				{
+					"method":  "DELETE",
+					"path":    "/api/v1/plate-reservations/:plate",
+					"handler": "Release a plate reservation",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/drivers/nearby",
 					"handler": "Find nearby drivers",
 				},
 				{
-					"method": "PUT",
-					"path":   "/api/v1/drivers/:id/location",
+					"method":  "POST",
+					"path":    "/api/v1/drivers/nearby/batch",
+					"handler": "Find nearby drivers for several pickup points",
+				},
+				{
+					"method":  "PUT",
+					"path":    "/api/v1/drivers/:id/location",
 					"handler": "Update driver location",
 				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/drivers/:id/locations/batch",
+					"handler": "Upload locations buffered while offline",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/drivers/:id/locations",
+					"handler": "Recorded location trace for playback",
+				},
+				{
+					"method":  "PUT",
+					"path":    "/api/v1/drivers/:id/password",
+					"handler": "Set driver login password",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/auth/login",
+					"handler": "Log in and get access and refresh tokens",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/auth/refresh",
+					"handler": "Exchange a refresh token for new tokens",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/fleets/:fleetId/accounts",
+					"handler": "Create a fleet owner or sub-admin account",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/fleets/:fleetId/accounts",
+					"handler": "List fleet admin accounts",
+				},
+				{
+					"method":  "PUT",
+					"path":    "/api/v1/fleets/:fleetId/accounts/:accountId",
+					"handler": "Change a fleet account's capabilities or password",
+				},
+				{
+					"method":  "DELETE",
+					"path":    "/api/v1/fleets/:fleetId/accounts/:accountId",
+					"handler": "Delete a fleet account",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/fleets/:fleetId/custom-fields",
+					"handler": "Define a custom driver field for the fleet",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/fleets/:fleetId/custom-fields",
+					"handler": "List the fleet's custom driver fields",
+				},
+				{
+					"method":  "DELETE",
+					"path":    "/api/v1/fleets/:fleetId/custom-fields/:key",
+					"handler": "Delete a custom driver field",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/fleets/:fleetId/drivers",
+					"handler": "Onboard a driver into the fleet",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/fleets/:fleetId/drivers/:id",
+					"handler": "Get a driver of the fleet",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/fleets/:fleetId/drivers/:id/earnings",
+					"handler": "Get a fleet driver's earnings of a day",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/fleets/:fleetId/drivers/export",
+					"handler": "Export the fleet's drivers as CSV",
+				},
+				{
+					"method":  "PUT",
+					"path":    "/api/v1/drivers/:id/status",
+					"handler": "Set driver availability (available, busy, offline)",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/drivers/:id/approve",
+					"handler": "Approve a pending or suspended driver (admin)",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/drivers/:id/reject",
+					"handler": "Reject a pending driver (admin)",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/drivers/:id/suspend",
+					"handler": "Suspend an approved driver (admin)",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/drivers/:id/maintenance",
+					"handler": "Log vehicle maintenance",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/drivers/:id/maintenance",
+					"handler": "List vehicle maintenance",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/drivers/:id/maintenance/due",
+					"handler": "List due vehicle maintenance",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/drivers/:id/earnings",
+					"handler": "Get a driver's earnings of a day (running total and entries)",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/drivers/:id/earnings/rides",
+					"handler": "Book a ride's fare and commission to a driver's earnings",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/drivers/:id/earnings/bonuses",
+					"handler": "Grant a driver a bonus",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/drivers/:id/ratings",
+					"handler": "Rate a driver (1-5 stars and a comment)",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/drivers/:id/ratings",
+					"handler": "List a driver's ratings, newest first",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/rating-prompts/settings",
+					"handler": "List rating prompt defaults and fleet settings",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/rating-prompts/settings/:fleetId",
+					"handler": "Get a fleet's rating prompt settings",
+				},
+				{
+					"method":  "PUT",
+					"path":    "/api/v1/admin/rating-prompts/settings/:fleetId",
+					"handler": "Tune a fleet's rating prompt timing",
+				},
+				{
+					"method":  "DELETE",
+					"path":    "/api/v1/admin/rating-prompts/settings/:fleetId",
+					"handler": "Put a fleet back on the default rating prompt settings",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/rating-prompts/stats",
+					"handler": "Rating prompt conversion funnel",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/rating-prompts/trips/:tripId",
+					"handler": "Get a trip's rating prompt",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/onboarding/funnel",
+					"handler": "Onboarding funnel conversion and drop-off",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/onboarding/stalled",
+					"handler": "List applicants stalled before an onboarding step",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/churn",
+					"handler": "Week-over-week driver churn by fleet and zone",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/churn/alerts",
+					"handler": "List driver churn alerts",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/driver-schema",
+					"handler": "Driver documents per schema version",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/driver-schema/backfill",
+					"handler": "Migrate stale driver documents now",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/trips",
+					"handler": "Create a trip",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/trips",
+					"handler": "List trips, newest first",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/trips/:id",
+					"handler": "Get a trip",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/trips/:id/assign",
+					"handler": "Assign a driver to a trip",
+				},
+				{
+					"method":  "PUT",
+					"path":    "/api/v1/trips/:id/status",
+					"handler": "Move a trip through its lifecycle",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/trips/:id/dispatch",
+					"handler": "Offer a trip to the best matching driver",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/trips/:id/accept",
+					"handler": "Accept a trip offer",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/trips/:id/decline",
+					"handler": "Decline a trip offer",
+				},
+				{
+					"method":  "PUT",
+					"path":    "/api/v1/trips/:id/preferences",
+					"handler": "Update the rider preferences of an unassigned trip",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/trips/:id/preferences/ack",
+					"handler": "Acknowledge a trip's rider preferences",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/riders/:riderId/preferences",
+					"handler": "Get a rider's saved ride preferences",
+				},
+				{
+					"method":  "PUT",
+					"path":    "/api/v1/riders/:riderId/preferences",
+					"handler": "Save a rider's ride preferences",
+				},
+				{
+					"method":  "DELETE",
+					"path":    "/api/v1/riders/:riderId/preferences",
+					"handler": "Delete a rider's saved ride preferences",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/request-logs",
+					"handler": "List captured request bodies",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/slo",
+					"handler": "SLO compliance and error budgets",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/watchdog",
+					"handler": "Supervised background worker status",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/app-config",
+					"handler": "Features enabled for the calling driver",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/feature-flags",
+					"handler": "List feature flags",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/feature-flags/:key",
+					"handler": "Get feature flag",
+				},
+				{
+					"method":  "PUT",
+					"path":    "/api/v1/admin/feature-flags/:key",
+					"handler": "Create or update feature flag",
+				},
+				{
+					"method":  "DELETE",
+					"path":    "/api/v1/admin/feature-flags/:key",
+					"handler": "Delete feature flag",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/drivers/:id/change-requests",
+					"handler": "Submit regulated profile change for approval",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/drivers/:id/change-requests",
+					"handler": "List driver change requests",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/change-requests",
+					"handler": "List change requests by status",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/change-requests/:requestId/approve",
+					"handler": "Approve change request",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/change-requests/:requestId/reject",
+					"handler": "Reject change request",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/drivers/:id/verify",
+					"handler": "Mark driver as verified",
+				},
+				{
+					"method":  "DELETE",
+					"path":    "/api/v1/admin/drivers/:id/verify",
+					"handler": "Revoke driver verification",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/public/verify-plate",
+					"handler": "Public plate verification (rate limited)",
+				},
+				{
+					"method":  "GET",
+					"path":    "/ws/drivers",
+					"handler": "WebSocket: push driver locations, subscribe to a bounding box",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/deprecations",
+					"handler": "Deprecated routes and callers still using them",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/validation-failures",
+					"handler": "Validation failures by endpoint, field and rule",
+				},
+				{
+					"method":  "GET",
+					"path":    "/metrics",
+					"handler": "Prometheus metrics (HTTP, MongoDB, repository and validation)",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/reference",
+					"handler": "Reference data bundle (ETag, conditional)",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/reference/taxi-types",
+					"handler": "Taxi types",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/reference/tariffs",
+					"handler": "Taxi tariffs",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/dispatch/assign",
+					"handler": "Assign a driver using the fleet's strategy",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/drivers/:id/boost",
+					"handler": "Cold-start dispatch boost status",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/dispatch-pauses",
+					"handler": "Pause dispatch in a zone for a time window",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/dispatch-pauses",
+					"handler": "List active and scheduled dispatch pauses",
+				},
+				{
+					"method":  "DELETE",
+					"path":    "/api/v1/admin/dispatch-pauses/:id",
+					"handler": "Cancel a dispatch pause",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/drivers/:id/license",
+					"handler": "Upload license image for OCR",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/drivers/:id/license",
+					"handler": "Get extracted license details",
+				},
+				{
+					"method":  "PATCH",
+					"path":    "/api/v1/drivers/:id/license",
+					"handler": "Correct extracted license details",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/anomalies",
+					"handler": "List flagged driver activity anomalies",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/anomalies/:anomalyId/review",
+					"handler": "Resolve or dismiss an anomaly",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/emails",
+					"handler": "Queue a transactional email",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/emails",
+					"handler": "List queued and sent emails",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/emails/:emailId",
+					"handler": "Get an email's delivery status",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/driver-imports/adapters",
+					"handler": "List driver import adapters, fields and transforms",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/driver-imports",
+					"handler": "Stage a legacy driver roster import and preview it",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/driver-imports",
+					"handler": "List driver imports",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/driver-imports/:importId",
+					"handler": "Get a driver import's preview or results",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/driver-imports/:importId/commit",
+					"handler": "Apply a staged driver import",
+				},
+				{
+					"method":  "DELETE",
+					"path":    "/api/v1/admin/driver-imports/:importId",
+					"handler": "Discard a staged driver import",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/gps-quality",
+					"handler": "Rate recent GPS signal quality per driver, worst first",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/analytics/availability-forecast",
+					"handler": "Forecast drivers per zone and hour from history and current status",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/drivers/:id/gps-quality",
+					"handler": "Rate a driver's recent GPS signal quality",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/tenants",
+					"handler": "List isolated tenant databases and their index status",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/tenants/:tenantId/migrate",
+					"handler": "Run index migrations for a tenant database",
+				},
+				{
+					"method":  "PUT",
+					"path":    "/api/v1/device/location",
+					"handler": "Push location with a device token",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/drivers/:id/device-tokens",
+					"handler": "Issue a device token",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/drivers/:id/device-tokens",
+					"handler": "List device tokens",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/drivers/:id/device-tokens/:tokenId/rotate",
+					"handler": "Rotate a device token",
+				},
+				{
+					"method":  "DELETE",
+					"path":    "/api/v1/admin/drivers/:id/device-tokens/:tokenId",
+					"handler": "Revoke a device token",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/drivers/:id/photos",
+					"handler": "Upload a driver photo for moderation",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/drivers/:id/photos",
+					"handler": "List a driver's photo uploads",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/drivers/:id/photo",
+					"handler": "Serve the driver's approved photo",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/photos",
+					"handler": "Photo moderation queue",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/photos/:photoId/image",
+					"handler": "View an uploaded photo",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/photos/:photoId/approve",
+					"handler": "Approve a photo",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/photos/:photoId/reject",
+					"handler": "Reject a photo",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/selftest",
+					"handler": "Run the post-deploy self-test suite",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/drivers/:id/license-overrides",
+					"handler": "License class override audit trail",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/chaos",
+					"handler": "List injected faults (non-prod, CHAOS_ENABLED)",
+				},
+				{
+					"method":  "PUT",
+					"path":    "/api/v1/admin/chaos/:kind",
+					"handler": "Inject a dependency fault (non-prod, CHAOS_ENABLED)",
+				},
+				{
+					"method":  "DELETE",
+					"path":    "/api/v1/admin/chaos[/:kind]",
+					"handler": "Remove one or all injected faults (non-prod, CHAOS_ENABLED)",
+				},
+				{
+					"method":  "POST",
+					"path":    "/api/v1/admin/demo/seed",
+					"handler": "Reset the database and seed the demo scenario (non-prod, DEMO_ENABLED)",
+				},
+				{
+					"method":  "GET",
+					"path":    "/api/v1/admin/demo/scenario",
+					"handler": "Get the seeded demo scenario and its routes (non-prod, DEMO_ENABLED)",
+				},
 			},
 		})
 	})
@@ -156,30 +1379,35 @@ func main() {
 	// Root endpoint
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
-			"message":  "TaxiHub Driver Service",
-			"version":  "1.0.0",
+			"message": "TaxiHub Driver Service",
+			"version": "1.0.0",
 			"endpoints": fiber.Map{
 				"health": "/health",
+				"livez":  "/livez",
+				"readyz": "/readyz",
 				"api":    "/api/v1",
 			},
 		})
 	})
 
 	// Set up graceful shutdown for the server
-	setupGracefulShutdown(app, cfg)
+	shutdownDone := setupGracefulShutdown(app, cfg, drainTracker)
 
 	// Startup logs
-	log.Println("=== TaxiHub Driver Service ===")
-	log.Printf("Server starting on %s", cfg.GetServerAddress())
-	log.Printf("Health check available at http://localhost:%s/health", cfg.ServerPort)
-	log.Printf("API base path: http://localhost:%s/api/v1", cfg.ServerPort)
-	log.Println("Press Ctrl+C to stop the server")
-	log.Println("================================")
+	slog.Info("TaxiHub Driver Service starting",
+		"address", cfg.GetServerAddress(),
+		"health_check", fmt.Sprintf("http://localhost:%s/health", cfg.ServerPort),
+		"api_base_path", fmt.Sprintf("http://localhost:%s/api/v1", cfg.ServerPort),
+	)
 
 	// Start server
 	if err := app.Listen(cfg.GetServerAddress()); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+		fatal("Failed to start server", "error", err)
 	}
+
+	// Listen returns as soon as the listener closes; wait for in-flight
+	// requests to drain before the deferred database close runs
+	<-shutdownDone
 }
 
 // defaultErrorHandler handles errors and returns JSON responses
@@ -194,8 +1422,14 @@ func defaultErrorHandler(c *fiber.Ctx, err error) error {
 		message = e.Message
 	}
 
-	// Log the error
-	log.Printf("Error: %v (Status: %d, Path: %s)", err, code, c.Path())
+	// Log the error; the request logger already carries the request ID,
+	// method and path
+	logger := logging.FromContext(c.Context())
+	if code >= fiber.StatusInternalServerError {
+		logger.Error("Request failed", "error", err, "status", code)
+	} else {
+		logger.Debug("Request failed", "error", err, "status", code)
+	}
 
 	// Return JSON error response
 	return c.Status(code).JSON(fiber.Map{
@@ -208,26 +1442,49 @@ func defaultErrorHandler(c *fiber.Ctx, err error) error {
 	})
 }
 
-// setupGracefulShutdown handles graceful server shutdown
-func setupGracefulShutdown(app *fiber.App, cfg *config.Config) {
+// fatal logs msg at error level and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// setupGracefulShutdown handles graceful server shutdown. The returned
+// channel is closed once the drain has finished and its summary is logged.
+func setupGracefulShutdown(app *fiber.App, cfg *config.Config, tracker *drain.Tracker) <-chan struct{} {
+	done := make(chan struct{})
+
 	// Create a channel to listen for OS signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	// Wait for signal in a goroutine
 	go func() {
+		defer close(done)
+
 		sig := <-sigChan
-		log.Printf("\nReceived signal: %v. Shutting down gracefully...", sig)
+		slog.Info("Received signal, shutting down gracefully", "signal", sig.String())
 
 		// Create a context with timeout for shutdown
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 		defer cancel()
 
 		// Shutdown the server
-		if err := app.ShutdownWithContext(ctx); err != nil {
-			log.Printf("Error during server shutdown: %v", err)
+		tracker.Begin()
+		err := app.ShutdownWithContext(ctx)
+		report := tracker.Finish(errors.Is(err, context.DeadlineExceeded))
+		if err != nil {
+			slog.Error("Error during server shutdown", "error", err)
 		}
 
-		log.Println("Server shutdown complete")
+		slog.Info("Shutdown summary",
+			"in_flight", report.InFlight,
+			"drained", report.Drained,
+			"force_closed", report.ForceClosed,
+			"drain_time_ms", report.Duration.Milliseconds(),
+			"timed_out", report.TimedOut,
+		)
+		slog.Info("Server shutdown complete")
 	}()
-}
\ No newline at end of file
+
+	return done
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/reindex"
+)
+
+func main() {
+	var names []string
+	for name := range reindex.Transforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	transformName := flag.String("transform", "geohash", "transform to apply ("+strings.Join(names, ", ")+")")
+	collection := flag.String("collection", "drivers", "collection to reindex")
+	workers := flag.Int("workers", 8, "number of parallel writers")
+	batchSize := flag.Int("batch", 1000, "documents per bulk write")
+	precision := flag.Int("precision", models.GeohashPrecision, "geohash precision")
+	progress := flag.Duration("progress", 10*time.Second, "progress report interval")
+	dryRun := flag.Bool("dry-run", false, "compute changes without writing them or the checkpoint")
+	reset := flag.Bool("reset", false, "ignore the stored checkpoint and start over")
+	tenant := flag.String("tenant", "", "reindex this tenant's isolated database instead of the shared one")
+	flag.Parse()
+
+	newTransform, ok := reindex.Transforms[*transformName]
+	if !ok {
+		log.Fatalf("Unknown transform %q (available: %s)", *transformName, strings.Join(names, ", "))
+	}
+
+	cfg := config.LoadConfig()
+	logger, err := logging.New(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		log.Fatalf("Failed to configure logging: %v", err)
+	}
+	slog.SetDefault(logger)
+
+	dbManager := config.NewDatabaseManager(cfg)
+	if err := dbManager.Initialize(); err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer dbManager.Close()
+
+	db := dbManager.GetMongoDB()
+	if *tenant != "" {
+		tenantDB, ok := db.Tenant(*tenant)
+		if !ok {
+			dbManager.Close()
+			log.Fatalf("Tenant %q has no isolated database (configured: %s)", *tenant, strings.Join(db.TenantIDs(), ", "))
+		}
+		db = tenantDB
+	}
+
+	// Stop cleanly on Ctrl+C; the checkpoint lets the next run resume
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	runner := reindex.NewRunner(db, newTransform(*precision), reindex.Options{
+		Collection:       *collection,
+		Workers:          *workers,
+		BatchSize:        *batchSize,
+		ProgressInterval: *progress,
+		DryRun:           *dryRun,
+		Reset:            *reset,
+	})
+
+	if _, err := runner.Run(ctx); err != nil {
+		slog.Error("Reindex failed; run the command again to resume from the last checkpoint", "error", err)
+		dbManager.Close()
+		os.Exit(1)
+	}
+}
@@ -4,24 +4,35 @@ go 1.21
 
 require (
 	github.com/go-playground/validator/v10 v10.16.0
+	github.com/gofiber/contrib/websocket v1.3.0
 	github.com/gofiber/fiber/v2 v2.52.4
+	github.com/redis/go-redis/v9 v9.5.1
+	github.com/segmentio/kafka-go v0.4.47
 	go.mongodb.org/mongo-driver v1.12.1
+	github.com/tinylib/msgp v1.1.8
+	golang.org/x/crypto v0.15.0
 )
 
 require (
 	github.com/andybalholm/brotli v1.0.5
+	github.com/cespare/xxhash/v2 v2.2.0
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f
+	github.com/fasthttp/websocket v1.5.7
 	github.com/gabriel-vasile/mimetype v1.4.2
 	github.com/go-playground/locales v0.14.1
 	github.com/go-playground/universal-translator v0.18.1
 	github.com/golang/snappy v0.0.1
 	github.com/google/uuid v1.5.0
-	github.com/klauspost/compress v1.17.0
+	github.com/klauspost/compress v1.17.3
 	github.com/leodido/go-urn v1.2.4
 	github.com/mattn/go-colorable v0.1.13
 	github.com/mattn/go-isatty v0.0.20
 	github.com/mattn/go-runewidth v0.0.15
 	github.com/montanaflynn/stats v0.0.0-20171201202039-1bf9dbcd8cbe
+	github.com/philhofer/fwd v1.1.2
+	github.com/pierrec/lz4/v4 v4.1.15
 	github.com/rivo/uniseg v0.2.0
+	github.com/savsgio/gotils v0.0.0-20230208104028-c358bd845dee
 	github.com/valyala/bytebufferpool v1.0.0
 	github.com/valyala/fasthttp v1.51.0
 	github.com/valyala/tcplisten v1.0.0
@@ -29,9 +40,8 @@ require (
 	github.com/xdg-go/scram v1.1.2
 	github.com/xdg-go/stringprep v1.0.4
 	github.com/youmark/pkcs8 v0.0.0-20181117223130-1be2e3e5546d
-	golang.org/x/crypto v0.14.0
-	golang.org/x/net v0.17.0
-	golang.org/x/sync v0.0.0-20220722155255-886fb9371eb4
+	golang.org/x/net v0.18.0
+	golang.org/x/sync v0.1.0
 	golang.org/x/sys v0.15.0
-	golang.org/x/text v0.13.0
+	golang.org/x/text v0.14.0
 )
@@ -0,0 +1,39 @@
+// Package adminui serves a small admin web UI built on the service's own
+// admin APIs, so small operators need no separate frontend.
+package adminui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+)
+
+// Path is where the UI is mounted.
+const Path = "/admin"
+
+//go:embed static
+var static embed.FS
+
+// Register mounts the UI under Path. Unknown paths below it serve
+// index.html so the UI can keep its own view in the URL.
+func Register(app *fiber.App) {
+	root, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // the embedded directory is fixed at build time
+	}
+
+	app.Use(Path, filesystem.New(filesystem.Config{
+		// Use matches any path starting with Path, /adminx included
+		Next: func(c *fiber.Ctx) bool {
+			return c.Path() != Path && !strings.HasPrefix(c.Path(), Path+"/")
+		},
+		Root:         http.FS(root),
+		Index:        "index.html",
+		NotFoundFile: "index.html",
+		MaxAge:       300,
+	}))
+}
@@ -0,0 +1,205 @@
+// Admin UI for the driver service. It only uses the service's public and
+// admin APIs, on the same origin.
+(function () {
+  'use strict';
+
+  const pageSize = 50;
+  const mapRefreshMs = 10000;
+  const tokenKey = 'taxihub-admin-token';
+
+  let page = 1;
+  let totalPages = 1;
+  let drivers = [];
+  let mapTimer = null;
+
+  const $ = (id) => document.getElementById(id);
+
+  function setStatus(message) {
+    $('status').textContent = message || '';
+  }
+
+  // The admin's access token is kept for the browser session only.
+  async function api(method, path) {
+    const headers = { Accept: 'application/json' };
+    const token = sessionStorage.getItem(tokenKey);
+    if (token) {
+      headers.Authorization = 'Bearer ' + token;
+    }
+    const response = await fetch(path, { method: method, headers: headers });
+    const body = await response.json().catch(() => ({}));
+    if (!response.ok) {
+      throw new Error(body.error || response.statusText);
+    }
+    return body;
+  }
+
+  async function loadDrivers() {
+    try {
+      const list = await api('GET', '/api/v1/drivers?page=' + page + '&pageSize=' + pageSize);
+      drivers = list.data || [];
+      totalPages = Math.max(list.total_pages || 1, 1);
+      $('page-info').textContent = 'Page ' + list.page + ' of ' + totalPages + ' (' + list.total_count + ' drivers)';
+      setStatus('');
+      renderDrivers();
+    } catch (err) {
+      setStatus('Failed to load drivers: ' + err.message);
+    }
+  }
+
+  function matches(driver, query) {
+    if (!query) {
+      return true;
+    }
+    const haystack = [driver.first_name, driver.last_name, driver.plate, driver.fleet_id || '']
+      .join(' ')
+      .toLowerCase();
+    return haystack.includes(query.toLowerCase());
+  }
+
+  function cell(text) {
+    const td = document.createElement('td');
+    td.textContent = text;
+    return td;
+  }
+
+  function renderDrivers() {
+    const query = $('search').value.trim();
+    const rows = $('driver-rows');
+    rows.replaceChildren();
+
+    drivers.filter((driver) => matches(driver, query)).forEach((driver) => {
+      const tr = document.createElement('tr');
+      tr.append(
+        cell(driver.first_name + ' ' + driver.last_name),
+        cell(driver.plate),
+        cell(driver.taxi_type),
+        cell(driver.fleet_id || ''),
+        cell(driver.verified ? 'yes' : 'no'),
+        cell(driver.review_status),
+        cell(new Date(driver.updated_at).toLocaleString())
+      );
+
+      const action = document.createElement('td');
+      const button = document.createElement('button');
+      button.textContent = driver.verified ? 'Unverify' : 'Verify';
+      button.addEventListener('click', () => toggleVerified(driver));
+      action.append(button);
+      const decision = reviewDecision(driver);
+      if (decision) {
+        const review = document.createElement('button');
+        review.textContent = decision.label;
+        review.addEventListener('click', () => reviewDriver(driver, decision.action));
+        action.append(' ', review);
+      }
+      tr.append(action);
+
+      rows.append(tr);
+    });
+  }
+
+  async function toggleVerified(driver) {
+    try {
+      const updated = await api(driver.verified ? 'DELETE' : 'POST', '/api/v1/admin/drivers/' + driver.id + '/verify');
+      drivers = drivers.map((d) => (d.id === updated.id ? updated : d));
+      renderDrivers();
+    } catch (err) {
+      setStatus('Failed to update verification: ' + err.message);
+    }
+  }
+
+  // Rejection is final, so it is left to the API rather than a single click.
+  function reviewDecision(driver) {
+    switch (driver.review_status) {
+      case 'pending_review':
+      case 'suspended':
+        return { label: 'Approve', action: 'approve' };
+      case 'approved':
+        return { label: 'Suspend', action: 'suspend' };
+      default:
+        return null;
+    }
+  }
+
+  async function reviewDriver(driver, action) {
+    try {
+      const updated = await api('POST', '/api/v1/drivers/' + driver.id + '/' + action);
+      drivers = drivers.map((d) => (d.id === updated.id ? updated : d));
+      renderDrivers();
+    } catch (err) {
+      setStatus('Failed to ' + action + ' driver: ' + err.message);
+    }
+  }
+
+  // The map plots the current page of drivers on an equirectangular
+  // projection fitted to their bounding box, without any tile provider.
+  function renderMap() {
+    const svg = $('map-canvas');
+    svg.replaceChildren();
+
+    const located = drivers.filter((d) => d.location && (d.location.lat || d.location.lon));
+    $('map-info').textContent = located.length + ' drivers on this page, refreshed every ' + mapRefreshMs / 1000 + 's';
+    if (located.length === 0) {
+      return;
+    }
+
+    const lats = located.map((d) => d.location.lat);
+    const lons = located.map((d) => d.location.lon);
+    const minLat = Math.min(...lats), maxLat = Math.max(...lats);
+    const minLon = Math.min(...lons), maxLon = Math.max(...lons);
+    const spanLat = Math.max(maxLat - minLat, 0.01);
+    const spanLon = Math.max(maxLon - minLon, 0.01);
+
+    located.forEach((driver) => {
+      const circle = document.createElementNS('http://www.w3.org/2000/svg', 'circle');
+      circle.setAttribute('cx', 20 + ((driver.location.lon - minLon) / spanLon) * 960);
+      circle.setAttribute('cy', 580 - ((driver.location.lat - minLat) / spanLat) * 560);
+      circle.setAttribute('r', 6);
+      circle.setAttribute('class', driver.verified ? 'verified' : 'unverified');
+
+      const title = document.createElementNS('http://www.w3.org/2000/svg', 'title');
+      title.textContent = driver.first_name + ' ' + driver.last_name + ' (' + driver.plate + ')';
+      circle.append(title);
+      svg.append(circle);
+    });
+  }
+
+  function showView(name) {
+    document.querySelectorAll('.view').forEach((view) => {
+      view.hidden = view.id !== name;
+    });
+    document.querySelectorAll('nav a').forEach((link) => {
+      link.classList.toggle('active', link.dataset.view === name);
+    });
+
+    clearInterval(mapTimer);
+    mapTimer = null;
+    if (name === 'drivers') {
+      loadDrivers();
+    } else if (name === 'map') {
+      loadDrivers().then(renderMap);
+      mapTimer = setInterval(() => loadDrivers().then(renderMap), mapRefreshMs);
+    }
+  }
+
+  $('token').value = sessionStorage.getItem(tokenKey) || '';
+  $('token').addEventListener('change', () => {
+    sessionStorage.setItem(tokenKey, $('token').value.trim());
+    loadDrivers();
+  });
+  $('search').addEventListener('input', renderDrivers);
+  $('prev').addEventListener('click', () => {
+    if (page > 1) {
+      page--;
+      loadDrivers();
+    }
+  });
+  $('next').addEventListener('click', () => {
+    if (page < totalPages) {
+      page++;
+      loadDrivers();
+    }
+  });
+  window.addEventListener('hashchange', () => showView(location.hash.slice(1) || 'drivers'));
+
+  showView(location.hash.slice(1) || 'drivers');
+})();
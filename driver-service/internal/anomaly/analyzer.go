@@ -0,0 +1,215 @@
+// Package anomaly flags suspicious driver activity from the stream of
+// location updates and queues it for review.
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/alerting"
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// minSpeedDistanceKm ignores GPS jitter between closely spaced fixes,
+	// which can imply absurd speeds over a few metres.
+	minSpeedDistanceKm = 0.5
+	recordTimeout      = 5 * time.Second
+	pruneInterval      = time.Minute
+)
+
+type Config struct {
+	// MaxSpeedKmh is the fastest plausible speed between two fixes.
+	MaxSpeedKmh float64
+	// TeleportKm is the jump that counts as teleporting when it also
+	// exceeds MaxSpeedKmh.
+	TeleportKm float64
+	// OnlineLimit is the continuous online time that gets flagged.
+	OnlineLimit time.Duration
+	// OnlineGap is the silence after which a driver counts as offline.
+	OnlineGap time.Duration
+	// Cooldown suppresses repeats of the same anomaly for a driver.
+	Cooldown  time.Duration
+	QueueSize int
+}
+
+// Analyzer consumes location samples in the background so location updates
+// never wait on analysis. Samples are dropped when the queue is full.
+type Analyzer struct {
+	cfg      Config
+	store    repository.AnomalyRepository
+	notifier alerting.Notifier
+	samples  chan models.LocationSample
+	dropped  atomic.Int64
+}
+
+type driverKey struct {
+	tenantID string
+	driverID primitive.ObjectID
+}
+
+type driverState struct {
+	last          models.LocationSample
+	sessionStart  time.Time
+	onlineFlagged bool
+	lastFlagged   map[string]time.Time
+}
+
+func NewAnalyzer(cfg Config, store repository.AnomalyRepository, notifier alerting.Notifier) *Analyzer {
+	return &Analyzer{
+		cfg:      cfg,
+		store:    store,
+		notifier: notifier,
+		samples:  make(chan models.LocationSample, cfg.QueueSize),
+	}
+}
+
+func (a *Analyzer) Observe(sample models.LocationSample) {
+	select {
+	case a.samples <- sample:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// Run analyzes samples until ctx is cancelled. Per-driver state lives only
+// for the lifetime of one run.
+func (a *Analyzer) Run(ctx context.Context, heartbeat func()) {
+	drivers := make(map[driverKey]*driverState)
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sample := <-a.samples:
+			a.analyze(ctx, drivers, sample)
+		case now := <-ticker.C:
+			for id, state := range drivers {
+				if now.Sub(state.last.RecordedAt) > a.cfg.OnlineGap {
+					delete(drivers, id)
+				}
+			}
+			if dropped := a.dropped.Swap(0); dropped > 0 {
+				logging.FromContext(ctx).Warn("Anomaly analyzer dropped location samples (queue full)", "count", dropped)
+			}
+			if heartbeat != nil {
+				heartbeat()
+			}
+		}
+	}
+}
+
+func (a *Analyzer) analyze(ctx context.Context, drivers map[driverKey]*driverState, sample models.LocationSample) {
+	key := driverKey{tenantID: sample.TenantID, driverID: sample.DriverID}
+	state, ok := drivers[key]
+	if !ok {
+		drivers[key] = &driverState{
+			last:         sample,
+			sessionStart: sample.RecordedAt,
+			lastFlagged:  make(map[string]time.Time),
+		}
+		return
+	}
+
+	elapsed := sample.RecordedAt.Sub(state.last.RecordedAt)
+	if elapsed <= 0 {
+		return
+	}
+
+	distance := state.last.Location.DistanceKm(sample.Location)
+	speed := distance / elapsed.Hours()
+	details := map[string]interface{}{
+		"from":        state.last.Location,
+		"to":          sample.Location,
+		"distance_km": round(distance),
+		"elapsed_s":   elapsed.Seconds(),
+		"speed_kmh":   round(speed),
+	}
+
+	switch {
+	case distance >= a.cfg.TeleportKm && speed > a.cfg.MaxSpeedKmh:
+		a.flag(ctx, state, sample, models.AnomalyGPSTeleport, models.AnomalySeverityHigh, details)
+	case distance >= minSpeedDistanceKm && speed > a.cfg.MaxSpeedKmh:
+		severity := models.AnomalySeverityMedium
+		if speed > 2*a.cfg.MaxSpeedKmh {
+			severity = models.AnomalySeverityHigh
+		}
+		a.flag(ctx, state, sample, models.AnomalyImpossibleSpeed, severity, details)
+	}
+
+	if elapsed > a.cfg.OnlineGap {
+		state.sessionStart = sample.RecordedAt
+		state.onlineFlagged = false
+	} else if online := sample.RecordedAt.Sub(state.sessionStart); online >= a.cfg.OnlineLimit && !state.onlineFlagged {
+		state.onlineFlagged = true
+		a.flag(ctx, state, sample, models.AnomalyContinuousOnline, models.AnomalySeverityMedium, map[string]interface{}{
+			"online_since": state.sessionStart,
+			"online_hours": round(online.Hours()),
+		})
+	}
+
+	state.last = sample
+}
+
+func (a *Analyzer) flag(ctx context.Context, state *driverState, sample models.LocationSample, kind, severity string, details map[string]interface{}) {
+	if last, ok := state.lastFlagged[kind]; ok && sample.RecordedAt.Sub(last) < a.cfg.Cooldown {
+		return
+	}
+	state.lastFlagged[kind] = sample.RecordedAt
+
+	anomaly := &models.DriverAnomaly{
+		DriverID:   sample.DriverID,
+		FleetID:    sample.FleetID,
+		Kind:       kind,
+		Severity:   severity,
+		Details:    details,
+		Status:     models.AnomalyOpen,
+		DetectedAt: sample.RecordedAt,
+	}
+
+	recordCtx, cancel := context.WithTimeout(config.WithTenant(ctx, sample.TenantID), recordTimeout)
+	defer cancel()
+
+	if err := a.store.Create(recordCtx, anomaly); err != nil {
+		logging.FromContext(ctx).Error("Failed to record anomaly", "kind", kind, "driver_id", sample.DriverID.Hex(), "tenant", sample.TenantID, "error", err)
+		return
+	}
+
+	alertSeverity := alerting.SeverityWarning
+	if severity == models.AnomalySeverityHigh {
+		alertSeverity = alerting.SeverityCritical
+	}
+	alertDetails := map[string]interface{}{
+		"anomaly_id": anomaly.ID.Hex(),
+		"driver_id":  sample.DriverID.Hex(),
+		"fleet_id":   sample.FleetID,
+		"tenant_id":  sample.TenantID,
+		"severity":   severity,
+	}
+	for k, v := range details {
+		alertDetails[k] = v
+	}
+
+	err := a.notifier.Notify(recordCtx, alerting.Alert{
+		Name:     "driver_anomaly:" + kind,
+		Severity: alertSeverity,
+		Message:  fmt.Sprintf("Driver %s flagged for %s", sample.DriverID.Hex(), kind),
+		Details:  alertDetails,
+		FiredAt:  time.Now(),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to notify anomaly", "kind", kind, "driver_id", sample.DriverID.Hex(), "tenant", sample.TenantID, "error", err)
+	}
+}
+
+func round(v float64) float64 {
+	return float64(int64(v*10+0.5)) / 10
+}
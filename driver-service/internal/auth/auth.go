@@ -0,0 +1,97 @@
+// Package auth issues and verifies the JWTs drivers, dispatchers, fleet
+// admins and admins use to call the API. Tokens are signed with HMAC-SHA256
+// (HS256) and a shared secret.
+package auth
+
+import (
+	"errors"
+	"time"
+)
+
+// Roles. Admins may do anything, dispatchers may read and search drivers,
+// drivers may read and change their own record, and fleet admins may use
+// their capabilities within their fleet.
+const (
+	RoleDriver     = "driver"
+	RoleDispatcher = "dispatcher"
+	RoleFleetAdmin = "fleet_admin"
+	RoleAdmin      = "admin"
+)
+
+// Token types. Refresh tokens are only accepted by the refresh endpoint and
+// access tokens by everything else.
+const (
+	TypeAccess  = "access"
+	TypeRefresh = "refresh"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Grant is whom a token is issued to and what it allows.
+type Grant struct {
+	Subject  string
+	Role     string
+	TenantID string
+	// FleetID and Capabilities scope fleet admin tokens.
+	FleetID      string
+	Capabilities []string
+}
+
+// Claims are the JWT payload. Subject is the driver ID for drivers, the
+// account ID for fleet admins and the user name for dispatchers and admins;
+// TenantID binds the token to the tenant it was issued for.
+type Claims struct {
+	ID           string   `json:"jti"`
+	Issuer       string   `json:"iss"`
+	Subject      string   `json:"sub"`
+	Role         string   `json:"role"`
+	Type         string   `json:"typ"`
+	TenantID     string   `json:"tid,omitempty"`
+	FleetID      string   `json:"fid,omitempty"`
+	Capabilities []string `json:"cap,omitempty"`
+	IssuedAt     int64    `json:"iat"`
+	ExpiresAt    int64    `json:"exp"`
+}
+
+func (c *Claims) IsAdmin() bool {
+	return c.Role == RoleAdmin
+}
+
+// HasRole reports whether the claims carry one of the roles.
+func (c *Claims) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if c.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// CanInFleet reports whether the claims allow the capability in the fleet.
+// Admins may do anything in every fleet.
+func (c *Claims) CanInFleet(fleetID, capability string) bool {
+	if c.IsAdmin() {
+		return true
+	}
+	if c.Role != RoleFleetAdmin || fleetID == "" || c.FleetID != fleetID {
+		return false
+	}
+	for _, granted := range c.Capabilities {
+		if granted == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDriver reports whether the claims belong to the given driver.
+func (c *Claims) IsDriver(driverID string) bool {
+	return c.Role == RoleDriver && c.Subject == driverID
+}
+
+func (c *Claims) IssuedAtTime() time.Time {
+	return time.Unix(c.IssuedAt, 0)
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MinSecretLength is the shortest signing secret accepted; HS256 keys should
+// be at least as long as the hash.
+const MinSecretLength = 32
+
+// clockSkew tolerates small clock differences between services when checking
+// expiry.
+const clockSkew = 30 * time.Second
+
+// The header is fixed: only HS256 tokens are issued or accepted, so a token
+// cannot pick its own algorithm ("none", or RS256 with the secret as key).
+var encodedHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+type Signer struct {
+	secret []byte
+	issuer string
+}
+
+func NewSigner(secret, issuer string) (*Signer, error) {
+	if len(secret) < MinSecretLength {
+		return nil, fmt.Errorf("signing secret must be at least %d bytes", MinSecretLength)
+	}
+	return &Signer{secret: []byte(secret), issuer: issuer}, nil
+}
+
+// Issue signs a token of the given type for the grant, valid for ttl from
+// now.
+func (s *Signer) Issue(grant Grant, tokenType string, ttl time.Duration, now time.Time) (string, error) {
+	id, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	claims := &Claims{
+		ID:           id,
+		Issuer:       s.issuer,
+		Subject:      grant.Subject,
+		Role:         grant.Role,
+		Type:         tokenType,
+		TenantID:     grant.TenantID,
+		FleetID:      grant.FleetID,
+		Capabilities: grant.Capabilities,
+		IssuedAt:     now.Unix(),
+		ExpiresAt:    now.Add(ttl).Unix(),
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token claims: %w", err)
+	}
+
+	unsigned := encodedHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + s.sign(unsigned), nil
+}
+
+// Verify checks the token's signature, issuer, type and expiry and returns
+// its claims.
+func (s *Signer) Verify(token, tokenType string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != encodedHeader {
+		return nil, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(s.sign(parts[0]+"."+parts[1]))) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.Issuer != s.issuer || claims.Type != tokenType || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	if !now.Before(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)) {
+		return nil, ErrExpiredToken
+	}
+
+	return &claims, nil
+}
+
+func (s *Signer) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newTokenID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.New("failed to generate token ID")
+	}
+	return hex.EncodeToString(buf), nil
+}
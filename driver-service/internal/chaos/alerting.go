@@ -0,0 +1,26 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/taxihub/driver-service/internal/alerting"
+	"github.com/taxihub/driver-service/internal/logging"
+)
+
+type droppingNotifier struct {
+	next     alerting.Notifier
+	injector *Injector
+}
+
+// Notifier wraps next so the drop_alerts fault can discard alert events.
+func (i *Injector) Notifier(next alerting.Notifier) alerting.Notifier {
+	return &droppingNotifier{next: next, injector: i}
+}
+
+func (n *droppingNotifier) Notify(ctx context.Context, alert alerting.Alert) error {
+	if n.injector.dropAlert() {
+		logging.FromContext(ctx).Warn("chaos: dropped alert", "alert", alert.Name)
+		return nil
+	}
+	return n.next.Notify(ctx, alert)
+}
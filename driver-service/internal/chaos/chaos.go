@@ -0,0 +1,178 @@
+// Package chaos injects dependency failures so retries, fallbacks and
+// timeouts can be exercised outside production.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// LocalKey is the fiber local (and request context value) holding the
+// faults selected for a request.
+const LocalKey = "chaos_faults"
+
+// DefaultDuration bounds faults set without an explicit duration so a
+// forgotten experiment cannot outlive the test session.
+const DefaultDuration = 10 * time.Minute
+
+var (
+	ErrUnknownFault  = errors.New("unknown chaos fault")
+	ErrFaultNotFound = errors.New("chaos fault not active")
+)
+
+// RequestFaults are the faults rolled for a single request.
+type RequestFaults struct {
+	MongoLatency time.Duration
+}
+
+func (f *RequestFaults) Kinds() []string {
+	var kinds []string
+	if f.MongoLatency > 0 {
+		kinds = append(kinds, models.ChaosMongoLatency)
+	}
+	return kinds
+}
+
+type Injector struct {
+	mu     sync.Mutex
+	faults map[string]*models.ChaosFault
+	rng    *rand.Rand
+	now    func() time.Time
+}
+
+func NewInjector() *Injector {
+	return &Injector{
+		faults: make(map[string]*models.ChaosFault),
+		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		now:    time.Now,
+	}
+}
+
+func (i *Injector) Set(kind string, req *models.SetChaosFaultRequest) (models.ChaosFault, error) {
+	if !models.IsValidChaosFault(kind) {
+		return models.ChaosFault{}, ErrUnknownFault
+	}
+
+	duration := DefaultDuration
+	if req.DurationSeconds > 0 {
+		duration = time.Duration(req.DurationSeconds) * time.Second
+	}
+
+	now := i.now()
+	fault := &models.ChaosFault{
+		Kind:        kind,
+		Percentage:  req.Percentage,
+		LatencyMs:   req.LatencyMs,
+		RoutePrefix: req.RoutePrefix,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(duration),
+	}
+
+	i.mu.Lock()
+	i.faults[kind] = fault
+	i.mu.Unlock()
+
+	return *fault, nil
+}
+
+func (i *Injector) Remove(kind string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if _, ok := i.faults[kind]; !ok {
+		return ErrFaultNotFound
+	}
+	delete(i.faults, kind)
+	return nil
+}
+
+func (i *Injector) Clear() {
+	i.mu.Lock()
+	i.faults = make(map[string]*models.ChaosFault)
+	i.mu.Unlock()
+}
+
+func (i *Injector) Faults() []models.ChaosFault {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.expireLocked()
+	faults := make([]models.ChaosFault, 0, len(i.faults))
+	for _, fault := range i.faults {
+		faults = append(faults, *fault)
+	}
+	sort.Slice(faults, func(a, b int) bool {
+		return faults[a].Kind < faults[b].Kind
+	})
+	return faults
+}
+
+// Roll selects the request-scoped faults for a request to path, or returns
+// nil when none apply.
+func (i *Injector) Roll(path string) *RequestFaults {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.expireLocked()
+	fault, ok := i.faults[models.ChaosMongoLatency]
+	if !ok || !strings.HasPrefix(path, fault.RoutePrefix) || !i.hitLocked(fault) {
+		return nil
+	}
+	return &RequestFaults{MongoLatency: time.Duration(fault.LatencyMs) * time.Millisecond}
+}
+
+// CommandMonitor delays MongoDB commands issued with a context carrying
+// RequestFaults. Fiber request contexts expose locals as context values, so
+// repositories called with c.Context() pick the faults up unchanged.
+func (i *Injector) CommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, _ *event.CommandStartedEvent) {
+			faults, ok := ctx.Value(LocalKey).(*RequestFaults)
+			if !ok || faults == nil || faults.MongoLatency <= 0 {
+				return
+			}
+
+			timer := time.NewTimer(faults.MongoLatency)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+			}
+		},
+	}
+}
+
+// dropAlert reports whether the next alert event should be dropped.
+func (i *Injector) dropAlert() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.expireLocked()
+	fault, ok := i.faults[models.ChaosDropAlerts]
+	return ok && i.hitLocked(fault)
+}
+
+func (i *Injector) hitLocked(fault *models.ChaosFault) bool {
+	if i.rng.Intn(100) >= fault.Percentage {
+		return false
+	}
+	fault.Injected++
+	return true
+}
+
+func (i *Injector) expireLocked() {
+	now := i.now()
+	for kind, fault := range i.faults {
+		if !now.Before(fault.ExpiresAt) {
+			delete(i.faults, kind)
+		}
+	}
+}
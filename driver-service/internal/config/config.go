@@ -3,12 +3,282 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	MongoDBURI      string
 	MongoDBDatabase string
 	ServerPort      string
+	Environment     string
+	SandboxAPIKeys  []string
+	SandboxSeed     int64
+
+	// LogLevel is the lowest level logged: debug, info, warn or error.
+	// LogFormat is json or text; it defaults to json in production.
+	LogLevel  string
+	LogFormat string
+
+	// MongoDBConnectMaxWait bounds how long startup retries an unavailable
+	// MongoDB; retries back off from MongoDBConnectBackoff up to
+	// MongoDBConnectMaxBackoff.
+	MongoDBConnectMaxWait    time.Duration
+	MongoDBConnectBackoff    time.Duration
+	MongoDBConnectMaxBackoff time.Duration
+
+	// ShutdownTimeout bounds how long shutdown waits for in-flight requests
+	// before they are force closed.
+	ShutdownTimeout time.Duration
+
+	// PlateReservationTTL is how long an onboarding flow holds its plate.
+	PlateReservationTTL time.Duration
+
+	// DispatchBoost* configure the cold-start boost for new drivers; a zero
+	// duration turns it off.
+	DispatchBoostDuration   time.Duration
+	DispatchBoostCap        int
+	DispatchBoostMaxExtraKm float64
+
+	// DispatchOffer* configure automatic trip matching: how long a driver
+	// has to accept an offer, how many drivers a trip is offered to, and
+	// how often expired offers move on to the next driver.
+	DispatchOfferTimeout       time.Duration
+	DispatchOfferMaxAttempts   int
+	DispatchOfferSweepInterval time.Duration
+
+	// TenantDatabases maps tenant IDs to their isolated database names;
+	// TenantMongoDBURIs optionally puts a tenant on its own cluster.
+	TenantDatabases   map[string]string
+	TenantMongoDBURIs map[string]string
+
+	BodyLogRoutes    []string
+	BodyLogFleets    []string
+	BodyLogRetention time.Duration
+
+	GeocodingProvider  string
+	GeocodingAPIKey    string
+	GeocodingURL       string
+	GeocodingUserAgent string
+	GeocodingCacheTTL  time.Duration
+
+	// GeoPrecisionRules override how precisely driver coordinates are shown,
+	// keyed by endpoint or endpoint.role.
+	GeoPrecisionRules map[string]string
+
+	NearbyPollWindow        time.Duration
+	NearbyPollSoftLimit     int
+	NearbyPollStep          time.Duration
+	NearbyPollMaxInterval   time.Duration
+	NearbySubscriptionURL   string
+	NearbyCoalesceWindow    time.Duration
+	NearbyCoalescePrecision int
+	NearbyBatchMaxPoints    int
+	NearbyBatchConcurrency  int
+	NearbyMinRadiusKm       float64
+	NearbyMaxRadiusKm       float64
+	NearbyMaxLimit          int
+	// NearbyBackend answers nearby searches: "mongo" ($geoNear) or "redis"
+	// (a GEO set at RedisURL mirroring MongoDB).
+	NearbyBackend string
+	RedisURL      string
+
+	// LocationBatchMaxPoints caps the points of one
+	// POST /drivers/:id/locations/batch upload.
+	LocationBatchMaxPoints int
+	// LocationHistoryTTL is how long points stay in the driver_locations
+	// trace; 0 keeps them forever.
+	LocationHistoryTTL time.Duration
+	// LocationValidation is "strict" (fixes on the null island, with an
+	// accuracy radius above LocationMaxAccuracyM or buffered for longer
+	// than LocationMaxAge are rejected) or "permissive" (they are only
+	// counted).
+	LocationValidation   string
+	LocationMaxAccuracyM float64
+	LocationMaxAge       time.Duration
+
+	MaintenanceReminderInterval time.Duration
+
+	// MailProvider sends transactional emails: "none" (emails stay queued),
+	// "smtp" or "sendgrid".
+	MailProvider         string
+	MailFrom             string
+	MailFromName         string
+	MailSMTPHost         string
+	MailSMTPPort         int
+	MailSMTPUsername     string
+	MailSMTPPassword     string
+	MailSendGridAPIKey   string
+	MailSendGridURL      string
+	MailDeliveryInterval time.Duration
+	// MailMaxAttempts is how many times an email is tried before it is
+	// marked failed; retries back off from MailRetryBackoff.
+	MailMaxAttempts  int
+	MailRetryBackoff time.Duration
+
+	// RatingPrompt* are the rating prompt timing of fleets without settings
+	// of their own, and how often due prompts are sent.
+	RatingPromptDelay        time.Duration
+	RatingPromptReminder     time.Duration
+	RatingPromptMaxPrompts   int
+	RatingPromptSendInterval time.Duration
+
+	// Churn* tune the week-over-week churn alerts: a fleet's zone is
+	// alerted once its active drivers fall by ChurnAlertThreshold, if it had
+	// at least ChurnMinDrivers. Checks run every ChurnCheckInterval.
+	ChurnAlertThreshold float64
+	ChurnMinDrivers     int
+	ChurnZonePrecision  int
+	ChurnCheckInterval  time.Duration
+
+	// DriverSchemaBackfill* pace the background migration of driver
+	// documents that reads have not migrated yet.
+	DriverSchemaBackfillInterval  time.Duration
+	DriverSchemaBackfillBatchSize int
+
+	// ImportMaxRows caps the rows of one legacy driver import file, and
+	// ImportStageTTL is how long an uncommitted import preview is kept.
+	ImportMaxRows  int
+	ImportStageTTL time.Duration
+
+	AlertWebhookURL       string
+	SLOWindow             time.Duration
+	SLOAvailabilityTarget float64
+	SLOLatencyTarget      float64
+	SLOLatencyThreshold   time.Duration
+	SLOLatencyRoute       string
+	SLOAlertHorizon       time.Duration
+	SLOEvaluationInterval time.Duration
+
+	WatchdogCheckInterval time.Duration
+	WatchdogStallTimeout  time.Duration
+
+	IndexCheckInterval time.Duration
+
+	HealthCheckInterval   time.Duration
+	HealthCheckMaxBackoff time.Duration
+
+	FeatureFlagCacheTTL time.Duration
+
+	PublicRateLimit  int
+	PublicRateWindow time.Duration
+
+	DispatchDefaultStrategy string
+	DispatchFleetStrategies map[string]string
+
+	OCRProvider            string
+	OCRURL                 string
+	OCRAPIKey              string
+	OCRConfidenceThreshold float64
+
+	// LicenseClassRequirements maps taxi types to the license class they
+	// need, on top of the default of B for every type.
+	LicenseClassRequirements map[string]string
+
+	// TaxiTariffs maps taxi types to "opening:per_km:minimum" fares in
+	// TariffCurrency.
+	TaxiTariffs     map[string]string
+	TariffCurrency  string
+	ReferenceMaxAge time.Duration
+
+	// EarningsCommissionRate is the fraction of each fare deducted from
+	// the driver's earnings. EarningsTimezone is the IANA time zone whose
+	// days the daily totals follow.
+	EarningsCommissionRate float64
+	EarningsTimezone       string
+
+	// DeprecatedRoutes maps "METHOD /route" patterns to "deprecated" or
+	// "deprecated:sunset" dates (YYYY-MM-DD).
+	DeprecatedRoutes map[string]string
+
+	FaceDetectionProvider string
+	FaceDetectionURL      string
+	FaceDetectionAPIKey   string
+	PhotoMinDimension     int
+
+	// StorageBackend is none, local, s3, minio or gcs; with none, photos
+	// stay in MongoDB.
+	StorageBackend   string
+	StorageLocalDir  string
+	StorageEndpoint  string
+	StorageRegion    string
+	StorageBucket    string
+	StorageAccessKey string
+	StorageSecretKey string
+	StoragePathStyle bool
+
+	// EventProducer is none or kafka. Events go to one topic per event
+	// type, prefixed with KafkaTopicPrefix.
+	EventProducer    string
+	KafkaBrokers     []string
+	KafkaTopicPrefix string
+
+	// AuthJWTSecret signs access and refresh tokens; the service does not
+	// start without it. AuthRequired rejects requests without an access token
+	// outside AuthPublicPaths. AuthAdmins and AuthDispatchers map user names
+	// to bcrypt password hashes.
+	AuthJWTSecret       string
+	AuthIssuer          string
+	AuthRequired        bool
+	AuthPublicPaths     []string
+	AuthAccessTokenTTL  time.Duration
+	AuthRefreshTokenTTL time.Duration
+	AuthAdmins          map[string]string
+	AuthDispatchers     map[string]string
+
+	ChaosEnabled bool
+
+	// DemoEnabled turns on the demo seeding routes and moves the seeded
+	// demo drivers every DemoMoveInterval. Never in production.
+	DemoEnabled      bool
+	DemoMoveInterval time.Duration
+
+	AdminUIEnabled bool
+
+	AnomalyMaxSpeedKmh float64
+	AnomalyTeleportKm  float64
+	AnomalyOnlineLimit time.Duration
+	AnomalyOnlineGap   time.Duration
+	AnomalyCooldown    time.Duration
+	AnomalyQueueSize   int
+
+	// GPS quality is scored from the updates of the last GPSQualityWindow;
+	// drivers scoring below GPSQualityPoorScore go last in ETA-sensitive
+	// dispatch.
+	GPSQualityWindow     time.Duration
+	GPSQualityMinSamples int
+	GPSQualityMaxGap     time.Duration
+	GPSQualityPoorScore  int
+
+	// LiveSubscriberBuffer is how many updates a /ws/drivers subscriber may
+	// fall behind before updates to it are dropped.
+	LiveSubscriberBuffer int
+	// LiveBackplane shares live locations, presence, sessions and offers
+	// between replicas: "none" or "redis" (at RedisURL).
+	LiveBackplane string
+	// LiveSessionTTL is how long a dropped /ws/drivers session can be
+	// resumed; LivePresenceTTL is how long a replica's claim on a driver
+	// outlives its last refresh.
+	LiveSessionTTL  time.Duration
+	LivePresenceTTL time.Duration
+}
+
+// defaultAuthPublicPaths stay open when AUTH_REQUIRED is set: health checks,
+// logins, the public and reference data, the admin UI assets and device
+// routes, which check device tokens themselves.
+var defaultAuthPublicPaths = []string{
+	"/",
+	"/health/*",
+	"/livez",
+	"/readyz",
+	"/routes",
+	"/metrics",
+	"/admin/*",
+	"/api/v1/auth/*",
+	"/api/v1/public/*",
+	"/api/v1/reference/*",
+	"/api/v1/device/*",
 }
 
 func LoadConfig() *Config {
@@ -16,6 +286,199 @@ func LoadConfig() *Config {
 		MongoDBURI:      getEnv("MONGODB_URI", "mongodb://localhost:27017"),
 		MongoDBDatabase: getEnv("MONGODB_DATABASE", "taxihub"),
 		ServerPort:      getEnv("SERVER_PORT", "9000"),
+		Environment:     getEnv("APP_ENV", "development"),
+		SandboxAPIKeys:  getEnvList("SANDBOX_API_KEYS"),
+		SandboxSeed:     getEnvInt64("SANDBOX_SEED", 42),
+
+		LogLevel:  getEnv("LOG_LEVEL", "info"),
+		LogFormat: getEnv("LOG_FORMAT", ""),
+
+		MongoDBConnectMaxWait:    getEnvDuration("MONGODB_CONNECT_MAX_WAIT", time.Minute),
+		MongoDBConnectBackoff:    getEnvDuration("MONGODB_CONNECT_BACKOFF", time.Second),
+		MongoDBConnectMaxBackoff: getEnvDuration("MONGODB_CONNECT_MAX_BACKOFF", 15*time.Second),
+
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+
+		PlateReservationTTL: getEnvDuration("PLATE_RESERVATION_TTL", 15*time.Minute),
+
+		DispatchBoostDuration:   getEnvDuration("DISPATCH_BOOST_DURATION", 14*24*time.Hour),
+		DispatchBoostCap:        getEnvInt("DISPATCH_BOOST_CAP", 10),
+		DispatchBoostMaxExtraKm: getEnvFloat("DISPATCH_BOOST_MAX_EXTRA_KM", 1),
+
+		DispatchOfferTimeout:       getEnvDuration("DISPATCH_OFFER_TIMEOUT", 20*time.Second),
+		DispatchOfferMaxAttempts:   getEnvInt("DISPATCH_OFFER_MAX_ATTEMPTS", 5),
+		DispatchOfferSweepInterval: getEnvDuration("DISPATCH_OFFER_SWEEP_INTERVAL", 5*time.Second),
+
+		TenantDatabases: getEnvMap("TENANT_DATABASES"),
+
+		BodyLogRoutes:    getEnvList("BODY_LOG_ROUTES"),
+		BodyLogFleets:    getEnvList("BODY_LOG_FLEETS"),
+		BodyLogRetention: getEnvDuration("BODY_LOG_RETENTION", 24*time.Hour),
+
+		GeocodingProvider:  getEnv("GEOCODING_PROVIDER", "none"),
+		GeocodingAPIKey:    getEnv("GEOCODING_API_KEY", ""),
+		GeocodingURL:       getEnv("GEOCODING_URL", ""),
+		GeocodingUserAgent: getEnv("GEOCODING_USER_AGENT", "taxihub-driver-service"),
+		GeocodingCacheTTL:  getEnvDuration("GEOCODING_CACHE_TTL", 24*time.Hour),
+
+		GeoPrecisionRules: getEnvMap("GEO_PRECISION"),
+
+		NearbyPollWindow:        getEnvDuration("NEARBY_POLL_WINDOW", time.Minute),
+		NearbyPollSoftLimit:     getEnvInt("NEARBY_POLL_SOFT_LIMIT", 30),
+		NearbyPollStep:          getEnvDuration("NEARBY_POLL_STEP", 500*time.Millisecond),
+		NearbyPollMaxInterval:   getEnvDuration("NEARBY_POLL_MAX_INTERVAL", 30*time.Second),
+		NearbySubscriptionURL:   getEnv("NEARBY_SUBSCRIPTION_URL", "/ws/drivers"),
+		NearbyCoalesceWindow:    getEnvDuration("NEARBY_COALESCE_WINDOW", time.Second),
+		NearbyCoalescePrecision: getEnvInt("NEARBY_COALESCE_PRECISION", 7),
+		NearbyBatchMaxPoints:    getEnvInt("NEARBY_BATCH_MAX_POINTS", 20),
+		NearbyBatchConcurrency:  getEnvInt("NEARBY_BATCH_CONCURRENCY", 5),
+		NearbyMinRadiusKm:       getEnvFloat("NEARBY_MIN_RADIUS_KM", 0.5),
+		NearbyMaxRadiusKm:       getEnvFloat("NEARBY_MAX_RADIUS_KM", 25),
+		NearbyMaxLimit:          getEnvInt("NEARBY_MAX_LIMIT", 100),
+		NearbyBackend:           getEnv("NEARBY_BACKEND", "mongo"),
+		RedisURL:                getEnv("REDIS_URL", "redis://localhost:6379/0"),
+
+		LocationBatchMaxPoints: getEnvInt("LOCATION_BATCH_MAX_POINTS", 1000),
+		LocationHistoryTTL:     getEnvDuration("LOCATION_HISTORY_TTL", 30*24*time.Hour),
+		LocationValidation:     getEnv("LOCATION_VALIDATION", "strict"),
+		LocationMaxAccuracyM:   getEnvFloat("LOCATION_MAX_ACCURACY_M", 500),
+		LocationMaxAge:         getEnvDuration("LOCATION_MAX_AGE", 48*time.Hour),
+
+		MaintenanceReminderInterval: getEnvDuration("MAINTENANCE_REMINDER_INTERVAL", time.Hour),
+
+		MailProvider:         getEnv("MAIL_PROVIDER", "none"),
+		MailFrom:             getEnv("MAIL_FROM", ""),
+		MailFromName:         getEnv("MAIL_FROM_NAME", "TaxiHub"),
+		MailSMTPHost:         getEnv("MAIL_SMTP_HOST", ""),
+		MailSMTPPort:         getEnvInt("MAIL_SMTP_PORT", 587),
+		MailSMTPUsername:     getEnv("MAIL_SMTP_USERNAME", ""),
+		MailSMTPPassword:     getEnv("MAIL_SMTP_PASSWORD", ""),
+		MailSendGridAPIKey:   getEnv("MAIL_SENDGRID_API_KEY", ""),
+		MailSendGridURL:      getEnv("MAIL_SENDGRID_URL", ""),
+		MailDeliveryInterval: getEnvDuration("MAIL_DELIVERY_INTERVAL", 15*time.Second),
+		MailMaxAttempts:      getEnvInt("MAIL_MAX_ATTEMPTS", 5),
+		MailRetryBackoff:     getEnvDuration("MAIL_RETRY_BACKOFF", time.Minute),
+
+		RatingPromptDelay:        getEnvDuration("RATING_PROMPT_DELAY", 5*time.Minute),
+		RatingPromptReminder:     getEnvDuration("RATING_PROMPT_REMINDER", 2*time.Hour),
+		RatingPromptMaxPrompts:   getEnvInt("RATING_PROMPT_MAX_PROMPTS", 3),
+		RatingPromptSendInterval: getEnvDuration("RATING_PROMPT_SEND_INTERVAL", 30*time.Second),
+
+		ChurnAlertThreshold: getEnvFloat("CHURN_ALERT_THRESHOLD", 0.2),
+		ChurnMinDrivers:     getEnvInt("CHURN_MIN_DRIVERS", 10),
+		ChurnZonePrecision:  getEnvInt("CHURN_ZONE_PRECISION", 5),
+		ChurnCheckInterval:  getEnvDuration("CHURN_CHECK_INTERVAL", 6*time.Hour),
+
+		DriverSchemaBackfillInterval:  getEnvDuration("DRIVER_SCHEMA_BACKFILL_INTERVAL", time.Hour),
+		DriverSchemaBackfillBatchSize: getEnvInt("DRIVER_SCHEMA_BACKFILL_BATCH_SIZE", 500),
+
+		ImportMaxRows:  getEnvInt("IMPORT_MAX_ROWS", 5000),
+		ImportStageTTL: getEnvDuration("IMPORT_STAGE_TTL", 24*time.Hour),
+
+		AlertWebhookURL:       getEnv("ALERT_WEBHOOK_URL", ""),
+		SLOWindow:             getEnvDuration("SLO_WINDOW", 30*24*time.Hour),
+		SLOAvailabilityTarget: getEnvFloat("SLO_AVAILABILITY_TARGET", 0.995),
+		SLOLatencyTarget:      getEnvFloat("SLO_LATENCY_TARGET", 0.99),
+		SLOLatencyThreshold:   getEnvDuration("SLO_LATENCY_THRESHOLD", 500*time.Millisecond),
+		SLOLatencyRoute:       getEnv("SLO_LATENCY_ROUTE", "/api/v1/drivers/nearby"),
+		SLOAlertHorizon:       getEnvDuration("SLO_ALERT_HORIZON", 24*time.Hour),
+		SLOEvaluationInterval: getEnvDuration("SLO_EVALUATION_INTERVAL", time.Minute),
+
+		WatchdogCheckInterval: getEnvDuration("WATCHDOG_CHECK_INTERVAL", 15*time.Second),
+		WatchdogStallTimeout:  getEnvDuration("WATCHDOG_STALL_TIMEOUT", 5*time.Minute),
+
+		IndexCheckInterval: getEnvDuration("INDEX_CHECK_INTERVAL", 5*time.Second),
+
+		HealthCheckInterval:   getEnvDuration("HEALTH_CHECK_INTERVAL", 5*time.Second),
+		HealthCheckMaxBackoff: getEnvDuration("HEALTH_CHECK_MAX_BACKOFF", time.Minute),
+
+		FeatureFlagCacheTTL: getEnvDuration("FEATURE_FLAG_CACHE_TTL", 30*time.Second),
+
+		PublicRateLimit:  getEnvInt("PUBLIC_RATE_LIMIT", 20),
+		PublicRateWindow: getEnvDuration("PUBLIC_RATE_WINDOW", time.Minute),
+
+		DispatchDefaultStrategy: getEnv("DISPATCH_DEFAULT_STRATEGY", "nearest"),
+		DispatchFleetStrategies: getEnvMap("DISPATCH_FLEET_STRATEGIES"),
+
+		OCRProvider:            getEnv("OCR_PROVIDER", "none"),
+		OCRURL:                 getEnv("OCR_URL", ""),
+		OCRAPIKey:              getEnv("OCR_API_KEY", ""),
+		OCRConfidenceThreshold: getEnvFloat("OCR_CONFIDENCE_THRESHOLD", 0.85),
+
+		LicenseClassRequirements: getEnvMap("LICENSE_CLASS_REQUIREMENTS"),
+
+		TaxiTariffs:     getEnvMap("TAXI_TARIFFS"),
+		TariffCurrency:  getEnv("TARIFF_CURRENCY", "TRY"),
+		ReferenceMaxAge: getEnvDuration("REFERENCE_MAX_AGE", time.Hour),
+
+		EarningsCommissionRate: getEnvFloat("EARNINGS_COMMISSION_RATE", 0.2),
+		EarningsTimezone:       getEnv("EARNINGS_TIMEZONE", "Europe/Istanbul"),
+
+		DeprecatedRoutes: getEnvMap("DEPRECATED_ROUTES"),
+
+		FaceDetectionProvider: getEnv("FACE_DETECTION_PROVIDER", "none"),
+		FaceDetectionURL:      getEnv("FACE_DETECTION_URL", ""),
+		FaceDetectionAPIKey:   getEnv("FACE_DETECTION_API_KEY", ""),
+		PhotoMinDimension:     getEnvInt("PHOTO_MIN_DIMENSION", 256),
+
+		StorageBackend:   getEnv("STORAGE_BACKEND", "none"),
+		StorageLocalDir:  getEnv("STORAGE_LOCAL_DIR", "./data/objects"),
+		StorageEndpoint:  getEnv("STORAGE_ENDPOINT", ""),
+		StorageRegion:    getEnv("STORAGE_REGION", "us-east-1"),
+		StorageBucket:    getEnv("STORAGE_BUCKET", ""),
+		StorageAccessKey: getEnv("STORAGE_ACCESS_KEY", ""),
+		StorageSecretKey: getEnv("STORAGE_SECRET_KEY", ""),
+		StoragePathStyle: getEnvBool("STORAGE_PATH_STYLE", false),
+
+		EventProducer:    getEnv("EVENT_PRODUCER", "none"),
+		KafkaBrokers:     getEnvList("KAFKA_BROKERS"),
+		KafkaTopicPrefix: getEnv("KAFKA_TOPIC_PREFIX", ""),
+
+		AuthJWTSecret:       getEnv("AUTH_JWT_SECRET", ""),
+		AuthIssuer:          getEnv("AUTH_ISSUER", "taxihub-driver-service"),
+		AuthRequired:        getEnvBool("AUTH_REQUIRED", false),
+		AuthPublicPaths:     getEnvList("AUTH_PUBLIC_PATHS"),
+		AuthAccessTokenTTL:  getEnvDuration("AUTH_ACCESS_TOKEN_TTL", 15*time.Minute),
+		AuthRefreshTokenTTL: getEnvDuration("AUTH_REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		AuthAdmins:          getEnvMap("AUTH_ADMINS"),
+		AuthDispatchers:     getEnvMap("AUTH_DISPATCHERS"),
+
+		ChaosEnabled: getEnvBool("CHAOS_ENABLED", false),
+
+		DemoEnabled:      getEnvBool("DEMO_ENABLED", false),
+		DemoMoveInterval: getEnvDuration("DEMO_MOVE_INTERVAL", 5*time.Second),
+
+		AdminUIEnabled: getEnvBool("ADMIN_UI_ENABLED", true),
+
+		AnomalyMaxSpeedKmh: getEnvFloat("ANOMALY_MAX_SPEED_KMH", 180),
+		AnomalyTeleportKm:  getEnvFloat("ANOMALY_TELEPORT_KM", 50),
+		AnomalyOnlineLimit: getEnvDuration("ANOMALY_ONLINE_LIMIT", 24*time.Hour),
+		AnomalyOnlineGap:   getEnvDuration("ANOMALY_ONLINE_GAP", 15*time.Minute),
+		AnomalyCooldown:    getEnvDuration("ANOMALY_COOLDOWN", 10*time.Minute),
+		AnomalyQueueSize:   getEnvInt("ANOMALY_QUEUE_SIZE", 10000),
+
+		GPSQualityWindow:     getEnvDuration("GPS_QUALITY_WINDOW", 15*time.Minute),
+		GPSQualityMinSamples: getEnvInt("GPS_QUALITY_MIN_SAMPLES", 5),
+		GPSQualityMaxGap:     getEnvDuration("GPS_QUALITY_MAX_GAP", 30*time.Second),
+		GPSQualityPoorScore:  getEnvInt("GPS_QUALITY_POOR_SCORE", 40),
+
+		LiveSubscriberBuffer: getEnvInt("LIVE_SUBSCRIBER_BUFFER", 64),
+		LiveBackplane:        getEnv("LIVE_BACKPLANE", "none"),
+		LiveSessionTTL:       getEnvDuration("LIVE_SESSION_TTL", 2*time.Minute),
+		LivePresenceTTL:      getEnvDuration("LIVE_PRESENCE_TTL", time.Minute),
+	}
+
+	if len(config.TenantDatabases) > 0 {
+		config.TenantMongoDBURIs = make(map[string]string)
+		for tenantID := range config.TenantDatabases {
+			if uri := getEnv(tenantEnvKey(tenantID), ""); uri != "" {
+				config.TenantMongoDBURIs[tenantID] = uri
+			}
+		}
+	}
+
+	if config.AuthPublicPaths == nil {
+		config.AuthPublicPaths = defaultAuthPublicPaths
 	}
 
 	if config.MongoDBURI == "" {
@@ -27,6 +490,21 @@ func LoadConfig() *Config {
 	if config.ServerPort == "" {
 		panic("SERVER_PORT is required")
 	}
+	if config.AuthRequired && config.AuthJWTSecret == "" {
+		panic("AUTH_JWT_SECRET is required when AUTH_REQUIRED is set")
+	}
+	if config.ChaosEnabled && config.IsProduction() {
+		panic("CHAOS_ENABLED must not be set in production")
+	}
+	if config.DemoEnabled && config.IsProduction() {
+		panic("DEMO_ENABLED must not be set in production")
+	}
+	if config.LogFormat == "" {
+		config.LogFormat = "text"
+		if config.IsProduction() {
+			config.LogFormat = "json"
+		}
+	}
 
 	return config
 }
@@ -38,6 +516,113 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+func getEnvList(key string) []string {
+	value := getEnv(key, "")
+	if value == "" {
+		return nil
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvMap parses comma-separated key=value pairs.
+func getEnvMap(key string) map[string]string {
+	items := getEnvList(key)
+	if len(items) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(items))
+	for _, item := range items {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			panic(fmt.Sprintf("%s must be a list of key=value pairs", key))
+		}
+		values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return values
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		panic(fmt.Sprintf("%s must be true or false", key))
+	}
+	return parsed
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Sprintf("%s must be an integer", key))
+	}
+	return parsed
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("%s must be an integer", key))
+	}
+	return parsed
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		panic(fmt.Sprintf("%s must be a number", key))
+	}
+	return parsed
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		panic(fmt.Sprintf("%s must be a duration (e.g. 30s, 5m)", key))
+	}
+	return parsed
+}
+
+func (c *Config) IsProduction() bool {
+	switch strings.ToLower(c.Environment) {
+	case "prod", "production":
+		return true
+	default:
+		return false
+	}
+}
+
 func (c *Config) GetServerAddress() string {
 	return fmt.Sprintf(":%s", c.ServerPort)
 }
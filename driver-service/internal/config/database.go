@@ -3,34 +3,129 @@ package config
 import (
 	"context"
 	"fmt"
-	"log"
-	"os"
-	"os/signal"
-	"syscall"
+	"log/slog"
 	"time"
+
+	"go.mongodb.org/mongo-driver/event"
 )
 
 type DatabaseManager struct {
-	mongoDB *MongoDB
-	config  *Config
+	mongoDB         *MongoDB
+	config          *Config
+	commandMonitors []*event.CommandMonitor
+	poolMonitor     *event.PoolMonitor
+
+	health *HealthMonitor
 }
 
 func NewDatabaseManager(config *Config) *DatabaseManager {
-	return &DatabaseManager{
+	dm := &DatabaseManager{
 		config: config,
 	}
+	dm.health = NewHealthMonitor(func(context.Context) error {
+		return dm.HealthCheck()
+	})
+	return dm
+}
+
+// AddCommandMonitor installs a MongoDB command monitor on the shared client;
+// it must be called before Initialize. Monitors see each event in the order
+// they were added.
+func (dm *DatabaseManager) AddCommandMonitor(monitor *event.CommandMonitor) {
+	dm.commandMonitors = append(dm.commandMonitors, monitor)
 }
 
+// SetPoolMonitor installs a connection pool monitor on the shared client; it
+// must be called before Initialize.
+func (dm *DatabaseManager) SetPoolMonitor(monitor *event.PoolMonitor) {
+	dm.poolMonitor = monitor
+}
+
+// Initialize connects to MongoDB and every tenant database. If MongoDB is not
+// reachable yet it retries with exponential backoff for up to
+// MongoDBConnectMaxWait, so the service survives starting before its
+// database.
 func (dm *DatabaseManager) Initialize() error {
-	mongoDB, err := ConnectMongoDB(dm.config.MongoDBURI, dm.config.MongoDBDatabase)
+	deadline := time.Now().Add(dm.config.MongoDBConnectMaxWait)
+	backoff := dm.config.MongoDBConnectBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := dm.connect()
+		if err == nil {
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 || backoff <= 0 {
+			return fmt.Errorf("giving up after %d attempt(s): %w", attempt, err)
+		}
+
+		wait := backoff
+		if wait > remaining {
+			wait = remaining
+		}
+		slog.Warn("MongoDB not available, retrying", "attempt", attempt, "error", err, "retry_in", wait.Round(time.Millisecond).String())
+		time.Sleep(wait)
+
+		backoff *= 2
+		if maxBackoff := dm.config.MongoDBConnectMaxBackoff; maxBackoff > 0 && backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (dm *DatabaseManager) connect() error {
+	mongoDB, err := ConnectMongoDB(dm.config.MongoDBURI, dm.config.MongoDBDatabase, chainCommandMonitors(dm.commandMonitors), dm.poolMonitor)
 	if err != nil {
 		return err
 	}
 
+	if len(dm.config.TenantDatabases) > 0 {
+		if err := mongoDB.ConnectTenants(dm.config.TenantDatabases, dm.config.TenantMongoDBURIs); err != nil {
+			mongoDB.Disconnect()
+			return err
+		}
+	}
+
 	dm.mongoDB = mongoDB
 	return nil
 }
 
+// chainCommandMonitors combines monitors into one, as a client takes only
+// one.
+func chainCommandMonitors(monitors []*event.CommandMonitor) *event.CommandMonitor {
+	switch len(monitors) {
+	case 0:
+		return nil
+	case 1:
+		return monitors[0]
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			for _, monitor := range monitors {
+				if monitor.Started != nil {
+					monitor.Started(ctx, e)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			for _, monitor := range monitors {
+				if monitor.Succeeded != nil {
+					monitor.Succeeded(ctx, e)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			for _, monitor := range monitors {
+				if monitor.Failed != nil {
+					monitor.Failed(ctx, e)
+				}
+			}
+		},
+	}
+}
+
 func (dm *DatabaseManager) GetMongoDB() *MongoDB {
 	return dm.mongoDB
 }
@@ -42,22 +137,6 @@ func (dm *DatabaseManager) Close() error {
 	return nil
 }
 
-func (dm *DatabaseManager) SetupGracefulShutdown() {
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
-	go func() {
-		sig := <-sigChan
-		log.Printf("Received signal: %v. Shutting down gracefully...", sig)
-
-		if err := dm.Close(); err != nil {
-			log.Printf("Error closing database connection: %v", err)
-		}
-
-		os.Exit(0)
-	}()
-}
-
 func (dm *DatabaseManager) HealthCheck() error {
 	if dm.mongoDB == nil {
 		return ErrDatabaseNotConnected
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	HealthStatusUnknown   = "unknown"
+	HealthStatusHealthy   = "healthy"
+	HealthStatusUnhealthy = "unhealthy"
+	HealthStatusDisabled  = "disabled"
+)
+
+// HealthStatus is the cached result of a background dependency check.
+type HealthStatus struct {
+	Status              string     `json:"status"`
+	Error               string     `json:"error,omitempty"`
+	CheckedAt           *time.Time `json:"checked_at,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+}
+
+func (s HealthStatus) Healthy() bool {
+	return s.Status == HealthStatusHealthy
+}
+
+// HealthMonitor runs a dependency check in the background and caches its
+// result, so probes report it without touching the dependency.
+type HealthMonitor struct {
+	check func(ctx context.Context) error
+
+	mu     sync.RWMutex
+	status HealthStatus
+}
+
+func NewHealthMonitor(check func(ctx context.Context) error) *HealthMonitor {
+	return &HealthMonitor{check: check}
+}
+
+// Health returns the last recorded status, or unknown before the first check.
+func (m *HealthMonitor) Health() HealthStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.status.Status == "" {
+		return HealthStatus{Status: HealthStatusUnknown}
+	}
+	return m.status
+}
+
+// Run checks every interval until ctx is cancelled. While checks fail the
+// interval doubles up to maxBackoff, so an outage is probed less often
+// instead of being hammered by every health request.
+func (m *HealthMonitor) Run(ctx context.Context, interval, maxBackoff time.Duration) {
+	for {
+		failures := m.record(m.check(ctx))
+
+		wait := interval
+		for i := 1; i < failures && wait < maxBackoff; i++ {
+			wait *= 2
+		}
+		if wait > maxBackoff && maxBackoff > interval {
+			wait = maxBackoff
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (m *HealthMonitor) record(err error) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	m.status.CheckedAt = &now
+	if err != nil {
+		m.status.Status = HealthStatusUnhealthy
+		m.status.Error = err.Error()
+		m.status.ConsecutiveFailures++
+	} else {
+		m.status.Status = HealthStatusHealthy
+		m.status.Error = ""
+		m.status.LastSuccessAt = &now
+		m.status.ConsecutiveFailures = 0
+	}
+	return m.status.ConsecutiveFailures
+}
+
+// Health returns the last recorded MongoDB status without touching MongoDB,
+// so health endpoints stay cheap during an outage.
+func (dm *DatabaseManager) Health() HealthStatus {
+	return dm.health.Health()
+}
+
+// RunHealthChecks pings MongoDB every interval until ctx is cancelled, backing
+// off while pings fail.
+func (dm *DatabaseManager) RunHealthChecks(ctx context.Context, interval, maxBackoff time.Duration) {
+	dm.health.Run(ctx, interval, maxBackoff)
+}
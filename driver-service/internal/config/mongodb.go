@@ -3,9 +3,10 @@ package config
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -14,9 +15,12 @@ import (
 type MongoDB struct {
 	Client   *mongo.Client
 	Database *mongo.Database
+
+	tenants       map[string]*MongoDB
+	tenantClients []*mongo.Client
 }
 
-func ConnectMongoDB(uri, database string) (*MongoDB, error) {
+func ConnectMongoDB(uri, database string, commandMonitor *event.CommandMonitor, poolMonitor *event.PoolMonitor) (*MongoDB, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -24,6 +28,12 @@ func ConnectMongoDB(uri, database string) (*MongoDB, error) {
 	clientOptions.SetMaxPoolSize(10)
 	clientOptions.SetMinPoolSize(5)
 	clientOptions.SetMaxConnIdleTime(30 * time.Second)
+	if commandMonitor != nil {
+		clientOptions.SetMonitor(commandMonitor)
+	}
+	if poolMonitor != nil {
+		clientOptions.SetPoolMonitor(poolMonitor)
+	}
 
 	// Connect to MongoDB
 	client, err := mongo.Connect(ctx, clientOptions)
@@ -32,17 +42,18 @@ func ConnectMongoDB(uri, database string) (*MongoDB, error) {
 	}
 
 	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		client.Disconnect(context.Background())
 		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
 	db := client.Database(database)
 
 	if err := db.RunCommand(ctx, map[string]interface{}{"ping": 1}).Err(); err != nil {
+		client.Disconnect(context.Background())
 		return nil, fmt.Errorf("failed to access database: %w", err)
 	}
 
-	log.Printf("Successfully connected to MongoDB at %s", uri)
-	log.Printf("Using database: %s", database)
+	slog.Info("Successfully connected to MongoDB", "uri", uri, "database", database)
 
 	return &MongoDB{
 		Client:   client,
@@ -58,11 +69,17 @@ func (m *MongoDB) Disconnect() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	for _, client := range m.tenantClients {
+		if err := client.Disconnect(ctx); err != nil {
+			slog.Error("Error disconnecting tenant client", "error", err)
+		}
+	}
+
 	if err := m.Client.Disconnect(ctx); err != nil {
 		return fmt.Errorf("failed to disconnect from MongoDB: %w", err)
 	}
 
-	log.Println("Successfully disconnected from MongoDB")
+	slog.Info("Successfully disconnected from MongoDB")
 	return nil
 }
 
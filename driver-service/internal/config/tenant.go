@@ -0,0 +1,130 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+type tenantContextKey struct{}
+
+// TenantKey is the fiber local (and request context value) holding the
+// tenant the request belongs to.
+var TenantKey = tenantContextKey{}
+
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	if tenantID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, TenantKey, tenantID)
+}
+
+func TenantFromContext(ctx context.Context) string {
+	tenantID, _ := ctx.Value(TenantKey).(string)
+	return tenantID
+}
+
+// tenantEnvKey is the per-tenant connection string variable, e.g.
+// TENANT_ACME_MONGODB_URI.
+func tenantEnvKey(tenantID string) string {
+	return "TENANT_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(tenantID)) + "_MONGODB_URI"
+}
+
+// ConnectTenants attaches an isolated database to each tenant. Tenants
+// without their own URI use a database on the shared client; tenants sharing
+// a URI share one cached client.
+func (m *MongoDB) ConnectTenants(databases, uris map[string]string) error {
+	clients := make(map[string]*mongo.Client)
+	m.tenants = make(map[string]*MongoDB, len(databases))
+
+	for tenantID, database := range databases {
+		client := m.Client
+		if uri := uris[tenantID]; uri != "" {
+			cached, ok := clients[uri]
+			if !ok {
+				var err error
+				if cached, err = connectTenantClient(uri); err != nil {
+					return fmt.Errorf("failed to connect tenant %s: %w", tenantID, err)
+				}
+				clients[uri] = cached
+			}
+			client = cached
+		}
+
+		m.tenants[tenantID] = &MongoDB{
+			Client:   client,
+			Database: client.Database(database),
+		}
+		slog.Info("Tenant isolated in its own database", "tenant", tenantID, "database", database)
+	}
+
+	for _, client := range clients {
+		m.tenantClients = append(m.tenantClients, client)
+	}
+	return nil
+}
+
+func connectTenantClient(uri string) (*mongo.Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	clientOptions := options.Client().ApplyURI(uri)
+	clientOptions.SetMaxPoolSize(10)
+	clientOptions.SetMaxConnIdleTime(30 * time.Second)
+
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+	return client, nil
+}
+
+// Tenant returns the isolated database of a tenant.
+func (m *MongoDB) Tenant(tenantID string) (*MongoDB, bool) {
+	tenant, ok := m.tenants[tenantID]
+	return tenant, ok
+}
+
+func (m *MongoDB) TenantIDs() []string {
+	ids := make([]string, 0, len(m.tenants))
+	for id := range m.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// For resolves the database for the tenant in ctx. Contexts without a tenant
+// use the shared database; requests naming a tenant that is not isolated are
+// rejected by the Tenant middleware before they get here.
+func (m *MongoDB) For(ctx context.Context) *MongoDB {
+	if tenant, ok := m.tenants[TenantFromContext(ctx)]; ok {
+		return tenant
+	}
+	return m
+}
+
+// ScopedCollection is a collection that lives in every tenant database.
+type ScopedCollection struct {
+	db   *MongoDB
+	name string
+}
+
+func (m *MongoDB) ScopedCollection(name string) ScopedCollection {
+	return ScopedCollection{db: m, name: name}
+}
+
+func (c ScopedCollection) For(ctx context.Context) *mongo.Collection {
+	return c.db.For(ctx).Database.Collection(c.name)
+}
@@ -0,0 +1,171 @@
+// Package deprecation keeps the list of routes slated for removal and counts
+// which callers still use them, so owners can be chased before the sunset.
+package deprecation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Rule marks one route pattern as deprecated.
+type Rule struct {
+	Method     string    `json:"method"`
+	Route      string    `json:"route"`
+	Deprecated time.Time `json:"deprecated"`
+	// Sunset is when the route will be removed; nil if not yet scheduled.
+	Sunset *time.Time `json:"sunset,omitempty"`
+}
+
+// ParseRules parses "METHOD /route" keys mapped to "deprecated" or
+// "deprecated:sunset" dates (YYYY-MM-DD, UTC).
+func ParseRules(values map[string]string) ([]Rule, error) {
+	rules := make([]Rule, 0, len(values))
+	for key, value := range values {
+		fields := strings.Fields(key)
+		if len(fields) != 2 || !strings.HasPrefix(fields[1], "/") {
+			return nil, fmt.Errorf("route %q must be \"METHOD /path\"", key)
+		}
+
+		rule := Rule{Method: strings.ToUpper(fields[0]), Route: normalizeRoute(fields[1])}
+		since, sunset, hasSunset := strings.Cut(value, ":")
+
+		var err error
+		if rule.Deprecated, err = time.Parse(dateLayout, strings.TrimSpace(since)); err != nil {
+			return nil, fmt.Errorf("route %q: invalid deprecation date %q", key, since)
+		}
+		if hasSunset {
+			at, err := time.Parse(dateLayout, strings.TrimSpace(sunset))
+			if err != nil {
+				return nil, fmt.Errorf("route %q: invalid sunset date %q", key, sunset)
+			}
+			if !at.After(rule.Deprecated) {
+				return nil, fmt.Errorf("route %q: sunset must be after the deprecation date", key)
+			}
+			rule.Sunset = &at
+		}
+		rules = append(rules, rule)
+	}
+
+	sortRules(rules)
+	return rules, nil
+}
+
+// Usage counts one caller's requests to one deprecated route.
+type Usage struct {
+	Method    string    `json:"method"`
+	Route     string    `json:"route"`
+	Caller    string    `json:"caller"`
+	Count     int64     `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+type usageKey struct {
+	method string
+	route  string
+	caller string
+}
+
+// Registry holds the deprecated routes and their usage since startup.
+type Registry struct {
+	rules map[string]Rule
+
+	mu    sync.Mutex
+	usage map[usageKey]*Usage
+}
+
+func NewRegistry(rules []Rule) *Registry {
+	byRoute := make(map[string]Rule, len(rules))
+	for _, rule := range rules {
+		byRoute[rule.Method+" "+rule.Route] = rule
+	}
+
+	return &Registry{
+		rules: byRoute,
+		usage: make(map[usageKey]*Usage),
+	}
+}
+
+// Lookup returns the rule for a matched route pattern, if it is deprecated.
+func (r *Registry) Lookup(method, route string) (Rule, bool) {
+	if len(r.rules) == 0 {
+		return Rule{}, false
+	}
+	rule, ok := r.rules[method+" "+normalizeRoute(route)]
+	return rule, ok
+}
+
+// Record counts a request to a deprecated route and reports whether it is the
+// caller's first one, so it can be logged once rather than on every request.
+func (r *Registry) Record(rule Rule, caller string, at time.Time) bool {
+	key := usageKey{method: rule.Method, route: rule.Route, caller: caller}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	usage, ok := r.usage[key]
+	if !ok {
+		usage = &Usage{Method: rule.Method, Route: rule.Route, Caller: caller, FirstSeen: at}
+		r.usage[key] = usage
+	}
+	usage.Count++
+	usage.LastSeen = at
+	return !ok
+}
+
+// Rules returns the deprecated routes ordered by route and method.
+func (r *Registry) Rules() []Rule {
+	rules := make([]Rule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		rules = append(rules, rule)
+	}
+	sortRules(rules)
+	return rules
+}
+
+// Report returns per-caller usage grouped by route, busiest callers first.
+func (r *Registry) Report() []Usage {
+	r.mu.Lock()
+	report := make([]Usage, 0, len(r.usage))
+	for _, usage := range r.usage {
+		report = append(report, *usage)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Caller < b.Caller
+	})
+	return report
+}
+
+func sortRules(rules []Rule) {
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Route != rules[j].Route {
+			return rules[i].Route < rules[j].Route
+		}
+		return rules[i].Method < rules[j].Method
+	})
+}
+
+func normalizeRoute(route string) string {
+	route = strings.TrimSpace(route)
+	if len(route) > 1 {
+		route = strings.TrimSuffix(route, "/")
+	}
+	return route
+}
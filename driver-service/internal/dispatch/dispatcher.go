@@ -0,0 +1,56 @@
+package dispatch
+
+import (
+	"fmt"
+)
+
+// Dispatcher picks the assignment strategy for a fleet. Fleets without an
+// explicit choice use the default strategy.
+type Dispatcher struct {
+	defaultStrategy Strategy
+	fleets          map[string]Strategy
+}
+
+// NewDispatcher builds one strategy instance per configured name so that
+// stateful strategies share rotation state across fleets using them, keyed
+// by fleet inside the strategy.
+func NewDispatcher(defaultName string, fleetStrategies map[string]string) (*Dispatcher, error) {
+	instances := make(map[string]Strategy)
+	resolve := func(name string) (Strategy, error) {
+		if strategy, ok := instances[name]; ok {
+			return strategy, nil
+		}
+		strategy, err := NewStrategy(name)
+		if err != nil {
+			return nil, err
+		}
+		instances[name] = strategy
+		return strategy, nil
+	}
+
+	defaultStrategy, err := resolve(defaultName)
+	if err != nil {
+		return nil, err
+	}
+
+	fleets := make(map[string]Strategy, len(fleetStrategies))
+	for fleetID, name := range fleetStrategies {
+		strategy, err := resolve(name)
+		if err != nil {
+			return nil, fmt.Errorf("fleet %s: %w", fleetID, err)
+		}
+		fleets[fleetID] = strategy
+	}
+
+	return &Dispatcher{
+		defaultStrategy: defaultStrategy,
+		fleets:          fleets,
+	}, nil
+}
+
+func (d *Dispatcher) StrategyFor(fleetID string) Strategy {
+	if strategy, ok := d.fleets[fleetID]; ok {
+		return strategy
+	}
+	return d.defaultStrategy
+}
@@ -0,0 +1,235 @@
+package dispatch
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+const (
+	StrategyNearest        = "nearest"
+	StrategyWeightedRandom = "weighted_random"
+	StrategyRoundRobin     = "round_robin"
+	StrategyBestMatch      = "best_match"
+)
+
+// Strategy orders nearby candidates by who should be offered a ride first.
+type Strategy interface {
+	Name() string
+	Rank(fleetID string, candidates []models.DriverWithDistance) []models.DriverWithDistance
+	// Assigned is called once a driver has been picked, for strategies that
+	// keep rotation state.
+	Assigned(fleetID, driverID string)
+}
+
+func NewStrategy(name string) (Strategy, error) {
+	switch name {
+	case StrategyNearest:
+		return Nearest{}, nil
+	case StrategyWeightedRandom:
+		return NewWeightedRandom(rand.NewSource(time.Now().UnixNano())), nil
+	case StrategyRoundRobin:
+		return NewRoundRobin(), nil
+	case StrategyBestMatch:
+		return NewBestMatch(), nil
+	default:
+		return nil, fmt.Errorf("unknown dispatch strategy %q (must be one of: %s, %s, %s, %s)",
+			name, StrategyNearest, StrategyWeightedRandom, StrategyRoundRobin, StrategyBestMatch)
+	}
+}
+
+// Nearest offers the closest driver first.
+type Nearest struct{}
+
+func (Nearest) Name() string { return StrategyNearest }
+
+func (Nearest) Rank(fleetID string, candidates []models.DriverWithDistance) []models.DriverWithDistance {
+	ranked := append([]models.DriverWithDistance(nil), candidates...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].DistanceKm < ranked[j].DistanceKm
+	})
+	return ranked
+}
+
+func (Nearest) Assigned(fleetID, driverID string) {}
+
+// WeightedRandom shuffles candidates with closer drivers more likely to come
+// first, so work spreads across a small fleet without sending far-away cars.
+// Weights fall off with the square of the distance.
+type WeightedRandom struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func NewWeightedRandom(source rand.Source) *WeightedRandom {
+	return &WeightedRandom{rng: rand.New(source)}
+}
+
+func (w *WeightedRandom) Name() string { return StrategyWeightedRandom }
+
+func (w *WeightedRandom) Rank(fleetID string, candidates []models.DriverWithDistance) []models.DriverWithDistance {
+	type keyed struct {
+		candidate models.DriverWithDistance
+		key       float64
+	}
+
+	w.mu.Lock()
+	items := make([]keyed, len(candidates))
+	for i, candidate := range candidates {
+		weight := 1 / math.Pow(candidate.DistanceKm+0.5, 2)
+		// Efraimidis-Spirakis: sorting by u^(1/w) yields a weighted
+		// permutation without replacement.
+		items[i] = keyed{candidate: candidate, key: math.Pow(w.rng.Float64(), 1/weight)}
+	}
+	w.mu.Unlock()
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].key > items[j].key
+	})
+
+	ranked := make([]models.DriverWithDistance, len(items))
+	for i, item := range items {
+		ranked[i] = item.candidate
+	}
+	return ranked
+}
+
+func (w *WeightedRandom) Assigned(fleetID, driverID string) {}
+
+// RoundRobin offers the driver who has waited longest since their last
+// assignment in the fleet, breaking ties by distance.
+type RoundRobin struct {
+	mu           sync.Mutex
+	lastAssigned map[string]map[string]time.Time
+	now          func() time.Time
+}
+
+func NewRoundRobin() *RoundRobin {
+	return &RoundRobin{
+		lastAssigned: make(map[string]map[string]time.Time),
+		now:          time.Now,
+	}
+}
+
+func (r *RoundRobin) Name() string { return StrategyRoundRobin }
+
+func (r *RoundRobin) Rank(fleetID string, candidates []models.DriverWithDistance) []models.DriverWithDistance {
+	r.mu.Lock()
+	assigned := make(map[string]time.Time, len(candidates))
+	for _, candidate := range candidates {
+		id := candidate.ID.Hex()
+		assigned[id] = r.lastAssigned[fleetID][id]
+	}
+	r.mu.Unlock()
+
+	ranked := append([]models.DriverWithDistance(nil), candidates...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		ti, tj := assigned[ranked[i].ID.Hex()], assigned[ranked[j].ID.Hex()]
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return ranked[i].DistanceKm < ranked[j].DistanceKm
+	})
+	return ranked
+}
+
+func (r *RoundRobin) Assigned(fleetID, driverID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.lastAssigned[fleetID] == nil {
+		r.lastAssigned[fleetID] = make(map[string]time.Time)
+	}
+	r.lastAssigned[fleetID][driverID] = r.now()
+}
+
+// Weights of the BestMatch score. They add up to one, so a score lies
+// between 0 (best) and 1.
+const (
+	bestMatchDistanceWeight = 0.5
+	bestMatchRatingWeight   = 0.25
+	bestMatchIdleWeight     = 0.25
+)
+
+// bestMatchIdleCap is how long a driver must have waited since their last
+// dispatch to count as fully idle.
+const bestMatchIdleCap = 30 * time.Minute
+
+// BestMatch scores candidates on distance, rating and time since their last
+// dispatch in the fleet, and offers the lowest score first. Distance is
+// relative to the farthest candidate; unrated drivers score as average, and
+// drivers never dispatched as fully idle. Ties go to the closer driver.
+type BestMatch struct {
+	mu             sync.Mutex
+	lastDispatched map[string]map[string]time.Time
+	now            func() time.Time
+}
+
+func NewBestMatch() *BestMatch {
+	return &BestMatch{
+		lastDispatched: make(map[string]map[string]time.Time),
+		now:            time.Now,
+	}
+}
+
+func (b *BestMatch) Name() string { return StrategyBestMatch }
+
+func (b *BestMatch) Rank(fleetID string, candidates []models.DriverWithDistance) []models.DriverWithDistance {
+	now := b.now()
+	b.mu.Lock()
+	idle := make(map[string]float64, len(candidates))
+	for _, candidate := range candidates {
+		id := candidate.ID.Hex()
+		last, ok := b.lastDispatched[fleetID][id]
+		if !ok {
+			idle[id] = 1
+			continue
+		}
+		idle[id] = math.Min(float64(now.Sub(last))/float64(bestMatchIdleCap), 1)
+	}
+	b.mu.Unlock()
+
+	farthest := 0.0
+	for _, candidate := range candidates {
+		farthest = math.Max(farthest, candidate.DistanceKm)
+	}
+
+	scores := make(map[string]float64, len(candidates))
+	for _, candidate := range candidates {
+		distance := 0.0
+		if farthest > 0 {
+			distance = candidate.DistanceKm / farthest
+		}
+		rating := 0.5
+		if candidate.RatingCount > 0 {
+			rating = (models.MaxRatingStars - candidate.AverageRating) / (models.MaxRatingStars - models.MinRatingStars)
+		}
+		id := candidate.ID.Hex()
+		scores[id] = bestMatchDistanceWeight*distance + bestMatchRatingWeight*rating + bestMatchIdleWeight*(1-idle[id])
+	}
+
+	ranked := append([]models.DriverWithDistance(nil), candidates...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		si, sj := scores[ranked[i].ID.Hex()], scores[ranked[j].ID.Hex()]
+		if si != sj {
+			return si < sj
+		}
+		return ranked[i].DistanceKm < ranked[j].DistanceKm
+	})
+	return ranked
+}
+
+func (b *BestMatch) Assigned(fleetID, driverID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.lastDispatched[fleetID] == nil {
+		b.lastDispatched[fleetID] = make(map[string]time.Time)
+	}
+	b.lastDispatched[fleetID][driverID] = b.now()
+}
@@ -0,0 +1,88 @@
+// Package drain counts in-flight requests so a graceful shutdown can report
+// how many requests it let finish and how many it had to cut off.
+package drain
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Tracker counts requests in flight. Between Begin and Finish it also counts
+// the requests that complete, which are the ones the shutdown drained.
+type Tracker struct {
+	mu        sync.Mutex
+	inFlight  int
+	draining  bool
+	startedAt time.Time
+	atStart   int
+	drained   int
+	now       func() time.Time
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{now: time.Now}
+}
+
+// Report summarises one graceful shutdown. String renders it as logfmt so
+// log-based metrics can pick the fields up.
+type Report struct {
+	// InFlight is the number of requests running when the shutdown began.
+	InFlight int
+	// Drained counts requests that completed during the drain, including
+	// ones that arrived on open keep-alive connections after it began.
+	Drained int
+	// ForceClosed counts requests still running when the drain gave up.
+	ForceClosed int
+	Duration    time.Duration
+	TimedOut    bool
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("in_flight=%d drained=%d force_closed=%d drain_time=%s timed_out=%t",
+		r.InFlight, r.Drained, r.ForceClosed, r.Duration, r.TimedOut)
+}
+
+// Start records a request entering the server.
+func (t *Tracker) Start() {
+	t.mu.Lock()
+	t.inFlight++
+	t.mu.Unlock()
+}
+
+// Done records a request leaving the server.
+func (t *Tracker) Done() {
+	t.mu.Lock()
+	t.inFlight--
+	if t.draining {
+		t.drained++
+	}
+	t.mu.Unlock()
+}
+
+// Begin marks the start of the drain.
+func (t *Tracker) Begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.draining = true
+	t.startedAt = t.now()
+	t.atStart = t.inFlight
+	t.drained = 0
+}
+
+// Finish ends the drain. Requests still in flight are counted as force
+// closed, since the process exits underneath them.
+func (t *Tracker) Finish(timedOut bool) Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.draining = false
+	return Report{
+		InFlight:    t.atStart,
+		Drained:     t.drained,
+		ForceClosed: t.inFlight,
+		Duration:    t.now().Sub(t.startedAt),
+		TimedOut:    timedOut,
+	}
+}
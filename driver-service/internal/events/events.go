@@ -0,0 +1,105 @@
+// Package events publishes driver lifecycle events so other services (trips,
+// pricing) can react without polling the driver API.
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DriverCreated         = "driver.created"
+	DriverUpdated         = "driver.updated"
+	DriverDeleted         = "driver.deleted"
+	DriverLocationUpdated = "driver.location_updated"
+
+	// TripOffered is keyed by the driver the trip is offered to.
+	TripOffered = "trip.offered"
+	// TripRatingPrompted asks the rider of a completed trip to rate it. It
+	// is keyed by the trip's driver.
+	TripRatingPrompted = "trip.rating_prompted"
+)
+
+// Event is the envelope of every published message. Data depends on Type:
+// the driver for created and updated, the location for location_updated,
+// the offer for trip.offered, the prompt for trip.rating_prompted, and
+// nothing for deleted.
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	TenantID   string      `json:"tenant_id,omitempty"`
+	DriverID   string      `json:"driver_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+// LocationData is the payload of driver.location_updated.
+type LocationData struct {
+	Lat        float64   `json:"lat"`
+	Lon        float64   `json:"lon"`
+	FleetID    string    `json:"fleet_id,omitempty"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// TripOfferData is the payload of trip.offered. The rider's preferences
+// are left out when they have none.
+type TripOfferData struct {
+	TripID      string    `json:"trip_id"`
+	FleetID     string    `json:"fleet_id,omitempty"`
+	PickupLat   float64   `json:"pickup_lat"`
+	PickupLon   float64   `json:"pickup_lon"`
+	DistanceKm  float64   `json:"distance_km"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	QuietRide   bool      `json:"quiet_ride,omitempty"`
+	Temperature string    `json:"temperature,omitempty"`
+	Music       string    `json:"music,omitempty"`
+}
+
+// RatingPromptData is the payload of trip.rating_prompted. Prompt numbers
+// the prompts of the trip from 1; Final is set on the last reminder.
+type RatingPromptData struct {
+	TripID  string `json:"trip_id"`
+	FleetID string `json:"fleet_id,omitempty"`
+	Prompt  int    `json:"prompt"`
+	Final   bool   `json:"final"`
+}
+
+func NewEvent(eventType, tenantID, driverID string, data interface{}) Event {
+	return Event{
+		ID:         primitive.NewObjectID().Hex(),
+		Type:       eventType,
+		TenantID:   tenantID,
+		DriverID:   driverID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// Producer hands events to a broker. Publish must not wait for the broker;
+// delivery failures are reported asynchronously.
+type Producer interface {
+	Publish(ctx context.Context, event Event) error
+	// Close flushes pending events.
+	Close() error
+	// Ping checks that the broker can be reached, for readiness probes.
+	Ping(ctx context.Context) error
+}
+
+// NewProducer returns the configured producer, or nil when events are
+// disabled.
+func NewProducer(name string, brokers []string, topicPrefix string) (Producer, error) {
+	switch name {
+	case "", "none":
+		return nil, nil
+	case "kafka":
+		if len(brokers) == 0 {
+			return nil, errors.New("kafka event producer requires KAFKA_BROKERS")
+		}
+		return NewKafkaProducer(brokers, topicPrefix), nil
+	default:
+		return nil, errors.New("unknown event producer: " + name)
+	}
+}
@@ -0,0 +1,167 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/taxihub/driver-service/internal/logging"
+)
+
+const (
+	kafkaQueueSize    = 1024
+	kafkaBatchSize    = 100
+	kafkaBatchTimeout = 100 * time.Millisecond
+	kafkaCloseTimeout = 10 * time.Second
+	kafkaPingTimeout  = 5 * time.Second
+)
+
+// KafkaProducer writes each event type to its own topic, named after the type
+// with an optional prefix ("taxihub.driver.created"). Messages are keyed by
+// driver ID so one driver's events stay ordered within a partition.
+//
+// Publish only queues the event: even an asynchronous kafka.Writer looks up
+// topic metadata on the caller's goroutine, and location updates must not
+// wait on the broker. Events are dropped when the queue is full.
+type KafkaProducer struct {
+	writer      *kafka.Writer
+	brokers     []string
+	topicPrefix string
+
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan kafka.Message
+	dropped atomic.Int64
+	done    chan struct{}
+	cancel  context.CancelFunc
+}
+
+func NewKafkaProducer(brokers []string, topicPrefix string) *KafkaProducer {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &KafkaProducer{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Balancer:               &kafka.Hash{},
+			BatchSize:              kafkaBatchSize,
+			BatchTimeout:           kafkaBatchTimeout,
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+		},
+		brokers:     brokers,
+		topicPrefix: topicPrefix,
+		queue:       make(chan kafka.Message, kafkaQueueSize),
+		done:        make(chan struct{}),
+		cancel:      cancel,
+	}
+	go p.run(ctx)
+	return p
+}
+
+func (p *KafkaProducer) Publish(ctx context.Context, event Event) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return errors.New("event producer is closed")
+	}
+
+	select {
+	case p.queue <- kafka.Message{
+		Topic: p.topicPrefix + event.Type,
+		Key:   []byte(event.DriverID),
+		Value: value,
+		Time:  event.OccurredAt,
+	}:
+	default:
+		p.dropped.Add(1)
+	}
+	return nil
+}
+
+// Ping connects to the first reachable broker and asks it for the cluster's
+// brokers, which fails unless the broker is serving requests.
+func (p *KafkaProducer) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, kafkaPingTimeout)
+	defer cancel()
+
+	var err error
+	for _, broker := range p.brokers {
+		var conn *kafka.Conn
+		conn, err = kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			continue
+		}
+		_, err = conn.Brokers()
+		conn.Close()
+		if err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("no kafka broker reachable: %w", err)
+}
+
+// Close stops accepting events and flushes the queue, giving up after
+// kafkaCloseTimeout when the broker is unreachable.
+func (p *KafkaProducer) Close() error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	var err error
+	select {
+	case <-p.done:
+	case <-time.After(kafkaCloseTimeout):
+		p.cancel()
+		<-p.done
+		err = fmt.Errorf("gave up flushing events after %s", kafkaCloseTimeout)
+	}
+	p.cancel()
+
+	if closeErr := p.writer.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// run writes queued events in batches of whatever has arrived since the
+// last write.
+func (p *KafkaProducer) run(ctx context.Context) {
+	defer close(p.done)
+
+	batch := make([]kafka.Message, 0, kafkaBatchSize)
+	for message := range p.queue {
+		batch = append(batch[:0], message)
+	fill:
+		for len(batch) < kafkaBatchSize {
+			select {
+			case message, ok := <-p.queue:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, message)
+			default:
+				break fill
+			}
+		}
+
+		if err := p.writer.WriteMessages(ctx, batch...); err != nil {
+			logging.FromContext(ctx).Error("Failed to publish events", "count", len(batch), "error", err)
+		}
+		if dropped := p.dropped.Swap(0); dropped > 0 {
+			logging.FromContext(ctx).Warn("Event producer dropped events (queue full)", "count", dropped)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package facedetect
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrProviderUnavailable = errors.New("face detection provider unavailable")
+
+// Detector counts the faces in an uploaded image.
+type Detector interface {
+	CountFaces(ctx context.Context, image []byte, contentType string) (int, error)
+}
+
+func NewDetector(name, baseURL, apiKey string) (Detector, error) {
+	switch name {
+	case "", "none":
+		return nil, nil
+	case "http":
+		if baseURL == "" {
+			return nil, errors.New("http face detection provider requires FACE_DETECTION_URL")
+		}
+		return NewHTTPDetector(baseURL, apiKey), nil
+	default:
+		return nil, errors.New("unknown face detection provider: " + name)
+	}
+}
@@ -0,0 +1,61 @@
+package facedetect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPDetector posts the raw image to a face detection service that answers
+// with {"faces": 1}.
+type HTTPDetector struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func NewHTTPDetector(url, apiKey string) *HTTPDetector {
+	return &HTTPDetector{
+		url:    url,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type httpResponse struct {
+	Faces *int `json:"faces"`
+}
+
+func (d *HTTPDetector) CountFaces(ctx context.Context, image []byte, contentType string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(image))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build face detection request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if d.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+d.apiKey)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%w: face detection returned status %d", ErrProviderUnavailable, resp.StatusCode)
+	}
+
+	var body httpResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("failed to decode face detection response: %w", err)
+	}
+	if body.Faces == nil {
+		return 0, fmt.Errorf("face detection response has no faces count")
+	}
+
+	return *body.Faces, nil
+}
@@ -0,0 +1,77 @@
+package geocoding
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+// cachePrecision rounds coordinates to roughly 100m cells so nearby lookups
+// share cache entries.
+const cachePrecision = 3
+
+type cacheEntry struct {
+	address   *models.Address
+	err       error
+	expiresAt time.Time
+}
+
+type CachedProvider struct {
+	provider   Provider
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func NewCachedProvider(provider Provider, ttl time.Duration, maxEntries int) *CachedProvider {
+	return &CachedProvider{
+		provider:   provider,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]cacheEntry),
+	}
+}
+
+func (p *CachedProvider) ReverseGeocode(ctx context.Context, lat, lon float64) (*models.Address, error) {
+	key := fmt.Sprintf("%.*f,%.*f", cachePrecision, lat, cachePrecision, lon)
+	now := time.Now()
+
+	p.mu.Lock()
+	if entry, ok := p.entries[key]; ok && now.Before(entry.expiresAt) {
+		p.mu.Unlock()
+		return entry.address, entry.err
+	}
+	p.mu.Unlock()
+
+	address, err := p.provider.ReverseGeocode(ctx, lat, lon)
+	// Provider outages are not cached; missing results are, to avoid
+	// repeatedly asking for coordinates in the sea or outside coverage.
+	if err != nil && err != ErrNoResult {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.entries) >= p.maxEntries {
+		p.evictExpired(now)
+	}
+	if len(p.entries) < p.maxEntries {
+		p.entries[key] = cacheEntry{address: address, err: err, expiresAt: now.Add(p.ttl)}
+	}
+
+	return address, err
+}
+
+func (p *CachedProvider) evictExpired(now time.Time) {
+	for key, entry := range p.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(p.entries, key)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+var (
+	ErrNoResult            = errors.New("no address found for coordinates")
+	ErrProviderUnavailable = errors.New("geocoding provider unavailable")
+)
+
+// Provider resolves coordinates to a human-readable address.
+type Provider interface {
+	ReverseGeocode(ctx context.Context, lat, lon float64) (*models.Address, error)
+}
+
+func NewProvider(name, apiKey, baseURL, userAgent string) (Provider, error) {
+	switch name {
+	case "", "none":
+		return nil, nil
+	case "nominatim":
+		return NewNominatimProvider(baseURL, userAgent), nil
+	case "google":
+		if apiKey == "" {
+			return nil, errors.New("google geocoding requires GEOCODING_API_KEY")
+		}
+		return NewGoogleProvider(apiKey), nil
+	default:
+		return nil, errors.New("unknown geocoding provider: " + name)
+	}
+}
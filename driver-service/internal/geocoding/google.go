@@ -0,0 +1,92 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+const googleGeocodeURL = "https://maps.googleapis.com/maps/api/geocode/json"
+
+type GoogleProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+func NewGoogleProvider(apiKey string) *GoogleProvider {
+	return &GoogleProvider{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+type googleResponse struct {
+	Status  string `json:"status"`
+	Results []struct {
+		FormattedAddress  string `json:"formatted_address"`
+		AddressComponents []struct {
+			LongName string   `json:"long_name"`
+			Types    []string `json:"types"`
+		} `json:"address_components"`
+	} `json:"results"`
+}
+
+func (p *GoogleProvider) ReverseGeocode(ctx context.Context, lat, lon float64) (*models.Address, error) {
+	query := url.Values{}
+	query.Set("latlng", fmt.Sprintf("%f,%f", lat, lon))
+	query.Set("key", p.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, googleGeocodeURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build google geocoding request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: google returned status %d", ErrProviderUnavailable, resp.StatusCode)
+	}
+
+	var body googleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode google geocoding response: %w", err)
+	}
+
+	switch body.Status {
+	case "OK":
+	case "ZERO_RESULTS":
+		return nil, ErrNoResult
+	default:
+		return nil, fmt.Errorf("%w: google returned status %s", ErrProviderUnavailable, body.Status)
+	}
+
+	result := body.Results[0]
+	address := &models.Address{FormattedAddress: result.FormattedAddress}
+	for _, component := range result.AddressComponents {
+		for _, componentType := range component.Types {
+			switch componentType {
+			case "neighborhood", "sublocality_level_1":
+				if address.Neighbourhood == "" {
+					address.Neighbourhood = component.LongName
+				}
+			case "administrative_area_level_2":
+				address.District = component.LongName
+			case "administrative_area_level_1":
+				address.City = component.LongName
+			case "country":
+				address.Country = component.LongName
+			}
+		}
+	}
+
+	return address, nil
+}
@@ -0,0 +1,98 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+const defaultNominatimURL = "https://nominatim.openstreetmap.org"
+
+type NominatimProvider struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+}
+
+func NewNominatimProvider(baseURL, userAgent string) *NominatimProvider {
+	if baseURL == "" {
+		baseURL = defaultNominatimURL
+	}
+	return &NominatimProvider{
+		baseURL:   baseURL,
+		userAgent: userAgent,
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+type nominatimResponse struct {
+	DisplayName string `json:"display_name"`
+	Error       string `json:"error"`
+	Address     struct {
+		Neighbourhood string `json:"neighbourhood"`
+		Suburb        string `json:"suburb"`
+		Town          string `json:"town"`
+		CityDistrict  string `json:"city_district"`
+		City          string `json:"city"`
+		Province      string `json:"province"`
+		Country       string `json:"country"`
+	} `json:"address"`
+}
+
+func (p *NominatimProvider) ReverseGeocode(ctx context.Context, lat, lon float64) (*models.Address, error) {
+	query := url.Values{}
+	query.Set("format", "jsonv2")
+	query.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	query.Set("lon", strconv.FormatFloat(lon, 'f', -1, 64))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/reverse?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build nominatim request: %w", err)
+	}
+	// Nominatim's usage policy requires an identifying user agent.
+	req.Header.Set("User-Agent", p.userAgent)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: nominatim returned status %d", ErrProviderUnavailable, resp.StatusCode)
+	}
+
+	var body nominatimResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode nominatim response: %w", err)
+	}
+	if body.Error != "" || body.DisplayName == "" {
+		return nil, ErrNoResult
+	}
+
+	district := firstNonEmpty(body.Address.CityDistrict, body.Address.Town, body.Address.Suburb)
+	city := firstNonEmpty(body.Address.City, body.Address.Province)
+
+	return &models.Address{
+		FormattedAddress: body.DisplayName,
+		Neighbourhood:    firstNonEmpty(body.Address.Neighbourhood, body.Address.Suburb),
+		District:         district,
+		City:             city,
+		Country:          body.Address.Country,
+	}, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package geohash
+
+import "strings"
+
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+type Box struct {
+	MinLat, MaxLat float64
+	MinLon, MaxLon float64
+}
+
+func (b Box) Center() (lat, lon float64) {
+	return (b.MinLat + b.MaxLat) / 2, (b.MinLon + b.MaxLon) / 2
+}
+
+// Encode returns the geohash of the given precision (number of characters)
+// containing the coordinates.
+func Encode(lat, lon float64, precision int) string {
+	box := Box{MinLat: -90, MaxLat: 90, MinLon: -180, MaxLon: 180}
+	var hash strings.Builder
+	hash.Grow(precision)
+
+	even := true
+	bit, ch := 0, 0
+	for hash.Len() < precision {
+		if even {
+			mid := (box.MinLon + box.MaxLon) / 2
+			if lon >= mid {
+				ch |= 1 << (4 - bit)
+				box.MinLon = mid
+			} else {
+				box.MaxLon = mid
+			}
+		} else {
+			mid := (box.MinLat + box.MaxLat) / 2
+			if lat >= mid {
+				ch |= 1 << (4 - bit)
+				box.MinLat = mid
+			} else {
+				box.MaxLat = mid
+			}
+		}
+		even = !even
+
+		if bit < 4 {
+			bit++
+		} else {
+			hash.WriteByte(base32[ch])
+			bit, ch = 0, 0
+		}
+	}
+
+	return hash.String()
+}
+
+// Valid reports whether hash is a non-empty geohash.
+func Valid(hash string) bool {
+	if hash == "" {
+		return false
+	}
+	for i := 0; i < len(hash); i++ {
+		if strings.IndexByte(base32, hash[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Decode returns the bounding box of a geohash. Invalid characters yield the
+// box decoded so far.
+func Decode(hash string) Box {
+	box := Box{MinLat: -90, MaxLat: 90, MinLon: -180, MaxLon: 180}
+	even := true
+	for i := 0; i < len(hash); i++ {
+		idx := strings.IndexByte(base32, hash[i])
+		if idx < 0 {
+			break
+		}
+		for bit := 4; bit >= 0; bit-- {
+			set := idx&(1<<bit) != 0
+			if even {
+				mid := (box.MinLon + box.MaxLon) / 2
+				if set {
+					box.MinLon = mid
+				} else {
+					box.MaxLon = mid
+				}
+			} else {
+				mid := (box.MinLat + box.MaxLat) / 2
+				if set {
+					box.MinLat = mid
+				} else {
+					box.MaxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return box
+}
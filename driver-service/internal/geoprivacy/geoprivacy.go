@@ -0,0 +1,102 @@
+// Package geoprivacy decides how precisely driver coordinates are shown in
+// responses, per endpoint and caller role.
+package geoprivacy
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+const (
+	PrecisionExact = "exact"
+	// PrecisionFuzzed snaps coordinates to a 0.001° grid, about 100m.
+	PrecisionFuzzed = "fuzzed"
+	// PrecisionCoarse snaps coordinates to a 0.01° grid, about 1km.
+	PrecisionCoarse = "coarse"
+)
+
+// Endpoints that return driver coordinates.
+const (
+	EndpointNearby   = "nearby"
+	EndpointDispatch = "dispatch"
+	EndpointDriver   = "driver"
+	EndpointLive     = "live"
+	EndpointTrace    = "trace"
+)
+
+var gridSizes = map[string]float64{
+	PrecisionFuzzed: 0.001,
+	PrecisionCoarse: 0.01,
+}
+
+var defaultPrecisions = map[string]string{
+	EndpointNearby:   PrecisionFuzzed,
+	EndpointDispatch: PrecisionExact,
+	EndpointDriver:   PrecisionExact,
+	EndpointLive:     PrecisionExact,
+	EndpointTrace:    PrecisionExact,
+}
+
+// Policy maps endpoints, optionally narrowed to a caller role, to a
+// precision.
+type Policy struct {
+	rules map[string]string
+}
+
+// NewPolicy overlays rules on the defaults. Keys are an endpoint ("nearby")
+// or an endpoint and role ("nearby.dispatcher"); values are precisions.
+func NewPolicy(rules map[string]string) (*Policy, error) {
+	policy := &Policy{rules: make(map[string]string, len(defaultPrecisions)+len(rules))}
+	for endpoint, precision := range defaultPrecisions {
+		policy.rules[endpoint] = precision
+	}
+
+	for key, precision := range rules {
+		endpoint, _, _ := strings.Cut(key, ".")
+		if _, ok := defaultPrecisions[endpoint]; !ok {
+			return nil, fmt.Errorf("unknown endpoint %q in geo precision rule %q", endpoint, key)
+		}
+		if precision != PrecisionExact && gridSizes[precision] == 0 {
+			return nil, fmt.Errorf("unknown precision %q in geo precision rule %q", precision, key)
+		}
+		policy.rules[strings.ToLower(key)] = precision
+	}
+
+	return policy, nil
+}
+
+// Precision returns the rule for the endpoint and role, falling back to the
+// endpoint's rule when the role has none.
+func (p *Policy) Precision(endpoint, role string) string {
+	if role != "" {
+		if precision, ok := p.rules[endpoint+"."+strings.ToLower(role)]; ok {
+			return precision
+		}
+	}
+	if precision, ok := p.rules[endpoint]; ok {
+		return precision
+	}
+	return PrecisionExact
+}
+
+// Apply returns location at the given precision. Snapping to a fixed grid,
+// unlike random jitter, cannot be averaged out by polling repeatedly.
+func Apply(location models.Location, precision string) models.Location {
+	size := gridSizes[precision]
+	if size == 0 {
+		return location
+	}
+
+	return models.Location{
+		Lat: snap(location.Lat, size),
+		Lon: snap(location.Lon, size),
+	}
+}
+
+func snap(value, size float64) float64 {
+	// Round the result too, so 41.01 does not come out as 41.010000000000005
+	return math.Round(math.Round(value/size)*size*1e6) / 1e6
+}
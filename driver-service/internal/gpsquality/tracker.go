@@ -0,0 +1,263 @@
+// Package gpsquality scores each driver's GPS signal from their recent
+// location updates: the accuracy radius the device reports, gaps between
+// updates, and jitter (short hops at speeds no taxi reaches).
+package gpsquality
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Reported accuracy at or below goodAccuracyM scores 100, at or above
+	// poorAccuracyM scores 0.
+	goodAccuracyM = 10.0
+	poorAccuracyM = 100.0
+	// jitterSpeedKmh is the implied speed above which a hop between two
+	// fixes counts as jitter.
+	jitterSpeedKmh = 150.0
+	// fairScore is the lowest score rated good.
+	fairScore     = 70
+	maxSamples    = 100
+	pruneInterval = time.Minute
+)
+
+type Config struct {
+	// Window is how far back samples are scored; drivers silent for longer
+	// are rated unknown.
+	Window time.Duration
+	// MinSamples is how many samples within Window a rating needs.
+	MinSamples int
+	// MaxGap is the longest interval between updates that is not a gap.
+	MaxGap time.Duration
+	// PoorScore is the score below which a driver is rated poor and
+	// deprioritized in ETA-bound dispatch.
+	PoorScore int
+}
+
+type driverKey struct {
+	tenantID string
+	driverID primitive.ObjectID
+}
+
+type sample struct {
+	at        time.Time
+	location  models.Location
+	accuracyM float64
+}
+
+type driverSamples struct {
+	fleetID string
+	samples []sample
+}
+
+// Tracker keeps each driver's recent samples in memory, so every replica
+// scores the updates it received.
+type Tracker struct {
+	cfg Config
+
+	mu        sync.Mutex
+	drivers   map[driverKey]*driverSamples
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+func NewTracker(cfg Config) *Tracker {
+	return &Tracker{
+		cfg:     cfg,
+		drivers: make(map[driverKey]*driverSamples),
+		now:     time.Now,
+	}
+}
+
+// Observe records a sample. Samples older than the driver's latest are
+// ignored.
+func (t *Tracker) Observe(update models.LocationSample) {
+	key := driverKey{tenantID: update.TenantID, driverID: update.DriverID}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if now.Sub(t.lastPrune) >= pruneInterval {
+		t.prune(now)
+		t.lastPrune = now
+	}
+
+	driver, ok := t.drivers[key]
+	if !ok {
+		driver = &driverSamples{}
+		t.drivers[key] = driver
+	}
+	if n := len(driver.samples); n > 0 && !update.RecordedAt.After(driver.samples[n-1].at) {
+		return
+	}
+
+	driver.fleetID = update.FleetID
+	driver.samples = append(driver.samples, sample{
+		at:        update.RecordedAt,
+		location:  update.Location,
+		accuracyM: update.AccuracyM,
+	})
+
+	cutoff := update.RecordedAt.Add(-t.cfg.Window)
+	drop := 0
+	if excess := len(driver.samples) - maxSamples; excess > 0 {
+		drop = excess
+	}
+	for drop < len(driver.samples) && driver.samples[drop].at.Before(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		driver.samples = append(driver.samples[:0], driver.samples[drop:]...)
+	}
+}
+
+// Quality rates the driver's signal over the last Window.
+func (t *Tracker) Quality(tenantID string, driverID primitive.ObjectID) models.GPSQuality {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	driver, ok := t.drivers[driverKey{tenantID: tenantID, driverID: driverID}]
+	if !ok {
+		return models.GPSQuality{DriverID: driverID, Level: models.GPSQualityUnknown}
+	}
+	return t.assess(driverID, driver, t.now())
+}
+
+// IsPoor reports whether the driver's signal is rated poor.
+func (t *Tracker) IsPoor(tenantID string, driverID primitive.ObjectID) bool {
+	return t.Quality(tenantID, driverID).Level == models.GPSQualityPoor
+}
+
+// Report rates every driver of the tenant seen within Window, worst first;
+// drivers without enough samples come last.
+func (t *Tracker) Report(tenantID string) []models.GPSQuality {
+	t.mu.Lock()
+	now := t.now()
+	report := make([]models.GPSQuality, 0, len(t.drivers))
+	for key, driver := range t.drivers {
+		if key.tenantID != tenantID {
+			continue
+		}
+		quality := t.assess(key.driverID, driver, now)
+		if quality.Samples > 0 {
+			report = append(report, quality)
+		}
+	}
+	t.mu.Unlock()
+
+	sort.Slice(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if (a.Score == nil) != (b.Score == nil) {
+			return a.Score != nil
+		}
+		if a.Score != nil && *a.Score != *b.Score {
+			return *a.Score < *b.Score
+		}
+		return a.DriverID.Hex() < b.DriverID.Hex()
+	})
+	return report
+}
+
+func (t *Tracker) assess(driverID primitive.ObjectID, driver *driverSamples, now time.Time) models.GPSQuality {
+	quality := models.GPSQuality{
+		DriverID: driverID,
+		FleetID:  driver.fleetID,
+		Level:    models.GPSQualityUnknown,
+	}
+
+	cutoff := now.Add(-t.cfg.Window)
+	var recent []sample
+	for _, s := range driver.samples {
+		if !s.at.Before(cutoff) {
+			recent = append(recent, s)
+		}
+	}
+	quality.Samples = len(recent)
+	if len(recent) == 0 {
+		return quality
+	}
+	last := recent[len(recent)-1].at
+	quality.LastSampleAt = &last
+	if len(recent) < t.cfg.MinSamples || len(recent) < 2 {
+		return quality
+	}
+
+	var accuracies []float64
+	for _, s := range recent {
+		if s.accuracyM > 0 {
+			accuracies = append(accuracies, s.accuracyM)
+		}
+	}
+	for i := 1; i < len(recent); i++ {
+		elapsed := recent[i].at.Sub(recent[i-1].at)
+		if elapsed > t.cfg.MaxGap {
+			quality.LongGaps++
+		}
+		distance := recent[i-1].location.DistanceKm(recent[i].location)
+		if distance/elapsed.Hours() > jitterSpeedKmh {
+			quality.JitterJumps++
+		}
+	}
+
+	intervals := float64(len(recent) - 1)
+	gapScore := 1 - float64(quality.LongGaps)/intervals
+	jitterScore := 1 - float64(quality.JitterJumps)/intervals
+	quality.GapScore = percent(gapScore)
+	quality.JitterScore = percent(jitterScore)
+
+	score := 0.5*gapScore + 0.5*jitterScore
+	if len(accuracies) > 0 {
+		median := medianOf(accuracies)
+		accuracyScore := clamp((poorAccuracyM - median) / (poorAccuracyM - goodAccuracyM))
+		quality.MedianAccuracyM = &median
+		quality.AccuracyScore = percent(accuracyScore)
+		score = 0.4*accuracyScore + 0.3*gapScore + 0.3*jitterScore
+	}
+	quality.Score = percent(score)
+
+	switch {
+	case *quality.Score < t.cfg.PoorScore:
+		quality.Level = models.GPSQualityPoor
+	case *quality.Score < fairScore:
+		quality.Level = models.GPSQualityFair
+	default:
+		quality.Level = models.GPSQualityGood
+	}
+	return quality
+}
+
+// prune forgets drivers with no sample within Window.
+func (t *Tracker) prune(now time.Time) {
+	cutoff := now.Add(-t.cfg.Window)
+	for key, driver := range t.drivers {
+		if n := len(driver.samples); n == 0 || driver.samples[n-1].at.Before(cutoff) {
+			delete(t.drivers, key)
+		}
+	}
+}
+
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func clamp(value float64) float64 {
+	return math.Max(0, math.Min(1, value))
+}
+
+func percent(value float64) *int {
+	p := int(math.Round(clamp(value) * 100))
+	return &p
+}
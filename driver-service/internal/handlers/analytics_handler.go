@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/geohash"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+type AnalyticsHandler struct {
+	forecasts service.AvailabilityForecastService
+}
+
+func NewAnalyticsHandler(forecasts service.AvailabilityForecastService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		forecasts: forecasts,
+	}
+}
+
+// RegisterRoutes registers the analytics routes for admins and dispatchers.
+func (h *AnalyticsHandler) RegisterRoutes(app *fiber.App) {
+	analytics := app.Group("/api/v1/analytics", middleware.RequireRole(auth.RoleAdmin, auth.RoleDispatcher))
+	{
+		analytics.Get("/availability-forecast", h.GetAvailabilityForecast)
+	}
+}
+
+// GetAvailabilityForecast forecasts the drivers per zone and hour. Zones are
+// geohash cells given as zones=a,b or as the cell around lat and lon.
+func (h *AnalyticsHandler) GetAvailabilityForecast(c *fiber.Ctx) error {
+	var req models.AvailabilityForecastRequest
+	if zones := c.Query("zones"); zones != "" {
+		for _, zone := range strings.Split(zones, ",") {
+			if zone = strings.ToLower(strings.TrimSpace(zone)); zone != "" {
+				req.Zones = append(req.Zones, zone)
+			}
+		}
+	} else if c.Query("lat") != "" || c.Query("lon") != "" {
+		lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+		lon, lonErr := strconv.ParseFloat(c.Query("lon"), 64)
+		if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			return errorResponse(c, http.StatusBadRequest, "lat and lon must be valid coordinates", nil)
+		}
+		req.Zones = []string{geohash.Encode(lat, lon, models.DefaultForecastZonePrecision)}
+	}
+
+	if hoursStr := c.Query("hours"); hoursStr != "" {
+		hours, err := strconv.Atoi(hoursStr)
+		if err != nil || hours < 1 || hours > models.MaxForecastHours {
+			return errorResponse(c, http.StatusBadRequest, fmt.Sprintf("hours must be between 1 and %d", models.MaxForecastHours), nil)
+		}
+		req.Hours = hours
+	}
+	if weeksStr := c.Query("weeks"); weeksStr != "" {
+		weeks, err := strconv.Atoi(weeksStr)
+		if err != nil || weeks < 1 {
+			return errorResponse(c, http.StatusBadRequest, "weeks must be a positive number", nil)
+		}
+		req.Weeks = weeks
+	}
+
+	forecast, err := h.forecasts.Forecast(c.Context(), req)
+	if err != nil {
+		if errors.Is(err, service.ErrValidationFailed) {
+			return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to forecast driver availability", []string{err.Error()})
+	}
+
+	return c.JSON(forecast)
+}
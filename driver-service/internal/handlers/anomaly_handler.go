@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AnomalyHandler struct {
+	anomalyService service.AnomalyService
+}
+
+func NewAnomalyHandler(anomalyService service.AnomalyService) *AnomalyHandler {
+	return &AnomalyHandler{
+		anomalyService: anomalyService,
+	}
+}
+
+func (h *AnomalyHandler) RegisterRoutes(app *fiber.App) {
+	admin := app.Group("/api/v1/admin/anomalies", middleware.RequireRole(auth.RoleAdmin))
+	{
+		admin.Get("/", h.ListAnomalies)
+		admin.Post("/:anomalyId/review", h.ReviewAnomaly)
+	}
+}
+
+func (h *AnomalyHandler) ListAnomalies(c *fiber.Ctx) error {
+	status := c.Query("status", models.AnomalyOpen)
+	switch status {
+	case models.AnomalyOpen, models.AnomalyResolved, models.AnomalyDismissed, "all":
+	default:
+		return errorResponse(c, http.StatusBadRequest, "Invalid status", []string{"status must be one of: open resolved dismissed all"})
+	}
+	if status == "all" {
+		status = ""
+	}
+
+	severity := c.Query("severity")
+	switch severity {
+	case "", models.AnomalySeverityMedium, models.AnomalySeverityHigh:
+	default:
+		return errorResponse(c, http.StatusBadRequest, "Invalid severity", []string{"severity must be one of: medium high"})
+	}
+
+	anomalies, err := h.anomalyService.List(c.Context(), status, severity, c.QueryInt("limit", 100))
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list anomalies", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": anomalies,
+	})
+}
+
+func (h *AnomalyHandler) ReviewAnomaly(c *fiber.Ctx) error {
+	id := c.Params("anomalyId")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid anomaly ID format", nil)
+	}
+
+	var req models.ReviewAnomalyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	anomaly, err := h.anomalyService.Review(c.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAnomalyNotFound):
+			return errorResponse(c, http.StatusNotFound, "Anomaly not found", nil)
+		case errors.Is(err, service.ErrAnomalyNotOpen):
+			return errorResponse(c, http.StatusConflict, "Anomaly has already been reviewed", nil)
+		default:
+			return errorResponse(c, http.StatusInternalServerError, "Failed to review anomaly", []string{err.Error()})
+		}
+	}
+
+	return c.JSON(anomaly)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AuthHandler struct {
+	authService service.AuthService
+}
+
+func NewAuthHandler(authService service.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+func (h *AuthHandler) RegisterRoutes(app *fiber.App) {
+	authGroup := app.Group("/api/v1/auth")
+	{
+		authGroup.Post("/login", h.Login)
+		authGroup.Post("/refresh", h.Refresh)
+	}
+
+	app.Put("/api/v1/drivers/:id/password", middleware.RequireSelfOrRole(auth.RoleAdmin), h.SetPassword)
+}
+
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req models.LoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	tokens, err := h.authService.Login(c.Context(), &req)
+	if err != nil {
+		return authError(c, err, "Failed to log in")
+	}
+
+	return c.JSON(tokens)
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token.
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req models.RefreshTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	tokens, err := h.authService.Refresh(c.Context(), &req)
+	if err != nil {
+		return authError(c, err, "Failed to refresh token")
+	}
+
+	return c.JSON(tokens)
+}
+
+// SetPassword sets the driver's login password. Only the driver themselves
+// and admins reach it; the driver must confirm the current password, while
+// admins may set it directly.
+func (h *AuthHandler) SetPassword(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	var req models.SetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	claims, authenticated := middleware.AuthClaims(c)
+	privileged := authenticated && claims.IsAdmin()
+
+	if err := h.authService.SetPassword(c.Context(), id, &req, privileged); err != nil {
+		return authError(c, err, "Failed to set password")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Password updated successfully",
+	})
+}
+
+func authError(c *fiber.Ctx, err error, failure string) error {
+	switch {
+	case errors.Is(err, service.ErrAuthDisabled):
+		return errorResponse(c, http.StatusServiceUnavailable, "Authentication is not configured", nil)
+	case errors.Is(err, service.ErrInvalidCredentials):
+		return errorResponse(c, http.StatusUnauthorized, "Invalid credentials", nil)
+	case errors.Is(err, service.ErrInvalidRefreshToken):
+		return errorResponse(c, http.StatusUnauthorized, "Invalid refresh token", nil)
+	case errors.Is(err, service.ErrPasswordRequired):
+		return errorResponse(c, http.StatusBadRequest, "Current password is required", nil)
+	case errors.Is(err, service.ErrDriverNotFound):
+		return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+	default:
+		return errorResponse(c, http.StatusInternalServerError, failure, []string{err.Error()})
+	}
+}
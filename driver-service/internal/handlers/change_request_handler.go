@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ChangeRequestHandler struct {
+	changeRequestService service.ChangeRequestService
+}
+
+func NewChangeRequestHandler(changeRequestService service.ChangeRequestService) *ChangeRequestHandler {
+	return &ChangeRequestHandler{
+		changeRequestService: changeRequestService,
+	}
+}
+
+func (h *ChangeRequestHandler) RegisterRoutes(app *fiber.App) {
+	staffOrSelf := middleware.RequireSelfOrRole(auth.RoleAdmin, auth.RoleDispatcher)
+	adminOrSelf := middleware.RequireSelfOrRole(auth.RoleAdmin)
+
+	driver := app.Group("/api/v1/drivers/:id/change-requests")
+	{
+		driver.Post("/", adminOrSelf, h.SubmitChangeRequest)
+		driver.Get("/", staffOrSelf, h.ListDriverChangeRequests)
+	}
+
+	admin := app.Group("/api/v1/admin/change-requests", middleware.RequireRole(auth.RoleAdmin))
+	{
+		admin.Get("/", h.ListChangeRequests)
+		admin.Post("/:requestId/approve", h.ApproveChangeRequest)
+		admin.Post("/:requestId/reject", h.RejectChangeRequest)
+	}
+}
+
+func (h *ChangeRequestHandler) SubmitChangeRequest(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	var req models.SubmitChangeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	request, err := h.changeRequestService.Submit(c.Context(), id, &req)
+	if err != nil {
+		return changeRequestError(c, err, "Failed to submit change request")
+	}
+
+	return c.Status(http.StatusAccepted).JSON(request)
+}
+
+func (h *ChangeRequestHandler) ListDriverChangeRequests(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	requests, err := h.changeRequestService.ListForDriver(c.Context(), id)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list change requests", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": requests,
+	})
+}
+
+func (h *ChangeRequestHandler) ListChangeRequests(c *fiber.Ctx) error {
+	status := c.Query("status", models.ChangeRequestPending)
+	switch status {
+	case models.ChangeRequestPending, models.ChangeRequestApproved, models.ChangeRequestRejected, "all":
+	default:
+		return errorResponse(c, http.StatusBadRequest, "Invalid status", []string{"status must be one of: pending approved rejected all"})
+	}
+	if status == "all" {
+		status = ""
+	}
+
+	requests, err := h.changeRequestService.ListByStatus(c.Context(), status, c.QueryInt("limit", 100))
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list change requests", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": requests,
+	})
+}
+
+func (h *ChangeRequestHandler) ApproveChangeRequest(c *fiber.Ctx) error {
+	return h.review(c, h.changeRequestService.Approve, "Failed to approve change request")
+}
+
+func (h *ChangeRequestHandler) RejectChangeRequest(c *fiber.Ctx) error {
+	return h.review(c, h.changeRequestService.Reject, "Failed to reject change request")
+}
+
+func (h *ChangeRequestHandler) review(c *fiber.Ctx, decide func(ctx context.Context, id string, req *models.ReviewChangeRequest) (*models.DriverChangeRequest, error), failure string) error {
+	id := c.Params("requestId")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid change request ID format", nil)
+	}
+
+	var req models.ReviewChangeRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	request, err := decide(c.Context(), id, &req)
+	if err != nil {
+		return changeRequestError(c, err, failure)
+	}
+
+	return c.JSON(request)
+}
+
+func changeRequestError(c *fiber.Ctx, err error, failure string) error {
+	switch {
+	case errors.Is(err, service.ErrDriverNotFound):
+		return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+	case errors.Is(err, service.ErrChangeRequestNotFound):
+		return errorResponse(c, http.StatusNotFound, "Change request not found", nil)
+	case errors.Is(err, service.ErrNoChanges):
+		return errorResponse(c, http.StatusBadRequest, "No changes requested", []string{err.Error()})
+	case errors.Is(err, service.ErrChangeRequestPending),
+		errors.Is(err, service.ErrChangeRequestNotPending),
+		errors.Is(err, service.ErrPlateTaken),
+		errors.Is(err, service.ErrLicenseClassNotPermitted):
+		return errorResponse(c, http.StatusConflict, failure, []string{err.Error()})
+	default:
+		return errorResponse(c, http.StatusInternalServerError, failure, []string{err.Error()})
+	}
+}
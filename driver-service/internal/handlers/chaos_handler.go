@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/chaos"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+type ChaosHandler struct {
+	injector *chaos.Injector
+}
+
+func NewChaosHandler(injector *chaos.Injector) *ChaosHandler {
+	return &ChaosHandler{
+		injector: injector,
+	}
+}
+
+func (h *ChaosHandler) RegisterRoutes(app *fiber.App) {
+	admin := app.Group("/api/v1/admin/chaos", middleware.RequireRole(auth.RoleAdmin))
+	{
+		admin.Get("/", h.ListFaults)
+		admin.Delete("/", h.ClearFaults)
+		admin.Put("/:kind", h.SetFault)
+		admin.Delete("/:kind", h.RemoveFault)
+	}
+}
+
+func (h *ChaosHandler) ListFaults(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"faults": h.injector.Faults(),
+	})
+}
+
+func (h *ChaosHandler) SetFault(c *fiber.Ctx) error {
+	var req models.SetChaosFaultRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	fault, err := h.injector.Set(c.Params("kind"), &req)
+	if err != nil {
+		if errors.Is(err, chaos.ErrUnknownFault) {
+			return errorResponse(c, http.StatusBadRequest, "Unknown chaos fault",
+				[]string{"kind must be one of: " + models.ChaosMongoLatency + ", " + models.ChaosDropAlerts})
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to set chaos fault", []string{err.Error()})
+	}
+
+	return c.JSON(fault)
+}
+
+func (h *ChaosHandler) RemoveFault(c *fiber.Ctx) error {
+	if err := h.injector.Remove(c.Params("kind")); err != nil {
+		if errors.Is(err, chaos.ErrFaultNotFound) {
+			return errorResponse(c, http.StatusNotFound, "Chaos fault not active", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to remove chaos fault", []string{err.Error()})
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func (h *ChaosHandler) ClearFaults(c *fiber.Ctx) error {
+	h.injector.Clear()
+	return c.SendStatus(http.StatusNoContent)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+type ChurnHandler struct {
+	churnService service.ChurnService
+}
+
+func NewChurnHandler(churnService service.ChurnService) *ChurnHandler {
+	return &ChurnHandler{
+		churnService: churnService,
+	}
+}
+
+// RegisterRoutes registers the admin churn report and alert list.
+func (h *ChurnHandler) RegisterRoutes(app *fiber.App) {
+	churn := app.Group("/api/v1/admin/churn", middleware.RequireRole(auth.RoleAdmin))
+	{
+		churn.Get("/", h.GetReport)
+		churn.Get("/alerts", h.ListAlerts)
+	}
+}
+
+// GetReport compares the drivers active over the last week with the week
+// before, by fleet and zone, and lists those who went inactive.
+func (h *ChurnHandler) GetReport(c *fiber.Ctx) error {
+	report, err := h.churnService.Report(c.Context(), c.Query("fleet_id"), c.QueryInt("zone_precision", models.DefaultChurnZonePrecision))
+	if err != nil {
+		if errors.Is(err, service.ErrValidationFailed) {
+			return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to build churn report", []string{err.Error()})
+	}
+
+	return c.JSON(report)
+}
+
+func (h *ChurnHandler) ListAlerts(c *fiber.Ctx) error {
+	alerts, err := h.churnService.ListAlerts(c.Context(), c.Query("fleet_id"), c.QueryInt("limit", models.DefaultChurnAlertLimit))
+	if err != nil {
+		if errors.Is(err, service.ErrValidationFailed) {
+			return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list churn alerts", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": alerts,
+	})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+type DemoHandler struct {
+	demoService service.DemoService
+}
+
+func NewDemoHandler(demoService service.DemoService) *DemoHandler {
+	return &DemoHandler{
+		demoService: demoService,
+	}
+}
+
+// RegisterRoutes registers the demo routes. They are only registered outside
+// production, with DEMO_ENABLED set.
+func (h *DemoHandler) RegisterRoutes(app *fiber.App) {
+	demo := app.Group("/api/v1/admin/demo", middleware.RequireRole(auth.RoleAdmin))
+	{
+		demo.Post("/seed", h.Seed)
+		demo.Get("/scenario", h.GetScenario)
+	}
+}
+
+// Seed wipes the tenant's database and seeds the demo scenario. The body is
+// optional.
+func (h *DemoHandler) Seed(c *fiber.Ctx) error {
+	var req models.SeedDemoRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	result, err := h.demoService.Seed(c.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrValidationFailed) {
+			return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to seed demo scenario", []string{err.Error()})
+	}
+
+	return c.Status(http.StatusCreated).JSON(result)
+}
+
+func (h *DemoHandler) GetScenario(c *fiber.Ctx) error {
+	scenario, err := h.demoService.Scenario(c.Context())
+	if err != nil {
+		if errors.Is(err, service.ErrDemoScenarioNotFound) {
+			return errorResponse(c, http.StatusNotFound, "No demo scenario has been seeded", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to get demo scenario", []string{err.Error()})
+	}
+
+	return c.JSON(scenario)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/deprecation"
+	"github.com/taxihub/driver-service/internal/middleware"
+)
+
+type DeprecationHandler struct {
+	registry *deprecation.Registry
+}
+
+func NewDeprecationHandler(registry *deprecation.Registry) *DeprecationHandler {
+	return &DeprecationHandler{
+		registry: registry,
+	}
+}
+
+func (h *DeprecationHandler) RegisterRoutes(app *fiber.App) {
+	admin := app.Group("/api/v1/admin")
+	admin.Get("/deprecations", middleware.RequireRole(auth.RoleAdmin), h.GetDeprecationReport)
+}
+
+// GetDeprecationReport lists the deprecated routes and which callers still
+// use them since the service started.
+func (h *DeprecationHandler) GetDeprecationReport(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"routes": h.registry.Rules(),
+		"usage":  h.registry.Report(),
+	})
+}
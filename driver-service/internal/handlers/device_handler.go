@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DeviceHandler struct {
+	tokenService  service.DeviceTokenService
+	driverService service.DriverService
+}
+
+func NewDeviceHandler(tokenService service.DeviceTokenService, driverService service.DriverService) *DeviceHandler {
+	return &DeviceHandler{
+		tokenService:  tokenService,
+		driverService: driverService,
+	}
+}
+
+func (h *DeviceHandler) RegisterRoutes(app *fiber.App) {
+	device := app.Group("/api/v1/device")
+	{
+		device.Put("/location", middleware.DeviceAuth(h.tokenService, models.ScopeLocationWrite), h.PushLocation)
+	}
+
+	admin := app.Group("/api/v1/admin/drivers/:id/device-tokens", middleware.RequireRole(auth.RoleAdmin))
+	{
+		admin.Post("/", h.IssueToken)
+		admin.Get("/", h.ListTokens)
+		admin.Post("/:tokenId/rotate", h.RotateToken)
+		admin.Delete("/:tokenId", h.RevokeToken)
+	}
+}
+
+// PushLocation updates the location of the driver the device token is bound
+// to; the body cannot name another driver.
+func (h *DeviceHandler) PushLocation(c *fiber.Ctx) error {
+	token, ok := middleware.DeviceToken(c)
+	if !ok {
+		return errorResponse(c, http.StatusUnauthorized, "Device token required", nil)
+	}
+
+	var req models.UpdateLocationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	if err := h.driverService.UpdateDriverLocation(c.Context(), token.DriverID.Hex(), &req); err != nil {
+		if errors.Is(err, service.ErrDriverNotFound) {
+			return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to update driver location", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Location updated successfully",
+	})
+}
+
+func (h *DeviceHandler) IssueToken(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	var req models.IssueDeviceTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	issued, err := h.tokenService.Issue(c.Context(), id, &req)
+	if err != nil {
+		return deviceTokenError(c, err, "Failed to issue device token")
+	}
+
+	return c.Status(http.StatusCreated).JSON(issued)
+}
+
+func (h *DeviceHandler) ListTokens(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	tokens, err := h.tokenService.List(c.Context(), id)
+	if err != nil {
+		return deviceTokenError(c, err, "Failed to list device tokens")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": tokens,
+	})
+}
+
+func (h *DeviceHandler) RotateToken(c *fiber.Ctx) error {
+	id, tokenID, ok := deviceTokenParams(c)
+	if !ok {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver or device token ID format", nil)
+	}
+
+	var req models.RotateDeviceTokenRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	issued, err := h.tokenService.Rotate(c.Context(), id, tokenID, &req)
+	if err != nil {
+		return deviceTokenError(c, err, "Failed to rotate device token")
+	}
+
+	return c.JSON(issued)
+}
+
+func (h *DeviceHandler) RevokeToken(c *fiber.Ctx) error {
+	id, tokenID, ok := deviceTokenParams(c)
+	if !ok {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver or device token ID format", nil)
+	}
+
+	token, err := h.tokenService.Revoke(c.Context(), id, tokenID)
+	if err != nil {
+		return deviceTokenError(c, err, "Failed to revoke device token")
+	}
+
+	return c.JSON(token)
+}
+
+func deviceTokenParams(c *fiber.Ctx) (string, string, bool) {
+	id, tokenID := c.Params("id"), c.Params("tokenId")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return "", "", false
+	}
+	if _, err := primitive.ObjectIDFromHex(tokenID); err != nil {
+		return "", "", false
+	}
+	return id, tokenID, true
+}
+
+func deviceTokenError(c *fiber.Ctx, err error, failure string) error {
+	switch {
+	case errors.Is(err, service.ErrDriverNotFound):
+		return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+	case errors.Is(err, service.ErrDeviceTokenNotFound):
+		return errorResponse(c, http.StatusNotFound, "Device token not found", nil)
+	case errors.Is(err, service.ErrDeviceTokenRevoked):
+		return errorResponse(c, http.StatusConflict, "Device token has been revoked", nil)
+	default:
+		return errorResponse(c, http.StatusInternalServerError, failure, []string{err.Error()})
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/geoprivacy"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DispatchHandler struct {
+	dispatchService service.DispatchService
+	geoPolicy       *geoprivacy.Policy
+}
+
+func NewDispatchHandler(dispatchService service.DispatchService, geoPolicy *geoprivacy.Policy) *DispatchHandler {
+	return &DispatchHandler{
+		dispatchService: dispatchService,
+		geoPolicy:       geoPolicy,
+	}
+}
+
+func (h *DispatchHandler) RegisterRoutes(app *fiber.App) {
+	dispatch := app.Group("/api/v1/dispatch")
+	dispatch.Post("/assign", middleware.RequireRole(auth.RoleAdmin, auth.RoleDispatcher), h.AssignDriver)
+
+	app.Get("/api/v1/admin/drivers/:id/boost", middleware.RequireRole(auth.RoleAdmin), h.GetBoostStatus)
+}
+
+func (h *DispatchHandler) AssignDriver(c *fiber.Ctx) error {
+	var req models.AssignDriverRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+	if req.FleetID == "" {
+		req.FleetID = c.Get(middleware.FleetIDHeader)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	assignment, err := h.dispatchService.Assign(c.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrNoDriversAvailable) {
+			return errorResponse(c, http.StatusNotFound, "No drivers available", nil)
+		}
+		var paused *service.DispatchPausedError
+		if errors.As(err, &paused) {
+			retryAfter := math.Ceil(time.Until(paused.Pause.EndsAt).Seconds())
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Max(retryAfter, 1))))
+			return errorResponse(c, http.StatusServiceUnavailable, "Dispatch is paused in this area", []string{
+				paused.Pause.Name + ": " + paused.Pause.Reason,
+				"paused until " + paused.Pause.EndsAt.Format(time.RFC3339),
+			})
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to assign driver", []string{err.Error()})
+	}
+
+	// Driver points into Candidates, so this covers it too
+	precision := locationPrecision(c, h.geoPolicy, geoprivacy.EndpointDispatch)
+	for _, candidate := range assignment.Candidates {
+		candidate.Location = geoprivacy.Apply(candidate.Location, precision)
+	}
+
+	return c.JSON(assignment)
+}
+
+// GetBoostStatus shows how much of the cold-start boost a driver has left.
+func (h *DispatchHandler) GetBoostStatus(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	status, err := h.dispatchService.BoostStatus(c.Context(), id)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to get boost status", []string{err.Error()})
+	}
+
+	return c.JSON(status)
+}
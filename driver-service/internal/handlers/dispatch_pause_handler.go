@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+type DispatchPauseHandler struct {
+	pauseService service.DispatchPauseService
+}
+
+func NewDispatchPauseHandler(pauseService service.DispatchPauseService) *DispatchPauseHandler {
+	return &DispatchPauseHandler{
+		pauseService: pauseService,
+	}
+}
+
+func (h *DispatchPauseHandler) RegisterRoutes(app *fiber.App) {
+	pauses := app.Group("/api/v1/admin/dispatch-pauses")
+	{
+		pauses.Post("/", h.CreatePause)
+		pauses.Get("/", h.ListPauses)
+		pauses.Delete("/:id", h.CancelPause)
+	}
+}
+
+func (h *DispatchPauseHandler) CreatePause(c *fiber.Ctx) error {
+	var req models.CreateDispatchPauseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	pause, err := h.pauseService.Create(c.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPauseWindow) {
+			return errorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to create dispatch pause", []string{err.Error()})
+	}
+
+	return c.Status(http.StatusCreated).JSON(pause)
+}
+
+func (h *DispatchPauseHandler) ListPauses(c *fiber.Ctx) error {
+	pauses, err := h.pauseService.List(c.Context())
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list dispatch pauses", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": pauses,
+	})
+}
+
+func (h *DispatchPauseHandler) CancelPause(c *fiber.Ctx) error {
+	if err := h.pauseService.Cancel(c.Context(), c.Params("id")); err != nil {
+		if errors.Is(err, service.ErrDispatchPauseNotFound) {
+			return errorResponse(c, http.StatusNotFound, "Dispatch pause not found", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to cancel dispatch pause", []string{err.Error()})
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
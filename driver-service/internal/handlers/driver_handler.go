@@ -1,44 +1,106 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/geocoding"
+	"github.com/taxihub/driver-service/internal/geoprivacy"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/middleware"
 	"github.com/taxihub/driver-service/internal/models"
-	"github.com/taxihub/driver-service/internal/repository"
 	"github.com/taxihub/driver-service/internal/service"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// NearbyConfig bounds the nearby searches.
+type NearbyConfig struct {
+	// MinRadiusKm, MaxRadiusKm and MaxLimit bound the radius_km and limit
+	// query parameters of GET /drivers/nearby.
+	MinRadiusKm float64
+	MaxRadiusKm float64
+	MaxLimit    int
+	// BatchMaxPoints and BatchConcurrency bound POST /drivers/nearby/batch:
+	// how many points one call may carry and how many of their queries run
+	// at once.
+	BatchMaxPoints   int
+	BatchConcurrency int
+}
+
 type DriverHandler struct {
-	driverService service.DriverService
-	validator     *validator.Validate
+	driverService   service.DriverService
+	sandboxServices *service.SandboxServices
+	geocoder        geocoding.Provider
+	geoPolicy       *geoprivacy.Policy
+	nearby          NearbyConfig
+	validator       *validator.Validate
+
+	locationBatchMaxPoints int
 }
 
-func NewDriverHandler(driverService service.DriverService) *DriverHandler {
+func NewDriverHandler(driverService service.DriverService, sandboxServices *service.SandboxServices, geocoder geocoding.Provider, geoPolicy *geoprivacy.Policy, nearby NearbyConfig, locationBatchMaxPoints int) *DriverHandler {
+	if nearby.BatchConcurrency < 1 {
+		nearby.BatchConcurrency = 1
+	}
 	return &DriverHandler{
-		driverService: driverService,
-		validator:     validator.New(),
+		driverService:   driverService,
+		sandboxServices: sandboxServices,
+		geocoder:        geocoder,
+		geoPolicy:       geoPolicy,
+		nearby:          nearby,
+		validator:       validator.New(),
+
+		locationBatchMaxPoints: locationBatchMaxPoints,
 	}
 }
 
+// RegisterRoutes registers the driver routes with the roles that may call
+// them: admins manage drivers, dispatchers list and search them, and drivers
+// read and update their own record.
 func (h *DriverHandler) RegisterRoutes(app *fiber.App) {
 	v1 := app.Group("/api/v1")
 
+	adminOnly := middleware.RequireRole(auth.RoleAdmin)
+	staff := middleware.RequireRole(auth.RoleAdmin, auth.RoleDispatcher)
+	staffOrSelf := middleware.RequireSelfOrRole(auth.RoleAdmin, auth.RoleDispatcher)
+	adminOrSelf := middleware.RequireSelfOrRole(auth.RoleAdmin)
+
 	drivers := v1.Group("/drivers")
 	{
-		drivers.Post("/", h.CreateDriver)
-		drivers.Get("/", h.ListDrivers)
-		drivers.Get("/:id", h.GetDriver)
-		drivers.Put("/:id", h.UpdateDriver)
-		drivers.Delete("/:id", h.DeleteDriver)
-		drivers.Get("/nearby", h.FindNearbyDrivers)
-		drivers.Put("/:id/location", h.UpdateDriverLocation)
+		drivers.Post("/", adminOnly, h.CreateDriver)
+		drivers.Get("/", staff, h.ListDrivers)
+		drivers.Get("/nearby", staff, h.FindNearbyDrivers) // before /:id, which would otherwise match it
+		drivers.Post("/nearby/batch", staff, h.FindNearbyDriversBatch)
+		drivers.Get("/search", staff, h.SearchDrivers)
+		drivers.Get("/by-plate/:plate", staff, h.GetDriverByPlate)
+		drivers.Get("/:id", staffOrSelf, h.GetDriver)
+		drivers.Get("/:id/card", staffOrSelf, h.GetDriverCard)
+		drivers.Put("/:id", adminOrSelf, h.UpdateDriver)
+		drivers.Delete("/:id", adminOnly, h.DeleteDriver)
+		drivers.Put("/:id/location", adminOrSelf, h.UpdateDriverLocation)
+		drivers.Post("/:id/locations/batch", adminOrSelf, h.UpdateDriverLocations)
+		drivers.Get("/:id/locations", staffOrSelf, h.GetLocationTrace)
+		drivers.Put("/:id/status", adminOrSelf, h.UpdateDriverStatus)
+		drivers.Post("/:id/approve", adminOnly, h.ApproveDriver)
+		drivers.Post("/:id/reject", adminOnly, h.RejectDriver)
+		drivers.Post("/:id/suspend", adminOnly, h.SuspendDriver)
+	}
+
+	admin := v1.Group("/admin/drivers", adminOnly)
+	{
+		admin.Post("/:id/verify", h.VerifyDriver)
+		admin.Delete("/:id/verify", h.UnverifyDriver)
+		admin.Get("/:id/license-overrides", h.ListLicenseOverrides)
 	}
 }
 
@@ -50,23 +112,12 @@ func (h *DriverHandler) CreateDriver(c *fiber.Ctx) error {
 
 	// Validate requests
 	if err := req.Validate(); err != nil {
-		var validationErrors []string
-		if validationErr, ok := err.(validator.ValidationErrors); ok {
-			for _, e := range validationErr {
-				validationErrors = append(validationErrors, h.formatValidationError(e))
-			}
-		} else {
-			validationErrors = append(validationErrors, err.Error())
-		}
-		return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", validationErrors)
+		return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
 	}
 
-	driverID, err := h.driverService.CreateDriver(c.Context(), &req)
+	driverID, err := h.serviceFor(c).CreateDriver(c.Context(), &req)
 	if err != nil {
-		if errors.Is(err, service.ErrDriverAlreadyExists) {
-			return h.ErrorResponse(c, http.StatusConflict, "Driver with this plate already exists", nil)
-		}
-		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to create driver", []string{err.Error()})
+		return h.HandleServiceErrors(c, err)
 	}
 
 	return c.Status(http.StatusCreated).JSON(fiber.Map{
@@ -87,30 +138,19 @@ func (h *DriverHandler) UpdateDriver(c *fiber.Ctx) error {
 
 	// Validate requests
 	if err := req.Validate(); err != nil {
-		var validationErrors []string
-		if validationErr, ok := err.(validator.ValidationErrors); ok {
-			for _, e := range validationErr {
-				validationErrors = append(validationErrors, h.formatValidationError(e))
-			}
-		} else {
-			validationErrors = append(validationErrors, err.Error())
-		}
-		return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", validationErrors)
+		return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
 	}
 
-	if err := h.driverService.UpdateDriver(c.Context(), id, &req); err != nil {
-		if errors.Is(err, service.ErrDriverNotFound) {
-			return h.ErrorResponse(c, http.StatusNotFound, "Driver not found", nil)
-		}
-		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to update driver", []string{err.Error()})
+	if err := h.serviceFor(c).UpdateDriver(c.Context(), id, &req); err != nil {
+		return h.HandleServiceErrors(c, err)
 	}
 
-	driver, err := h.driverService.GetDriverByID(c.Context(), id)
+	driver, err := h.serviceFor(c).GetDriverByID(c.Context(), id)
 	if err != nil {
-		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch updated driver", []string{err.Error()})
+		return h.HandleServiceErrors(c, err)
 	}
 
-	return c.JSON(models.NewDriverResponse(driver))
+	return c.JSON(h.driverResponse(c, models.NewDriverResponse(driver)))
 }
 
 func (h *DriverHandler) GetDriver(c *fiber.Ctx) error {
@@ -119,7 +159,45 @@ func (h *DriverHandler) GetDriver(c *fiber.Ctx) error {
 		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
 	}
 
-	driver, err := h.driverService.GetDriverByID(c.Context(), id)
+	driver, err := h.serviceFor(c).GetDriverByID(c.Context(), id)
+	if err != nil {
+		return h.HandleServiceErrors(c, err)
+	}
+
+	if c.QueryBool("unmasked") {
+		return c.JSON(h.driverResponse(c, models.NewUnmaskedDriverResponse(driver)))
+	}
+	return c.JSON(h.driverResponse(c, models.NewDriverResponse(driver)))
+}
+
+// GetDriverByPlate looks a driver up by plate for dispatchers reading it off
+// the car. Spacing and case do not matter; spaces may be sent URL-encoded.
+func (h *DriverHandler) GetDriverByPlate(c *fiber.Ctx) error {
+	plate, err := url.PathUnescape(c.Params("plate"))
+	if err != nil || plate == "" {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid plate", nil)
+	}
+
+	driver, err := h.serviceFor(c).GetDriverByPlate(c.Context(), plate)
+	if err != nil {
+		if errors.Is(err, service.ErrDriverNotFound) {
+			return h.ErrorResponse(c, http.StatusNotFound, "Driver not found", nil)
+		}
+		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to get driver", []string{err.Error()})
+	}
+
+	return c.JSON(h.driverResponse(c, models.NewDriverResponse(driver)))
+}
+
+// GetDriverCard returns the rider-facing driver card localized for the
+// request's Accept-Language header, or the lang query parameter if given.
+func (h *DriverHandler) GetDriverCard(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if !h.isValidObjectID(id) {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	driver, err := h.serviceFor(c).GetDriverByID(c.Context(), id)
 	if err != nil {
 		if errors.Is(err, service.ErrDriverNotFound) {
 			return h.ErrorResponse(c, http.StatusNotFound, "Driver not found", nil)
@@ -127,7 +205,15 @@ func (h *DriverHandler) GetDriver(c *fiber.Ctx) error {
 		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to get driver", []string{err.Error()})
 	}
 
-	return c.JSON(models.NewDriverResponse(driver))
+	preferred := models.ParseAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+	if lang := c.Query("lang"); lang != "" {
+		preferred = append([]string{lang}, preferred...)
+	}
+
+	card := models.NewDriverCardResponse(driver, preferred)
+	c.Set(fiber.HeaderContentLanguage, card.Locale)
+	c.Vary(fiber.HeaderAcceptLanguage)
+	return c.JSON(card)
 }
 
 func (h *DriverHandler) ListDrivers(c *fiber.Ctx) error {
@@ -150,8 +236,28 @@ func (h *DriverHandler) ListDrivers(c *fiber.Ctx) error {
 		}
 	}
 
-	response, err := h.driverService.ListDrivers(c.Context(), page, pageSize)
+	filter := models.DriverListFilter{
+		TaxiType:     c.Query("taxi_type"),
+		CarBrand:     strings.TrimSpace(c.Query("car_brand")),
+		Status:       c.Query("status"),
+		ReviewStatus: c.Query("review_status"),
+		FleetID:      c.Query("fleet_id"),
+		CustomFields: customFieldQuery(c),
+		Sort:         c.Query("sort"),
+	}
+	if createdAfterStr := c.Query("created_after"); createdAfterStr != "" {
+		parsed, err := time.Parse(time.RFC3339, createdAfterStr)
+		if err != nil {
+			return h.ErrorResponse(c, http.StatusBadRequest, "created_after must be an RFC 3339 time", nil)
+		}
+		filter.CreatedAfter = parsed
+	}
+
+	response, err := h.serviceFor(c).ListDrivers(c.Context(), page, pageSize, filter)
 	if err != nil {
+		if errors.Is(err, service.ErrValidationFailed) {
+			return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+		}
 		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to list drivers", []string{err.Error()})
 	}
 
@@ -163,7 +269,42 @@ func (h *DriverHandler) ListDrivers(c *fiber.Ctx) error {
 		TotalPages: response.TotalPages,
 	}
 
-	return c.JSON(models.NewListDriversResponse(serviceResp))
+	list := models.NewListDriversResponse(serviceResp)
+	precision := locationPrecision(c, h.geoPolicy, geoprivacy.EndpointDriver)
+	for i := range list.Data {
+		list.Data[i].Location = geoprivacy.Apply(list.Data[i].Location, precision)
+	}
+
+	return c.JSON(list)
+}
+
+// SearchDrivers finds drivers for call-center agents from a name or plate,
+// best matches first.
+func (h *DriverHandler) SearchDrivers(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	pageSize := c.QueryInt("pageSize", 20)
+
+	response, err := h.serviceFor(c).SearchDrivers(c.Context(), c.Query("q"), page, pageSize)
+	if err != nil {
+		if errors.Is(err, service.ErrValidationFailed) {
+			return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+		}
+		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to search drivers", []string{err.Error()})
+	}
+
+	list := models.NewListDriversResponse(&models.PaginatedServiceResponse{
+		Data:       response.Data,
+		Page:       response.Page,
+		PageSize:   response.PageSize,
+		TotalCount: response.TotalCount,
+		TotalPages: response.TotalPages,
+	})
+	precision := locationPrecision(c, h.geoPolicy, geoprivacy.EndpointDriver)
+	for i := range list.Data {
+		list.Data[i].Location = geoprivacy.Apply(list.Data[i].Location, precision)
+	}
+
+	return c.JSON(list)
 }
 
 func (h *DriverHandler) DeleteDriver(c *fiber.Ctx) error {
@@ -172,14 +313,12 @@ func (h *DriverHandler) DeleteDriver(c *fiber.Ctx) error {
 		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
 	}
 
-	if err := h.driverService.DeleteDriver(c.Context(), id); err != nil {
-		if errors.Is(err, service.ErrDriverNotFound) {
-			return h.ErrorResponse(c, http.StatusNotFound, "Driver not found", nil)
-		}
-		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete driver", []string{err.Error()})
+	report, err := h.serviceFor(c).DeleteDriver(c.Context(), id)
+	if err != nil {
+		return h.HandleServiceErrors(c, err)
 	}
 
-	return c.Status(http.StatusNoContent).Send(nil)
+	return c.Status(http.StatusOK).JSON(report)
 }
 
 func (h *DriverHandler) FindNearbyDrivers(c *fiber.Ctx) error {
@@ -201,28 +340,163 @@ func (h *DriverHandler) FindNearbyDrivers(c *fiber.Ctx) error {
 		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid longitude format", nil)
 	}
 
-	drivers, err := h.driverService.FindNearbyDrivers(c.Context(), lat, lon, taxiType)
+	filter := models.NearbyFilter{
+		TaxiType:     taxiType,
+		VerifiedOnly: c.QueryBool("verified_only"),
+
+		IncludeUnavailable: c.QueryBool("include_unavailable"),
+	}
+	if ratingStr := c.Query("min_rating"); ratingStr != "" {
+		minRating, err := strconv.ParseFloat(ratingStr, 64)
+		if err != nil || minRating < models.MinRatingStars || minRating > models.MaxRatingStars {
+			return h.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("min_rating must be between %d and %d", models.MinRatingStars, models.MaxRatingStars), nil)
+		}
+		filter.MinRating = minRating
+	}
+	if etaStr := c.Query("max_eta_minutes"); etaStr != "" {
+		eta, err := strconv.Atoi(etaStr)
+		if err != nil || eta < 1 || eta > models.MaxNearbyETAMinutes {
+			return h.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("max_eta_minutes must be between 1 and %d", models.MaxNearbyETAMinutes), nil)
+		}
+		filter.MaxETAMinutes = eta
+	}
+
+	if radiusStr := c.Query("radius_km"); radiusStr != "" {
+		radiusKm, err := strconv.ParseFloat(radiusStr, 64)
+		if err != nil || radiusKm < h.nearby.MinRadiusKm || radiusKm > h.nearby.MaxRadiusKm {
+			return h.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("radius_km must be between %g and %g", h.nearby.MinRadiusKm, h.nearby.MaxRadiusKm), nil)
+		}
+		filter.RadiusKm = radiusKm
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > h.nearby.MaxLimit {
+			return h.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", h.nearby.MaxLimit), nil)
+		}
+		filter.Limit = limit
+	}
+
+	drivers, err := h.serviceFor(c).FindNearbyDrivers(c.Context(), lat, lon, filter)
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidLocation) {
+		if errors.Is(err, service.ErrInvalidLocation) || errors.Is(err, service.ErrInvalidTaxiType) || errors.Is(err, service.ErrValidationFailed) {
 			return h.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
 		}
 		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to find nearby drivers", []string{err.Error()})
 	}
 
+	// Addresses are looked up for the reported location, so they are no more
+	// precise than the coordinates
+	precision := locationPrecision(c, h.geoPolicy, geoprivacy.EndpointNearby)
 	response := make([]*models.DriverWithDistanceResponse, len(drivers))
 	for i, driver := range drivers {
 		response[i] = models.NewDriverWithDistanceResponse(driver)
+		response[i].Location = geoprivacy.Apply(response[i].Location, precision)
+	}
+
+	location := fiber.Map{
+		"lat": lat,
+		"lon": lon,
+	}
+	if c.QueryBool("include_address") && h.geocoder != nil {
+		if address := h.enrichAddresses(c.Context(), lat, lon, response); address != nil {
+			location["address"] = address
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"drivers":  response,
+		"location": location,
+	})
+}
+
+// FindNearbyDriversBatch runs a nearby search for each pickup point, with at
+// most nearby.BatchConcurrency queries in flight. Results keep the request
+// order; a failed point reports its error without failing the others.
+func (h *DriverHandler) FindNearbyDriversBatch(c *fiber.Ctx) error {
+	var req models.NearbyBatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	if h.nearby.BatchMaxPoints > 0 && len(req.Points) > h.nearby.BatchMaxPoints {
+		return h.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("at most %d points are allowed per batch", h.nearby.BatchMaxPoints), nil)
+	}
+
+	driverService := h.serviceFor(c)
+	precision := locationPrecision(c, h.geoPolicy, geoprivacy.EndpointNearby)
+	ctx := c.Context()
+
+	results := make([]models.NearbyBatchResult, len(req.Points))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, h.nearby.BatchConcurrency)
+	for i, point := range req.Points {
+		wg.Add(1)
+		go func(i int, point models.NearbyBatchPoint) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result := models.NearbyBatchResult{
+				ID:      point.ID,
+				Lat:     point.Lat,
+				Lon:     point.Lon,
+				Drivers: []*models.DriverWithDistanceResponse{},
+			}
+			drivers, err := driverService.FindNearbyDrivers(ctx, point.Lat, point.Lon, point.Filter())
+			if err != nil {
+				result.Error = err.Error()
+				results[i] = result
+				return
+			}
+			for _, driver := range drivers {
+				response := models.NewDriverWithDistanceResponse(driver)
+				response.Location = geoprivacy.Apply(response.Location, precision)
+				result.Drivers = append(result.Drivers, response)
+			}
+			results[i] = result
+		}(i, point)
 	}
+	wg.Wait()
 
 	return c.JSON(fiber.Map{
-		"drivers": response,
-		"location": fiber.Map{
-			"lat": lat,
-			"lon": lon,
-		},
+		"results": results,
 	})
 }
 
+// enrichAddresses reverse-geocodes the search point and each driver with a
+// bounded number of concurrent provider calls. Lookups that fail are left
+// without an address rather than failing the search.
+func (h *DriverHandler) enrichAddresses(ctx context.Context, lat, lon float64, drivers []*models.DriverWithDistanceResponse) *models.Address {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 5)
+	for _, driver := range drivers {
+		wg.Add(1)
+		go func(driver *models.DriverWithDistanceResponse) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if address, err := h.geocoder.ReverseGeocode(ctx, driver.Location.Lat, driver.Location.Lon); err == nil {
+				driver.Address = address
+			}
+		}(driver)
+	}
+
+	center, err := h.geocoder.ReverseGeocode(ctx, lat, lon)
+	wg.Wait()
+	if err != nil {
+		return nil
+	}
+	return center
+}
+
 func (h *DriverHandler) UpdateDriverLocation(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if !h.isValidObjectID(id) {
@@ -235,21 +509,16 @@ func (h *DriverHandler) UpdateDriverLocation(c *fiber.Ctx) error {
 	}
 
 	if err := req.Validate(); err != nil {
-		var validationErrors []string
-		if validationErr, ok := err.(validator.ValidationErrors); ok {
-			for _, e := range validationErr {
-				validationErrors = append(validationErrors, h.formatValidationError(e))
-			}
-		} else {
-			validationErrors = append(validationErrors, err.Error())
-		}
-		return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", validationErrors)
+		return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
 	}
 
-	if err := h.driverService.UpdateDriverLocation(c.Context(), id, &req); err != nil {
+	if err := h.serviceFor(c).UpdateDriverLocation(c.Context(), id, &req); err != nil {
 		if errors.Is(err, service.ErrDriverNotFound) {
 			return h.ErrorResponse(c, http.StatusNotFound, "Driver not found", nil)
 		}
+		if errors.Is(err, service.ErrLocationRejected) {
+			return h.ErrorResponse(c, http.StatusUnprocessableEntity, "Location rejected", []string{err.Error()})
+		}
 		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to update driver location", []string{err.Error()})
 	}
 
@@ -258,40 +527,259 @@ func (h *DriverHandler) UpdateDriverLocation(c *fiber.Ctx) error {
 	})
 }
 
+// UpdateDriverLocations takes the points a driver app buffered while offline.
+// The whole batch is rejected if any point is invalid; points failing the
+// location checks are dropped and counted in the result instead.
+func (h *DriverHandler) UpdateDriverLocations(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if !h.isValidObjectID(id) {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	var req models.BatchLocationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	if h.locationBatchMaxPoints > 0 && len(req.Points) > h.locationBatchMaxPoints {
+		return h.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("at most %d points are allowed per batch", h.locationBatchMaxPoints), nil)
+	}
+
+	result, err := h.serviceFor(c).UpdateDriverLocations(c.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrDriverNotFound) {
+			return h.ErrorResponse(c, http.StatusNotFound, "Driver not found", nil)
+		}
+		if errors.Is(err, service.ErrLocationRejected) {
+			return h.ErrorResponse(c, http.StatusUnprocessableEntity, "Location rejected", []string{err.Error()})
+		}
+		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to update driver locations", []string{err.Error()})
+	}
+
+	return c.Status(http.StatusOK).JSON(result)
+}
+
+// GetLocationTrace returns the recorded path of a driver for playback.
+// from and to are RFC 3339 times; to defaults to now and from to an hour
+// before to.
+func (h *DriverHandler) GetLocationTrace(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if !h.isValidObjectID(id) {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return h.ErrorResponse(c, http.StatusBadRequest, "to must be an RFC 3339 time", nil)
+		}
+		to = parsed
+	}
+	from := to.Add(-models.DefaultLocationTraceWindow)
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return h.ErrorResponse(c, http.StatusBadRequest, "from must be an RFC 3339 time", nil)
+		}
+		from = parsed
+	}
+	limit := models.DefaultLocationTraceLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > models.MaxLocationTraceLimit {
+			return h.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", models.MaxLocationTraceLimit), nil)
+		}
+		limit = parsed
+	}
+
+	trace, err := h.serviceFor(c).GetLocationTrace(c.Context(), id, from, to, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDriverNotFound):
+			return h.ErrorResponse(c, http.StatusNotFound, "Driver not found", nil)
+		case errors.Is(err, service.ErrValidationFailed):
+			return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+		}
+		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to get location trace", []string{err.Error()})
+	}
+
+	precision := locationPrecision(c, h.geoPolicy, geoprivacy.EndpointTrace)
+	for i, point := range trace.Points {
+		location := geoprivacy.Apply(point.Location(), precision)
+		trace.Points[i].Lat, trace.Points[i].Lon = location.Lat, location.Lon
+	}
+
+	return c.JSON(trace)
+}
+
+func (h *DriverHandler) UpdateDriverStatus(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if !h.isValidObjectID(id) {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	var req models.UpdateStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	if err := h.serviceFor(c).SetStatus(c.Context(), id, req.Status); err != nil {
+		if errors.Is(err, service.ErrDriverNotFound) {
+			return h.ErrorResponse(c, http.StatusNotFound, "Driver not found", nil)
+		}
+		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to update driver status", []string{err.Error()})
+	}
+
+	driver, err := h.serviceFor(c).GetDriverByID(c.Context(), id)
+	if err != nil {
+		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to get driver", []string{err.Error()})
+	}
+
+	return c.JSON(h.driverResponse(c, models.NewDriverResponse(driver)))
+}
+
+func (h *DriverHandler) VerifyDriver(c *fiber.Ctx) error {
+	return h.setVerified(c, true)
+}
+
+func (h *DriverHandler) UnverifyDriver(c *fiber.Ctx) error {
+	return h.setVerified(c, false)
+}
+
+func (h *DriverHandler) setVerified(c *fiber.Ctx, verified bool) error {
+	id := c.Params("id")
+	if !h.isValidObjectID(id) {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	if err := h.serviceFor(c).SetVerified(c.Context(), id, verified); err != nil {
+		if errors.Is(err, service.ErrDriverNotFound) {
+			return h.ErrorResponse(c, http.StatusNotFound, "Driver not found", nil)
+		}
+		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to update driver verification", []string{err.Error()})
+	}
+
+	driver, err := h.serviceFor(c).GetDriverByID(c.Context(), id)
+	if err != nil {
+		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to get driver", []string{err.Error()})
+	}
+
+	return c.JSON(h.driverResponse(c, models.NewDriverResponse(driver)))
+}
+
+// ApproveDriver admits a driver under review, or reinstates a suspended one,
+// to nearby searches and dispatch.
+func (h *DriverHandler) ApproveDriver(c *fiber.Ctx) error {
+	return h.reviewDriver(c, models.DriverReviewApproved, "Failed to approve driver")
+}
+
+func (h *DriverHandler) RejectDriver(c *fiber.Ctx) error {
+	return h.reviewDriver(c, models.DriverReviewRejected, "Failed to reject driver")
+}
+
+func (h *DriverHandler) SuspendDriver(c *fiber.Ctx) error {
+	return h.reviewDriver(c, models.DriverReviewSuspended, "Failed to suspend driver")
+}
+
+func (h *DriverHandler) reviewDriver(c *fiber.Ctx, status, failure string) error {
+	id := c.Params("id")
+	if !h.isValidObjectID(id) {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	var req models.ReviewDriverRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return h.ErrorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	driver, err := h.serviceFor(c).ReviewDriver(c.Context(), id, status, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDriverNotFound):
+			return h.ErrorResponse(c, http.StatusNotFound, "Driver not found", nil)
+		case errors.Is(err, service.ErrInvalidReviewTransition):
+			return h.ErrorResponse(c, http.StatusConflict, failure, []string{err.Error()})
+		}
+		return h.ErrorResponse(c, http.StatusInternalServerError, failure, []string{err.Error()})
+	}
+
+	return c.JSON(h.driverResponse(c, models.NewDriverResponse(driver)))
+}
+
+// ListLicenseOverrides returns the audit trail of license class overrides
+// used for the driver, newest first.
+func (h *DriverHandler) ListLicenseOverrides(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if !h.isValidObjectID(id) {
+		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	records, err := h.serviceFor(c).ListLicenseOverrides(c.Context(), id)
+	if err != nil {
+		return h.ErrorResponse(c, http.StatusInternalServerError, "Failed to list license overrides", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": records,
+	})
+}
+
+func (h *DriverHandler) driverResponse(c *fiber.Ctx, response *models.DriverResponse) *models.DriverResponse {
+	response.Location = geoprivacy.Apply(response.Location, locationPrecision(c, h.geoPolicy, geoprivacy.EndpointDriver))
+	return response
+}
+
+// customFieldQuery collects the field.<key> query parameters of a driver list
+// as raw strings; the service converts them to the fields' types.
+func customFieldQuery(c *fiber.Ctx) map[string]interface{} {
+	var fields map[string]interface{}
+	for name, value := range c.Queries() {
+		key, ok := strings.CutPrefix(name, "field.")
+		if !ok {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]interface{})
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+// serviceFor routes sandbox API keys to their synthetic dataset.
+func (h *DriverHandler) serviceFor(c *fiber.Ctx) service.DriverService {
+	if key, ok := middleware.SandboxKey(c); ok && h.sandboxServices != nil {
+		return h.sandboxServices.For(key)
+	}
+	return h.driverService
+}
+
 func (h *DriverHandler) isValidObjectID(id string) bool {
 	_, err := primitive.ObjectIDFromHex(id)
 	return err == nil
 }
 
 func (h *DriverHandler) ErrorResponse(c *fiber.Ctx, statusCode int, message string, details []string) error {
-	response := models.ErrorResponse{
-		Error:   message,
-		Details: details,
-		Code:    statusCode,
-	}
-	return c.Status(statusCode).JSON(response)
+	return errorResponse(c, statusCode, message, details)
 }
 
 func (h *DriverHandler) formatValidationError(err validator.FieldError) string {
-	field := strings.ToLower(err.Field())
-	tag := err.Tag()
-
-	switch tag {
-	case "required":
-		return fmt.Sprintf("%s is required", field)
-	case "min":
-		return fmt.Sprintf("%s must be at least %s characters", field, err.Param())
-	case "max":
-		return fmt.Sprintf("%s must be at most %s characters", field, err.Param())
-	case "oneof":
-		return fmt.Sprintf("%s must be one of: %s", field, err.Param())
-	case "email":
-		return fmt.Sprintf("%s must be a valid email address", field)
-	case "turkish_plate":
-		return "plate must be a valid Turkish license plate (e.g., 34 ABC 123)"
-	default:
-		return fmt.Sprintf("%s is invalid", field)
-	}
+	return formatValidationError(err)
 }
 
 func (h *DriverHandler) HandleValidationErrors(err error) []string {
@@ -306,21 +794,28 @@ func (h *DriverHandler) HandleValidationErrors(err error) []string {
 	return errors
 }
 
+// HandleServiceErrors answers a failed driver service call on the driver
+// routes. Errors the caller can act on get their status; anything else is
+// logged and answered with a bare 500, so storage details stay out of the
+// response.
 func (h *DriverHandler) HandleServiceErrors(c *fiber.Ctx, err error) error {
 	switch {
 	case errors.Is(err, service.ErrDriverNotFound):
 		return h.ErrorResponse(c, http.StatusNotFound, "Driver not found", nil)
 	case errors.Is(err, service.ErrDriverAlreadyExists):
-		return h.ErrorResponse(c, http.StatusConflict, "Driver already exists", nil)
+		return h.ErrorResponse(c, http.StatusConflict, "Driver with this plate already exists", nil)
+	case errors.Is(err, service.ErrLicenseClassNotPermitted):
+		return h.ErrorResponse(c, http.StatusConflict, "License class does not permit this taxi type", []string{err.Error()})
+	case errors.Is(err, service.ErrPlateReserved):
+		return h.ErrorResponse(c, http.StatusConflict, "Plate is reserved by another registration", nil)
 	case errors.Is(err, service.ErrInvalidID):
 		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid driver ID", nil)
 	case errors.Is(err, service.ErrValidationFailed):
-		return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", nil)
-	case errors.Is(err, repository.ErrDriverNotFound):
-		return h.ErrorResponse(c, http.StatusNotFound, "Driver not found", nil)
-	case errors.Is(err, repository.ErrInvalidID):
-		return h.ErrorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+		return h.ErrorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+	case errors.Is(err, service.ErrInvalidLocation), errors.Is(err, service.ErrInvalidTaxiType):
+		return h.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
 	default:
-		return h.ErrorResponse(c, http.StatusInternalServerError, "Internal server error", []string{err.Error()})
+		logging.FromContext(c.Context()).Error("Driver service call failed", "error", err)
+		return h.ErrorResponse(c, http.StatusInternalServerError, "Internal server error", nil)
 	}
 }
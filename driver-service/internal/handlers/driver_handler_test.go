@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// faultyDriverRepository serves drivers from the sandbox dataset, except
+// that finding and creating drivers fail with err when it is set.
+type faultyDriverRepository struct {
+	*repository.SandboxDriverRepository
+	err error
+}
+
+func (r *faultyDriverRepository) FindByID(ctx context.Context, id string) (*models.Driver, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.SandboxDriverRepository.FindByID(ctx, id)
+}
+
+func (r *faultyDriverRepository) Create(ctx context.Context, driver *models.Driver) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	return r.SandboxDriverRepository.Create(ctx, driver)
+}
+
+func TestDriverRoutesMapRepositoryErrors(t *testing.T) {
+	sandbox := repository.NewSandboxDriverRepository(1)
+	drivers, _, err := sandbox.FindAll(context.Background(), 1, 1, models.DriverListFilter{})
+	if err != nil || len(drivers) == 0 {
+		t.Fatalf("FindAll() = %v, %v; want a sandbox driver", drivers, err)
+	}
+	existing := drivers[0]
+	missingID := primitive.NewObjectID().Hex()
+
+	newDriver := func(plate string) string {
+		return fmt.Sprintf(`{"first_name": "Ahmet", "last_name": "Yilmaz", "plate": %q, "taxi_type": "sari", "car_brand": "Fiat", "car_model": "Egea", "lat": 41.0082, "lon": 28.9784}`, plate)
+	}
+	databaseError := fmt.Errorf("%w: %w", repository.ErrDatabaseError, context.DeadlineExceeded)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		repoErr     error
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:       "get found",
+			method:     http.MethodGet,
+			path:       "/drivers/" + existing.ID.Hex(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "get not found",
+			method:      http.MethodGet,
+			path:        "/drivers/" + missingID,
+			wantStatus:  http.StatusNotFound,
+			wantMessage: "Driver not found",
+		},
+		{
+			name:        "get malformed ID",
+			method:      http.MethodGet,
+			path:        "/drivers/not-an-id",
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "Invalid driver ID format",
+		},
+		{
+			name:        "get invalid ID from the repository",
+			method:      http.MethodGet,
+			path:        "/drivers/" + missingID,
+			repoErr:     fmt.Errorf("%w: %q", repository.ErrInvalidID, missingID),
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "Invalid driver ID",
+		},
+		{
+			name:        "get database error",
+			method:      http.MethodGet,
+			path:        "/drivers/" + existing.ID.Hex(),
+			repoErr:     databaseError,
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: "Internal server error",
+		},
+		{
+			name:        "create duplicate plate",
+			method:      http.MethodPost,
+			path:        "/drivers",
+			body:        newDriver(existing.Plate),
+			wantStatus:  http.StatusConflict,
+			wantMessage: "Driver with this plate already exists",
+		},
+		{
+			name:        "create database error",
+			method:      http.MethodPost,
+			path:        "/drivers",
+			body:        newDriver("06 ABC 123"),
+			repoErr:     databaseError,
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: "Internal server error",
+		},
+		{
+			name:        "update not found",
+			method:      http.MethodPut,
+			path:        "/drivers/" + missingID,
+			body:        `{"first_name": "Mehmet"}`,
+			wantStatus:  http.StatusNotFound,
+			wantMessage: "Driver not found",
+		},
+		{
+			name:        "update database error",
+			method:      http.MethodPut,
+			path:        "/drivers/" + existing.ID.Hex(),
+			body:        `{"first_name": "Mehmet"}`,
+			repoErr:     databaseError,
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: "Internal server error",
+		},
+		{
+			name:        "delete not found",
+			method:      http.MethodDelete,
+			path:        "/drivers/" + missingID,
+			wantStatus:  http.StatusNotFound,
+			wantMessage: "Driver not found",
+		},
+		{
+			name:        "delete database error",
+			method:      http.MethodDelete,
+			path:        "/drivers/" + existing.ID.Hex(),
+			repoErr:     databaseError,
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: "Internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &faultyDriverRepository{SandboxDriverRepository: repository.NewSandboxDriverRepository(1), err: tt.repoErr}
+			driverService := service.NewDriverService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			h := NewDriverHandler(driverService, nil, nil, nil, NearbyConfig{}, 0)
+
+			app := fiber.New()
+			app.Post("/drivers", h.CreateDriver)
+			app.Get("/drivers/:id", h.GetDriver)
+			app.Put("/drivers/:id", h.UpdateDriver)
+			app.Delete("/drivers/:id", h.DeleteDriver)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantMessage == "" {
+				return
+			}
+			var body models.ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if body.Error != tt.wantMessage {
+				t.Errorf("error = %q, want %q", body.Error, tt.wantMessage)
+			}
+			if tt.wantStatus == http.StatusInternalServerError && len(body.Details) > 0 {
+				t.Errorf("details = %q, want none on a 500", body.Details)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/importer"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxDriverImportBytes = 5 << 20
+
+type DriverImportHandler struct {
+	importService service.DriverImportService
+}
+
+func NewDriverImportHandler(importService service.DriverImportService) *DriverImportHandler {
+	return &DriverImportHandler{
+		importService: importService,
+	}
+}
+
+func (h *DriverImportHandler) RegisterRoutes(app *fiber.App) {
+	imports := app.Group("/api/v1/admin/driver-imports", middleware.RequireRole(auth.RoleAdmin))
+	{
+		imports.Get("/adapters", h.ListAdapters)
+		imports.Post("/", h.StageImport)
+		imports.Get("/", h.ListImports)
+		imports.Get("/:importId", h.GetImport)
+		imports.Post("/:importId/commit", h.CommitImport)
+		imports.Delete("/:importId", h.DiscardImport)
+	}
+}
+
+func (h *DriverImportHandler) ListAdapters(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"adapters":   importer.AdapterNames(),
+		"fields":     importer.Fields,
+		"transforms": importer.TransformNames(),
+	})
+}
+
+// StageImport accepts a multipart "file" with the optional form fields
+// "adapter", "mapping" (a JSON importer.Mapping) and "fleet_id", and returns
+// the preview.
+func (h *DriverImportHandler) StageImport(c *fiber.Ctx) error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "file is required", nil)
+	}
+	if file.Size > maxDriverImportBytes {
+		return errorResponse(c, http.StatusRequestEntityTooLarge, "file must be at most 5MB", nil)
+	}
+
+	var mapping *importer.Mapping
+	if raw := c.FormValue("mapping"); raw != "" {
+		mapping = &importer.Mapping{}
+		if err := json.Unmarshal([]byte(raw), mapping); err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid mapping", []string{err.Error()})
+		}
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Failed to read file", nil)
+	}
+	defer reader.Close()
+
+	driverImport, err := h.importService.Stage(c.Context(), c.FormValue("adapter"), mapping, file.Filename, c.FormValue("fleet_id"), reader)
+	if err != nil {
+		return driverImportError(c, err, "Failed to stage import")
+	}
+
+	return c.Status(http.StatusCreated).JSON(driverImport)
+}
+
+func (h *DriverImportHandler) ListImports(c *fiber.Ctx) error {
+	imports, err := h.importService.List(c.Context(), c.QueryInt("limit", 20))
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list imports", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": imports,
+	})
+}
+
+// GetImport returns an import with its rows, optionally only those with the
+// given action.
+func (h *DriverImportHandler) GetImport(c *fiber.Ctx) error {
+	id := c.Params("importId")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid import ID format", nil)
+	}
+
+	action := c.Query("action")
+	switch action {
+	case "", models.ImportActionCreate, models.ImportActionUpdate, models.ImportActionUnchanged, models.ImportActionInvalid, models.ImportActionDuplicate:
+	default:
+		return errorResponse(c, http.StatusBadRequest, "Invalid action", []string{"action must be one of: create update unchanged invalid duplicate"})
+	}
+
+	driverImport, err := h.importService.Get(c.Context(), id)
+	if err != nil {
+		return driverImportError(c, err, "Failed to get import")
+	}
+
+	if action != "" {
+		rows := []models.DriverImportRow{}
+		for _, row := range driverImport.Rows {
+			if row.Action == action {
+				rows = append(rows, row)
+			}
+		}
+		driverImport.Rows = rows
+	}
+
+	return c.JSON(driverImport)
+}
+
+func (h *DriverImportHandler) CommitImport(c *fiber.Ctx) error {
+	id := c.Params("importId")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid import ID format", nil)
+	}
+
+	driverImport, err := h.importService.Commit(c.Context(), id)
+	if err != nil {
+		return driverImportError(c, err, "Failed to commit import")
+	}
+
+	return c.JSON(driverImport)
+}
+
+func (h *DriverImportHandler) DiscardImport(c *fiber.Ctx) error {
+	id := c.Params("importId")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid import ID format", nil)
+	}
+
+	if err := h.importService.Discard(c.Context(), id); err != nil {
+		return driverImportError(c, err, "Failed to discard import")
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func driverImportError(c *fiber.Ctx, err error, failure string) error {
+	switch {
+	case errors.Is(err, service.ErrDriverImportNotFound):
+		return errorResponse(c, http.StatusNotFound, "Import not found", nil)
+	case errors.Is(err, service.ErrDriverImportNotStaged):
+		return errorResponse(c, http.StatusConflict, "Import has already been committed", nil)
+	case errors.Is(err, service.ErrValidationFailed):
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+	default:
+		return errorResponse(c, http.StatusInternalServerError, failure, []string{err.Error()})
+	}
+}
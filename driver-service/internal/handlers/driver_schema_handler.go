@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+type DriverSchemaHandler struct {
+	schemaService service.DriverSchemaService
+}
+
+func NewDriverSchemaHandler(schemaService service.DriverSchemaService) *DriverSchemaHandler {
+	return &DriverSchemaHandler{
+		schemaService: schemaService,
+	}
+}
+
+// RegisterRoutes registers the admin view of driver schema versions and the
+// manual backfill.
+func (h *DriverSchemaHandler) RegisterRoutes(app *fiber.App) {
+	schema := app.Group("/api/v1/admin/driver-schema", middleware.RequireRole(auth.RoleAdmin))
+	{
+		schema.Get("/", h.GetStatus)
+		schema.Post("/backfill", h.Backfill)
+	}
+}
+
+func (h *DriverSchemaHandler) GetStatus(c *fiber.Ctx) error {
+	status, err := h.schemaService.Status(c.Context())
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to get driver schema status", []string{err.Error()})
+	}
+
+	return c.JSON(status)
+}
+
+// Backfill migrates every stale driver document now instead of waiting for
+// the background job.
+func (h *DriverSchemaHandler) Backfill(c *fiber.Ctx) error {
+	result, err := h.schemaService.Backfill(c.Context())
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to backfill driver schema", []string{err.Error()})
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EarningsHandler struct {
+	earningsService service.EarningsService
+}
+
+func NewEarningsHandler(earningsService service.EarningsService) *EarningsHandler {
+	return &EarningsHandler{
+		earningsService: earningsService,
+	}
+}
+
+// RegisterRoutes registers the earnings routes. A driver reads their own
+// day, and admins and dispatchers read any driver's. Rides and bonuses are
+// booked under the admin routes.
+func (h *EarningsHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/api/v1/drivers/:id/earnings", middleware.RequireSelfOrRole(auth.RoleAdmin, auth.RoleDispatcher), h.GetEarnings)
+
+	admin := app.Group("/api/v1/admin/drivers/:id/earnings", middleware.RequireRole(auth.RoleAdmin))
+	{
+		admin.Post("/rides", h.RecordRide)
+		admin.Post("/bonuses", h.GrantBonus)
+	}
+}
+
+// GetEarnings returns the driver's running total and entries of the day
+// given as date (YYYY-MM-DD), today by default.
+func (h *EarningsHandler) GetEarnings(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	earnings, err := h.earningsService.Day(c.Context(), id, c.Query("date"))
+	if err != nil {
+		return earningsError(c, err, "Failed to get earnings")
+	}
+
+	return c.JSON(earnings)
+}
+
+func (h *EarningsHandler) RecordRide(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	var req models.RecordRideRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	entries, err := h.earningsService.RecordRide(c.Context(), id, &req)
+	if err != nil {
+		return earningsError(c, err, "Failed to record ride")
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"data": entries,
+	})
+}
+
+func (h *EarningsHandler) GrantBonus(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	var req models.GrantBonusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	entry, err := h.earningsService.GrantBonus(c.Context(), id, &req)
+	if err != nil {
+		return earningsError(c, err, "Failed to grant bonus")
+	}
+
+	return c.Status(http.StatusCreated).JSON(entry)
+}
+
+func earningsError(c *fiber.Ctx, err error, failure string) error {
+	switch {
+	case errors.Is(err, service.ErrDriverNotFound):
+		return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+	case errors.Is(err, service.ErrRideAlreadyRecorded):
+		return errorResponse(c, http.StatusConflict, "Ride already recorded", nil)
+	case errors.Is(err, service.ErrInvalidID), errors.Is(err, service.ErrValidationFailed):
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+	default:
+		return errorResponse(c, http.StatusInternalServerError, failure, []string{err.Error()})
+	}
+}
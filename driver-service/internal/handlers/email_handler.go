@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EmailHandler struct {
+	emailService service.EmailService
+}
+
+func NewEmailHandler(emailService service.EmailService) *EmailHandler {
+	return &EmailHandler{
+		emailService: emailService,
+	}
+}
+
+func (h *EmailHandler) RegisterRoutes(app *fiber.App) {
+	admin := app.Group("/api/v1/admin/emails", middleware.RequireRole(auth.RoleAdmin))
+	{
+		admin.Post("/", h.SendEmail)
+		admin.Get("/", h.ListEmails)
+		admin.Get("/:emailId", h.GetEmail)
+	}
+}
+
+func (h *EmailHandler) SendEmail(c *fiber.Ctx) error {
+	var req models.SendEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	email, err := h.emailService.Send(c.Context(), &req)
+	if err != nil {
+		return emailError(c, err, "Failed to queue email")
+	}
+
+	return c.Status(http.StatusAccepted).JSON(email)
+}
+
+func (h *EmailHandler) ListEmails(c *fiber.Ctx) error {
+	status := c.Query("status")
+	switch status {
+	case "", models.EmailStatusQueued, models.EmailStatusSending, models.EmailStatusSent, models.EmailStatusFailed:
+	default:
+		return errorResponse(c, http.StatusBadRequest, "Invalid status", []string{"status must be one of: queued sending sent failed"})
+	}
+
+	emails, err := h.emailService.List(c.Context(), status, c.QueryInt("limit", 100))
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list emails", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": emails,
+	})
+}
+
+func (h *EmailHandler) GetEmail(c *fiber.Ctx) error {
+	id := c.Params("emailId")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid email ID format", nil)
+	}
+
+	email, err := h.emailService.Get(c.Context(), id)
+	if err != nil {
+		return emailError(c, err, "Failed to get email")
+	}
+
+	return c.JSON(email)
+}
+
+func emailError(c *fiber.Ctx, err error, failure string) error {
+	switch {
+	case errors.Is(err, service.ErrEmailNotFound):
+		return errorResponse(c, http.StatusNotFound, "Email not found", nil)
+	case errors.Is(err, service.ErrDriverNotFound):
+		return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+	case errors.Is(err, service.ErrNoEmailAddress):
+		return errorResponse(c, http.StatusUnprocessableEntity, "Driver has no email address", nil)
+	case errors.Is(err, service.ErrValidationFailed):
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+	default:
+		return errorResponse(c, http.StatusInternalServerError, failure, []string{err.Error()})
+	}
+}
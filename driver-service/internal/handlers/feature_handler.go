@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+type FeatureHandler struct {
+	featureService service.FeatureService
+}
+
+func NewFeatureHandler(featureService service.FeatureService) *FeatureHandler {
+	return &FeatureHandler{
+		featureService: featureService,
+	}
+}
+
+func (h *FeatureHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/api/v1/app-config", h.GetAppConfig)
+
+	flags := app.Group("/api/v1/admin/feature-flags", middleware.RequireRole(auth.RoleAdmin))
+	{
+		flags.Get("/", h.ListFlags)
+		flags.Get("/:key", h.GetFlag)
+		flags.Put("/:key", h.UpsertFlag)
+		flags.Delete("/:key", h.DeleteFlag)
+	}
+}
+
+// GetAppConfig returns the features enabled for the calling driver, who is
+// identified by the X-Driver-ID, X-Fleet-ID, X-City and X-App-Version headers.
+func (h *FeatureHandler) GetAppConfig(c *fiber.Ctx) error {
+	target := middleware.TargetingContext(c)
+
+	return c.JSON(fiber.Map{
+		"features": h.featureService.Evaluate(c.Context(), target),
+		"context":  target,
+	})
+}
+
+func (h *FeatureHandler) ListFlags(c *fiber.Ctx) error {
+	flags, err := h.featureService.ListFlags(c.Context())
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list feature flags", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": flags,
+	})
+}
+
+func (h *FeatureHandler) GetFlag(c *fiber.Ctx) error {
+	flag, err := h.featureService.GetFlag(c.Context(), c.Params("key"))
+	if err != nil {
+		if errors.Is(err, service.ErrFeatureFlagNotFound) {
+			return errorResponse(c, http.StatusNotFound, "Feature flag not found", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to get feature flag", []string{err.Error()})
+	}
+
+	return c.JSON(flag)
+}
+
+func (h *FeatureHandler) UpsertFlag(c *fiber.Ctx) error {
+	var req models.UpsertFeatureFlagRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	flag, err := h.featureService.UpsertFlag(c.Context(), c.Params("key"), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFlagKey) {
+			return errorResponse(c, http.StatusBadRequest, "Invalid feature flag key", []string{"key must be 2-64 lowercase letters, digits, '.', '_' or '-'"})
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save feature flag", []string{err.Error()})
+	}
+
+	return c.JSON(flag)
+}
+
+func (h *FeatureHandler) DeleteFlag(c *fiber.Ctx) error {
+	if err := h.featureService.DeleteFlag(c.Context(), c.Params("key")); err != nil {
+		if errors.Is(err, service.ErrFeatureFlagNotFound) {
+			return errorResponse(c, http.StatusNotFound, "Feature flag not found", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to delete feature flag", []string{err.Error()})
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxFleetIDLength matches the fleet_id limit on driver requests.
+const maxFleetIDLength = 64
+
+// FleetHandler serves the fleet-scoped routes fleet admins use. Each route
+// requires a capability in the fleet named by :fleetId.
+type FleetHandler struct {
+	accountService  service.FleetAccountService
+	fieldService    service.CustomFieldService
+	driverService   service.DriverService
+	earningsService service.EarningsService
+}
+
+func NewFleetHandler(accountService service.FleetAccountService, fieldService service.CustomFieldService, driverService service.DriverService, earningsService service.EarningsService) *FleetHandler {
+	return &FleetHandler{
+		accountService:  accountService,
+		fieldService:    fieldService,
+		driverService:   driverService,
+		earningsService: earningsService,
+	}
+}
+
+func (h *FleetHandler) RegisterRoutes(app *fiber.App) {
+	manageAccounts := middleware.RequireFleetCapability(models.FleetCapabilityManageAccounts)
+	viewDrivers := middleware.RequireFleetCapability(models.FleetCapabilityViewDrivers)
+	viewEarnings := middleware.RequireFleetCapability(models.FleetCapabilityViewEarnings)
+	onboardDrivers := middleware.RequireFleetCapability(models.FleetCapabilityOnboardDrivers)
+	manageFields := middleware.RequireFleetCapability(models.FleetCapabilityManageFields)
+
+	fleet := app.Group("/api/v1/fleets/:fleetId", fleetScope)
+	{
+		fleet.Post("/accounts", manageAccounts, h.CreateAccount)
+		fleet.Get("/accounts", manageAccounts, h.ListAccounts)
+		fleet.Put("/accounts/:accountId", manageAccounts, h.UpdateAccount)
+		fleet.Delete("/accounts/:accountId", manageAccounts, h.DeleteAccount)
+		fleet.Post("/custom-fields", manageFields, h.CreateCustomField)
+		fleet.Get("/custom-fields", viewDrivers, h.ListCustomFields)
+		fleet.Delete("/custom-fields/:key", manageFields, h.DeleteCustomField)
+		fleet.Post("/drivers", onboardDrivers, h.OnboardDriver)
+		fleet.Get("/drivers/export", viewDrivers, h.ExportDrivers) // before /drivers/:id, which would otherwise match it
+		fleet.Get("/drivers/:id", viewDrivers, h.GetDriver)
+		fleet.Get("/drivers/:id/earnings", viewEarnings, h.GetDriverEarnings)
+	}
+}
+
+// fleetScope checks the fleet ID and keeps sandbox requests out: they pass Auth
+// without a token, and fleet routes have no synthetic dataset to send them to.
+func fleetScope(c *fiber.Ctx) error {
+	if _, ok := middleware.SandboxKey(c); ok {
+		return errorResponse(c, http.StatusForbidden, "Fleet routes are not available in the sandbox", nil)
+	}
+	if fleetID := c.Params("fleetId"); fleetID == "" || len(fleetID) > maxFleetIDLength {
+		return errorResponse(c, http.StatusBadRequest, "Invalid fleet ID", nil)
+	}
+	return c.Next()
+}
+
+// CreateAccount creates a sub-admin of the fleet. Owners can only be created
+// by admins.
+func (h *FleetHandler) CreateAccount(c *fiber.Ctx) error {
+	var req models.CreateFleetAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	createdBy := ""
+	claims, authenticated := middleware.AuthClaims(c)
+	if authenticated {
+		createdBy = claims.Subject
+	}
+
+	account, err := h.accountService.Create(c.Context(), c.Params("fleetId"), createdBy, &req, isFleetPrivileged(c))
+	if err != nil {
+		return fleetAccountError(c, err, "Failed to create fleet account")
+	}
+
+	return c.Status(http.StatusCreated).JSON(account)
+}
+
+func (h *FleetHandler) ListAccounts(c *fiber.Ctx) error {
+	accounts, err := h.accountService.List(c.Context(), c.Params("fleetId"))
+	if err != nil {
+		return fleetAccountError(c, err, "Failed to list fleet accounts")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": accounts,
+	})
+}
+
+func (h *FleetHandler) UpdateAccount(c *fiber.Ctx) error {
+	var req models.UpdateFleetAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	account, err := h.accountService.Update(c.Context(), c.Params("fleetId"), c.Params("accountId"), &req, isFleetPrivileged(c))
+	if err != nil {
+		return fleetAccountError(c, err, "Failed to update fleet account")
+	}
+
+	return c.JSON(account)
+}
+
+func (h *FleetHandler) DeleteAccount(c *fiber.Ctx) error {
+	if err := h.accountService.Delete(c.Context(), c.Params("fleetId"), c.Params("accountId"), isFleetPrivileged(c)); err != nil {
+		return fleetAccountError(c, err, "Failed to delete fleet account")
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// OnboardDriver creates a driver in the fleet; a fleet_id in the body must
+// name the same fleet.
+func (h *FleetHandler) OnboardDriver(c *fiber.Ctx) error {
+	var req models.CreateDriverRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	fleetID := c.Params("fleetId")
+	if req.FleetID != "" && req.FleetID != fleetID {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{"fleet_id must match the fleet in the path"})
+	}
+	req.FleetID = fleetID
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	driverID, err := h.driverService.CreateDriver(c.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDriverAlreadyExists):
+			return errorResponse(c, http.StatusConflict, "Driver with this plate already exists", nil)
+		case errors.Is(err, service.ErrLicenseClassNotPermitted):
+			return errorResponse(c, http.StatusConflict, "License class does not permit this taxi type", []string{err.Error()})
+		case errors.Is(err, service.ErrPlateReserved):
+			return errorResponse(c, http.StatusConflict, "Plate is reserved by another registration", nil)
+		case errors.Is(err, service.ErrValidationFailed):
+			return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+		default:
+			return errorResponse(c, http.StatusInternalServerError, "Failed to create driver", []string{err.Error()})
+		}
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"id": driverID,
+	})
+}
+
+// GetDriver returns a driver of the fleet; drivers of other fleets are
+// reported as not found.
+func (h *FleetHandler) GetDriver(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	driver, err := h.driverService.GetDriverByID(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrDriverNotFound) {
+			return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to get driver", []string{err.Error()})
+	}
+	if driver.FleetID != c.Params("fleetId") {
+		return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+	}
+
+	return c.JSON(models.NewDriverResponse(driver))
+}
+
+// GetDriverEarnings returns a day's earnings of a driver of the fleet, like
+// GET /api/v1/drivers/:id/earnings; drivers of other fleets are reported as
+// not found.
+func (h *FleetHandler) GetDriverEarnings(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	driver, err := h.driverService.GetDriverByID(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrDriverNotFound) {
+			return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to get driver", []string{err.Error()})
+	}
+	if driver.FleetID != c.Params("fleetId") {
+		return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+	}
+
+	earnings, err := h.earningsService.Day(c.Context(), id, c.Query("date"))
+	if err != nil {
+		return earningsError(c, err, "Failed to get earnings")
+	}
+
+	return c.JSON(earnings)
+}
+
+func (h *FleetHandler) CreateCustomField(c *fiber.Ctx) error {
+	var req models.CreateCustomFieldRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	definition, err := h.fieldService.Create(c.Context(), c.Params("fleetId"), &req)
+	if err != nil {
+		return customFieldError(c, err, "Failed to create custom field")
+	}
+
+	return c.Status(http.StatusCreated).JSON(definition)
+}
+
+func (h *FleetHandler) ListCustomFields(c *fiber.Ctx) error {
+	definitions, err := h.fieldService.Definitions(c.Context(), c.Params("fleetId"))
+	if err != nil {
+		return customFieldError(c, err, "Failed to list custom fields")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": definitions,
+	})
+}
+
+// DeleteCustomField removes a definition. Drivers keep the stored value until
+// their custom fields are next written, but it is left out of exports.
+func (h *FleetHandler) DeleteCustomField(c *fiber.Ctx) error {
+	if err := h.fieldService.Delete(c.Context(), c.Params("fleetId"), c.Params("key")); err != nil {
+		return customFieldError(c, err, "Failed to delete custom field")
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// exportPageSize is how many drivers ExportDrivers reads at a time.
+const exportPageSize = 100
+
+// exportColumns are the built-in columns of a driver export; the fleet's
+// custom fields follow them in definition order.
+var exportColumns = []string{"id", "first_name", "last_name", "plate", "taxi_type", "car_brand", "car_model", "car_color", "status", "review_status", "created_at"}
+
+// ExportDrivers writes the fleet's drivers as CSV, with a column per custom
+// field. The same field.<key> filters as the driver list apply.
+func (h *FleetHandler) ExportDrivers(c *fiber.Ctx) error {
+	fleetID := c.Params("fleetId")
+	definitions, err := h.fieldService.Definitions(c.Context(), fleetID)
+	if err != nil {
+		return customFieldError(c, err, "Failed to export drivers")
+	}
+
+	filter := models.DriverListFilter{
+		FleetID:      fleetID,
+		CustomFields: customFieldQuery(c),
+		Sort:         "created_at",
+	}
+
+	header := append([]string{}, exportColumns...)
+	for _, definition := range definitions {
+		header = append(header, definition.Key)
+	}
+	rows := [][]string{header}
+	for page := 1; ; page++ {
+		response, err := h.driverService.ListDrivers(c.Context(), page, exportPageSize, filter)
+		if err != nil {
+			if errors.Is(err, service.ErrValidationFailed) {
+				return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+			}
+			return errorResponse(c, http.StatusInternalServerError, "Failed to export drivers", []string{err.Error()})
+		}
+		for i := range response.Data {
+			rows = append(rows, exportRow(&response.Data[i], definitions))
+		}
+		if page >= response.TotalPages {
+			break
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="drivers-%s.csv"`, fleetID))
+	return csv.NewWriter(c.Response().BodyWriter()).WriteAll(rows)
+}
+
+func exportRow(driver *models.Driver, definitions []models.CustomFieldDefinition) []string {
+	row := []string{
+		driver.ID.Hex(),
+		driver.FirstName,
+		driver.LastName,
+		driver.Plate,
+		driver.TaxiType,
+		driver.CarBrand,
+		driver.CarModel,
+		driver.CarColor,
+		driver.Status,
+		driver.EffectiveReviewStatus(),
+		driver.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	for _, definition := range definitions {
+		switch value := driver.CustomFields[definition.Key].(type) {
+		case nil:
+			row = append(row, "")
+		case float64:
+			row = append(row, strconv.FormatFloat(value, 'f', -1, 64))
+		default:
+			row = append(row, fmt.Sprint(value))
+		}
+	}
+	return row
+}
+
+// isFleetPrivileged reports whether the caller may manage fleet owners, which
+// only admins may.
+func isFleetPrivileged(c *fiber.Ctx) bool {
+	claims, authenticated := middleware.AuthClaims(c)
+	return authenticated && claims.IsAdmin()
+}
+
+func fleetAccountError(c *fiber.Ctx, err error, failure string) error {
+	switch {
+	case errors.Is(err, service.ErrFleetAccountNotFound):
+		return errorResponse(c, http.StatusNotFound, "Fleet account not found", nil)
+	case errors.Is(err, service.ErrFleetAccountExists):
+		return errorResponse(c, http.StatusConflict, "Username is already taken", nil)
+	case errors.Is(err, service.ErrFleetOwnerProtected):
+		return errorResponse(c, http.StatusForbidden, "Only admins may create or change fleet owners", nil)
+	case errors.Is(err, service.ErrValidationFailed):
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+	default:
+		return errorResponse(c, http.StatusInternalServerError, failure, []string{err.Error()})
+	}
+}
+
+func customFieldError(c *fiber.Ctx, err error, failure string) error {
+	switch {
+	case errors.Is(err, service.ErrCustomFieldNotFound):
+		return errorResponse(c, http.StatusNotFound, "Custom field not found", nil)
+	case errors.Is(err, service.ErrCustomFieldExists):
+		return errorResponse(c, http.StatusConflict, "Custom field key is already defined for this fleet", nil)
+	case errors.Is(err, service.ErrTooManyCustomFields):
+		return errorResponse(c, http.StatusConflict, "Fleet has reached the custom field limit", []string{fmt.Sprintf("at most %d custom fields per fleet", models.MaxCustomFieldsPerFleet)})
+	default:
+		return errorResponse(c, http.StatusInternalServerError, failure, []string{err.Error()})
+	}
+}
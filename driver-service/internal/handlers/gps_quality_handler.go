@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/gpsquality"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GPSQualityHandler struct {
+	tracker *gpsquality.Tracker
+}
+
+func NewGPSQualityHandler(tracker *gpsquality.Tracker) *GPSQualityHandler {
+	return &GPSQualityHandler{
+		tracker: tracker,
+	}
+}
+
+func (h *GPSQualityHandler) RegisterRoutes(app *fiber.App) {
+	adminOnly := middleware.RequireRole(auth.RoleAdmin)
+
+	admin := app.Group("/api/v1/admin")
+	{
+		admin.Get("/gps-quality", adminOnly, h.ListGPSQuality)
+		admin.Get("/drivers/:id/gps-quality", adminOnly, h.GetGPSQuality)
+	}
+}
+
+// ListGPSQuality rates the GPS signal of every driver that sent updates
+// recently, worst first.
+func (h *GPSQualityHandler) ListGPSQuality(c *fiber.Ctx) error {
+	level := c.Query("level")
+	switch level {
+	case "", models.GPSQualityGood, models.GPSQualityFair, models.GPSQualityPoor, models.GPSQualityUnknown:
+	default:
+		return errorResponse(c, http.StatusBadRequest, "Invalid level", []string{"level must be one of: good fair poor unknown"})
+	}
+
+	report := h.tracker.Report(config.TenantFromContext(c.Context()))
+	if level != "" {
+		filtered := report[:0]
+		for _, quality := range report {
+			if quality.Level == level {
+				filtered = append(filtered, quality)
+			}
+		}
+		report = filtered
+	}
+
+	return c.JSON(fiber.Map{
+		"data": report,
+	})
+}
+
+func (h *GPSQualityHandler) GetGPSQuality(c *fiber.Ctx) error {
+	driverID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	return c.JSON(h.tracker.Quality(config.TenantFromContext(c.Context()), driverID))
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxLicenseImageBytes = 5 << 20
+
+type LicenseHandler struct {
+	licenseService service.LicenseService
+}
+
+func NewLicenseHandler(licenseService service.LicenseService) *LicenseHandler {
+	return &LicenseHandler{
+		licenseService: licenseService,
+	}
+}
+
+func (h *LicenseHandler) RegisterRoutes(app *fiber.App) {
+	staffOrSelf := middleware.RequireSelfOrRole(auth.RoleAdmin, auth.RoleDispatcher)
+	adminOrSelf := middleware.RequireSelfOrRole(auth.RoleAdmin)
+
+	license := app.Group("/api/v1/drivers/:id/license")
+	{
+		license.Post("/", adminOrSelf, h.UploadLicense)
+		license.Get("/", staffOrSelf, h.GetLicense)
+		license.Patch("/", adminOrSelf, h.CorrectLicense)
+	}
+}
+
+// UploadLicense accepts a multipart "image" file and returns the fields read
+// from it, flagged where they need manual correction.
+func (h *LicenseHandler) UploadLicense(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "image file is required", nil)
+	}
+	if file.Size > maxLicenseImageBytes {
+		return errorResponse(c, http.StatusRequestEntityTooLarge, "image must be at most 5MB", nil)
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Failed to read image", nil)
+	}
+	defer reader.Close()
+
+	image, err := io.ReadAll(reader)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Failed to read image", nil)
+	}
+
+	document, err := h.licenseService.Upload(c.Context(), id, image, http.DetectContentType(image))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDriverNotFound):
+			return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+		case errors.Is(err, service.ErrUnsupportedImageType):
+			return errorResponse(c, http.StatusUnsupportedMediaType, "image must be a JPEG or PNG", nil)
+		case errors.Is(err, service.ErrOCRUnavailable):
+			return errorResponse(c, http.StatusServiceUnavailable, "License OCR is not configured", nil)
+		default:
+			return errorResponse(c, http.StatusBadGateway, "Failed to read license", []string{err.Error()})
+		}
+	}
+
+	return c.Status(http.StatusCreated).JSON(document)
+}
+
+func (h *LicenseHandler) GetLicense(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	document, err := h.licenseService.Get(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrLicenseNotFound) {
+			return errorResponse(c, http.StatusNotFound, "License not found", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to get license", []string{err.Error()})
+	}
+
+	return c.JSON(document)
+}
+
+func (h *LicenseHandler) CorrectLicense(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	var req models.CorrectLicenseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	document, err := h.licenseService.Correct(c.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrLicenseNotFound) {
+			return errorResponse(c, http.StatusNotFound, "License not found", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to correct license", []string{err.Error()})
+	}
+
+	return c.JSON(document)
+}
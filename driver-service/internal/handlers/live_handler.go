@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/geoprivacy"
+	"github.com/taxihub/driver-service/internal/live"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	liveSessionLocal   = "live_session"
+	liveReadLimit      = 4096
+	liveWriteTimeout   = 10 * time.Second
+	liveUpdateTimeout  = 5 * time.Second
+	liveSessionTimeout = 2 * time.Second
+)
+
+// liveSession carries what the stream needs from the upgrade request; the
+// fiber context is gone once the connection is hijacked.
+type liveSession struct {
+	driverService service.DriverService
+	tenantID      string
+	precision     string
+	// resume is the session ID the client asked to resume.
+	resume string
+	// driverID is the driver of the access token. Only they may push
+	// locations on the stream, and it is only ever bound to them.
+	driverID string
+	// watcher reports whether the caller may subscribe to an area.
+	watcher bool
+	// sandbox streams never receive offers, so they are not bound to
+	// drivers. Their frames only reach synthetic data, so they may push
+	// for any driver and subscribe.
+	sandbox bool
+}
+
+// LiveHandler serves /ws/drivers: drivers push their location frames through
+// the driver service and receive ride offers, and admins and dispatchers
+// subscribe to the updates inside a bounding box. Each stream has a session, which a client
+// reconnecting with ?session= resumes.
+type LiveHandler struct {
+	driverService   service.DriverService
+	sandboxServices *service.SandboxServices
+	hub             *live.Hub
+	geoPolicy       *geoprivacy.Policy
+	sessions        live.SessionStore
+}
+
+func NewLiveHandler(driverService service.DriverService, sandboxServices *service.SandboxServices, hub *live.Hub, geoPolicy *geoprivacy.Policy, sessions live.SessionStore) *LiveHandler {
+	return &LiveHandler{
+		driverService:   driverService,
+		sandboxServices: sandboxServices,
+		hub:             hub,
+		geoPolicy:       geoPolicy,
+		sessions:        sessions,
+	}
+}
+
+func (h *LiveHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/ws/drivers", middleware.RequireRole(auth.RoleAdmin, auth.RoleDispatcher, auth.RoleDriver), h.Upgrade, websocket.New(h.Stream, websocket.Config{
+		Subprotocols: live.Subprotocols(),
+	}))
+}
+
+// Upgrade rejects plain HTTP requests and captures the caller's service,
+// tenant, identity and coordinate precision for the stream.
+func (h *LiveHandler) Upgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return errorResponse(c, http.StatusUpgradeRequired, "WebSocket upgrade required", nil)
+	}
+
+	session := &liveSession{
+		driverService: h.driverService,
+		precision:     locationPrecision(c, h.geoPolicy, geoprivacy.EndpointLive),
+		resume:        c.Query("session"),
+	}
+	if claims, ok := middleware.AuthClaims(c); ok {
+		if claims.Role == auth.RoleDriver {
+			session.driverID = claims.Subject
+		}
+		session.watcher = claims.HasRole(auth.RoleAdmin, auth.RoleDispatcher)
+	}
+	if key, ok := middleware.SandboxKey(c); ok && h.sandboxServices != nil {
+		session.driverService = h.sandboxServices.For(key)
+		session.sandbox = true
+		session.watcher = true
+	}
+	session.tenantID, _ = c.Locals(config.TenantKey).(string)
+
+	c.Locals(liveSessionLocal, session)
+	return c.Next()
+}
+
+func (h *LiveHandler) Stream(conn *websocket.Conn) {
+	session, ok := conn.Locals(liveSessionLocal).(*liveSession)
+	if !ok {
+		return
+	}
+	conn.SetReadLimit(liveReadLimit)
+
+	// The protocol version was negotiated through the subprotocol
+	codec := live.CodecFor(conn.Subprotocol())
+	messageType := websocket.TextMessage
+	if codec.Binary() {
+		messageType = websocket.BinaryMessage
+	}
+
+	sub := h.hub.Subscribe(session.tenantID)
+	var writeMu sync.Mutex
+	write := func(frame []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		conn.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+		return conn.WriteMessage(messageType, frame)
+	}
+
+	ctx := config.WithTenant(context.Background(), session.tenantID)
+	sessionID, state := h.openSession(ctx, session, sub)
+	if err := write(codec.EncodeStatus(models.LiveStatusMessage{Type: models.LiveSession, Session: sessionID, BBox: state.BBox})); err != nil {
+		h.hub.Unsubscribe(sub)
+		return
+	}
+
+	// Unsubscribing closes the updates channel, which ends the writer; the
+	// connection is only released after both loops are done.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			var err error
+			select {
+			case sample, ok := <-sub.Updates():
+				if !ok {
+					return
+				}
+				sample.Location = geoprivacy.Apply(sample.Location, session.precision)
+				err = write(codec.EncodeLocation(models.NewLiveLocationMessage(sample)))
+			case offer := <-sub.Offers():
+				err = write(codec.EncodeOffer(offer))
+			case earnings := <-sub.Earnings():
+				err = write(codec.EncodeEarnings(earnings))
+			}
+			if err != nil {
+				conn.Close()
+				return
+			}
+		}
+	}()
+	defer func() {
+		h.hub.Unsubscribe(sub)
+		<-done
+		// Saving again restarts the session's expiry from the disconnect
+		h.saveSession(ctx, sessionID, state)
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		msg, err := codec.Decode(data)
+		if err != nil {
+			return
+		}
+
+		if message := h.handleFrame(ctx, session, sub, &msg); message != "" {
+			if err := write(codec.EncodeStatus(models.LiveStatusMessage{Type: models.LiveError, Message: message})); err != nil {
+				return
+			}
+		} else {
+			if h.updateSession(&state, &msg) {
+				h.saveSession(ctx, sessionID, state)
+			}
+			if msg.Type == models.LiveSubscribe {
+				if err := write(codec.EncodeStatus(models.LiveStatusMessage{Type: models.LiveSubscribed, BBox: msg.BBox})); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// openSession resumes the session the client asked for, restoring its area
+// and driver, or starts a new one. Sessions of another tenant are not
+// resumed, and the area and driver are only restored for a caller who may
+// still have them.
+func (h *LiveHandler) openSession(ctx context.Context, session *liveSession, sub *live.Subscription) (string, live.Session) {
+	if session.resume != "" {
+		loadCtx, cancel := context.WithTimeout(ctx, liveSessionTimeout)
+		state, err := h.sessions.Load(loadCtx, session.resume)
+		cancel()
+		switch {
+		case err == nil && state.TenantID == session.tenantID:
+			if !session.watcher {
+				state.BBox = nil
+			}
+			sub.SetArea(state.BBox)
+			if session.sandbox || state.DriverID != session.driverID {
+				state.DriverID = ""
+			}
+			if state.DriverID != "" {
+				h.hub.Bind(sub, state.DriverID)
+			}
+			return session.resume, *state
+		case err != nil && !errors.Is(err, live.ErrSessionNotFound):
+			logging.FromContext(ctx).Warn("Failed to resume live session", "error", err)
+		}
+	}
+
+	state := live.Session{TenantID: session.tenantID}
+	id, err := live.NewSessionID()
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to create live session", "error", err)
+		return "", state
+	}
+	h.saveSession(ctx, id, state)
+	return id, state
+}
+
+// updateSession records an applied frame in the session state and reports
+// whether it changed. A driver's location frames bind the connection to
+// them, so their offers reach it.
+func (h *LiveHandler) updateSession(state *live.Session, msg *models.LiveClientMessage) bool {
+	switch msg.Type {
+	case models.LiveSubscribe:
+		state.BBox = msg.BBox
+	case models.LiveUnsubscribe:
+		if state.BBox == nil {
+			return false
+		}
+		state.BBox = nil
+	case models.LiveLocation:
+		if state.DriverID == msg.DriverID {
+			return false
+		}
+		state.DriverID = msg.DriverID
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *LiveHandler) saveSession(ctx context.Context, id string, state live.Session) {
+	if id == "" {
+		return
+	}
+	saveCtx, cancel := context.WithTimeout(ctx, liveSessionTimeout)
+	defer cancel()
+	if err := h.sessions.Save(saveCtx, id, state); err != nil {
+		logging.FromContext(ctx).Error("Failed to save live session", "error", err)
+	}
+}
+
+// handleFrame applies one client frame and returns an error message for the
+// client, or "" on success.
+func (h *LiveHandler) handleFrame(ctx context.Context, session *liveSession, sub *live.Subscription, msg *models.LiveClientMessage) string {
+	switch msg.Type {
+	case models.LiveSubscribe:
+		if !session.watcher {
+			return "only admins and dispatchers may subscribe"
+		}
+		if msg.BBox == nil {
+			return "subscribe requires a bbox"
+		}
+		if err := msg.BBox.Validate(); err != nil {
+			return "invalid bbox: " + strings.Join(validationErrorDetails(err), "; ")
+		}
+		sub.SetArea(msg.BBox)
+	case models.LiveUnsubscribe:
+		sub.SetArea(nil)
+	case models.LiveLocation:
+		if !session.sandbox {
+			if session.driverID == "" {
+				return "location frames need a driver access token"
+			}
+			if msg.DriverID != "" && !strings.EqualFold(msg.DriverID, session.driverID) {
+				return "driver_id does not match the access token"
+			}
+			msg.DriverID = session.driverID
+		}
+		if _, err := primitive.ObjectIDFromHex(msg.DriverID); err != nil {
+			return "invalid driver_id"
+		}
+		updateCtx, cancel := context.WithTimeout(ctx, liveUpdateTimeout)
+		defer cancel()
+		if err := session.driverService.UpdateDriverLocation(updateCtx, msg.DriverID, &models.UpdateLocationRequest{Lat: msg.Lat, Lon: msg.Lon}); err != nil {
+			return err.Error()
+		}
+		if !session.sandbox {
+			h.hub.Bind(sub, session.driverID)
+		}
+	default:
+		return "unknown frame type " + msg.Type
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MaintenanceHandler struct {
+	maintenanceService service.MaintenanceService
+}
+
+func NewMaintenanceHandler(maintenanceService service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: maintenanceService,
+	}
+}
+
+func (h *MaintenanceHandler) RegisterRoutes(app *fiber.App) {
+	staffOrSelf := middleware.RequireSelfOrRole(auth.RoleAdmin, auth.RoleDispatcher)
+	adminOrSelf := middleware.RequireSelfOrRole(auth.RoleAdmin)
+
+	maintenance := app.Group("/api/v1/drivers/:id/maintenance")
+	{
+		maintenance.Post("/", adminOrSelf, h.LogMaintenance)
+		maintenance.Get("/", staffOrSelf, h.ListMaintenance)
+		maintenance.Get("/due", staffOrSelf, h.DueMaintenance)
+	}
+}
+
+func (h *MaintenanceHandler) LogMaintenance(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	var req models.CreateMaintenanceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	record, err := h.maintenanceService.LogMaintenance(c.Context(), id, &req)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to log maintenance", []string{err.Error()})
+	}
+
+	return c.Status(http.StatusCreated).JSON(record)
+}
+
+func (h *MaintenanceHandler) ListMaintenance(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	records, err := h.maintenanceService.ListMaintenance(c.Context(), id)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list maintenance", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": records,
+	})
+}
+
+func (h *MaintenanceHandler) DueMaintenance(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	due, err := h.maintenanceService.DueMaintenance(c.Context(), id)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to compute due maintenance", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": due,
+	})
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/metrics"
+)
+
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type MetricsHandler struct {
+	registry *metrics.Registry
+}
+
+func NewMetricsHandler(registry *metrics.Registry) *MetricsHandler {
+	return &MetricsHandler{
+		registry: registry,
+	}
+}
+
+func (h *MetricsHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/metrics", h.GetMetrics)
+}
+
+// GetMetrics exposes the registered metrics for Prometheus to scrape.
+func (h *MetricsHandler) GetMetrics(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, prometheusContentType)
+	return h.registry.WriteMetrics(c)
+}
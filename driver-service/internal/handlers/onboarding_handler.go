@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+type OnboardingHandler struct {
+	onboardingService service.OnboardingService
+}
+
+func NewOnboardingHandler(onboardingService service.OnboardingService) *OnboardingHandler {
+	return &OnboardingHandler{
+		onboardingService: onboardingService,
+	}
+}
+
+// RegisterRoutes registers the admin onboarding reports.
+func (h *OnboardingHandler) RegisterRoutes(app *fiber.App) {
+	onboarding := app.Group("/api/v1/admin/onboarding", middleware.RequireRole(auth.RoleAdmin))
+	{
+		onboarding.Get("/funnel", h.GetFunnel)
+		onboarding.Get("/stalled", h.ListStalled)
+	}
+}
+
+// GetFunnel returns the onboarding funnel of the drivers who registered
+// between from and to (default the last 30 days), for one fleet or all of
+// them.
+func (h *OnboardingHandler) GetFunnel(c *fiber.Ctx) error {
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "to must be an RFC 3339 time", nil)
+		}
+		to = parsed
+	}
+	from := to.Add(-models.DefaultOnboardingWindow)
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "from must be an RFC 3339 time", nil)
+		}
+		from = parsed
+	}
+
+	funnel, err := h.onboardingService.Funnel(c.Context(), c.Query("fleet_id"), from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrValidationFailed) {
+			return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to get onboarding funnel", []string{err.Error()})
+	}
+
+	return c.JSON(funnel)
+}
+
+// ListStalled returns the drivers who have been waiting at least older_than
+// (default 72h) to reach the given step, longest waiting first.
+func (h *OnboardingHandler) ListStalled(c *fiber.Ctx) error {
+	olderThan := models.DefaultStalledOnboardingAge
+	if olderThanStr := c.Query("older_than"); olderThanStr != "" {
+		parsed, err := time.ParseDuration(olderThanStr)
+		if err != nil || parsed < 0 {
+			return errorResponse(c, http.StatusBadRequest, "older_than must be a non-negative duration such as 48h", nil)
+		}
+		olderThan = parsed
+	}
+
+	drivers, err := h.onboardingService.Stalled(c.Context(), c.Query("fleet_id"), c.Query("step"), olderThan, c.QueryInt("limit", models.DefaultStalledOnboardingList))
+	if err != nil {
+		if errors.Is(err, service.ErrValidationFailed) {
+			return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list stalled drivers", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": drivers,
+	})
+}
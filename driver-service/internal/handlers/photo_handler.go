@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxPhotoImageBytes = 5 << 20
+
+type PhotoHandler struct {
+	photoService service.PhotoService
+}
+
+func NewPhotoHandler(photoService service.PhotoService) *PhotoHandler {
+	return &PhotoHandler{
+		photoService: photoService,
+	}
+}
+
+func (h *PhotoHandler) RegisterRoutes(app *fiber.App) {
+	staffOrSelf := middleware.RequireSelfOrRole(auth.RoleAdmin, auth.RoleDispatcher)
+	adminOrSelf := middleware.RequireSelfOrRole(auth.RoleAdmin)
+
+	driver := app.Group("/api/v1/drivers/:id")
+	{
+		driver.Post("/photos", adminOrSelf, h.UploadPhoto)
+		driver.Get("/photos", staffOrSelf, h.ListDriverPhotos)
+		driver.Get("/photo", staffOrSelf, h.GetApprovedPhoto)
+	}
+
+	admin := app.Group("/api/v1/admin/photos", middleware.RequireRole(auth.RoleAdmin))
+	{
+		admin.Get("/", h.ListPhotos)
+		admin.Get("/:photoId/image", h.GetPhotoImage)
+		admin.Post("/:photoId/approve", h.ApprovePhoto)
+		admin.Post("/:photoId/reject", h.RejectPhoto)
+	}
+}
+
+// UploadPhoto accepts a multipart "image" file and returns it with the
+// results of the automated checks.
+func (h *PhotoHandler) UploadPhoto(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "image file is required", nil)
+	}
+	if file.Size > maxPhotoImageBytes {
+		return errorResponse(c, http.StatusRequestEntityTooLarge, "image must be at most 5MB", nil)
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Failed to read image", nil)
+	}
+	defer reader.Close()
+
+	image, err := io.ReadAll(reader)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Failed to read image", nil)
+	}
+
+	photo, err := h.photoService.Upload(c.Context(), id, image, http.DetectContentType(image))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDriverNotFound):
+			return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+		case errors.Is(err, service.ErrUnsupportedImageType):
+			return errorResponse(c, http.StatusUnsupportedMediaType, "image must be a JPEG or PNG", nil)
+		default:
+			return errorResponse(c, http.StatusInternalServerError, "Failed to upload photo", []string{err.Error()})
+		}
+	}
+
+	return c.Status(http.StatusCreated).JSON(photo)
+}
+
+func (h *PhotoHandler) ListDriverPhotos(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	photos, err := h.photoService.ListForDriver(c.Context(), id)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list photos", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": photos,
+	})
+}
+
+// GetApprovedPhoto serves the driver's approved photo to riders. Pending and
+// rejected uploads are never returned here.
+func (h *PhotoHandler) GetApprovedPhoto(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	photo, err := h.photoService.GetApproved(c.Context(), id)
+	if err != nil {
+		return photoError(c, err, "Failed to get photo")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return h.sendPhoto(c, photo)
+}
+
+func (h *PhotoHandler) ListPhotos(c *fiber.Ctx) error {
+	status := c.Query("status", models.PhotoPending)
+	switch status {
+	case models.PhotoPending, models.PhotoApproved, models.PhotoRejected, models.PhotoSuperseded, "all":
+	default:
+		return errorResponse(c, http.StatusBadRequest, "Invalid status", []string{"status must be one of: pending approved rejected superseded all"})
+	}
+	if status == "all" {
+		status = ""
+	}
+
+	photos, err := h.photoService.ListByStatus(c.Context(), status, c.QueryInt("limit", 100))
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list photos", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": photos,
+	})
+}
+
+func (h *PhotoHandler) GetPhotoImage(c *fiber.Ctx) error {
+	id := c.Params("photoId")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid photo ID format", nil)
+	}
+
+	photo, err := h.photoService.Get(c.Context(), id)
+	if err != nil {
+		return photoError(c, err, "Failed to get photo")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return h.sendPhoto(c, photo)
+}
+
+func (h *PhotoHandler) ApprovePhoto(c *fiber.Ctx) error {
+	return h.review(c, h.photoService.Approve, "Failed to approve photo")
+}
+
+func (h *PhotoHandler) RejectPhoto(c *fiber.Ctx) error {
+	return h.review(c, h.photoService.Reject, "Failed to reject photo")
+}
+
+func (h *PhotoHandler) review(c *fiber.Ctx, decide func(ctx context.Context, id string, req *models.ReviewPhotoRequest) (*models.DriverPhoto, error), failure string) error {
+	id := c.Params("photoId")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid photo ID format", nil)
+	}
+
+	var req models.ReviewPhotoRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	photo, err := decide(c.Context(), id, &req)
+	if err != nil {
+		return photoError(c, err, failure)
+	}
+
+	return c.JSON(photo)
+}
+
+func (h *PhotoHandler) sendPhoto(c *fiber.Ctx, photo *models.DriverPhoto) error {
+	image, err := h.photoService.OpenImage(c.Context(), photo)
+	if err != nil {
+		return photoError(c, err, "Failed to get photo")
+	}
+
+	c.Set(fiber.HeaderContentType, photo.ContentType)
+	return c.SendStream(image, photo.Size)
+}
+
+func photoError(c *fiber.Ctx, err error, failure string) error {
+	switch {
+	case errors.Is(err, service.ErrDriverNotFound):
+		return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+	case errors.Is(err, service.ErrPhotoNotFound):
+		return errorResponse(c, http.StatusNotFound, "Photo not found", nil)
+	case errors.Is(err, service.ErrPhotoNotPending):
+		return errorResponse(c, http.StatusConflict, failure, []string{err.Error()})
+	default:
+		return errorResponse(c, http.StatusInternalServerError, failure, []string{err.Error()})
+	}
+}
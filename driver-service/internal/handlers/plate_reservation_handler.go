@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+type PlateReservationHandler struct {
+	reservationService service.PlateReservationService
+}
+
+func NewPlateReservationHandler(reservationService service.PlateReservationService) *PlateReservationHandler {
+	return &PlateReservationHandler{
+		reservationService: reservationService,
+	}
+}
+
+func (h *PlateReservationHandler) RegisterRoutes(app *fiber.App) {
+	reservations := app.Group("/api/v1/plate-reservations", middleware.RequireRole(auth.RoleAdmin))
+	{
+		reservations.Post("/", h.Reserve)
+		reservations.Delete("/:plate", h.Release)
+	}
+}
+
+func (h *PlateReservationHandler) Reserve(c *fiber.Ctx) error {
+	var req models.ReservePlateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	reservation, err := h.reservationService.Reserve(c.Context(), &req)
+	if err != nil {
+		return plateReservationError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(reservation)
+}
+
+func (h *PlateReservationHandler) Release(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return errorResponse(c, http.StatusBadRequest, "token query parameter is required", nil)
+	}
+
+	if err := h.reservationService.Release(c.Context(), c.Params("plate"), token); err != nil {
+		return plateReservationError(c, err)
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func plateReservationError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrPlateTaken):
+		return errorResponse(c, http.StatusConflict, "Plate is already registered to a driver", nil)
+	case errors.Is(err, service.ErrPlateReserved):
+		return errorResponse(c, http.StatusConflict, "Plate is reserved by another registration", nil)
+	case errors.Is(err, service.ErrPlateReservationNotFound):
+		return errorResponse(c, http.StatusNotFound, "Plate reservation not found", nil)
+	default:
+		return errorResponse(c, http.StatusInternalServerError, "Failed to process plate reservation", []string{err.Error()})
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+// PublicHandler serves unauthenticated, rider-facing endpoints. Responses
+// must never contain personal data.
+type PublicHandler struct {
+	driverService service.DriverService
+	rateLimit     int
+	rateWindow    time.Duration
+}
+
+func NewPublicHandler(driverService service.DriverService, rateLimit int, rateWindow time.Duration) *PublicHandler {
+	return &PublicHandler{
+		driverService: driverService,
+		rateLimit:     rateLimit,
+		rateWindow:    rateWindow,
+	}
+}
+
+func (h *PublicHandler) RegisterRoutes(app *fiber.App) {
+	public := app.Group("/api/v1/public", limiter.New(limiter.Config{
+		Max:        h.rateLimit,
+		Expiration: h.rateWindow,
+		LimitReached: func(c *fiber.Ctx) error {
+			return errorResponse(c, http.StatusTooManyRequests, "Too many requests", nil)
+		},
+	}))
+	public.Get("/verify-plate", h.VerifyPlate)
+}
+
+func (h *PublicHandler) VerifyPlate(c *fiber.Ctx) error {
+	plate := strings.TrimSpace(c.Query("plate"))
+	if plate == "" {
+		return errorResponse(c, http.StatusBadRequest, "plate is required", nil)
+	}
+	if len(plate) > 20 {
+		return errorResponse(c, http.StatusBadRequest, "plate is invalid", nil)
+	}
+
+	result, err := h.driverService.VerifyPlate(c.Context(), plate)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to verify plate", nil)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(result)
+}
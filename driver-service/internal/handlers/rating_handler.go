@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RatingHandler struct {
+	ratingService service.RatingService
+}
+
+func NewRatingHandler(ratingService service.RatingService) *RatingHandler {
+	return &RatingHandler{
+		ratingService: ratingService,
+	}
+}
+
+// RegisterRoutes registers the rating routes. Ratings are submitted on a
+// rider's behalf by admins and dispatchers, never by drivers; a driver may
+// read their own.
+func (h *RatingHandler) RegisterRoutes(app *fiber.App) {
+	ratings := app.Group("/api/v1/drivers/:id/ratings")
+	{
+		ratings.Post("/", middleware.RequireRole(auth.RoleAdmin, auth.RoleDispatcher), h.RateDriver)
+		ratings.Get("/", middleware.RequireSelfOrRole(auth.RoleAdmin, auth.RoleDispatcher), h.ListRatings)
+	}
+}
+
+func (h *RatingHandler) RateDriver(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	var req models.CreateRatingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	result, err := h.ratingService.Rate(c.Context(), id, &req)
+	if err != nil {
+		return ratingError(c, err, "Failed to rate driver")
+	}
+
+	return c.Status(http.StatusCreated).JSON(result)
+}
+
+func (h *RatingHandler) ListRatings(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+	}
+
+	limit := models.DefaultRatingListLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > models.MaxRatingListLimit {
+			return errorResponse(c, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", models.MaxRatingListLimit), nil)
+		}
+		limit = parsed
+	}
+
+	ratings, err := h.ratingService.List(c.Context(), id, limit)
+	if err != nil {
+		return ratingError(c, err, "Failed to list ratings")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": ratings,
+	})
+}
+
+func ratingError(c *fiber.Ctx, err error, failure string) error {
+	switch {
+	case errors.Is(err, service.ErrDriverNotFound):
+		return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+	case errors.Is(err, service.ErrTripNotFound):
+		return errorResponse(c, http.StatusNotFound, "Trip not found", nil)
+	case errors.Is(err, service.ErrTripRated):
+		return errorResponse(c, http.StatusConflict, "Trip has already been rated", nil)
+	case errors.Is(err, service.ErrValidationFailed):
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+	}
+	return errorResponse(c, http.StatusInternalServerError, failure, []string{err.Error()})
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RatingPromptHandler struct {
+	promptService service.RatingPromptService
+}
+
+func NewRatingPromptHandler(promptService service.RatingPromptService) *RatingPromptHandler {
+	return &RatingPromptHandler{
+		promptService: promptService,
+	}
+}
+
+// RegisterRoutes registers the admin routes for tuning rating prompts per
+// fleet and following their conversion.
+func (h *RatingPromptHandler) RegisterRoutes(app *fiber.App) {
+	prompts := app.Group("/api/v1/admin/rating-prompts", middleware.RequireRole(auth.RoleAdmin))
+	{
+		prompts.Get("/settings", h.ListSettings)
+		prompts.Get("/settings/:fleetId", h.GetSettings)
+		prompts.Put("/settings/:fleetId", h.UpdateSettings)
+		prompts.Delete("/settings/:fleetId", h.ResetSettings)
+		prompts.Get("/stats", h.GetStats)
+		prompts.Get("/trips/:tripId", h.GetPrompt)
+	}
+}
+
+// ListSettings returns the defaults and the fleets that override them.
+func (h *RatingPromptHandler) ListSettings(c *fiber.Ctx) error {
+	defaults, err := h.promptService.Settings(c.Context(), "")
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to get rating prompt settings", []string{err.Error()})
+	}
+
+	settings, err := h.promptService.ListSettings(c.Context())
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list rating prompt settings", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"default": defaults,
+		"data":    settings,
+	})
+}
+
+func (h *RatingPromptHandler) GetSettings(c *fiber.Ctx) error {
+	settings, err := h.promptService.Settings(c.Context(), c.Params("fleetId"))
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to get rating prompt settings", []string{err.Error()})
+	}
+
+	return c.JSON(settings)
+}
+
+func (h *RatingPromptHandler) UpdateSettings(c *fiber.Ctx) error {
+	var req models.UpdateRatingPromptSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	settings, err := h.promptService.UpdateSettings(c.Context(), c.Params("fleetId"), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrValidationFailed) {
+			return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to update rating prompt settings", []string{err.Error()})
+	}
+
+	return c.JSON(settings)
+}
+
+func (h *RatingPromptHandler) ResetSettings(c *fiber.Ctx) error {
+	if err := h.promptService.ResetSettings(c.Context(), c.Params("fleetId")); err != nil {
+		if errors.Is(err, service.ErrRatingPromptSettingsNotFound) {
+			return errorResponse(c, http.StatusNotFound, "Fleet uses the default rating prompt settings", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to reset rating prompt settings", []string{err.Error()})
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// GetStats returns the prompt funnel of the prompts scheduled since the
+// given time (default 30 days ago), for one fleet or all of them.
+func (h *RatingPromptHandler) GetStats(c *fiber.Ctx) error {
+	since := time.Now().Add(-models.DefaultRatingPromptStatsWindow)
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "since must be an RFC 3339 time", nil)
+		}
+		since = parsed
+	}
+
+	stats, err := h.promptService.Stats(c.Context(), c.Query("fleet_id"), since)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to get rating prompt stats", []string{err.Error()})
+	}
+
+	return c.JSON(stats)
+}
+
+func (h *RatingPromptHandler) GetPrompt(c *fiber.Ctx) error {
+	tripID := c.Params("tripId")
+	if _, err := primitive.ObjectIDFromHex(tripID); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid trip ID format", nil)
+	}
+
+	prompt, err := h.promptService.Get(c.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, service.ErrRatingPromptNotFound) {
+			return errorResponse(c, http.StatusNotFound, "Rating prompt not found", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to get rating prompt", []string{err.Error()})
+	}
+
+	return c.JSON(prompt)
+}
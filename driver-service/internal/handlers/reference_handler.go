@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+// ReferenceHandler serves reference data that only changes with a deploy
+// or config change. Responses carry an ETag, so clients revalidate with
+// If-None-Match and get 304 when nothing changed.
+type ReferenceHandler struct {
+	data   *models.ReferenceData
+	maxAge time.Duration
+}
+
+func NewReferenceHandler(data *models.ReferenceData, maxAge time.Duration) *ReferenceHandler {
+	return &ReferenceHandler{
+		data:   data,
+		maxAge: maxAge,
+	}
+}
+
+func (h *ReferenceHandler) RegisterRoutes(app *fiber.App) {
+	reference := app.Group("/api/v1/reference", etag.New(), h.cacheControl)
+	{
+		reference.Get("/", h.GetBundle)
+		reference.Get("/taxi-types", h.GetTaxiTypes)
+		reference.Get("/tariffs", h.GetTariffs)
+	}
+}
+
+func (h *ReferenceHandler) cacheControl(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds())))
+	return c.Next()
+}
+
+// GetBundle returns all reference data in one response, so an app syncs its
+// configuration with a single conditional request.
+func (h *ReferenceHandler) GetBundle(c *fiber.Ctx) error {
+	return c.JSON(h.data)
+}
+
+func (h *ReferenceHandler) GetTaxiTypes(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"data": h.data.TaxiTypes,
+	})
+}
+
+func (h *ReferenceHandler) GetTariffs(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"data": h.data.Tariffs,
+	})
+}
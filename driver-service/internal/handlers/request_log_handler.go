@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+type RequestLogHandler struct {
+	requestLogRepo repository.RequestLogRepository
+}
+
+func NewRequestLogHandler(requestLogRepo repository.RequestLogRepository) *RequestLogHandler {
+	return &RequestLogHandler{
+		requestLogRepo: requestLogRepo,
+	}
+}
+
+func (h *RequestLogHandler) RegisterRoutes(app *fiber.App) {
+	admin := app.Group("/api/v1/admin")
+	admin.Get("/request-logs", middleware.RequireRole(auth.RoleAdmin), h.ListRequestLogs)
+}
+
+func (h *RequestLogHandler) ListRequestLogs(c *fiber.Ctx) error {
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	entries, err := h.requestLogRepo.FindRecent(c.Context(), c.Query("request_id"), limit)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to list request logs", []string{err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": entries,
+	})
+}
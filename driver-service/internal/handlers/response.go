@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/geoprivacy"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+func errorResponse(c *fiber.Ctx, statusCode int, message string, details []string) error {
+	response := models.ErrorResponse{
+		Error:   message,
+		Details: details,
+		Code:    statusCode,
+	}
+	return c.Status(statusCode).JSON(response)
+}
+
+// locationPrecision resolves how precisely the caller may see driver
+// coordinates on the endpoint. The role comes from the caller's verified
+// access token; callers without one get the endpoint's rule.
+func locationPrecision(c *fiber.Ctx, policy *geoprivacy.Policy, endpoint string) string {
+	if policy == nil {
+		return geoprivacy.PrecisionExact
+	}
+	var role string
+	if claims, ok := middleware.AuthClaims(c); ok {
+		role = claims.Role
+	}
+	return policy.Precision(endpoint, role)
+}
+
+// validationFailureDetails formats a rejected request's validation error and
+// notes it for the validation stats.
+func validationFailureDetails(c *fiber.Ctx, err error) []string {
+	middleware.NoteValidationFailure(c, err)
+	return validationErrorDetails(err)
+}
+
+func validationErrorDetails(err error) []string {
+	var details []string
+	if validationErr, ok := err.(validator.ValidationErrors); ok {
+		for _, e := range validationErr {
+			details = append(details, formatValidationError(e))
+		}
+	} else {
+		details = append(details, err.Error())
+	}
+	return details
+}
+
+func formatValidationError(err validator.FieldError) string {
+	field := strings.ToLower(err.Field())
+	tag := err.Tag()
+
+	switch tag {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "min":
+		return fmt.Sprintf("%s must be at least %s characters", field, err.Param())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s characters", field, err.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, err.Param())
+	case "startswith":
+		return fmt.Sprintf("%s must start with %s", field, err.Param())
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+	case "turkish_plate":
+		return "plate must be a valid Turkish license plate (e.g., 34 ABC 123)"
+	case "tckn":
+		return "tckn must be a valid 11-digit Turkish identity number"
+	case "vergi_no":
+		return "tax_number must be a valid 10-digit Turkish tax number"
+	case "datetime":
+		return fmt.Sprintf("%s must be a date in %s format", field, err.Param())
+	case "app_version":
+		return fmt.Sprintf("%s must be a dotted version number (e.g., 4.12.0)", field)
+	case "locale":
+		return fmt.Sprintf("%s must use valid locale keys (e.g., en, en-GB)", field)
+	case "field_key":
+		return fmt.Sprintf("%s must start with a lowercase letter and use only lowercase letters, digits and underscores", field)
+	default:
+		return fmt.Sprintf("%s is invalid", field)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+type RiderHandler struct {
+	preferencesService service.RiderPreferencesService
+}
+
+func NewRiderHandler(preferencesService service.RiderPreferencesService) *RiderHandler {
+	return &RiderHandler{
+		preferencesService: preferencesService,
+	}
+}
+
+// RegisterRoutes registers the routes for riders' saved ride preferences,
+// which admins and dispatchers keep on the booking system's behalf. Like
+// trips, they are not available in the sandbox.
+func (h *RiderHandler) RegisterRoutes(app *fiber.App) {
+	staff := middleware.RequireRole(auth.RoleAdmin, auth.RoleDispatcher)
+
+	riders := app.Group("/api/v1/riders", tripScope, staff)
+	{
+		riders.Get("/:riderId/preferences", h.GetPreferences)
+		riders.Put("/:riderId/preferences", h.UpdatePreferences)
+		riders.Delete("/:riderId/preferences", h.DeletePreferences)
+	}
+}
+
+func (h *RiderHandler) GetPreferences(c *fiber.Ctx) error {
+	preferences, err := h.preferencesService.Get(c.Context(), c.Params("riderId"))
+	if err != nil {
+		if errors.Is(err, service.ErrRiderPreferencesNotFound) {
+			return errorResponse(c, http.StatusNotFound, "Rider has no saved preferences", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to get rider preferences", []string{err.Error()})
+	}
+
+	return c.JSON(preferences)
+}
+
+func (h *RiderHandler) UpdatePreferences(c *fiber.Ctx) error {
+	var req models.RidePreferences
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	preferences, err := h.preferencesService.Update(c.Context(), c.Params("riderId"), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrValidationFailed) {
+			return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to update rider preferences", []string{err.Error()})
+	}
+
+	return c.JSON(preferences)
+}
+
+func (h *RiderHandler) DeletePreferences(c *fiber.Ctx) error {
+	if err := h.preferencesService.Delete(c.Context(), c.Params("riderId")); err != nil {
+		if errors.Is(err, service.ErrRiderPreferencesNotFound) {
+			return errorResponse(c, http.StatusNotFound, "Rider has no saved preferences", nil)
+		}
+		return errorResponse(c, http.StatusInternalServerError, "Failed to delete rider preferences", []string{err.Error()})
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
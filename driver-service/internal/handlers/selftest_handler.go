@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/selftest"
+)
+
+type SelfTestHandler struct {
+	newSuite func() *selftest.Suite
+}
+
+// NewSelfTestHandler builds a fresh suite for every run, since checks share
+// state such as the probe driver.
+func NewSelfTestHandler(newSuite func() *selftest.Suite) *SelfTestHandler {
+	return &SelfTestHandler{
+		newSuite: newSuite,
+	}
+}
+
+func (h *SelfTestHandler) RegisterRoutes(app *fiber.App) {
+	app.Post("/api/v1/admin/selftest", middleware.RequireRole(auth.RoleAdmin), h.RunSelfTest)
+}
+
+// RunSelfTest runs the suite against the caller's tenant database and
+// answers 503 if any check failed, so deploy pipelines can gate on the
+// status code.
+func (h *SelfTestHandler) RunSelfTest(c *fiber.Ctx) error {
+	report := h.newSuite().Run(c.Context())
+	report.Tenant = config.TenantFromContext(c.Context())
+
+	status := http.StatusOK
+	if report.Status == selftest.StatusFailed {
+		status = http.StatusServiceUnavailable
+	}
+
+	return c.Status(status).JSON(report)
+}
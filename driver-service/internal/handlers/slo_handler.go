@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/slo"
+)
+
+type SLOHandler struct {
+	tracker *slo.Tracker
+}
+
+func NewSLOHandler(tracker *slo.Tracker) *SLOHandler {
+	return &SLOHandler{
+		tracker: tracker,
+	}
+}
+
+func (h *SLOHandler) RegisterRoutes(app *fiber.App) {
+	admin := app.Group("/api/v1/admin")
+	admin.Get("/slo", middleware.RequireRole(auth.RoleAdmin), h.GetSLOStatus)
+}
+
+func (h *SLOHandler) GetSLOStatus(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"objectives": h.tracker.Report(),
+	})
+}
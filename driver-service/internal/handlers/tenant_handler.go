@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+const tenantMigrationTimeout = time.Minute
+
+type TenantHandler struct {
+	db      *config.MongoDB
+	indexes map[string]*repository.IndexManager
+}
+
+// NewTenantHandler takes the index manager of every isolated tenant database,
+// keyed by tenant ID.
+func NewTenantHandler(db *config.MongoDB, indexes map[string]*repository.IndexManager) *TenantHandler {
+	return &TenantHandler{
+		db:      db,
+		indexes: indexes,
+	}
+}
+
+func (h *TenantHandler) RegisterRoutes(app *fiber.App) {
+	admin := app.Group("/api/v1/admin/tenants", middleware.RequireRole(auth.RoleAdmin))
+	{
+		admin.Get("/", h.ListTenants)
+		admin.Post("/:tenantId/migrate", h.MigrateTenant)
+	}
+}
+
+func (h *TenantHandler) ListTenants(c *fiber.Ctx) error {
+	tenants := make([]fiber.Map, 0, len(h.indexes))
+	for _, tenantID := range h.db.TenantIDs() {
+		tenants = append(tenants, h.tenantStatus(tenantID))
+	}
+
+	return c.JSON(fiber.Map{
+		"tenants": tenants,
+	})
+}
+
+// MigrateTenant creates any missing indexes in the tenant's database right
+// away instead of waiting for the next background check.
+func (h *TenantHandler) MigrateTenant(c *fiber.Ctx) error {
+	tenantID := c.Params("tenantId")
+	manager, ok := h.indexes[tenantID]
+	if !ok {
+		return errorResponse(c, http.StatusNotFound, "Tenant not found", nil)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), tenantMigrationTimeout)
+	defer cancel()
+
+	if _, err := manager.Ensure(ctx); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to migrate tenant", []string{err.Error()})
+	}
+
+	return c.JSON(h.tenantStatus(tenantID))
+}
+
+func (h *TenantHandler) tenantStatus(tenantID string) fiber.Map {
+	status := fiber.Map{
+		"tenant_id": tenantID,
+	}
+	if tenant, ok := h.db.Tenant(tenantID); ok {
+		status["database"] = tenant.Database.Name()
+	}
+	if manager, ok := h.indexes[tenantID]; ok {
+		status["ready"] = manager.Ready()
+		status["indexes"] = manager.Statuses()
+	}
+	return status
+}
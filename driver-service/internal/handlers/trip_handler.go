@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TripHandler struct {
+	tripService service.TripService
+}
+
+func NewTripHandler(tripService service.TripService) *TripHandler {
+	return &TripHandler{
+		tripService: tripService,
+	}
+}
+
+// RegisterRoutes registers the trip routes. Admins and dispatchers create,
+// assign, dispatch and list trips; the assigned driver may read their trip,
+// answer its offer, acknowledge the rider's preferences and move it along.
+func (h *TripHandler) RegisterRoutes(app *fiber.App) {
+	staff := middleware.RequireRole(auth.RoleAdmin, auth.RoleDispatcher)
+	staffOrDriver := middleware.RequireRole(auth.RoleAdmin, auth.RoleDispatcher, auth.RoleDriver)
+
+	trips := app.Group("/api/v1/trips", tripScope)
+	{
+		trips.Post("/", staff, h.CreateTrip)
+		trips.Get("/", staff, h.ListTrips)
+		trips.Get("/:id", staffOrDriver, h.GetTrip)
+		trips.Post("/:id/assign", staff, h.AssignTrip)
+		trips.Post("/:id/dispatch", staff, h.DispatchTrip)
+		trips.Post("/:id/accept", staffOrDriver, h.AcceptOffer)
+		trips.Post("/:id/decline", staffOrDriver, h.DeclineOffer)
+		trips.Put("/:id/status", staffOrDriver, h.UpdateTripStatus)
+		trips.Put("/:id/preferences", staff, h.UpdateTripPreferences)
+		trips.Post("/:id/preferences/ack", staffOrDriver, h.AcknowledgePreferences)
+	}
+}
+
+// tripScope keeps sandbox requests out, as the synthetic datasets have no
+// trips.
+func tripScope(c *fiber.Ctx) error {
+	if _, ok := middleware.SandboxKey(c); ok {
+		return errorResponse(c, http.StatusForbidden, "Trip routes are not available in the sandbox", nil)
+	}
+	return c.Next()
+}
+
+func (h *TripHandler) CreateTrip(c *fiber.Ctx) error {
+	var req models.CreateTripRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	createdBy := ""
+	if claims, ok := middleware.AuthClaims(c); ok {
+		createdBy = claims.Subject
+	}
+
+	trip, err := h.tripService.Create(c.Context(), createdBy, &req)
+	if err != nil {
+		return tripError(c, err, "Failed to create trip")
+	}
+
+	return c.Status(http.StatusCreated).JSON(trip)
+}
+
+func (h *TripHandler) ListTrips(c *fiber.Ctx) error {
+	driverID := c.Query("driver_id")
+	if driverID != "" {
+		if _, err := primitive.ObjectIDFromHex(driverID); err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid driver ID format", nil)
+		}
+	}
+
+	status := c.Query("status")
+	if status != "" && !models.IsValidTripStatus(status) {
+		return errorResponse(c, http.StatusBadRequest, "Invalid trip status", nil)
+	}
+
+	limit := models.DefaultTripListLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > models.MaxTripListLimit {
+			return errorResponse(c, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", models.MaxTripListLimit), nil)
+		}
+		limit = parsed
+	}
+
+	trips, err := h.tripService.List(c.Context(), driverID, status, limit)
+	if err != nil {
+		return tripError(c, err, "Failed to list trips")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": trips,
+	})
+}
+
+func (h *TripHandler) GetTrip(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid trip ID format", nil)
+	}
+
+	trip, err := h.visibleTrip(c, id)
+	if err != nil {
+		return tripError(c, err, "Failed to get trip")
+	}
+
+	return c.JSON(trip)
+}
+
+func (h *TripHandler) AssignTrip(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid trip ID format", nil)
+	}
+
+	var req models.AssignTripRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	trip, err := h.tripService.Assign(c.Context(), id, &req)
+	if err != nil {
+		return tripError(c, err, "Failed to assign trip")
+	}
+
+	return c.JSON(trip)
+}
+
+// DispatchTrip offers a created trip to the best matching driver.
+func (h *TripHandler) DispatchTrip(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid trip ID format", nil)
+	}
+
+	trip, err := h.tripService.Dispatch(c.Context(), id)
+	if err != nil {
+		return tripError(c, err, "Failed to dispatch trip")
+	}
+
+	return c.JSON(trip)
+}
+
+func (h *TripHandler) AcceptOffer(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid trip ID format", nil)
+	}
+
+	if _, err := h.visibleTrip(c, id); err != nil {
+		return tripError(c, err, "Failed to accept trip offer")
+	}
+
+	trip, err := h.tripService.AcceptOffer(c.Context(), id, offerDriverID(c))
+	if err != nil {
+		return tripError(c, err, "Failed to accept trip offer")
+	}
+
+	return c.JSON(trip)
+}
+
+// DeclineOffer declines the pending offer. Staff get the trip as it is
+// afterwards; drivers get no content, as the trip is no longer theirs.
+func (h *TripHandler) DeclineOffer(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid trip ID format", nil)
+	}
+
+	if _, err := h.visibleTrip(c, id); err != nil {
+		return tripError(c, err, "Failed to decline trip offer")
+	}
+
+	driverID := offerDriverID(c)
+	trip, err := h.tripService.DeclineOffer(c.Context(), id, driverID)
+	if err != nil {
+		return tripError(c, err, "Failed to decline trip offer")
+	}
+
+	if driverID != "" {
+		return c.SendStatus(http.StatusNoContent)
+	}
+	return c.JSON(trip)
+}
+
+func (h *TripHandler) UpdateTripStatus(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid trip ID format", nil)
+	}
+
+	var req models.UpdateTripStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+	if req.Fare != nil && !setsFares(c) {
+		return errorResponse(c, http.StatusForbidden, "Only admins and dispatchers may set the fare", nil)
+	}
+
+	if _, err := h.visibleTrip(c, id); err != nil {
+		return tripError(c, err, "Failed to update trip status")
+	}
+
+	trip, err := h.tripService.UpdateStatus(c.Context(), id, &req)
+	if err != nil {
+		return tripError(c, err, "Failed to update trip status")
+	}
+
+	return c.JSON(trip)
+}
+
+// UpdateTripPreferences replaces the rider's preferences of a trip that is
+// still waiting for a driver.
+func (h *TripHandler) UpdateTripPreferences(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid trip ID format", nil)
+	}
+
+	var req models.RidePreferences
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid JSON format", nil)
+	}
+
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", validationFailureDetails(c, err))
+	}
+
+	trip, err := h.tripService.UpdatePreferences(c.Context(), id, &req)
+	if err != nil {
+		return tripError(c, err, "Failed to update trip preferences")
+	}
+
+	return c.JSON(trip)
+}
+
+// AcknowledgePreferences records that the assigned driver has seen the
+// rider's preferences. Staff may acknowledge for the driver.
+func (h *TripHandler) AcknowledgePreferences(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid trip ID format", nil)
+	}
+
+	if _, err := h.visibleTrip(c, id); err != nil {
+		return tripError(c, err, "Failed to acknowledge trip preferences")
+	}
+
+	trip, err := h.tripService.AcknowledgePreferences(c.Context(), id, offerDriverID(c))
+	if err != nil {
+		return tripError(c, err, "Failed to acknowledge trip preferences")
+	}
+
+	return c.JSON(trip)
+}
+
+// visibleTrip loads a trip the caller may see. Drivers only see trips
+// assigned to them; other trips are reported as not found.
+func (h *TripHandler) visibleTrip(c *fiber.Ctx, id string) (*models.Trip, error) {
+	trip, err := h.tripService.Get(c.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if claims, ok := middleware.AuthClaims(c); ok && claims.Role == auth.RoleDriver && !claims.IsDriver(trip.DriverID) {
+		return nil, service.ErrTripNotFound
+	}
+	return trip, nil
+}
+
+// offerDriverID is the driver whose offer a driver caller may answer: their
+// own. Staff may answer any offer.
+func offerDriverID(c *fiber.Ctx) string {
+	if claims, ok := middleware.AuthClaims(c); ok && claims.Role == auth.RoleDriver {
+		return claims.Subject
+	}
+	return ""
+}
+
+// setsFares reports whether the caller may give the fare of a completed
+// trip. The fare is booked to the driver's earnings, so drivers may not set
+// their own.
+func setsFares(c *fiber.Ctx) bool {
+	claims, ok := middleware.AuthClaims(c)
+	return ok && claims.HasRole(auth.RoleAdmin, auth.RoleDispatcher)
+}
+
+func tripError(c *fiber.Ctx, err error, failure string) error {
+	var paused *service.DispatchPausedError
+	if errors.As(err, &paused) {
+		retryAfter := math.Ceil(time.Until(paused.Pause.EndsAt).Seconds())
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Max(retryAfter, 1))))
+		return errorResponse(c, http.StatusServiceUnavailable, "Dispatch is paused in this area", []string{
+			paused.Pause.Name + ": " + paused.Pause.Reason,
+			"paused until " + paused.Pause.EndsAt.Format(time.RFC3339),
+		})
+	}
+
+	switch {
+	case errors.Is(err, service.ErrTripNotFound):
+		return errorResponse(c, http.StatusNotFound, "Trip not found", nil)
+	case errors.Is(err, service.ErrDriverNotFound):
+		return errorResponse(c, http.StatusNotFound, "Driver not found", nil)
+	case errors.Is(err, service.ErrInvalidTripTransition):
+		return errorResponse(c, http.StatusConflict, "Trip status cannot change this way", []string{err.Error()})
+	case errors.Is(err, service.ErrDriverUnavailable):
+		return errorResponse(c, http.StatusConflict, "Driver is not available", []string{err.Error()})
+	case errors.Is(err, service.ErrDriverOnTrip):
+		return errorResponse(c, http.StatusConflict, "Driver already has an active trip", nil)
+	case errors.Is(err, service.ErrNoPendingOffer):
+		return errorResponse(c, http.StatusConflict, "Trip has no pending offer", []string{err.Error()})
+	case errors.Is(err, service.ErrTripPreferencesLocked):
+		return errorResponse(c, http.StatusConflict, "Trip preferences can no longer change", []string{err.Error()})
+	case errors.Is(err, service.ErrTripNotAssigned):
+		return errorResponse(c, http.StatusConflict, "Trip has no assigned driver", []string{err.Error()})
+	case errors.Is(err, service.ErrNoDriversAvailable):
+		return errorResponse(c, http.StatusNotFound, "No drivers available", []string{err.Error()})
+	case errors.Is(err, service.ErrMatchingDisabled):
+		return errorResponse(c, http.StatusNotImplemented, "Automatic matching is not configured", nil)
+	case errors.Is(err, service.ErrValidationFailed):
+		return errorResponse(c, http.StatusBadRequest, "Validation failed", []string{err.Error()})
+	default:
+		return errorResponse(c, http.StatusInternalServerError, failure, []string{err.Error()})
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/validationstats"
+)
+
+type ValidationStatsHandler struct {
+	tracker *validationstats.Tracker
+}
+
+func NewValidationStatsHandler(tracker *validationstats.Tracker) *ValidationStatsHandler {
+	return &ValidationStatsHandler{
+		tracker: tracker,
+	}
+}
+
+func (h *ValidationStatsHandler) RegisterRoutes(app *fiber.App) {
+	admin := app.Group("/api/v1/admin")
+	admin.Get("/validation-failures", middleware.RequireRole(auth.RoleAdmin), h.GetValidationFailureReport)
+}
+
+// GetValidationFailureReport lists which endpoints reject requests and which
+// fields and rules fail most often since the service started.
+func (h *ValidationStatsHandler) GetValidationFailureReport(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"endpoints": h.tracker.Endpoints(),
+		"failures":  h.tracker.Report(),
+	})
+}
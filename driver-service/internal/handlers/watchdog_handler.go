@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/middleware"
+	"github.com/taxihub/driver-service/internal/watchdog"
+)
+
+type WatchdogHandler struct {
+	watchdog *watchdog.Watchdog
+}
+
+func NewWatchdogHandler(wd *watchdog.Watchdog) *WatchdogHandler {
+	return &WatchdogHandler{
+		watchdog: wd,
+	}
+}
+
+func (h *WatchdogHandler) RegisterRoutes(app *fiber.App) {
+	admin := app.Group("/api/v1/admin")
+	admin.Get("/watchdog", middleware.RequireRole(auth.RoleAdmin), h.GetWatchdogStatus)
+}
+
+func (h *WatchdogHandler) GetWatchdogStatus(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"workers": h.watchdog.Status(),
+	})
+}
@@ -0,0 +1,212 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Mapping describes a CSV export.
+type Mapping struct {
+	// Delimiter separates the cells; it defaults to a comma.
+	Delimiter string `json:"delimiter,omitempty"`
+	// Columns maps fields to the header of the column holding them. Fields
+	// not listed are read from a column named after the field.
+	Columns map[string]string `json:"columns,omitempty"`
+	// Transforms are applied in order to a field's cell; see TransformNames.
+	Transforms map[string][]string `json:"transforms,omitempty"`
+	// Defaults fill fields whose cell is missing or empty.
+	Defaults map[string]string `json:"defaults,omitempty"`
+}
+
+// merge returns m with the settings of override on top.
+func (m Mapping) merge(override Mapping) Mapping {
+	merged := Mapping{
+		Delimiter:  m.Delimiter,
+		Columns:    make(map[string]string),
+		Transforms: make(map[string][]string),
+		Defaults:   make(map[string]string),
+	}
+	if override.Delimiter != "" {
+		merged.Delimiter = override.Delimiter
+	}
+	for _, source := range []Mapping{m, override} {
+		for field, column := range source.Columns {
+			merged.Columns[field] = column
+		}
+		for field, transforms := range source.Transforms {
+			merged.Transforms[field] = transforms
+		}
+		for field, value := range source.Defaults {
+			merged.Defaults[field] = value
+		}
+	}
+	return merged
+}
+
+func (m Mapping) validate() error {
+	if m.Delimiter != "" && utf8.RuneCountInString(m.Delimiter) != 1 {
+		return errors.New("delimiter must be a single character")
+	}
+	known := make(map[string]bool, len(Fields))
+	for _, field := range Fields {
+		known[field] = true
+	}
+	for field := range m.Columns {
+		if !known[field] {
+			return fmt.Errorf("unknown field in columns: %s", field)
+		}
+	}
+	for field, names := range m.Transforms {
+		if !known[field] {
+			return fmt.Errorf("unknown field in transforms: %s", field)
+		}
+		for _, name := range names {
+			if _, ok := transforms[name]; !ok {
+				return fmt.Errorf("unknown transform for %s: %s", field, name)
+			}
+		}
+	}
+	for field := range m.Defaults {
+		if !known[field] {
+			return fmt.Errorf("unknown field in defaults: %s", field)
+		}
+	}
+	return nil
+}
+
+// durakTRMapping reads the Turkish-headed spreadsheets most durak software
+// exports, with decimal commas and semicolon separators.
+var durakTRMapping = Mapping{
+	Delimiter: ";",
+	Columns: map[string]string{
+		"first_name":      "Ad",
+		"last_name":       "Soyad",
+		"plate":           "Plaka",
+		"taxi_type":       "Taksi Tipi",
+		"fleet_id":        "Durak",
+		"car_brand":       "Marka",
+		"car_model":       "Model",
+		"car_color":       "Renk",
+		"lat":             "Enlem",
+		"lon":             "Boylam",
+		"email":           "E-posta",
+		"languages":       "Diller",
+		"license_classes": "Ehliyet Sınıfı",
+		"tckn":            "TC Kimlik No",
+		"tax_number":      "Vergi No",
+		"billing_address": "Fatura Adresi",
+	},
+	Transforms: map[string][]string{
+		"first_name":      {"title"},
+		"last_name":       {"title"},
+		"plate":           {"plate"},
+		"taxi_type":       {"taxi_type"},
+		"lat":             {"decimal_comma"},
+		"lon":             {"decimal_comma"},
+		"email":           {"lower"},
+		"languages":       {"lower"},
+		"license_classes": {"upper"},
+		"tckn":            {"digits"},
+		"tax_number":      {"digits"},
+	},
+}
+
+// CSVAdapter reads a CSV file with a header row.
+type CSVAdapter struct {
+	name    string
+	mapping Mapping
+}
+
+func (a *CSVAdapter) Name() string {
+	return a.name
+}
+
+// Read reads at most maxRows records, failing if the file has more. Blank
+// lines are skipped.
+func (a *CSVAdapter) Read(r io.Reader, maxRows int) ([]Row, error) {
+	reader := csv.NewReader(r)
+	if a.mapping.Delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(a.mapping.Delimiter)
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("file is empty")
+		}
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		columns[strings.ToLower(name)] = i
+	}
+
+	index := make(map[string]int)
+	for _, field := range Fields {
+		column := field
+		if mapped, ok := a.mapping.Columns[field]; ok {
+			column = mapped
+		}
+		if i, ok := columns[strings.ToLower(column)]; ok {
+			index[field] = i
+		}
+	}
+	if _, ok := index["plate"]; !ok {
+		return nil, errors.New("file has no plate column")
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlank(record) {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("file has more than %d rows", maxRows)
+		}
+
+		values := make(map[string]string, len(index))
+		for _, field := range Fields {
+			value := ""
+			if i, ok := index[field]; ok && i < len(record) {
+				value = strings.TrimSpace(record[i])
+			}
+			if value == "" {
+				value = a.mapping.Defaults[field]
+			}
+			if value == "" {
+				continue
+			}
+			for _, name := range a.mapping.Transforms[field] {
+				value = transforms[name](value)
+			}
+			values[field] = value
+		}
+		rows = append(rows, Row{Line: line, Values: values})
+	}
+
+	return rows, nil
+}
+
+func isBlank(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,152 @@
+// Package importer reads driver rosters exported from legacy durak (taxi
+// stand) management systems into driver registration requests.
+package importer
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+// Fields are the driver fields a row may fill, named as in
+// models.CreateDriverRequest.
+var Fields = []string{
+	"first_name", "last_name", "plate", "taxi_type", "fleet_id",
+	"car_brand", "car_model", "car_color", "lat", "lon", "email",
+	"languages", "license_classes", "tckn", "tax_number", "billing_address",
+}
+
+// Row is one record read from an import file, keyed by field.
+type Row struct {
+	// Line is the record's line in the file, counting the header as 1.
+	Line   int
+	Values map[string]string
+}
+
+// Adapter reads the records of one legacy export format.
+type Adapter interface {
+	Name() string
+	Read(r io.Reader, maxRows int) ([]Row, error)
+}
+
+// NewAdapter returns the named adapter. mapping customizes the generic "csv"
+// adapter and is layered over the presets' own.
+func NewAdapter(name string, mapping *Mapping) (Adapter, error) {
+	base := Mapping{}
+	switch name {
+	case "", "csv":
+		name = "csv"
+	case "durak_tr":
+		base = durakTRMapping
+	default:
+		return nil, fmt.Errorf("unknown import adapter: %s", name)
+	}
+	if mapping != nil {
+		base = base.merge(*mapping)
+	}
+	if err := base.validate(); err != nil {
+		return nil, err
+	}
+	return &CSVAdapter{name: name, mapping: base}, nil
+}
+
+// AdapterNames lists the adapters NewAdapter accepts.
+func AdapterNames() []string {
+	return []string{"csv", "durak_tr"}
+}
+
+// BuildRequest turns a row into a registration request. fleetID fills the
+// fleet of rows without one. It returns the cells that could not be parsed;
+// the request is not validated, as a row for a registered driver is applied
+// as an update and need not be a complete registration.
+func BuildRequest(row Row, fleetID string) (*models.CreateDriverRequest, []string) {
+	var issues []string
+	v := row.Values
+
+	req := &models.CreateDriverRequest{
+		FirstName:      v["first_name"],
+		LastName:       v["last_name"],
+		Plate:          v["plate"],
+		TaxiType:       v["taxi_type"],
+		FleetID:        v["fleet_id"],
+		CarBrand:       v["car_brand"],
+		CarModel:       v["car_model"],
+		CarColor:       v["car_color"],
+		Email:          v["email"],
+		Languages:      splitList(v["languages"]),
+		LicenseClasses: splitList(v["license_classes"]),
+		TCKN:           v["tckn"],
+		TaxNumber:      v["tax_number"],
+		BillingAddress: v["billing_address"],
+	}
+	if req.FleetID == "" {
+		req.FleetID = fleetID
+	}
+	for _, coordinate := range []struct {
+		field string
+		dest  *float64
+	}{
+		{"lat", &req.Lat},
+		{"lon", &req.Lon},
+	} {
+		raw := v[coordinate.field]
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			issues = append(issues, fmt.Sprintf("%s: %q is not a number", coordinate.field, raw))
+			continue
+		}
+		*coordinate.dest = value
+	}
+
+	return req, issues
+}
+
+// splitList splits a cell holding several values, separated by commas,
+// semicolons or pipes.
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || r == '|'
+	})
+	list := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
+
+// ValidationIssues describes the validation failures of request, naming
+// fields by their JSON names.
+func ValidationIssues(err error, request interface{}) []string {
+	validationErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return []string{err.Error()}
+	}
+
+	requestType := reflect.Indirect(reflect.ValueOf(request)).Type()
+	issues := make([]string, 0, len(validationErrs))
+	for _, e := range validationErrs {
+		field := e.Field()
+		if structField, ok := requestType.FieldByName(e.StructField()); ok {
+			field = strings.Split(structField.Tag.Get("json"), ",")[0]
+		}
+		issue := fmt.Sprintf("%s: failed %s", field, e.Tag())
+		if e.Param() != "" {
+			issue += " " + e.Param()
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// transforms normalize cells. Transforms cannot fail; a value they cannot
+// make sense of is passed on for validation to reject.
+var transforms = map[string]func(string) string{
+	"upper": func(value string) string {
+		return strings.ToUpperSpecial(unicode.TurkishCase, value)
+	},
+	"lower": func(value string) string {
+		return strings.ToLowerSpecial(unicode.TurkishCase, value)
+	},
+	// title capitalizes each word: "AYŞE NUR" becomes "Ayşe Nur".
+	"title": func(value string) string {
+		words := strings.Fields(value)
+		for i, word := range words {
+			runes := []rune(strings.ToLowerSpecial(unicode.TurkishCase, word))
+			runes[0] = unicode.TurkishCase.ToUpper(runes[0])
+			words[i] = string(runes)
+		}
+		return strings.Join(words, " ")
+	},
+	// plate upper-cases a plate and removes its spaces and dashes.
+	"plate": func(value string) string {
+		value = strings.ToUpperSpecial(unicode.TurkishCase, value)
+		return strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) || r == '-' {
+				return -1
+			}
+			return r
+		}, value)
+	},
+	// digits keeps only the digits, for identity numbers written with
+	// spaces or dashes.
+	"digits": func(value string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, value)
+	},
+	// decimal_comma reads "41,0082" as 41.0082.
+	"decimal_comma": func(value string) string {
+		return strings.Replace(value, ",", ".", 1)
+	},
+	// taxi_type maps the names taxis go by to the service's taxi types.
+	"taxi_type": func(value string) string {
+		key := strings.ToLowerSpecial(unicode.TurkishCase, value)
+		if taxiType, ok := taxiTypeNames[key]; ok {
+			return taxiType
+		}
+		return key
+	},
+}
+
+var taxiTypeNames = map[string]string{
+	"sarı":      "sari",
+	"sari":      "sari",
+	"yellow":    "sari",
+	"turkuaz":   "turkuaz",
+	"turquoise": "turkuaz",
+	"siyah":     "siyah",
+	"black":     "siyah",
+	"vip":       "siyah",
+}
+
+// TransformNames lists the transforms a mapping may use.
+func TransformNames() []string {
+	names := make([]string, 0, len(transforms))
+	for name := range transforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,18 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+func CheckDriverChurn(churnService service.ChurnService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		raised, err := churnService.CheckAlerts(ctx)
+		if raised > 0 {
+			logging.FromContext(ctx).Info("Raised driver churn alerts", "count", raised)
+		}
+		return err
+	}
+}
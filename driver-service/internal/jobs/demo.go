@@ -0,0 +1,16 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+// MoveDemoDrivers moves the drivers of the seeded demo scenario along their
+// routes. It runs often, so unlike other jobs it does not log its count.
+func MoveDemoDrivers(demoService service.DemoService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := demoService.Move(ctx)
+		return err
+	}
+}
@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+// BackfillDriverSchema migrates the driver documents that reads have not
+// brought to the current schema version yet.
+func BackfillDriverSchema(schemaService service.DriverSchemaService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		result, err := schemaService.Backfill(ctx)
+		if result != nil && (result.Migrated > 0 || result.Failed > 0) {
+			logging.FromContext(ctx).Info("Backfilled driver schema", "migrated", result.Migrated, "failed", result.Failed)
+		}
+		return err
+	}
+}
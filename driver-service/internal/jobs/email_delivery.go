@@ -0,0 +1,18 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+func DeliverEmails(emailService service.EmailService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		sent, err := emailService.DeliverDue(ctx)
+		if sent > 0 {
+			logging.FromContext(ctx).Info("Sent emails", "count", sent)
+		}
+		return err
+	}
+}
@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/watchdog"
+)
+
+// RunPeriodically calls fn every interval until ctx is cancelled. Errors are
+// logged and do not stop the schedule. heartbeat, when not nil, is called
+// after every run so a watchdog can tell the job is still alive.
+func RunPeriodically(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error, heartbeat func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Everything the job logs is tagged with its name
+	ctx = logging.With(ctx, "job", name)
+	logger := logging.FromContext(ctx)
+
+	logger.Info("Job scheduled", "interval", interval.String())
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Job stopped")
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				logger.Error("Job failed", "error", err)
+			}
+			if heartbeat != nil {
+				heartbeat()
+			}
+		}
+	}
+}
+
+// Periodic wraps RunPeriodically as a watchdog worker.
+func Periodic(name string, interval time.Duration, fn func(ctx context.Context) error) watchdog.Worker {
+	return func(ctx context.Context, heartbeat func()) {
+		RunPeriodically(ctx, name, interval, fn, heartbeat)
+	}
+}
+
+// ForEachTenant runs fn against the shared database and then each isolated
+// tenant database. Every run is attempted; the first error is returned.
+func ForEachTenant(tenantIDs []string, fn func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		firstErr := fn(ctx)
+		for _, tenantID := range tenantIDs {
+			tenantCtx := logging.With(config.WithTenant(ctx, tenantID), "tenant", tenantID)
+			if err := fn(tenantCtx); err != nil {
+				logging.FromContext(tenantCtx).Error("Tenant job failed", "error", err)
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+		return firstErr
+	}
+}
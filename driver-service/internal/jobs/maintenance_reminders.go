@@ -0,0 +1,18 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+func MaintenanceReminders(maintenanceService service.MaintenanceService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		sent, err := maintenanceService.SendDueReminders(ctx)
+		if sent > 0 {
+			logging.FromContext(ctx).Info("Sent maintenance reminders", "count", sent)
+		}
+		return err
+	}
+}
@@ -0,0 +1,18 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+func SendRatingPrompts(promptService service.RatingPromptService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		sent, err := promptService.SendDue(ctx)
+		if sent > 0 {
+			logging.FromContext(ctx).Info("Sent rating prompts", "count", sent)
+		}
+		return err
+	}
+}
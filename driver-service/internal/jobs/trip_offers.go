@@ -0,0 +1,18 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+func ExpireTripOffers(tripService service.TripService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		expired, err := tripService.ExpireOffers(ctx)
+		if expired > 0 {
+			logging.FromContext(ctx).Info("Expired trip offers", "count", expired)
+		}
+		return err
+	}
+}
@@ -0,0 +1,315 @@
+package live
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/tinylib/msgp/msgp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Subprotocols of /ws/drivers, negotiated through Sec-WebSocket-Protocol.
+// Clients that ask for none get JSON v1.
+const (
+	SubprotocolJSONV1    = "taxihub-live.v1.json"
+	SubprotocolMsgPackV2 = "taxihub-live.v2.msgpack"
+)
+
+// Codec encodes and decodes the frames of one protocol version.
+type Codec interface {
+	Subprotocol() string
+	// Binary reports whether frames are sent as binary rather than text
+	// WebSocket messages.
+	Binary() bool
+	Decode(data []byte) (models.LiveClientMessage, error)
+	EncodeLocation(msg models.LiveLocationMessage) ([]byte, error)
+	EncodeStatus(msg models.LiveStatusMessage) ([]byte, error)
+	EncodeOffer(msg models.LiveOfferMessage) ([]byte, error)
+	EncodeEarnings(msg models.LiveEarningsMessage) ([]byte, error)
+}
+
+var codecs = []Codec{MsgPackCodec{}, JSONCodec{}}
+
+// Subprotocols lists the supported subprotocols, most preferred first.
+func Subprotocols() []string {
+	names := make([]string, len(codecs))
+	for i, codec := range codecs {
+		names[i] = codec.Subprotocol()
+	}
+	return names
+}
+
+// CodecFor returns the codec of a negotiated subprotocol; JSON v1 when there
+// is none.
+func CodecFor(subprotocol string) Codec {
+	for _, codec := range codecs {
+		if codec.Subprotocol() == subprotocol {
+			return codec
+		}
+	}
+	return JSONCodec{}
+}
+
+// JSONCodec is protocol v1: the JSON frames documented in the README.
+type JSONCodec struct{}
+
+func (JSONCodec) Subprotocol() string { return SubprotocolJSONV1 }
+func (JSONCodec) Binary() bool        { return false }
+
+func (JSONCodec) Decode(data []byte) (models.LiveClientMessage, error) {
+	var msg models.LiveClientMessage
+	err := json.Unmarshal(data, &msg)
+	return msg, err
+}
+
+func (JSONCodec) EncodeLocation(msg models.LiveLocationMessage) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (JSONCodec) EncodeStatus(msg models.LiveStatusMessage) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (JSONCodec) EncodeOffer(msg models.LiveOfferMessage) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (JSONCodec) EncodeEarnings(msg models.LiveEarningsMessage) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+// MsgPackCodec is protocol v2: MessagePack maps with short keys. Driver IDs
+// travel as their 12 raw bytes, times as Unix milliseconds, and pushed
+// coordinates as float32, which keeps well under a meter of precision.
+//
+//	location (server): {"t": "location", "d": bin, "f": fleet, "la": lat, "lo": lon, "ts": ms}
+//	status (server):   {"t": "subscribed", "b": bbox}, {"t": "error", "m": message} or {"t": "session", "s": id, "b": bbox}
+//	offer (server):    {"t": "offer", "d": bin, "f": fleet, "tr": trip, "la": lat, "lo": lon, "km": distance, "ts": ms, "ex": ms}
+//	earnings (server): {"t": "earnings", "d": bin, "k": kind, "a": amount, "tr": trip, "n": note, "ts": ms, "tot": totals}
+//	client:            {"t": type, "b": bbox, "d": bin or hex string, "la": lat, "lo": lon}
+//
+// A bbox is the array [min_lat, min_lon, max_lat, max_lon]. "f" is omitted
+// without a fleet, and "tr" and "ex" for offers outside a trip; clients may
+// send any numeric type.//
+// Earnings totals are the map {"dy": day, "g": gross, "c": commission,
+// "bo": bonuses, "ne": net, "r": rides, "cu": currency}. "tr" and "n" are
+// omitted from earnings without a trip or a note.
+type MsgPackCodec struct{}
+
+func (MsgPackCodec) Subprotocol() string { return SubprotocolMsgPackV2 }
+func (MsgPackCodec) Binary() bool        { return true }
+
+func (MsgPackCodec) EncodeLocation(msg models.LiveLocationMessage) ([]byte, error) {
+	driverID, err := primitive.ObjectIDFromHex(msg.DriverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver ID %q: %w", msg.DriverID, err)
+	}
+
+	fields := uint32(5)
+	if msg.FleetID != "" {
+		fields++
+	}
+	b := make([]byte, 0, 64)
+	b = msgp.AppendMapHeader(b, fields)
+	b = msgp.AppendString(msgp.AppendString(b, "t"), msg.Type)
+	b = msgp.AppendBytes(msgp.AppendString(b, "d"), driverID[:])
+	if msg.FleetID != "" {
+		b = msgp.AppendString(msgp.AppendString(b, "f"), msg.FleetID)
+	}
+	b = msgp.AppendFloat32(msgp.AppendString(b, "la"), float32(msg.Location.Lat))
+	b = msgp.AppendFloat32(msgp.AppendString(b, "lo"), float32(msg.Location.Lon))
+	b = msgp.AppendInt64(msgp.AppendString(b, "ts"), msg.RecordedAt.UnixMilli())
+	return b, nil
+}
+
+func (MsgPackCodec) EncodeStatus(msg models.LiveStatusMessage) ([]byte, error) {
+	fields := uint32(1)
+	if msg.Message != "" {
+		fields++
+	}
+	if msg.BBox != nil {
+		fields++
+	}
+	if msg.Session != "" {
+		fields++
+	}
+	b := msgp.AppendMapHeader(nil, fields)
+	b = msgp.AppendString(msgp.AppendString(b, "t"), msg.Type)
+	if msg.Message != "" {
+		b = msgp.AppendString(msgp.AppendString(b, "m"), msg.Message)
+	}
+	if msg.Session != "" {
+		b = msgp.AppendString(msgp.AppendString(b, "s"), msg.Session)
+	}
+	if msg.BBox != nil {
+		b = msgp.AppendArrayHeader(msgp.AppendString(b, "b"), 4)
+		for _, v := range []float64{msg.BBox.MinLat, msg.BBox.MinLon, msg.BBox.MaxLat, msg.BBox.MaxLon} {
+			b = msgp.AppendFloat64(b, v)
+		}
+	}
+	return b, nil
+}
+
+// EncodeOffer sends the pickup at full precision, as the driver drives to
+// it.
+func (MsgPackCodec) EncodeOffer(msg models.LiveOfferMessage) ([]byte, error) {
+	driverID, err := primitive.ObjectIDFromHex(msg.DriverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver ID %q: %w", msg.DriverID, err)
+	}
+
+	fields := uint32(6)
+	if msg.FleetID != "" {
+		fields++
+	}
+	if msg.TripID != "" {
+		fields++
+	}
+	if msg.ExpiresAt != nil {
+		fields++
+	}
+	b := make([]byte, 0, 120)
+	b = msgp.AppendMapHeader(b, fields)
+	b = msgp.AppendString(msgp.AppendString(b, "t"), msg.Type)
+	b = msgp.AppendBytes(msgp.AppendString(b, "d"), driverID[:])
+	if msg.FleetID != "" {
+		b = msgp.AppendString(msgp.AppendString(b, "f"), msg.FleetID)
+	}
+	if msg.TripID != "" {
+		b = msgp.AppendString(msgp.AppendString(b, "tr"), msg.TripID)
+	}
+	b = msgp.AppendFloat64(msgp.AppendString(b, "la"), msg.Pickup.Lat)
+	b = msgp.AppendFloat64(msgp.AppendString(b, "lo"), msg.Pickup.Lon)
+	b = msgp.AppendFloat64(msgp.AppendString(b, "km"), msg.DistanceKm)
+	b = msgp.AppendInt64(msgp.AppendString(b, "ts"), msg.OfferedAt.UnixMilli())
+	if msg.ExpiresAt != nil {
+		b = msgp.AppendInt64(msgp.AppendString(b, "ex"), msg.ExpiresAt.UnixMilli())
+	}
+	return b, nil
+}
+
+// EncodeEarnings sends amounts as float64, as money needs the cents.
+func (MsgPackCodec) EncodeEarnings(msg models.LiveEarningsMessage) ([]byte, error) {
+	driverID, err := primitive.ObjectIDFromHex(msg.DriverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver ID %q: %w", msg.DriverID, err)
+	}
+
+	fields := uint32(6)
+	if msg.TripID != "" {
+		fields++
+	}
+	if msg.Note != "" {
+		fields++
+	}
+	b := make([]byte, 0, 160)
+	b = msgp.AppendMapHeader(b, fields)
+	b = msgp.AppendString(msgp.AppendString(b, "t"), msg.Type)
+	b = msgp.AppendBytes(msgp.AppendString(b, "d"), driverID[:])
+	b = msgp.AppendString(msgp.AppendString(b, "k"), msg.Kind)
+	b = msgp.AppendFloat64(msgp.AppendString(b, "a"), msg.Amount)
+	if msg.TripID != "" {
+		b = msgp.AppendString(msgp.AppendString(b, "tr"), msg.TripID)
+	}
+	if msg.Note != "" {
+		b = msgp.AppendString(msgp.AppendString(b, "n"), msg.Note)
+	}
+	b = msgp.AppendInt64(msgp.AppendString(b, "ts"), msg.OccurredAt.UnixMilli())
+
+	totals := msg.Totals
+	b = msgp.AppendMapHeader(msgp.AppendString(b, "tot"), 7)
+	b = msgp.AppendString(msgp.AppendString(b, "dy"), totals.Day)
+	b = msgp.AppendFloat64(msgp.AppendString(b, "g"), totals.Gross)
+	b = msgp.AppendFloat64(msgp.AppendString(b, "c"), totals.Commission)
+	b = msgp.AppendFloat64(msgp.AppendString(b, "bo"), totals.Bonuses)
+	b = msgp.AppendFloat64(msgp.AppendString(b, "ne"), totals.Net)
+	b = msgp.AppendInt(msgp.AppendString(b, "r"), totals.Rides)
+	b = msgp.AppendString(msgp.AppendString(b, "cu"), totals.Currency)
+	return b, nil
+}
+
+func (MsgPackCodec) Decode(data []byte) (models.LiveClientMessage, error) {
+	var msg models.LiveClientMessage
+	fields, b, err := msgp.ReadMapHeaderBytes(data)
+	if err != nil {
+		return msg, err
+	}
+
+	for ; fields > 0; fields-- {
+		var key []byte
+		if key, b, err = msgp.ReadMapKeyZC(b); err != nil {
+			return msg, err
+		}
+		switch string(key) {
+		case "t":
+			msg.Type, b, err = msgp.ReadStringBytes(b)
+		case "b":
+			msg.BBox, b, err = readBoundingBox(b)
+		case "d":
+			msg.DriverID, b, err = readDriverID(b)
+		case "la":
+			msg.Lat, b, err = readNumber(b)
+		case "lo":
+			msg.Lon, b, err = readNumber(b)
+		default:
+			b, err = msgp.Skip(b)
+		}
+		if err != nil {
+			return msg, fmt.Errorf("field %q: %w", key, err)
+		}
+	}
+	return msg, nil
+}
+
+func readBoundingBox(b []byte) (*models.BoundingBox, []byte, error) {
+	size, b, err := msgp.ReadArrayHeaderBytes(b)
+	if err != nil {
+		return nil, b, err
+	}
+	if size != 4 {
+		return nil, b, errors.New("bbox must be [min_lat, min_lon, max_lat, max_lon]")
+	}
+
+	var corners [4]float64
+	for i := range corners {
+		if corners[i], b, err = readNumber(b); err != nil {
+			return nil, b, err
+		}
+	}
+	return &models.BoundingBox{MinLat: corners[0], MinLon: corners[1], MaxLat: corners[2], MaxLon: corners[3]}, b, nil
+}
+
+// readDriverID accepts the 12 raw ID bytes or the hex string.
+func readDriverID(b []byte) (string, []byte, error) {
+	if msgp.NextType(b) == msgp.StrType {
+		return msgp.ReadStringBytes(b)
+	}
+
+	raw, b, err := msgp.ReadBytesZC(b)
+	if err != nil {
+		return "", b, err
+	}
+	if len(raw) != len(primitive.ObjectID{}) {
+		return "", b, fmt.Errorf("driver ID must be %d bytes", len(primitive.ObjectID{}))
+	}
+	var id primitive.ObjectID
+	copy(id[:], raw)
+	return id.Hex(), b, nil
+}
+
+// readNumber reads any MessagePack number, as encoders differ in how they
+// write whole numbers.
+func readNumber(b []byte) (float64, []byte, error) {
+	switch msgp.NextType(b) {
+	case msgp.IntType:
+		v, o, err := msgp.ReadInt64Bytes(b)
+		return float64(v), o, err
+	case msgp.UintType:
+		v, o, err := msgp.ReadUint64Bytes(b)
+		return float64(v), o, err
+	default:
+		return msgp.ReadFloat64Bytes(b)
+	}
+}
@@ -0,0 +1,310 @@
+// Package live fans accepted driver location updates out to subscribers
+// watching an area of the map, such as dispatcher screens on /ws/drivers,
+// and pushes ride offers and earnings updates to the connections of
+// individual drivers.
+package live
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+// offerBuffer is how many offers a connection may have waiting.
+const offerBuffer = 4
+
+// earningsBuffer is how many earnings updates a connection may have
+// waiting. A ride sends two at once.
+const earningsBuffer = 8
+
+// Subscription is one /ws/drivers connection. It receives the updates of
+// its tenant that fall inside its area, and nothing until an area is set.
+// Once bound to a driver it also receives the offers and earnings updates
+// for that driver.
+type Subscription struct {
+	tenantID string
+	updates  chan models.LocationSample
+	offers   chan models.LiveOfferMessage
+	earnings chan models.LiveEarningsMessage
+	dropped  atomic.Int64
+
+	mu       sync.RWMutex
+	area     *models.BoundingBox
+	driverID string
+}
+
+// Updates is closed when the subscription is removed from the hub.
+func (s *Subscription) Updates() <-chan models.LocationSample {
+	return s.updates
+}
+
+// Offers is never closed; stop reading it once Updates is closed.
+func (s *Subscription) Offers() <-chan models.LiveOfferMessage {
+	return s.offers
+}
+
+// Earnings is never closed; stop reading it once Updates is closed.
+func (s *Subscription) Earnings() <-chan models.LiveEarningsMessage {
+	return s.earnings
+}
+
+// SetArea replaces the watched area; nil pauses the subscription.
+func (s *Subscription) SetArea(area *models.BoundingBox) {
+	s.mu.Lock()
+	s.area = area
+	s.mu.Unlock()
+}
+
+// Dropped counts updates skipped because the subscriber fell behind.
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+func (s *Subscription) wants(sample models.LocationSample) bool {
+	if sample.TenantID != s.tenantID {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.area != nil && s.area.Contains(sample.Location)
+}
+
+func (s *Subscription) boundDriver() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.driverID
+}
+
+// Backplane connects the hubs of several replicas: location updates are
+// shared between them, and offers and earnings updates are routed to the
+// replica holding the driver's connection. Its methods must not block.
+type Backplane interface {
+	PublishLocation(sample models.LocationSample)
+	// SetPresence records that this replica holds the driver's connection.
+	SetPresence(tenantID, driverID string)
+	ClearPresence(tenantID, driverID string)
+	// RouteOffer hands an offer to the replica holding the driver's
+	// connection and reports whether there was one.
+	RouteOffer(ctx context.Context, tenantID, driverID string, offer models.LiveOfferMessage) (bool, error)
+	RouteEarnings(ctx context.Context, tenantID, driverID string, msg models.LiveEarningsMessage) (bool, error)
+}
+
+type driverKey struct {
+	tenantID string
+	driverID string
+}
+
+// Hub implements service.LocationObserver. Observe never blocks: a
+// subscriber whose buffer is full misses the update, and its next one
+// supersedes it anyway.
+type Hub struct {
+	bufferSize int
+	backplane  Backplane
+
+	mu      sync.RWMutex
+	subs    map[*Subscription]struct{}
+	drivers map[driverKey]*Subscription
+}
+
+func NewHub(bufferSize int) *Hub {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	return &Hub{
+		bufferSize: bufferSize,
+		subs:       make(map[*Subscription]struct{}),
+		drivers:    make(map[driverKey]*Subscription),
+	}
+}
+
+// useBackplane must be called before the hub is used.
+func (h *Hub) useBackplane(backplane Backplane) {
+	h.backplane = backplane
+}
+
+func (h *Hub) Subscribe(tenantID string) *Subscription {
+	sub := &Subscription{
+		tenantID: tenantID,
+		updates:  make(chan models.LocationSample, h.bufferSize),
+		offers:   make(chan models.LiveOfferMessage, offerBuffer),
+		earnings: make(chan models.LiveEarningsMessage, earningsBuffer),
+	}
+
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	_, ok := h.subs[sub]
+	if ok {
+		delete(h.subs, sub)
+		close(sub.updates)
+	}
+	unbound := h.unbind(sub)
+	h.mu.Unlock()
+
+	if unbound != "" && h.backplane != nil {
+		h.backplane.ClearPresence(sub.tenantID, unbound)
+	}
+}
+
+// Bind makes sub the connection of a driver, replacing any earlier one, so
+// offers for the driver reach it. Callers must have checked that the
+// connection belongs to the driver.
+func (h *Hub) Bind(sub *Subscription, driverID string) {
+	if sub.boundDriver() == driverID {
+		return
+	}
+
+	h.mu.Lock()
+	if _, ok := h.subs[sub]; !ok {
+		h.mu.Unlock()
+		return
+	}
+	h.unbind(sub)
+	sub.mu.Lock()
+	sub.driverID = driverID
+	sub.mu.Unlock()
+	h.drivers[driverKey{tenantID: sub.tenantID, driverID: driverID}] = sub
+	h.mu.Unlock()
+
+	if h.backplane != nil {
+		h.backplane.SetPresence(sub.tenantID, driverID)
+	}
+}
+
+// unbind drops sub's driver binding and returns the driver if sub was still
+// its connection. Callers must hold h.mu.
+func (h *Hub) unbind(sub *Subscription) string {
+	driverID := sub.boundDriver()
+	if driverID == "" {
+		return ""
+	}
+	key := driverKey{tenantID: sub.tenantID, driverID: driverID}
+	if h.drivers[key] != sub {
+		return ""
+	}
+	delete(h.drivers, key)
+	return driverID
+}
+
+// boundDrivers lists the drivers whose connection this hub holds.
+func (h *Hub) boundDrivers() []driverKey {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	keys := make([]driverKey, 0, len(h.drivers))
+	for key := range h.drivers {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (h *Hub) Observe(sample models.LocationSample) {
+	h.deliver(sample)
+	if h.backplane != nil {
+		h.backplane.PublishLocation(sample)
+	}
+}
+
+func (h *Hub) deliver(sample models.LocationSample) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subs {
+		if !sub.wants(sample) {
+			continue
+		}
+		select {
+		case sub.updates <- sample:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// SendOffer implements service.OfferSender. The offer goes to the driver's
+// connection on this replica, or through the backplane to the replica that
+// holds it. Delivery is best effort: an offer for a driver without a
+// connection, or whose connection is behind on offers, is dropped.
+func (h *Hub) SendOffer(ctx context.Context, driverID string, offer models.LiveOfferMessage) bool {
+	tenantID := config.TenantFromContext(ctx)
+	if h.deliverOffer(tenantID, driverID, offer) {
+		return true
+	}
+	if h.backplane == nil {
+		return false
+	}
+
+	routed, err := h.backplane.RouteOffer(ctx, tenantID, driverID, offer)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to route offer", "driver_id", driverID, "error", err)
+	}
+	return routed
+}
+
+func (h *Hub) deliverOffer(tenantID, driverID string, offer models.LiveOfferMessage) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	sub, ok := h.drivers[driverKey{tenantID: tenantID, driverID: driverID}]
+	if !ok {
+		return false
+	}
+	select {
+	case sub.offers <- offer:
+		return true
+	default:
+		return false
+	}
+}
+
+// SendEarnings implements service.EarningsSender, delivering like SendOffer.
+// A driver without a connection sees the update in their totals when they
+// next fetch them.
+func (h *Hub) SendEarnings(ctx context.Context, driverID string, update models.EarningsUpdate) bool {
+	msg := models.NewLiveEarningsMessage(update)
+	tenantID := config.TenantFromContext(ctx)
+	if h.deliverEarnings(tenantID, driverID, msg) {
+		return true
+	}
+	if h.backplane == nil {
+		return false
+	}
+
+	routed, err := h.backplane.RouteEarnings(ctx, tenantID, driverID, msg)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to route earnings update", "driver_id", driverID, "error", err)
+	}
+	return routed
+}
+
+func (h *Hub) deliverEarnings(tenantID, driverID string, msg models.LiveEarningsMessage) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	sub, ok := h.drivers[driverKey{tenantID: tenantID, driverID: driverID}]
+	if !ok {
+		return false
+	}
+	select {
+	case sub.earnings <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// Subscribers returns the number of open subscriptions.
+func (h *Hub) Subscribers() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subs)
+}
@@ -0,0 +1,319 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+const (
+	redisLocationsChannel = "taxihub:live:locations"
+	redisReplicaChannel   = "taxihub:live:replica:"
+	redisEarningsChannel  = "taxihub:live:earnings:"
+	redisPresenceKey      = "taxihub:live:presence:"
+	redisSessionKey       = "taxihub:live:session:"
+
+	// redisPublishBuffer is how many location updates may wait to be
+	// published before further ones are dropped.
+	redisPublishBuffer  = 1024
+	redisPresenceBuffer = 256
+	redisCommandTimeout = 2 * time.Second
+	redisBeatInterval   = 10 * time.Second
+)
+
+// clearPresenceScript deletes a presence key only if it still names this
+// replica, so a driver who reconnected elsewhere keeps their new presence.
+var clearPresenceScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+type redisLocation struct {
+	Origin string                `json:"origin"`
+	Sample models.LocationSample `json:"sample"`
+}
+
+type presenceChange struct {
+	key   driverKey
+	clear bool
+}
+
+type redisOffer struct {
+	TenantID string                  `json:"tenant_id"`
+	DriverID string                  `json:"driver_id"`
+	Offer    models.LiveOfferMessage `json:"offer"`
+}
+
+type redisEarnings struct {
+	TenantID string                     `json:"tenant_id"`
+	DriverID string                     `json:"driver_id"`
+	Earnings models.LiveEarningsMessage `json:"earnings"`
+}
+
+// RedisBackplane shares a hub with the other replicas through Redis. Each
+// replica publishes its location updates on one channel and delivers the
+// others' to its own subscribers. Presence keys name the replica holding
+// each driver's connection, and offers and earnings updates are published
+// on that replica's channels. Sessions are stored in Redis so a client may
+// resume on any replica.
+type RedisBackplane struct {
+	client      redis.UniversalClient
+	hub         *Hub
+	replicaID   string
+	presenceTTL time.Duration
+	sessionTTL  time.Duration
+
+	outgoing chan models.LocationSample
+	presence chan presenceChange
+	dropped  atomic.Int64
+}
+
+// NewRedisBackplane attaches a backplane to hub; Run must be running for
+// updates to flow. Presence keys expire after presenceTTL unless refreshed,
+// so a crashed replica's drivers are released.
+func NewRedisBackplane(client redis.UniversalClient, hub *Hub, presenceTTL, sessionTTL time.Duration) *RedisBackplane {
+	b := &RedisBackplane{
+		client:      client,
+		hub:         hub,
+		replicaID:   newReplicaID(),
+		presenceTTL: presenceTTL,
+		sessionTTL:  sessionTTL,
+		outgoing:    make(chan models.LocationSample, redisPublishBuffer),
+		presence:    make(chan presenceChange, redisPresenceBuffer),
+	}
+	hub.useBackplane(b)
+	return b
+}
+
+// newReplicaID names this process: its host name, which is the pod name on
+// Kubernetes, and a random suffix for restarts.
+func newReplicaID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "replica"
+	}
+	suffix, err := NewSessionID()
+	if err != nil {
+		return host
+	}
+	return host + "-" + suffix[:8]
+}
+
+func (b *RedisBackplane) ReplicaID() string {
+	return b.replicaID
+}
+
+// Run publishes this replica's updates and delivers the other replicas'
+// updates, offers and earnings updates until ctx is cancelled.
+func (b *RedisBackplane) Run(ctx context.Context, heartbeat func()) {
+	pubsub := b.client.Subscribe(ctx, redisLocationsChannel, redisReplicaChannel+b.replicaID, redisEarningsChannel+b.replicaID)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		logging.FromContext(ctx).Error("Live backplane failed to subscribe", "error", err)
+		return
+	}
+	messages := pubsub.Channel()
+
+	ticker := time.NewTicker(redisBeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sample := <-b.outgoing:
+			b.publish(ctx, sample)
+		case change := <-b.presence:
+			b.applyPresence(ctx, change)
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			b.handle(msg)
+		case <-ticker.C:
+			b.refreshPresence(ctx)
+			if dropped := b.dropped.Swap(0); dropped > 0 {
+				logging.FromContext(ctx).Warn("Live backplane dropped location updates (publish queue full)", "count", dropped)
+			}
+			if heartbeat != nil {
+				heartbeat()
+			}
+		}
+	}
+}
+
+func (b *RedisBackplane) PublishLocation(sample models.LocationSample) {
+	select {
+	case b.outgoing <- sample:
+	default:
+		b.dropped.Add(1)
+	}
+}
+
+func (b *RedisBackplane) publish(ctx context.Context, sample models.LocationSample) {
+	payload, err := json.Marshal(redisLocation{Origin: b.replicaID, Sample: sample})
+	if err != nil {
+		return
+	}
+	publishCtx, cancel := context.WithTimeout(ctx, redisCommandTimeout)
+	defer cancel()
+	if err := b.client.Publish(publishCtx, redisLocationsChannel, payload).Err(); err != nil {
+		b.dropped.Add(1)
+	}
+}
+
+func (b *RedisBackplane) handle(msg *redis.Message) {
+	if msg.Channel == redisLocationsChannel {
+		var location redisLocation
+		if err := json.Unmarshal([]byte(msg.Payload), &location); err != nil || location.Origin == b.replicaID {
+			return
+		}
+		b.hub.deliver(location.Sample)
+		return
+	}
+	if msg.Channel == redisEarningsChannel+b.replicaID {
+		var earnings redisEarnings
+		if err := json.Unmarshal([]byte(msg.Payload), &earnings); err != nil {
+			return
+		}
+		if !b.hub.deliverEarnings(earnings.TenantID, earnings.DriverID, earnings.Earnings) {
+			slog.Warn("Dropped earnings update: driver has no connection on this replica", "driver_id", earnings.DriverID, "tenant", earnings.TenantID)
+		}
+		return
+	}
+
+	var offer redisOffer
+	if err := json.Unmarshal([]byte(msg.Payload), &offer); err != nil {
+		return
+	}
+	if !b.hub.deliverOffer(offer.TenantID, offer.DriverID, offer.Offer) {
+		slog.Warn("Dropped offer: driver has no connection on this replica", "driver_id", offer.DriverID, "tenant", offer.TenantID)
+	}
+}
+
+// SetPresence and ClearPresence are applied in order by Run, so a driver
+// reconnecting to this replica is not cleared by their old connection. A
+// change dropped because the queue is full is repaired by the next refresh,
+// or by the key expiring.
+func (b *RedisBackplane) SetPresence(tenantID, driverID string) {
+	select {
+	case b.presence <- presenceChange{key: driverKey{tenantID: tenantID, driverID: driverID}}:
+	default:
+	}
+}
+
+func (b *RedisBackplane) ClearPresence(tenantID, driverID string) {
+	select {
+	case b.presence <- presenceChange{key: driverKey{tenantID: tenantID, driverID: driverID}, clear: true}:
+	default:
+	}
+}
+
+func (b *RedisBackplane) applyPresence(ctx context.Context, change presenceChange) {
+	presenceCtx, cancel := context.WithTimeout(ctx, redisCommandTimeout)
+	defer cancel()
+
+	key := presenceKey(change.key.tenantID, change.key.driverID)
+	var err error
+	if change.clear {
+		err = clearPresenceScript.Run(presenceCtx, b.client, []string{key}, b.replicaID).Err()
+	} else {
+		err = b.client.Set(presenceCtx, key, b.replicaID, b.presenceTTL).Err()
+	}
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to update live presence", "driver_id", change.key.driverID, "tenant", change.key.tenantID, "error", err)
+	}
+}
+
+// refreshPresence extends the presence of every driver connected here.
+func (b *RedisBackplane) refreshPresence(ctx context.Context) {
+	drivers := b.hub.boundDrivers()
+	if len(drivers) == 0 {
+		return
+	}
+
+	refreshCtx, cancel := context.WithTimeout(ctx, redisCommandTimeout)
+	defer cancel()
+	pipe := b.client.Pipeline()
+	for _, key := range drivers {
+		pipe.Set(refreshCtx, presenceKey(key.tenantID, key.driverID), b.replicaID, b.presenceTTL)
+	}
+	if _, err := pipe.Exec(refreshCtx); err != nil {
+		logging.FromContext(ctx).Warn("Failed to refresh live presence", "count", len(drivers), "error", err)
+	}
+}
+
+func (b *RedisBackplane) RouteOffer(ctx context.Context, tenantID, driverID string, offer models.LiveOfferMessage) (bool, error) {
+	return b.route(ctx, tenantID, driverID, redisReplicaChannel, redisOffer{TenantID: tenantID, DriverID: driverID, Offer: offer})
+}
+
+func (b *RedisBackplane) RouteEarnings(ctx context.Context, tenantID, driverID string, msg models.LiveEarningsMessage) (bool, error) {
+	return b.route(ctx, tenantID, driverID, redisEarningsChannel, redisEarnings{TenantID: tenantID, DriverID: driverID, Earnings: msg})
+}
+
+// route publishes message on the channel, named by prefix, of the replica
+// holding the driver's connection.
+func (b *RedisBackplane) route(ctx context.Context, tenantID, driverID, prefix string, message any) (bool, error) {
+	replicaID, err := b.client.Get(ctx, presenceKey(tenantID, driverID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		return false, err
+	}
+	// A presence naming this replica is stale: the hub had no connection
+	if replicaID == b.replicaID {
+		return false, nil
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return false, err
+	}
+	receivers, err := b.client.Publish(ctx, prefix+replicaID, payload).Result()
+	if err != nil {
+		return false, err
+	}
+	return receivers > 0, nil
+}
+
+func (b *RedisBackplane) Save(ctx context.Context, id string, session Session) error {
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	if err := b.client.Set(ctx, redisSessionKey+id, payload, b.sessionTTL).Err(); err != nil {
+		return fmt.Errorf("failed to save live session: %w", err)
+	}
+	return nil
+}
+
+func (b *RedisBackplane) Load(ctx context.Context, id string) (*Session, error) {
+	payload, err := b.client.Get(ctx, redisSessionKey+id).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to load live session: %w", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal(payload, &session); err != nil {
+		return nil, fmt.Errorf("failed to decode live session: %w", err)
+	}
+	return &session, nil
+}
+
+func presenceKey(tenantID, driverID string) string {
+	return redisPresenceKey + tenantID + ":" + driverID
+}
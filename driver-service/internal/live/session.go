@@ -0,0 +1,88 @@
+package live
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+var ErrSessionNotFound = errors.New("live session not found")
+
+// Session is the state a /ws/drivers connection restores when a client
+// reconnects with its session ID, possibly to another replica.
+type Session struct {
+	TenantID string              `json:"tenant_id"`
+	DriverID string              `json:"driver_id,omitempty"`
+	BBox     *models.BoundingBox `json:"bbox,omitempty"`
+}
+
+// SessionStore keeps sessions for a while after their connection ends.
+type SessionStore interface {
+	Save(ctx context.Context, id string, session Session) error
+	// Load fails with ErrSessionNotFound for unknown and expired sessions.
+	Load(ctx context.Context, id string) (*Session, error)
+}
+
+func NewSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// MemorySessionStore keeps sessions in the process, for single replica
+// deployments.
+type MemorySessionStore struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	sessions  map[string]memorySession
+	lastPrune time.Time
+}
+
+type memorySession struct {
+	session   Session
+	expiresAt time.Time
+}
+
+func NewMemorySessionStore(ttl time.Duration) *MemorySessionStore {
+	return &MemorySessionStore{
+		ttl:      ttl,
+		sessions: make(map[string]memorySession),
+	}
+}
+
+func (s *MemorySessionStore) Save(ctx context.Context, id string, session Session) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastPrune) > time.Minute {
+		for id, stored := range s.sessions {
+			if now.After(stored.expiresAt) {
+				delete(s.sessions, id)
+			}
+		}
+		s.lastPrune = now
+	}
+	s.sessions[id] = memorySession{session: session, expiresAt: now.Add(s.ttl)}
+	return nil
+}
+
+func (s *MemorySessionStore) Load(ctx context.Context, id string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.sessions[id]
+	if !ok || time.Now().After(stored.expiresAt) {
+		return nil, ErrSessionNotFound
+	}
+	session := stored.session
+	return &session, nil
+}
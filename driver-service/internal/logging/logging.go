@@ -0,0 +1,68 @@
+// Package logging sets up the service's structured logger and carries a
+// logger per request, so handlers, services and repositories log with the
+// request's ID, driver and tenant without passing them around.
+//
+// It is built on log/slog rather than zerolog or zap: slog is in the
+// standard library, so logging adds no dependency.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Formats of the log output.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+type loggerContextKey struct{}
+
+// LoggerKey is the fiber local (and request context value) holding the
+// request's logger.
+var LoggerKey = loggerContextKey{}
+
+// New creates a logger writing records at level ("debug", "info", "warn" or
+// "error") and above to w, as JSON lines or as key=value text.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var minLevel slog.Level
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("unknown log level %q; use debug, info, warn or error", level)
+	}
+
+	options := &slog.HandlerOptions{Level: minLevel}
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q; use json or text", format)
+	}
+}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, LoggerKey, logger)
+}
+
+// FromContext returns the logger of the request or job in ctx, or the
+// default logger if it has none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(LoggerKey).(*slog.Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// With returns a copy of ctx whose logger adds the given attributes, as
+// key-value pairs, to everything it logs.
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
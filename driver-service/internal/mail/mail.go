@@ -0,0 +1,83 @@
+// Package mail renders the service's transactional emails from localized
+// templates and hands them to an email provider.
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Message is a rendered email ready to send.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Provider delivers messages. A failed Send is retried later unless the
+// error is a PermanentError.
+type Provider interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// PermanentError is a rejection that would happen again on retry, such as an
+// invalid recipient or bad credentials.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+// Sender is who emails come from.
+type Sender struct {
+	Address string
+	Name    string
+}
+
+type ProviderConfig struct {
+	From Sender
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	SendGridAPIKey string
+	// SendGridURL overrides the SendGrid API endpoint, for tests and proxies.
+	SendGridURL string
+}
+
+// NewProvider returns the named provider, or nil for "none", in which case
+// emails are not sent.
+func NewProvider(name string, cfg ProviderConfig) (Provider, error) {
+	switch name {
+	case "", "none":
+		return nil, nil
+	case "smtp":
+		if cfg.SMTPHost == "" || cfg.From.Address == "" {
+			return nil, errors.New("smtp mail requires MAIL_SMTP_HOST and MAIL_FROM")
+		}
+		return NewSMTPProvider(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From), nil
+	case "sendgrid":
+		if cfg.SendGridAPIKey == "" || cfg.From.Address == "" {
+			return nil, errors.New("sendgrid mail requires MAIL_SENDGRID_API_KEY and MAIL_FROM")
+		}
+		return NewSendGridProvider(cfg.SendGridAPIKey, cfg.SendGridURL, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unknown mail provider: %s", name)
+	}
+}
@@ -0,0 +1,120 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+// SMTPProvider sends through an SMTP relay, authenticating with PLAIN when a
+// username is set. net/smtp upgrades to TLS when the server offers STARTTLS.
+type SMTPProvider struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from Sender
+}
+
+func NewSMTPProvider(host string, port int, username, password string, from Sender) *SMTPProvider {
+	if port == 0 {
+		port = 587
+	}
+	p := &SMTPProvider{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		host: host,
+		from: from,
+	}
+	if username != "" {
+		p.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return p
+}
+
+func (p *SMTPProvider) Name() string {
+	return "smtp"
+}
+
+// Send ignores ctx: net/smtp has no way to cancel a conversation.
+func (p *SMTPProvider) Send(ctx context.Context, msg Message) error {
+	body, err := p.compose(msg)
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+
+	if err := smtp.SendMail(p.addr, p.auth, p.from.Address, []string{msg.To}, body); err != nil {
+		// 5xx replies reject the message or the recipient for good
+		var reply *textproto.Error
+		if errors.As(err, &reply) && reply.Code >= 500 {
+			return &PermanentError{Err: fmt.Errorf("smtp: %w", err)}
+		}
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return nil
+}
+
+// compose builds a multipart/alternative message with the text and HTML
+// bodies, quoted-printable encoded.
+func (p *SMTPProvider) compose(msg Message) ([]byte, error) {
+	if _, err := mail.ParseAddress(msg.To); err != nil {
+		return nil, fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	boundary, err := newBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	from := mail.Address{Name: p.from.Name, Address: p.from.Address}
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	for _, part := range []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	} {
+		if part.body == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		w := quotedprintable.NewWriter(&buf)
+		if _, err := w.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+func newBoundary() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate MIME boundary: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
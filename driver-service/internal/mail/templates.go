@@ -0,0 +1,227 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+const (
+	TemplateWelcome          = "welcome"
+	TemplateReceipt          = "receipt"
+	TemplateSuspensionNotice = "suspension_notice"
+)
+
+var (
+	ErrUnknownTemplate = errors.New("unknown email template")
+	ErrMissingData     = errors.New("missing email template data")
+)
+
+type templateSource struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// templateSources are the templates by name and locale. Every template has a
+// models.DefaultLocale version, which is used when no preferred locale has
+// one. Optional fields are read with index so they may be left out.
+var templateSources = map[string]map[string]templateSource{
+	TemplateWelcome: {
+		"tr": {
+			Subject: "TaxiHub'a hoş geldiniz",
+			Text: `Merhaba {{.first_name}},
+
+{{.plate}} plakalı aracınızla TaxiHub kaydınız tamamlandı. Sürücü uygulamasına giriş yaparak çağrı almaya başlayabilirsiniz.
+
+TaxiHub`,
+			HTML: `<p>Merhaba {{.first_name}},</p>
+<p><strong>{{.plate}}</strong> plakalı aracınızla TaxiHub kaydınız tamamlandı. Sürücü uygulamasına giriş yaparak çağrı almaya başlayabilirsiniz.</p>
+<p>TaxiHub</p>`,
+		},
+		"en": {
+			Subject: "Welcome to TaxiHub",
+			Text: `Hello {{.first_name}},
+
+Your TaxiHub registration for the vehicle with plate {{.plate}} is complete. Log in to the driver app to start receiving rides.
+
+TaxiHub`,
+			HTML: `<p>Hello {{.first_name}},</p>
+<p>Your TaxiHub registration for the vehicle with plate <strong>{{.plate}}</strong> is complete. Log in to the driver app to start receiving rides.</p>
+<p>TaxiHub</p>`,
+		},
+	},
+	TemplateReceipt: {
+		"tr": {
+			Subject: "Makbuz {{.reference}}",
+			Text: `Merhaba {{.first_name}},
+
+{{.issued_on}} tarihli {{.reference}} numaralı makbuzunuz:
+
+{{.description}}: {{.amount}} {{.currency}}
+
+TaxiHub`,
+			HTML: `<p>Merhaba {{.first_name}},</p>
+<p>{{.issued_on}} tarihli <strong>{{.reference}}</strong> numaralı makbuzunuz:</p>
+<p>{{.description}}: <strong>{{.amount}} {{.currency}}</strong></p>
+<p>TaxiHub</p>`,
+		},
+		"en": {
+			Subject: "Receipt {{.reference}}",
+			Text: `Hello {{.first_name}},
+
+Here is your receipt {{.reference}} of {{.issued_on}}:
+
+{{.description}}: {{.amount}} {{.currency}}
+
+TaxiHub`,
+			HTML: `<p>Hello {{.first_name}},</p>
+<p>Here is your receipt <strong>{{.reference}}</strong> of {{.issued_on}}:</p>
+<p>{{.description}}: <strong>{{.amount}} {{.currency}}</strong></p>
+<p>TaxiHub</p>`,
+		},
+	},
+	TemplateSuspensionNotice: {
+		"tr": {
+			Subject: "TaxiHub hesabınız askıya alındı",
+			Text: `Merhaba {{.first_name}},
+
+TaxiHub sürücü hesabınız askıya alındı{{with index . "until"}} ({{.}} tarihine kadar){{end}}.
+
+Gerekçe: {{.reason}}
+
+İtiraz etmek için destek ekibimize bu e-postayı yanıtlayarak ulaşabilirsiniz.
+
+TaxiHub`,
+			HTML: `<p>Merhaba {{.first_name}},</p>
+<p>TaxiHub sürücü hesabınız askıya alındı{{with index . "until"}} ({{.}} tarihine kadar){{end}}.</p>
+<p>Gerekçe: {{.reason}}</p>
+<p>İtiraz etmek için destek ekibimize bu e-postayı yanıtlayarak ulaşabilirsiniz.</p>
+<p>TaxiHub</p>`,
+		},
+		"en": {
+			Subject: "Your TaxiHub account has been suspended",
+			Text: `Hello {{.first_name}},
+
+Your TaxiHub driver account has been suspended{{with index . "until"}} until {{.}}{{end}}.
+
+Reason: {{.reason}}
+
+To appeal, reply to this email to reach our support team.
+
+TaxiHub`,
+			HTML: `<p>Hello {{.first_name}},</p>
+<p>Your TaxiHub driver account has been suspended{{with index . "until"}} until {{.}}{{end}}.</p>
+<p>Reason: {{.reason}}</p>
+<p>To appeal, reply to this email to reach our support team.</p>
+<p>TaxiHub</p>`,
+		},
+	},
+}
+
+// templateFields are the data fields each template requires.
+var templateFields = map[string][]string{
+	TemplateWelcome:          {"first_name", "plate"},
+	TemplateReceipt:          {"first_name", "reference", "issued_on", "description", "amount", "currency"},
+	TemplateSuspensionNotice: {"first_name", "reason"},
+}
+
+type localizedTemplate struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// Templates renders the email templates.
+type Templates struct {
+	templates map[string]map[string]localizedTemplate
+}
+
+// NewTemplates parses every template.
+func NewTemplates() (*Templates, error) {
+	t := &Templates{templates: make(map[string]map[string]localizedTemplate)}
+	for name, locales := range templateSources {
+		t.templates[name] = make(map[string]localizedTemplate)
+		for locale, source := range locales {
+			id := name + "." + locale
+			subject, err := texttemplate.New(id + ".subject").Option("missingkey=error").Parse(source.Subject)
+			if err != nil {
+				return nil, err
+			}
+			text, err := texttemplate.New(id + ".text").Option("missingkey=error").Parse(source.Text)
+			if err != nil {
+				return nil, err
+			}
+			html, err := htmltemplate.New(id + ".html").Option("missingkey=error").Parse(source.HTML)
+			if err != nil {
+				return nil, err
+			}
+			t.templates[name][locale] = localizedTemplate{subject: subject, text: text, html: html}
+		}
+	}
+	return t, nil
+}
+
+// Names returns the template names, sorted.
+func (t *Templates) Names() []string {
+	names := make([]string, 0, len(t.templates))
+	for name := range t.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render renders the template in the first preferred locale it exists in,
+// trying each exact tag before its base language, and otherwise in
+// models.DefaultLocale. It returns the message, without a recipient, and the
+// locale used.
+func (t *Templates) Render(name string, preferred []string, data map[string]string) (Message, string, error) {
+	locales, ok := t.templates[name]
+	if !ok {
+		return Message{}, "", fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	for _, field := range templateFields[name] {
+		if strings.TrimSpace(data[field]) == "" {
+			return Message{}, "", fmt.Errorf("%w: %s needs %s", ErrMissingData, name, field)
+		}
+	}
+
+	locale := models.DefaultLocale
+	for _, candidate := range preferred {
+		candidate = models.NormalizeLocale(candidate)
+		if _, ok := locales[candidate]; ok {
+			locale = candidate
+			break
+		}
+		base := strings.SplitN(candidate, "-", 2)[0]
+		if _, ok := locales[base]; ok {
+			locale = base
+			break
+		}
+	}
+	tmpl := locales[locale]
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return Message{}, "", fmt.Errorf("failed to render %s subject: %w", name, err)
+	}
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return Message{}, "", fmt.Errorf("failed to render %s text: %w", name, err)
+	}
+	if err := tmpl.html.Execute(&html, data); err != nil {
+		return Message{}, "", fmt.Errorf("failed to render %s HTML: %w", name, err)
+	}
+
+	return Message{
+		Subject: subject.String(),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, locale, nil
+}
@@ -0,0 +1,161 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// NearbyResultBuckets bound how many drivers a nearby search returns.
+var NearbyResultBuckets = []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500}
+
+// HTTP counts requests and their latency per matched route.
+type HTTP struct {
+	requests *CounterVec
+	duration *HistogramVec
+}
+
+func NewHTTP() *HTTP {
+	return &HTTP{
+		requests: NewCounterVec("driver_service_http_requests_total",
+			"HTTP requests by matched route and status code.", "method", "route", "status"),
+		duration: NewHistogramVec("driver_service_http_request_duration_seconds",
+			"HTTP request latency by matched route.", LatencyBuckets, "method", "route"),
+	}
+}
+
+func (h *HTTP) Observe(method, route string, status int, elapsed time.Duration) {
+	h.requests.Inc(method, route, strconv.Itoa(status))
+	h.duration.Observe(elapsed.Seconds(), method, route)
+}
+
+func (h *HTTP) WriteMetrics(w io.Writer) error {
+	if err := h.requests.WriteMetrics(w); err != nil {
+		return err
+	}
+	return h.duration.WriteMetrics(w)
+}
+
+// Mongo times MongoDB commands and follows the connection pools through the
+// driver's monitoring events.
+type Mongo struct {
+	commands         *HistogramVec
+	connections      *GaugeVec
+	checkoutFailures *CounterVec
+}
+
+func NewMongo() *Mongo {
+	return &Mongo{
+		commands: NewHistogramVec("driver_service_mongo_command_duration_seconds",
+			"MongoDB command latency by command and outcome.", LatencyBuckets, "command", "outcome"),
+		connections: NewGaugeVec("driver_service_mongo_pool_connections",
+			"MongoDB pool connections by server and state (open or in_use).", "address", "state"),
+		checkoutFailures: NewCounterVec("driver_service_mongo_pool_checkout_failures_total",
+			"MongoDB connection checkouts that failed, by server and reason.", "address", "reason"),
+	}
+}
+
+func (m *Mongo) CommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			m.commands.Observe(e.Duration.Seconds(), e.CommandName, "ok")
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			m.commands.Observe(e.Duration.Seconds(), e.CommandName, "error")
+		},
+	}
+}
+
+func (m *Mongo) PoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			switch e.Type {
+			case event.ConnectionCreated:
+				m.connections.Add(1, e.Address, "open")
+			case event.ConnectionClosed:
+				m.connections.Add(-1, e.Address, "open")
+			case event.GetSucceeded:
+				m.connections.Add(1, e.Address, "in_use")
+			case event.ConnectionReturned:
+				m.connections.Add(-1, e.Address, "in_use")
+			case event.GetFailed:
+				m.checkoutFailures.Inc(e.Address, e.Reason)
+			}
+		},
+	}
+}
+
+func (m *Mongo) WriteMetrics(w io.Writer) error {
+	for _, collector := range []Collector{m.commands, m.connections, m.checkoutFailures} {
+		if err := collector.WriteMetrics(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Repository times repository calls and counts the drivers nearby searches
+// return.
+type Repository struct {
+	operations    *HistogramVec
+	nearbyResults *HistogramVec
+}
+
+func NewRepository() *Repository {
+	return &Repository{
+		operations: NewHistogramVec("driver_service_repository_operation_duration_seconds",
+			"Repository call latency by repository, operation and outcome.", LatencyBuckets, "repository", "operation", "outcome"),
+		nearbyResults: NewHistogramVec("driver_service_nearby_search_results",
+			"Drivers returned per nearby query sent to MongoDB.", NearbyResultBuckets),
+	}
+}
+
+// ObserveOperation records a call that took elapsed; a non-nil err counts it
+// as an error.
+func (r *Repository) ObserveOperation(repository, operation string, elapsed time.Duration, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	r.operations.Observe(elapsed.Seconds(), repository, operation, outcome)
+}
+
+func (r *Repository) ObserveNearbyResults(count int) {
+	r.nearbyResults.Observe(float64(count))
+}
+
+func (r *Repository) WriteMetrics(w io.Writer) error {
+	if err := r.operations.WriteMetrics(w); err != nil {
+		return err
+	}
+	return r.nearbyResults.WriteMetrics(w)
+}
+
+// LocationRejections counts location fixes failing the location checks, by
+// reason and by whether they were rejected (strict) or only counted
+// (permissive).
+type LocationRejections struct {
+	rejections *CounterVec
+}
+
+func NewLocationRejections() *LocationRejections {
+	return &LocationRejections{
+		rejections: NewCounterVec("driver_service_location_rejections_total",
+			"Location fixes failing the location checks, by reason and mode (strict or permissive).", "reason", "mode"),
+	}
+}
+
+func (l *LocationRejections) ObserveLocationRejection(reason string, enforced bool) {
+	mode := "permissive"
+	if enforced {
+		mode = "strict"
+	}
+	l.rejections.Inc(reason, mode)
+}
+
+func (l *LocationRejections) WriteMetrics(w io.Writer) error {
+	return l.rejections.WriteMetrics(w)
+}
@@ -0,0 +1,238 @@
+// Package metrics keeps the service's Prometheus metrics and writes them in
+// the text exposition format, without pulling in the Prometheus client.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LatencyBuckets are histogram bounds in seconds, from 1ms to 10s.
+var LatencyBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Collector writes its metrics in the Prometheus text format.
+type Collector interface {
+	WriteMetrics(w io.Writer) error
+}
+
+// Registry is the set of collectors behind GET /metrics.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) Register(collectors ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collectors...)
+}
+
+// WriteMetrics writes every collector's metrics in registration order.
+func (r *Registry) WriteMetrics(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	for _, collector := range collectors {
+		if err := collector.WriteMetrics(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vec holds the series of one metric, keyed by their label values.
+type vec struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	values []string
+
+	// value is a counter's or gauge's value.
+	value float64
+
+	// counts are a histogram's observations per bucket, not cumulative; the
+	// last bucket is +Inf.
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func newVec(name, help, kind string, labels []string) vec {
+	return vec{
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: labels,
+		series: make(map[string]*series),
+	}
+}
+
+// with returns the series for the label values. The caller must hold v.mu.
+func (v *vec) with(values []string) *series {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metric %s takes %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{values: append([]string(nil), values...)}
+		v.series[key] = s
+	}
+	return s
+}
+
+// writeTo writes the header and then each series, sorted by label values,
+// through write.
+func (v *vec) writeTo(w io.Writer, write func(b *strings.Builder, labels string, s *series)) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s %s\n", v.name, v.help)
+	fmt.Fprintf(&b, "# TYPE %s %s\n", v.name, v.kind)
+
+	v.mu.Lock()
+	keys := make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := v.series[key]
+		write(&b, labelPairs(v.labels, s.values), s)
+	}
+	v.mu.Unlock()
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (v *vec) add(delta float64, values []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.with(values).value += delta
+}
+
+func (v *vec) writeValues(w io.Writer) error {
+	return v.writeTo(w, func(b *strings.Builder, labels string, s *series) {
+		fmt.Fprintf(b, "%s%s %s\n", v.name, braces(labels), formatFloat(s.value))
+	})
+}
+
+// CounterVec is a counter per combination of label values.
+type CounterVec struct {
+	vec
+}
+
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{vec: newVec(name, help, "counter", labels)}
+}
+
+func (v *CounterVec) Inc(values ...string) {
+	v.add(1, values)
+}
+
+func (v *CounterVec) WriteMetrics(w io.Writer) error {
+	return v.writeValues(w)
+}
+
+// GaugeVec is a gauge per combination of label values.
+type GaugeVec struct {
+	vec
+}
+
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{vec: newVec(name, help, "gauge", labels)}
+}
+
+func (v *GaugeVec) Add(delta float64, values ...string) {
+	v.add(delta, values)
+}
+
+func (v *GaugeVec) WriteMetrics(w io.Writer) error {
+	return v.writeValues(w)
+}
+
+// HistogramVec is a histogram per combination of label values.
+type HistogramVec struct {
+	vec
+	buckets []float64
+}
+
+// NewHistogramVec creates a histogram with the given ascending upper bounds;
+// the +Inf bucket is implied.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{
+		vec:     newVec(name, help, "histogram", labels),
+		buckets: buckets,
+	}
+}
+
+func (v *HistogramVec) Observe(value float64, values ...string) {
+	bucket := sort.SearchFloat64s(v.buckets, value)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	s := v.with(values)
+	if s.counts == nil {
+		s.counts = make([]uint64, len(v.buckets)+1)
+	}
+	s.counts[bucket]++
+	s.sum += value
+	s.count++
+}
+
+func (v *HistogramVec) WriteMetrics(w io.Writer) error {
+	return v.writeTo(w, func(b *strings.Builder, labels string, s *series) {
+		prefix := labels
+		if prefix != "" {
+			prefix += ","
+		}
+		var cumulative uint64
+		for i, bound := range v.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(b, "%s_bucket{%sle=\"%s\"} %d\n", v.name, prefix, formatFloat(bound), cumulative)
+		}
+		fmt.Fprintf(b, "%s_bucket{%sle=\"+Inf\"} %d\n", v.name, prefix, s.count)
+		fmt.Fprintf(b, "%s_sum%s %s\n", v.name, braces(labels), formatFloat(s.sum))
+		fmt.Fprintf(b, "%s_count%s %d\n", v.name, braces(labels), s.count)
+	})
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labelPairs(names, values []string) string {
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + labelEscaper.Replace(values[i]) + `"`
+	}
+	return strings.Join(pairs, ",")
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
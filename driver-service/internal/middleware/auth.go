@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	authClaimsLocal   = "auth_claims"
+	driversPathPrefix = "/api/v1/drivers/"
+	adminPathPrefix   = "/api/v1/admin"
+)
+
+type TokenAuthenticator interface {
+	AuthenticateAccessToken(ctx context.Context, token string) (*auth.Claims, error)
+}
+
+type AuthConfig struct {
+	// Required rejects requests without an access token unless the path is
+	// public. Otherwise they pass unauthenticated, as before tokens existed.
+	// A token that is presented is always checked.
+	Required bool
+	// PublicPaths are paths open without a token; entries ending in "/*"
+	// match everything below them.
+	PublicPaths []string
+}
+
+// Auth checks bearer access tokens and what their claims allow: tokens only
+// work for their own tenant, only admins may call admin routes, and other
+// roles may only write under /api/v1/drivers/{ID} for their own record.
+// Routes are not matched yet when this runs, so the checks go by path;
+// RequireRole and RequireSelfOrRole narrow individual routes further. It must
+// run after Tenant. Device tokens are left to DeviceAuth, and sandbox
+// requests need no token: Sandbox has kept them to the routes served from
+// synthetic data.
+func Auth(authenticator TokenAuthenticator, cfg AuthConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := SandboxKey(c); ok {
+			return c.Next()
+		}
+
+		path := requestPath(c)
+
+		token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		token = strings.TrimSpace(token)
+		if !ok || token == "" || strings.HasPrefix(token, models.DeviceTokenPrefix) {
+			if cfg.Required && !isPublicPath(path, cfg.PublicPaths) {
+				c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+				return errorResponse(c, http.StatusUnauthorized, "Access token required", nil)
+			}
+			return c.Next()
+		}
+
+		claims, err := authenticator.AuthenticateAccessToken(c.Context(), token)
+		if err != nil {
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+			return errorResponse(c, http.StatusUnauthorized, "Invalid access token", nil)
+		}
+		// A token only works for the tenant it was issued for, whatever
+		// X-Tenant-ID says
+		if claims.TenantID != requestTenant(c) {
+			return errorResponse(c, http.StatusForbidden, "Access token belongs to another tenant", nil)
+		}
+
+		if !claims.IsAdmin() {
+			if path == adminPathPrefix || strings.HasPrefix(path, adminPathPrefix+"/") {
+				return errorResponse(c, http.StatusForbidden, "Admin access required", nil)
+			}
+			if isWrite(c.Method()) && !ownsDriverPath(claims, path) {
+				return errorResponse(c, http.StatusForbidden, "Only admins may change other drivers' records", nil)
+			}
+		}
+
+		c.Locals(authClaimsLocal, claims)
+		return c.Next()
+	}
+}
+
+// AuthClaims returns the claims of the request's access token, if it had one.
+func AuthClaims(c *fiber.Ctx) (*auth.Claims, bool) {
+	claims, ok := c.Locals(authClaimsLocal).(*auth.Claims)
+	return claims, ok && claims != nil
+}
+
+// requestPath is the request path as routing matches it: routing ignores
+// case and trailing slashes, so path checks must too.
+func requestPath(c *fiber.Ctx) string {
+	path := strings.ToLower(c.Path())
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+// ownsDriverPath reports whether a write to path is allowed for the driver:
+// writes to a driver record must target their own, and creating drivers is
+// left to admins. Writes outside driver records, such as searches sent as
+// POST, are allowed.
+func ownsDriverPath(claims *auth.Claims, path string) bool {
+	if path == strings.TrimSuffix(driversPathPrefix, "/") {
+		return false
+	}
+
+	rest, ok := strings.CutPrefix(path, driversPathPrefix)
+	if !ok {
+		return true
+	}
+	driverID, _, _ := strings.Cut(rest, "/")
+	if !primitive.IsValidObjectID(driverID) {
+		return true
+	}
+	return claims.IsDriver(driverID)
+}
+
+func isWrite(method string) bool {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+func isPublicPath(path string, publicPaths []string) bool {
+	for _, public := range publicPaths {
+		public = strings.ToLower(public)
+		if prefix, ok := strings.CutSuffix(public, "/*"); ok {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				return true
+			}
+		} else if path == public {
+			return true
+		}
+	}
+	return false
+}
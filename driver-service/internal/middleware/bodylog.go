@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+const (
+	FleetIDHeader      = "X-Fleet-ID"
+	maxLoggedBodyBytes = 16 * 1024
+)
+
+type BodyLogConfig struct {
+	// Routes lists "METHOD /path" or "/path" route patterns to capture, or "*".
+	Routes []string
+	// Fleets restricts capture to the given X-Fleet-ID values; empty means all.
+	Fleets    []string
+	Retention time.Duration
+}
+
+// BodyLogger captures request bodies for enabled routes and fleets, redacts
+// PII and stores them with a short retention. It is a no-op when no routes
+// are configured.
+func BodyLogger(cfg BodyLogConfig, store repository.RequestLogRepository) fiber.Handler {
+	routes := make(map[string]struct{}, len(cfg.Routes))
+	for _, route := range cfg.Routes {
+		routes[normalizeRoute(route)] = struct{}{}
+	}
+	fleets := make(map[string]struct{}, len(cfg.Fleets))
+	for _, fleet := range cfg.Fleets {
+		fleets[fleet] = struct{}{}
+	}
+
+	return func(c *fiber.Ctx) error {
+		if len(routes) == 0 {
+			return c.Next()
+		}
+
+		fleetID := c.Get(FleetIDHeader)
+		if len(fleets) > 0 {
+			if _, ok := fleets[fleetID]; !ok {
+				return c.Next()
+			}
+		}
+
+		body := c.Body()
+		if len(body) > maxLoggedBodyBytes {
+			body = nil
+		} else {
+			body = append([]byte(nil), body...)
+		}
+
+		err := c.Next()
+
+		// The matched route is only known after routing has run.
+		route := normalizeRoute(c.Route().Path)
+		if !routeEnabled(routes, c.Method(), route) {
+			return err
+		}
+
+		now := time.Now()
+		entry := &models.RequestLog{
+			RequestID: requestID(c),
+			Method:    c.Method(),
+			Route:     route,
+			Path:      c.Path(),
+			FleetID:   fleetID,
+			Status:    c.Response().StatusCode(),
+			Body:      RedactBody(body),
+			CreatedAt: now,
+			ExpiresAt: now.Add(cfg.Retention),
+		}
+
+		logger := logging.FromContext(c.Context())
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := store.Create(ctx, entry); err != nil {
+				logger.Error("Error storing request body log", "error", err)
+			}
+		}()
+
+		return err
+	}
+}
+
+func routeEnabled(routes map[string]struct{}, method, route string) bool {
+	if _, ok := routes["*"]; ok {
+		return true
+	}
+	if _, ok := routes[route]; ok {
+		return true
+	}
+	_, ok := routes[method+" "+route]
+	return ok
+}
+
+func normalizeRoute(route string) string {
+	route = strings.TrimSpace(route)
+	if len(route) > 1 {
+		route = strings.TrimSuffix(route, "/")
+	}
+	return route
+}
+
+func requestID(c *fiber.Ctx) string {
+	if id, ok := c.Locals("requestid").(string); ok {
+		return id
+	}
+	return c.GetRespHeader(fiber.HeaderXRequestID)
+}
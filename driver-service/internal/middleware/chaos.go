@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/chaos"
+)
+
+const chaosHeader = "X-Chaos-Faults"
+
+// Chaos rolls the active request-scoped faults for each request and tags
+// affected responses so injected failures are easy to tell apart.
+func Chaos(injector *chaos.Injector) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if faults := injector.Roll(c.Path()); faults != nil {
+			c.Locals(chaos.LocalKey, faults)
+			c.Set(chaosHeader, strings.Join(faults.Kinds(), ","))
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/deprecation"
+	"github.com/taxihub/driver-service/internal/logging"
+)
+
+const (
+	deprecationHeader = "Deprecation"
+	sunsetHeader      = "Sunset"
+)
+
+// Deprecation marks responses from deprecated routes with the Deprecation
+// (RFC 9745) and Sunset (RFC 8594) headers and records the caller. The route
+// is only known once the router has matched it, so this runs after c.Next.
+func Deprecation(registry *deprecation.Registry) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		rule, ok := registry.Lookup(c.Method(), c.Route().Path)
+		if !ok {
+			return err
+		}
+
+		c.Set(deprecationHeader, "@"+strconv.FormatInt(rule.Deprecated.Unix(), 10))
+		if rule.Sunset != nil {
+			c.Set(sunsetHeader, rule.Sunset.UTC().Format(http.TimeFormat))
+		}
+
+		caller := deprecationCaller(c)
+		if registry.Record(rule, caller, time.Now()) {
+			logging.FromContext(c.Context()).Warn("Deprecated route called", "route", rule.Method+" "+rule.Route, "caller", caller)
+		}
+		return err
+	}
+}
+
+// deprecationCaller identifies the caller like the polling guard does, but
+// only keeps a prefix of API keys so the report and logs do not leak them.
+func deprecationCaller(c *fiber.Ctx) string {
+	key := clientKey(c)
+	if apiKey, ok := strings.CutPrefix(key, "key:"); ok {
+		if len(apiKey) > 4 {
+			apiKey = apiKey[:4]
+		}
+		return "key:" + apiKey + "…"
+	}
+	return key
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/service"
+)
+
+const deviceTokenLocal = "device_token"
+
+type DeviceAuthenticator interface {
+	Authenticate(ctx context.Context, secret string) (*models.DeviceToken, error)
+}
+
+// DeviceAuth accepts only device tokens carrying scope. Handlers behind it
+// must act on DeviceToken(c).DriverID and nothing else.
+func DeviceAuth(authenticator DeviceAuthenticator, scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		secret, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || secret == "" {
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return errorResponse(c, http.StatusUnauthorized, "Device token required", nil)
+		}
+
+		token, err := authenticator.Authenticate(c.Context(), strings.TrimSpace(secret))
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidDeviceToken) {
+				c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+				return errorResponse(c, http.StatusUnauthorized, "Invalid device token", nil)
+			}
+			return errorResponse(c, http.StatusInternalServerError, "Failed to check device token", nil)
+		}
+		if !token.HasScope(scope) {
+			return errorResponse(c, http.StatusForbidden, "Device token lacks scope "+scope, nil)
+		}
+
+		c.Locals(deviceTokenLocal, token)
+		return c.Next()
+	}
+}
+
+func DeviceToken(c *fiber.Ctx) (*models.DeviceToken, bool) {
+	token, ok := c.Locals(deviceTokenLocal).(*models.DeviceToken)
+	return token, ok && token != nil
+}
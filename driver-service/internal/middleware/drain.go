@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/drain"
+)
+
+// DrainTracking counts the request as in flight until every later handler
+// has returned, panics included, so shutdown can report what it drained.
+func DrainTracking(tracker *drain.Tracker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tracker.Start()
+		defer tracker.Done()
+		return c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+const (
+	DriverIDHeader   = "X-Driver-ID"
+	CityHeader       = "X-City"
+	AppVersionHeader = "X-App-Version"
+)
+
+type FeatureEvaluator interface {
+	IsEnabled(ctx context.Context, key string, target models.TargetingContext) bool
+}
+
+// TargetingContext describes the calling driver from request headers. The
+// driver ID falls back to the :id route parameter.
+func TargetingContext(c *fiber.Ctx) models.TargetingContext {
+	driverID := c.Get(DriverIDHeader)
+	if driverID == "" {
+		driverID = c.Params("id")
+	}
+
+	return models.TargetingContext{
+		DriverID:   driverID,
+		Fleet:      c.Get(FleetIDHeader),
+		City:       c.Get(CityHeader),
+		AppVersion: c.Get(AppVersionHeader),
+	}
+}
+
+// RequireFeature rejects requests from drivers the flag is not enabled for,
+// keeping beta APIs closed to everyone outside the targeted cohort.
+func RequireFeature(evaluator FeatureEvaluator, key string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !evaluator.IsEnabled(c.Context(), key, TargetingContext(c)) {
+			return errorResponse(c, http.StatusForbidden, "Feature not enabled", []string{key})
+		}
+		return c.Next()
+	}
+}
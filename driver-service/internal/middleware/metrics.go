@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/metrics"
+)
+
+// HTTPMetrics counts requests and their latency per matched route. Like
+// SLORecorder it must run outside recover, so panics are counted as 500s.
+func HTTPMetrics(m *metrics.HTTP) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		m.Observe(c.Method(), normalizeRoute(c.Route().Path), status, time.Since(start))
+		return err
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole lets a request through when its access token has one of the
+// roles. Requests without a token get 401 even while tokens are optional, so
+// a route limited to roles is never open; only sandbox requests, which
+// Sandbox keeps to synthetic data, pass without one.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := AuthClaims(c)
+		if !ok {
+			return requireToken(c)
+		}
+		if !claims.HasRole(roles...) {
+			return errorResponse(c, http.StatusForbidden, "Insufficient role", nil)
+		}
+		return c.Next()
+	}
+}
+
+// RequireSelfOrRole is RequireRole for routes on one driver's record: the
+// driver named by the :id parameter is let through as well.
+func RequireSelfOrRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := AuthClaims(c)
+		if !ok {
+			return requireToken(c)
+		}
+		if !claims.HasRole(roles...) && !claims.IsDriver(strings.ToLower(c.Params("id"))) {
+			return errorResponse(c, http.StatusForbidden, "Insufficient role", nil)
+		}
+		return c.Next()
+	}
+}
+
+// RequireFleetCapability is RequireRole for fleet-scoped routes: fleet admins
+// of the fleet named by the :fleetId parameter holding the capability are let
+// through, as are admins.
+func RequireFleetCapability(capability string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := AuthClaims(c)
+		if !ok {
+			return requireToken(c)
+		}
+		if !claims.CanInFleet(c.Params("fleetId"), capability) {
+			return errorResponse(c, http.StatusForbidden, "Missing fleet capability", []string{capability})
+		}
+		return c.Next()
+	}
+}
+
+// requireToken answers a request that reached a guarded route without an
+// access token.
+func requireToken(c *fiber.Ctx) error {
+	if _, ok := SandboxKey(c); ok {
+		return c.Next()
+	}
+	c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+	return errorResponse(c, http.StatusUnauthorized, "Access token required", nil)
+}
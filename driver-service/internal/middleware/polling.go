@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	ClientIDHeader         = "X-Client-ID"
+	subscriptionHintHeader = "X-Subscription-Hint"
+	pollingSweepInterval   = 5 * time.Minute
+)
+
+type PollingGuardConfig struct {
+	// Window is the period over which polls are counted per client.
+	Window time.Duration
+	// SoftLimit is the number of polls per window before hints and
+	// throttling kick in.
+	SoftLimit int
+	// Step is the minimum spacing added per poll above the soft limit.
+	Step time.Duration
+	// MaxInterval caps the enforced spacing between polls.
+	MaxInterval time.Duration
+	// SubscriptionURL is advertised to clients that poll too often.
+	SubscriptionURL string
+}
+
+type pollingClient struct {
+	windowStart time.Time
+	count       int
+	lastServed  time.Time
+}
+
+type pollingGuard struct {
+	cfg       PollingGuardConfig
+	mu        sync.Mutex
+	clients   map[string]*pollingClient
+	lastSweep time.Time
+}
+
+// PollingGuard detects clients polling an endpoint at high frequency. Past
+// the soft limit responses advertise the subscription endpoint, and the
+// enforced spacing between polls grows with every extra request until
+// MaxInterval; polls arriving too early get 429 with Retry-After.
+func PollingGuard(cfg PollingGuardConfig) fiber.Handler {
+	guard := &pollingGuard{
+		cfg:     cfg,
+		clients: make(map[string]*pollingClient),
+	}
+
+	return func(c *fiber.Ctx) error {
+		if cfg.SoftLimit <= 0 {
+			return c.Next()
+		}
+
+		over, retryAfter := guard.record(clientKey(c), time.Now())
+		if over > 0 {
+			c.Set(subscriptionHintHeader, cfg.SubscriptionURL)
+			c.Append(fiber.HeaderLink, "<"+cfg.SubscriptionURL+`>; rel="subscribe"`)
+		}
+		if retryAfter > 0 {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			return errorResponse(c, fiber.StatusTooManyRequests, "Polling too frequently, subscribe to live updates instead", []string{
+				"subscription endpoint: " + cfg.SubscriptionURL,
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// record counts a poll and returns how far the client is over the soft limit
+// and, when throttled, how long it has to wait.
+func (g *pollingGuard) record(key string, now time.Time) (int, time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if now.Sub(g.lastSweep) > pollingSweepInterval {
+		for k, client := range g.clients {
+			if now.Sub(client.windowStart) > g.cfg.Window {
+				delete(g.clients, k)
+			}
+		}
+		g.lastSweep = now
+	}
+
+	client, ok := g.clients[key]
+	if !ok || now.Sub(client.windowStart) > g.cfg.Window {
+		client = &pollingClient{windowStart: now}
+		g.clients[key] = client
+	}
+	client.count++
+
+	over := client.count - g.cfg.SoftLimit
+	if over <= 0 {
+		client.lastServed = now
+		return 0, 0
+	}
+
+	interval := time.Duration(over) * g.cfg.Step
+	if interval > g.cfg.MaxInterval {
+		interval = g.cfg.MaxInterval
+	}
+	if wait := interval - now.Sub(client.lastServed); wait > 0 {
+		return over, wait
+	}
+
+	client.lastServed = now
+	return over, 0
+}
+
+func clientKey(c *fiber.Ctx) string {
+	if key := c.Get(APIKeyHeader); key != "" {
+		return "key:" + key
+	}
+	if id := c.Get(ClientIDHeader); id != "" {
+		return "client:" + id
+	}
+	return "ip:" + c.IP()
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+var (
+	piiFields = map[string]struct{}{
+		"first_name":   {},
+		"last_name":    {},
+		"name":         {},
+		"full_name":    {},
+		"plate":        {},
+		"phone":        {},
+		"phone_number": {},
+		"email":        {},
+	}
+
+	phonePattern = regexp.MustCompile(`\+?\d[\d\s\-()]{8,}\d`)
+)
+
+// RedactBody masks PII in a JSON request body. Known PII fields are masked
+// wholesale and phone-number-looking substrings are masked anywhere else.
+// Bodies that are not valid JSON are not stored verbatim.
+func RedactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "[non-JSON body omitted]"
+	}
+
+	redacted, err := json.Marshal(redactValue("", payload))
+	if err != nil {
+		return "[unserializable body omitted]"
+	}
+	return string(redacted)
+}
+
+func redactValue(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, nested := range v {
+			v[k] = redactValue(strings.ToLower(k), nested)
+		}
+		return v
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = redactValue(key, nested)
+		}
+		return v
+	case string:
+		if _, ok := piiFields[key]; ok {
+			return maskString(v)
+		}
+		return phonePattern.ReplaceAllStringFunc(v, maskString)
+	default:
+		return v
+	}
+}
+
+func maskString(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 1 {
+		return "*"
+	}
+	return string(runes[0]) + strings.Repeat("*", len(runes)-1)
+}
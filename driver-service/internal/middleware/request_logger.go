@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/logging"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RequestLogger gives each request a logger carrying its request ID, method,
+// path, tenant and, on driver routes, the driver ID. Handlers, services and
+// repositories reach it with logging.FromContext. Once the request is done
+// it logs the status and latency: as an error for 5xx, a warning for 4xx
+// and info otherwise. It must run after requestid.
+func RequestLogger(base *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		attrs := []any{"request_id", requestID(c), "method", c.Method(), "path", c.Path()}
+		if tenantID := c.Get(TenantHeader); tenantID != "" {
+			attrs = append(attrs, "tenant", tenantID)
+		}
+		driverID := pathDriverID(c.Path())
+		if driverID != "" {
+			attrs = append(attrs, "driver_id", driverID)
+		}
+		logger := base.With(attrs...)
+		c.Locals(logging.LoggerKey, logger)
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= fiber.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		args := []any{
+			"status", status,
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+		}
+		// Drivers calling routes outside their record are known once
+		// authenticated
+		if driverID == "" {
+			if claims, ok := AuthClaims(c); ok && claims.IsDriver(claims.Subject) {
+				args = append(args, "driver_id", claims.Subject)
+			} else if token, ok := DeviceToken(c); ok {
+				args = append(args, "driver_id", token.DriverID.Hex())
+			}
+		}
+		if err != nil {
+			args = append(args, "error", err)
+		}
+		logger.Log(c.Context(), level, "request", args...)
+
+		return err
+	}
+}
+
+// pathDriverID returns the driver ID of a /api/v1/drivers/{ID} path, or "".
+func pathDriverID(path string) string {
+	rest, ok := strings.CutPrefix(strings.ToLower(path), driversPathPrefix)
+	if !ok {
+		return ""
+	}
+	driverID, _, _ := strings.Cut(rest, "/")
+	if !primitive.IsValidObjectID(driverID) {
+		return ""
+	}
+	return driverID
+}
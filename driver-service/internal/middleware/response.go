@@ -0,0 +1,14 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+func errorResponse(c *fiber.Ctx, statusCode int, message string, details []string) error {
+	return c.Status(statusCode).JSON(models.ErrorResponse{
+		Error:   message,
+		Details: details,
+		Code:    statusCode,
+	})
+}
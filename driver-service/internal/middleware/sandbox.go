@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	APIKeyHeader     = "X-API-Key"
+	sandboxKeyLocal  = "sandbox_api_key"
+	sandboxHeaderKey = "X-Sandbox-Mode"
+)
+
+// sandboxRoutes are the routes served from the synthetic dataset, as path
+// patterns whose ":" segments match any value. Every other route would reach
+// production data.
+var sandboxRoutes = []string{
+	"/api/v1/drivers",
+	"/api/v1/drivers/nearby",
+	"/api/v1/drivers/nearby/batch",
+	"/api/v1/drivers/search",
+	"/api/v1/drivers/by-plate/:plate",
+	"/api/v1/drivers/:id",
+	"/api/v1/drivers/:id/card",
+	"/api/v1/drivers/:id/location",
+	"/api/v1/drivers/:id/locations",
+	"/api/v1/drivers/:id/locations/batch",
+	"/api/v1/drivers/:id/status",
+	"/api/v1/drivers/:id/approve",
+	"/api/v1/drivers/:id/reject",
+	"/api/v1/drivers/:id/suspend",
+	"/api/v1/admin/drivers/:id/verify",
+	"/api/v1/admin/drivers/:id/license-overrides",
+	"/ws/drivers",
+}
+
+// Sandbox marks requests carrying one of the configured sandbox API keys so
+// handlers can serve them from the synthetic dataset instead of MongoDB.
+// Sandbox keys skip access tokens, so they are refused on routes the
+// dataset does not serve.
+func Sandbox(apiKeys []string) fiber.Handler {
+	allowed := make(map[string]struct{}, len(apiKeys))
+	for _, key := range apiKeys {
+		allowed[key] = struct{}{}
+	}
+
+	return func(c *fiber.Ctx) error {
+		key := c.Get(APIKeyHeader)
+		if _, ok := allowed[key]; ok && key != "" {
+			if !isSandboxPath(requestPath(c)) {
+				return errorResponse(c, http.StatusForbidden, "Sandbox API keys only work on driver routes", nil)
+			}
+			c.Locals(sandboxKeyLocal, key)
+			c.Set(sandboxHeaderKey, "true")
+		}
+		return c.Next()
+	}
+}
+
+func SandboxKey(c *fiber.Ctx) (string, bool) {
+	key, ok := c.Locals(sandboxKeyLocal).(string)
+	return key, ok && key != ""
+}
+
+func isSandboxPath(path string) bool {
+	segments := strings.Split(path, "/")
+	for _, route := range sandboxRoutes {
+		pattern := strings.Split(route, "/")
+		if len(pattern) != len(segments) {
+			continue
+		}
+		matched := true
+		for i, part := range pattern {
+			if !strings.HasPrefix(part, ":") && part != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
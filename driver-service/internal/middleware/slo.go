@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/slo"
+)
+
+// SLORecorder feeds every request's outcome and latency into the tracker.
+func SLORecorder(tracker *slo.Tracker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		// Errors are turned into responses by the app's error handler later,
+		// so derive the status code the client is going to see.
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		tracker.Record(normalizeRoute(c.Route().Path), status, time.Since(start))
+		return err
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/config"
+)
+
+const TenantHeader = "X-Tenant-ID"
+
+// Tenant records the calling tenant so repositories can resolve its
+// isolated database from the request context. Only the isolated tenants are
+// known: any other X-Tenant-ID gets 400 instead of falling back to the
+// shared database.
+func Tenant(tenantIDs []string) fiber.Handler {
+	known := make(map[string]bool, len(tenantIDs))
+	for _, tenantID := range tenantIDs {
+		known[tenantID] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		tenantID := c.Get(TenantHeader)
+		if tenantID == "" {
+			return c.Next()
+		}
+		if !known[tenantID] {
+			return errorResponse(c, http.StatusBadRequest, "Unknown tenant", []string{tenantID})
+		}
+		c.Locals(config.TenantKey, tenantID)
+		return c.Next()
+	}
+}
+
+// requestTenant returns the tenant Tenant recorded for the request, or "".
+func requestTenant(c *fiber.Ctx) string {
+	tenantID, _ := c.Locals(config.TenantKey).(string)
+	return tenantID
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/taxihub/driver-service/internal/validationstats"
+)
+
+const validationFailuresKey = "validation_failures"
+
+// NoteValidationFailure records why the request failed validation, for
+// ValidationStats to count once the handler returns.
+func NoteValidationFailure(c *fiber.Ctx, err error) {
+	c.Locals(validationFailuresKey, validationstats.Failures(err))
+}
+
+// ValidationStats counts the validation failures handlers note, per matched
+// route. Like Deprecation it runs after c.Next, once the route is known.
+func ValidationStats(tracker *validationstats.Tracker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		if failures, ok := c.Locals(validationFailuresKey).([]validationstats.Failure); ok {
+			tracker.Record(c.Method(), c.Route().Path, failures, time.Now())
+		}
+		return err
+	}
+}
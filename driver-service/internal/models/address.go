@@ -0,0 +1,9 @@
+package models
+
+type Address struct {
+	FormattedAddress string `json:"formatted_address"`
+	Neighbourhood    string `json:"neighbourhood,omitempty"`
+	District         string `json:"district,omitempty"`
+	City             string `json:"city,omitempty"`
+	Country          string `json:"country,omitempty"`
+}
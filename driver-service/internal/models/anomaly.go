@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	AnomalyImpossibleSpeed  = "impossible_speed"
+	AnomalyGPSTeleport      = "gps_teleport"
+	AnomalyContinuousOnline = "continuous_online"
+)
+
+const (
+	AnomalySeverityMedium = "medium"
+	AnomalySeverityHigh   = "high"
+)
+
+const (
+	AnomalyOpen      = "open"
+	AnomalyResolved  = "resolved"
+	AnomalyDismissed = "dismissed"
+)
+
+// LocationSample is one accepted location update, fed to the anomaly
+// analyzer and the GPS quality tracker. AccuracyM is the reported accuracy
+// radius in meters, zero when the app did not send one.
+type LocationSample struct {
+	TenantID   string
+	DriverID   primitive.ObjectID
+	FleetID    string
+	Location   Location
+	RecordedAt time.Time
+	AccuracyM  float64
+}
+
+type DriverAnomaly struct {
+	ID         primitive.ObjectID     `json:"id" bson:"_id"`
+	DriverID   primitive.ObjectID     `json:"driver_id" bson:"driver_id"`
+	FleetID    string                 `json:"fleet_id,omitempty" bson:"fleet_id,omitempty"`
+	Kind       string                 `json:"kind" bson:"kind"`
+	Severity   string                 `json:"severity" bson:"severity"`
+	Details    map[string]interface{} `json:"details,omitempty" bson:"details,omitempty"`
+	Status     string                 `json:"status" bson:"status"`
+	ReviewNote string                 `json:"review_note,omitempty" bson:"review_note,omitempty"`
+	ReviewedBy string                 `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time             `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+	DetectedAt time.Time              `json:"detected_at" bson:"detected_at"`
+}
+
+type ReviewAnomalyRequest struct {
+	Status     string `json:"status" validate:"required,oneof=resolved dismissed"`
+	Note       string `json:"note" validate:"max=500"`
+	ReviewedBy string `json:"reviewed_by" validate:"max=100"`
+}
+
+func (r *ReviewAnomalyRequest) Validate() error {
+	return newValidator().Struct(r)
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DriverCredential is a driver's login password. Only the bcrypt hash is
+// stored; PasswordChangedAt invalidates refresh tokens issued before it.
+type DriverCredential struct {
+	ID                primitive.ObjectID `json:"id" bson:"_id"`
+	DriverID          primitive.ObjectID `json:"driver_id" bson:"driver_id"`
+	PasswordHash      string             `json:"-" bson:"password_hash"`
+	PasswordChangedAt time.Time          `json:"password_changed_at" bson:"password_changed_at"`
+	CreatedAt         time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// LoginRequest logs in a driver by plate, or a dispatcher or admin by
+// username.
+type LoginRequest struct {
+	Plate    string `json:"plate" validate:"required_without=Username,excluded_with=Username"`
+	Username string `json:"username" validate:"required_without=Plate,max=100"`
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+func (r *LoginRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+func (r *RefreshTokenRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+// SetPasswordRequest sets a driver's login password. Drivers changing their
+// own password must give the current one; admins may omit it.
+type SetPasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"max=72"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=72"`
+}
+
+func (r *SetPasswordRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+type TokenResponse struct {
+	AccessToken      string   `json:"access_token"`
+	RefreshToken     string   `json:"refresh_token"`
+	TokenType        string   `json:"token_type"`
+	ExpiresIn        int64    `json:"expires_in"`
+	RefreshExpiresIn int64    `json:"refresh_expires_in"`
+	Role             string   `json:"role"`
+	Subject          string   `json:"subject"`
+	FleetID          string   `json:"fleet_id,omitempty"`
+	Capabilities     []string `json:"capabilities,omitempty"`
+}
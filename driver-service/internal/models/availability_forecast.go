@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Bounds of an availability forecast query. Zones are geohash cells; 5
+// characters is about 5km x 5km.
+const (
+	MinForecastZonePrecision     = 4
+	MaxForecastZonePrecision     = 6
+	DefaultForecastZonePrecision = 5
+	MaxForecastZones             = 20
+	DefaultForecastHours         = 12
+	MaxForecastHours             = 48
+	DefaultForecastWeeks         = 4
+	MaxForecastWeeks             = 8
+)
+
+// AvailabilityForecastRequest asks for the drivers expected in each zone for
+// the next Hours hours, learned from the last Weeks weeks.
+type AvailabilityForecastRequest struct {
+	Zones []string
+	Hours int
+	Weeks int
+}
+
+// ZoneDriverCount is how many drivers are in a zone right now. Online drivers
+// are available or busy.
+type ZoneDriverCount struct {
+	Online    int `json:"online"`
+	Available int `json:"available"`
+}
+
+// HourlyDriverCount is how many distinct drivers sent a location from a zone
+// during the hour starting at Hour.
+type HourlyDriverCount struct {
+	Hour    time.Time `bson:"_id"`
+	Drivers int       `bson:"drivers"`
+}
+
+// HourlyAvailability forecasts one hour of a zone. Historical is the mean
+// number of drivers seen in the same hour of the week over the lookback;
+// Expected corrects it by how far the zone is from its usual level now.
+type HourlyAvailability struct {
+	StartsAt   time.Time `json:"starts_at"`
+	Historical float64   `json:"historical"`
+	Expected   float64   `json:"expected"`
+}
+
+type ZoneAvailabilityForecast struct {
+	Zone    string               `json:"zone"`
+	Center  Location             `json:"center"`
+	Current ZoneDriverCount      `json:"current"`
+	Hours   []HourlyAvailability `json:"hours"`
+}
+
+type AvailabilityForecast struct {
+	GeneratedAt time.Time                  `json:"generated_at"`
+	Weeks       int                        `json:"weeks"`
+	Zones       []ZoneAvailabilityForecast `json:"zones"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DriverBoostUsage counts the assignments a driver won through the
+// cold-start boost.
+type DriverBoostUsage struct {
+	DriverID   primitive.ObjectID `json:"driver_id" bson:"_id"`
+	Used       int                `json:"used" bson:"used"`
+	LastUsedAt *time.Time         `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+}
+
+type ColdStartBoostStatus struct {
+	DriverID      string    `json:"driver_id"`
+	ActivatedAt   time.Time `json:"activated_at"`
+	EligibleUntil time.Time `json:"eligible_until"`
+	Used          int       `json:"used"`
+	Cap           int       `json:"cap"`
+	Active        bool      `json:"active"`
+}
+
+// ActivatedAt is when the driver became dispatchable: verification when
+// it happened, registration otherwise.
+func (d *Driver) ActivatedAt() time.Time {
+	if d.VerifiedAt != nil {
+		return *d.VerifiedAt
+	}
+	return d.CreatedAt
+}
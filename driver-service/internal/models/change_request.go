@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ChangeRequestPending  = "pending"
+	ChangeRequestApproved = "approved"
+	ChangeRequestRejected = "rejected"
+)
+
+// RegulatedFields are the driver profile fields that only change after an
+// admin approves the edit.
+type RegulatedFields struct {
+	Plate    *string `json:"plate,omitempty" bson:"plate,omitempty" validate:"omitempty,turkish_plate"`
+	TaxiType *string `json:"taxi_type,omitempty" bson:"taxi_type,omitempty" validate:"omitempty,oneof=sari turkuaz siyah"`
+}
+
+func (f RegulatedFields) IsEmpty() bool {
+	return f.Plate == nil && f.TaxiType == nil
+}
+
+type DriverChangeRequest struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	DriverID   primitive.ObjectID `json:"driver_id" bson:"driver_id"`
+	Changes    RegulatedFields    `json:"changes" bson:"changes"`
+	Previous   RegulatedFields    `json:"previous" bson:"previous"`
+	Reason     string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	Status     string             `json:"status" bson:"status"`
+	ReviewNote string             `json:"review_note,omitempty" bson:"review_note,omitempty"`
+	ReviewedBy string             `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time         `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+}
+
+type SubmitChangeRequest struct {
+	RegulatedFields
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+func (r *SubmitChangeRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+type ReviewChangeRequest struct {
+	Note       string `json:"note" validate:"max=500"`
+	ReviewedBy string `json:"reviewed_by" validate:"max=100"`
+}
+
+func (r *ReviewChangeRequest) Validate() error {
+	return newValidator().Struct(r)
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Chaos fault kinds. Faults only exist outside production.
+const (
+	// ChaosMongoLatency delays every MongoDB command issued while serving a
+	// selected request.
+	ChaosMongoLatency = "mongo_latency"
+	// ChaosDropAlerts silently drops outgoing alert events.
+	ChaosDropAlerts = "drop_alerts"
+)
+
+func IsValidChaosFault(kind string) bool {
+	switch kind {
+	case ChaosMongoLatency, ChaosDropAlerts:
+		return true
+	default:
+		return false
+	}
+}
+
+type ChaosFault struct {
+	Kind        string    `json:"kind"`
+	Percentage  int       `json:"percentage"`
+	LatencyMs   int       `json:"latency_ms,omitempty"`
+	RoutePrefix string    `json:"route_prefix,omitempty"`
+	Injected    int64     `json:"injected"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type SetChaosFaultRequest struct {
+	Percentage      int    `json:"percentage" validate:"required,min=1,max=100"`
+	LatencyMs       int    `json:"latency_ms" validate:"min=0,max=60000"`
+	RoutePrefix     string `json:"route_prefix" validate:"omitempty,startswith=/"`
+	DurationSeconds int    `json:"duration_seconds" validate:"min=0,max=86400"`
+}
+
+func (r *SetChaosFaultRequest) Validate() error {
+	return newValidator().Struct(r)
+}
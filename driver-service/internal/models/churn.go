@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reasons a driver went inactive, where the service knows one.
+const (
+	ChurnReasonSuspended      = "suspended"
+	ChurnReasonUnverified     = "unverified"
+	ChurnReasonLicenseExpired = "license_expired"
+)
+
+// Bounds of a churn report and of an alert list. Zones are geohash cells of
+// the drivers' last known location.
+const (
+	MinChurnZonePrecision     = 2
+	MaxChurnZonePrecision     = 6
+	DefaultChurnZonePrecision = 5
+	DefaultChurnAlertLimit    = 50
+	MaxChurnAlertLimit        = 200
+)
+
+// DriverActivity is when a driver last sent a location within a window.
+type DriverActivity struct {
+	DriverID primitive.ObjectID `bson:"_id"`
+	LastSeen time.Time          `bson:"last_seen"`
+}
+
+// ChurnReport compares the drivers active in the week up to To with those
+// active the week before, by fleet and zone. A driver is active in a week
+// if they sent a location during it.
+type ChurnReport struct {
+	GeneratedAt   time.Time       `json:"generated_at"`
+	FleetID       string          `json:"fleet_id,omitempty"`
+	ZonePrecision int             `json:"zone_precision"`
+	PreviousFrom  time.Time       `json:"previous_from"`
+	CurrentFrom   time.Time       `json:"current_from"`
+	To            time.Time       `json:"to"`
+	Groups        []ChurnGroup    `json:"groups"`
+	Drivers       []ChurnedDriver `json:"drivers"`
+}
+
+// ChurnGroup is one fleet and zone of a churn report. Churned drivers were
+// active the previous week and not this one; joined drivers the other way
+// round. ChangeRate is the change in active drivers relative to the
+// previous week, -0.25 for a quarter fewer.
+type ChurnGroup struct {
+	FleetID    string  `json:"fleet_id,omitempty"`
+	Zone       string  `json:"zone"`
+	Previous   int     `json:"previous"`
+	Current    int     `json:"current"`
+	Churned    int     `json:"churned"`
+	Joined     int     `json:"joined"`
+	ChangeRate float64 `json:"change_rate"`
+}
+
+// ChurnedDriver is a driver who was active the previous week and not this
+// one. Reason is empty when the service cannot tell why.
+type ChurnedDriver struct {
+	ID           string    `json:"id"`
+	FirstName    string    `json:"first_name"`
+	LastName     string    `json:"last_name"`
+	FleetID      string    `json:"fleet_id,omitempty"`
+	Zone         string    `json:"zone"`
+	LastSeen     time.Time `json:"last_seen"`
+	Status       string    `json:"status,omitempty"`
+	ReviewStatus string    `json:"review_status,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+}
+
+// ChurnAlert records a fleet and zone whose active drivers dropped by more
+// than the threshold week over week. Each fleet and zone is alerted at most
+// once per calendar week, starting WeekOf (Monday, UTC).
+type ChurnAlert struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	FleetID    string             `json:"fleet_id" bson:"fleet_id"`
+	Zone       string             `json:"zone" bson:"zone"`
+	WeekOf     time.Time          `json:"week_of" bson:"week_of"`
+	Severity   string             `json:"severity" bson:"severity"`
+	Previous   int                `json:"previous" bson:"previous"`
+	Current    int                `json:"current" bson:"current"`
+	Churned    int                `json:"churned" bson:"churned"`
+	ChangeRate float64            `json:"change_rate" bson:"change_rate"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Types of a fleet's custom driver field.
+const (
+	CustomFieldString = "string"
+	CustomFieldNumber = "number"
+	CustomFieldDate   = "date"
+	CustomFieldEnum   = "enum"
+)
+
+const (
+	// CustomFieldDateLayout is how date values are written and stored, so
+	// they compare and sort as strings.
+	CustomFieldDateLayout = "2006-01-02"
+	// MaxCustomFieldsPerFleet caps the definitions of one fleet.
+	MaxCustomFieldsPerFleet = 50
+	// MaxCustomFieldStringLength caps string values.
+	MaxCustomFieldStringLength = 500
+)
+
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// CustomFieldDefinition is one field a fleet records on its drivers beyond
+// the built-in profile. Values are stored on the driver under the Key.
+type CustomFieldDefinition struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	FleetID   string             `json:"fleet_id" bson:"fleet_id"`
+	Key       string             `json:"key" bson:"key"`
+	Label     string             `json:"label,omitempty" bson:"label,omitempty"`
+	Type      string             `json:"type" bson:"type"`
+	Options   []string           `json:"options,omitempty" bson:"options,omitempty"`
+	Required  bool               `json:"required" bson:"required"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// CreateCustomFieldRequest defines a custom field. The type cannot change
+// later, since stored values would no longer match it.
+type CreateCustomFieldRequest struct {
+	Key      string   `json:"key" validate:"required,max=40,field_key"`
+	Label    string   `json:"label" validate:"max=100"`
+	Type     string   `json:"type" validate:"required,oneof=string number date enum"`
+	Options  []string `json:"options" validate:"required_if=Type enum,excluded_unless=Type enum,omitempty,max=50,unique,dive,required,max=100"`
+	Required bool     `json:"required"`
+}
+
+func (r *CreateCustomFieldRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+func FieldKeyValidator(fl validator.FieldLevel) bool {
+	return customFieldKeyPattern.MatchString(fl.Field().String())
+}
+
+// NormalizeCustomFields checks values against the fleet's definitions and
+// merges them into current, returning the values to store. A nil value
+// removes the field. Numbers become float64 and dates are rewritten in
+// CustomFieldDateLayout. Required fields must have a value afterwards.
+func NormalizeCustomFields(definitions []CustomFieldDefinition, current, values map[string]interface{}) (map[string]interface{}, error) {
+	byKey := make(map[string]*CustomFieldDefinition, len(definitions))
+	for i := range definitions {
+		byKey[definitions[i].Key] = &definitions[i]
+	}
+
+	merged := make(map[string]interface{}, len(current)+len(values))
+	for key, value := range current {
+		if _, ok := byKey[key]; ok {
+			merged[key] = value
+		}
+	}
+	for _, key := range sortedKeys(values) {
+		definition, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("custom_fields.%s is not defined for this fleet", key)
+		}
+		if values[key] == nil {
+			delete(merged, key)
+			continue
+		}
+		value, err := definition.normalize(values[key])
+		if err != nil {
+			return nil, fmt.Errorf("custom_fields.%s %s", key, err)
+		}
+		merged[key] = value
+	}
+
+	for _, definition := range definitions {
+		if _, ok := merged[definition.Key]; definition.Required && !ok {
+			return nil, fmt.Errorf("custom_fields.%s is required", definition.Key)
+		}
+	}
+	if len(merged) == 0 {
+		return nil, nil
+	}
+	return merged, nil
+}
+
+// ParseCustomFieldFilter converts the raw query values of a custom field
+// filter to the fields' types, so they compare equal to stored values.
+func ParseCustomFieldFilter(definitions []CustomFieldDefinition, raw map[string]interface{}) (map[string]interface{}, error) {
+	byKey := make(map[string]*CustomFieldDefinition, len(definitions))
+	for i := range definitions {
+		byKey[definitions[i].Key] = &definitions[i]
+	}
+
+	parsed := make(map[string]interface{}, len(raw))
+	for _, key := range sortedKeys(raw) {
+		definition, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("field.%s is not defined for this fleet", key)
+		}
+		value := raw[key]
+		if text, ok := value.(string); ok && definition.Type == CustomFieldNumber {
+			number, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+			if err != nil {
+				return nil, fmt.Errorf("field.%s must be a number", key)
+			}
+			value = number
+		}
+		normalized, err := definition.normalize(value)
+		if err != nil {
+			return nil, fmt.Errorf("field.%s %s", key, err)
+		}
+		parsed[key] = normalized
+	}
+	return parsed, nil
+}
+
+// normalize checks a value against the definition's type and returns it in
+// its stored form; the error reads after the field name.
+func (d *CustomFieldDefinition) normalize(value interface{}) (interface{}, error) {
+	switch d.Type {
+	case CustomFieldNumber:
+		var number float64
+		switch v := value.(type) {
+		case float64:
+			number = v
+		case int:
+			number = float64(v)
+		case int64:
+			number = float64(v)
+		default:
+			return nil, errors.New("must be a number")
+		}
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, errors.New("must be a finite number")
+		}
+		return number, nil
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return nil, errors.New("must be a string")
+	}
+	switch d.Type {
+	case CustomFieldDate:
+		date, err := time.Parse(CustomFieldDateLayout, strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("must be a date in %s format", CustomFieldDateLayout)
+		}
+		return date.Format(CustomFieldDateLayout), nil
+	case CustomFieldEnum:
+		for _, option := range d.Options {
+			if text == option {
+				return text, nil
+			}
+		}
+		return nil, fmt.Errorf("must be one of: %s", strings.Join(d.Options, " "))
+	default:
+		if len(text) > MaxCustomFieldStringLength {
+			return nil, fmt.Errorf("must be at most %d characters", MaxCustomFieldStringLength)
+		}
+		return text, nil
+	}
+}
+
+// sortedKeys orders the keys so the first error reported is stable.
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type DeletionReport struct {
+	DriverID      string           `json:"driver_id"`
+	Archived      map[string]int64 `json:"archived"`
+	Transactional bool             `json:"transactional"`
+	DeletedAt     time.Time        `json:"deleted_at"`
+}
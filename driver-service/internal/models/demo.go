@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// Bounds of a demo scenario.
+const (
+	DefaultDemoSeed    = 1
+	DefaultDemoDrivers = 30
+	MaxDemoDrivers     = 200
+)
+
+// DemoScenario is the demo dataset last seeded into a database. Its routes
+// are driven from SeededAt on, so every replica moves the drivers to the
+// same spot at the same time.
+type DemoScenario struct {
+	ID       string      `json:"-" bson:"_id"`
+	Seed     int64       `json:"seed" bson:"seed"`
+	SeededAt time.Time   `json:"seeded_at" bson:"seeded_at"`
+	Routes   []DemoRoute `json:"routes" bson:"routes"`
+}
+
+// DemoRoute is the closed loop a demo driver drives at SpeedKmh, starting
+// OffsetKm into the loop.
+type DemoRoute struct {
+	DriverID  string     `json:"driver_id" bson:"driver_id"`
+	Waypoints []Location `json:"waypoints" bson:"waypoints"`
+	SpeedKmh  float64    `json:"speed_kmh" bson:"speed_kmh"`
+	OffsetKm  float64    `json:"offset_km" bson:"offset_km"`
+}
+
+// LengthKm returns the length of the loop, back to the first waypoint.
+func (r *DemoRoute) LengthKm() float64 {
+	length := 0.0
+	for i := range r.Waypoints {
+		length += r.Waypoints[i].DistanceKm(r.Waypoints[(i+1)%len(r.Waypoints)])
+	}
+	return length
+}
+
+// PositionAt returns where the driver is after driving for elapsed,
+// interpolating linearly between waypoints.
+func (r *DemoRoute) PositionAt(elapsed time.Duration) Location {
+	if len(r.Waypoints) == 0 {
+		return Location{}
+	}
+	length := r.LengthKm()
+	if length == 0 {
+		return r.Waypoints[0]
+	}
+
+	remaining := r.OffsetKm + r.SpeedKmh*elapsed.Hours()
+	for remaining >= length {
+		remaining -= length
+	}
+	for i := range r.Waypoints {
+		from, to := r.Waypoints[i], r.Waypoints[(i+1)%len(r.Waypoints)]
+		leg := from.DistanceKm(to)
+		if remaining <= leg && leg > 0 {
+			share := remaining / leg
+			return Location{
+				Lat: from.Lat + (to.Lat-from.Lat)*share,
+				Lon: from.Lon + (to.Lon-from.Lon)*share,
+			}
+		}
+		remaining -= leg
+	}
+	return r.Waypoints[0]
+}
+
+// SeedDemoRequest picks the scenario to seed. The same seed and driver
+// count always give the same drivers, routes and rides.
+type SeedDemoRequest struct {
+	Seed    int64 `json:"seed"`
+	Drivers int   `json:"drivers" validate:"omitempty,min=1,max=200"`
+}
+
+func (r *SeedDemoRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+// DemoSeedResult tells what seeding cleared and created.
+type DemoSeedResult struct {
+	Seed               int64     `json:"seed"`
+	SeededAt           time.Time `json:"seeded_at"`
+	ClearedCollections []string  `json:"cleared_collections"`
+	Drivers            int       `json:"drivers"`
+	Trips              int       `json:"trips"`
+	Ratings            int       `json:"ratings"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Device token scopes. Tokens are bound to one driver and can only act on it.
+const (
+	ScopeLocationWrite = "location:write"
+)
+
+// DeviceTokenPrefix marks device tokens so they are easy to spot in logs and
+// secret scanners.
+const DeviceTokenPrefix = "dvt_"
+
+// DeviceToken is a long-lived credential for a vehicle-mounted device. Only a
+// hash of the secret is stored. After a rotation the previous secret keeps
+// working until PreviousExpiresAt so devices can pick up the new one.
+type DeviceToken struct {
+	ID                primitive.ObjectID `json:"id" bson:"_id"`
+	DriverID          primitive.ObjectID `json:"driver_id" bson:"driver_id"`
+	Name              string             `json:"name" bson:"name"`
+	Scopes            []string           `json:"scopes" bson:"scopes"`
+	Hint              string             `json:"hint" bson:"hint"`
+	TokenHash         string             `json:"-" bson:"token_hash"`
+	PreviousTokenHash string             `json:"-" bson:"previous_token_hash,omitempty"`
+	PreviousExpiresAt *time.Time         `json:"previous_expires_at,omitempty" bson:"previous_expires_at,omitempty"`
+	ExpiresAt         *time.Time         `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	LastUsedAt        *time.Time         `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	RotatedAt         *time.Time         `json:"rotated_at,omitempty" bson:"rotated_at,omitempty"`
+	RevokedAt         *time.Time         `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	CreatedAt         time.Time          `json:"created_at" bson:"created_at"`
+}
+
+func (t *DeviceToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type IssueDeviceTokenRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"omitempty,dive,oneof=location:write"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"min=0,max=3650"`
+}
+
+func (r *IssueDeviceTokenRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+type RotateDeviceTokenRequest struct {
+	// GraceSeconds keeps the old secret valid while the device is updated.
+	GraceSeconds int `json:"grace_seconds" validate:"min=0,max=604800"`
+}
+
+func (r *RotateDeviceTokenRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+// IssuedDeviceTokenResponse is the only response that carries the secret.
+type IssuedDeviceTokenResponse struct {
+	Token       string       `json:"token"`
+	DeviceToken *DeviceToken `json:"device_token"`
+}
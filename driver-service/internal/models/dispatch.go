@@ -0,0 +1,41 @@
+package models
+
+type AssignDriverRequest struct {
+	Lat      float64 `json:"lat" validate:"required,min=-90,max=90"`
+	Lon      float64 `json:"lon" validate:"required,min=-180,max=180"`
+	TaxiType string  `json:"taxi_type" validate:"omitempty,oneof=sari turkuaz siyah"`
+	FleetID  string  `json:"fleet_id" validate:"omitempty,max=64"`
+
+	// Language is the rider's preferred language. LanguageMode decides
+	// whether it is only preferred (the default) or required.
+	Language     string `json:"language" validate:"omitempty,locale"`
+	LanguageMode string `json:"language_mode" validate:"omitempty,oneof=prefer require"`
+
+	// ETASensitive marks pickups where a wrong ETA hurts, such as immediate
+	// rides; drivers with a poor GPS signal are then considered last.
+	ETASensitive bool `json:"eta_sensitive"`
+}
+
+func (r *AssignDriverRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+type AssignmentResponse struct {
+	Strategy   string                        `json:"strategy"`
+	FleetID    string                        `json:"fleet_id,omitempty"`
+	Boosted    bool                          `json:"boosted,omitempty"`
+	Driver     *DriverWithDistanceResponse   `json:"driver"`
+	Candidates []*DriverWithDistanceResponse `json:"candidates"`
+
+	// LanguageMatched tells whether the assigned driver speaks the requested
+	// language; it is omitted when no language was requested.
+	LanguageMatched *bool `json:"language_matched,omitempty"`
+
+	// GPSDeprioritized counts the candidates moved back for a poor GPS
+	// signal.
+	GPSDeprioritized int `json:"gps_deprioritized,omitempty"`
+
+	// OfferSent tells whether the assigned driver was sent an offer over
+	// /ws/drivers.
+	OfferSent bool `json:"offer_sent,omitempty"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DispatchPause stops dispatch inside a circular zone for a time window,
+// e.g. for a parade or a road closure.
+type DispatchPause struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	Name      string             `json:"name" bson:"name"`
+	Reason    string             `json:"reason" bson:"reason"`
+	Center    Location           `json:"center" bson:"center"`
+	RadiusKm  float64            `json:"radius_km" bson:"radius_km"`
+	StartsAt  time.Time          `json:"starts_at" bson:"starts_at"`
+	EndsAt    time.Time          `json:"ends_at" bson:"ends_at"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// Contains reports whether the point lies inside the paused zone.
+func (p *DispatchPause) Contains(point Location) bool {
+	return p.Center.DistanceKm(point) <= p.RadiusKm
+}
+
+func (p *DispatchPause) ActiveAt(at time.Time) bool {
+	return !at.Before(p.StartsAt) && at.Before(p.EndsAt)
+}
+
+type CreateDispatchPauseRequest struct {
+	Name     string  `json:"name" validate:"required,max=100"`
+	Reason   string  `json:"reason" validate:"required,max=500"`
+	Lat      float64 `json:"lat" validate:"required,min=-90,max=90"`
+	Lon      float64 `json:"lon" validate:"required,min=-180,max=180"`
+	RadiusKm float64 `json:"radius_km" validate:"required,gt=0,max=50"`
+	// StartsAt defaults to now.
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   time.Time  `json:"ends_at" validate:"required"`
+}
+
+func (r *CreateDispatchPauseRequest) Validate() error {
+	return newValidator().Struct(r)
+}
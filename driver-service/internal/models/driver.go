@@ -6,9 +6,11 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Location is a point on the map. The API keeps the flat {lat, lon} shape;
+// in MongoDB it is stored as a GeoJSON Point (see MarshalBSON).
 type Location struct {
-	Lat float64 `json:"lat" bson:"lat"`
-	Lon float64 `json:"lon" bson:"lon"`
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
 }
 
 type Driver struct {
@@ -16,20 +18,192 @@ type Driver struct {
 	FirstName string             `json:"first_name" bson:"first_name"`
 	LastName  string             `json:"last_name" bson:"last_name"`
 	Plate     string             `json:"plate" bson:"plate"`
-	TaxiType  string             `json:"taxi_type" bson:"taxi_type"`
-	CarBrand  string             `json:"car_brand" bson:"car_brand"`
-	CarModel  string             `json:"car_model" bson:"car_model"`
-	Location  Location           `json:"location" bson:"location"`
-	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+	// PlateKey is the plate's spelling-independent PlateKey, which plate
+	// lookups and uniqueness go by.
+	PlateKey      string                        `json:"-" bson:"plate_key,omitempty"`
+	TaxiType      string                        `json:"taxi_type" bson:"taxi_type"`
+	FleetID       string                        `json:"fleet_id,omitempty" bson:"fleet_id,omitempty"`
+	CarBrand      string                        `json:"car_brand" bson:"car_brand"`
+	CarModel      string                        `json:"car_model" bson:"car_model"`
+	CarColor      string                        `json:"car_color,omitempty" bson:"car_color,omitempty"`
+	Bio           string                        `json:"bio,omitempty" bson:"bio,omitempty"`
+	Location      Location                      `json:"location" bson:"location"`
+	Geohash       string                        `json:"geohash,omitempty" bson:"geohash,omitempty"`
+	TraveledKm    float64                       `json:"traveled_km" bson:"traveled_km"`
+	TaxInfo       *TaxInfo                      `json:"tax_info,omitempty" bson:"tax_info,omitempty"`
+	PhotoURL      string                        `json:"photo_url,omitempty" bson:"photo_url,omitempty"`
+	Verified      bool                          `json:"verified" bson:"verified"`
+	VerifiedAt    *time.Time                    `json:"verified_at,omitempty" bson:"verified_at,omitempty"`
+	Localizations map[string]DriverLocalization `json:"localizations,omitempty" bson:"localizations,omitempty"`
+	CreatedAt     time.Time                     `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time                     `json:"updated_at" bson:"updated_at"`
+
+	// LicenseClasses are the license classes the driver holds; empty means
+	// they have not been recorded.
+	LicenseClasses []string `json:"license_classes,omitempty" bson:"license_classes,omitempty"`
+
+	// Languages are the primary language tags the driver speaks, e.g. "en".
+	Languages []string `json:"languages,omitempty" bson:"languages,omitempty"`
+
+	// Status is the driver's availability. Drivers stored before statuses
+	// existed have none and count as available.
+	Status          string     `json:"status,omitempty" bson:"status,omitempty"`
+	StatusUpdatedAt *time.Time `json:"status_updated_at,omitempty" bson:"status_updated_at,omitempty"`
+
+	// ReviewStatus is where the driver is in the admin approval workflow.
+	// Drivers stored before the workflow existed have none and count as
+	// approved. ReviewNote is the admin's note on the last decision.
+	ReviewStatus string     `json:"review_status,omitempty" bson:"review_status,omitempty"`
+	ReviewNote   string     `json:"review_note,omitempty" bson:"review_note,omitempty"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+
+	// LocationRecordedAt is when the driver app took the current location
+	// fix. Buffered points older than it only go to the location history.
+	LocationRecordedAt *time.Time `json:"location_recorded_at,omitempty" bson:"location_recorded_at,omitempty"`
+
+	// Email is where transactional emails to the driver are sent.
+	Email string `json:"email,omitempty" bson:"email,omitempty"`
+
+	// The rating aggregates are only changed together with a new rating;
+	// AverageRating is RatingSum / RatingCount, rounded to two decimals.
+	AverageRating float64 `json:"average_rating,omitempty" bson:"average_rating,omitempty"`
+	RatingCount   int     `json:"rating_count,omitempty" bson:"rating_count,omitempty"`
+	RatingSum     int     `json:"-" bson:"rating_sum,omitempty"`
+
+	// CustomFields holds values of the fleet's custom fields by key, in the
+	// form NormalizeCustomFields stores them.
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty" bson:"custom_fields,omitempty"`
+
+	// Onboarding records when the driver first reached each onboarding
+	// step. Updates leave it alone; steps are only ever added.
+	Onboarding map[string]time.Time `json:"onboarding,omitempty" bson:"onboarding,omitempty"`
+
+	// SchemaVersion is the shape the document was stored in. Reads migrate
+	// older documents to CurrentDriverSchemaVersion.
+	SchemaVersion int `json:"-" bson:"schema_version"`
 }
 
+// GeohashPrecision is the length of the geohash stored with each driver
+// (~5m cells). Any coarser cell is a prefix of it.
+const GeohashPrecision = 9
+
 const (
 	TaxiTypeSari    = "sari"
 	TaxiTypeTurkuaz = "turkuaz"
 	TaxiTypeSiyah   = "siyah"
 )
 
+const (
+	DriverStatusAvailable = "available"
+	DriverStatusBusy      = "busy"
+	DriverStatusOffline   = "offline"
+)
+
+// DriverField names a driver field that DriverRepository.Update writes. The
+// value is the field's BSON name. Plate also writes the plate key, Location
+// the geohash and Verified the verification time.
+type DriverField string
+
+const (
+	DriverFieldFirstName          DriverField = "first_name"
+	DriverFieldLastName           DriverField = "last_name"
+	DriverFieldPlate              DriverField = "plate"
+	DriverFieldTaxiType           DriverField = "taxi_type"
+	DriverFieldFleetID            DriverField = "fleet_id"
+	DriverFieldCarBrand           DriverField = "car_brand"
+	DriverFieldCarModel           DriverField = "car_model"
+	DriverFieldCarColor           DriverField = "car_color"
+	DriverFieldBio                DriverField = "bio"
+	DriverFieldPhotoURL           DriverField = "photo_url"
+	DriverFieldVerified           DriverField = "verified"
+	DriverFieldLocation           DriverField = "location"
+	DriverFieldTraveledKm         DriverField = "traveled_km"
+	DriverFieldTaxInfo            DriverField = "tax_info"
+	DriverFieldLocalizations      DriverField = "localizations"
+	DriverFieldLicenseClasses     DriverField = "license_classes"
+	DriverFieldLanguages          DriverField = "languages"
+	DriverFieldLocationRecordedAt DriverField = "location_recorded_at"
+	DriverFieldEmail              DriverField = "email"
+	DriverFieldCustomFields       DriverField = "custom_fields"
+)
+
+func IsValidDriverStatus(status string) bool {
+	switch status {
+	case DriverStatusAvailable, DriverStatusBusy, DriverStatusOffline:
+		return true
+	default:
+		return false
+	}
+}
+
+// EffectiveStatus reports a missing status as available.
+func (d *Driver) EffectiveStatus() string {
+	if d.Status == "" {
+		return DriverStatusAvailable
+	}
+	return d.Status
+}
+
+func (d *Driver) IsAvailable() bool {
+	return d.EffectiveStatus() == DriverStatusAvailable
+}
+
+// Review statuses of a driver. New drivers start pending; only approved
+// drivers are offered rides.
+const (
+	DriverReviewPending   = "pending_review"
+	DriverReviewApproved  = "approved"
+	DriverReviewRejected  = "rejected"
+	DriverReviewSuspended = "suspended"
+)
+
+// driverReviewTransitions lists the statuses each review status may move to.
+// A suspended driver can be approved again; a rejected one has to register
+// anew.
+var driverReviewTransitions = map[string][]string{
+	DriverReviewPending:   {DriverReviewApproved, DriverReviewRejected},
+	DriverReviewApproved:  {DriverReviewSuspended},
+	DriverReviewSuspended: {DriverReviewApproved},
+}
+
+func IsValidDriverReviewStatus(status string) bool {
+	switch status {
+	case DriverReviewPending, DriverReviewApproved, DriverReviewRejected, DriverReviewSuspended:
+		return true
+	default:
+		return false
+	}
+}
+
+// UnapprovedDriverReviewStatuses are the review statuses that keep a driver
+// out of nearby searches and dispatch.
+func UnapprovedDriverReviewStatuses() []string {
+	return []string{DriverReviewPending, DriverReviewRejected, DriverReviewSuspended}
+}
+
+// CanTransitionReview reports whether a driver may move from one review
+// status to another.
+func CanTransitionReview(from, to string) bool {
+	for _, next := range driverReviewTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveReviewStatus reports a missing review status as approved.
+func (d *Driver) EffectiveReviewStatus() string {
+	if d.ReviewStatus == "" {
+		return DriverReviewApproved
+	}
+	return d.ReviewStatus
+}
+
+func (d *Driver) IsApproved() bool {
+	return d.EffectiveReviewStatus() == DriverReviewApproved
+}
+
 func IsValidTaxiType(taxiType string) bool {
 	switch taxiType {
 	case TaxiTypeSari, TaxiTypeTurkuaz, TaxiTypeSiyah:
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Driver import statuses. A staged import holds a preview and expires unless
+// committed; committing imports are being applied.
+const (
+	DriverImportStaged     = "staged"
+	DriverImportCommitting = "committing"
+	DriverImportCommitted  = "committed"
+)
+
+// Import row actions, decided when the file is staged.
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged"
+	ImportActionInvalid   = "invalid"
+	// ImportActionDuplicate marks a row whose plate an earlier row of the
+	// same file already has.
+	ImportActionDuplicate = "duplicate"
+)
+
+// Import row results, set when the import is committed.
+const (
+	ImportResultCreated = "created"
+	ImportResultUpdated = "updated"
+	ImportResultSkipped = "skipped"
+	ImportResultFailed  = "failed"
+)
+
+// FieldChange is a difference between an import row and the registered
+// driver. Tax identity numbers are masked.
+type FieldChange struct {
+	Field string `json:"field" bson:"field"`
+	From  string `json:"from" bson:"from"`
+	To    string `json:"to" bson:"to"`
+}
+
+type DriverImportRow struct {
+	Line     int           `json:"line" bson:"line"`
+	Plate    string        `json:"plate" bson:"plate"`
+	Action   string        `json:"action" bson:"action"`
+	DriverID string        `json:"driver_id,omitempty" bson:"driver_id,omitempty"`
+	Changes  []FieldChange `json:"changes,omitempty" bson:"changes,omitempty"`
+	Issues   []string      `json:"issues,omitempty" bson:"issues,omitempty"`
+	// Request is the registration read from the row, kept to be applied on
+	// commit. It holds tax details and is never returned.
+	Request *CreateDriverRequest `json:"-" bson:"request,omitempty"`
+
+	Result string `json:"result,omitempty" bson:"result,omitempty"`
+	Error  string `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+type DriverImportSummary struct {
+	Rows      int `json:"rows" bson:"rows"`
+	Create    int `json:"create" bson:"create"`
+	Update    int `json:"update" bson:"update"`
+	Unchanged int `json:"unchanged" bson:"unchanged"`
+	Invalid   int `json:"invalid" bson:"invalid"`
+	Duplicate int `json:"duplicate" bson:"duplicate"`
+
+	Created int `json:"created" bson:"created"`
+	Updated int `json:"updated" bson:"updated"`
+	Failed  int `json:"failed" bson:"failed"`
+}
+
+// DriverImport is a legacy roster import, from its staged preview to its
+// committed results.
+type DriverImport struct {
+	ID       primitive.ObjectID  `json:"id" bson:"_id"`
+	Adapter  string              `json:"adapter" bson:"adapter"`
+	FileName string              `json:"file_name,omitempty" bson:"file_name,omitempty"`
+	FleetID  string              `json:"fleet_id,omitempty" bson:"fleet_id,omitempty"`
+	Status   string              `json:"status" bson:"status"`
+	Summary  DriverImportSummary `json:"summary" bson:"summary"`
+	Rows     []DriverImportRow   `json:"rows,omitempty" bson:"rows"`
+
+	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
+	CommittedAt *time.Time `json:"committed_at,omitempty" bson:"committed_at,omitempty"`
+	// ExpiresAt is when a staged import is dropped; committed imports are
+	// kept.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDriverListSort lists the newest drivers first.
+const DefaultDriverListSort = "-created_at"
+
+// driverListSortFields maps the sort keys accepted on GET /api/v1/drivers to
+// the stored fields; nothing else may be sorted on.
+var driverListSortFields = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"first_name": "first_name",
+	"last_name":  "last_name",
+	"car_brand":  "car_brand",
+	"taxi_type":  "taxi_type",
+}
+
+// DriverListFilter narrows and orders a driver listing. Empty fields do not
+// filter.
+type DriverListFilter struct {
+	TaxiType string
+	// CarBrand matches the whole brand, ignoring case.
+	CarBrand string
+	// Status matches the effective status, so available includes drivers
+	// stored before statuses existed.
+	Status string
+	// ReviewStatus matches the effective review status, so approved
+	// includes drivers stored before the approval workflow existed.
+	ReviewStatus string
+	FleetID      string
+	// CustomFields matches custom field values by key. They need a FleetID,
+	// whose definitions give them their types; see ParseCustomFieldFilter.
+	CustomFields map[string]interface{}
+	CreatedAfter time.Time
+	// Sort is a key of driverListSortFields, prefixed with "-" for
+	// descending order; empty means DefaultDriverListSort.
+	Sort string
+}
+
+// Validate checks the filter values and the sort key against the allowlist.
+func (f DriverListFilter) Validate() error {
+	if f.TaxiType != "" && !IsValidTaxiType(f.TaxiType) {
+		return fmt.Errorf("taxi_type must be one of %s, %s, %s", TaxiTypeSari, TaxiTypeTurkuaz, TaxiTypeSiyah)
+	}
+	if f.Status != "" && !IsValidDriverStatus(f.Status) {
+		return fmt.Errorf("status must be one of %s, %s, %s", DriverStatusAvailable, DriverStatusBusy, DriverStatusOffline)
+	}
+	if f.ReviewStatus != "" && !IsValidDriverReviewStatus(f.ReviewStatus) {
+		return fmt.Errorf("review_status must be one of %s, %s, %s, %s", DriverReviewPending, DriverReviewApproved, DriverReviewRejected, DriverReviewSuspended)
+	}
+	if len(f.CustomFields) > 0 && f.FleetID == "" {
+		return fmt.Errorf("custom field filters need a fleet_id")
+	}
+	if _, _, ok := f.SortField(); !ok {
+		return fmt.Errorf("sort must be one of %s, optionally prefixed with -", strings.Join(DriverListSortKeys(), ", "))
+	}
+	return nil
+}
+
+// SortField returns the stored field to sort on and whether the order is
+// descending; ok is false for keys outside the allowlist.
+func (f DriverListFilter) SortField() (field string, descending bool, ok bool) {
+	key := f.Sort
+	if key == "" {
+		key = DefaultDriverListSort
+	}
+	if strings.HasPrefix(key, "-") {
+		key = key[1:]
+		descending = true
+	}
+	field, ok = driverListSortFields[key]
+	return field, descending, ok
+}
+
+// Matches reports whether the driver passes the filter, for repositories
+// that cannot push it into a query.
+func (f DriverListFilter) Matches(driver *Driver) bool {
+	if f.TaxiType != "" && driver.TaxiType != f.TaxiType {
+		return false
+	}
+	if f.CarBrand != "" && !strings.EqualFold(driver.CarBrand, f.CarBrand) {
+		return false
+	}
+	if f.Status != "" && driver.EffectiveStatus() != f.Status {
+		return false
+	}
+	if f.ReviewStatus != "" && driver.EffectiveReviewStatus() != f.ReviewStatus {
+		return false
+	}
+	if f.FleetID != "" && driver.FleetID != f.FleetID {
+		return false
+	}
+	for key, value := range f.CustomFields {
+		if driver.CustomFields[key] != value {
+			return false
+		}
+	}
+	if !f.CreatedAfter.IsZero() && !driver.CreatedAt.After(f.CreatedAfter) {
+		return false
+	}
+	return true
+}
+
+// Less orders two drivers by the filter's sort, breaking ties by ID so pages
+// do not overlap.
+func (f DriverListFilter) Less(a, b *Driver) bool {
+	field, descending, _ := f.SortField()
+	cmp := 0
+	switch field {
+	case "created_at":
+		cmp = compareTimes(a.CreatedAt, b.CreatedAt)
+	case "updated_at":
+		cmp = compareTimes(a.UpdatedAt, b.UpdatedAt)
+	case "first_name":
+		cmp = strings.Compare(a.FirstName, b.FirstName)
+	case "last_name":
+		cmp = strings.Compare(a.LastName, b.LastName)
+	case "car_brand":
+		cmp = strings.Compare(a.CarBrand, b.CarBrand)
+	case "taxi_type":
+		cmp = strings.Compare(a.TaxiType, b.TaxiType)
+	}
+	if cmp == 0 {
+		cmp = strings.Compare(a.ID.Hex(), b.ID.Hex())
+	}
+	if descending {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+// DriverListSortKeys returns the accepted sort keys, sorted.
+func DriverListSortKeys() []string {
+	keys := make([]string, 0, len(driverListSortFields))
+	for key := range driverListSortFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
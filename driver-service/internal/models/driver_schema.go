@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// CurrentDriverSchemaVersion is the schema version of driver documents this
+// build writes. Documents stored before versioning have none and are version
+// 1. Raising it needs an up-migration in repository/driver_schema.go.
+const CurrentDriverSchemaVersion = 4
+
+// DriverSchemaMigration describes one up-migration of driver documents.
+type DriverSchemaMigration struct {
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+}
+
+// DriverSchemaStatus counts driver documents by schema version. Pending
+// counts those behind the current version.
+type DriverSchemaStatus struct {
+	CurrentVersion int                     `json:"current_version"`
+	Versions       map[int]int64           `json:"versions"`
+	Pending        int64                   `json:"pending"`
+	Migrations     []DriverSchemaMigration `json:"migrations"`
+}
+
+// DriverSchemaBackfill is the outcome of one backfill run. Failed documents
+// stay on their version and are retried by the next run.
+type DriverSchemaBackfill struct {
+	Scanned    int       `json:"scanned"`
+	Migrated   int       `json:"migrated"`
+	Failed     int       `json:"failed"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
@@ -20,15 +20,63 @@ func TurkishLicensePlateValidator(fl validator.FieldLevel) bool {
 	return matched
 }
 
+func newValidator() *validator.Validate {
+	validate := validator.New()
+
+	validate.RegisterValidation("turkish_plate", TurkishLicensePlateValidator)
+	validate.RegisterValidation("tckn", TCKNValidator)
+	validate.RegisterValidation("vergi_no", TaxNumberValidator)
+	validate.RegisterValidation("locale", LocaleValidator)
+	validate.RegisterValidation("app_version", AppVersionValidator)
+	validate.RegisterValidation("field_key", FieldKeyValidator)
+
+	return validate
+}
+
 type CreateDriverRequest struct {
-	FirstName string  `json:"first_name" validate:"required,min=2,max=50"`
-	LastName  string  `json:"last_name" validate:"required,min=2,max=50"`
-	Plate     string  `json:"plate" validate:"required,turkish_plate"`
-	TaxiType  string  `json:"taxi_type" validate:"required,oneof=sari turkuaz siyah"`
-	CarBrand  string  `json:"car_brand" validate:"required,min=2,max=30"`
-	CarModel  string  `json:"car_model" validate:"required,min=1,max=30"`
-	Lat       float64 `json:"lat" validate:"required,min=-90,max=90"`
-	Lon       float64 `json:"lon" validate:"required,min=-180,max=180"`
+	FirstName      string                        `json:"first_name" validate:"required,min=2,max=50"`
+	LastName       string                        `json:"last_name" validate:"required,min=2,max=50"`
+	Plate          string                        `json:"plate" validate:"required,turkish_plate"`
+	TaxiType       string                        `json:"taxi_type" validate:"required,oneof=sari turkuaz siyah"`
+	FleetID        string                        `json:"fleet_id" validate:"omitempty,max=64"`
+	CarBrand       string                        `json:"car_brand" validate:"required,min=2,max=30"`
+	CarModel       string                        `json:"car_model" validate:"required,min=1,max=30"`
+	Lat            float64                       `json:"lat" validate:"required,min=-90,max=90"`
+	Lon            float64                       `json:"lon" validate:"required,min=-180,max=180"`
+	TCKN           string                        `json:"tckn" validate:"omitempty,tckn"`
+	TaxNumber      string                        `json:"tax_number" validate:"omitempty,vergi_no"`
+	BillingAddress string                        `json:"billing_address" validate:"omitempty,max=300"`
+	CarColor       string                        `json:"car_color" validate:"omitempty,max=30"`
+	Bio            string                        `json:"bio" validate:"omitempty,max=500"`
+	PhotoURL       string                        `json:"photo_url" validate:"omitempty,url,max=500"`
+	Localizations  map[string]DriverLocalization `json:"localizations" validate:"omitempty,dive,keys,locale,endkeys"`
+
+	LicenseClasses []string `json:"license_classes" validate:"omitempty,dive,oneof=A B BE C1 C D1 D"`
+	// LicenseOverride allows a taxi type the license classes do not permit.
+	LicenseOverride *LicenseOverride `json:"license_override" validate:"omitempty"`
+
+	Languages []string `json:"languages" validate:"omitempty,max=10,dive,locale"`
+
+	// PlateReservation is the token of the caller's plate reservation.
+	PlateReservation string `json:"plate_reservation" validate:"omitempty,max=64"`
+
+	// Email gets the welcome email and later transactional emails.
+	Email string `json:"email" validate:"omitempty,email,max=254"`
+
+	// CustomFields are values of the fleet's custom fields, by key.
+	CustomFields map[string]interface{} `json:"custom_fields" validate:"omitempty,max=50"`
+}
+
+func (r *CreateDriverRequest) GetTaxInfo() *TaxInfo {
+	info := &TaxInfo{
+		TCKN:           r.TCKN,
+		TaxNumber:      r.TaxNumber,
+		BillingAddress: r.BillingAddress,
+	}
+	if info.IsEmpty() {
+		return nil
+	}
+	return info
 }
 
 func (r *CreateDriverRequest) ToDriver() *Driver {
@@ -38,31 +86,64 @@ func (r *CreateDriverRequest) ToDriver() *Driver {
 		LastName:  r.LastName,
 		Plate:     r.Plate,
 		TaxiType:  r.TaxiType,
+		FleetID:   r.FleetID,
 		CarBrand:  r.CarBrand,
 		CarModel:  r.CarModel,
 		Location: Location{
 			Lat: r.Lat,
 			Lon: r.Lon,
 		},
+		CarColor:      r.CarColor,
+		Bio:           r.Bio,
+		PhotoURL:      r.PhotoURL,
+		TaxInfo:       r.GetTaxInfo(),
+		Localizations: NormalizeLocalizations(r.Localizations),
+
+		LicenseClasses: NormalizeLicenseClasses(r.LicenseClasses),
+
+		Languages: NormalizeLanguages(r.Languages),
+
+		Email: r.Email,
 	}
 }
 
 func (r *CreateDriverRequest) Validate() error {
-	validate := validator.New()
-
-	validate.RegisterValidation("turkish_plate", TurkishLicensePlateValidator)
-
-	return validate.Struct(r)
+	return newValidator().Struct(r)
 }
 
 type UpdateDriverRequest struct {
-	FirstName *string  `json:"first_name,omitempty" validate:"omitempty,min=2,max=50"`
-	LastName  *string  `json:"last_name,omitempty" validate:"omitempty,min=2,max=50"`
-	TaxiType  *string  `json:"taxi_type,omitempty" validate:"omitempty,oneof=sari turkuaz siyah"`
-	CarBrand  *string  `json:"car_brand,omitempty" validate:"omitempty,min=2,max=30"`
-	CarModel  *string  `json:"car_model,omitempty" validate:"omitempty,min=1,max=30"`
-	Lat       *float64 `json:"lat,omitempty" validate:"omitempty,min=-90,max=90"`
-	Lon       *float64 `json:"lon,omitempty" validate:"omitempty,min=-180,max=180"`
+	FirstName      *string  `json:"first_name,omitempty" validate:"omitempty,min=2,max=50"`
+	LastName       *string  `json:"last_name,omitempty" validate:"omitempty,min=2,max=50"`
+	TaxiType       *string  `json:"taxi_type,omitempty" validate:"omitempty,oneof=sari turkuaz siyah"`
+	FleetID        *string  `json:"fleet_id,omitempty" validate:"omitempty,max=64"`
+	CarBrand       *string  `json:"car_brand,omitempty" validate:"omitempty,min=2,max=30"`
+	CarModel       *string  `json:"car_model,omitempty" validate:"omitempty,min=1,max=30"`
+	Lat            *float64 `json:"lat,omitempty" validate:"omitempty,min=-90,max=90"`
+	Lon            *float64 `json:"lon,omitempty" validate:"omitempty,min=-180,max=180"`
+	TCKN           *string  `json:"tckn,omitempty" validate:"omitempty,tckn"`
+	TaxNumber      *string  `json:"tax_number,omitempty" validate:"omitempty,vergi_no"`
+	BillingAddress *string  `json:"billing_address,omitempty" validate:"omitempty,max=300"`
+	CarColor       *string  `json:"car_color,omitempty" validate:"omitempty,max=30"`
+	Bio            *string  `json:"bio,omitempty" validate:"omitempty,max=500"`
+	PhotoURL       *string  `json:"photo_url,omitempty" validate:"omitempty,url,max=500"`
+	// Localizations replaces the given locales; an empty entry removes one.
+	Localizations map[string]DriverLocalization `json:"localizations,omitempty" validate:"omitempty,dive,keys,locale,endkeys"`
+
+	// LicenseClasses replaces the recorded classes; an empty list clears them.
+	LicenseClasses *[]string `json:"license_classes,omitempty" validate:"omitempty,dive,oneof=A B BE C1 C D1 D"`
+	// LicenseOverride allows a taxi type the license classes do not permit.
+	LicenseOverride *LicenseOverride `json:"license_override,omitempty" validate:"omitempty"`
+
+	// Languages replaces the spoken languages; an empty list clears them.
+	Languages *[]string `json:"languages,omitempty" validate:"omitempty,max=10,dive,locale"`
+
+	// Email replaces the email address; an empty string clears it.
+	Email *string `json:"email,omitempty" validate:"omitempty,max=254,email|len=0"`
+
+	// CustomFields replaces the given custom field values; a null value
+	// removes one. Moving the driver to another fleet drops the values the
+	// new fleet does not define.
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty" validate:"omitempty,max=50"`
 }
 
 func (r *UpdateDriverRequest) HasLocation() bool {
@@ -79,14 +160,20 @@ func (r *UpdateDriverRequest) GetLocation() *Location {
 	return nil
 }
 
+func (r *UpdateDriverRequest) HasTaxInfo() bool {
+	return r.TCKN != nil || r.TaxNumber != nil || r.BillingAddress != nil
+}
+
 func (r *UpdateDriverRequest) Validate() error {
-	validate := validator.New()
-	return validate.Struct(r)
+	return newValidator().Struct(r)
 }
 
 type UpdateLocationRequest struct {
 	Lat float64 `json:"lat" validate:"required,min=-90,max=90"`
 	Lon float64 `json:"lon" validate:"required,min=-180,max=180"`
+	// Accuracy is the fix's accuracy radius in meters, as reported by the
+	// device; optional.
+	Accuracy float64 `json:"accuracy" validate:"min=0,max=10000"`
 }
 
 func (r *UpdateLocationRequest) ToLocation() Location {
@@ -96,36 +183,146 @@ func (r *UpdateLocationRequest) ToLocation() Location {
 	}
 }
 
+type UpdateStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=available busy offline"`
+}
+
+// ReviewDriverRequest carries an admin's approval, rejection or suspension
+// of a driver.
+type ReviewDriverRequest struct {
+	Note string `json:"note" validate:"max=500"`
+}
+
+func (r *ReviewDriverRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+func (r *UpdateStatusRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
 func (r *UpdateLocationRequest) Validate() error {
 	validate := validator.New()
 	return validate.Struct(r)
 }
 
 type DriverResponse struct {
-	ID        string   `json:"id"`
-	FirstName string   `json:"first_name"`
-	LastName  string   `json:"last_name"`
-	Plate     string   `json:"plate"`
-	TaxiType  string   `json:"taxi_type"`
-	CarBrand  string   `json:"car_brand"`
-	CarModel  string   `json:"car_model"`
-	Location  Location `json:"location"`
-	CreatedAt string   `json:"created_at"`
-	UpdatedAt string   `json:"updated_at"`
+	ID            string                        `json:"id"`
+	FirstName     string                        `json:"first_name"`
+	LastName      string                        `json:"last_name"`
+	Plate         string                        `json:"plate"`
+	TaxiType      string                        `json:"taxi_type"`
+	FleetID       string                        `json:"fleet_id,omitempty"`
+	CarBrand      string                        `json:"car_brand"`
+	CarModel      string                        `json:"car_model"`
+	CarColor      string                        `json:"car_color,omitempty"`
+	Bio           string                        `json:"bio,omitempty"`
+	PhotoURL      string                        `json:"photo_url,omitempty"`
+	Verified      bool                          `json:"verified"`
+	Location      Location                      `json:"location"`
+	TraveledKm    float64                       `json:"traveled_km"`
+	TaxInfo       *TaxInfo                      `json:"tax_info,omitempty"`
+	Localizations map[string]DriverLocalization `json:"localizations,omitempty"`
+	CreatedAt     string                        `json:"created_at"`
+	UpdatedAt     string                        `json:"updated_at"`
+
+	LicenseClasses []string `json:"license_classes,omitempty"`
+
+	Languages []string `json:"languages,omitempty"`
+
+	Status string `json:"status"`
+
+	ReviewStatus string `json:"review_status"`
+	ReviewNote   string `json:"review_note,omitempty"`
+	ReviewedAt   string `json:"reviewed_at,omitempty"`
+
+	Email string `json:"email,omitempty"`
+
+	AverageRating float64 `json:"average_rating,omitempty"`
+	RatingCount   int     `json:"rating_count"`
+
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
 }
 
+// NewDriverResponse masks tax identity fields; use NewUnmaskedDriverResponse
+// where the caller is allowed to see them in full.
 func NewDriverResponse(driver *Driver) *DriverResponse {
-	return &DriverResponse{
+	response := NewUnmaskedDriverResponse(driver)
+	response.TaxInfo = driver.TaxInfo.Masked()
+	return response
+}
+
+func NewUnmaskedDriverResponse(driver *Driver) *DriverResponse {
+	response := &DriverResponse{
+		ID:            driver.ID.Hex(),
+		FirstName:     driver.FirstName,
+		LastName:      driver.LastName,
+		Plate:         driver.Plate,
+		TaxiType:      driver.TaxiType,
+		FleetID:       driver.FleetID,
+		CarBrand:      driver.CarBrand,
+		CarModel:      driver.CarModel,
+		CarColor:      driver.CarColor,
+		Bio:           driver.Bio,
+		PhotoURL:      driver.PhotoURL,
+		Verified:      driver.Verified,
+		Location:      driver.Location,
+		TraveledKm:    driver.TraveledKm,
+		TaxInfo:       driver.TaxInfo,
+		Localizations: driver.Localizations,
+		CreatedAt:     driver.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     driver.UpdatedAt.Format(time.RFC3339),
+
+		LicenseClasses: driver.LicenseClasses,
+
+		Languages: driver.Languages,
+
+		Status: driver.EffectiveStatus(),
+
+		ReviewStatus: driver.EffectiveReviewStatus(),
+		ReviewNote:   driver.ReviewNote,
+
+		Email: driver.Email,
+
+		AverageRating: driver.AverageRating,
+		RatingCount:   driver.RatingCount,
+
+		CustomFields: driver.CustomFields,
+	}
+	if driver.ReviewedAt != nil {
+		response.ReviewedAt = driver.ReviewedAt.Format(time.RFC3339)
+	}
+	return response
+}
+
+// DriverCardResponse is the rider-facing view of a driver with display
+// fields resolved for the rider's language.
+type DriverCardResponse struct {
+	ID        string `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Plate     string `json:"plate"`
+	TaxiType  string `json:"taxi_type"`
+	CarBrand  string `json:"car_brand"`
+	CarModel  string `json:"car_model"`
+	CarColor  string `json:"car_color,omitempty"`
+	Bio       string `json:"bio,omitempty"`
+	Locale    string `json:"locale"`
+}
+
+func NewDriverCardResponse(driver *Driver, preferredLocales []string) *DriverCardResponse {
+	localized, locale := driver.Localize(preferredLocales)
+	return &DriverCardResponse{
 		ID:        driver.ID.Hex(),
 		FirstName: driver.FirstName,
 		LastName:  driver.LastName,
 		Plate:     driver.Plate,
 		TaxiType:  driver.TaxiType,
-		CarBrand:  driver.CarBrand,
-		CarModel:  driver.CarModel,
-		Location:  driver.Location,
-		CreatedAt: driver.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: driver.UpdatedAt.Format(time.RFC3339),
+		CarBrand:  localized.CarBrand,
+		CarModel:  localized.CarModel,
+		CarColor:  localized.CarColor,
+		Bio:       localized.Bio,
+		Locale:    locale,
 	}
 }
 
@@ -138,7 +335,17 @@ type DriverWithDistanceResponse struct {
 	CarBrand   string   `json:"car_brand"`
 	CarModel   string   `json:"car_model"`
 	Location   Location `json:"location"`
+	Verified   bool     `json:"verified"`
 	DistanceKm float64  `json:"distance_km"`
+	ETAMinutes int      `json:"eta_minutes"`
+	Address    *Address `json:"address,omitempty"`
+
+	Languages []string `json:"languages,omitempty"`
+
+	Status string `json:"status"`
+
+	AverageRating float64 `json:"average_rating,omitempty"`
+	RatingCount   int     `json:"rating_count"`
 }
 
 func NewDriverWithDistanceResponse(driver DriverWithDistance) *DriverWithDistanceResponse {
@@ -155,7 +362,16 @@ func NewDriverWithDistanceResponse(driver DriverWithDistance) *DriverWithDistanc
 		CarBrand:   driver.CarBrand,
 		CarModel:   driver.CarModel,
 		Location:   driver.Location,
+		Verified:   driver.Verified,
 		DistanceKm: roundedDistance,
+		ETAMinutes: EstimateETAMinutes(driver.DistanceKm),
+
+		Languages: driver.Languages,
+
+		Status: driver.EffectiveStatus(),
+
+		AverageRating: driver.AverageRating,
+		RatingCount:   driver.RatingCount,
 	}
 }
 
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kinds of earnings entries. A ride earns its fare and has the commission
+// deducted from it; bonuses are granted by staff.
+const (
+	EarningsRide       = "ride"
+	EarningsCommission = "commission"
+	EarningsBonus      = "bonus"
+)
+
+// EarningsDayLayout formats the days earnings are totalled by.
+const EarningsDayLayout = "2006-01-02"
+
+// EarningsEntry is one change to a driver's income. Commissions are
+// negative. Entries of a trip are recorded once per kind, even when the
+// ride is booked again.
+type EarningsEntry struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	DriverID  string             `json:"driver_id" bson:"driver_id"`
+	Kind      string             `json:"kind" bson:"kind"`
+	Amount    float64            `json:"amount" bson:"amount"`
+	TripID    string             `json:"trip_id,omitempty" bson:"trip_id,omitempty"`
+	Note      string             `json:"note,omitempty" bson:"note,omitempty"`
+	Day       string             `json:"day" bson:"day"`
+	CreatedBy string             `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// DailyEarnings is a driver's running total of one day. Commission is the
+// amount deducted, so Net is Gross less Commission plus Bonuses.
+type DailyEarnings struct {
+	DriverID   string    `json:"driver_id" bson:"driver_id"`
+	Day        string    `json:"day" bson:"day"`
+	Gross      float64   `json:"gross" bson:"gross"`
+	Commission float64   `json:"commission" bson:"commission"`
+	Bonuses    float64   `json:"bonuses" bson:"bonuses"`
+	Net        float64   `json:"net" bson:"net"`
+	Rides      int       `json:"rides" bson:"rides"`
+	Currency   string    `json:"currency" bson:"-"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// Rounded returns the totals in whole cents, as floating point sums drift.
+func (d DailyEarnings) Rounded() DailyEarnings {
+	d.Gross = RoundAmount(d.Gross)
+	d.Commission = RoundAmount(d.Commission)
+	d.Bonuses = RoundAmount(d.Bonuses)
+	d.Net = RoundAmount(d.Net)
+	return d
+}
+
+// RoundAmount rounds an amount of money to cents.
+func RoundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// EarningsUpdate is sent to a driver for every entry: the entry and the
+// running total of its day after it.
+type EarningsUpdate struct {
+	Entry  EarningsEntry `json:"entry"`
+	Totals DailyEarnings `json:"totals"`
+}
+
+// DailyEarningsResponse is a driver's day: the running total and the
+// entries that make it up, newest first.
+type DailyEarningsResponse struct {
+	Totals  DailyEarnings   `json:"totals"`
+	Entries []EarningsEntry `json:"entries"`
+}
+
+// MaxEarningsEntries bounds the entries returned with a day's totals.
+const MaxEarningsEntries = 500
+
+// RecordRideRequest books the fare of a ride the driver gave. TripID is the
+// booking system's own and keeps a ride from being booked twice.
+type RecordRideRequest struct {
+	TripID string  `json:"trip_id" validate:"required,max=64"`
+	Fare   float64 `json:"fare" validate:"gt=0,max=100000"`
+}
+
+func (r *RecordRideRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+// GrantBonusRequest adds a bonus to a driver's earnings of today.
+type GrantBonusRequest struct {
+	Amount    float64 `json:"amount" validate:"gt=0,max=100000"`
+	Note      string  `json:"note" validate:"max=200"`
+	GrantedBy string  `json:"granted_by" validate:"max=100"`
+}
+
+func (r *GrantBonusRequest) Validate() error {
+	return newValidator().Struct(r)
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Email delivery statuses. Queued emails are waiting for their next attempt,
+// sending ones are claimed by a worker, and failed ones ran out of attempts
+// or were rejected for good.
+const (
+	EmailStatusQueued  = "queued"
+	EmailStatusSending = "sending"
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed"
+)
+
+// Email is a rendered transactional email and its delivery status.
+type Email struct {
+	ID        primitive.ObjectID  `json:"id" bson:"_id"`
+	Template  string              `json:"template" bson:"template"`
+	Locale    string              `json:"locale" bson:"locale"`
+	To        string              `json:"to" bson:"to"`
+	DriverID  *primitive.ObjectID `json:"driver_id,omitempty" bson:"driver_id,omitempty"`
+	Subject   string              `json:"subject" bson:"subject"`
+	Text      string              `json:"-" bson:"text"`
+	HTML      string              `json:"-" bson:"html"`
+	Status    string              `json:"status" bson:"status"`
+	Attempts  int                 `json:"attempts" bson:"attempts"`
+	LastError string              `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	Provider  string              `json:"provider,omitempty" bson:"provider,omitempty"`
+	// NextAttemptAt is when a queued email is due, or when a claim on a
+	// sending one lapses.
+	NextAttemptAt time.Time  `json:"next_attempt_at" bson:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty" bson:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// SendEmailRequest queues a templated email. With a driver ID and no
+// recipient, it goes to the driver's email address, in the driver's
+// language unless a locale is given; data fields the template needs that
+// the driver record has, such as first_name and plate, are filled in.
+type SendEmailRequest struct {
+	Template string            `json:"template" validate:"required,oneof=welcome receipt suspension_notice"`
+	To       string            `json:"to" validate:"required_without=DriverID,omitempty,email,max=254"`
+	DriverID string            `json:"driver_id" validate:"omitempty,len=24,hexadecimal"`
+	Locale   string            `json:"locale" validate:"omitempty,locale"`
+	Data     map[string]string `json:"data" validate:"omitempty,max=20,dive,keys,max=50,endkeys,max=1000"`
+}
+
+func (r *SendEmailRequest) Validate() error {
+	return newValidator().Struct(r)
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FeatureFlag enables a feature for the drivers matched by any of its rules.
+// A disabled flag is off for everyone regardless of rules.
+type FeatureFlag struct {
+	Key         string          `json:"key" bson:"_id"`
+	Description string          `json:"description,omitempty" bson:"description,omitempty"`
+	Enabled     bool            `json:"enabled" bson:"enabled"`
+	Rules       []TargetingRule `json:"rules" bson:"rules"`
+	CreatedAt   time.Time       `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" bson:"updated_at"`
+}
+
+// TargetingRule describes a driver cohort. Every condition that is set must
+// match; an empty rule matches everyone. Percentage buckets drivers by a hash
+// of the flag key and driver ID, so a driver stays in or out of a rollout as
+// the percentage grows.
+type TargetingRule struct {
+	DriverIDs     []string `json:"driver_ids,omitempty" bson:"driver_ids,omitempty" validate:"omitempty,dive,len=24,hexadecimal"`
+	Fleets        []string `json:"fleets,omitempty" bson:"fleets,omitempty"`
+	Cities        []string `json:"cities,omitempty" bson:"cities,omitempty"`
+	MinAppVersion string   `json:"min_app_version,omitempty" bson:"min_app_version,omitempty" validate:"omitempty,app_version"`
+	Percentage    *int     `json:"percentage,omitempty" bson:"percentage,omitempty" validate:"omitempty,min=0,max=100"`
+}
+
+// TargetingContext is what is known about the driver a flag is evaluated for.
+type TargetingContext struct {
+	DriverID   string `json:"driver_id,omitempty"`
+	Fleet      string `json:"fleet,omitempty"`
+	City       string `json:"city,omitempty"`
+	AppVersion string `json:"app_version,omitempty"`
+}
+
+func (f *FeatureFlag) IsEnabledFor(target TargetingContext) bool {
+	if !f.Enabled {
+		return false
+	}
+	for _, rule := range f.Rules {
+		if rule.matches(f.Key, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r TargetingRule) matches(flagKey string, target TargetingContext) bool {
+	if len(r.DriverIDs) > 0 && !containsFold(r.DriverIDs, target.DriverID) {
+		return false
+	}
+	if len(r.Fleets) > 0 && !containsFold(r.Fleets, target.Fleet) {
+		return false
+	}
+	if len(r.Cities) > 0 && !containsFold(r.Cities, target.City) {
+		return false
+	}
+	if r.MinAppVersion != "" && (target.AppVersion == "" || CompareAppVersions(target.AppVersion, r.MinAppVersion) < 0) {
+		return false
+	}
+	if r.Percentage != nil {
+		if target.DriverID == "" {
+			return false
+		}
+		hash := fnv.New32a()
+		hash.Write([]byte(flagKey + ":" + target.DriverID))
+		if int(hash.Sum32()%100) >= *r.Percentage {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareAppVersions compares dotted numeric versions such as 4.12.0,
+// ignoring a leading "v" and any pre-release suffix. Missing parts count as 0.
+func CompareAppVersions(a, b string) int {
+	pa, pb := appVersionParts(a), appVersionParts(b)
+	for len(pa) < len(pb) {
+		pa = append(pa, 0)
+	}
+	for len(pb) < len(pa) {
+		pb = append(pb, 0)
+	}
+
+	for i := range pa {
+		switch {
+		case pa[i] < pb[i]:
+			return -1
+		case pa[i] > pb[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+func appVersionParts(version string) []int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+
+	var parts []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+func AppVersionValidator(fl validator.FieldLevel) bool {
+	return appVersionParts(fl.Field().String()) != nil
+}
+
+type UpsertFeatureFlagRequest struct {
+	Description string          `json:"description" validate:"omitempty,max=200"`
+	Enabled     bool            `json:"enabled"`
+	Rules       []TargetingRule `json:"rules" validate:"omitempty,dive"`
+}
+
+func (r *UpsertFeatureFlagRequest) Validate() error {
+	return newValidator().Struct(r)
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Capabilities a fleet admin account can hold within its fleet. Owners hold
+// all of them; sub-admins are granted a subset of the delegable ones by an
+// owner.
+const (
+	FleetCapabilityViewDrivers    = "drivers:view"
+	FleetCapabilityOnboardDrivers = "drivers:onboard"
+	FleetCapabilityViewEarnings   = "earnings:view"
+	FleetCapabilityManageAccounts = "accounts:manage"
+	FleetCapabilityManageFields   = "fields:manage"
+)
+
+// DelegableFleetCapabilities can be granted to sub-admins. Managing accounts
+// stays with owners, so sub-admins cannot widen their own access, and so does
+// the fleet's custom field schema.
+var DelegableFleetCapabilities = []string{FleetCapabilityViewDrivers, FleetCapabilityOnboardDrivers, FleetCapabilityViewEarnings}
+
+// FleetAccount is a fleet owner or sub-admin login, scoped to one fleet.
+type FleetAccount struct {
+	ID                primitive.ObjectID `json:"id" bson:"_id"`
+	FleetID           string             `json:"fleet_id" bson:"fleet_id"`
+	Username          string             `json:"username" bson:"username"`
+	PasswordHash      string             `json:"-" bson:"password_hash"`
+	Owner             bool               `json:"owner" bson:"owner"`
+	Capabilities      []string           `json:"capabilities" bson:"capabilities"`
+	CreatedBy         string             `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	PasswordChangedAt time.Time          `json:"password_changed_at" bson:"password_changed_at"`
+	CreatedAt         time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// GrantedCapabilities are what the account may do: everything for owners,
+// the granted capabilities otherwise.
+func (a *FleetAccount) GrantedCapabilities() []string {
+	if a.Owner {
+		return []string{FleetCapabilityViewDrivers, FleetCapabilityOnboardDrivers, FleetCapabilityViewEarnings, FleetCapabilityManageAccounts, FleetCapabilityManageFields}
+	}
+	return a.Capabilities
+}
+
+// CreateFleetAccountRequest creates a fleet account. Only platform admins may
+// create owners, whose capabilities are implied.
+type CreateFleetAccountRequest struct {
+	Username     string   `json:"username" validate:"required,min=3,max=100"`
+	Password     string   `json:"password" validate:"required,min=8,max=72"`
+	Owner        bool     `json:"owner"`
+	Capabilities []string `json:"capabilities" validate:"excluded_with=Owner,unique,dive,oneof=drivers:view drivers:onboard earnings:view"`
+}
+
+func (r *CreateFleetAccountRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+// UpdateFleetAccountRequest replaces a sub-admin's capabilities or resets an
+// account's password; omitted fields are kept.
+type UpdateFleetAccountRequest struct {
+	Password     *string   `json:"password,omitempty" validate:"omitempty,min=8,max=72"`
+	Capabilities *[]string `json:"capabilities,omitempty" validate:"omitempty,unique,dive,oneof=drivers:view drivers:onboard earnings:view"`
+}
+
+func (r *UpdateFleetAccountRequest) Validate() error {
+	return newValidator().Struct(r)
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"math"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const earthRadiusKm = 6371.0
+
+// DistanceKm returns the great-circle distance between two locations.
+func (l Location) DistanceKm(other Location) float64 {
+	lat1 := l.Lat * math.Pi / 180
+	lat2 := other.Lat * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (other.Lon - l.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+func (l Location) IsZero() bool {
+	return l.Lat == 0 && l.Lon == 0
+}
+
+// GeoJSONPointType is the GeoJSON type locations are stored with.
+const GeoJSONPointType = "Point"
+
+// storedLocation is the BSON shape of a Location. Lat and Lon are only read,
+// from documents written before locations became GeoJSON; `go run
+// ./cmd/reindex -transform geojson` rewrites those.
+type storedLocation struct {
+	Type        string    `bson:"type,omitempty"`
+	Coordinates []float64 `bson:"coordinates,omitempty"`
+	Lat         *float64  `bson:"lat,omitempty"`
+	Lon         *float64  `bson:"lon,omitempty"`
+}
+
+// MarshalBSON stores the location as a GeoJSON Point, which lists the
+// longitude first, so 2dsphere indexes and $geoNear read it correctly.
+func (l Location) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(storedLocation{
+		Type:        GeoJSONPointType,
+		Coordinates: []float64{l.Lon, l.Lat},
+	})
+}
+
+// UnmarshalBSON reads a GeoJSON Point, or the legacy {lat, lon} document. A
+// null location decodes as the zero Location.
+func (l *Location) UnmarshalBSON(data []byte) error {
+	if len(data) == 0 {
+		*l = Location{}
+		return nil
+	}
+
+	var stored storedLocation
+	if err := bson.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+
+	switch {
+	case stored.Type == GeoJSONPointType && len(stored.Coordinates) == 2:
+		l.Lon, l.Lat = stored.Coordinates[0], stored.Coordinates[1]
+	case stored.Lat != nil && stored.Lon != nil:
+		l.Lat, l.Lon = *stored.Lat, *stored.Lon
+	default:
+		return errors.New("location is neither a GeoJSON Point nor {lat, lon}")
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	GPSQualityGood    = "good"
+	GPSQualityFair    = "fair"
+	GPSQualityPoor    = "poor"
+	GPSQualityUnknown = "unknown"
+)
+
+// GPSQuality scores how trustworthy a driver's recent location updates are,
+// from 0 (useless) to 100. Each component is also 0-100; AccuracyScore is nil
+// when the app reports no accuracy. Drivers with fewer than the minimum
+// samples are rated unknown and have no scores.
+type GPSQuality struct {
+	DriverID primitive.ObjectID `json:"driver_id"`
+	FleetID  string             `json:"fleet_id,omitempty"`
+	Level    string             `json:"level"`
+	Score    *int               `json:"score,omitempty"`
+
+	AccuracyScore *int `json:"accuracy_score,omitempty"`
+	GapScore      *int `json:"gap_score,omitempty"`
+	JitterScore   *int `json:"jitter_score,omitempty"`
+
+	Samples         int        `json:"samples"`
+	MedianAccuracyM *float64   `json:"median_accuracy_m,omitempty"`
+	LongGaps        int        `json:"long_gaps"`
+	JitterJumps     int        `json:"jitter_jumps"`
+	LastSampleAt    *time.Time `json:"last_sample_at,omitempty"`
+}
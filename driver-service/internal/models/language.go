@@ -0,0 +1,48 @@
+package models
+
+import "strings"
+
+const (
+	// LanguageModePrefer ranks drivers who speak the language first but
+	// still assigns someone else when none of them do.
+	LanguageModePrefer = "prefer"
+	// LanguageModeRequire only considers drivers who speak the language.
+	LanguageModeRequire = "require"
+)
+
+// PrimaryLanguage reduces a locale to its language subtag ("en-GB" -> "en"),
+// which is what driver language tags are compared by.
+func PrimaryLanguage(locale string) string {
+	language, _, _ := strings.Cut(NormalizeLocale(locale), "-")
+	return language
+}
+
+// NormalizeLanguages reduces the tags to primary languages and drops
+// duplicates, keeping the driver's order.
+func NormalizeLanguages(languages []string) []string {
+	if len(languages) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(languages))
+	normalized := make([]string, 0, len(languages))
+	for _, language := range languages {
+		language = PrimaryLanguage(language)
+		if !seen[language] {
+			seen[language] = true
+			normalized = append(normalized, language)
+		}
+	}
+	return normalized
+}
+
+// SpeaksLanguage reports whether the driver listed the language of locale.
+func (d *Driver) SpeaksLanguage(locale string) bool {
+	language := PrimaryLanguage(locale)
+	for _, spoken := range d.Languages {
+		if spoken == language {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	FieldSourceOCR    = "ocr"
+	FieldSourceManual = "manual"
+
+	LicenseStatusNeedsReview = "needs_review"
+	LicenseStatusComplete    = "complete"
+)
+
+// ExtractedField is a value read from a document together with how sure the
+// reader was. Manual corrections always have confidence 1.
+type ExtractedField struct {
+	Value       string  `json:"value" bson:"value"`
+	Confidence  float64 `json:"confidence" bson:"confidence"`
+	Source      string  `json:"source" bson:"source"`
+	NeedsReview bool    `json:"needs_review" bson:"needs_review"`
+}
+
+// LicenseExtraction is what an OCR provider read from a license image.
+type LicenseExtraction struct {
+	FullName      ExtractedField
+	LicenseNumber ExtractedField
+	ExpiresAt     ExtractedField
+}
+
+type LicenseDocument struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id"`
+	DriverID      primitive.ObjectID `json:"driver_id" bson:"driver_id"`
+	FullName      ExtractedField     `json:"full_name" bson:"full_name"`
+	LicenseNumber ExtractedField     `json:"license_number" bson:"license_number"`
+	ExpiresAt     ExtractedField     `json:"expires_at" bson:"expires_at"`
+	Status        string             `json:"status" bson:"status"`
+	ImageSHA256   string             `json:"image_sha256" bson:"image_sha256"`
+	ImageSize     int                `json:"image_size" bson:"image_size"`
+	ContentType   string             `json:"content_type" bson:"content_type"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// RefreshStatus marks the document complete once no field needs review.
+func (d *LicenseDocument) RefreshStatus() {
+	d.Status = LicenseStatusComplete
+	for _, field := range []ExtractedField{d.FullName, d.LicenseNumber, d.ExpiresAt} {
+		if field.NeedsReview {
+			d.Status = LicenseStatusNeedsReview
+		}
+	}
+}
+
+type CorrectLicenseRequest struct {
+	FullName      *string `json:"full_name,omitempty" validate:"omitempty,min=2,max=100"`
+	LicenseNumber *string `json:"license_number,omitempty" validate:"omitempty,min=3,max=30"`
+	ExpiresAt     *string `json:"expires_at,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+func (r *CorrectLicenseRequest) Validate() error {
+	return newValidator().Struct(r)
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Turkish driving license classes relevant to passenger vehicles.
+const (
+	LicenseClassA  = "A"
+	LicenseClassB  = "B"
+	LicenseClassBE = "BE"
+	LicenseClassC1 = "C1"
+	LicenseClassC  = "C"
+	LicenseClassD1 = "D1"
+	LicenseClassD  = "D"
+)
+
+// licenseClassCoverage lists the classes each license also permits, e.g. a
+// D1 holder may drive B vehicles.
+var licenseClassCoverage = map[string][]string{
+	LicenseClassA:  {LicenseClassA},
+	LicenseClassB:  {LicenseClassB},
+	LicenseClassBE: {LicenseClassB, LicenseClassBE},
+	LicenseClassC1: {LicenseClassB, LicenseClassC1},
+	LicenseClassC:  {LicenseClassB, LicenseClassC1, LicenseClassC},
+	LicenseClassD1: {LicenseClassB, LicenseClassD1},
+	LicenseClassD:  {LicenseClassB, LicenseClassD1, LicenseClassD},
+}
+
+func IsValidLicenseClass(class string) bool {
+	_, ok := licenseClassCoverage[class]
+	return ok
+}
+
+// LicensePermits tells whether any of the held classes permits required.
+func LicensePermits(held []string, required string) bool {
+	for _, class := range held {
+		for _, covered := range licenseClassCoverage[class] {
+			if covered == required {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LicenseClassRequirements maps taxi types to the license class needed to
+// drive them.
+type LicenseClassRequirements map[string]string
+
+// DefaultLicenseClassRequirements require a B license for every taxi type.
+func DefaultLicenseClassRequirements() LicenseClassRequirements {
+	return LicenseClassRequirements{
+		TaxiTypeSari:    LicenseClassB,
+		TaxiTypeTurkuaz: LicenseClassB,
+		TaxiTypeSiyah:   LicenseClassB,
+	}
+}
+
+// NewLicenseClassRequirements overlays overrides, keyed by taxi type, on the
+// defaults.
+func NewLicenseClassRequirements(overrides map[string]string) (LicenseClassRequirements, error) {
+	requirements := DefaultLicenseClassRequirements()
+	for taxiType, class := range overrides {
+		if !IsValidTaxiType(taxiType) {
+			return nil, fmt.Errorf("unknown taxi type %q", taxiType)
+		}
+		if !IsValidLicenseClass(class) {
+			return nil, fmt.Errorf("unknown license class %q for taxi type %s", class, taxiType)
+		}
+		requirements[taxiType] = class
+	}
+	return requirements, nil
+}
+
+// NormalizeLicenseClasses sorts and de-duplicates classes.
+func NormalizeLicenseClasses(classes []string) []string {
+	if len(classes) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(classes))
+	normalized := make([]string, 0, len(classes))
+	for _, class := range classes {
+		if !seen[class] {
+			seen[class] = true
+			normalized = append(normalized, class)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// LicenseOverride lets an admin attach a driver to a taxi type their license
+// class does not permit.
+type LicenseOverride struct {
+	Reason     string `json:"reason" validate:"required,max=500"`
+	ApprovedBy string `json:"approved_by" validate:"required,max=100"`
+}
+
+// LicenseOverrideRecord is the audit entry written for every override that
+// was used.
+type LicenseOverrideRecord struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id"`
+	DriverID       primitive.ObjectID `json:"driver_id" bson:"driver_id"`
+	TaxiType       string             `json:"taxi_type" bson:"taxi_type"`
+	RequiredClass  string             `json:"required_class" bson:"required_class"`
+	LicenseClasses []string           `json:"license_classes" bson:"license_classes"`
+	Reason         string             `json:"reason" bson:"reason"`
+	ApprovedBy     string             `json:"approved_by" bson:"approved_by"`
+	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,114 @@
+package models
+
+import "time"
+
+// Frame types on the /ws/drivers stream.
+const (
+	LiveSubscribe   = "subscribe"
+	LiveUnsubscribe = "unsubscribe"
+	LiveSubscribed  = "subscribed"
+	LiveLocation    = "location"
+	LiveError       = "error"
+	LiveSession     = "session"
+	LiveOffer       = "offer"
+	LiveEarnings    = "earnings"
+)
+
+// BoundingBox is a map area. It does not wrap around the antimeridian.
+type BoundingBox struct {
+	MinLat float64 `json:"min_lat" validate:"min=-90,max=90"`
+	MinLon float64 `json:"min_lon" validate:"min=-180,max=180"`
+	MaxLat float64 `json:"max_lat" validate:"min=-90,max=90,gtefield=MinLat"`
+	MaxLon float64 `json:"max_lon" validate:"min=-180,max=180,gtefield=MinLon"`
+}
+
+func (b *BoundingBox) Validate() error {
+	return newValidator().Struct(b)
+}
+
+func (b BoundingBox) Contains(location Location) bool {
+	return location.Lat >= b.MinLat && location.Lat <= b.MaxLat &&
+		location.Lon >= b.MinLon && location.Lon <= b.MaxLon
+}
+
+// LiveClientMessage is a frame sent by a client. Dispatchers send subscribe
+// (with BBox) and unsubscribe; drivers send location with their own ID.
+type LiveClientMessage struct {
+	Type     string       `json:"type"`
+	BBox     *BoundingBox `json:"bbox,omitempty"`
+	DriverID string       `json:"driver_id,omitempty"`
+	Lat      float64      `json:"lat"`
+	Lon      float64      `json:"lon"`
+}
+
+// LiveStatusMessage answers a client frame: subscribed echoes the BBox, and
+// error carries the Message. The session frame opens every stream with its
+// Session ID, and the BBox restored from it.
+type LiveStatusMessage struct {
+	Type    string       `json:"type"`
+	Message string       `json:"message,omitempty"`
+	BBox    *BoundingBox `json:"bbox,omitempty"`
+	Session string       `json:"session,omitempty"`
+}
+
+// LiveLocationMessage is pushed to subscribers for every location update
+// inside their area.
+type LiveLocationMessage struct {
+	Type       string    `json:"type"`
+	DriverID   string    `json:"driver_id"`
+	FleetID    string    `json:"fleet_id,omitempty"`
+	Location   Location  `json:"location"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+func NewLiveLocationMessage(sample LocationSample) LiveLocationMessage {
+	return LiveLocationMessage{
+		Type:       LiveLocation,
+		DriverID:   sample.DriverID.Hex(),
+		FleetID:    sample.FleetID,
+		Location:   sample.Location,
+		RecordedAt: sample.RecordedAt,
+	}
+}
+
+// LiveOfferMessage is pushed to the connection of a driver assigned a
+// pickup. Trip offers carry the trip ID, when the offer expires and the
+// rider's preferences.
+type LiveOfferMessage struct {
+	Type        string           `json:"type"`
+	DriverID    string           `json:"driver_id"`
+	FleetID     string           `json:"fleet_id,omitempty"`
+	TripID      string           `json:"trip_id,omitempty"`
+	Pickup      Location         `json:"pickup"`
+	DistanceKm  float64          `json:"distance_km"`
+	OfferedAt   time.Time        `json:"offered_at"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty"`
+	Preferences *RidePreferences `json:"preferences,omitempty"`
+}
+
+// LiveEarningsMessage is pushed to a driver's connection for every change
+// to their earnings: a ride booked, its commission deducted or a bonus
+// added. Totals is the running total of the entry's day after it.
+type LiveEarningsMessage struct {
+	Type       string        `json:"type"`
+	DriverID   string        `json:"driver_id"`
+	Kind       string        `json:"kind"`
+	Amount     float64       `json:"amount"`
+	TripID     string        `json:"trip_id,omitempty"`
+	Note       string        `json:"note,omitempty"`
+	Totals     DailyEarnings `json:"totals"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
+
+func NewLiveEarningsMessage(update EarningsUpdate) LiveEarningsMessage {
+	return LiveEarningsMessage{
+		Type:       LiveEarnings,
+		DriverID:   update.Entry.DriverID,
+		Kind:       update.Entry.Kind,
+		Amount:     update.Entry.Amount,
+		TripID:     update.Entry.TripID,
+		Note:       update.Entry.Note,
+		Totals:     update.Totals,
+		OccurredAt: update.Entry.CreatedAt,
+	}
+}
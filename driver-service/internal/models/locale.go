@@ -0,0 +1,164 @@
+package models
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// DefaultLocale is the language the plain profile fields are written in.
+const DefaultLocale = "tr"
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// DriverLocalization holds per-locale overrides for the display fields of a
+// driver profile. Empty fields fall back to the next matching locale.
+type DriverLocalization struct {
+	CarBrand string `json:"car_brand,omitempty" bson:"car_brand,omitempty" validate:"omitempty,max=30"`
+	CarModel string `json:"car_model,omitempty" bson:"car_model,omitempty" validate:"omitempty,max=30"`
+	CarColor string `json:"car_color,omitempty" bson:"car_color,omitempty" validate:"omitempty,max=30"`
+	Bio      string `json:"bio,omitempty" bson:"bio,omitempty" validate:"omitempty,max=500"`
+}
+
+func (l DriverLocalization) IsEmpty() bool {
+	return l.CarBrand == "" && l.CarModel == "" && l.CarColor == "" && l.Bio == ""
+}
+
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+func IsValidLocale(locale string) bool {
+	return localePattern.MatchString(NormalizeLocale(locale))
+}
+
+func LocaleValidator(fl validator.FieldLevel) bool {
+	return IsValidLocale(fl.Field().String())
+}
+
+// NormalizeLocalizations lowercases locale keys so lookups are case
+// insensitive.
+func NormalizeLocalizations(localizations map[string]DriverLocalization) map[string]DriverLocalization {
+	if len(localizations) == 0 {
+		return nil
+	}
+
+	normalized := make(map[string]DriverLocalization, len(localizations))
+	for locale, localization := range localizations {
+		normalized[NormalizeLocale(locale)] = localization
+	}
+	return normalized
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header in
+// order of preference, dropping wildcards and entries with q=0.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var entries []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		locale := NormalizeLocale(fields[0])
+		if locale == "" || locale == "*" || !IsValidLocale(locale) {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if parsed, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		entries = append(entries, weighted{locale: locale, q: q})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].q > entries[j].q
+	})
+
+	locales := make([]string, len(entries))
+	for i, entry := range entries {
+		locales[i] = entry.locale
+	}
+	return locales
+}
+
+// Localize resolves the display fields for the preferred locales. Each field
+// is taken from the first preferred locale that defines it, trying the exact
+// tag before its base language (en-gb, then en), and falls back to the
+// driver's default fields. The returned locale is the one that supplied the
+// most specific match, or DefaultLocale when nothing matched.
+func (d *Driver) Localize(preferred []string) (DriverLocalization, string) {
+	resolved := DriverLocalization{
+		CarBrand: d.CarBrand,
+		CarModel: d.CarModel,
+		CarColor: d.CarColor,
+		Bio:      d.Bio,
+	}
+
+	var candidates []string
+	seen := make(map[string]bool)
+	for _, locale := range preferred {
+		locale = NormalizeLocale(locale)
+		for _, candidate := range []string{locale, strings.SplitN(locale, "-", 2)[0]} {
+			if !seen[candidate] {
+				seen[candidate] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	var overrides []DriverLocalization
+	matched := ""
+	for _, candidate := range candidates {
+		if candidate == DefaultLocale {
+			// The default fields are written in this locale, so lower
+			// preferences can no longer contribute.
+			if matched == "" {
+				matched = DefaultLocale
+			}
+			break
+		}
+		localization, ok := d.Localizations[candidate]
+		if !ok || localization.IsEmpty() {
+			continue
+		}
+		if matched == "" {
+			matched = candidate
+		}
+		overrides = append(overrides, localization)
+	}
+
+	for i := len(overrides) - 1; i >= 0; i-- {
+		override := overrides[i]
+		if override.CarBrand != "" {
+			resolved.CarBrand = override.CarBrand
+		}
+		if override.CarModel != "" {
+			resolved.CarModel = override.CarModel
+		}
+		if override.CarColor != "" {
+			resolved.CarColor = override.CarColor
+		}
+		if override.Bio != "" {
+			resolved.Bio = override.Bio
+		}
+	}
+
+	if matched == "" {
+		matched = DefaultLocale
+	}
+	return resolved, matched
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxLocationClockSkew is how far in the future a buffered point may be
+// stamped before it is rejected; phone clocks drift.
+const MaxLocationClockSkew = time.Minute
+
+// Bounds of a location trace query.
+const (
+	DefaultLocationTraceWindow = time.Hour
+	MaxLocationTraceWindow     = 7 * 24 * time.Hour
+	DefaultLocationTraceLimit  = 1000
+	MaxLocationTraceLimit      = 10000
+)
+
+// LocationPoint is one GPS fix buffered by a driver app while offline.
+type LocationPoint struct {
+	Lat        float64   `json:"lat" validate:"required,min=-90,max=90"`
+	Lon        float64   `json:"lon" validate:"required,min=-180,max=180"`
+	RecordedAt time.Time `json:"recorded_at" validate:"required"`
+	Accuracy   float64   `json:"accuracy,omitempty" validate:"min=0,max=10000"`
+}
+
+func (p LocationPoint) Location() Location {
+	return Location{Lat: p.Lat, Lon: p.Lon}
+}
+
+type BatchLocationRequest struct {
+	Points []LocationPoint `json:"points" validate:"required,min=1,dive"`
+}
+
+func (r *BatchLocationRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+// SortedPoints returns the points oldest first.
+func (r *BatchLocationRequest) SortedPoints() []LocationPoint {
+	points := append([]LocationPoint(nil), r.Points...)
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].RecordedAt.Before(points[j].RecordedAt)
+	})
+	return points
+}
+
+// LocationHistoryEntry is one point of a driver's location trace, stored in
+// the driver_locations time-series collection.
+type LocationHistoryEntry struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	DriverID   primitive.ObjectID `json:"driver_id" bson:"driver_id"`
+	Location   Location           `json:"location" bson:"location"`
+	RecordedAt time.Time          `json:"recorded_at" bson:"recorded_at"`
+	ReceivedAt time.Time          `json:"received_at" bson:"received_at"`
+}
+
+// BatchLocationResult reports what a batch did. Duplicates are points already
+// received, e.g. from an earlier attempt of the same batch.
+type BatchLocationResult struct {
+	Accepted       int  `json:"accepted"`
+	CurrentUpdated bool `json:"current_updated"`
+	HistoryStored  int  `json:"history_stored"`
+	Duplicates     int  `json:"duplicates"`
+	// Rejected counts the points that failed the location checks, by
+	// reason in RejectedReasons; they are neither applied nor stored.
+	Rejected        int            `json:"rejected,omitempty"`
+	RejectedReasons map[string]int `json:"rejected_reasons,omitempty"`
+}
+
+// LocationTrace is a driver's recorded path between From and To, oldest point
+// first. Truncated is set when more points exist than the limit allowed.
+type LocationTrace struct {
+	DriverID  string          `json:"driver_id"`
+	From      time.Time       `json:"from"`
+	To        time.Time       `json:"to"`
+	Points    []LocationPoint `json:"points"`
+	Truncated bool            `json:"truncated"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	MaintenanceTypeOilChange  = "oil_change"
+	MaintenanceTypeTires      = "tires"
+	MaintenanceTypeBrakes     = "brakes"
+	MaintenanceTypeInspection = "inspection"
+	MaintenanceTypeOther      = "other"
+)
+
+// MaintenanceInterval describes when a maintenance type is due again,
+// whichever of distance or time comes first. Zero values disable a limit.
+type MaintenanceInterval struct {
+	Km       float64
+	Duration time.Duration
+}
+
+var MaintenanceIntervals = map[string]MaintenanceInterval{
+	MaintenanceTypeOilChange:  {Km: 10000, Duration: 365 * 24 * time.Hour},
+	MaintenanceTypeTires:      {Km: 40000, Duration: 4 * 365 * 24 * time.Hour},
+	MaintenanceTypeBrakes:     {Km: 30000, Duration: 2 * 365 * 24 * time.Hour},
+	MaintenanceTypeInspection: {Duration: 365 * 24 * time.Hour},
+}
+
+type MaintenanceRecord struct {
+	ID                  primitive.ObjectID `json:"id" bson:"_id"`
+	DriverID            primitive.ObjectID `json:"driver_id" bson:"driver_id"`
+	Plate               string             `json:"plate" bson:"plate"`
+	Type                string             `json:"type" bson:"type"`
+	OdometerKm          float64            `json:"odometer_km" bson:"odometer_km"`
+	TraveledKmAtService float64            `json:"traveled_km_at_service" bson:"traveled_km_at_service"`
+	Notes               string             `json:"notes,omitempty" bson:"notes,omitempty"`
+	PerformedAt         time.Time          `json:"performed_at" bson:"performed_at"`
+	ReminderSentAt      *time.Time         `json:"reminder_sent_at,omitempty" bson:"reminder_sent_at,omitempty"`
+	CreatedAt           time.Time          `json:"created_at" bson:"created_at"`
+}
+
+type CreateMaintenanceRequest struct {
+	Type        string     `json:"type" validate:"required,oneof=oil_change tires brakes inspection other"`
+	OdometerKm  float64    `json:"odometer_km" validate:"min=0"`
+	Notes       string     `json:"notes" validate:"max=500"`
+	PerformedAt *time.Time `json:"performed_at"`
+}
+
+func (r *CreateMaintenanceRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type MaintenanceDue struct {
+	Type           string    `json:"type"`
+	LastServicedAt time.Time `json:"last_serviced_at"`
+	KmSinceService float64   `json:"km_since_service"`
+	DueByKm        bool      `json:"due_by_km"`
+	DueByTime      bool      `json:"due_by_time"`
+}
@@ -0,0 +1,120 @@
+package models
+
+import "math"
+
+const (
+	// DefaultNearbyRadiusKm is the search radius when no ETA bound is given.
+	DefaultNearbyRadiusKm = 5.0
+	// DefaultNearbyLimit caps the results when no limit is given.
+	DefaultNearbyLimit = 50
+	// MaxNearbyETAMinutes is the largest accepted max_eta_minutes.
+	MaxNearbyETAMinutes = 60
+
+	// etaSpeedKmh is the assumed average city speed over the straight-line
+	// distance, which already absorbs the detour of real roads.
+	etaSpeedKmh = 20.0
+)
+
+// NearbyFilter narrows a nearby search. Repositories push the filters into
+// the query so they are applied before the result limit.
+type NearbyFilter struct {
+	TaxiType     string
+	VerifiedOnly bool
+	// MaxETAMinutes shrinks the search radius to what a driver can cover in
+	// that time; zero keeps the default radius.
+	MaxETAMinutes int
+	// IncludeUnavailable also returns busy and offline drivers, which are
+	// left out by default.
+	IncludeUnavailable bool
+	// RadiusKm overrides DefaultNearbyRadiusKm when positive; an ETA bound
+	// can still shrink it.
+	RadiusKm float64
+	// Limit caps the results when positive, otherwise DefaultNearbyLimit.
+	Limit int
+	// MinRating keeps drivers rated at least this on average; unrated
+	// drivers are left out. Zero does not filter.
+	MinRating float64
+}
+
+// ResultLimit returns the number of drivers a search may return.
+func (f NearbyFilter) ResultLimit() int {
+	if f.Limit > 0 {
+		return f.Limit
+	}
+	return DefaultNearbyLimit
+}
+
+// Matches reports whether the driver passes the filter. Drivers that are not
+// approved never do.
+func (f NearbyFilter) Matches(driver *Driver) bool {
+	if !driver.IsApproved() {
+		return false
+	}
+	if f.TaxiType != "" && IsValidTaxiType(f.TaxiType) && driver.TaxiType != f.TaxiType {
+		return false
+	}
+	if f.VerifiedOnly && !driver.Verified {
+		return false
+	}
+	if !f.IncludeUnavailable && !driver.IsAvailable() {
+		return false
+	}
+	if f.MinRating > 0 && (driver.RatingCount == 0 || driver.AverageRating < f.MinRating) {
+		return false
+	}
+	return true
+}
+
+// EstimateETAMinutes estimates the pickup time for a driver distanceKm away,
+// rounded up to whole minutes.
+func EstimateETAMinutes(distanceKm float64) int {
+	return int(math.Ceil(distanceKm / etaSpeedKmh * 60))
+}
+
+// RadiusForETA is the distance reachable within etaMinutes.
+func RadiusForETA(etaMinutes int) float64 {
+	return float64(etaMinutes) * etaSpeedKmh / 60
+}
+
+// NearbyBatchPoint is one pickup point of a batch nearby lookup. ID is an
+// optional caller reference echoed back with the point's candidates.
+type NearbyBatchPoint struct {
+	ID            string  `json:"id" validate:"omitempty,max=64"`
+	Lat           float64 `json:"lat" validate:"required,min=-90,max=90"`
+	Lon           float64 `json:"lon" validate:"required,min=-180,max=180"`
+	TaxiType      string  `json:"taxi_type" validate:"omitempty,oneof=sari turkuaz siyah"`
+	VerifiedOnly  bool    `json:"verified_only"`
+	MaxETAMinutes int     `json:"max_eta_minutes" validate:"omitempty,min=1,max=60"`
+	MinRating     float64 `json:"min_rating" validate:"omitempty,min=1,max=5"`
+
+	IncludeUnavailable bool `json:"include_unavailable"`
+}
+
+func (p NearbyBatchPoint) Filter() NearbyFilter {
+	return NearbyFilter{
+		TaxiType:      p.TaxiType,
+		VerifiedOnly:  p.VerifiedOnly,
+		MaxETAMinutes: p.MaxETAMinutes,
+		MinRating:     p.MinRating,
+
+		IncludeUnavailable: p.IncludeUnavailable,
+	}
+}
+
+type NearbyBatchRequest struct {
+	Points []NearbyBatchPoint `json:"points" validate:"required,min=1,dive"`
+}
+
+func (r *NearbyBatchRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+// NearbyBatchResult holds the candidates for one point, in request order.
+// A point whose lookup failed carries Error instead of failing the batch.
+type NearbyBatchResult struct {
+	ID      string                        `json:"id,omitempty"`
+	Lat     float64                       `json:"lat"`
+	Lon     float64                       `json:"lon"`
+	Drivers []*DriverWithDistanceResponse `json:"drivers"`
+	Error   string                        `json:"error,omitempty"`
+}
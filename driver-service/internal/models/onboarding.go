@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// Onboarding steps of a driver applicant, in funnel order. Each is recorded
+// the first time the driver gets there.
+const (
+	OnboardingRegistered       = "registered"
+	OnboardingLicenseSubmitted = "license_submitted"
+	OnboardingPhotoSubmitted   = "photo_submitted"
+	OnboardingVerified         = "verified"
+	OnboardingApproved         = "approved"
+	OnboardingFirstTrip        = "first_trip"
+)
+
+// OnboardingSteps lists the steps in funnel order.
+var OnboardingSteps = []string{
+	OnboardingRegistered,
+	OnboardingLicenseSubmitted,
+	OnboardingPhotoSubmitted,
+	OnboardingVerified,
+	OnboardingApproved,
+	OnboardingFirstTrip,
+}
+
+// Bounds of an onboarding report.
+const (
+	DefaultOnboardingWindow      = 30 * 24 * time.Hour
+	DefaultStalledOnboardingAge  = 72 * time.Hour
+	DefaultStalledOnboardingList = 50
+	MaxStalledOnboardingList     = 200
+)
+
+// OnboardingStepIndex returns the position of the step in the funnel, or -1
+// for an unknown step.
+func OnboardingStepIndex(step string) int {
+	for i, known := range OnboardingSteps {
+		if known == step {
+			return i
+		}
+	}
+	return -1
+}
+
+// OnboardingFunnel follows the drivers who registered in a time range
+// through the onboarding steps. A driver reaches a step once it and every
+// step before it are done, in whatever order.
+type OnboardingFunnel struct {
+	FleetID string                 `json:"fleet_id,omitempty"`
+	From    time.Time              `json:"from"`
+	To      time.Time              `json:"to"`
+	Steps   []OnboardingFunnelStep `json:"steps"`
+}
+
+// OnboardingFunnelStep is one step of the funnel. Conversion is the share of
+// the drivers at the previous step who reached this one, and DroppedOff how
+// many of them did not. AvgHours is the mean time from registration to the
+// step for the drivers who reached it.
+type OnboardingFunnelStep struct {
+	Step       string   `json:"step"`
+	Reached    int      `json:"reached"`
+	DroppedOff int      `json:"dropped_off"`
+	Conversion float64  `json:"conversion"`
+	AvgHours   *float64 `json:"avg_hours,omitempty"`
+}
+
+// OnboardingStepCount is how many drivers reached the step at Index, and
+// their mean hours from registration to it.
+type OnboardingStepCount struct {
+	Index    int     `bson:"_id"`
+	Reached  int     `bson:"reached"`
+	AvgHours float64 `bson:"avg_hours"`
+}
+
+// StalledDriver is an applicant who got up to a step and has not gone on to
+// the next one since StalledSince.
+type StalledDriver struct {
+	ID           string               `json:"id"`
+	FirstName    string               `json:"first_name"`
+	LastName     string               `json:"last_name"`
+	FleetID      string               `json:"fleet_id,omitempty"`
+	ReviewStatus string               `json:"review_status,omitempty"`
+	StalledSince time.Time            `json:"stalled_since"`
+	Onboarding   map[string]time.Time `json:"onboarding"`
+}
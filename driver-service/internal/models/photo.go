@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	PhotoPending  = "pending"
+	PhotoApproved = "approved"
+	PhotoRejected = "rejected"
+	// PhotoSuperseded marks a pending photo replaced by a newer upload, or an
+	// approved photo replaced by a newer approval.
+	PhotoSuperseded = "superseded"
+)
+
+const (
+	PhotoCheckPassed  = "passed"
+	PhotoCheckFailed  = "failed"
+	PhotoCheckSkipped = "skipped"
+)
+
+// AutomatedReviewer is recorded as the reviewer of photos rejected by the
+// automated checks.
+const AutomatedReviewer = "automated"
+
+type PhotoCheck struct {
+	Name   string `json:"name" bson:"name"`
+	Result string `json:"result" bson:"result"`
+	Detail string `json:"detail,omitempty" bson:"detail,omitempty"`
+}
+
+// DriverPhoto is an uploaded profile photo. Only the approved one is served
+// to riders.
+type DriverPhoto struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	DriverID    primitive.ObjectID `json:"driver_id" bson:"driver_id"`
+	ContentType string             `json:"content_type" bson:"content_type"`
+	Size        int                `json:"size" bson:"size"`
+	Width       int                `json:"width" bson:"width"`
+	Height      int                `json:"height" bson:"height"`
+	Image       []byte             `json:"-" bson:"image,omitempty"`
+	StorageKey  string             `json:"-" bson:"storage_key,omitempty"`
+	Checks      []PhotoCheck       `json:"checks" bson:"checks"`
+	Status      string             `json:"status" bson:"status"`
+	ReviewNote  string             `json:"review_note,omitempty" bson:"review_note,omitempty"`
+	ReviewedBy  string             `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time         `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+}
+
+func (p *DriverPhoto) FailedChecks() []string {
+	var failed []string
+	for _, check := range p.Checks {
+		if check.Result == PhotoCheckFailed {
+			failed = append(failed, check.Name)
+		}
+	}
+	return failed
+}
+
+type ReviewPhotoRequest struct {
+	Note       string `json:"note" validate:"max=500"`
+	ReviewedBy string `json:"reviewed_by" validate:"max=100"`
+}
+
+func (r *ReviewPhotoRequest) Validate() error {
+	return newValidator().Struct(r)
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+var platePartsPattern = regexp.MustCompile(`^([0-9]{2})([A-Z]{1,3})([0-9]{1,4})$`)
+
+// PlateVariants returns the spellings a plate may be stored under: as typed
+// (upper-cased, trimmed) and in the spaced form "34 ABC 123".
+func PlateVariants(plate string) []string {
+	plate = strings.ToUpper(strings.TrimSpace(plate))
+	if plate == "" {
+		return nil
+	}
+
+	variants := []string{plate}
+	compact := strings.Join(strings.Fields(plate), "")
+	if parts := platePartsPattern.FindStringSubmatch(compact); parts != nil {
+		for _, variant := range []string{parts[1] + " " + parts[2] + " " + parts[3], compact} {
+			if variant != plate {
+				variants = append(variants, variant)
+			}
+		}
+	}
+	return variants
+}
+
+// PlateVerification is the public answer to "is this car a registered
+// taxi". It deliberately carries no personal data.
+type PlateVerification struct {
+	Plate    string `json:"plate"`
+	Verified bool   `json:"verified"`
+	TaxiType string `json:"taxi_type,omitempty"`
+	PhotoURL string `json:"photo_url,omitempty"`
+}
+
+// MaxDriverSearchQueryLength bounds the q of a driver search.
+const MaxDriverSearchQueryLength = 100
+
+// DriverSearchTerms returns the terms a driver search looks for: the words
+// typed plus, when they spell a plate, its other stored spellings, so
+// "34ABC123" also finds a plate stored as "34 ABC 123" and the other way
+// round.
+func DriverSearchTerms(query string) string {
+	terms := strings.Fields(query)
+	if variants := PlateVariants(query); len(variants) > 1 {
+		terms = append(terms, variants[1:]...)
+	}
+	return strings.Join(terms, " ")
+}
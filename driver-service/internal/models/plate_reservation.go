@@ -0,0 +1,32 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// PlateReservation holds a plate for one onboarding flow until ExpiresAt,
+// so a concurrent registration of the same plate fails early. ID is the
+// plate's PlateKey.
+type PlateReservation struct {
+	ID        string    `json:"plate" bson:"_id"`
+	Token     string    `json:"token" bson:"token"`
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// PlateKey is the spelling-independent form of a plate ("34 abc 123" and
+// "34ABC123" share a key).
+func PlateKey(plate string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(plate)), "")
+}
+
+type ReservePlateRequest struct {
+	Plate string `json:"plate" validate:"required,turkish_plate"`
+	// Token renews a reservation the caller already holds.
+	Token string `json:"token" validate:"omitempty,max=64"`
+}
+
+func (r *ReservePlateRequest) Validate() error {
+	return newValidator().Struct(r)
+}
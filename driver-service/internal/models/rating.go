@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Bounds of a rating and of a rating list.
+const (
+	MinRatingStars         = 1
+	MaxRatingStars         = 5
+	DefaultRatingListLimit = 50
+	MaxRatingListLimit     = 200
+)
+
+// Rating is one rider's 1-5 star rating of a driver, optionally for one of
+// their trips.
+type Rating struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	DriverID  primitive.ObjectID `json:"driver_id" bson:"driver_id"`
+	TripID    string             `json:"trip_id,omitempty" bson:"trip_id,omitempty"`
+	Stars     int                `json:"stars" bson:"stars"`
+	Comment   string             `json:"comment,omitempty" bson:"comment,omitempty"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// CreateRatingRequest rates a driver. With TripID it rates that trip, which
+// must be the driver's and completed, and may be rated only once.
+type CreateRatingRequest struct {
+	Stars   int    `json:"stars" validate:"required,min=1,max=5"`
+	Comment string `json:"comment" validate:"max=500"`
+	TripID  string `json:"trip_id" validate:"omitempty,len=24,hexadecimal"`
+}
+
+func (r *CreateRatingRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+// RatingResult is a stored rating with the driver's aggregates after it.
+type RatingResult struct {
+	Rating        Rating  `json:"rating"`
+	AverageRating float64 `json:"average_rating"`
+	RatingCount   int     `json:"rating_count"`
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Rating prompt statuses. A prompt is pending while prompts are still due,
+// exhausted once the last one was sent without a rating, rated as soon as
+// the ride is rated, and cancelled if its fleet turned prompts off before
+// the ride was rated.
+const (
+	RatingPromptPending   = "pending"
+	RatingPromptRated     = "rated"
+	RatingPromptExhausted = "exhausted"
+	RatingPromptCancelled = "cancelled"
+)
+
+// DefaultRatingPromptStatsWindow is how far back prompt stats look unless
+// told otherwise.
+const DefaultRatingPromptStatsWindow = 30 * 24 * time.Hour
+
+// RatingPrompt asks the rider of a completed trip to rate their driver, and
+// reminds them until they do or the fleet's reminders run out. Sent counts
+// the prompts sent so far, so a rated prompt with Sent > 0 is a conversion.
+type RatingPrompt struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	TripID     string             `json:"trip_id" bson:"trip_id"`
+	DriverID   string             `json:"driver_id" bson:"driver_id"`
+	FleetID    string             `json:"fleet_id,omitempty" bson:"fleet_id,omitempty"`
+	Status     string             `json:"status" bson:"status"`
+	Sent       int                `json:"sent" bson:"sent"`
+	NextAt     *time.Time         `json:"next_at,omitempty" bson:"next_at,omitempty"`
+	LastSentAt *time.Time         `json:"last_sent_at,omitempty" bson:"last_sent_at,omitempty"`
+	RatedAt    *time.Time         `json:"rated_at,omitempty" bson:"rated_at,omitempty"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// RatingPromptSettings tune prompt timing for a fleet. The first prompt
+// goes out Delay after the trip completes; the first reminder Reminder
+// after that, each further reminder waiting twice as long as the one
+// before, until MaxPrompts prompts have been sent.
+type RatingPromptSettings struct {
+	FleetID         string     `json:"fleet_id" bson:"_id"`
+	Enabled         bool       `json:"enabled" bson:"enabled"`
+	DelaySeconds    int        `json:"delay_seconds" bson:"delay_seconds"`
+	ReminderSeconds int        `json:"reminder_seconds" bson:"reminder_seconds"`
+	MaxPrompts      int        `json:"max_prompts" bson:"max_prompts"`
+	Default         bool       `json:"default" bson:"-"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// NextPromptAt returns when the prompt after the sent ones is due, counting
+// from the trip's completion for the first prompt and from the last prompt
+// for reminders. It reports false once no more prompts are due.
+func (s *RatingPromptSettings) NextPromptAt(sent int, from time.Time) (time.Time, bool) {
+	if sent >= s.MaxPrompts {
+		return time.Time{}, false
+	}
+	if sent == 0 {
+		return from.Add(time.Duration(s.DelaySeconds) * time.Second), true
+	}
+	wait := time.Duration(s.ReminderSeconds) * time.Second
+	for i := 1; i < sent; i++ {
+		wait *= 2
+	}
+	return from.Add(wait), true
+}
+
+type UpdateRatingPromptSettingsRequest struct {
+	Enabled         *bool `json:"enabled" validate:"required"`
+	DelaySeconds    int   `json:"delay_seconds" validate:"min=0,max=86400"`
+	ReminderSeconds int   `json:"reminder_seconds" validate:"min=60,max=604800"`
+	MaxPrompts      int   `json:"max_prompts" validate:"min=1,max=10"`
+}
+
+func (r *UpdateRatingPromptSettingsRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+// RatingPromptStats is the prompt funnel of the trips completed in a window.
+// Converted counts rides rated after a prompt; RatedUnprompted those rated
+// before their first prompt went out. ConversionRate is Converted over the
+// prompted rides.
+type RatingPromptStats struct {
+	FleetID         string             `json:"fleet_id,omitempty"`
+	Since           time.Time          `json:"since"`
+	Scheduled       int                `json:"scheduled"`
+	Prompted        int                `json:"prompted"`
+	Converted       int                `json:"converted"`
+	RatedUnprompted int                `json:"rated_unprompted"`
+	Pending         int                `json:"pending"`
+	Exhausted       int                `json:"exhausted"`
+	Cancelled       int                `json:"cancelled"`
+	ConversionRate  float64            `json:"conversion_rate"`
+	ByPrompt        []RatingPromptStep `json:"by_prompt"`
+}
+
+// RatingPromptStep is one prompt of the sequence: how many rides got that
+// prompt, and how many were rated after it and before the next one.
+type RatingPromptStep struct {
+	Prompt    int `json:"prompt"`
+	Reached   int `json:"reached"`
+	Converted int `json:"converted"`
+}
+
+// RatingPromptCount is the number of prompts with a status and sent count.
+type RatingPromptCount struct {
+	Status string `bson:"status"`
+	Sent   int    `bson:"sent"`
+	Count  int    `bson:"count"`
+}
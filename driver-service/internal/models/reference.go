@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// taxiTypeNames are the display names of the taxi types, in DefaultLocale.
+var taxiTypeNames = map[string]string{
+	TaxiTypeSari:    "Sarı Taksi",
+	TaxiTypeTurkuaz: "Turkuaz Taksi",
+	TaxiTypeSiyah:   "Siyah Taksi",
+}
+
+type TaxiTypeInfo struct {
+	Code                 string `json:"code"`
+	Name                 string `json:"name"`
+	RequiredLicenseClass string `json:"required_license_class"`
+}
+
+// Tariff is the metered fare of a taxi type: the opening fee plus PerKm per
+// kilometre, but never less than MinimumFare.
+type Tariff struct {
+	TaxiType    string  `json:"taxi_type"`
+	Currency    string  `json:"currency"`
+	OpeningFee  float64 `json:"opening_fee"`
+	PerKm       float64 `json:"per_km"`
+	MinimumFare float64 `json:"minimum_fare"`
+}
+
+// ParseTariffs reads tariffs keyed by taxi type, each given as
+// "opening:per_km:minimum".
+func ParseTariffs(specs map[string]string, currency string) ([]Tariff, error) {
+	tariffs := make([]Tariff, 0, len(specs))
+	for taxiType, spec := range specs {
+		if !IsValidTaxiType(taxiType) {
+			return nil, fmt.Errorf("unknown taxi type %q", taxiType)
+		}
+
+		parts := strings.Split(spec, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("tariff for %s must be opening:per_km:minimum, got %q", taxiType, spec)
+		}
+		var amounts [3]float64
+		for i, part := range parts {
+			amount, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil || amount < 0 {
+				return nil, fmt.Errorf("invalid tariff amount %q for %s", part, taxiType)
+			}
+			amounts[i] = amount
+		}
+
+		tariffs = append(tariffs, Tariff{
+			TaxiType:    taxiType,
+			Currency:    currency,
+			OpeningFee:  amounts[0],
+			PerKm:       amounts[1],
+			MinimumFare: amounts[2],
+		})
+	}
+
+	sort.Slice(tariffs, func(i, j int) bool { return tariffs[i].TaxiType < tariffs[j].TaxiType })
+	return tariffs, nil
+}
+
+// ReferenceData is the slow-changing configuration mobile apps sync.
+type ReferenceData struct {
+	TaxiTypes []TaxiTypeInfo `json:"taxi_types"`
+	Tariffs   []Tariff       `json:"tariffs"`
+}
+
+func NewReferenceData(requirements LicenseClassRequirements, tariffs []Tariff) *ReferenceData {
+	taxiTypes := make([]TaxiTypeInfo, 0, len(taxiTypeNames))
+	for _, code := range []string{TaxiTypeSari, TaxiTypeTurkuaz, TaxiTypeSiyah} {
+		taxiTypes = append(taxiTypes, TaxiTypeInfo{
+			Code:                 code,
+			Name:                 taxiTypeNames[code],
+			RequiredLicenseClass: requirements[code],
+		})
+	}
+
+	if tariffs == nil {
+		tariffs = []Tariff{}
+	}
+	return &ReferenceData{
+		TaxiTypes: taxiTypes,
+		Tariffs:   tariffs,
+	}
+}
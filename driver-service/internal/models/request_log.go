@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RequestLog struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	RequestID string             `json:"request_id" bson:"request_id"`
+	Method    string             `json:"method" bson:"method"`
+	Route     string             `json:"route" bson:"route"`
+	Path      string             `json:"path" bson:"path"`
+	FleetID   string             `json:"fleet_id,omitempty" bson:"fleet_id,omitempty"`
+	Status    int                `json:"status" bson:"status"`
+	Body      string             `json:"body" bson:"body"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+}
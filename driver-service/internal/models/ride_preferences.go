@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Cabin temperatures a rider may ask for.
+const (
+	RideTemperatureCool    = "cool"
+	RideTemperatureNeutral = "neutral"
+	RideTemperatureWarm    = "warm"
+)
+
+// Music settings a rider may ask for. "any" leaves it to the driver.
+const (
+	RideMusicOff = "off"
+	RideMusicLow = "low"
+	RideMusicAny = "any"
+)
+
+// RidePreferences are what a rider asks of the ride: no small talk,
+// a cabin temperature and music. Empty fields mean no preference.
+type RidePreferences struct {
+	QuietRide   bool   `json:"quiet_ride" bson:"quiet_ride"`
+	Temperature string `json:"temperature,omitempty" bson:"temperature,omitempty" validate:"omitempty,oneof=cool neutral warm"`
+	Music       string `json:"music,omitempty" bson:"music,omitempty" validate:"omitempty,oneof=off low any"`
+}
+
+func (p *RidePreferences) Validate() error {
+	return newValidator().Struct(p)
+}
+
+// IsZero reports whether the rider asked for nothing.
+func (p *RidePreferences) IsZero() bool {
+	return !p.QuietRide && p.Temperature == "" && p.Music == ""
+}
+
+// RiderPreferences are a rider's saved preferences, copied onto the trips
+// created for them unless the trip brings its own.
+type RiderPreferences struct {
+	RiderID         string `json:"rider_id" bson:"_id"`
+	RidePreferences `bson:",inline"`
+	UpdatedAt       time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// PreferencesAck records that the assigned driver has seen the trip's
+// preferences.
+type PreferencesAck struct {
+	DriverID       string    `json:"driver_id" bson:"driver_id"`
+	AcknowledgedAt time.Time `json:"acknowledged_at" bson:"acknowledged_at"`
+}
+
+// MaxRiderIDLength bounds the rider IDs of the booking system.
+const MaxRiderIDLength = 64
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type TaxInfo struct {
+	TCKN           string `json:"tckn,omitempty" bson:"tckn,omitempty"`
+	TaxNumber      string `json:"tax_number,omitempty" bson:"tax_number,omitempty"`
+	BillingAddress string `json:"billing_address,omitempty" bson:"billing_address,omitempty"`
+}
+
+func (t *TaxInfo) IsEmpty() bool {
+	return t == nil || (t.TCKN == "" && t.TaxNumber == "" && t.BillingAddress == "")
+}
+
+// Masked returns a copy that only reveals the trailing digits of identity
+// numbers and the first line of the billing address.
+func (t *TaxInfo) Masked() *TaxInfo {
+	if t.IsEmpty() {
+		return nil
+	}
+
+	address := t.BillingAddress
+	if i := strings.IndexAny(address, ",\n"); i >= 0 {
+		address = address[:i] + ", ***"
+	}
+
+	return &TaxInfo{
+		TCKN:           maskDigits(t.TCKN, 2),
+		TaxNumber:      maskDigits(t.TaxNumber, 2),
+		BillingAddress: address,
+	}
+}
+
+func maskDigits(value string, visible int) string {
+	if len(value) <= visible {
+		return value
+	}
+	return strings.Repeat("*", len(value)-visible) + value[len(value)-visible:]
+}
+
+// IsValidTCKN checks the length, leading digit and both checksum digits of a
+// Turkish national identity number.
+func IsValidTCKN(tckn string) bool {
+	if len(tckn) != 11 || tckn[0] == '0' {
+		return false
+	}
+
+	var digits [11]int
+	for i, r := range tckn {
+		if r < '0' || r > '9' {
+			return false
+		}
+		digits[i] = int(r - '0')
+	}
+
+	odd := digits[0] + digits[2] + digits[4] + digits[6] + digits[8]
+	even := digits[1] + digits[3] + digits[5] + digits[7]
+	if ((odd*7-even)%10+10)%10 != digits[9] {
+		return false
+	}
+
+	sum := 0
+	for _, d := range digits[:10] {
+		sum += d
+	}
+	return sum%10 == digits[10]
+}
+
+// IsValidTaxNumber checks the 10-digit Turkish tax number (vergi kimlik no)
+// against its checksum digit.
+func IsValidTaxNumber(taxNumber string) bool {
+	if len(taxNumber) != 10 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 9; i++ {
+		r := taxNumber[i]
+		if r < '0' || r > '9' {
+			return false
+		}
+
+		tmp := (int(r-'0') + 9 - i) % 10
+		if tmp != 0 {
+			pow := 1
+			for j := 0; j < 9-i; j++ {
+				pow = pow * 2 % 9
+			}
+			tmp = tmp * pow % 9
+			if tmp == 0 {
+				tmp = 9
+			}
+		}
+		sum += tmp
+	}
+
+	last := taxNumber[9]
+	if last < '0' || last > '9' {
+		return false
+	}
+	return (10-sum%10)%10 == int(last-'0')
+}
+
+func TCKNValidator(fl validator.FieldLevel) bool {
+	return IsValidTCKN(fl.Field().String())
+}
+
+func TaxNumberValidator(fl validator.FieldLevel) bool {
+	return IsValidTaxNumber(fl.Field().String())
+}
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Trip statuses. A trip is created, gets a driver assigned, who drives to
+// the pickup (en route), starts the ride and completes it. It may be
+// cancelled at any point before it completes.
+const (
+	TripCreated        = "created"
+	TripDriverAssigned = "driver_assigned"
+	TripEnRoute        = "en_route"
+	TripStarted        = "started"
+	TripCompleted      = "completed"
+	TripCancelled      = "cancelled"
+)
+
+// Bounds of a trip list.
+const (
+	DefaultTripListLimit = 50
+	MaxTripListLimit     = 200
+)
+
+// tripTransitions lists the statuses each status may move to. Assignment is
+// its own operation, as it needs a driver, so created only leads on to
+// cancelled here.
+var tripTransitions = map[string][]string{
+	TripCreated:        {TripCancelled},
+	TripDriverAssigned: {TripEnRoute, TripCancelled},
+	TripEnRoute:        {TripStarted, TripCancelled},
+	TripStarted:        {TripCompleted, TripCancelled},
+}
+
+// CanTransitionTrip reports whether a trip in status from may move to
+// status to.
+func CanTransitionTrip(from, to string) bool {
+	for _, next := range tripTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidTripStatus(status string) bool {
+	switch status {
+	case TripCreated, TripDriverAssigned, TripEnRoute, TripStarted, TripCompleted, TripCancelled:
+		return true
+	}
+	return false
+}
+
+// IsTripActive reports whether a trip in the status occupies its driver.
+func IsTripActive(status string) bool {
+	switch status {
+	case TripDriverAssigned, TripEnRoute, TripStarted:
+		return true
+	}
+	return false
+}
+
+// Trip is one ride request and its progress. The driver is busy from
+// assignment until the trip completes or is cancelled.
+type Trip struct {
+	ID       primitive.ObjectID `json:"id" bson:"_id"`
+	FleetID  string             `json:"fleet_id,omitempty" bson:"fleet_id,omitempty"`
+	TaxiType string             `json:"taxi_type,omitempty" bson:"taxi_type,omitempty"`
+	Pickup   Location           `json:"pickup" bson:"pickup"`
+	Dropoff  *Location          `json:"dropoff,omitempty" bson:"dropoff,omitempty"`
+	Notes    string             `json:"notes,omitempty" bson:"notes,omitempty"`
+	Status   string             `json:"status" bson:"status"`
+	DriverID string             `json:"driver_id,omitempty" bson:"driver_id,omitempty"`
+	// RiderID is the booking system's rider, whose saved preferences the
+	// trip starts with. Preferences are shown to the driver with the offer;
+	// PreferencesAck is set once the assigned driver has acknowledged them.
+	RiderID        string           `json:"rider_id,omitempty" bson:"rider_id,omitempty"`
+	Preferences    *RidePreferences `json:"preferences,omitempty" bson:"preferences,omitempty"`
+	PreferencesAck *PreferencesAck  `json:"preferences_ack,omitempty" bson:"preferences_ack,omitempty"`
+	// Active is set while the trip occupies its driver; a partial unique
+	// index on it keeps a driver from being assigned two trips at once.
+	Active       bool   `json:"-" bson:"active"`
+	CancelReason string `json:"cancel_reason,omitempty" bson:"cancel_reason,omitempty"`
+	// Fare is what the rider paid, given when the trip completes. It is
+	// booked to the driver's earnings, less the commission.
+	Fare *float64 `json:"fare,omitempty" bson:"fare,omitempty"`
+	// Offer is set while the assigned driver was matched automatically and
+	// has yet to accept. DeclinedBy lists the drivers who declined the trip
+	// or let their offer expire, so matching skips them.
+	Offer      *TripOffer `json:"offer,omitempty" bson:"offer,omitempty"`
+	DeclinedBy []string   `json:"declined_by,omitempty" bson:"declined_by,omitempty"`
+	// Revision counts the stored changes, so concurrent updates of the
+	// same trip cannot overwrite each other.
+	Revision int `json:"-" bson:"revision"`
+
+	CreatedBy   string     `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" bson:"updated_at"`
+	AssignedAt  *time.Time `json:"assigned_at,omitempty" bson:"assigned_at,omitempty"`
+	EnRouteAt   *time.Time `json:"en_route_at,omitempty" bson:"en_route_at,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty" bson:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty" bson:"cancelled_at,omitempty"`
+}
+
+// TripOffer is an automatic match waiting for the driver's answer. If the
+// driver declines or does not answer by ExpiresAt, the trip is offered to
+// the next candidate.
+type TripOffer struct {
+	DriverID   string    `json:"driver_id" bson:"driver_id"`
+	DistanceKm float64   `json:"distance_km" bson:"distance_km"`
+	Strategy   string    `json:"strategy" bson:"strategy"`
+	OfferedAt  time.Time `json:"offered_at" bson:"offered_at"`
+	ExpiresAt  time.Time `json:"expires_at" bson:"expires_at"`
+}
+
+// HasPendingOffer reports whether the trip waits for its driver to accept
+// an offer.
+func (t *Trip) HasPendingOffer() bool {
+	return t.Status == TripDriverAssigned && t.Offer != nil
+}
+
+// MarkStatus moves the trip to status at the given time and records when
+// it did. Leaving driver_assigned settles a pending offer.
+func (t *Trip) MarkStatus(status string, at time.Time) {
+	if status != TripDriverAssigned {
+		t.Offer = nil
+	}
+	t.Status = status
+	t.Active = IsTripActive(status)
+	t.UpdatedAt = at
+	switch status {
+	case TripDriverAssigned:
+		t.AssignedAt = &at
+	case TripEnRoute:
+		t.EnRouteAt = &at
+	case TripStarted:
+		t.StartedAt = &at
+	case TripCompleted:
+		t.CompletedAt = &at
+	case TripCancelled:
+		t.CancelledAt = &at
+	}
+}
+
+type CreateTripRequest struct {
+	PickupLat  float64  `json:"pickup_lat" validate:"required,min=-90,max=90"`
+	PickupLon  float64  `json:"pickup_lon" validate:"required,min=-180,max=180"`
+	DropoffLat *float64 `json:"dropoff_lat" validate:"required_with=DropoffLon,omitempty,min=-90,max=90"`
+	DropoffLon *float64 `json:"dropoff_lon" validate:"required_with=DropoffLat,omitempty,min=-180,max=180"`
+	TaxiType   string   `json:"taxi_type" validate:"omitempty,oneof=sari turkuaz siyah"`
+	FleetID    string   `json:"fleet_id" validate:"max=64"`
+	Notes      string   `json:"notes" validate:"max=500"`
+	// The trip starts with the saved preferences of RiderID, unless it
+	// brings Preferences of its own
+	RiderID     string           `json:"rider_id" validate:"max=64"`
+	Preferences *RidePreferences `json:"preferences"`
+}
+
+func (r *CreateTripRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+// AssignTripRequest assigns a driver to a created trip.
+type AssignTripRequest struct {
+	DriverID string `json:"driver_id" validate:"required,len=24,hexadecimal"`
+}
+
+func (r *AssignTripRequest) Validate() error {
+	return newValidator().Struct(r)
+}
+
+// UpdateTripStatusRequest moves a trip along its lifecycle. Reason is kept
+// for cancellations, and Fare, what the rider paid, for completions.
+type UpdateTripStatusRequest struct {
+	Status string   `json:"status" validate:"required,oneof=en_route started completed cancelled"`
+	Reason string   `json:"reason" validate:"excluded_unless=Status cancelled,max=200"`
+	Fare   *float64 `json:"fare" validate:"omitempty,min=0,max=100000"`
+}
+
+func (r *UpdateTripStatusRequest) Validate() error {
+	if err := newValidator().Struct(r); err != nil {
+		return err
+	}
+	if r.Fare != nil && r.Status != TripCompleted {
+		return errors.New("fare is only given when the trip completes")
+	}
+	return nil
+}
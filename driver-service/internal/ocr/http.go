@@ -0,0 +1,87 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+// HTTPProvider posts the raw image to an OCR service that answers with
+// {"fields": {"full_name": {"value": "...", "confidence": 0.97}, ...}} for
+// full_name, license_number and expiry_date.
+type HTTPProvider struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func NewHTTPProvider(url, apiKey string) *HTTPProvider {
+	return &HTTPProvider{
+		url:    url,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 20 * time.Second},
+	}
+}
+
+type httpField struct {
+	Value      string  `json:"value"`
+	Confidence float64 `json:"confidence"`
+}
+
+type httpResponse struct {
+	Fields struct {
+		FullName      httpField `json:"full_name"`
+		LicenseNumber httpField `json:"license_number"`
+		ExpiryDate    httpField `json:"expiry_date"`
+	} `json:"fields"`
+}
+
+func (p *HTTPProvider) ExtractLicense(ctx context.Context, image []byte, contentType string) (*models.LicenseExtraction, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(image))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build ocr request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: ocr returned status %d", ErrProviderUnavailable, resp.StatusCode)
+	}
+
+	var body httpResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode ocr response: %w", err)
+	}
+
+	fields := body.Fields
+	if fields.FullName.Value == "" && fields.LicenseNumber.Value == "" && fields.ExpiryDate.Value == "" {
+		return nil, ErrNoText
+	}
+
+	return &models.LicenseExtraction{
+		FullName:      extracted(fields.FullName),
+		LicenseNumber: extracted(fields.LicenseNumber),
+		ExpiresAt:     extracted(fields.ExpiryDate),
+	}, nil
+}
+
+func extracted(field httpField) models.ExtractedField {
+	return models.ExtractedField{
+		Value:      field.Value,
+		Confidence: field.Confidence,
+		Source:     models.FieldSourceOCR,
+	}
+}
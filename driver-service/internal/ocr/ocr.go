@@ -0,0 +1,32 @@
+package ocr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+var (
+	ErrNoText              = errors.New("no readable text in image")
+	ErrProviderUnavailable = errors.New("ocr provider unavailable")
+)
+
+// Provider reads driver license fields from an uploaded image.
+type Provider interface {
+	ExtractLicense(ctx context.Context, image []byte, contentType string) (*models.LicenseExtraction, error)
+}
+
+func NewProvider(name, baseURL, apiKey string) (Provider, error) {
+	switch name {
+	case "", "none":
+		return nil, nil
+	case "http":
+		if baseURL == "" {
+			return nil, errors.New("http ocr provider requires OCR_URL")
+		}
+		return NewHTTPProvider(baseURL, apiKey), nil
+	default:
+		return nil, errors.New("unknown ocr provider: " + name)
+	}
+}
@@ -0,0 +1,354 @@
+package reindex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Transform rewrites the geo representation of a single document.
+type Transform interface {
+	Name() string
+	// Apply returns the $set document for raw, or nil when the document is
+	// already up to date.
+	Apply(raw bson.Raw) (bson.M, error)
+}
+
+type Options struct {
+	Collection       string
+	Workers          int
+	BatchSize        int
+	ProgressInterval time.Duration
+	DryRun           bool
+	// Reset discards the stored checkpoint and starts from the beginning.
+	Reset bool
+}
+
+// Checkpoint is persisted after every contiguous run of completed batches so
+// an interrupted reindex resumes after the last document known to be done.
+type Checkpoint struct {
+	ID          string             `bson:"_id"`
+	Collection  string             `bson:"collection"`
+	LastID      primitive.ObjectID `bson:"last_id"`
+	Scanned     int64              `bson:"scanned"`
+	Updated     int64              `bson:"updated"`
+	Failed      int64              `bson:"failed"`
+	StartedAt   time.Time          `bson:"started_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty"`
+}
+
+type batch struct {
+	seq  int
+	docs []bson.Raw
+	last primitive.ObjectID
+}
+
+type batchResult struct {
+	seq     int
+	last    primitive.ObjectID
+	scanned int64
+	updated int64
+	failed  int64
+	err     error
+}
+
+type Runner struct {
+	db          *mongo.Database
+	checkpoints *mongo.Collection
+	transform   Transform
+	opts        Options
+}
+
+func NewRunner(db *config.MongoDB, transform Transform, opts Options) *Runner {
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 1000
+	}
+	if opts.ProgressInterval <= 0 {
+		opts.ProgressInterval = 10 * time.Second
+	}
+
+	return &Runner{
+		db:          db.Database,
+		checkpoints: db.GetCollection("reindex_checkpoints"),
+		transform:   transform,
+		opts:        opts,
+	}
+}
+
+func (r *Runner) checkpointID() string {
+	return r.opts.Collection + ":" + r.transform.Name()
+}
+
+// Run scans the collection in _id order, hands batches to parallel workers
+// and bulk-writes the transformed fields. Documents are visited once per run;
+// documents inserted behind the scan position are expected to be written in
+// the new format by the application already.
+func (r *Runner) Run(ctx context.Context) (*Checkpoint, error) {
+	checkpoint, err := r.loadCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint.CompletedAt != nil {
+		slog.Info("Reindex already completed; use -reset to run again", "reindex", checkpoint.ID, "completed_at", checkpoint.CompletedAt.Format(time.RFC3339))
+		return checkpoint, nil
+	}
+
+	collection := r.db.Collection(r.opts.Collection)
+	total, err := collection.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents: %w", err)
+	}
+	if checkpoint.LastID.IsZero() {
+		slog.Info("Reindex starting", "reindex", checkpoint.ID, "estimated_documents", total, "workers", r.opts.Workers, "batch_size", r.opts.BatchSize)
+	} else {
+		slog.Info("Reindex resuming", "reindex", checkpoint.ID, "after", checkpoint.LastID.Hex(), "scanned", checkpoint.Scanned)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	batches := make(chan batch, r.opts.Workers)
+	results := make(chan batchResult, r.opts.Workers)
+
+	var workers sync.WaitGroup
+	for i := 0; i < r.opts.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for b := range batches {
+				results <- r.processBatch(ctx, collection, b)
+			}
+		}()
+	}
+
+	scanErr := make(chan error, 1)
+	go func() {
+		scanErr <- r.scan(ctx, collection, checkpoint.LastID, batches)
+		close(batches)
+		workers.Wait()
+		close(results)
+	}()
+
+	// Batches finish out of order; the checkpoint only advances over the
+	// contiguous prefix of finished batches.
+	pending := make(map[int]batchResult)
+	next := 0
+	var runErr error
+	ticker := time.NewTicker(r.opts.ProgressInterval)
+	defer ticker.Stop()
+	startedAt := time.Now()
+	startScanned := checkpoint.Scanned
+
+	for results != nil {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				results = nil
+				continue
+			}
+			if result.err != nil && runErr == nil {
+				runErr = result.err
+				cancel()
+			}
+			pending[result.seq] = result
+
+			advanced := false
+			for {
+				done, ok := pending[next]
+				if !ok || done.err != nil {
+					break
+				}
+				delete(pending, next)
+				next++
+				checkpoint.LastID = done.last
+				checkpoint.Scanned += done.scanned
+				checkpoint.Updated += done.updated
+				checkpoint.Failed += done.failed
+				advanced = true
+			}
+			if advanced {
+				if err := r.saveCheckpoint(context.WithoutCancel(ctx), checkpoint); err != nil && runErr == nil {
+					runErr = err
+					cancel()
+				}
+			}
+		case <-ticker.C:
+			logProgress(checkpoint, total, startScanned, startedAt)
+		}
+	}
+
+	if err := <-scanErr; err != nil && runErr == nil && !errors.Is(err, context.Canceled) {
+		runErr = err
+	}
+	if runErr == nil && ctx.Err() != nil {
+		runErr = ctx.Err()
+	}
+
+	logProgress(checkpoint, total, startScanned, startedAt)
+	if runErr != nil {
+		return checkpoint, fmt.Errorf("reindex stopped after %s: %w", checkpoint.LastID.Hex(), runErr)
+	}
+
+	completedAt := time.Now()
+	checkpoint.CompletedAt = &completedAt
+	if err := r.saveCheckpoint(context.WithoutCancel(ctx), checkpoint); err != nil {
+		return checkpoint, err
+	}
+	slog.Info("Reindex completed", "reindex", checkpoint.ID, "scanned", checkpoint.Scanned, "updated", checkpoint.Updated, "failed", checkpoint.Failed)
+
+	return checkpoint, nil
+}
+
+func (r *Runner) scan(ctx context.Context, collection *mongo.Collection, after primitive.ObjectID, batches chan<- batch) error {
+	filter := bson.M{}
+	if !after.IsZero() {
+		filter["_id"] = bson.M{"$gt": after}
+	}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(bson.M{"_id": 1}).
+		SetBatchSize(int32(r.opts.BatchSize)))
+	if err != nil {
+		return fmt.Errorf("failed to open cursor: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	seq := 0
+	current := batch{seq: seq}
+	for cursor.Next(ctx) {
+		doc := make(bson.Raw, len(cursor.Current))
+		copy(doc, cursor.Current)
+		current.docs = append(current.docs, doc)
+
+		if len(current.docs) == r.opts.BatchSize {
+			if err := send(ctx, batches, current); err != nil {
+				return err
+			}
+			seq++
+			current = batch{seq: seq}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("cursor failed: %w", err)
+	}
+
+	if len(current.docs) > 0 {
+		return send(ctx, batches, current)
+	}
+	return nil
+}
+
+func send(ctx context.Context, batches chan<- batch, b batch) error {
+	b.last, _ = b.docs[len(b.docs)-1].Lookup("_id").ObjectIDOK()
+	select {
+	case batches <- b:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Runner) processBatch(ctx context.Context, collection *mongo.Collection, b batch) batchResult {
+	result := batchResult{seq: b.seq, last: b.last, scanned: int64(len(b.docs))}
+
+	var writes []mongo.WriteModel
+	for _, doc := range b.docs {
+		set, err := r.transform.Apply(doc)
+		if err != nil {
+			// Bad documents are counted and skipped rather than blocking the run.
+			slog.Warn("Reindex skipping document", "id", doc.Lookup("_id").String(), "error", err)
+			result.failed++
+			continue
+		}
+		if set == nil {
+			continue
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": doc.Lookup("_id")}).
+			SetUpdate(bson.M{"$set": set}))
+	}
+
+	if len(writes) == 0 || r.opts.DryRun {
+		result.updated = int64(len(writes))
+		return result
+	}
+
+	res, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		result.err = fmt.Errorf("bulk write failed: %w", err)
+		return result
+	}
+	result.updated = res.ModifiedCount
+
+	return result
+}
+
+func (r *Runner) loadCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	id := r.checkpointID()
+	fresh := &Checkpoint{ID: id, Collection: r.opts.Collection, StartedAt: time.Now()}
+	if r.opts.Reset || r.opts.DryRun {
+		return fresh, nil
+	}
+
+	var checkpoint Checkpoint
+	if err := r.checkpoints.FindOne(ctx, bson.M{"_id": id}).Decode(&checkpoint); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fresh, nil
+		}
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+func (r *Runner) saveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	if r.opts.DryRun {
+		return nil
+	}
+
+	checkpoint.UpdatedAt = time.Now()
+	_, err := r.checkpoints.ReplaceOne(ctx, bson.M{"_id": checkpoint.ID}, checkpoint, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+func logProgress(checkpoint *Checkpoint, total, startScanned int64, startedAt time.Time) {
+	elapsed := time.Since(startedAt)
+	rate := float64(checkpoint.Scanned-startScanned) / elapsed.Seconds()
+
+	eta := "unknown"
+	if remaining := total - checkpoint.Scanned; rate > 0 && remaining > 0 {
+		eta = (time.Duration(float64(remaining)/rate) * time.Second).Round(time.Second).String()
+	}
+
+	percent := 100.0
+	if total > 0 {
+		percent = float64(checkpoint.Scanned) / float64(total) * 100
+	}
+
+	slog.Info("Reindex progress",
+		"reindex", checkpoint.ID,
+		"scanned", checkpoint.Scanned,
+		"total", total,
+		"percent", math.Round(percent*10)/10,
+		"updated", checkpoint.Updated,
+		"failed", checkpoint.Failed,
+		"docs_per_second", math.Round(rate),
+		"eta", eta,
+	)
+}
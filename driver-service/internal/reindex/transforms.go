@@ -0,0 +1,109 @@
+package reindex
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taxihub/driver-service/internal/geohash"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Transforms lists the available transforms by name.
+var Transforms = map[string]func(precision int) Transform{
+	"geohash": func(precision int) Transform { return GeohashTransform{Precision: precision} },
+	"geojson": func(int) Transform { return GeoJSONTransform{} },
+}
+
+// GeohashTransform (re)computes the geohash bucket of each document's
+// location, e.g. after the stored precision changes.
+type GeohashTransform struct {
+	Precision int
+}
+
+func (t GeohashTransform) Name() string {
+	return fmt.Sprintf("geohash-%d", t.Precision)
+}
+
+func (t GeohashTransform) Apply(raw bson.Raw) (bson.M, error) {
+	lat, lon, err := readLocation(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := geohash.Encode(lat, lon, t.Precision)
+	if current, ok := raw.Lookup("geohash").StringValueOK(); ok && current == hash {
+		return nil, nil
+	}
+	return bson.M{"geohash": hash}, nil
+}
+
+// GeoJSONTransform rewrites legacy {lat, lon} locations as GeoJSON Points.
+// Documents already stored as GeoJSON are left alone.
+type GeoJSONTransform struct{}
+
+func (GeoJSONTransform) Name() string {
+	return "geojson"
+}
+
+func (GeoJSONTransform) Apply(raw bson.Raw) (bson.M, error) {
+	if location, ok := raw.Lookup("location").DocumentOK(); ok {
+		if kind, _ := location.Lookup("type").StringValueOK(); kind == models.GeoJSONPointType {
+			return nil, nil
+		}
+	}
+
+	lat, lon, err := readLocation(raw)
+	if err != nil {
+		return nil, err
+	}
+	return bson.M{"location": models.Location{Lat: lat, Lon: lon}}, nil
+}
+
+// readLocation reads a document's location, either a GeoJSON Point or the
+// legacy {lat, lon} document.
+func readLocation(raw bson.Raw) (float64, float64, error) {
+	location, ok := raw.Lookup("location").DocumentOK()
+	if !ok {
+		return 0, 0, errors.New("document has no location")
+	}
+
+	var lat, lon float64
+	if coordinates, ok := location.Lookup("coordinates").ArrayOK(); ok {
+		values, err := coordinates.Values()
+		if err != nil || len(values) != 2 {
+			return 0, 0, errors.New("location coordinates must be [lon, lat]")
+		}
+		var lonOK, latOK bool
+		lon, lonOK = numeric(values[0])
+		lat, latOK = numeric(values[1])
+		if !lonOK || !latOK {
+			return 0, 0, errors.New("location coordinates must be numbers")
+		}
+	} else {
+		var latOK, lonOK bool
+		lat, latOK = numeric(location.Lookup("lat"))
+		lon, lonOK = numeric(location.Lookup("lon"))
+		if !latOK || !lonOK {
+			return 0, 0, errors.New("location is missing lat or lon")
+		}
+	}
+
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return 0, 0, fmt.Errorf("location out of range: %f,%f", lat, lon)
+	}
+	return lat, lon, nil
+}
+
+func numeric(value bson.RawValue) (float64, bool) {
+	if f, ok := value.DoubleOK(); ok {
+		return f, true
+	}
+	if i, ok := value.Int32OK(); ok {
+		return float64(i), true
+	}
+	if i, ok := value.Int64OK(); ok {
+		return float64(i), true
+	}
+	return 0, false
+}
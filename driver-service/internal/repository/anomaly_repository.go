@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AnomalyRepository interface {
+	Create(ctx context.Context, anomaly *models.DriverAnomaly) error
+	FindByID(ctx context.Context, id string) (*models.DriverAnomaly, error)
+	Find(ctx context.Context, status, severity string, limit int) ([]models.DriverAnomaly, error)
+	// Review closes an open anomaly. It fails with ErrAnomalyConflict if the
+	// anomaly is no longer open.
+	Review(ctx context.Context, anomaly *models.DriverAnomaly) error
+}
+
+type MongoAnomalyRepository struct {
+	collection config.ScopedCollection
+	archive    config.ScopedCollection
+}
+
+func NewMongoAnomalyRepository(db *config.MongoDB) *MongoAnomalyRepository {
+	return &MongoAnomalyRepository{
+		collection: db.ScopedCollection("driver_anomalies"),
+		archive:    db.ScopedCollection("driver_anomalies_archive"),
+	}
+}
+
+func (r *MongoAnomalyRepository) Create(ctx context.Context, anomaly *models.DriverAnomaly) error {
+	if anomaly == nil {
+		return errors.New("anomaly cannot be nil")
+	}
+
+	if anomaly.ID.IsZero() {
+		anomaly.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, anomaly); err != nil {
+		return fmt.Errorf("failed to create anomaly: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoAnomalyRepository) FindByID(ctx context.Context, id string) (*models.DriverAnomaly, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid anomaly ID format: %w", err)
+	}
+
+	var anomaly models.DriverAnomaly
+	if err := r.collection.For(ctx).FindOne(ctx, bson.M{"_id": objectID}).Decode(&anomaly); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrAnomalyNotFound
+		}
+		return nil, fmt.Errorf("failed to find anomaly: %w", err)
+	}
+
+	return &anomaly, nil
+}
+
+func (r *MongoAnomalyRepository) Find(ctx context.Context, status, severity string, limit int) ([]models.DriverAnomaly, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	if severity != "" {
+		filter["severity"] = severity
+	}
+
+	findOptions := options.Find().SetSort(bson.M{"detected_at": -1})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.collection.For(ctx).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find anomalies: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	anomalies := []models.DriverAnomaly{}
+	if err := cursor.All(ctx, &anomalies); err != nil {
+		return nil, fmt.Errorf("failed to decode anomalies: %w", err)
+	}
+
+	return anomalies, nil
+}
+
+func (r *MongoAnomalyRepository) Review(ctx context.Context, anomaly *models.DriverAnomaly) error {
+	result, err := r.collection.For(ctx).UpdateOne(ctx,
+		bson.M{"_id": anomaly.ID, "status": models.AnomalyOpen},
+		bson.M{"$set": bson.M{
+			"status":      anomaly.Status,
+			"review_note": anomaly.ReviewNote,
+			"reviewed_by": anomaly.ReviewedBy,
+			"reviewed_at": anomaly.ReviewedAt,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update anomaly: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrAnomalyConflict
+	}
+
+	return nil
+}
+
+func (r *MongoAnomalyRepository) CollectionName() string {
+	return "driver_anomalies"
+}
+
+func (r *MongoAnomalyRepository) ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error) {
+	return archiveMany(ctx, r.collection.For(ctx), r.archive.For(ctx), bson.M{"driver_id": driverID}, archivedAt)
+}
+
+func (r *MongoAnomalyRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "driver_anomalies",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "detected_at", Value: -1}},
+				Options: options.Index().SetName("driver_anomalies_status_detected_at"),
+			},
+		},
+	}
+}
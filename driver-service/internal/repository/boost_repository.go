@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BoostRepository interface {
+	// FindUsage returns the boosted assignment count per driver; drivers
+	// without any are left out.
+	FindUsage(ctx context.Context, driverIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error)
+	FindByDriver(ctx context.Context, driverID primitive.ObjectID) (*models.DriverBoostUsage, error)
+	RecordUse(ctx context.Context, driverID primitive.ObjectID, at time.Time) error
+}
+
+type MongoBoostRepository struct {
+	collection config.ScopedCollection
+	archive    config.ScopedCollection
+}
+
+func NewMongoBoostRepository(db *config.MongoDB) *MongoBoostRepository {
+	return &MongoBoostRepository{
+		collection: db.ScopedCollection("driver_boosts"),
+		archive:    db.ScopedCollection("driver_boosts_archive"),
+	}
+}
+
+func (r *MongoBoostRepository) FindUsage(ctx context.Context, driverIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+	usage := make(map[primitive.ObjectID]int)
+	if len(driverIDs) == 0 {
+		return usage, nil
+	}
+
+	cursor, err := r.collection.For(ctx).Find(ctx, bson.M{"_id": bson.M{"$in": driverIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find boost usage: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []models.DriverBoostUsage
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode boost usage: %w", err)
+	}
+	for _, record := range records {
+		usage[record.DriverID] = record.Used
+	}
+
+	return usage, nil
+}
+
+func (r *MongoBoostRepository) FindByDriver(ctx context.Context, driverID primitive.ObjectID) (*models.DriverBoostUsage, error) {
+	usage := models.DriverBoostUsage{DriverID: driverID}
+	err := r.collection.For(ctx).FindOne(ctx, bson.M{"_id": driverID}).Decode(&usage)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to find boost usage: %w", err)
+	}
+
+	return &usage, nil
+}
+
+func (r *MongoBoostRepository) RecordUse(ctx context.Context, driverID primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.For(ctx).UpdateOne(ctx,
+		bson.M{"_id": driverID},
+		bson.M{
+			"$inc": bson.M{"used": 1},
+			"$set": bson.M{"last_used_at": at},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record boost use: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoBoostRepository) CollectionName() string {
+	return "driver_boosts"
+}
+
+func (r *MongoBoostRepository) ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error) {
+	return archiveMany(ctx, r.collection.For(ctx), r.archive.For(ctx), bson.M{"_id": driverID}, archivedAt)
+}
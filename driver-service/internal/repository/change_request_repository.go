@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ChangeRequestRepository interface {
+	Create(ctx context.Context, request *models.DriverChangeRequest) error
+	FindByID(ctx context.Context, id string) (*models.DriverChangeRequest, error)
+	FindByDriver(ctx context.Context, driverID string) ([]models.DriverChangeRequest, error)
+	FindByStatus(ctx context.Context, status string, limit int) ([]models.DriverChangeRequest, error)
+	HasPending(ctx context.Context, driverID primitive.ObjectID) (bool, error)
+	// Transition moves a request out of fromStatus. It fails with
+	// ErrChangeRequestConflict if another reviewer got there first.
+	Transition(ctx context.Context, request *models.DriverChangeRequest, fromStatus string) error
+}
+
+type MongoChangeRequestRepository struct {
+	collection config.ScopedCollection
+	archive    config.ScopedCollection
+}
+
+func NewMongoChangeRequestRepository(db *config.MongoDB) *MongoChangeRequestRepository {
+	return &MongoChangeRequestRepository{
+		collection: db.ScopedCollection("driver_change_requests"),
+		archive:    db.ScopedCollection("driver_change_requests_archive"),
+	}
+}
+
+func (r *MongoChangeRequestRepository) Create(ctx context.Context, request *models.DriverChangeRequest) error {
+	if request == nil {
+		return errors.New("change request cannot be nil")
+	}
+
+	if request.ID.IsZero() {
+		request.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, request); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrChangeRequestConflict
+		}
+		return fmt.Errorf("failed to create change request: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoChangeRequestRepository) FindByID(ctx context.Context, id string) (*models.DriverChangeRequest, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid change request ID format: %w", err)
+	}
+
+	var request models.DriverChangeRequest
+	if err := r.collection.For(ctx).FindOne(ctx, bson.M{"_id": objectID}).Decode(&request); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrChangeRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to find change request: %w", err)
+	}
+
+	return &request, nil
+}
+
+func (r *MongoChangeRequestRepository) FindByDriver(ctx context.Context, driverID string) ([]models.DriverChangeRequest, error) {
+	objectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver ID format: %w", err)
+	}
+
+	return r.find(ctx, bson.M{"driver_id": objectID}, 0)
+}
+
+func (r *MongoChangeRequestRepository) FindByStatus(ctx context.Context, status string, limit int) ([]models.DriverChangeRequest, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	return r.find(ctx, filter, limit)
+}
+
+func (r *MongoChangeRequestRepository) find(ctx context.Context, filter bson.M, limit int) ([]models.DriverChangeRequest, error) {
+	findOptions := options.Find().SetSort(bson.M{"created_at": -1})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.collection.For(ctx).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find change requests: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	requests := []models.DriverChangeRequest{}
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, fmt.Errorf("failed to decode change requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+func (r *MongoChangeRequestRepository) HasPending(ctx context.Context, driverID primitive.ObjectID) (bool, error) {
+	count, err := r.collection.For(ctx).CountDocuments(ctx, bson.M{
+		"driver_id": driverID,
+		"status":    models.ChangeRequestPending,
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to check pending change requests: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *MongoChangeRequestRepository) Transition(ctx context.Context, request *models.DriverChangeRequest, fromStatus string) error {
+	result, err := r.collection.For(ctx).UpdateOne(ctx,
+		bson.M{"_id": request.ID, "status": fromStatus},
+		bson.M{"$set": bson.M{
+			"status":      request.Status,
+			"review_note": request.ReviewNote,
+			"reviewed_by": request.ReviewedBy,
+			"reviewed_at": request.ReviewedAt,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update change request: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrChangeRequestConflict
+	}
+
+	return nil
+}
+
+func (r *MongoChangeRequestRepository) CollectionName() string {
+	return "driver_change_requests"
+}
+
+func (r *MongoChangeRequestRepository) ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error) {
+	return archiveMany(ctx, r.collection.For(ctx), r.archive.For(ctx), bson.M{"driver_id": driverID}, archivedAt)
+}
+
+// RequiredIndexes includes a partial unique index that allows at most one
+// pending request per driver.
+func (r *MongoChangeRequestRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "driver_change_requests",
+			Model: mongo.IndexModel{
+				Keys: bson.D{{Key: "driver_id", Value: 1}},
+				Options: options.Index().
+					SetName("driver_change_requests_one_pending").
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"status": models.ChangeRequestPending}),
+			},
+		},
+		{
+			Collection: "driver_change_requests",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("driver_change_requests_status_created_at"),
+			},
+		},
+	}
+}
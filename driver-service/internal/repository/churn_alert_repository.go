@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ChurnAlertRepository interface {
+	// Create stores the alert. It fails with ErrChurnAlertExists if the
+	// fleet and zone were alerted for the same week already.
+	Create(ctx context.Context, alert *models.ChurnAlert) error
+	// List returns the newest alerts first, for one fleet or, with "", all
+	// of them, up to limit.
+	List(ctx context.Context, fleetID string, limit int) ([]models.ChurnAlert, error)
+}
+
+type MongoChurnAlertRepository struct {
+	collection config.ScopedCollection
+}
+
+func NewMongoChurnAlertRepository(db *config.MongoDB) *MongoChurnAlertRepository {
+	return &MongoChurnAlertRepository{
+		collection: db.ScopedCollection("churn_alerts"),
+	}
+}
+
+func (r *MongoChurnAlertRepository) Create(ctx context.Context, alert *models.ChurnAlert) error {
+	if alert == nil {
+		return errors.New("churn alert cannot be nil")
+	}
+
+	if alert.ID.IsZero() {
+		alert.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, alert); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrChurnAlertExists
+		}
+		return fmt.Errorf("failed to create churn alert: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoChurnAlertRepository) List(ctx context.Context, fleetID string, limit int) ([]models.ChurnAlert, error) {
+	filter := bson.M{}
+	if fleetID != "" {
+		filter["fleet_id"] = fleetID
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.collection.For(ctx).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list churn alerts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	alerts := []models.ChurnAlert{}
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to decode churn alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+func (r *MongoChurnAlertRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			// One alert per fleet, zone and week, however often the check runs
+			Collection: "churn_alerts",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "fleet_id", Value: 1}, {Key: "zone", Value: 1}, {Key: "week_of", Value: 1}},
+				Options: options.Index().SetName("churn_alerts_fleet_id_zone_week_of_unique").SetUnique(true),
+			},
+		},
+		{
+			Collection: "churn_alerts",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "fleet_id", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("churn_alerts_fleet_id_created_at"),
+			},
+		},
+	}
+}
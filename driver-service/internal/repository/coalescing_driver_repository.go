@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/geohash"
+	"github.com/taxihub/driver-service/internal/models"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	coalesceQueryTimeout  = 10 * time.Second
+	coalesceMaxCacheCells = 10000
+	// coalesceLimitFactor sizes the shared query at this many times the
+	// largest limit a caller may ask for.
+	coalesceLimitFactor = 4
+)
+
+type coalescedNearby struct {
+	drivers   []models.DriverWithDistance
+	expiresAt time.Time
+}
+
+// CoalescingDriverRepository collapses concurrent nearby searches that fall
+// into the same geohash cell into a single query. The shared query runs from
+// the cell center with the radius widened by the cell's half diagonal, and
+// each caller gets the result re-filtered and re-sorted for its own point.
+// Results are reused for a short window after the query completes.
+//
+// The shared query is ordered from the cell center, so its own limit could
+// cut off drivers closest to a caller elsewhere in the cell. It therefore
+// runs with a limit well above any caller's, and a caller whose cell fills
+// it runs its own query instead.
+type CoalescingDriverRepository struct {
+	DriverRepository
+
+	window      time.Duration
+	precision   int
+	sharedLimit int
+	group       singleflight.Group
+
+	mu    sync.Mutex
+	cache map[string]coalescedNearby
+}
+
+// NewCoalescingDriverRepository takes maxLimit, the largest limit a nearby
+// search may ask for, to size the shared queries.
+func NewCoalescingDriverRepository(repo DriverRepository, window time.Duration, precision, maxLimit int) *CoalescingDriverRepository {
+	if maxLimit < models.DefaultNearbyLimit {
+		maxLimit = models.DefaultNearbyLimit
+	}
+	return &CoalescingDriverRepository{
+		DriverRepository: repo,
+		window:           window,
+		precision:        precision,
+		sharedLimit:      maxLimit * coalesceLimitFactor,
+		cache:            make(map[string]coalescedNearby),
+	}
+}
+
+func (r *CoalescingDriverRepository) FindNearby(ctx context.Context, lat, lon, radiusKm float64, filter models.NearbyFilter) ([]models.DriverWithDistance, error) {
+	if radiusKm <= 0 || lat < -90 || lat > 90 || lon < -180 || lon > 180 || filter.ResultLimit() > r.sharedLimit {
+		return r.DriverRepository.FindNearby(ctx, lat, lon, radiusKm, filter)
+	}
+
+	// Callers share the query whatever their limit, which only refineNearby
+	// applies.
+	cell := geohash.Encode(lat, lon, r.precision)
+	key := fmt.Sprintf("%s|%s|%s|%t|%t|%g|%g", config.TenantFromContext(ctx), cell, filter.TaxiType, filter.VerifiedOnly, filter.IncludeUnavailable, radiusKm, filter.MinRating)
+
+	if drivers, ok := r.cached(key); ok {
+		return r.refine(ctx, drivers, lat, lon, radiusKm, filter)
+	}
+
+	result, err, _ := r.group.Do(key, func() (interface{}, error) {
+		box := geohash.Decode(cell)
+		centerLat, centerLon := box.Center()
+		center := models.Location{Lat: centerLat, Lon: centerLon}
+		halfDiagonal := center.DistanceKm(models.Location{Lat: box.MaxLat, Lon: box.MaxLon})
+
+		// The shared query must not be cancelled when the first caller goes away.
+		queryCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), coalesceQueryTimeout)
+		defer cancel()
+
+		shared := filter
+		shared.Limit = r.sharedLimit
+		drivers, err := r.DriverRepository.FindNearby(queryCtx, centerLat, centerLon, radiusKm+halfDiagonal, shared)
+		if err != nil {
+			return nil, err
+		}
+
+		r.store(key, drivers)
+		return drivers, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.refine(ctx, result.([]models.DriverWithDistance), lat, lon, radiusKm, filter)
+}
+
+// refine narrows the shared result down to the caller's search. A shared
+// result that reached its limit may be missing drivers closer to the caller
+// than to the cell center, so the caller then runs its own query.
+func (r *CoalescingDriverRepository) refine(ctx context.Context, shared []models.DriverWithDistance, lat, lon, radiusKm float64, filter models.NearbyFilter) ([]models.DriverWithDistance, error) {
+	if len(shared) >= r.sharedLimit {
+		return r.DriverRepository.FindNearby(ctx, lat, lon, radiusKm, filter)
+	}
+	return refineNearby(shared, lat, lon, radiusKm, filter.ResultLimit()), nil
+}
+
+func (r *CoalescingDriverRepository) cached(key string) ([]models.DriverWithDistance, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.drivers, true
+}
+
+func (r *CoalescingDriverRepository) store(key string, drivers []models.DriverWithDistance) {
+	if r.window <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if len(r.cache) >= coalesceMaxCacheCells {
+		for k, entry := range r.cache {
+			if now.After(entry.expiresAt) {
+				delete(r.cache, k)
+			}
+		}
+	}
+	if len(r.cache) < coalesceMaxCacheCells {
+		r.cache[key] = coalescedNearby{drivers: drivers, expiresAt: now.Add(r.window)}
+	}
+}
+
+// refineNearby recomputes distances from the caller's own point on a copy of
+// the shared result set and keeps the closest limit drivers.
+func refineNearby(shared []models.DriverWithDistance, lat, lon, radiusKm float64, limit int) []models.DriverWithDistance {
+	origin := models.Location{Lat: lat, Lon: lon}
+	drivers := make([]models.DriverWithDistance, 0, len(shared))
+	for _, driver := range shared {
+		distance := origin.DistanceKm(driver.Location)
+		if distance > radiusKm {
+			continue
+		}
+		driver.DistanceKm = distance
+		drivers = append(drivers, driver)
+	}
+
+	sort.Slice(drivers, func(i, j int) bool {
+		return drivers[i].DistanceKm < drivers[j].DistanceKm
+	})
+	if len(drivers) > limit {
+		drivers = drivers[:limit]
+	}
+	return drivers
+}
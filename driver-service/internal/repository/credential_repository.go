@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CredentialRepository interface {
+	// Save stores the driver's credential, replacing any earlier one.
+	Save(ctx context.Context, credential *models.DriverCredential) error
+	FindByDriver(ctx context.Context, driverID primitive.ObjectID) (*models.DriverCredential, error)
+}
+
+type MongoCredentialRepository struct {
+	collection config.ScopedCollection
+	archive    config.ScopedCollection
+}
+
+func NewMongoCredentialRepository(db *config.MongoDB) *MongoCredentialRepository {
+	return &MongoCredentialRepository{
+		collection: db.ScopedCollection("driver_credentials"),
+		archive:    db.ScopedCollection("driver_credentials_archive"),
+	}
+}
+
+func (r *MongoCredentialRepository) Save(ctx context.Context, credential *models.DriverCredential) error {
+	if credential == nil {
+		return errors.New("credential cannot be nil")
+	}
+
+	if credential.ID.IsZero() {
+		credential.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.For(ctx).ReplaceOne(ctx,
+		bson.M{"driver_id": credential.DriverID},
+		credential,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save credential: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoCredentialRepository) FindByDriver(ctx context.Context, driverID primitive.ObjectID) (*models.DriverCredential, error) {
+	var credential models.DriverCredential
+	if err := r.collection.For(ctx).FindOne(ctx, bson.M{"driver_id": driverID}).Decode(&credential); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCredentialNotFound
+		}
+		return nil, fmt.Errorf("failed to find credential: %w", err)
+	}
+
+	return &credential, nil
+}
+
+func (r *MongoCredentialRepository) CollectionName() string {
+	return "driver_credentials"
+}
+
+func (r *MongoCredentialRepository) ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error) {
+	return archiveMany(ctx, r.collection.For(ctx), r.archive.For(ctx), bson.M{"driver_id": driverID}, archivedAt)
+}
+
+func (r *MongoCredentialRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "driver_credentials",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "driver_id", Value: 1}},
+				Options: options.Index().SetName("driver_credentials_driver_unique").SetUnique(true),
+			},
+		},
+	}
+}
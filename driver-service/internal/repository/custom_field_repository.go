@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CustomFieldRepository interface {
+	Create(ctx context.Context, definition *models.CustomFieldDefinition) error
+	FindByFleet(ctx context.Context, fleetID string) ([]models.CustomFieldDefinition, error)
+	CountByFleet(ctx context.Context, fleetID string) (int64, error)
+	Delete(ctx context.Context, fleetID, key string) error
+}
+
+type MongoCustomFieldRepository struct {
+	collection config.ScopedCollection
+}
+
+func NewMongoCustomFieldRepository(db *config.MongoDB) *MongoCustomFieldRepository {
+	return &MongoCustomFieldRepository{
+		collection: db.ScopedCollection("custom_fields"),
+	}
+}
+
+func (r *MongoCustomFieldRepository) Create(ctx context.Context, definition *models.CustomFieldDefinition) error {
+	if definition == nil {
+		return errors.New("custom field cannot be nil")
+	}
+
+	if definition.ID.IsZero() {
+		definition.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, definition); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrCustomFieldExists
+		}
+		return fmt.Errorf("failed to create custom field: %w", err)
+	}
+
+	return nil
+}
+
+// FindByFleet returns the fleet's definitions in the order they were
+// created, which is also the column order of exports.
+func (r *MongoCustomFieldRepository) FindByFleet(ctx context.Context, fleetID string) ([]models.CustomFieldDefinition, error) {
+	cursor, err := r.collection.For(ctx).Find(ctx, bson.M{"fleet_id": fleetID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find custom fields: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	definitions := []models.CustomFieldDefinition{}
+	if err := cursor.All(ctx, &definitions); err != nil {
+		return nil, fmt.Errorf("failed to decode custom fields: %w", err)
+	}
+
+	return definitions, nil
+}
+
+func (r *MongoCustomFieldRepository) CountByFleet(ctx context.Context, fleetID string) (int64, error) {
+	count, err := r.collection.For(ctx).CountDocuments(ctx, bson.M{"fleet_id": fleetID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count custom fields: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *MongoCustomFieldRepository) Delete(ctx context.Context, fleetID, key string) error {
+	result, err := r.collection.For(ctx).DeleteOne(ctx, bson.M{"fleet_id": fleetID, "key": key})
+	if err != nil {
+		return fmt.Errorf("failed to delete custom field: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrCustomFieldNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoCustomFieldRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "custom_fields",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "fleet_id", Value: 1}, {Key: "key", Value: 1}},
+				Options: options.Index().SetName("custom_fields_fleet_id_key_unique").SetUnique(true),
+			},
+		},
+	}
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongoIllegalOperation is returned by standalone servers that cannot run
+// multi-document transactions.
+const mongoIllegalOperation = 20
+
+// CascadeDependent is implemented by repositories holding records that
+// reference a driver and must not be orphaned when it is deleted.
+type CascadeDependent interface {
+	CollectionName() string
+	ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error)
+}
+
+// UntransactedDependent is a CascadeDependent whose collection cannot be
+// written inside a transaction, such as a time-series collection.
+type UntransactedDependent interface {
+	CascadeDependent
+	Untransacted() bool
+}
+
+// DeletionCoordinator deletes a driver together with its dependent records.
+// The driver and every dependent record are moved into *_archive collections
+// inside one transaction, so a failure leaves nothing half-deleted.
+// Untransacted dependents are archived after the transaction commits; if that
+// fails, their records stay behind and a warning is logged.
+type DeletionCoordinator struct {
+	db             *config.MongoDB
+	drivers        config.ScopedCollection
+	driversArchive config.ScopedCollection
+	dependents     []CascadeDependent
+	untransacted   []CascadeDependent
+}
+
+func NewDeletionCoordinator(db *config.MongoDB, dependents ...CascadeDependent) *DeletionCoordinator {
+	c := &DeletionCoordinator{
+		db:             db,
+		drivers:        db.ScopedCollection("drivers"),
+		driversArchive: db.ScopedCollection("drivers_archive"),
+	}
+	for _, dependent := range dependents {
+		if untransacted, ok := dependent.(UntransactedDependent); ok && untransacted.Untransacted() {
+			c.untransacted = append(c.untransacted, dependent)
+		} else {
+			c.dependents = append(c.dependents, dependent)
+		}
+	}
+	return c
+}
+
+func (c *DeletionCoordinator) DeleteDriver(ctx context.Context, id string) (*models.DeletionReport, error) {
+	objectID, err := parseDriverID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := c.db.For(ctx).Client.StartSession()
+	if err != nil {
+		return nil, dbError("start session", err)
+	}
+	defer session.EndSession(ctx)
+
+	var report *models.DeletionReport
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		var err error
+		report, err = c.cascade(sc, objectID)
+		return nil, err
+	})
+	if err == nil {
+		report.Transactional = true
+		c.archiveUntransacted(ctx, objectID, report)
+		return report, nil
+	}
+
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Code != mongoIllegalOperation {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Warn("Transactions unavailable, deleting driver without a transaction", "driver_id", id)
+	report, err = c.cascade(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	c.archiveUntransacted(ctx, objectID, report)
+	return report, nil
+}
+
+func (c *DeletionCoordinator) archiveUntransacted(ctx context.Context, driverID primitive.ObjectID, report *models.DeletionReport) {
+	for _, dependent := range c.untransacted {
+		count, err := dependent.ArchiveByDriver(ctx, driverID, report.DeletedAt)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to archive records of deleted driver", "collection", dependent.CollectionName(), "driver_id", driverID.Hex(), "error", err)
+			continue
+		}
+		report.Archived[dependent.CollectionName()] = count
+	}
+}
+
+func (c *DeletionCoordinator) cascade(ctx context.Context, driverID primitive.ObjectID) (*models.DeletionReport, error) {
+	now := time.Now()
+	report := &models.DeletionReport{
+		DriverID:  driverID.Hex(),
+		Archived:  make(map[string]int64),
+		DeletedAt: now,
+	}
+
+	var driver bson.M
+	if err := c.drivers.For(ctx).FindOne(ctx, bson.M{"_id": driverID}).Decode(&driver); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("%w: %s", ErrDriverNotFound, driverID.Hex())
+		}
+		return nil, dbError("find driver", err)
+	}
+
+	for _, dependent := range c.dependents {
+		count, err := dependent.ArchiveByDriver(ctx, driverID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive %s: %w", dependent.CollectionName(), err)
+		}
+		report.Archived[dependent.CollectionName()] = count
+	}
+
+	driver["archived_at"] = now
+	if _, err := c.driversArchive.For(ctx).InsertOne(ctx, driver); err != nil {
+		return nil, dbError("archive driver", err)
+	}
+	if _, err := c.drivers.For(ctx).DeleteOne(ctx, bson.M{"_id": driverID}); err != nil {
+		return nil, dbError("delete driver", err)
+	}
+	report.Archived["drivers"] = 1
+
+	return report, nil
+}
+
+// archiveMany moves every document matching filter from source into archive,
+// stamping it with archivedAt.
+func archiveMany(ctx context.Context, source, archive *mongo.Collection, filter bson.M, archivedAt time.Time) (int64, error) {
+	cursor, err := source.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return 0, err
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+
+	archived := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		doc["archived_at"] = archivedAt
+		archived[i] = doc
+	}
+	if _, err := archive.InsertMany(ctx, archived); err != nil {
+		return 0, err
+	}
+
+	result, err := source.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
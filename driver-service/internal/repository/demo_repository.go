@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/geohash"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// demoScenarioID is the ID of the one scenario document per database.
+const demoScenarioID = "current"
+
+type DemoRepository interface {
+	// Reset empties every collection of the database, keeping the
+	// collections and their indexes, and returns their names.
+	Reset(ctx context.Context) ([]string, error)
+	// Seed stores a demo dataset as given, IDs and timestamps included.
+	Seed(ctx context.Context, scenario *models.DemoScenario, drivers []models.Driver, trips []models.Trip, ratings []models.Rating) error
+	FindScenario(ctx context.Context) (*models.DemoScenario, error)
+}
+
+// MongoDemoRepository writes demo datasets straight into the collections
+// the other repositories read, and keeps the scenario in demo_scenarios.
+type MongoDemoRepository struct {
+	db        *config.MongoDB
+	drivers   config.ScopedCollection
+	trips     config.ScopedCollection
+	ratings   config.ScopedCollection
+	scenarios config.ScopedCollection
+}
+
+func NewMongoDemoRepository(db *config.MongoDB) *MongoDemoRepository {
+	return &MongoDemoRepository{
+		db:        db,
+		drivers:   db.ScopedCollection("drivers"),
+		trips:     db.ScopedCollection("trips"),
+		ratings:   db.ScopedCollection("ratings"),
+		scenarios: db.ScopedCollection("demo_scenarios"),
+	}
+}
+
+func (r *MongoDemoRepository) Reset(ctx context.Context) ([]string, error) {
+	database := r.db.For(ctx).Database
+	names, err := database.ListCollectionNames(ctx, bson.M{"type": "collection"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	cleared := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, "system.") {
+			continue
+		}
+		if _, err := database.Collection(name).DeleteMany(ctx, bson.M{}); err != nil {
+			return cleared, fmt.Errorf("failed to clear %s: %w", name, err)
+		}
+		cleared = append(cleared, name)
+	}
+
+	return cleared, nil
+}
+
+func (r *MongoDemoRepository) Seed(ctx context.Context, scenario *models.DemoScenario, drivers []models.Driver, trips []models.Trip, ratings []models.Rating) error {
+	if len(drivers) > 0 {
+		documents := make([]interface{}, len(drivers))
+		for i := range drivers {
+			drivers[i].Geohash = geohash.Encode(drivers[i].Location.Lat, drivers[i].Location.Lon, models.GeohashPrecision)
+			drivers[i].PlateKey = models.PlateKey(drivers[i].Plate)
+			drivers[i].SchemaVersion = models.CurrentDriverSchemaVersion
+			documents[i] = drivers[i]
+		}
+		if _, err := r.drivers.For(ctx).InsertMany(ctx, documents); err != nil {
+			return fmt.Errorf("failed to seed demo drivers: %w", err)
+		}
+	}
+
+	if len(trips) > 0 {
+		documents := make([]interface{}, len(trips))
+		for i := range trips {
+			documents[i] = trips[i]
+		}
+		if _, err := r.trips.For(ctx).InsertMany(ctx, documents); err != nil {
+			return fmt.Errorf("failed to seed demo trips: %w", err)
+		}
+	}
+
+	if len(ratings) > 0 {
+		documents := make([]interface{}, len(ratings))
+		for i := range ratings {
+			documents[i] = ratings[i]
+		}
+		if _, err := r.ratings.For(ctx).InsertMany(ctx, documents); err != nil {
+			return fmt.Errorf("failed to seed demo ratings: %w", err)
+		}
+	}
+
+	// Stored last, so the mover only picks up a fully seeded dataset
+	scenario.ID = demoScenarioID
+	_, err := r.scenarios.For(ctx).ReplaceOne(ctx, bson.M{"_id": demoScenarioID}, scenario, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to store demo scenario: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoDemoRepository) FindScenario(ctx context.Context) (*models.DemoScenario, error) {
+	var scenario models.DemoScenario
+	if err := r.scenarios.For(ctx).FindOne(ctx, bson.M{"_id": demoScenarioID}).Decode(&scenario); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrDemoScenarioNotFound
+		}
+		return nil, fmt.Errorf("failed to find demo scenario: %w", err)
+	}
+
+	return &scenario, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DeviceTokenRepository interface {
+	Create(ctx context.Context, token *models.DeviceToken) error
+	FindByID(ctx context.Context, driverID, tokenID string) (*models.DeviceToken, error)
+	FindByDriver(ctx context.Context, driverID string) ([]models.DeviceToken, error)
+	// FindByHash matches the current secret, or the previous one while its
+	// rotation grace period lasts.
+	FindByHash(ctx context.Context, hash string, now time.Time) (*models.DeviceToken, error)
+	Update(ctx context.Context, token *models.DeviceToken) error
+	TouchLastUsed(ctx context.Context, id primitive.ObjectID, usedAt time.Time) error
+}
+
+type MongoDeviceTokenRepository struct {
+	collection config.ScopedCollection
+	archive    config.ScopedCollection
+}
+
+func NewMongoDeviceTokenRepository(db *config.MongoDB) *MongoDeviceTokenRepository {
+	return &MongoDeviceTokenRepository{
+		collection: db.ScopedCollection("device_tokens"),
+		archive:    db.ScopedCollection("device_tokens_archive"),
+	}
+}
+
+func (r *MongoDeviceTokenRepository) Create(ctx context.Context, token *models.DeviceToken) error {
+	if token == nil {
+		return errors.New("device token cannot be nil")
+	}
+
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, token); err != nil {
+		return fmt.Errorf("failed to create device token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoDeviceTokenRepository) FindByID(ctx context.Context, driverID, tokenID string) (*models.DeviceToken, error) {
+	driverObjectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver ID format: %w", err)
+	}
+	tokenObjectID, err := primitive.ObjectIDFromHex(tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid device token ID format: %w", err)
+	}
+
+	return r.findOne(ctx, bson.M{"_id": tokenObjectID, "driver_id": driverObjectID})
+}
+
+func (r *MongoDeviceTokenRepository) FindByDriver(ctx context.Context, driverID string) ([]models.DeviceToken, error) {
+	objectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver ID format: %w", err)
+	}
+
+	cursor, err := r.collection.For(ctx).Find(ctx, bson.M{"driver_id": objectID}, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find device tokens: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	tokens := []models.DeviceToken{}
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to decode device tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *MongoDeviceTokenRepository) FindByHash(ctx context.Context, hash string, now time.Time) (*models.DeviceToken, error) {
+	return r.findOne(ctx, bson.M{"$or": []bson.M{
+		{"token_hash": hash},
+		{"previous_token_hash": hash, "previous_expires_at": bson.M{"$gt": now}},
+	}})
+}
+
+func (r *MongoDeviceTokenRepository) findOne(ctx context.Context, filter bson.M) (*models.DeviceToken, error) {
+	var token models.DeviceToken
+	if err := r.collection.For(ctx).FindOne(ctx, filter).Decode(&token); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrDeviceTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to find device token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r *MongoDeviceTokenRepository) Update(ctx context.Context, token *models.DeviceToken) error {
+	result, err := r.collection.For(ctx).ReplaceOne(ctx, bson.M{"_id": token.ID}, token)
+	if err != nil {
+		return fmt.Errorf("failed to update device token: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrDeviceTokenNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoDeviceTokenRepository) TouchLastUsed(ctx context.Context, id primitive.ObjectID, usedAt time.Time) error {
+	if _, err := r.collection.For(ctx).UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_used_at": usedAt}}); err != nil {
+		return fmt.Errorf("failed to update device token usage: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoDeviceTokenRepository) CollectionName() string {
+	return "device_tokens"
+}
+
+func (r *MongoDeviceTokenRepository) ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error) {
+	return archiveMany(ctx, r.collection.For(ctx), r.archive.For(ctx), bson.M{"driver_id": driverID}, archivedAt)
+}
+
+func (r *MongoDeviceTokenRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "device_tokens",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "token_hash", Value: 1}},
+				Options: options.Index().SetName("device_tokens_token_hash_unique").SetUnique(true),
+			},
+		},
+		{
+			Collection: "device_tokens",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "previous_token_hash", Value: 1}},
+				Options: options.Index().SetName("device_tokens_previous_token_hash").SetSparse(true),
+			},
+		},
+		{
+			Collection: "device_tokens",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "driver_id", Value: 1}},
+				Options: options.Index().SetName("device_tokens_driver_id"),
+			},
+		},
+	}
+}
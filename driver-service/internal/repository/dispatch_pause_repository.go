@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DispatchPauseRepository interface {
+	Create(ctx context.Context, pause *models.DispatchPause) error
+	// FindNotEnded returns the pauses that are active or still to come.
+	FindNotEnded(ctx context.Context, now time.Time) ([]models.DispatchPause, error)
+	FindActive(ctx context.Context, at time.Time) ([]models.DispatchPause, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type MongoDispatchPauseRepository struct {
+	collection config.ScopedCollection
+}
+
+func NewMongoDispatchPauseRepository(db *config.MongoDB) *MongoDispatchPauseRepository {
+	return &MongoDispatchPauseRepository{
+		collection: db.ScopedCollection("dispatch_pauses"),
+	}
+}
+
+func (r *MongoDispatchPauseRepository) Create(ctx context.Context, pause *models.DispatchPause) error {
+	if pause == nil {
+		return errors.New("dispatch pause cannot be nil")
+	}
+
+	if pause.ID.IsZero() {
+		pause.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, pause); err != nil {
+		return fmt.Errorf("failed to create dispatch pause: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoDispatchPauseRepository) FindNotEnded(ctx context.Context, now time.Time) ([]models.DispatchPause, error) {
+	return r.find(ctx, bson.M{"ends_at": bson.M{"$gt": now}})
+}
+
+func (r *MongoDispatchPauseRepository) FindActive(ctx context.Context, at time.Time) ([]models.DispatchPause, error) {
+	return r.find(ctx, bson.M{
+		"starts_at": bson.M{"$lte": at},
+		"ends_at":   bson.M{"$gt": at},
+	})
+}
+
+func (r *MongoDispatchPauseRepository) find(ctx context.Context, filter bson.M) ([]models.DispatchPause, error) {
+	cursor, err := r.collection.For(ctx).Find(ctx, filter, options.Find().SetSort(bson.M{"starts_at": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dispatch pauses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	pauses := []models.DispatchPause{}
+	if err := cursor.All(ctx, &pauses); err != nil {
+		return nil, fmt.Errorf("failed to decode dispatch pauses: %w", err)
+	}
+
+	return pauses, nil
+}
+
+func (r *MongoDispatchPauseRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrDispatchPauseNotFound
+	}
+
+	result, err := r.collection.For(ctx).DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return fmt.Errorf("failed to delete dispatch pause: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrDispatchPauseNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoDispatchPauseRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "dispatch_pauses",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "ends_at", Value: 1}, {Key: "starts_at", Value: 1}},
+				Options: options.Index().SetName("dispatch_pauses_ends_at_starts_at"),
+			},
+		},
+	}
+}
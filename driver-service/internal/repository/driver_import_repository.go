@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DriverImportRepository interface {
+	Create(ctx context.Context, driverImport *models.DriverImport) error
+	FindByID(ctx context.Context, id string) (*models.DriverImport, error)
+	// Find lists imports newest first, without their rows.
+	Find(ctx context.Context, limit int) ([]models.DriverImport, error)
+	// StartCommit moves a staged import to committing, where it no longer
+	// expires. It fails with ErrDriverImportConflict if the import is not
+	// staged, so only one commit applies it.
+	StartCommit(ctx context.Context, id primitive.ObjectID) error
+	// Save replaces the import.
+	Save(ctx context.Context, driverImport *models.DriverImport) error
+	// DeleteStaged drops an import that has not been committed.
+	DeleteStaged(ctx context.Context, id string) error
+}
+
+type MongoDriverImportRepository struct {
+	collection config.ScopedCollection
+}
+
+func NewMongoDriverImportRepository(db *config.MongoDB) *MongoDriverImportRepository {
+	return &MongoDriverImportRepository{
+		collection: db.ScopedCollection("driver_imports"),
+	}
+}
+
+func (r *MongoDriverImportRepository) Create(ctx context.Context, driverImport *models.DriverImport) error {
+	if driverImport == nil {
+		return errors.New("driver import cannot be nil")
+	}
+
+	if driverImport.ID.IsZero() {
+		driverImport.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, driverImport); err != nil {
+		return fmt.Errorf("failed to create driver import: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoDriverImportRepository) FindByID(ctx context.Context, id string) (*models.DriverImport, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver import ID format: %w", err)
+	}
+
+	var driverImport models.DriverImport
+	if err := r.collection.For(ctx).FindOne(ctx, bson.M{"_id": objectID}).Decode(&driverImport); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrDriverImportNotFound
+		}
+		return nil, fmt.Errorf("failed to find driver import: %w", err)
+	}
+
+	return &driverImport, nil
+}
+
+func (r *MongoDriverImportRepository) Find(ctx context.Context, limit int) ([]models.DriverImport, error) {
+	findOptions := options.Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetProjection(bson.M{"rows": 0})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.collection.For(ctx).Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find driver imports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	imports := []models.DriverImport{}
+	if err := cursor.All(ctx, &imports); err != nil {
+		return nil, fmt.Errorf("failed to decode driver imports: %w", err)
+	}
+
+	return imports, nil
+}
+
+func (r *MongoDriverImportRepository) StartCommit(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.For(ctx).UpdateOne(ctx,
+		bson.M{"_id": id, "status": models.DriverImportStaged},
+		bson.M{
+			"$set":   bson.M{"status": models.DriverImportCommitting},
+			"$unset": bson.M{"expires_at": ""},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to start driver import commit: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrDriverImportConflict
+	}
+
+	return nil
+}
+
+func (r *MongoDriverImportRepository) Save(ctx context.Context, driverImport *models.DriverImport) error {
+	if _, err := r.collection.For(ctx).ReplaceOne(ctx, bson.M{"_id": driverImport.ID}, driverImport); err != nil {
+		return fmt.Errorf("failed to save driver import: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoDriverImportRepository) DeleteStaged(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid driver import ID format: %w", err)
+	}
+
+	result, err := r.collection.For(ctx).DeleteOne(ctx, bson.M{"_id": objectID, "status": models.DriverImportStaged})
+	if err != nil {
+		return fmt.Errorf("failed to delete driver import: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		if _, err := r.FindByID(ctx, id); err != nil {
+			return err
+		}
+		return ErrDriverImportConflict
+	}
+
+	return nil
+}
+
+func (r *MongoDriverImportRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "driver_imports",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("driver_imports_created_at"),
+			},
+		},
+		{
+			// Staged imports expire; committed ones have no expires_at
+			Collection: "driver_imports",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetName("driver_imports_expires_at").SetExpireAfterSeconds(0),
+			},
+		},
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/geohash"
 	"github.com/taxihub/driver-service/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,21 +18,37 @@ import (
 
 type DriverRepository interface {
 	Create(ctx context.Context, driver *models.Driver) (string, error)
-	Update(ctx context.Context, id string, driver *models.Driver) error
+	// Update writes the given fields of driver, and its update time. Fields
+	// that are not named keep their stored values, so concurrent updates of
+	// other fields are not undone.
+	Update(ctx context.Context, id string, driver *models.Driver, fields ...models.DriverField) error
+	// SetStatus changes only the driver's availability status, so a
+	// concurrent Update cannot undo it.
+	SetStatus(ctx context.Context, id string, status string, at time.Time) error
+	// SetReviewStatus moves the driver's review status from one status to
+	// another with the admin's note. It fails with ErrDriverReviewChanged
+	// when the status is no longer from, so a decision is never made on a
+	// stale read. Drivers without a review status count as approved.
+	SetReviewStatus(ctx context.Context, id string, from, to, note string, at time.Time) error
 	FindByID(ctx context.Context, id string) (*models.Driver, error)
-	FindAll(ctx context.Context, page, pageSize int) ([]models.Driver, int64, error)
-	FindNearby(ctx context.Context, lat, lon, radiusKm float64, taxiType string) ([]models.DriverWithDistance, error)
+	FindAll(ctx context.Context, page, pageSize int, filter models.DriverListFilter) ([]models.Driver, int64, error)
+	FindNearby(ctx context.Context, lat, lon, radiusKm float64, filter models.NearbyFilter) ([]models.DriverWithDistance, error)
+	// FindByPlate finds the driver by the plate's models.PlateKey, so any
+	// spelling of it matches.
 	FindByPlate(ctx context.Context, plate string) (*models.Driver, error)
+	// Search matches the words of terms against first_name, last_name and
+	// plate, best matches first.
+	Search(ctx context.Context, terms string, page, pageSize int) ([]models.Driver, int64, error)
 	Delete(ctx context.Context, id string) error
 }
 
 type MongoDriverRepository struct {
-	collection *mongo.Collection
+	collection config.ScopedCollection
 }
 
 func NewMongoDriverRepository(db *config.MongoDB) *MongoDriverRepository {
 	return &MongoDriverRepository{
-		collection: db.GetCollection("drivers"),
+		collection: db.ScopedCollection("drivers"),
 	}
 }
 
@@ -46,13 +64,16 @@ func (r *MongoDriverRepository) Create(ctx context.Context, driver *models.Drive
 	if driver.ID.IsZero() {
 		driver.ID = primitive.NewObjectID()
 	}
+	driver.Geohash = geohash.Encode(driver.Location.Lat, driver.Location.Lon, models.GeohashPrecision)
+	driver.PlateKey = models.PlateKey(driver.Plate)
+	driver.SchemaVersion = models.CurrentDriverSchemaVersion
 
-	result, err := r.collection.InsertOne(ctx, driver)
+	result, err := r.collection.For(ctx).InsertOne(ctx, driver)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
-			return "", fmt.Errorf("driver with plate %s already exists", driver.Plate)
+			return "", fmt.Errorf("%w: plate %s", ErrDriverAlreadyExists, driver.Plate)
 		}
-		return "", fmt.Errorf("failed to create driver: %w", err)
+		return "", dbError("create driver", err)
 	}
 
 	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
@@ -62,76 +83,174 @@ func (r *MongoDriverRepository) Create(ctx context.Context, driver *models.Drive
 	return driver.ID.Hex(), nil
 }
 
-func (r *MongoDriverRepository) Update(ctx context.Context, id string, driver *models.Driver) error {
-	if id == "" {
-		return errors.New("driver ID cannot be empty")
-	}
+func (r *MongoDriverRepository) Update(ctx context.Context, id string, driver *models.Driver, fields ...models.DriverField) error {
 	if driver == nil {
 		return errors.New("driver cannot be nil")
 	}
 
-	objectID, err := primitive.ObjectIDFromHex(id)
+	objectID, err := parseDriverID(id)
 	if err != nil {
-		return fmt.Errorf("invalid driver ID format: %w", err)
+		return err
 	}
 
 	driver.UpdatedAt = time.Now()
+	driver.PlateKey = models.PlateKey(driver.Plate)
 
-	update := bson.M{
-		"$set": bson.M{
-			"first_name": driver.FirstName,
-			"last_name":  driver.LastName,
-			"plate":      driver.Plate,
-			"taxi_type":  driver.TaxiType,
-			"car_brand":  driver.CarBrand,
-			"car_model":  driver.CarModel,
-			"location":   driver.Location,
-			"updated_at": driver.UpdatedAt,
-		},
+	set := bson.M{"updated_at": driver.UpdatedAt}
+	for _, field := range fields {
+		values, err := driverFieldValues(driver, field)
+		if err != nil {
+			return err
+		}
+		for name, value := range values {
+			set[name] = value
+		}
 	}
+	update := bson.M{"$set": set}
 
-	result, err := r.collection.UpdateOne(
+	result, err := r.collection.For(ctx).UpdateOne(
 		ctx,
 		bson.M{"_id": objectID},
 		update,
 	)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
-			return fmt.Errorf("driver with plate %s already exists", driver.Plate)
+			return fmt.Errorf("%w: plate %s", ErrDriverAlreadyExists, driver.Plate)
 		}
-		return fmt.Errorf("failed to update driver: %w", err)
+		return dbError("update driver", err)
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("driver with ID %s not found", id)
+		return fmt.Errorf("%w: %s", ErrDriverNotFound, id)
 	}
 
 	return nil
 }
 
-func (r *MongoDriverRepository) FindByID(ctx context.Context, id string) (*models.Driver, error) {
-	if id == "" {
-		return nil, errors.New("driver ID cannot be empty")
+// driverFieldValues returns the document fields Update sets for field.
+func driverFieldValues(driver *models.Driver, field models.DriverField) (bson.M, error) {
+	switch field {
+	case models.DriverFieldPlate:
+		return bson.M{"plate": driver.Plate, "plate_key": driver.PlateKey}, nil
+	case models.DriverFieldVerified:
+		return bson.M{"verified": driver.Verified, "verified_at": driver.VerifiedAt}, nil
+	case models.DriverFieldLocation:
+		return bson.M{
+			"location": driver.Location,
+			"geohash":  geohash.Encode(driver.Location.Lat, driver.Location.Lon, models.GeohashPrecision),
+		}, nil
+	}
+
+	values := bson.M{
+		"first_name":    driver.FirstName,
+		"last_name":     driver.LastName,
+		"taxi_type":     driver.TaxiType,
+		"fleet_id":      driver.FleetID,
+		"car_brand":     driver.CarBrand,
+		"car_model":     driver.CarModel,
+		"car_color":     driver.CarColor,
+		"bio":           driver.Bio,
+		"photo_url":     driver.PhotoURL,
+		"traveled_km":   driver.TraveledKm,
+		"tax_info":      driver.TaxInfo,
+		"localizations": driver.Localizations,
+
+		"license_classes": driver.LicenseClasses,
+		"languages":       driver.Languages,
+
+		"location_recorded_at": driver.LocationRecordedAt,
+
+		"email": driver.Email,
+
+		"custom_fields": driver.CustomFields,
+	}
+	value, ok := values[string(field)]
+	if !ok {
+		return nil, fmt.Errorf("unknown driver field %q", field)
+	}
+	return bson.M{string(field): value}, nil
+}
+
+func (r *MongoDriverRepository) SetStatus(ctx context.Context, id string, status string, at time.Time) error {
+	objectID, err := parseDriverID(id)
+	if err != nil {
+		return err
 	}
 
-	objectID, err := primitive.ObjectIDFromHex(id)
+	result, err := r.collection.For(ctx).UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{
+			"status":            status,
+			"status_updated_at": at,
+			"updated_at":        at,
+		}},
+	)
 	if err != nil {
-		return nil, fmt.Errorf("invalid driver ID format: %w", err)
+		return dbError("update driver status", err)
 	}
 
-	var driver models.Driver
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&driver)
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w: %s", ErrDriverNotFound, id)
+	}
+
+	return nil
+}
+
+func (r *MongoDriverRepository) SetReviewStatus(ctx context.Context, id string, from, to, note string, at time.Time) error {
+	objectID, err := parseDriverID(id)
+	if err != nil {
+		return err
+	}
+
+	current := interface{}(from)
+	if from == models.DriverReviewApproved {
+		current = bson.M{"$in": bson.A{from, "", nil}}
+	}
+	result, err := r.collection.For(ctx).UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "review_status": current},
+		bson.M{"$set": bson.M{
+			"review_status": to,
+			"review_note":   note,
+			"reviewed_at":   at,
+			"updated_at":    at,
+		}},
+	)
+	if err != nil {
+		return dbError("update driver review status", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w: %s", ErrDriverReviewChanged, id)
+	}
+
+	return nil
+}
+
+func (r *MongoDriverRepository) FindByID(ctx context.Context, id string) (*models.Driver, error) {
+	objectID, err := parseDriverID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := r.collection.For(ctx).FindOne(ctx, bson.M{"_id": objectID}).DecodeBytes()
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("driver with ID %s not found", id)
+			return nil, fmt.Errorf("%w: %s", ErrDriverNotFound, id)
 		}
-		return nil, fmt.Errorf("failed to find driver: %w", err)
+		return nil, dbError("find driver", err)
+	}
+
+	var driver models.Driver
+	if err := r.decodeDriver(ctx, raw, &driver); err != nil {
+		return nil, dbError("decode driver", err)
 	}
 
 	return &driver, nil
 }
 
-func (r *MongoDriverRepository) FindAll(ctx context.Context, page, pageSize int) ([]models.Driver, int64, error) {
+func (r *MongoDriverRepository) FindAll(ctx context.Context, page, pageSize int, filter models.DriverListFilter) ([]models.Driver, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -142,102 +261,268 @@ func (r *MongoDriverRepository) FindAll(ctx context.Context, page, pageSize int)
 		pageSize = 100
 	}
 
+	sortField, descending, ok := filter.SortField()
+	if !ok {
+		return nil, 0, fmt.Errorf("unsupported sort %q", filter.Sort)
+	}
+	direction := 1
+	if descending {
+		direction = -1
+	}
+
 	skip := (page - 1) * pageSize
+	query := driverListQuery(filter)
 
-	totalCount, err := r.collection.CountDocuments(ctx, bson.M{})
+	totalCount, err := r.collection.For(ctx).CountDocuments(ctx, query)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count drivers: %w", err)
+		return nil, 0, dbError("count drivers", err)
 	}
 
 	findOptions := options.Find()
 	findOptions.SetSkip(int64(skip))
 	findOptions.SetLimit(int64(pageSize))
-	findOptions.SetSort(bson.M{"created_at": -1}) // Sort by creation date, newest first
+	// _id breaks ties so equal sort values do not repeat across pages
+	findOptions.SetSort(bson.D{{Key: sortField, Value: direction}, {Key: "_id", Value: direction}})
 
-	cursor, err := r.collection.Find(ctx, bson.M{}, findOptions)
+	cursor, err := r.collection.For(ctx).Find(ctx, query, findOptions)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to find drivers: %w", err)
+		return nil, 0, dbError("find drivers", err)
 	}
 	defer cursor.Close(ctx)
 
 	// Decode results
-	var drivers []models.Driver
-	if err = cursor.All(ctx, &drivers); err != nil {
-		return nil, 0, fmt.Errorf("failed to decode drivers: %w", err)
+	drivers, err := r.decodeDrivers(ctx, cursor)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	return drivers, totalCount, nil
 }
 
-func (r *MongoDriverRepository) FindNearby(ctx context.Context, lat, lon, radiusKm float64, taxiType string) ([]models.DriverWithDistance, error) {
-	if lat < -90 || lat > 90 {
-		return nil, errors.New("invalid latitude value")
+func (r *MongoDriverRepository) Search(ctx context.Context, terms string, page, pageSize int) ([]models.Driver, int64, error) {
+	if page < 1 {
+		page = 1
 	}
-	if lon < -180 || lon > 180 {
-		return nil, errors.New("invalid longitude value")
+	if pageSize < 1 {
+		pageSize = 10
 	}
-	if radiusKm <= 0 {
-		return nil, errors.New("radius must be positive")
+	if pageSize > 100 {
+		pageSize = 100
 	}
 
-	center := bson.M{
-		"type":        "Point",
-		"coordinates": []float64{lon, lat},
+	query := bson.M{"$text": bson.M{"$search": terms}}
+	totalCount, err := r.collection.For(ctx).CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, dbError("count matching drivers", err)
 	}
 
-	query := bson.M{
-		"location": bson.M{
-			"$nearSphere": bson.M{
-				"$geometry":    center,
-				"$maxDistance": radiusKm * 1000,
-			},
-		},
+	score := bson.M{"$meta": "textScore"}
+	findOptions := options.Find().
+		SetProjection(bson.M{"search_score": score}).
+		SetSort(bson.D{{Key: "search_score", Value: score}, {Key: "_id", Value: 1}}).
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize))
+
+	cursor, err := r.collection.For(ctx).Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, 0, dbError("search drivers", err)
 	}
+	defer cursor.Close(ctx)
 
-	if taxiType != "" && models.IsValidTaxiType(taxiType) {
-		query["taxi_type"] = taxiType
+	drivers, err := r.decodeDrivers(ctx, cursor)
+	if err != nil {
+		return nil, 0, err
+	}
+	if drivers == nil {
+		drivers = []models.Driver{}
 	}
 
-	pipeline := []bson.M{
-		{
-			"$geoNear": bson.M{
-				"near":          center,
-				"distanceField": "distance",
-				"maxDistance":   radiusKm * 1000,
-				"spherical":     true,
-			},
-		},
+	return drivers, totalCount, nil
+}
+
+// CountInZone counts the approved drivers whose stored geohash lies in the
+// zone cell.
+func (r *MongoDriverRepository) CountInZone(ctx context.Context, zone string) (*models.ZoneDriverCount, error) {
+	inZone := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(zone)}
+
+	approved := bson.M{"$nin": models.UnapprovedDriverReviewStatuses()}
+	online, err := r.collection.For(ctx).CountDocuments(ctx, bson.M{
+		"geohash":       inZone,
+		"status":        bson.M{"$ne": models.DriverStatusOffline},
+		"review_status": approved,
+	})
+	if err != nil {
+		return nil, dbError("count online drivers", err)
 	}
 
-	if taxiType != "" && models.IsValidTaxiType(taxiType) {
-		pipeline = append(pipeline, bson.M{
-			"$match": bson.M{"taxi_type": taxiType},
-		})
+	available, err := r.collection.For(ctx).CountDocuments(ctx, bson.M{
+		"geohash":       inZone,
+		"status":        bson.M{"$nin": bson.A{models.DriverStatusBusy, models.DriverStatusOffline}},
+		"review_status": approved,
+	})
+	if err != nil {
+		return nil, dbError("count available drivers", err)
+	}
+
+	return &models.ZoneDriverCount{Online: int(online), Available: int(available)}, nil
+}
+
+// driverListQuery turns a list filter into a MongoDB query.
+func driverListQuery(filter models.DriverListFilter) bson.M {
+	query := bson.M{}
+	if filter.TaxiType != "" {
+		query["taxi_type"] = filter.TaxiType
+	}
+	if filter.CarBrand != "" {
+		query["car_brand"] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(filter.CarBrand) + "$", Options: "i"}
+	}
+	switch filter.Status {
+	case "":
+	case models.DriverStatusAvailable:
+		// Drivers stored before statuses existed have none and count as available
+		query["status"] = bson.M{"$nin": bson.A{models.DriverStatusBusy, models.DriverStatusOffline}}
+	default:
+		query["status"] = filter.Status
+	}
+	switch filter.ReviewStatus {
+	case "":
+	case models.DriverReviewApproved:
+		// Drivers stored before the approval workflow have none and count as approved
+		query["review_status"] = bson.M{"$nin": models.UnapprovedDriverReviewStatuses()}
+	default:
+		query["review_status"] = filter.ReviewStatus
+	}
+	if filter.FleetID != "" {
+		query["fleet_id"] = filter.FleetID
+	}
+	for key, value := range filter.CustomFields {
+		query["custom_fields."+key] = value
+	}
+	if !filter.CreatedAfter.IsZero() {
+		query["created_at"] = bson.M{"$gt": filter.CreatedAfter}
+	}
+	return query
+}
+
+// FindByIDs returns the drivers with the given IDs, in no particular order;
+// IDs with no driver are skipped.
+func (r *MongoDriverRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Driver, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := r.collection.For(ctx).Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, dbError("find drivers", err)
 	}
+	defer cursor.Close(ctx)
 
-	pipeline = append(pipeline, bson.M{"$limit": 50})
+	return r.decodeDrivers(ctx, cursor)
+}
+
+// decodeDrivers decodes the drivers of a cursor, migrating stale documents.
+func (r *MongoDriverRepository) decodeDrivers(ctx context.Context, cursor *mongo.Cursor) ([]models.Driver, error) {
+	var drivers []models.Driver
+	for cursor.Next(ctx) {
+		var driver models.Driver
+		if err := r.decodeDriver(ctx, cursor.Current, &driver); err != nil {
+			return nil, dbError("decode drivers", err)
+		}
+		drivers = append(drivers, driver)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, dbError("decode drivers", err)
+	}
+	return drivers, nil
+}
 
-	cursor, err := r.collection.Aggregate(ctx, pipeline)
+// ScanLocations calls fn with the ID and location of every driver.
+func (r *MongoDriverRepository) ScanLocations(ctx context.Context, fn func(id primitive.ObjectID, location models.Location) error) error {
+	findOptions := options.Find().SetProjection(bson.M{"location": 1})
+	cursor, err := r.collection.For(ctx).Find(ctx, bson.M{}, findOptions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find nearby drivers: %w", err)
+		return dbError("scan driver locations", err)
 	}
 	defer cursor.Close(ctx)
 
-	var results []struct {
-		models.Driver `bson:",inline"`
-		Distance      float64 `bson:"distance"`
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID       primitive.ObjectID `bson:"_id"`
+			Location models.Location    `bson:"location"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("failed to decode driver location: %w", err)
+		}
+		if err := fn(doc.ID, doc.Location); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (r *MongoDriverRepository) FindNearby(ctx context.Context, lat, lon, radiusKm float64, filter models.NearbyFilter) ([]models.DriverWithDistance, error) {
+	if lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("%w: latitude must be between -90 and 90", ErrInvalidCoordinates)
+	}
+	if lon < -180 || lon > 180 {
+		return nil, fmt.Errorf("%w: longitude must be between -180 and 180", ErrInvalidCoordinates)
+	}
+	if radiusKm <= 0 {
+		return nil, fmt.Errorf("%w: radius must be positive", ErrInvalidRadius)
+	}
+
+	// Filters go into $geoNear's query so they apply before the limit
+	query := bson.M{
+		"review_status": bson.M{"$nin": models.UnapprovedDriverReviewStatuses()},
+	}
+	if filter.TaxiType != "" && models.IsValidTaxiType(filter.TaxiType) {
+		query["taxi_type"] = filter.TaxiType
+	}
+	if filter.VerifiedOnly {
+		query["verified"] = true
+	}
+	if !filter.IncludeUnavailable {
+		query["status"] = bson.M{"$nin": bson.A{models.DriverStatusBusy, models.DriverStatusOffline}}
+	}
+	if filter.MinRating > 0 {
+		query["average_rating"] = bson.M{"$gte": filter.MinRating}
+	}
+
+	pipeline, err := NewPipeline().
+		GeoNear(GeoNear{
+			Near:              models.Location{Lat: lat, Lon: lon},
+			DistanceField:     "distance",
+			MaxDistanceMeters: radiusKm * 1000,
+			Query:             query,
+		}).
+		Limit(filter.ResultLimit()).
+		Build()
+	if err != nil {
+		return nil, err
 	}
 
-	if err = cursor.All(ctx, &results); err != nil {
-		return nil, fmt.Errorf("failed to decode nearby drivers: %w", err)
+	cursor, err := r.collection.For(ctx).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, dbError("find nearby drivers", err)
 	}
+	defer cursor.Close(ctx)
 
-	driversWithDistance := make([]models.DriverWithDistance, len(results))
-	for i, result := range results {
-		driversWithDistance[i] = models.DriverWithDistance{
+	driversWithDistance := []models.DriverWithDistance{}
+	for cursor.Next(ctx) {
+		var result struct {
+			models.Driver `bson:",inline"`
+			Distance      float64 `bson:"distance"`
+		}
+		if err := r.decodeDriver(ctx, cursor.Current, &result); err != nil {
+			return nil, dbError("decode nearby drivers", err)
+		}
+		driversWithDistance = append(driversWithDistance, models.DriverWithDistance{
 			Driver:     result.Driver,
 			DistanceKm: result.Distance / 1000,
-		}
+		})
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, dbError("decode nearby drivers", err)
 	}
 
 	return driversWithDistance, nil
@@ -248,36 +533,93 @@ func (r *MongoDriverRepository) FindByPlate(ctx context.Context, plate string) (
 		return nil, errors.New("plate cannot be empty")
 	}
 
-	var driver models.Driver
-	err := r.collection.FindOne(ctx, bson.M{"plate": plate}).Decode(&driver)
+	// Drivers the schema backfill has not reached yet have no key and are
+	// found by the spellings their plate may be stored under.
+	filter := bson.M{"$or": bson.A{
+		bson.M{"plate_key": models.PlateKey(plate)},
+		bson.M{"plate": bson.M{"$in": models.PlateVariants(plate)}, "plate_key": bson.M{"$exists": false}},
+	}}
+	raw, err := r.collection.For(ctx).FindOne(ctx, filter).DecodeBytes()
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, ErrDriverNotFound
 		}
-		return nil, fmt.Errorf("failed to find driver by plate: %w", err)
+		return nil, dbError("find driver by plate", err)
+	}
+
+	var driver models.Driver
+	if err := r.decodeDriver(ctx, raw, &driver); err != nil {
+		return nil, dbError("decode driver", err)
 	}
 
 	return &driver, nil
 }
 
 func (r *MongoDriverRepository) Delete(ctx context.Context, id string) error {
-	if id == "" {
-		return errors.New("driver ID cannot be empty")
-	}
-
-	objectID, err := primitive.ObjectIDFromHex(id)
+	objectID, err := parseDriverID(id)
 	if err != nil {
-		return fmt.Errorf("invalid driver ID format: %w", err)
+		return err
 	}
 
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	result, err := r.collection.For(ctx).DeleteOne(ctx, bson.M{"_id": objectID})
 	if err != nil {
-		return fmt.Errorf("failed to delete driver: %w", err)
+		return dbError("delete driver", err)
 	}
 
 	if result.DeletedCount == 0 {
-		return fmt.Errorf("driver with ID %s not found", id)
+		return fmt.Errorf("%w: %s", ErrDriverNotFound, id)
 	}
 
 	return nil
 }
+
+func (r *MongoDriverRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "drivers",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "location", Value: "2dsphere"}},
+				Options: options.Index().SetName("drivers_location_2dsphere"),
+			},
+		},
+		{
+			Collection: "drivers",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "plate", Value: 1}},
+				Options: options.Index().SetName("drivers_plate_unique").SetUnique(true),
+			},
+		},
+		{
+			// Spellings of one plate share a key, so "34ABC123" cannot be
+			// registered next to "34 ABC 123"
+			Collection: "drivers",
+			Model: mongo.IndexModel{
+				Keys: bson.D{{Key: "plate_key", Value: 1}},
+				Options: options.Index().
+					SetName("drivers_plate_key_unique").
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"plate_key": bson.M{"$type": "string"}}),
+			},
+		},
+		{
+			// Names are Turkish, so no language's stemming or stop words apply
+			Collection: "drivers",
+			Model: mongo.IndexModel{
+				Keys: bson.D{{Key: "first_name", Value: "text"}, {Key: "last_name", Value: "text"}, {Key: "plate", Value: "text"}},
+				Options: options.Index().
+					SetName("drivers_search_text").
+					SetDefaultLanguage("none").
+					SetWeights(bson.D{{Key: "plate", Value: 3}, {Key: "last_name", Value: 2}, {Key: "first_name", Value: 1}}),
+			},
+		},
+		{
+			// Fleets define their own custom fields, so a wildcard index
+			// covers whichever one a list filters on
+			Collection: "drivers",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "custom_fields.$**", Value: 1}},
+				Options: options.Index().SetName("drivers_custom_fields_wildcard"),
+			},
+		},
+	}
+}
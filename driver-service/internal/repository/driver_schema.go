@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/reindex"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DriverMigration upgrades driver documents by one schema version.
+type DriverMigration struct {
+	// Version is the schema version the migration produces.
+	Version     int
+	Description string
+	// Up returns the top-level fields to set on a document of the previous
+	// version, or nil when it needs none.
+	Up func(raw bson.Raw) (bson.M, error)
+}
+
+// DriverMigrations are the up-migrations of driver documents in order, one
+// per version after 1. The last one produces models.CurrentDriverSchemaVersion.
+var DriverMigrations = []DriverMigration{
+	{
+		Version:     2,
+		Description: "store location as a GeoJSON Point instead of {lat, lon}",
+		Up:          reindex.GeoJSONTransform{}.Apply,
+	},
+	{
+		Version:     3,
+		Description: "store the implied status and review status of drivers that have none",
+		Up:          migrateDriverImpliedStatuses,
+	},
+	{
+		Version:     4,
+		Description: "store the spelling-independent plate_key of the plate",
+		Up:          migrateDriverPlateKey,
+	},
+}
+
+// migrateDriverImpliedStatuses makes explicit that drivers stored before
+// statuses and the approval workflow are available and approved.
+func migrateDriverImpliedStatuses(raw bson.Raw) (bson.M, error) {
+	set := bson.M{}
+	if status, _ := raw.Lookup("status").StringValueOK(); status == "" {
+		set["status"] = models.DriverStatusAvailable
+	}
+	if reviewStatus, _ := raw.Lookup("review_status").StringValueOK(); reviewStatus == "" {
+		set["review_status"] = models.DriverReviewApproved
+	}
+	if len(set) == 0 {
+		return nil, nil
+	}
+	return set, nil
+}
+
+// migrateDriverPlateKey stores the key plate lookups and the unique index go
+// by.
+func migrateDriverPlateKey(raw bson.Raw) (bson.M, error) {
+	plate, _ := raw.Lookup("plate").StringValueOK()
+	if plate == "" {
+		return nil, nil
+	}
+	return bson.M{"plate_key": models.PlateKey(plate)}, nil
+}
+
+// DriverSchemaRepository reports and backfills the schema versions of driver
+// documents.
+type DriverSchemaRepository interface {
+	CountBySchemaVersion(ctx context.Context) (map[int]int64, error)
+	// MigrateBatch migrates up to limit stale driver documents with IDs
+	// after the given one, in ID order.
+	MigrateBatch(ctx context.Context, after primitive.ObjectID, limit int) (*DriverMigrationBatch, error)
+}
+
+// DriverMigrationBatch is the outcome of one MigrateBatch call. LastID is
+// the last document scanned; fewer than limit scanned means none are left.
+type DriverMigrationBatch struct {
+	Scanned  int
+	Migrated int
+	Failed   int
+	LastID   primitive.ObjectID
+}
+
+// staleDriverSchemaQuery matches driver documents behind the current schema
+// version, including those without one.
+func staleDriverSchemaQuery() bson.M {
+	return bson.M{"schema_version": bson.M{"$not": bson.M{"$gte": models.CurrentDriverSchemaVersion}}}
+}
+
+// driverSchemaVersion reads a document's schema version; documents stored
+// before versioning are version 1.
+func driverSchemaVersion(raw bson.Raw) int {
+	value := raw.Lookup("schema_version")
+	if v, ok := value.Int32OK(); ok {
+		return int(v)
+	}
+	if v, ok := value.Int64OK(); ok {
+		return int(v)
+	}
+	return 1
+}
+
+// migrateDriverDocument applies the pending up-migrations to raw. It returns
+// the migrated document and the $set that persists it, or raw and a nil $set
+// when the document is current.
+func migrateDriverDocument(raw bson.Raw) (bson.Raw, bson.M, error) {
+	version := driverSchemaVersion(raw)
+	if version >= models.CurrentDriverSchemaVersion {
+		return raw, nil, nil
+	}
+
+	set := bson.M{}
+	for _, migration := range DriverMigrations {
+		if migration.Version <= version {
+			continue
+		}
+		changes, err := migration.Up(raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("migration to schema version %d failed: %w", migration.Version, err)
+		}
+		for key, value := range changes {
+			set[key] = value
+		}
+		// Later migrations see the fields set by earlier ones
+		if raw, err = setFields(raw, changes); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	set["schema_version"] = models.CurrentDriverSchemaVersion
+	migrated, err := setFields(raw, bson.M{"schema_version": models.CurrentDriverSchemaVersion})
+	if err != nil {
+		return nil, nil, err
+	}
+	return migrated, set, nil
+}
+
+// setFields returns raw with the given top-level fields replaced or added.
+func setFields(raw bson.Raw, fields bson.M) (bson.Raw, error) {
+	if len(fields) == 0 {
+		return raw, nil
+	}
+
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode driver document: %w", err)
+	}
+	for key, value := range fields {
+		replaced := false
+		for i := range doc {
+			if doc[i].Key == key {
+				doc[i].Value = value
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			doc = append(doc, bson.E{Key: key, Value: value})
+		}
+	}
+
+	updated, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode driver document: %w", err)
+	}
+	return updated, nil
+}
+
+// decodeDriver decodes a driver document into out, migrating and persisting
+// it first when it is behind the current schema version. A document that
+// fails to migrate is decoded as stored, so reads never fail on it; the
+// backfill reports it.
+func (r *MongoDriverRepository) decodeDriver(ctx context.Context, raw bson.Raw, out interface{}) error {
+	migrated, set, err := migrateDriverDocument(raw)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to migrate driver document", "driver_id", driverDocumentID(raw), "error", err)
+		return bson.Unmarshal(raw, out)
+	}
+	if set != nil {
+		if err := r.persistMigration(ctx, raw, set); err != nil {
+			logging.FromContext(ctx).Warn("Failed to persist driver migration", "driver_id", driverDocumentID(raw), "error", err)
+		}
+	}
+	return bson.Unmarshal(migrated, out)
+}
+
+func driverDocumentID(raw bson.Raw) string {
+	id, _ := raw.Lookup("_id").ObjectIDOK()
+	return id.Hex()
+}
+
+// persistMigration writes the migrated fields of a document back, unless it
+// changed since it was read; that write wins and the next read migrates the
+// document again.
+func (r *MongoDriverRepository) persistMigration(ctx context.Context, raw bson.Raw, set bson.M) error {
+	filter := staleDriverSchemaQuery()
+	filter["_id"] = raw.Lookup("_id")
+	if updatedAt, err := raw.LookupErr("updated_at"); err == nil {
+		filter["updated_at"] = updatedAt
+	}
+
+	if _, err := r.collection.For(ctx).UpdateOne(ctx, filter, bson.M{"$set": set}); err != nil {
+		return fmt.Errorf("failed to persist driver migration: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoDriverRepository) CountBySchemaVersion(ctx context.Context) (map[int]int64, error) {
+	pipeline, err := NewPipeline().
+		Group(bson.M{"$ifNull": bson.A{"$schema_version", 1}}, bson.M{
+			"count": bson.M{"$sum": 1},
+		}).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.For(ctx).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count driver schema versions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Version int   `bson:"_id"`
+		Count   int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode driver schema versions: %w", err)
+	}
+
+	counts := make(map[int]int64, len(groups))
+	for _, group := range groups {
+		counts[group.Version] = group.Count
+	}
+	return counts, nil
+}
+
+func (r *MongoDriverRepository) MigrateBatch(ctx context.Context, after primitive.ObjectID, limit int) (*DriverMigrationBatch, error) {
+	query := staleDriverSchemaQuery()
+	if !after.IsZero() {
+		query["_id"] = bson.M{"$gt": after}
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.For(ctx).Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stale drivers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	batch := &DriverMigrationBatch{LastID: after}
+	for cursor.Next(ctx) {
+		raw := cursor.Current
+		batch.Scanned++
+		if id, ok := raw.Lookup("_id").ObjectIDOK(); ok {
+			batch.LastID = id
+		}
+
+		_, set, err := migrateDriverDocument(raw)
+		if err == nil && set != nil {
+			err = r.persistMigration(ctx, raw, set)
+		}
+		if err != nil {
+			batch.Failed++
+			logging.FromContext(ctx).Warn("Failed to migrate driver document", "driver_id", batch.LastID.Hex(), "error", err)
+			continue
+		}
+		batch.Migrated++
+	}
+	if err := cursor.Err(); err != nil {
+		return batch, fmt.Errorf("failed to read stale drivers: %w", err)
+	}
+	return batch, nil
+}
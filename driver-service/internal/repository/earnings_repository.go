@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EarningsRepository keeps drivers' earnings entries and the running total
+// of each driver's day.
+type EarningsRepository interface {
+	// Record stores the entry and adds it to the total of its day,
+	// returning the total after it. A trip's entry of a kind already
+	// recorded fails with ErrEarningsEntryExists and changes nothing.
+	Record(ctx context.Context, entry *models.EarningsEntry) (*models.DailyEarnings, error)
+	// FindDay returns the driver's total of the day, zero when they earned
+	// nothing, and up to limit of its entries, newest first.
+	FindDay(ctx context.Context, driverID, day string, limit int) (*models.DailyEarnings, []models.EarningsEntry, error)
+}
+
+// MongoEarningsRepository keeps entries in driver_earnings and the totals
+// in driver_earnings_daily. An entry and its day's total are written in one
+// transaction, so a failure between them cannot leave the entry out of the
+// total.
+type MongoEarningsRepository struct {
+	db             *config.MongoDB
+	entries        config.ScopedCollection
+	entriesArchive config.ScopedCollection
+	daily          config.ScopedCollection
+	dailyArchive   config.ScopedCollection
+}
+
+func NewMongoEarningsRepository(db *config.MongoDB) *MongoEarningsRepository {
+	return &MongoEarningsRepository{
+		db:             db,
+		entries:        db.ScopedCollection("driver_earnings"),
+		entriesArchive: db.ScopedCollection("driver_earnings_archive"),
+		daily:          db.ScopedCollection("driver_earnings_daily"),
+		dailyArchive:   db.ScopedCollection("driver_earnings_daily_archive"),
+	}
+}
+
+func (r *MongoEarningsRepository) Record(ctx context.Context, entry *models.EarningsEntry) (*models.DailyEarnings, error) {
+	if entry == nil {
+		return nil, errors.New("earnings entry cannot be nil")
+	}
+
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+
+	session, err := r.db.For(ctx).Client.StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	var total *models.DailyEarnings
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		var err error
+		total, err = r.record(sc, entry)
+		return nil, err
+	})
+	if err == nil {
+		return total, nil
+	}
+
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Code != mongoIllegalOperation {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Warn("Transactions unavailable, recording earnings without a transaction", "driver_id", entry.DriverID)
+	return r.record(ctx, entry)
+}
+
+// record inserts the entry first, so a ride booked again stops at the
+// unique index before the total is counted twice, and then adds it to the
+// total of its day. If the total cannot be updated the entry is removed
+// again, which also covers servers without transactions.
+func (r *MongoEarningsRepository) record(ctx context.Context, entry *models.EarningsEntry) (*models.DailyEarnings, error) {
+	if _, err := r.entries.For(ctx).InsertOne(ctx, entry); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrEarningsEntryExists
+		}
+		return nil, fmt.Errorf("failed to record earnings entry: %w", err)
+	}
+
+	inc := bson.M{"net": entry.Amount}
+	switch entry.Kind {
+	case models.EarningsRide:
+		inc["gross"] = entry.Amount
+		inc["rides"] = 1
+	case models.EarningsCommission:
+		inc["commission"] = -entry.Amount
+	case models.EarningsBonus:
+		inc["bonuses"] = entry.Amount
+	}
+	update := bson.M{
+		"$inc":         inc,
+		"$max":         bson.M{"updated_at": entry.CreatedAt},
+		"$setOnInsert": bson.M{"driver_id": entry.DriverID, "day": entry.Day},
+	}
+
+	var total models.DailyEarnings
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if err := r.daily.For(ctx).FindOneAndUpdate(ctx, dailyEarningsID(entry.DriverID, entry.Day), update, opts).Decode(&total); err != nil {
+		if _, deleteErr := r.entries.For(ctx).DeleteOne(ctx, bson.M{"_id": entry.ID}); deleteErr != nil {
+			logging.FromContext(ctx).Error("Failed to remove earnings entry", "entry_id", entry.ID.Hex(), "driver_id", entry.DriverID, "error", deleteErr)
+		}
+		return nil, fmt.Errorf("failed to update daily earnings: %w", err)
+	}
+
+	return &total, nil
+}
+
+func (r *MongoEarningsRepository) FindDay(ctx context.Context, driverID, day string, limit int) (*models.DailyEarnings, []models.EarningsEntry, error) {
+	total := models.DailyEarnings{DriverID: driverID, Day: day}
+	if err := r.daily.For(ctx).FindOne(ctx, dailyEarningsID(driverID, day)).Decode(&total); err != nil && err != mongo.ErrNoDocuments {
+		return nil, nil, fmt.Errorf("failed to find daily earnings: %w", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.entries.For(ctx).Find(ctx, bson.M{"driver_id": driverID, "day": day}, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find earnings entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.EarningsEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode earnings entries: %w", err)
+	}
+
+	return &total, entries, nil
+}
+
+// dailyEarningsID filters the total of a driver's day, which is keyed by
+// both.
+func dailyEarningsID(driverID, day string) bson.M {
+	return bson.M{"_id": driverID + ":" + day}
+}
+
+func (r *MongoEarningsRepository) CollectionName() string {
+	return "driver_earnings"
+}
+
+// ArchiveByDriver archives the driver's daily totals with their entries,
+// and counts the entries.
+func (r *MongoEarningsRepository) ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error) {
+	filter := bson.M{"driver_id": driverID.Hex()}
+	if _, err := archiveMany(ctx, r.daily.For(ctx), r.dailyArchive.For(ctx), filter, archivedAt); err != nil {
+		return 0, err
+	}
+	return archiveMany(ctx, r.entries.For(ctx), r.entriesArchive.For(ctx), filter, archivedAt)
+}
+
+func (r *MongoEarningsRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			// One entry of each kind per trip, even when a ride is booked
+			// again
+			Collection: "driver_earnings",
+			Model: mongo.IndexModel{
+				Keys: bson.D{{Key: "trip_id", Value: 1}, {Key: "kind", Value: 1}},
+				Options: options.Index().
+					SetName("driver_earnings_trip_id_kind_unique").
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"trip_id": bson.M{"$type": "string"}}),
+			},
+		},
+		{
+			Collection: "driver_earnings",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "driver_id", Value: 1}, {Key: "day", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("driver_earnings_driver_id_day_created_at"),
+			},
+		},
+	}
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type EmailRepository interface {
+	Create(ctx context.Context, email *models.Email) error
+	FindByID(ctx context.Context, id string) (*models.Email, error)
+	Find(ctx context.Context, status string, limit int) ([]models.Email, error)
+	// ClaimDue marks the oldest due email as sending until leaseUntil and
+	// counts the attempt. Queued emails are due at their next attempt time;
+	// sending ones whose lease has lapsed are due again, as their worker is
+	// presumed gone. It fails with ErrEmailNotFound if nothing is due.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*models.Email, error)
+	// RecordAttempt stores the outcome of a claimed email's attempt.
+	RecordAttempt(ctx context.Context, email *models.Email) error
+}
+
+type MongoEmailRepository struct {
+	collection config.ScopedCollection
+	archive    config.ScopedCollection
+}
+
+func NewMongoEmailRepository(db *config.MongoDB) *MongoEmailRepository {
+	return &MongoEmailRepository{
+		collection: db.ScopedCollection("emails"),
+		archive:    db.ScopedCollection("emails_archive"),
+	}
+}
+
+func (r *MongoEmailRepository) Create(ctx context.Context, email *models.Email) error {
+	if email == nil {
+		return errors.New("email cannot be nil")
+	}
+
+	if email.ID.IsZero() {
+		email.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, email); err != nil {
+		return fmt.Errorf("failed to create email: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoEmailRepository) FindByID(ctx context.Context, id string) (*models.Email, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email ID format: %w", err)
+	}
+
+	var email models.Email
+	if err := r.collection.For(ctx).FindOne(ctx, bson.M{"_id": objectID}).Decode(&email); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrEmailNotFound
+		}
+		return nil, fmt.Errorf("failed to find email: %w", err)
+	}
+
+	return &email, nil
+}
+
+func (r *MongoEmailRepository) Find(ctx context.Context, status string, limit int) ([]models.Email, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	findOptions := options.Find().SetSort(bson.M{"created_at": -1})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.collection.For(ctx).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find emails: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	emails := []models.Email{}
+	if err := cursor.All(ctx, &emails); err != nil {
+		return nil, fmt.Errorf("failed to decode emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+func (r *MongoEmailRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*models.Email, error) {
+	filter := bson.M{
+		"status":          bson.M{"$in": []string{models.EmailStatusQueued, models.EmailStatusSending}},
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":          models.EmailStatusSending,
+			"next_attempt_at": leaseUntil,
+			"updated_at":      now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+	findOptions := options.FindOneAndUpdate().
+		SetSort(bson.M{"next_attempt_at": 1}).
+		SetReturnDocument(options.After)
+
+	var email models.Email
+	if err := r.collection.For(ctx).FindOneAndUpdate(ctx, filter, update, findOptions).Decode(&email); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrEmailNotFound
+		}
+		return nil, fmt.Errorf("failed to claim email: %w", err)
+	}
+
+	return &email, nil
+}
+
+func (r *MongoEmailRepository) RecordAttempt(ctx context.Context, email *models.Email) error {
+	_, err := r.collection.For(ctx).UpdateOne(ctx,
+		bson.M{"_id": email.ID, "status": models.EmailStatusSending},
+		bson.M{"$set": bson.M{
+			"status":          email.Status,
+			"last_error":      email.LastError,
+			"provider":        email.Provider,
+			"next_attempt_at": email.NextAttemptAt,
+			"sent_at":         email.SentAt,
+			"updated_at":      email.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update email: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoEmailRepository) CollectionName() string {
+	return "emails"
+}
+
+func (r *MongoEmailRepository) ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error) {
+	return archiveMany(ctx, r.collection.For(ctx), r.archive.For(ctx), bson.M{"driver_id": driverID}, archivedAt)
+}
+
+func (r *MongoEmailRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "emails",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+				Options: options.Index().SetName("emails_status_next_attempt_at"),
+			},
+		},
+		{
+			Collection: "emails",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("emails_created_at"),
+			},
+		},
+		{
+			Collection: "emails",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "driver_id", Value: 1}},
+				Options: options.Index().SetName("emails_driver_id").SetSparse(true),
+			},
+		},
+	}
+}
@@ -1,12 +1,87 @@
 package repository
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 var (
 	ErrDriverNotFound      = errors.New("driver not found")
 	ErrDriverAlreadyExists = errors.New("driver already exists")
+	ErrDriverReviewChanged = errors.New("driver review status changed meanwhile")
 	ErrInvalidID           = errors.New("invalid driver ID")
 	ErrInvalidCoordinates  = errors.New("invalid coordinates")
 	ErrInvalidRadius       = errors.New("invalid radius")
 	ErrDatabaseError       = errors.New("database error")
+	ErrFeatureFlagNotFound = errors.New("feature flag not found")
+
+	ErrChangeRequestNotFound = errors.New("change request not found")
+	ErrChangeRequestConflict = errors.New("change request conflicts with another pending or reviewed request")
+
+	ErrLicenseNotFound = errors.New("license document not found")
+
+	ErrAnomalyNotFound = errors.New("anomaly not found")
+	ErrAnomalyConflict = errors.New("anomaly is no longer open")
+
+	ErrDeviceTokenNotFound = errors.New("device token not found")
+
+	ErrPhotoNotFound = errors.New("photo not found")
+	ErrPhotoConflict = errors.New("photo has already been reviewed")
+
+	ErrDispatchPauseNotFound = errors.New("dispatch pause not found")
+
+	ErrPlateReserved            = errors.New("plate is reserved")
+	ErrPlateReservationNotFound = errors.New("plate reservation not found")
+
+	ErrCredentialNotFound = errors.New("credential not found")
+
+	ErrFleetAccountNotFound = errors.New("fleet account not found")
+	ErrFleetAccountExists   = errors.New("fleet account username is taken")
+
+	ErrEmailNotFound = errors.New("email not found")
+
+	ErrDriverImportNotFound = errors.New("driver import not found")
+	ErrDriverImportConflict = errors.New("driver import is no longer staged")
+
+	ErrCustomFieldNotFound = errors.New("custom field not found")
+	ErrCustomFieldExists   = errors.New("custom field key is taken")
+
+	ErrTripNotFound   = errors.New("trip not found")
+	ErrTripConflict   = errors.New("trip changed meanwhile")
+	ErrTripDriverBusy = errors.New("driver already has an active trip")
+	ErrTripRated      = errors.New("trip has already been rated")
+
+	ErrRatingPromptNotFound         = errors.New("rating prompt not found")
+	ErrRatingPromptExists           = errors.New("trip already has a rating prompt")
+	ErrRatingPromptSettingsNotFound = errors.New("rating prompt settings not found")
+
+	ErrDemoScenarioNotFound = errors.New("no demo scenario has been seeded")
+
+	ErrChurnAlertExists = errors.New("churn alert already raised this week")
+
+	ErrRiderPreferencesNotFound = errors.New("rider preferences not found")
+
+	ErrEarningsEntryExists = errors.New("earnings entry already recorded")
 )
+
+// dbError wraps a failed MongoDB call in ErrDatabaseError, keeping the
+// driver's error in the chain for callers that check it.
+func dbError(action string, err error) error {
+	return fmt.Errorf("%w: failed to %s: %w", ErrDatabaseError, action, err)
+}
+
+// parseDriverID parses a driver's hex ObjectID, wrapping failures in
+// ErrInvalidID.
+func parseDriverID(id string) (primitive.ObjectID, error) {
+	if id == "" {
+		return primitive.NilObjectID, fmt.Errorf("%w: driver ID cannot be empty", ErrInvalidID)
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	return objectID, nil
+}
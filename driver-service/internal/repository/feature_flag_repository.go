@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FeatureFlagRepository interface {
+	FindAll(ctx context.Context) ([]models.FeatureFlag, error)
+	FindByKey(ctx context.Context, key string) (*models.FeatureFlag, error)
+	Upsert(ctx context.Context, flag *models.FeatureFlag) error
+	Delete(ctx context.Context, key string) error
+}
+
+type MongoFeatureFlagRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoFeatureFlagRepository(db *config.MongoDB) *MongoFeatureFlagRepository {
+	return &MongoFeatureFlagRepository{
+		collection: db.GetCollection("feature_flags"),
+	}
+}
+
+func (r *MongoFeatureFlagRepository) FindAll(ctx context.Context) ([]models.FeatureFlag, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find feature flags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	flags := []models.FeatureFlag{}
+	if err := cursor.All(ctx, &flags); err != nil {
+		return nil, fmt.Errorf("failed to decode feature flags: %w", err)
+	}
+
+	return flags, nil
+}
+
+func (r *MongoFeatureFlagRepository) FindByKey(ctx context.Context, key string) (*models.FeatureFlag, error) {
+	var flag models.FeatureFlag
+	if err := r.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&flag); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrFeatureFlagNotFound
+		}
+		return nil, fmt.Errorf("failed to find feature flag: %w", err)
+	}
+
+	return &flag, nil
+}
+
+func (r *MongoFeatureFlagRepository) Upsert(ctx context.Context, flag *models.FeatureFlag) error {
+	if flag == nil {
+		return errors.New("feature flag cannot be nil")
+	}
+
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": flag.Key}, flag, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save feature flag: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoFeatureFlagRepository) Delete(ctx context.Context, key string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": key})
+	if err != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrFeatureFlagNotFound
+	}
+
+	return nil
+}
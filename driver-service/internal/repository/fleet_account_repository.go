@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FleetAccountRepository interface {
+	Create(ctx context.Context, account *models.FleetAccount) error
+	Update(ctx context.Context, account *models.FleetAccount) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.FleetAccount, error)
+	FindByUsername(ctx context.Context, username string) (*models.FleetAccount, error)
+	FindByFleet(ctx context.Context, fleetID string) ([]models.FleetAccount, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type MongoFleetAccountRepository struct {
+	collection config.ScopedCollection
+}
+
+func NewMongoFleetAccountRepository(db *config.MongoDB) *MongoFleetAccountRepository {
+	return &MongoFleetAccountRepository{
+		collection: db.ScopedCollection("fleet_accounts"),
+	}
+}
+
+func (r *MongoFleetAccountRepository) Create(ctx context.Context, account *models.FleetAccount) error {
+	if account == nil {
+		return errors.New("fleet account cannot be nil")
+	}
+
+	if account.ID.IsZero() {
+		account.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, account); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrFleetAccountExists
+		}
+		return fmt.Errorf("failed to create fleet account: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoFleetAccountRepository) Update(ctx context.Context, account *models.FleetAccount) error {
+	if account == nil {
+		return errors.New("fleet account cannot be nil")
+	}
+
+	result, err := r.collection.For(ctx).ReplaceOne(ctx, bson.M{"_id": account.ID}, account)
+	if err != nil {
+		return fmt.Errorf("failed to update fleet account: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrFleetAccountNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoFleetAccountRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.FleetAccount, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *MongoFleetAccountRepository) FindByUsername(ctx context.Context, username string) (*models.FleetAccount, error) {
+	return r.findOne(ctx, bson.M{"username": username})
+}
+
+func (r *MongoFleetAccountRepository) findOne(ctx context.Context, filter bson.M) (*models.FleetAccount, error) {
+	var account models.FleetAccount
+	if err := r.collection.For(ctx).FindOne(ctx, filter).Decode(&account); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrFleetAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to find fleet account: %w", err)
+	}
+
+	return &account, nil
+}
+
+func (r *MongoFleetAccountRepository) FindByFleet(ctx context.Context, fleetID string) ([]models.FleetAccount, error) {
+	cursor, err := r.collection.For(ctx).Find(ctx, bson.M{"fleet_id": fleetID}, options.Find().SetSort(bson.M{"username": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find fleet accounts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	accounts := []models.FleetAccount{}
+	if err := cursor.All(ctx, &accounts); err != nil {
+		return nil, fmt.Errorf("failed to decode fleet accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+func (r *MongoFleetAccountRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.For(ctx).DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete fleet account: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrFleetAccountNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoFleetAccountRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "fleet_accounts",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "username", Value: 1}},
+				Options: options.Index().SetName("fleet_accounts_username_unique").SetUnique(true),
+			},
+		},
+		{
+			Collection: "fleet_accounts",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "fleet_id", Value: 1}, {Key: "username", Value: 1}},
+				Options: options.Index().SetName("fleet_accounts_fleet_id_username"),
+			},
+		},
+	}
+}
@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoNamespaceExists is returned when creating a collection that already
+// exists.
+const mongoNamespaceExists = 48
+
+// RequiredIndex is an index a repository's queries depend on. Model must
+// carry an explicit name so it can be reported on.
+type RequiredIndex struct {
+	Collection string
+	Model      mongo.IndexModel
+}
+
+func (i RequiredIndex) Name() string {
+	if i.Model.Options == nil || i.Model.Options.Name == nil {
+		return ""
+	}
+	return *i.Model.Options.Name
+}
+
+// IndexedRepository is implemented by repositories that declare the indexes
+// they need.
+type IndexedRepository interface {
+	RequiredIndexes() []RequiredIndex
+}
+
+// RequiredCollection is a collection that has to be created with options,
+// such as a time-series collection, before a first write or index creates it
+// as a plain one.
+type RequiredCollection struct {
+	Name    string
+	Options *options.CreateCollectionOptions
+}
+
+// CollectionProvider is implemented by indexed repositories whose
+// collections need creation options.
+type CollectionProvider interface {
+	RequiredCollections() []RequiredCollection
+}
+
+const (
+	IndexStateMissing  = "missing"
+	IndexStateBuilding = "building"
+	IndexStateReady    = "ready"
+	IndexStateFailed   = "failed"
+)
+
+type IndexStatus struct {
+	Collection string `json:"collection"`
+	Name       string `json:"name"`
+	State      string `json:"state"`
+	Error      string `json:"error,omitempty"`
+}
+
+// IndexManager verifies that required indexes exist and have finished
+// building, creating missing ones. Ready reports false until every index is
+// confirmed, so a fresh replica is kept out of rotation instead of serving
+// queries with collection scans.
+type IndexManager struct {
+	database    *mongo.Database
+	collections []RequiredCollection
+	required    []RequiredIndex
+
+	mu       sync.RWMutex
+	statuses []IndexStatus
+	ready    bool
+}
+
+func NewIndexManager(db *config.MongoDB, repositories ...IndexedRepository) *IndexManager {
+	var collections []RequiredCollection
+	var required []RequiredIndex
+	for _, repo := range repositories {
+		if provider, ok := repo.(CollectionProvider); ok {
+			collections = append(collections, provider.RequiredCollections()...)
+		}
+		required = append(required, repo.RequiredIndexes()...)
+	}
+
+	statuses := make([]IndexStatus, len(required))
+	for i, index := range required {
+		statuses[i] = IndexStatus{Collection: index.Collection, Name: index.Name(), State: IndexStateMissing}
+	}
+
+	return &IndexManager{
+		database:    db.Database,
+		collections: collections,
+		required:    required,
+		statuses:    statuses,
+	}
+}
+
+func (m *IndexManager) Ready() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ready
+}
+
+func (m *IndexManager) Statuses() []IndexStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]IndexStatus(nil), m.statuses...)
+}
+
+// Run checks the indexes every interval until all of them are ready or ctx is
+// cancelled.
+func (m *IndexManager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ready, err := m.Ensure(ctx)
+		if err != nil {
+			logging.FromContext(ctx).Error("Index check failed", "error", err)
+		}
+		if ready {
+			logging.FromContext(ctx).Info("All required indexes are ready", "count", len(m.required))
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type existingIndex struct {
+	name  string
+	keys  bson.Raw
+	state string
+}
+
+// Ensure performs one pass: required collections are created first, then
+// indexes that are missing are created, and indexes still being built (for
+// example by another replica) are left to finish. An existing index with the
+// same keys counts even if it was created under a different name.
+func (m *IndexManager) Ensure(ctx context.Context) (bool, error) {
+	statuses := make([]IndexStatus, len(m.required))
+	listed := make(map[string][]existingIndex)
+	var firstErr error
+
+	// Creating an index would create a missing collection without its
+	// options, so indexes wait until their collection exists.
+	uncreated := make(map[string]string)
+	for _, collection := range m.collections {
+		if err := m.database.CreateCollection(ctx, collection.Name, collection.Options); err != nil {
+			var cmdErr mongo.CommandError
+			if errors.As(err, &cmdErr) && cmdErr.Code == mongoNamespaceExists {
+				continue
+			}
+			uncreated[collection.Name] = "failed to create collection: " + err.Error()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to create collection %s: %w", collection.Name, err)
+			}
+		}
+	}
+
+	for i, index := range m.required {
+		status := IndexStatus{Collection: index.Collection, Name: index.Name()}
+
+		if reason, ok := uncreated[index.Collection]; ok {
+			status.State = IndexStateFailed
+			status.Error = reason
+			statuses[i] = status
+			continue
+		}
+
+		existing, ok := listed[index.Collection]
+		if !ok {
+			var err error
+			if existing, err = m.listIndexes(ctx, index.Collection); err != nil {
+				existing = nil
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+			listed[index.Collection] = existing
+		}
+
+		if existing == nil {
+			status.State = IndexStateFailed
+			status.Error = "failed to list indexes"
+		} else if match := findIndex(existing, index); match != nil {
+			status.State = match.state
+		} else {
+			logging.FromContext(ctx).Info("Creating missing index", "index", status.Name, "collection", index.Collection)
+			if _, err := m.database.Collection(index.Collection).Indexes().CreateOne(ctx, index.Model); err != nil {
+				status.State = IndexStateFailed
+				status.Error = err.Error()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to create index %s on %s: %w", status.Name, index.Collection, err)
+				}
+			} else {
+				status.State = IndexStateReady
+			}
+		}
+
+		statuses[i] = status
+	}
+
+	ready := true
+	for _, status := range statuses {
+		if status.State != IndexStateReady {
+			ready = false
+		}
+	}
+
+	m.mu.Lock()
+	m.statuses = statuses
+	m.ready = ready
+	m.mu.Unlock()
+
+	return ready, firstErr
+}
+
+func findIndex(existing []existingIndex, index RequiredIndex) *existingIndex {
+	keys, err := bson.Marshal(index.Model.Keys)
+	if err != nil {
+		keys = nil
+	}
+
+	for i := range existing {
+		if existing[i].name == index.Name() || (keys != nil && bytes.Equal(existing[i].keys, keys)) {
+			return &existing[i]
+		}
+	}
+	return nil
+}
+
+// listIndexes returns the indexes on the collection. Build progress is only
+// reported by servers supporting includeIndexBuildInfo (MongoDB 7.1+); older
+// servers fall back to listing the index specifications.
+func (m *IndexManager) listIndexes(ctx context.Context, collection string) ([]existingIndex, error) {
+	var result struct {
+		Cursor struct {
+			FirstBatch []bson.Raw `bson:"firstBatch"`
+		} `bson:"cursor"`
+	}
+
+	err := m.database.RunCommand(ctx, bson.D{
+		{Key: "listIndexes", Value: collection},
+		{Key: "includeIndexBuildInfo", Value: true},
+	}).Decode(&result)
+	if err != nil {
+		return m.listIndexSpecifications(ctx, collection)
+	}
+
+	indexes := make([]existingIndex, 0, len(result.Cursor.FirstBatch))
+	for _, raw := range result.Cursor.FirstBatch {
+		spec := raw
+		if nested, ok := raw.Lookup("spec").DocumentOK(); ok {
+			spec = nested
+		}
+
+		index := existingIndex{state: IndexStateReady}
+		index.name, _ = spec.Lookup("name").StringValueOK()
+		index.keys, _ = spec.Lookup("key").DocumentOK()
+		if _, err := raw.LookupErr("indexBuildInfo"); err == nil {
+			index.state = IndexStateBuilding
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+func (m *IndexManager) listIndexSpecifications(ctx context.Context, collection string) ([]existingIndex, error) {
+	specs, err := m.database.Collection(collection).Indexes().ListSpecifications(ctx)
+	if err != nil {
+		var cmdErr mongo.CommandError
+		// NamespaceNotFound: the collection does not exist yet.
+		if errors.As(err, &cmdErr) && cmdErr.Code == 26 {
+			return []existingIndex{}, nil
+		}
+		return nil, fmt.Errorf("failed to list indexes on %s: %w", collection, err)
+	}
+
+	indexes := make([]existingIndex, len(specs))
+	for i, spec := range specs {
+		indexes[i] = existingIndex{name: spec.Name, keys: spec.KeysDocument, state: IndexStateReady}
+	}
+	return indexes, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/metrics"
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+const driversRepositoryName = "drivers"
+
+// InstrumentedDriverRepository times every call to the wrapped repository
+// and counts the drivers nearby searches return. Wrapped under the
+// coalescing repository, it sees the queries that really reach the store,
+// MongoDB or the Redis GEO set in front of it.
+type InstrumentedDriverRepository struct {
+	repo    DriverRepository
+	metrics *metrics.Repository
+}
+
+func NewInstrumentedDriverRepository(repo DriverRepository, m *metrics.Repository) *InstrumentedDriverRepository {
+	return &InstrumentedDriverRepository{
+		repo:    repo,
+		metrics: m,
+	}
+}
+
+func (r *InstrumentedDriverRepository) Create(ctx context.Context, driver *models.Driver) (string, error) {
+	start := time.Now()
+	id, err := r.repo.Create(ctx, driver)
+	r.observe("create", start, err)
+	return id, err
+}
+
+func (r *InstrumentedDriverRepository) Update(ctx context.Context, id string, driver *models.Driver, fields ...models.DriverField) error {
+	start := time.Now()
+	err := r.repo.Update(ctx, id, driver, fields...)
+	r.observe("update", start, err)
+	return err
+}
+
+func (r *InstrumentedDriverRepository) SetStatus(ctx context.Context, id string, status string, at time.Time) error {
+	start := time.Now()
+	err := r.repo.SetStatus(ctx, id, status, at)
+	r.observe("set_status", start, err)
+	return err
+}
+
+func (r *InstrumentedDriverRepository) SetReviewStatus(ctx context.Context, id string, from, to, note string, at time.Time) error {
+	start := time.Now()
+	err := r.repo.SetReviewStatus(ctx, id, from, to, note, at)
+	r.observe("set_review_status", start, err)
+	return err
+}
+
+func (r *InstrumentedDriverRepository) FindByID(ctx context.Context, id string) (*models.Driver, error) {
+	start := time.Now()
+	driver, err := r.repo.FindByID(ctx, id)
+	r.observe("find_by_id", start, err)
+	return driver, err
+}
+
+func (r *InstrumentedDriverRepository) FindAll(ctx context.Context, page, pageSize int, filter models.DriverListFilter) ([]models.Driver, int64, error) {
+	start := time.Now()
+	drivers, total, err := r.repo.FindAll(ctx, page, pageSize, filter)
+	r.observe("find_all", start, err)
+	return drivers, total, err
+}
+
+func (r *InstrumentedDriverRepository) Search(ctx context.Context, terms string, page, pageSize int) ([]models.Driver, int64, error) {
+	start := time.Now()
+	drivers, total, err := r.repo.Search(ctx, terms, page, pageSize)
+	r.observe("search", start, err)
+	return drivers, total, err
+}
+
+func (r *InstrumentedDriverRepository) FindNearby(ctx context.Context, lat, lon, radiusKm float64, filter models.NearbyFilter) ([]models.DriverWithDistance, error) {
+	start := time.Now()
+	drivers, err := r.repo.FindNearby(ctx, lat, lon, radiusKm, filter)
+	r.observe("find_nearby", start, err)
+	if err == nil {
+		r.metrics.ObserveNearbyResults(len(drivers))
+	}
+	return drivers, err
+}
+
+func (r *InstrumentedDriverRepository) FindByPlate(ctx context.Context, plate string) (*models.Driver, error) {
+	start := time.Now()
+	driver, err := r.repo.FindByPlate(ctx, plate)
+	r.observe("find_by_plate", start, err)
+	return driver, err
+}
+
+func (r *InstrumentedDriverRepository) Delete(ctx context.Context, id string) error {
+	start := time.Now()
+	err := r.repo.Delete(ctx, id)
+	r.observe("delete", start, err)
+	return err
+}
+
+func (r *InstrumentedDriverRepository) observe(operation string, start time.Time, err error) {
+	r.metrics.ObserveOperation(driversRepositoryName, operation, time.Since(start), err)
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LicenseOverrideRepository is the audit trail of license class overrides.
+// Records are only ever added.
+type LicenseOverrideRepository interface {
+	Create(ctx context.Context, record *models.LicenseOverrideRecord) error
+	FindByDriver(ctx context.Context, driverID string) ([]models.LicenseOverrideRecord, error)
+}
+
+type MongoLicenseOverrideRepository struct {
+	collection config.ScopedCollection
+	archive    config.ScopedCollection
+}
+
+func NewMongoLicenseOverrideRepository(db *config.MongoDB) *MongoLicenseOverrideRepository {
+	return &MongoLicenseOverrideRepository{
+		collection: db.ScopedCollection("license_class_overrides"),
+		archive:    db.ScopedCollection("license_class_overrides_archive"),
+	}
+}
+
+func (r *MongoLicenseOverrideRepository) Create(ctx context.Context, record *models.LicenseOverrideRecord) error {
+	if record == nil {
+		return errors.New("license override record cannot be nil")
+	}
+
+	if record.ID.IsZero() {
+		record.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, record); err != nil {
+		return fmt.Errorf("failed to create license override record: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoLicenseOverrideRepository) FindByDriver(ctx context.Context, driverID string) ([]models.LicenseOverrideRecord, error) {
+	objectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver ID format: %w", err)
+	}
+
+	cursor, err := r.collection.For(ctx).Find(ctx,
+		bson.M{"driver_id": objectID},
+		options.Find().SetSort(bson.M{"created_at": -1}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find license override records: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	records := []models.LicenseOverrideRecord{}
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode license override records: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *MongoLicenseOverrideRepository) CollectionName() string {
+	return "license_class_overrides"
+}
+
+func (r *MongoLicenseOverrideRepository) ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error) {
+	return archiveMany(ctx, r.collection.For(ctx), r.archive.For(ctx), bson.M{"driver_id": driverID}, archivedAt)
+}
+
+func (r *MongoLicenseOverrideRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "license_class_overrides",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "driver_id", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("license_class_overrides_driver_created_at"),
+			},
+		},
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LicenseRepository interface {
+	// Save stores the driver's license document, replacing any earlier one.
+	Save(ctx context.Context, document *models.LicenseDocument) error
+	FindByDriver(ctx context.Context, driverID string) (*models.LicenseDocument, error)
+}
+
+type MongoLicenseRepository struct {
+	collection config.ScopedCollection
+	archive    config.ScopedCollection
+}
+
+func NewMongoLicenseRepository(db *config.MongoDB) *MongoLicenseRepository {
+	return &MongoLicenseRepository{
+		collection: db.ScopedCollection("license_documents"),
+		archive:    db.ScopedCollection("license_documents_archive"),
+	}
+}
+
+func (r *MongoLicenseRepository) Save(ctx context.Context, document *models.LicenseDocument) error {
+	if document == nil {
+		return errors.New("license document cannot be nil")
+	}
+
+	if document.ID.IsZero() {
+		document.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.For(ctx).ReplaceOne(ctx,
+		bson.M{"driver_id": document.DriverID},
+		document,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save license document: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoLicenseRepository) FindByDriver(ctx context.Context, driverID string) (*models.LicenseDocument, error) {
+	objectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver ID format: %w", err)
+	}
+
+	var document models.LicenseDocument
+	if err := r.collection.For(ctx).FindOne(ctx, bson.M{"driver_id": objectID}).Decode(&document); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrLicenseNotFound
+		}
+		return nil, fmt.Errorf("failed to find license document: %w", err)
+	}
+
+	return &document, nil
+}
+
+func (r *MongoLicenseRepository) CollectionName() string {
+	return "license_documents"
+}
+
+func (r *MongoLicenseRepository) ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error) {
+	return archiveMany(ctx, r.collection.For(ctx), r.archive.For(ctx), bson.M{"driver_id": driverID}, archivedAt)
+}
+
+func (r *MongoLicenseRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "license_documents",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "driver_id", Value: 1}},
+				Options: options.Index().SetName("license_documents_driver_unique").SetUnique(true),
+			},
+		},
+	}
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/geohash"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LocationHistoryRepository interface {
+	Insert(ctx context.Context, entry *models.LocationHistoryEntry) error
+	// InsertMany stores the entries and returns how many were new. Entries
+	// the driver already sent, matched by driver and recorded_at, are
+	// skipped so a retried upload is harmless.
+	InsertMany(ctx context.Context, entries []models.LocationHistoryEntry) (int, error)
+	// FindByDriver returns the driver's points recorded in [from, to), oldest
+	// first, up to limit.
+	FindByDriver(ctx context.Context, driverID string, from, to time.Time, limit int) ([]models.LocationHistoryEntry, error)
+	// CountDriversByHour counts, for each hour in [from, to), the distinct
+	// drivers with a point inside box. Hours without any are left out.
+	CountDriversByHour(ctx context.Context, box geohash.Box, from, to time.Time) ([]models.HourlyDriverCount, error)
+	// LastSeenByDriver returns, for each driver with a point recorded in
+	// [from, to), when they recorded their last one.
+	LastSeenByDriver(ctx context.Context, from, to time.Time) ([]models.DriverActivity, error)
+}
+
+// MongoLocationHistoryRepository keeps the trace in the driver_locations
+// time-series collection (MongoDB 5.0+), bucketed by driver. Points expire
+// after the configured TTL.
+type MongoLocationHistoryRepository struct {
+	collection config.ScopedCollection
+	archive    config.ScopedCollection
+	ttl        time.Duration
+}
+
+func NewMongoLocationHistoryRepository(db *config.MongoDB, ttl time.Duration) *MongoLocationHistoryRepository {
+	return &MongoLocationHistoryRepository{
+		collection: db.ScopedCollection("driver_locations"),
+		archive:    db.ScopedCollection("driver_locations_archive"),
+		ttl:        ttl,
+	}
+}
+
+func (r *MongoLocationHistoryRepository) Insert(ctx context.Context, entry *models.LocationHistoryEntry) error {
+	if entry == nil {
+		return errors.New("location history entry cannot be nil")
+	}
+
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, entry); err != nil {
+		return fmt.Errorf("failed to store location: %w", err)
+	}
+
+	return nil
+}
+
+// InsertMany deduplicates by querying first: time-series collections do not
+// support unique indexes.
+func (r *MongoLocationHistoryRepository) InsertMany(ctx context.Context, entries []models.LocationHistoryEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	seen, err := r.recordedTimes(ctx, entries)
+	if err != nil {
+		return 0, err
+	}
+
+	var documents []interface{}
+	for i := range entries {
+		recordedAt := entries[i].RecordedAt.UnixMilli()
+		if seen[entries[i].DriverID][recordedAt] {
+			continue
+		}
+		if seen[entries[i].DriverID] == nil {
+			seen[entries[i].DriverID] = make(map[int64]bool)
+		}
+		seen[entries[i].DriverID][recordedAt] = true
+
+		if entries[i].ID.IsZero() {
+			entries[i].ID = primitive.NewObjectID()
+		}
+		documents = append(documents, entries[i])
+	}
+	if len(documents) == 0 {
+		return 0, nil
+	}
+
+	if _, err := r.collection.For(ctx).InsertMany(ctx, documents); err != nil {
+		return 0, fmt.Errorf("failed to store location history: %w", err)
+	}
+
+	return len(documents), nil
+}
+
+// recordedTimes returns the recorded_at values, in milliseconds (the
+// precision MongoDB keeps), already stored for the entries' drivers within
+// the entries' time span.
+func (r *MongoLocationHistoryRepository) recordedTimes(ctx context.Context, entries []models.LocationHistoryEntry) (map[primitive.ObjectID]map[int64]bool, error) {
+	var driverIDs []primitive.ObjectID
+	seen := make(map[primitive.ObjectID]map[int64]bool)
+	from, to := entries[0].RecordedAt, entries[0].RecordedAt
+	for _, entry := range entries {
+		if _, ok := seen[entry.DriverID]; !ok {
+			seen[entry.DriverID] = make(map[int64]bool)
+			driverIDs = append(driverIDs, entry.DriverID)
+		}
+		if entry.RecordedAt.Before(from) {
+			from = entry.RecordedAt
+		}
+		if entry.RecordedAt.After(to) {
+			to = entry.RecordedAt
+		}
+	}
+
+	filter := bson.M{
+		"driver_id":   bson.M{"$in": driverIDs},
+		"recorded_at": bson.M{"$gte": from, "$lte": to},
+	}
+	opts := options.Find().SetProjection(bson.M{"driver_id": 1, "recorded_at": 1})
+	cursor, err := r.collection.For(ctx).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check location history: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var existing models.LocationHistoryEntry
+		if err := cursor.Decode(&existing); err != nil {
+			return nil, fmt.Errorf("failed to decode location history: %w", err)
+		}
+		seen[existing.DriverID][existing.RecordedAt.UnixMilli()] = true
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to check location history: %w", err)
+	}
+
+	return seen, nil
+}
+
+func (r *MongoLocationHistoryRepository) FindByDriver(ctx context.Context, driverID string, from, to time.Time, limit int) ([]models.LocationHistoryEntry, error) {
+	objectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver ID format: %w", err)
+	}
+
+	filter := bson.M{
+		"driver_id":   objectID,
+		"recorded_at": bson.M{"$gte": from, "$lt": to},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "recorded_at", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := r.collection.For(ctx).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find location history: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.LocationHistoryEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode location history: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (r *MongoLocationHistoryRepository) CountDriversByHour(ctx context.Context, box geohash.Box, from, to time.Time) ([]models.HourlyDriverCount, error) {
+	ring := bson.A{
+		bson.A{box.MinLon, box.MinLat},
+		bson.A{box.MaxLon, box.MinLat},
+		bson.A{box.MaxLon, box.MaxLat},
+		bson.A{box.MinLon, box.MaxLat},
+		bson.A{box.MinLon, box.MinLat},
+	}
+
+	// Each driver counts once per hour however many points they sent
+	pipeline, err := NewPipeline().
+		Match(bson.M{
+			"recorded_at": bson.M{"$gte": from, "$lt": to},
+			"location": bson.M{"$geoWithin": bson.M{
+				"$geometry": bson.M{"type": "Polygon", "coordinates": bson.A{ring}},
+			}},
+		}).
+		Group(bson.M{
+			"hour":   bson.M{"$dateTrunc": bson.M{"date": "$recorded_at", "unit": "hour"}},
+			"driver": "$driver_id",
+		}, nil).
+		Group("$_id.hour", bson.M{"drivers": bson.M{"$sum": 1}}).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.For(ctx).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count drivers by hour: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	counts := []models.HourlyDriverCount{}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, fmt.Errorf("failed to decode hourly driver counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *MongoLocationHistoryRepository) LastSeenByDriver(ctx context.Context, from, to time.Time) ([]models.DriverActivity, error) {
+	pipeline, err := NewPipeline().
+		Match(bson.M{"recorded_at": bson.M{"$gte": from, "$lt": to}}).
+		Group("$driver_id", bson.M{"last_seen": bson.M{"$max": "$recorded_at"}}).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.For(ctx).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active drivers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	activity := []models.DriverActivity{}
+	if err := cursor.All(ctx, &activity); err != nil {
+		return nil, fmt.Errorf("failed to decode driver activity: %w", err)
+	}
+
+	return activity, nil
+}
+
+func (r *MongoLocationHistoryRepository) CollectionName() string {
+	return "driver_locations"
+}
+
+func (r *MongoLocationHistoryRepository) ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error) {
+	return archiveMany(ctx, r.collection.For(ctx), r.archive.For(ctx), bson.M{"driver_id": driverID}, archivedAt)
+}
+
+// Untransacted reports that driver_locations, as a time-series collection,
+// cannot be written inside a transaction.
+func (r *MongoLocationHistoryRepository) Untransacted() bool {
+	return true
+}
+
+func (r *MongoLocationHistoryRepository) RequiredCollections() []RequiredCollection {
+	timeSeries := options.TimeSeries().
+		SetTimeField("recorded_at").
+		SetMetaField("driver_id").
+		SetGranularity("seconds")
+	opts := options.CreateCollection().SetTimeSeriesOptions(timeSeries)
+	if r.ttl > 0 {
+		opts.SetExpireAfterSeconds(int64(r.ttl.Seconds()))
+	}
+
+	return []RequiredCollection{{Name: "driver_locations", Options: opts}}
+}
+
+func (r *MongoLocationHistoryRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "driver_locations",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "driver_id", Value: 1}, {Key: "recorded_at", Value: 1}},
+				Options: options.Index().SetName("driver_locations_driver_recorded"),
+			},
+		},
+	}
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MaintenanceRepository interface {
+	Create(ctx context.Context, record *models.MaintenanceRecord) (string, error)
+	FindByDriver(ctx context.Context, driverID string) ([]models.MaintenanceRecord, error)
+	FindDriverIDs(ctx context.Context) ([]string, error)
+	MarkReminderSent(ctx context.Context, id primitive.ObjectID, sentAt time.Time) error
+}
+
+type MongoMaintenanceRepository struct {
+	collection config.ScopedCollection
+	archive    config.ScopedCollection
+}
+
+func NewMongoMaintenanceRepository(db *config.MongoDB) *MongoMaintenanceRepository {
+	return &MongoMaintenanceRepository{
+		collection: db.ScopedCollection("maintenance_records"),
+		archive:    db.ScopedCollection("maintenance_records_archive"),
+	}
+}
+
+func (r *MongoMaintenanceRepository) Create(ctx context.Context, record *models.MaintenanceRecord) (string, error) {
+	if record == nil {
+		return "", errors.New("maintenance record cannot be nil")
+	}
+
+	record.CreatedAt = time.Now()
+	if record.ID.IsZero() {
+		record.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, record); err != nil {
+		return "", fmt.Errorf("failed to create maintenance record: %w", err)
+	}
+
+	return record.ID.Hex(), nil
+}
+
+func (r *MongoMaintenanceRepository) FindByDriver(ctx context.Context, driverID string) ([]models.MaintenanceRecord, error) {
+	objectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver ID format: %w", err)
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.M{"performed_at": -1})
+
+	cursor, err := r.collection.For(ctx).Find(ctx, bson.M{"driver_id": objectID}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find maintenance records: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	records := []models.MaintenanceRecord{}
+	if err = cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode maintenance records: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *MongoMaintenanceRepository) FindDriverIDs(ctx context.Context) ([]string, error) {
+	values, err := r.collection.For(ctx).Distinct(ctx, "driver_id", bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintained drivers: %w", err)
+	}
+
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		if oid, ok := value.(primitive.ObjectID); ok {
+			ids = append(ids, oid.Hex())
+		}
+	}
+
+	return ids, nil
+}
+
+func (r *MongoMaintenanceRepository) MarkReminderSent(ctx context.Context, id primitive.ObjectID, sentAt time.Time) error {
+	_, err := r.collection.For(ctx).UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"reminder_sent_at": sentAt}})
+	if err != nil {
+		return fmt.Errorf("failed to mark maintenance reminder: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoMaintenanceRepository) CollectionName() string {
+	return "maintenance_records"
+}
+
+func (r *MongoMaintenanceRepository) ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error) {
+	return archiveMany(ctx, r.collection.For(ctx), r.archive.For(ctx), bson.M{"driver_id": driverID}, archivedAt)
+}
+
+func (r *MongoMaintenanceRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "maintenance_records",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "driver_id", Value: 1}, {Key: "performed_at", Value: -1}},
+				Options: options.Index().SetName("maintenance_records_driver_performed_at"),
+			},
+		},
+	}
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OnboardingRepository interface {
+	// MarkStep records that the driver reached the step at the given time,
+	// unless they reached it earlier.
+	MarkStep(ctx context.Context, driverID, step string, at time.Time) error
+	// CountSteps counts, for each funnel step, the drivers registered in
+	// [from, to) who reached it, for one fleet or, with "", all of them.
+	CountSteps(ctx context.Context, fleetID string, from, to time.Time) ([]models.OnboardingStepCount, error)
+	// FindStalled returns the drivers who reached every step before the
+	// one at index but not that one, and whose last step was before
+	// stalledBefore, longest stalled first. Rejected drivers are left out.
+	FindStalled(ctx context.Context, fleetID string, index int, stalledBefore time.Time, limit int) ([]models.StalledDriver, error)
+}
+
+// MongoOnboardingRepository keeps the onboarding steps on the driver
+// documents, where driver updates never overwrite them.
+type MongoOnboardingRepository struct {
+	drivers config.ScopedCollection
+}
+
+func NewMongoOnboardingRepository(db *config.MongoDB) *MongoOnboardingRepository {
+	return &MongoOnboardingRepository{
+		drivers: db.ScopedCollection("drivers"),
+	}
+}
+
+func (r *MongoOnboardingRepository) MarkStep(ctx context.Context, driverID, step string, at time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return fmt.Errorf("invalid driver ID format: %w", err)
+	}
+
+	_, err = r.drivers.For(ctx).UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$min": bson.M{"onboarding." + step: at}})
+	if err != nil {
+		return fmt.Errorf("failed to record onboarding step: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoOnboardingRepository) CountSteps(ctx context.Context, fleetID string, from, to time.Time) ([]models.OnboardingStepCount, error) {
+	filter := bson.M{"onboarding." + models.OnboardingRegistered: bson.M{"$gte": from, "$lt": to}}
+	if fleetID != "" {
+		filter["fleet_id"] = fleetID
+	}
+
+	// Each driver becomes one entry per step they reached, holding the
+	// hours from registration to it
+	steps := bson.A{}
+	for i := range models.OnboardingSteps {
+		hours := bson.M{"$divide": bson.A{
+			bson.M{"$subtract": bson.A{bson.M{"$max": stepFields(i)}, "$onboarding." + models.OnboardingRegistered}},
+			float64(time.Hour / time.Millisecond),
+		}}
+		steps = append(steps, bson.M{"$cond": bson.A{stepsReached(i), hours, nil}})
+	}
+
+	pipeline, err := NewPipeline().
+		Match(filter).
+		Project(bson.M{"_id": 0, "steps": steps}).
+		Unwind("$steps", "index").
+		Match(bson.M{"steps": bson.M{"$ne": nil}}).
+		Group("$index", bson.M{
+			"reached":   bson.M{"$sum": 1},
+			"avg_hours": bson.M{"$avg": "$steps"},
+		}).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.drivers.For(ctx).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count onboarding steps: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	counts := []models.OnboardingStepCount{}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, fmt.Errorf("failed to decode onboarding step counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *MongoOnboardingRepository) FindStalled(ctx context.Context, fleetID string, index int, stalledBefore time.Time, limit int) ([]models.StalledDriver, error) {
+	if index < 1 || index >= len(models.OnboardingSteps) {
+		return nil, fmt.Errorf("onboarding step index %d out of range", index)
+	}
+
+	stalledSince := bson.M{"$max": stepFields(index - 1)}
+	filter := bson.M{
+		"onboarding." + models.OnboardingSteps[index]: bson.M{"$exists": false},
+		"review_status": bson.M{"$ne": models.DriverReviewRejected},
+		"$expr": bson.M{"$and": bson.A{
+			stepsReached(index - 1),
+			bson.M{"$lt": bson.A{stalledSince, stalledBefore}},
+		}},
+	}
+	if fleetID != "" {
+		filter["fleet_id"] = fleetID
+	}
+
+	pipeline, err := NewPipeline().
+		Match(filter).
+		Project(bson.M{
+			"first_name":    1,
+			"last_name":     1,
+			"fleet_id":      1,
+			"review_status": 1,
+			"onboarding":    1,
+			"stalled_since": stalledSince,
+		}).
+		Sort(bson.D{{Key: "stalled_since", Value: 1}, {Key: "_id", Value: 1}}).
+		Limit(limit).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.drivers.For(ctx).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stalled drivers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var found []struct {
+		ID           primitive.ObjectID   `bson:"_id"`
+		FirstName    string               `bson:"first_name"`
+		LastName     string               `bson:"last_name"`
+		FleetID      string               `bson:"fleet_id"`
+		ReviewStatus string               `bson:"review_status"`
+		StalledSince time.Time            `bson:"stalled_since"`
+		Onboarding   map[string]time.Time `bson:"onboarding"`
+	}
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, fmt.Errorf("failed to decode stalled drivers: %w", err)
+	}
+
+	drivers := make([]models.StalledDriver, len(found))
+	for i, driver := range found {
+		drivers[i] = models.StalledDriver{
+			ID:           driver.ID.Hex(),
+			FirstName:    driver.FirstName,
+			LastName:     driver.LastName,
+			FleetID:      driver.FleetID,
+			ReviewStatus: driver.ReviewStatus,
+			StalledSince: driver.StalledSince,
+			Onboarding:   driver.Onboarding,
+		}
+	}
+
+	return drivers, nil
+}
+
+// stepFields lists the fields of the steps up to and including index.
+func stepFields(index int) bson.A {
+	fields := bson.A{}
+	for _, step := range models.OnboardingSteps[:index+1] {
+		fields = append(fields, "$onboarding."+step)
+	}
+	return fields
+}
+
+// stepsReached is an expression telling whether the driver reached every
+// step up to and including index.
+func stepsReached(index int) bson.M {
+	conditions := bson.A{}
+	for _, field := range stepFields(index) {
+		conditions = append(conditions, bson.M{"$gt": bson.A{field, nil}})
+	}
+	return bson.M{"$and": conditions}
+}
+
+func (r *MongoOnboardingRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "drivers",
+			Model: mongo.IndexModel{
+				Keys: bson.D{
+					{Key: "fleet_id", Value: 1},
+					{Key: "onboarding." + models.OnboardingRegistered, Value: 1},
+				},
+				Options: options.Index().SetName("drivers_fleet_id_onboarding_registered"),
+			},
+		},
+	}
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PhotoRepository interface {
+	Create(ctx context.Context, photo *models.DriverPhoto) error
+	// FindByID and FindApproved load the image bytes; the listings do not.
+	FindByID(ctx context.Context, id string) (*models.DriverPhoto, error)
+	FindApproved(ctx context.Context, driverID string) (*models.DriverPhoto, error)
+	FindByDriver(ctx context.Context, driverID string) ([]models.DriverPhoto, error)
+	FindByStatus(ctx context.Context, status string, limit int) ([]models.DriverPhoto, error)
+	// Transition moves a photo out of fromStatus. It fails with
+	// ErrPhotoConflict if another reviewer got there first.
+	Transition(ctx context.Context, photo *models.DriverPhoto, fromStatus string) error
+	// Supersede marks the driver's other photos in status as superseded.
+	Supersede(ctx context.Context, driverID, keepID primitive.ObjectID, status string) error
+}
+
+type MongoPhotoRepository struct {
+	collection config.ScopedCollection
+	archive    config.ScopedCollection
+}
+
+func NewMongoPhotoRepository(db *config.MongoDB) *MongoPhotoRepository {
+	return &MongoPhotoRepository{
+		collection: db.ScopedCollection("driver_photos"),
+		archive:    db.ScopedCollection("driver_photos_archive"),
+	}
+}
+
+func (r *MongoPhotoRepository) Create(ctx context.Context, photo *models.DriverPhoto) error {
+	if photo == nil {
+		return errors.New("photo cannot be nil")
+	}
+
+	if photo.ID.IsZero() {
+		photo.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, photo); err != nil {
+		return fmt.Errorf("failed to create photo: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoPhotoRepository) FindByID(ctx context.Context, id string) (*models.DriverPhoto, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid photo ID format: %w", err)
+	}
+
+	return r.findOne(ctx, bson.M{"_id": objectID}, nil)
+}
+
+func (r *MongoPhotoRepository) FindApproved(ctx context.Context, driverID string) (*models.DriverPhoto, error) {
+	objectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver ID format: %w", err)
+	}
+
+	return r.findOne(ctx,
+		bson.M{"driver_id": objectID, "status": models.PhotoApproved},
+		options.FindOne().SetSort(bson.M{"reviewed_at": -1}),
+	)
+}
+
+func (r *MongoPhotoRepository) findOne(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (*models.DriverPhoto, error) {
+	var photo models.DriverPhoto
+	if err := r.collection.For(ctx).FindOne(ctx, filter, opts).Decode(&photo); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrPhotoNotFound
+		}
+		return nil, fmt.Errorf("failed to find photo: %w", err)
+	}
+
+	return &photo, nil
+}
+
+func (r *MongoPhotoRepository) FindByDriver(ctx context.Context, driverID string) ([]models.DriverPhoto, error) {
+	objectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver ID format: %w", err)
+	}
+
+	return r.find(ctx, bson.M{"driver_id": objectID}, 0)
+}
+
+func (r *MongoPhotoRepository) FindByStatus(ctx context.Context, status string, limit int) ([]models.DriverPhoto, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	return r.find(ctx, filter, limit)
+}
+
+func (r *MongoPhotoRepository) find(ctx context.Context, filter bson.M, limit int) ([]models.DriverPhoto, error) {
+	findOptions := options.Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetProjection(bson.M{"image": 0})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.collection.For(ctx).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find photos: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	photos := []models.DriverPhoto{}
+	if err := cursor.All(ctx, &photos); err != nil {
+		return nil, fmt.Errorf("failed to decode photos: %w", err)
+	}
+
+	return photos, nil
+}
+
+func (r *MongoPhotoRepository) Transition(ctx context.Context, photo *models.DriverPhoto, fromStatus string) error {
+	result, err := r.collection.For(ctx).UpdateOne(ctx,
+		bson.M{"_id": photo.ID, "status": fromStatus},
+		bson.M{"$set": bson.M{
+			"status":      photo.Status,
+			"review_note": photo.ReviewNote,
+			"reviewed_by": photo.ReviewedBy,
+			"reviewed_at": photo.ReviewedAt,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update photo: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrPhotoConflict
+	}
+
+	return nil
+}
+
+func (r *MongoPhotoRepository) Supersede(ctx context.Context, driverID, keepID primitive.ObjectID, status string) error {
+	_, err := r.collection.For(ctx).UpdateMany(ctx,
+		bson.M{"driver_id": driverID, "status": status, "_id": bson.M{"$ne": keepID}},
+		bson.M{"$set": bson.M{"status": models.PhotoSuperseded}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to supersede photos: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoPhotoRepository) CollectionName() string {
+	return "driver_photos"
+}
+
+func (r *MongoPhotoRepository) ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error) {
+	return archiveMany(ctx, r.collection.For(ctx), r.archive.For(ctx), bson.M{"driver_id": driverID}, archivedAt)
+}
+
+func (r *MongoPhotoRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "driver_photos",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "driver_id", Value: 1}, {Key: "status", Value: 1}},
+				Options: options.Index().SetName("driver_photos_driver_status"),
+			},
+		},
+		{
+			Collection: "driver_photos",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("driver_photos_status_created_at"),
+			},
+		},
+	}
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GeoNear is a $geoNear stage over a 2dsphere index. Distances are in meters.
+type GeoNear struct {
+	Near models.Location
+	// DistanceField receives each document's distance from Near.
+	DistanceField string
+	// MaxDistanceMeters bounds the search; zero means unbounded.
+	MaxDistanceMeters float64
+	// Query filters documents before the distance sort and any later limit.
+	Query bson.M
+}
+
+// Pipeline builds an aggregation pipeline stage by stage. $geoNear is always
+// placed first, as MongoDB requires. Mistakes MongoDB would only report at
+// query time, such as a second $geoNear, are collected and returned by Build.
+type Pipeline struct {
+	stages mongo.Pipeline
+	errs   []error
+}
+
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+func (p *Pipeline) GeoNear(stage GeoNear) *Pipeline {
+	switch {
+	case len(p.stages) > 0 && p.stages[0][0].Key == "$geoNear":
+		p.errs = append(p.errs, errors.New("a pipeline takes only one $geoNear stage"))
+	case stage.Near.Lat < -90 || stage.Near.Lat > 90 || stage.Near.Lon < -180 || stage.Near.Lon > 180:
+		p.errs = append(p.errs, fmt.Errorf("$geoNear point (%g, %g) is out of range", stage.Near.Lat, stage.Near.Lon))
+	case stage.DistanceField == "":
+		p.errs = append(p.errs, errors.New("$geoNear needs a distance field"))
+	case stage.MaxDistanceMeters < 0:
+		p.errs = append(p.errs, errors.New("$geoNear max distance cannot be negative"))
+	}
+
+	options := bson.D{
+		{Key: "near", Value: stage.Near},
+		{Key: "distanceField", Value: stage.DistanceField},
+		{Key: "spherical", Value: true},
+	}
+	if stage.MaxDistanceMeters > 0 {
+		options = append(options, bson.E{Key: "maxDistance", Value: stage.MaxDistanceMeters})
+	}
+	if len(stage.Query) > 0 {
+		options = append(options, bson.E{Key: "query", Value: stage.Query})
+	}
+
+	p.stages = append(mongo.Pipeline{{{Key: "$geoNear", Value: options}}}, p.stages...)
+	return p
+}
+
+// Match adds a $match stage; an empty filter adds nothing.
+func (p *Pipeline) Match(filter bson.M) *Pipeline {
+	if len(filter) == 0 {
+		return p
+	}
+	return p.add("$match", filter)
+}
+
+// Limit adds a $limit stage; a limit below one adds nothing.
+func (p *Pipeline) Limit(n int) *Pipeline {
+	if n <= 0 {
+		return p
+	}
+	return p.add("$limit", int64(n))
+}
+
+// Project adds a $project stage. Fields map to 1 or 0 to include or exclude
+// them, or to an expression.
+func (p *Pipeline) Project(fields bson.M) *Pipeline {
+	if len(fields) == 0 {
+		p.errs = append(p.errs, errors.New("$project needs at least one field"))
+	}
+	return p.add("$project", fields)
+}
+
+// Unwind adds an $unwind stage emitting one document per element of the
+// array at path, with the element's position in indexField unless that is
+// empty.
+func (p *Pipeline) Unwind(path, indexField string) *Pipeline {
+	if !strings.HasPrefix(path, "$") {
+		p.errs = append(p.errs, fmt.Errorf("$unwind path %q must start with $", path))
+	}
+	stage := bson.M{"path": path}
+	if indexField != "" {
+		stage["includeArrayIndex"] = indexField
+	}
+	return p.add("$unwind", stage)
+}
+
+// Sort adds a $sort stage; keys keep their order.
+func (p *Pipeline) Sort(keys bson.D) *Pipeline {
+	if len(keys) == 0 {
+		p.errs = append(p.errs, errors.New("$sort needs at least one key"))
+	}
+	return p.add("$sort", keys)
+}
+
+// Group adds a $group stage keyed by id, with accumulators in fields.
+func (p *Pipeline) Group(id interface{}, fields bson.M) *Pipeline {
+	group := bson.M{"_id": id}
+	for name, accumulator := range fields {
+		if name == "_id" {
+			p.errs = append(p.errs, errors.New("$group fields cannot replace _id"))
+			continue
+		}
+		group[name] = accumulator
+	}
+	return p.add("$group", group)
+}
+
+// Build returns the stages, or every mistake found while adding them.
+func (p *Pipeline) Build() (mongo.Pipeline, error) {
+	if len(p.errs) > 0 {
+		return nil, fmt.Errorf("invalid aggregation pipeline: %w", errors.Join(p.errs...))
+	}
+	return p.stages, nil
+}
+
+func (p *Pipeline) add(name string, value interface{}) *Pipeline {
+	p.stages = append(p.stages, bson.D{{Key: name, Value: value}})
+	return p
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func stageNames(t *testing.T, p *Pipeline) []string {
+	t.Helper()
+	stages, err := p.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	names := make([]string, len(stages))
+	for i, stage := range stages {
+		names[i] = stage[0].Key
+	}
+	return names
+}
+
+func TestPipelineStageOrder(t *testing.T) {
+	near := GeoNear{Near: models.Location{Lat: 41.0, Lon: 29.0}, DistanceField: "distance"}
+
+	tests := []struct {
+		name     string
+		pipeline *Pipeline
+		want     []string
+	}{
+		{
+			name:     "stages keep the order they were added in",
+			pipeline: NewPipeline().Match(bson.M{"a": 1}).Sort(bson.D{{Key: "a", Value: 1}}).Limit(5).Project(bson.M{"a": 1}),
+			want:     []string{"$match", "$sort", "$limit", "$project"},
+		},
+		{
+			name:     "geoNear added first stays first",
+			pipeline: NewPipeline().GeoNear(near).Match(bson.M{"a": 1}).Limit(5),
+			want:     []string{"$geoNear", "$match", "$limit"},
+		},
+		{
+			name:     "geoNear added last is moved first",
+			pipeline: NewPipeline().Match(bson.M{"a": 1}).Limit(5).GeoNear(near),
+			want:     []string{"$geoNear", "$match", "$limit"},
+		},
+		{
+			name:     "empty match adds nothing",
+			pipeline: NewPipeline().Match(nil).Limit(5),
+			want:     []string{"$limit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stageNames(t, tt.pipeline); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipelineLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  []string
+	}{
+		{name: "positive limit", limit: 10, want: []string{"$match", "$limit"}},
+		{name: "zero limit is dropped", limit: 0, want: []string{"$match"}},
+		{name: "negative limit is dropped", limit: -3, want: []string{"$match"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPipeline().Match(bson.M{"a": 1}).Limit(tt.limit)
+			if got := stageNames(t, p); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	stages, err := NewPipeline().Limit(10).Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if got := stages[0][0].Value; got != int64(10) {
+		t.Errorf("$limit = %#v, want int64(10)", got)
+	}
+}
+
+func TestPipelineGeoNearOptions(t *testing.T) {
+	stages, err := NewPipeline().GeoNear(GeoNear{
+		Near:              models.Location{Lat: 41.0, Lon: 29.0},
+		DistanceField:     "distance",
+		MaxDistanceMeters: 5000,
+		Query:             bson.M{"verified": true},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	options, ok := stages[0][0].Value.(bson.D)
+	if !ok {
+		t.Fatalf("$geoNear options are %T, want bson.D", stages[0][0].Value)
+	}
+	got := options.Map()
+	if got["distanceField"] != "distance" || got["spherical"] != true || got["maxDistance"] != 5000.0 {
+		t.Errorf("$geoNear options = %v", got)
+	}
+	if !reflect.DeepEqual(got["query"], bson.M{"verified": true}) {
+		t.Errorf("$geoNear query = %v, want the filter", got["query"])
+	}
+}
+
+func TestPipelineErrors(t *testing.T) {
+	near := GeoNear{Near: models.Location{Lat: 41.0, Lon: 29.0}, DistanceField: "distance"}
+
+	tests := []struct {
+		name     string
+		pipeline *Pipeline
+		want     string
+	}{
+		{
+			name:     "second geoNear",
+			pipeline: NewPipeline().GeoNear(near).Match(bson.M{"a": 1}).GeoNear(near),
+			want:     "only one $geoNear",
+		},
+		{
+			name:     "geoNear point out of range",
+			pipeline: NewPipeline().GeoNear(GeoNear{Near: models.Location{Lat: 91, Lon: 29}, DistanceField: "distance"}),
+			want:     "out of range",
+		},
+		{
+			name:     "geoNear without distance field",
+			pipeline: NewPipeline().GeoNear(GeoNear{Near: near.Near}),
+			want:     "distance field",
+		},
+		{
+			name:     "negative max distance",
+			pipeline: NewPipeline().GeoNear(GeoNear{Near: near.Near, DistanceField: "distance", MaxDistanceMeters: -1}),
+			want:     "max distance",
+		},
+		{
+			name:     "empty projection",
+			pipeline: NewPipeline().Project(bson.M{}),
+			want:     "$project",
+		},
+		{
+			name:     "unwind path without $",
+			pipeline: NewPipeline().Unwind("steps", ""),
+			want:     "$unwind",
+		},
+		{
+			name:     "group replacing _id",
+			pipeline: NewPipeline().Group("$a", bson.M{"_id": 1}),
+			want:     "_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stages, err := tt.pipeline.Build()
+			if err == nil {
+				t.Fatalf("Build() = %v, want an error", stages)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Build() error = %q, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
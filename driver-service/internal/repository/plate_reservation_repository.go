@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PlateReservationRepository interface {
+	// Reserve takes or renews the reservation for key. It fails with
+	// ErrPlateReserved while another token holds an unexpired reservation.
+	Reserve(ctx context.Context, reservation *models.PlateReservation, now time.Time) error
+	FindByKey(ctx context.Context, key string) (*models.PlateReservation, error)
+	Release(ctx context.Context, key, token string) error
+}
+
+type MongoPlateReservationRepository struct {
+	collection config.ScopedCollection
+}
+
+func NewMongoPlateReservationRepository(db *config.MongoDB) *MongoPlateReservationRepository {
+	return &MongoPlateReservationRepository{
+		collection: db.ScopedCollection("plate_reservations"),
+	}
+}
+
+// Reserve relies on the _id being the plate key: when the filter misses
+// because someone else holds the plate, the upsert collides with their
+// document and the duplicate key error becomes ErrPlateReserved.
+func (r *MongoPlateReservationRepository) Reserve(ctx context.Context, reservation *models.PlateReservation, now time.Time) error {
+	filter := bson.M{
+		"_id": reservation.ID,
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$lte": now}},
+			bson.M{"token": reservation.Token},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"token":      reservation.Token,
+			"expires_at": reservation.ExpiresAt,
+			"created_at": reservation.CreatedAt,
+		},
+	}
+
+	_, err := r.collection.For(ctx).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrPlateReserved
+		}
+		return fmt.Errorf("failed to reserve plate: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoPlateReservationRepository) FindByKey(ctx context.Context, key string) (*models.PlateReservation, error) {
+	var reservation models.PlateReservation
+	if err := r.collection.For(ctx).FindOne(ctx, bson.M{"_id": key}).Decode(&reservation); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrPlateReservationNotFound
+		}
+		return nil, fmt.Errorf("failed to find plate reservation: %w", err)
+	}
+
+	return &reservation, nil
+}
+
+func (r *MongoPlateReservationRepository) Release(ctx context.Context, key, token string) error {
+	result, err := r.collection.For(ctx).DeleteOne(ctx, bson.M{"_id": key, "token": token})
+	if err != nil {
+		return fmt.Errorf("failed to release plate reservation: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrPlateReservationNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoPlateReservationRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			// Expired reservations are already ignored; the TTL index only
+			// keeps the collection small
+			Collection: "plate_reservations",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetName("plate_reservations_expires_at_ttl").SetExpireAfterSeconds(0),
+			},
+		},
+	}
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RatingPromptRepository interface {
+	// Create stores the prompt of a trip. It fails with
+	// ErrRatingPromptExists if the trip has one already.
+	Create(ctx context.Context, prompt *models.RatingPrompt) error
+	FindByTrip(ctx context.Context, tripID string) (*models.RatingPrompt, error)
+	// ClaimDue pushes the next due pending prompt back to leaseUntil, so no
+	// other worker sends it meanwhile, and returns it. It fails with
+	// ErrRatingPromptNotFound if nothing is due.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*models.RatingPrompt, error)
+	// Update stores a claimed prompt unless it left pending meanwhile, as
+	// it does when the ride is rated while its prompt is being sent.
+	Update(ctx context.Context, prompt *models.RatingPrompt) error
+	// MarkRated settles the trip's prompt as rated if it is pending or
+	// exhausted. A trip without such a prompt is left alone.
+	MarkRated(ctx context.Context, tripID string, at time.Time) error
+	// CountByStatus counts the prompts created since the given time by
+	// status and number sent, for one fleet or, with "", all of them.
+	CountByStatus(ctx context.Context, fleetID string, since time.Time) ([]models.RatingPromptCount, error)
+
+	FindSettings(ctx context.Context, fleetID string) (*models.RatingPromptSettings, error)
+	FindAllSettings(ctx context.Context) ([]models.RatingPromptSettings, error)
+	UpsertSettings(ctx context.Context, settings *models.RatingPromptSettings) error
+	DeleteSettings(ctx context.Context, fleetID string) error
+}
+
+// MongoRatingPromptRepository keeps prompts in the rating_prompts collection
+// and per-fleet prompt settings, keyed by fleet ID, in
+// rating_prompt_settings.
+type MongoRatingPromptRepository struct {
+	collection config.ScopedCollection
+	settings   config.ScopedCollection
+}
+
+func NewMongoRatingPromptRepository(db *config.MongoDB) *MongoRatingPromptRepository {
+	return &MongoRatingPromptRepository{
+		collection: db.ScopedCollection("rating_prompts"),
+		settings:   db.ScopedCollection("rating_prompt_settings"),
+	}
+}
+
+func (r *MongoRatingPromptRepository) Create(ctx context.Context, prompt *models.RatingPrompt) error {
+	if prompt == nil {
+		return errors.New("rating prompt cannot be nil")
+	}
+
+	if prompt.ID.IsZero() {
+		prompt.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, prompt); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrRatingPromptExists
+		}
+		return fmt.Errorf("failed to create rating prompt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoRatingPromptRepository) FindByTrip(ctx context.Context, tripID string) (*models.RatingPrompt, error) {
+	var prompt models.RatingPrompt
+	if err := r.collection.For(ctx).FindOne(ctx, bson.M{"trip_id": tripID}).Decode(&prompt); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrRatingPromptNotFound
+		}
+		return nil, fmt.Errorf("failed to find rating prompt: %w", err)
+	}
+
+	return &prompt, nil
+}
+
+func (r *MongoRatingPromptRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*models.RatingPrompt, error) {
+	filter := bson.M{
+		"status":  models.RatingPromptPending,
+		"next_at": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"next_at": leaseUntil}}
+	findOptions := options.FindOneAndUpdate().SetSort(bson.M{"next_at": 1})
+
+	// The returned prompt keeps its due time; only the stored one is leased
+	var prompt models.RatingPrompt
+	if err := r.collection.For(ctx).FindOneAndUpdate(ctx, filter, update, findOptions).Decode(&prompt); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrRatingPromptNotFound
+		}
+		return nil, fmt.Errorf("failed to claim rating prompt: %w", err)
+	}
+
+	return &prompt, nil
+}
+
+func (r *MongoRatingPromptRepository) Update(ctx context.Context, prompt *models.RatingPrompt) error {
+	if prompt == nil {
+		return errors.New("rating prompt cannot be nil")
+	}
+
+	_, err := r.collection.For(ctx).ReplaceOne(ctx, bson.M{"_id": prompt.ID, "status": models.RatingPromptPending}, prompt)
+	if err != nil {
+		return fmt.Errorf("failed to update rating prompt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoRatingPromptRepository) MarkRated(ctx context.Context, tripID string, at time.Time) error {
+	_, err := r.collection.For(ctx).UpdateOne(ctx,
+		bson.M{
+			"trip_id": tripID,
+			"status":  bson.M{"$in": []string{models.RatingPromptPending, models.RatingPromptExhausted}},
+		},
+		bson.M{
+			"$set":   bson.M{"status": models.RatingPromptRated, "rated_at": at, "updated_at": at},
+			"$unset": bson.M{"next_at": ""},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark rating prompt rated: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoRatingPromptRepository) CountByStatus(ctx context.Context, fleetID string, since time.Time) ([]models.RatingPromptCount, error) {
+	filter := bson.M{"created_at": bson.M{"$gte": since}}
+	if fleetID != "" {
+		filter["fleet_id"] = fleetID
+	}
+
+	pipeline, err := NewPipeline().
+		Match(filter).
+		Group(bson.M{"status": "$status", "sent": "$sent"}, bson.M{"count": bson.M{"$sum": 1}}).
+		Project(bson.M{"_id": 0, "status": "$_id.status", "sent": "$_id.sent", "count": 1}).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.For(ctx).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count rating prompts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	counts := []models.RatingPromptCount{}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, fmt.Errorf("failed to decode rating prompt counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *MongoRatingPromptRepository) FindSettings(ctx context.Context, fleetID string) (*models.RatingPromptSettings, error) {
+	var settings models.RatingPromptSettings
+	if err := r.settings.For(ctx).FindOne(ctx, bson.M{"_id": fleetID}).Decode(&settings); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrRatingPromptSettingsNotFound
+		}
+		return nil, fmt.Errorf("failed to find rating prompt settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+func (r *MongoRatingPromptRepository) FindAllSettings(ctx context.Context) ([]models.RatingPromptSettings, error) {
+	cursor, err := r.settings.For(ctx).Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rating prompt settings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	settings := []models.RatingPromptSettings{}
+	if err := cursor.All(ctx, &settings); err != nil {
+		return nil, fmt.Errorf("failed to decode rating prompt settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (r *MongoRatingPromptRepository) UpsertSettings(ctx context.Context, settings *models.RatingPromptSettings) error {
+	if settings == nil {
+		return errors.New("rating prompt settings cannot be nil")
+	}
+
+	_, err := r.settings.For(ctx).ReplaceOne(ctx, bson.M{"_id": settings.FleetID}, settings, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save rating prompt settings: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoRatingPromptRepository) DeleteSettings(ctx context.Context, fleetID string) error {
+	result, err := r.settings.For(ctx).DeleteOne(ctx, bson.M{"_id": fleetID})
+	if err != nil {
+		return fmt.Errorf("failed to delete rating prompt settings: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrRatingPromptSettingsNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoRatingPromptRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			// One prompt per trip, even when a completion is retried
+			Collection: "rating_prompts",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "trip_id", Value: 1}},
+				Options: options.Index().SetName("rating_prompts_trip_id_unique").SetUnique(true),
+			},
+		},
+		{
+			Collection: "rating_prompts",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_at", Value: 1}},
+				Options: options.Index().SetName("rating_prompts_status_next_at"),
+			},
+		},
+		{
+			Collection: "rating_prompts",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "fleet_id", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("rating_prompts_fleet_id_created_at"),
+			},
+		},
+	}
+}
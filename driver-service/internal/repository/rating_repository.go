@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RatingRepository interface {
+	// Create stores the rating and adds it to the driver's rating
+	// aggregates, returning them as they are afterwards.
+	Create(ctx context.Context, rating *models.Rating) (*models.RatingResult, error)
+	// FindByDriver returns the driver's newest ratings first, up to limit.
+	FindByDriver(ctx context.Context, driverID string, limit int) ([]models.Rating, error)
+	// ExistsForTrip reports whether the trip has been rated.
+	ExistsForTrip(ctx context.Context, tripID string) (bool, error)
+}
+
+// MongoRatingRepository keeps ratings in the ratings collection. A rating
+// and the driver's aggregates are written in one transaction, and each
+// aggregate update is a single pipeline update of the driver document, so
+// concurrent ratings never lose a count.
+type MongoRatingRepository struct {
+	db         *config.MongoDB
+	collection config.ScopedCollection
+	archive    config.ScopedCollection
+	drivers    config.ScopedCollection
+}
+
+func NewMongoRatingRepository(db *config.MongoDB) *MongoRatingRepository {
+	return &MongoRatingRepository{
+		db:         db,
+		collection: db.ScopedCollection("ratings"),
+		archive:    db.ScopedCollection("ratings_archive"),
+		drivers:    db.ScopedCollection("drivers"),
+	}
+}
+
+func (r *MongoRatingRepository) Create(ctx context.Context, rating *models.Rating) (*models.RatingResult, error) {
+	if rating == nil {
+		return nil, errors.New("rating cannot be nil")
+	}
+
+	rating.CreatedAt = time.Now()
+	if rating.ID.IsZero() {
+		rating.ID = primitive.NewObjectID()
+	}
+
+	session, err := r.db.For(ctx).Client.StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	var result *models.RatingResult
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		var err error
+		result, err = r.create(sc, rating)
+		return nil, err
+	})
+	if err == nil {
+		return result, nil
+	}
+
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Code != mongoIllegalOperation {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Warn("Transactions unavailable, storing rating without a transaction", "driver_id", rating.DriverID.Hex())
+	return r.create(ctx, rating)
+}
+
+// create inserts the rating and then folds it into the driver's aggregates.
+// If the driver cannot be updated the rating is removed again, which also
+// covers servers without transactions.
+func (r *MongoRatingRepository) create(ctx context.Context, rating *models.Rating) (*models.RatingResult, error) {
+	if _, err := r.collection.For(ctx).InsertOne(ctx, rating); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrTripRated
+		}
+		return nil, fmt.Errorf("failed to create rating: %w", err)
+	}
+
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"rating_sum":   bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$rating_sum", 0}}, rating.Stars}},
+			"rating_count": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$rating_count", 0}}, 1}},
+		}},
+		bson.M{"$set": bson.M{
+			"average_rating": bson.M{"$round": bson.A{bson.M{"$divide": bson.A{"$rating_sum", "$rating_count"}}, 2}},
+		}},
+	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"average_rating": 1, "rating_count": 1})
+
+	var driver models.Driver
+	err := r.drivers.For(ctx).FindOneAndUpdate(ctx, bson.M{"_id": rating.DriverID}, update, opts).Decode(&driver)
+	if err != nil {
+		if _, deleteErr := r.collection.For(ctx).DeleteOne(ctx, bson.M{"_id": rating.ID}); deleteErr != nil {
+			logging.FromContext(ctx).Error("Failed to remove rating", "rating_id", rating.ID.Hex(), "driver_id", rating.DriverID.Hex(), "error", deleteErr)
+		}
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrDriverNotFound
+		}
+		return nil, fmt.Errorf("failed to update driver rating: %w", err)
+	}
+
+	return &models.RatingResult{
+		Rating:        *rating,
+		AverageRating: driver.AverageRating,
+		RatingCount:   driver.RatingCount,
+	}, nil
+}
+
+func (r *MongoRatingRepository) FindByDriver(ctx context.Context, driverID string, limit int) ([]models.Rating, error) {
+	objectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver ID format: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.For(ctx).Find(ctx, bson.M{"driver_id": objectID}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ratings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	ratings := []models.Rating{}
+	if err = cursor.All(ctx, &ratings); err != nil {
+		return nil, fmt.Errorf("failed to decode ratings: %w", err)
+	}
+
+	return ratings, nil
+}
+
+func (r *MongoRatingRepository) ExistsForTrip(ctx context.Context, tripID string) (bool, error) {
+	count, err := r.collection.For(ctx).CountDocuments(ctx, bson.M{"trip_id": tripID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to find trip rating: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *MongoRatingRepository) CollectionName() string {
+	return "ratings"
+}
+
+func (r *MongoRatingRepository) ArchiveByDriver(ctx context.Context, driverID primitive.ObjectID, archivedAt time.Time) (int64, error) {
+	return archiveMany(ctx, r.collection.For(ctx), r.archive.For(ctx), bson.M{"driver_id": driverID}, archivedAt)
+}
+
+func (r *MongoRatingRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "ratings",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "driver_id", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("ratings_driver_created_at"),
+			},
+		},
+		{
+			// A trip is rated once, even when the rider submits twice
+			Collection: "ratings",
+			Model: mongo.IndexModel{
+				Keys: bson.D{{Key: "trip_id", Value: 1}},
+				Options: options.Index().
+					SetName("ratings_trip_id_unique").
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"trip_id": bson.M{"$type": "string"}}),
+			},
+		},
+	}
+}
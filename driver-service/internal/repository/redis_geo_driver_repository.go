@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/metrics"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	driversGeoRepositoryName = "drivers_geo"
+	driversGeoKey            = "taxihub:drivers:geo"
+	// driversGeoSyncedSuffix names the marker written once a GEO set holds
+	// every driver; without it searches go to MongoDB.
+	driversGeoSyncedSuffix = ":synced"
+
+	// geoCandidateFactor is how many candidates a search first asks Redis
+	// for per result, as the filters are applied afterwards.
+	geoCandidateFactor = 4
+	geoMaxCandidates   = 5000
+	geoSyncBatchSize   = 500
+	geoSyncTimeout     = 5 * time.Minute
+)
+
+var errGeoSetNotSynced = errors.New("driver GEO set is not synced")
+
+// RedisGeoDriverRepository answers nearby searches from a Redis GEO set kept
+// next to MongoDB, which stays the source of truth. Writes go to MongoDB
+// first and are then mirrored with GEOADD and ZREM. A search asks GEOSEARCH
+// for the closest candidates, loads them from MongoDB, and applies the
+// filters and distances to the stored documents, so a lagging or stale GEO
+// entry can cost a candidate but never returns wrong data.
+//
+// A GEO set is used only once it has been filled from MongoDB; until then,
+// and whenever Redis fails, searches fall back to $geoNear. Each isolated
+// tenant database has its own set.
+type RedisGeoDriverRepository struct {
+	*MongoDriverRepository
+
+	client  redis.UniversalClient
+	db      *config.MongoDB
+	metrics *metrics.Repository
+
+	mu      sync.Mutex
+	syncing map[string]bool
+}
+
+func NewRedisGeoDriverRepository(repo *MongoDriverRepository, client redis.UniversalClient, db *config.MongoDB, m *metrics.Repository) *RedisGeoDriverRepository {
+	return &RedisGeoDriverRepository{
+		MongoDriverRepository: repo,
+		client:                client,
+		db:                    db,
+		metrics:               m,
+		syncing:               make(map[string]bool),
+	}
+}
+
+func (r *RedisGeoDriverRepository) Create(ctx context.Context, driver *models.Driver) (string, error) {
+	id, err := r.MongoDriverRepository.Create(ctx, driver)
+	if err != nil {
+		return "", err
+	}
+	r.add(ctx, id, driver.Location)
+	return id, nil
+}
+
+func (r *RedisGeoDriverRepository) Update(ctx context.Context, id string, driver *models.Driver, fields ...models.DriverField) error {
+	if err := r.MongoDriverRepository.Update(ctx, id, driver, fields...); err != nil {
+		return err
+	}
+	if slices.Contains(fields, models.DriverFieldLocation) {
+		r.add(ctx, id, driver.Location)
+	}
+	return nil
+}
+
+func (r *RedisGeoDriverRepository) Delete(ctx context.Context, id string) error {
+	if err := r.MongoDriverRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.remove(ctx, id)
+	return nil
+}
+
+// FindNearby widens the candidate count until enough candidates pass the
+// filters or the radius runs out of drivers.
+func (r *RedisGeoDriverRepository) FindNearby(ctx context.Context, lat, lon, radiusKm float64, filter models.NearbyFilter) ([]models.DriverWithDistance, error) {
+	if radiusKm <= 0 || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return r.MongoDriverRepository.FindNearby(ctx, lat, lon, radiusKm, filter)
+	}
+
+	limit := filter.ResultLimit()
+	for count := limit * geoCandidateFactor; ; count *= 2 {
+		if count > geoMaxCandidates {
+			count = geoMaxCandidates
+		}
+
+		members, err := r.search(ctx, lat, lon, radiusKm, count)
+		if err != nil {
+			if !errors.Is(err, errGeoSetNotSynced) {
+				logging.FromContext(ctx).Warn("Redis nearby search failed, using MongoDB", "error", err)
+			}
+			return r.MongoDriverRepository.FindNearby(ctx, lat, lon, radiusKm, filter)
+		}
+
+		results, err := r.candidates(ctx, members, lat, lon, radiusKm, filter)
+		if err != nil {
+			return nil, err
+		}
+		exhausted := len(members) < count
+		if len(results) >= limit || exhausted {
+			if len(results) > limit {
+				results = results[:limit]
+			}
+			return results, nil
+		}
+		if count == geoMaxCandidates {
+			return r.MongoDriverRepository.FindNearby(ctx, lat, lon, radiusKm, filter)
+		}
+	}
+}
+
+// search returns up to count driver IDs within the radius, closest first. It
+// fails with errGeoSetNotSynced, and starts a sync, if the set has not been
+// filled from MongoDB.
+func (r *RedisGeoDriverRepository) search(ctx context.Context, lat, lon, radiusKm float64, count int) ([]string, error) {
+	key := r.key(ctx)
+
+	start := time.Now()
+	pipe := r.client.Pipeline()
+	synced := pipe.Exists(ctx, key+driversGeoSyncedSuffix)
+	search := pipe.GeoSearch(ctx, key, &redis.GeoSearchQuery{
+		Longitude:  lon,
+		Latitude:   lat,
+		Radius:     radiusKm,
+		RadiusUnit: "km",
+		Sort:       "ASC",
+		Count:      count,
+	})
+	_, err := pipe.Exec(ctx)
+	r.observe("geo_search", start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if synced.Val() == 0 {
+		r.startSync(ctx, key)
+		return nil, errGeoSetNotSynced
+	}
+	return search.Val(), nil
+}
+
+// candidates loads the drivers from MongoDB and keeps those matching the
+// filter within the radius, closest first. Members with no driver are
+// removed from the set; they are drivers deleted without going through
+// Delete.
+func (r *RedisGeoDriverRepository) candidates(ctx context.Context, members []string, lat, lon, radiusKm float64, filter models.NearbyFilter) ([]models.DriverWithDistance, error) {
+	ids := make([]primitive.ObjectID, 0, len(members))
+	for _, member := range members {
+		if id, err := primitive.ObjectIDFromHex(member); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	drivers, err := r.MongoDriverRepository.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(drivers))
+	center := models.Location{Lat: lat, Lon: lon}
+	var results []models.DriverWithDistance
+	for i := range drivers {
+		driver := &drivers[i]
+		found[driver.ID.Hex()] = true
+		if !filter.Matches(driver) {
+			continue
+		}
+		distance := center.DistanceKm(driver.Location)
+		if distance > radiusKm {
+			continue
+		}
+		results = append(results, models.DriverWithDistance{
+			Driver:     *driver,
+			DistanceKm: distance,
+		})
+	}
+
+	var stale []interface{}
+	for _, member := range members {
+		if !found[member] {
+			stale = append(stale, member)
+		}
+	}
+	if len(stale) > 0 {
+		start := time.Now()
+		err := r.client.ZRem(ctx, r.key(ctx), stale...).Err()
+		r.observe("geo_remove", start, err)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].DistanceKm < results[j].DistanceKm
+	})
+	return results, nil
+}
+
+func (r *RedisGeoDriverRepository) add(ctx context.Context, id string, location models.Location) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return
+	}
+
+	start := time.Now()
+	err = r.client.GeoAdd(ctx, r.key(ctx), &redis.GeoLocation{
+		Name:      objectID.Hex(),
+		Longitude: location.Lon,
+		Latitude:  location.Lat,
+	}).Err()
+	r.observe("geo_add", start, err)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to mirror driver location to Redis", "driver_id", id, "error", err)
+	}
+}
+
+func (r *RedisGeoDriverRepository) remove(ctx context.Context, id string) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return
+	}
+
+	start := time.Now()
+	err = r.client.ZRem(ctx, r.key(ctx), objectID.Hex()).Err()
+	r.observe("geo_remove", start, err)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to remove driver from Redis", "driver_id", id, "error", err)
+	}
+}
+
+// startSync fills the set from MongoDB in the background, once per key at a
+// time. Updates racing the sync may be overwritten with the location MongoDB
+// had when it was read; the next update corrects them.
+func (r *RedisGeoDriverRepository) startSync(ctx context.Context, key string) {
+	r.mu.Lock()
+	if r.syncing[key] {
+		r.mu.Unlock()
+		return
+	}
+	r.syncing[key] = true
+	r.mu.Unlock()
+
+	syncCtx, cancel := context.WithTimeout(config.WithTenant(context.Background(), config.TenantFromContext(ctx)), geoSyncTimeout)
+	logger := logging.FromContext(ctx).With("key", key)
+	go func() {
+		defer cancel()
+		defer func() {
+			r.mu.Lock()
+			delete(r.syncing, key)
+			r.mu.Unlock()
+		}()
+
+		start := time.Now()
+		count, err := r.sync(syncCtx, key)
+		r.observe("sync", start, err)
+		if err != nil {
+			logger.Warn("Failed to sync driver locations to Redis", "error", err)
+			return
+		}
+		logger.Info("Synced driver locations to Redis", "count", count, "duration_ms", time.Since(start).Milliseconds())
+	}()
+}
+
+func (r *RedisGeoDriverRepository) sync(ctx context.Context, key string) (int, error) {
+	count := 0
+	batch := make([]*redis.GeoLocation, 0, geoSyncBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := r.client.GeoAdd(ctx, key, batch...).Err(); err != nil {
+			return fmt.Errorf("failed to add driver locations: %w", err)
+		}
+		count += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	err := r.MongoDriverRepository.ScanLocations(ctx, func(id primitive.ObjectID, location models.Location) error {
+		batch = append(batch, &redis.GeoLocation{
+			Name:      id.Hex(),
+			Longitude: location.Lon,
+			Latitude:  location.Lat,
+		})
+		if len(batch) == geoSyncBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return count, err
+	}
+
+	if err := r.client.Set(ctx, key+driversGeoSyncedSuffix, time.Now().UTC().Format(time.RFC3339), 0).Err(); err != nil {
+		return count, fmt.Errorf("failed to mark driver locations synced: %w", err)
+	}
+	return count, nil
+}
+
+// key is the tenant's GEO set. Tenants without their own database share the
+// drivers collection, and so the set.
+func (r *RedisGeoDriverRepository) key(ctx context.Context) string {
+	tenantID := config.TenantFromContext(ctx)
+	if _, ok := r.db.Tenant(tenantID); ok {
+		return driversGeoKey + ":" + tenantID
+	}
+	return driversGeoKey
+}
+
+func (r *RedisGeoDriverRepository) observe(operation string, start time.Time, err error) {
+	r.metrics.ObserveOperation(driversGeoRepositoryName, operation, time.Since(start), err)
+}
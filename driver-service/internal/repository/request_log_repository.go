@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RequestLogRepository interface {
+	Create(ctx context.Context, entry *models.RequestLog) error
+	FindRecent(ctx context.Context, requestID string, limit int) ([]models.RequestLog, error)
+}
+
+type MongoRequestLogRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRequestLogRepository(db *config.MongoDB) *MongoRequestLogRepository {
+	return &MongoRequestLogRepository{
+		collection: db.GetCollection("request_logs"),
+	}
+}
+
+func (r *MongoRequestLogRepository) Create(ctx context.Context, entry *models.RequestLog) error {
+	if entry == nil {
+		return errors.New("request log cannot be nil")
+	}
+
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.InsertOne(ctx, entry); err != nil {
+		return fmt.Errorf("failed to store request log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoRequestLogRepository) FindRecent(ctx context.Context, requestID string, limit int) ([]models.RequestLog, error) {
+	if limit < 1 || limit > 100 {
+		limit = 100
+	}
+
+	filter := bson.M{}
+	if requestID != "" {
+		filter["request_id"] = requestID
+	}
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.M{"created_at": -1})
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request logs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.RequestLog
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode request logs: %w", err)
+	}
+
+	return entries, nil
+}
+
+// RequiredIndexes includes the TTL index that enforces log retention. Each
+// entry carries its own expires_at, so the index expires at that instant.
+func (r *MongoRequestLogRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			Collection: "request_logs",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetName("request_logs_ttl").SetExpireAfterSeconds(0),
+			},
+		},
+		{
+			Collection: "request_logs",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "request_id", Value: 1}},
+				Options: options.Index().SetName("request_logs_request_id"),
+			},
+		},
+	}
+}
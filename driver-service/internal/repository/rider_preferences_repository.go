@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RiderPreferencesRepository stores riders' saved ride preferences, one
+// document per rider.
+type RiderPreferencesRepository interface {
+	Find(ctx context.Context, riderID string) (*models.RiderPreferences, error)
+	Upsert(ctx context.Context, preferences *models.RiderPreferences) error
+	Delete(ctx context.Context, riderID string) error
+}
+
+type MongoRiderPreferencesRepository struct {
+	collection config.ScopedCollection
+}
+
+func NewMongoRiderPreferencesRepository(db *config.MongoDB) *MongoRiderPreferencesRepository {
+	return &MongoRiderPreferencesRepository{
+		collection: db.ScopedCollection("rider_preferences"),
+	}
+}
+
+func (r *MongoRiderPreferencesRepository) Find(ctx context.Context, riderID string) (*models.RiderPreferences, error) {
+	var preferences models.RiderPreferences
+	if err := r.collection.For(ctx).FindOne(ctx, bson.M{"_id": riderID}).Decode(&preferences); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrRiderPreferencesNotFound
+		}
+		return nil, fmt.Errorf("failed to find rider preferences: %w", err)
+	}
+
+	return &preferences, nil
+}
+
+func (r *MongoRiderPreferencesRepository) Upsert(ctx context.Context, preferences *models.RiderPreferences) error {
+	if preferences == nil {
+		return errors.New("rider preferences cannot be nil")
+	}
+
+	_, err := r.collection.For(ctx).ReplaceOne(ctx, bson.M{"_id": preferences.RiderID}, preferences, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save rider preferences: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoRiderPreferencesRepository) Delete(ctx context.Context, riderID string) error {
+	result, err := r.collection.For(ctx).DeleteOne(ctx, bson.M{"_id": riderID})
+	if err != nil {
+		return fmt.Errorf("failed to delete rider preferences: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrRiderPreferencesNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,505 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	sandboxDriverCount           = 40
+	sandboxCenterLat             = 41.0082
+	sandboxCenterLon             = 28.9784
+	sandboxSpreadKm              = 8.0
+	sandboxOrbitKm               = 0.4
+	sandboxOrbitPeriod           = 10 * time.Minute
+	sandboxRepositionToleranceKm = 0.05
+	kmPerDegreeLatitude          = 111.32
+	sandboxBaseTimestamp         = 1700000000
+)
+
+var (
+	sandboxFirstNames = []string{"Ahmet", "Mehmet", "Mustafa", "Ali", "Hüseyin", "Hasan", "Ayşe", "Fatma", "Emine", "Zeynep"}
+	sandboxLastNames  = []string{"Yılmaz", "Kaya", "Demir", "Şahin", "Çelik", "Yıldız", "Aydın", "Öztürk", "Arslan", "Doğan"}
+	sandboxCars       = [][2]string{{"Fiat", "Egea"}, {"Renault", "Clio"}, {"Hyundai", "i20"}, {"Toyota", "Corolla"}, {"Volkswagen", "Passat"}, {"Mercedes", "Vito"}}
+	sandboxTaxiTypes  = []string{models.TaxiTypeSari, models.TaxiTypeTurkuaz, models.TaxiTypeSiyah}
+)
+
+// sandboxDriver keeps the anchor point a synthetic driver circles around,
+// so positions move over time while staying reproducible for a given seed.
+type sandboxDriver struct {
+	driver models.Driver
+	anchor models.Location
+	phase  float64
+}
+
+type SandboxDriverRepository struct {
+	mu      sync.RWMutex
+	drivers map[primitive.ObjectID]*sandboxDriver
+	now     func() time.Time
+}
+
+func NewSandboxDriverRepository(seed int64) *SandboxDriverRepository {
+	rng := rand.New(rand.NewSource(seed))
+	repo := &SandboxDriverRepository{
+		drivers: make(map[primitive.ObjectID]*sandboxDriver, sandboxDriverCount),
+		now:     time.Now,
+	}
+
+	for i := 0; i < sandboxDriverCount; i++ {
+		createdAt := time.Unix(sandboxBaseTimestamp+int64(i)*3600, 0).UTC()
+		car := sandboxCars[rng.Intn(len(sandboxCars))]
+
+		driver := models.Driver{
+			ID:        sandboxObjectID(seed, i, createdAt),
+			FirstName: sandboxFirstNames[rng.Intn(len(sandboxFirstNames))],
+			LastName:  sandboxLastNames[rng.Intn(len(sandboxLastNames))],
+			Plate:     fmt.Sprintf("34 SBX %03d", i+1),
+			TaxiType:  sandboxTaxiTypes[rng.Intn(len(sandboxTaxiTypes))],
+			CarBrand:  car[0],
+			CarModel:  car[1],
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}
+
+		repo.drivers[driver.ID] = &sandboxDriver{
+			driver: driver,
+			anchor: offsetLocation(
+				models.Location{Lat: sandboxCenterLat, Lon: sandboxCenterLon},
+				(rng.Float64()*2-1)*sandboxSpreadKm,
+				(rng.Float64()*2-1)*sandboxSpreadKm,
+			),
+			phase: rng.Float64() * 2 * math.Pi,
+		}
+	}
+
+	return repo
+}
+
+// sandboxObjectID derives a stable ObjectID from the seed and index so that
+// integrators can hardcode sandbox driver IDs in their tests.
+func sandboxObjectID(seed int64, index int, createdAt time.Time) primitive.ObjectID {
+	var id primitive.ObjectID
+	ts := uint32(createdAt.Unix())
+	id[0], id[1], id[2], id[3] = byte(ts>>24), byte(ts>>16), byte(ts>>8), byte(ts)
+	for i := 0; i < 4; i++ {
+		id[4+i] = byte(seed >> (8 * i))
+	}
+	id[8], id[9], id[10], id[11] = byte(index>>24), byte(index>>16), byte(index>>8), byte(index)
+	return id
+}
+
+func (r *SandboxDriverRepository) Create(ctx context.Context, driver *models.Driver) (string, error) {
+	if driver == nil {
+		return "", errors.New("driver cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.drivers {
+		if models.PlateKey(existing.driver.Plate) == models.PlateKey(driver.Plate) {
+			return "", fmt.Errorf("%w: plate %s", ErrDriverAlreadyExists, driver.Plate)
+		}
+	}
+
+	now := r.now()
+	driver.CreatedAt = now
+	driver.UpdatedAt = now
+	if driver.ID.IsZero() {
+		driver.ID = primitive.NewObjectID()
+	}
+
+	created := &sandboxDriver{driver: *driver}
+	created.anchor = r.anchorFor(created, driver.Location, now)
+	r.drivers[driver.ID] = created
+
+	return driver.ID.Hex(), nil
+}
+
+func (r *SandboxDriverRepository) Update(ctx context.Context, id string, driver *models.Driver, fields ...models.DriverField) error {
+	if driver == nil {
+		return errors.New("driver cannot be nil")
+	}
+
+	objectID, err := parseDriverID(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.drivers[objectID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrDriverNotFound, id)
+	}
+
+	if slices.Contains(fields, models.DriverFieldPlate) {
+		for otherID, other := range r.drivers {
+			if otherID != objectID && models.PlateKey(other.driver.Plate) == models.PlateKey(driver.Plate) {
+				return fmt.Errorf("%w: plate %s", ErrDriverAlreadyExists, driver.Plate)
+			}
+		}
+	}
+
+	updated := existing.driver
+	for _, field := range fields {
+		if err := copyDriverField(&updated, driver, field); err != nil {
+			return err
+		}
+	}
+
+	// Moving the driver away from its simulated position re-anchors the
+	// orbit so the reported location matches the update right away.
+	now := r.now()
+	if slices.Contains(fields, models.DriverFieldLocation) &&
+		driver.Location.DistanceKm(r.positionAt(existing, now)) > sandboxRepositionToleranceKm {
+		existing.anchor = r.anchorFor(existing, driver.Location, now)
+	}
+
+	updated.UpdatedAt = now
+	existing.driver = updated
+
+	return nil
+}
+
+// copyDriverField copies field, as MongoDriverRepository.Update writes it,
+// from src to dst.
+func copyDriverField(dst, src *models.Driver, field models.DriverField) error {
+	switch field {
+	case models.DriverFieldFirstName:
+		dst.FirstName = src.FirstName
+	case models.DriverFieldLastName:
+		dst.LastName = src.LastName
+	case models.DriverFieldPlate:
+		dst.Plate = src.Plate
+	case models.DriverFieldTaxiType:
+		dst.TaxiType = src.TaxiType
+	case models.DriverFieldFleetID:
+		dst.FleetID = src.FleetID
+	case models.DriverFieldCarBrand:
+		dst.CarBrand = src.CarBrand
+	case models.DriverFieldCarModel:
+		dst.CarModel = src.CarModel
+	case models.DriverFieldCarColor:
+		dst.CarColor = src.CarColor
+	case models.DriverFieldBio:
+		dst.Bio = src.Bio
+	case models.DriverFieldPhotoURL:
+		dst.PhotoURL = src.PhotoURL
+	case models.DriverFieldVerified:
+		dst.Verified = src.Verified
+		dst.VerifiedAt = src.VerifiedAt
+	case models.DriverFieldLocation:
+		dst.Location = src.Location
+	case models.DriverFieldTraveledKm:
+		dst.TraveledKm = src.TraveledKm
+	case models.DriverFieldTaxInfo:
+		dst.TaxInfo = src.TaxInfo
+	case models.DriverFieldLocalizations:
+		dst.Localizations = src.Localizations
+	case models.DriverFieldLicenseClasses:
+		dst.LicenseClasses = src.LicenseClasses
+	case models.DriverFieldLanguages:
+		dst.Languages = src.Languages
+	case models.DriverFieldLocationRecordedAt:
+		dst.LocationRecordedAt = src.LocationRecordedAt
+	case models.DriverFieldEmail:
+		dst.Email = src.Email
+	case models.DriverFieldCustomFields:
+		dst.CustomFields = src.CustomFields
+	default:
+		return fmt.Errorf("unknown driver field %q", field)
+	}
+	return nil
+}
+
+func (r *SandboxDriverRepository) SetStatus(ctx context.Context, id string, status string, at time.Time) error {
+	objectID, err := parseDriverID(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.drivers[objectID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrDriverNotFound, id)
+	}
+
+	existing.driver.Status = status
+	existing.driver.StatusUpdatedAt = &at
+	existing.driver.UpdatedAt = at
+
+	return nil
+}
+
+func (r *SandboxDriverRepository) SetReviewStatus(ctx context.Context, id string, from, to, note string, at time.Time) error {
+	objectID, err := parseDriverID(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.drivers[objectID]
+	if !ok || existing.driver.EffectiveReviewStatus() != from {
+		return fmt.Errorf("%w: %s", ErrDriverReviewChanged, id)
+	}
+
+	existing.driver.ReviewStatus = to
+	existing.driver.ReviewNote = note
+	existing.driver.ReviewedAt = &at
+	existing.driver.UpdatedAt = at
+
+	return nil
+}
+
+func (r *SandboxDriverRepository) FindByID(ctx context.Context, id string) (*models.Driver, error) {
+	objectID, err := parseDriverID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	existing, ok := r.drivers[objectID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDriverNotFound, id)
+	}
+
+	return r.snapshot(existing, r.now()), nil
+}
+
+func (r *SandboxDriverRepository) FindAll(ctx context.Context, page, pageSize int, filter models.DriverListFilter) ([]models.Driver, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	all := make([]models.Driver, 0, len(r.drivers))
+	for _, existing := range r.drivers {
+		driver := r.snapshot(existing, now)
+		if filter.Matches(driver) {
+			all = append(all, *driver)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return filter.Less(&all[i], &all[j])
+	})
+
+	skip := (page - 1) * pageSize
+	if skip >= len(all) {
+		return []models.Driver{}, int64(len(all)), nil
+	}
+	end := skip + pageSize
+	if end > len(all) {
+		end = len(all)
+	}
+
+	return all[skip:end], int64(len(all)), nil
+}
+
+func (r *SandboxDriverRepository) FindNearby(ctx context.Context, lat, lon, radiusKm float64, filter models.NearbyFilter) ([]models.DriverWithDistance, error) {
+	if lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("%w: latitude must be between -90 and 90", ErrInvalidCoordinates)
+	}
+	if lon < -180 || lon > 180 {
+		return nil, fmt.Errorf("%w: longitude must be between -180 and 180", ErrInvalidCoordinates)
+	}
+	if radiusKm <= 0 {
+		return nil, fmt.Errorf("%w: radius must be positive", ErrInvalidRadius)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	center := models.Location{Lat: lat, Lon: lon}
+	var results []models.DriverWithDistance
+	for _, existing := range r.drivers {
+		driver := r.snapshot(existing, now)
+		if !filter.Matches(driver) {
+			continue
+		}
+
+		distance := center.DistanceKm(driver.Location)
+		if distance > radiusKm {
+			continue
+		}
+
+		results = append(results, models.DriverWithDistance{
+			Driver:     *driver,
+			DistanceKm: distance,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].DistanceKm < results[j].DistanceKm
+	})
+	if limit := filter.ResultLimit(); len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+func (r *SandboxDriverRepository) FindByPlate(ctx context.Context, plate string) (*models.Driver, error) {
+	if plate == "" {
+		return nil, errors.New("plate cannot be empty")
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key := models.PlateKey(plate)
+	for _, existing := range r.drivers {
+		if models.PlateKey(existing.driver.Plate) == key {
+			return r.snapshot(existing, r.now()), nil
+		}
+	}
+
+	return nil, ErrDriverNotFound
+}
+
+// Search scores drivers like the MongoDB text index: each term found in the
+// plate, last name or first name adds that field's weight.
+func (r *SandboxDriverRepository) Search(ctx context.Context, terms string, page, pageSize int) ([]models.Driver, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	words := searchWords(terms)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type match struct {
+		driver models.Driver
+		score  int
+	}
+	now := r.now()
+	var matches []match
+	for _, existing := range r.drivers {
+		driver := r.snapshot(existing, now)
+		fields := []struct {
+			words  []string
+			weight int
+		}{
+			{searchWords(driver.Plate), 3},
+			{searchWords(driver.LastName), 2},
+			{searchWords(driver.FirstName), 1},
+		}
+		score := 0
+		for _, word := range words {
+			for _, field := range fields {
+				for _, fieldWord := range field.words {
+					if fieldWord == word {
+						score += field.weight
+					}
+				}
+			}
+		}
+		if score > 0 {
+			matches = append(matches, match{driver: *driver, score: score})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].driver.ID.Hex() < matches[j].driver.ID.Hex()
+	})
+
+	drivers := []models.Driver{}
+	for i := (page - 1) * pageSize; i < len(matches) && len(drivers) < pageSize; i++ {
+		drivers = append(drivers, matches[i].driver)
+	}
+
+	return drivers, int64(len(matches)), nil
+}
+
+// searchWords splits text into lower-cased words the way a text index does.
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func (r *SandboxDriverRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := parseDriverID(id)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.drivers[objectID]; !ok {
+		return fmt.Errorf("%w: %s", ErrDriverNotFound, id)
+	}
+	delete(r.drivers, objectID)
+
+	return nil
+}
+
+func (r *SandboxDriverRepository) snapshot(existing *sandboxDriver, now time.Time) *models.Driver {
+	driver := existing.driver
+	driver.Location = r.positionAt(existing, now)
+	return &driver
+}
+
+// anchorFor returns the orbit center that places the driver exactly at
+// location at the given instant.
+func (r *SandboxDriverRepository) anchorFor(existing *sandboxDriver, location models.Location, now time.Time) models.Location {
+	angle := r.angleAt(existing, now)
+	return offsetLocation(location, -sandboxOrbitKm*math.Sin(angle), -sandboxOrbitKm*math.Cos(angle))
+}
+
+func (r *SandboxDriverRepository) angleAt(existing *sandboxDriver, now time.Time) float64 {
+	return existing.phase + 2*math.Pi*float64(now.UnixNano()%int64(sandboxOrbitPeriod))/float64(sandboxOrbitPeriod)
+}
+
+func (r *SandboxDriverRepository) positionAt(existing *sandboxDriver, now time.Time) models.Location {
+	angle := r.angleAt(existing, now)
+	return offsetLocation(existing.anchor, sandboxOrbitKm*math.Sin(angle), sandboxOrbitKm*math.Cos(angle))
+}
+
+func offsetLocation(origin models.Location, northKm, eastKm float64) models.Location {
+	lat := origin.Lat + northKm/kmPerDegreeLatitude
+	lon := origin.Lon + eastKm/(kmPerDegreeLatitude*math.Cos(origin.Lat*math.Pi/180))
+	return models.Location{
+		Lat: math.Round(lat*1e6) / 1e6,
+		Lon: math.Round(lon*1e6) / 1e6,
+	}
+}
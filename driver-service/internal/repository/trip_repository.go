@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TripRepository interface {
+	Create(ctx context.Context, trip *models.Trip) error
+	FindByID(ctx context.Context, id string) (*models.Trip, error)
+	Find(ctx context.Context, driverID, status string, limit int) ([]models.Trip, error)
+	// FindExpiredOffers returns trips whose pending offer expired before
+	// now, oldest first, up to limit.
+	FindExpiredOffers(ctx context.Context, now time.Time, limit int) ([]models.Trip, error)
+	// Update stores the trip if it has not changed since it was read. It
+	// fails with ErrTripConflict if it has, and with ErrTripDriverBusy if
+	// the driver already has an active trip.
+	Update(ctx context.Context, trip *models.Trip) error
+}
+
+type MongoTripRepository struct {
+	collection config.ScopedCollection
+}
+
+func NewMongoTripRepository(db *config.MongoDB) *MongoTripRepository {
+	return &MongoTripRepository{
+		collection: db.ScopedCollection("trips"),
+	}
+}
+
+func (r *MongoTripRepository) Create(ctx context.Context, trip *models.Trip) error {
+	if trip == nil {
+		return errors.New("trip cannot be nil")
+	}
+
+	if trip.ID.IsZero() {
+		trip.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.For(ctx).InsertOne(ctx, trip); err != nil {
+		return fmt.Errorf("failed to create trip: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoTripRepository) FindByID(ctx context.Context, id string) (*models.Trip, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrTripNotFound
+	}
+
+	var trip models.Trip
+	if err := r.collection.For(ctx).FindOne(ctx, bson.M{"_id": objectID}).Decode(&trip); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTripNotFound
+		}
+		return nil, fmt.Errorf("failed to find trip: %w", err)
+	}
+
+	return &trip, nil
+}
+
+func (r *MongoTripRepository) Find(ctx context.Context, driverID, status string, limit int) ([]models.Trip, error) {
+	filter := bson.M{}
+	if driverID != "" {
+		filter["driver_id"] = driverID
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.collection.For(ctx).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trips: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	trips := []models.Trip{}
+	if err := cursor.All(ctx, &trips); err != nil {
+		return nil, fmt.Errorf("failed to decode trips: %w", err)
+	}
+
+	return trips, nil
+}
+
+func (r *MongoTripRepository) FindExpiredOffers(ctx context.Context, now time.Time, limit int) ([]models.Trip, error) {
+	filter := bson.M{
+		"status":           models.TripDriverAssigned,
+		"offer.expires_at": bson.M{"$lt": now},
+	}
+	findOptions := options.Find().SetSort(bson.M{"offer.expires_at": 1}).SetLimit(int64(limit))
+
+	cursor, err := r.collection.For(ctx).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired trip offers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	trips := []models.Trip{}
+	if err := cursor.All(ctx, &trips); err != nil {
+		return nil, fmt.Errorf("failed to decode trips: %w", err)
+	}
+
+	return trips, nil
+}
+
+func (r *MongoTripRepository) Update(ctx context.Context, trip *models.Trip) error {
+	if trip == nil {
+		return errors.New("trip cannot be nil")
+	}
+
+	read := trip.Revision
+	trip.Revision++
+	result, err := r.collection.For(ctx).ReplaceOne(ctx, bson.M{"_id": trip.ID, "revision": read}, trip)
+	if err != nil {
+		trip.Revision = read
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrTripDriverBusy
+		}
+		return fmt.Errorf("failed to update trip: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		trip.Revision = read
+		return ErrTripConflict
+	}
+
+	return nil
+}
+
+func (r *MongoTripRepository) RequiredIndexes() []RequiredIndex {
+	return []RequiredIndex{
+		{
+			// One active trip per driver, even when two dispatchers
+			// assign the same driver at once
+			Collection: "trips",
+			Model: mongo.IndexModel{
+				Keys: bson.D{{Key: "driver_id", Value: 1}},
+				Options: options.Index().
+					SetName("trips_one_active_per_driver").
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"active": true}),
+			},
+		},
+		{
+			Collection: "trips",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "driver_id", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("trips_driver_id_created_at"),
+			},
+		},
+		{
+			Collection: "trips",
+			Model: mongo.IndexModel{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("trips_status_created_at"),
+			},
+		},
+		{
+			Collection: "trips",
+			Model: mongo.IndexModel{
+				Keys: bson.D{{Key: "offer.expires_at", Value: 1}},
+				Options: options.Index().
+					SetName("trips_offer_expires_at").
+					SetPartialFilterExpression(bson.M{"status": models.TripDriverAssigned}),
+			},
+		},
+	}
+}
@@ -0,0 +1,135 @@
+package selftest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/geocoding"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// probeLocation is in the Southern Ocean, where no rider searches, and has
+// distinct latitude and longitude so swapped coordinates are caught.
+var probeLocation = models.Location{Lat: -60.5, Lon: -30.25}
+
+// geocodingProbe is a central Istanbul point that always has an address.
+var geocodingProbe = models.Location{Lat: 41.0369, Lon: 28.9850}
+
+// DriverStoreChecks write a temporary driver, read it back, find it with a
+// geo query and delete it again. The driver is deleted even when a check in
+// between fails.
+func DriverStoreChecks(db *config.MongoDB, drivers repository.DriverRepository) []Check {
+	var probe *models.Driver
+
+	return []Check{
+		{
+			Name: "mongo_ping",
+			Run: func(ctx context.Context) error {
+				return db.For(ctx).PingWithContext(ctx)
+			},
+		},
+		{
+			Name: "mongo_write",
+			Run: func(ctx context.Context) error {
+				id := primitive.NewObjectID()
+				driver := &models.Driver{
+					ID:        id,
+					FirstName: "Self",
+					LastName:  "Test",
+					Plate:     "SELFTEST " + id.Hex(),
+					TaxiType:  models.TaxiTypeSari,
+					CarBrand:  "Probe",
+					CarModel:  "Probe",
+					Location:  probeLocation,
+				}
+				if _, err := drivers.Create(ctx, driver); err != nil {
+					return err
+				}
+				probe = driver
+				return nil
+			},
+		},
+		{
+			Name: "mongo_read",
+			Run: func(ctx context.Context) error {
+				if probe == nil {
+					return Skipped{Reason: "probe driver was not written"}
+				}
+				found, err := drivers.FindByID(ctx, probe.ID.Hex())
+				if err != nil {
+					return err
+				}
+				if found.Plate != probe.Plate || found.Location != probe.Location {
+					return errors.New("read back a different driver than was written")
+				}
+				return nil
+			},
+		},
+		{
+			Name: "mongo_geo_query",
+			Run: func(ctx context.Context) error {
+				if probe == nil {
+					return Skipped{Reason: "probe driver was not written"}
+				}
+				nearby, err := drivers.FindNearby(ctx, probeLocation.Lat, probeLocation.Lon, 1, models.NearbyFilter{})
+				if err != nil {
+					return err
+				}
+				for _, driver := range nearby {
+					if driver.ID == probe.ID {
+						return nil
+					}
+				}
+				return fmt.Errorf("probe driver not found within 1 km of its own location (%d results)", len(nearby))
+			},
+		},
+		{
+			Name: "mongo_delete",
+			Run: func(ctx context.Context) error {
+				if probe == nil {
+					return Skipped{Reason: "probe driver was not written"}
+				}
+				if err := drivers.Delete(ctx, probe.ID.Hex()); err != nil {
+					return err
+				}
+				probe = nil
+				return nil
+			},
+		},
+	}
+}
+
+// GeocodingCheck reverse-geocodes a fixed point through the configured
+// provider and its cache.
+func GeocodingCheck(provider geocoding.Provider) Check {
+	return Check{
+		Name: "geocoding",
+		Run: func(ctx context.Context) error {
+			if provider == nil {
+				return Skipped{Reason: "no geocoding provider configured"}
+			}
+			address, err := provider.ReverseGeocode(ctx, geocodingProbe.Lat, geocodingProbe.Lon)
+			if err != nil {
+				return err
+			}
+			if address == nil || address.FormattedAddress == "" {
+				return errors.New("provider returned an empty address")
+			}
+			return nil
+		},
+	}
+}
+
+// Unsupported reports a check for a dependency this service does not have.
+func Unsupported(name, reason string) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			return Skipped{Reason: reason}
+		},
+	}
+}
@@ -0,0 +1,101 @@
+// Package selftest exercises critical paths against the live dependencies
+// for post-deploy verification.
+package selftest
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// Skipped marks a check that cannot run in this deployment; it does not fail
+// the suite.
+type Skipped struct {
+	Reason string
+}
+
+func (s Skipped) Error() string {
+	return s.Reason
+}
+
+type Result struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+type Report struct {
+	Status     string    `json:"status"`
+	Tenant     string    `json:"tenant,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Checks     []Result  `json:"checks"`
+}
+
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Suite runs its checks in order, each with its own timeout. Checks may
+// depend on earlier ones; a check whose prerequisite failed should return
+// Skipped.
+type Suite struct {
+	checks  []Check
+	timeout time.Duration
+}
+
+func NewSuite(timeout time.Duration, checks ...Check) *Suite {
+	return &Suite{checks: checks, timeout: timeout}
+}
+
+func (s *Suite) Run(ctx context.Context) *Report {
+	report := &Report{
+		Status:    StatusPassed,
+		StartedAt: time.Now().UTC(),
+		Checks:    make([]Result, 0, len(s.checks)),
+	}
+
+	for _, check := range s.checks {
+		result := s.run(ctx, check)
+		if result.Status == StatusFailed {
+			report.Status = StatusFailed
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	report.DurationMs = time.Since(report.StartedAt).Milliseconds()
+	return report
+}
+
+func (s *Suite) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	started := time.Now()
+	err := check.Run(ctx)
+	result := Result{
+		Name:       check.Name,
+		Status:     StatusPassed,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+
+	var skipped Skipped
+	switch {
+	case errors.As(err, &skipped):
+		result.Status = StatusSkipped
+		result.Detail = skipped.Reason
+	case err != nil:
+		result.Status = StatusFailed
+		result.Detail = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// AnomalyService is the review queue for anomalies raised by the activity
+// analyzer.
+type AnomalyService interface {
+	List(ctx context.Context, status, severity string, limit int) ([]models.DriverAnomaly, error)
+	Review(ctx context.Context, id string, req *models.ReviewAnomalyRequest) (*models.DriverAnomaly, error)
+}
+
+type anomalyService struct {
+	anomalyRepo repository.AnomalyRepository
+	now         func() time.Time
+}
+
+func NewAnomalyService(anomalyRepo repository.AnomalyRepository) AnomalyService {
+	return &anomalyService{
+		anomalyRepo: anomalyRepo,
+		now:         time.Now,
+	}
+}
+
+func (s *anomalyService) List(ctx context.Context, status, severity string, limit int) ([]models.DriverAnomaly, error) {
+	if limit < 1 || limit > 500 {
+		limit = 100
+	}
+
+	return s.anomalyRepo.Find(ctx, status, severity, limit)
+}
+
+func (s *anomalyService) Review(ctx context.Context, id string, req *models.ReviewAnomalyRequest) (*models.DriverAnomaly, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	anomaly, err := s.anomalyRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrAnomalyNotFound) {
+			return nil, ErrAnomalyNotFound
+		}
+		return nil, err
+	}
+	if anomaly.Status != models.AnomalyOpen {
+		return nil, ErrAnomalyNotOpen
+	}
+
+	reviewedAt := s.now()
+	anomaly.Status = req.Status
+	anomaly.ReviewNote = req.Note
+	anomaly.ReviewedBy = req.ReviewedBy
+	anomaly.ReviewedAt = &reviewedAt
+
+	if err := s.anomalyRepo.Review(ctx, anomaly); err != nil {
+		if errors.Is(err, repository.ErrAnomalyConflict) {
+			return nil, ErrAnomalyNotOpen
+		}
+		return nil, err
+	}
+
+	return anomaly, nil
+}
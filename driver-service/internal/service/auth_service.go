@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/auth"
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyPasswordHash is compared against when the login name is unknown, so
+// unknown plates take as long to reject as wrong passwords.
+const dummyPasswordHash = "$2a$10$jJL2Wk4SPBHyMfgTzIkJNeRXGLnG.26NyHrDsjZryLVPywfACOufu"
+
+// AuthService logs drivers, dispatchers, fleet admins and admins in and
+// issues the JWTs the Auth middleware checks. Drivers log in by plate with the
+// password set through SetPassword; admins and dispatchers are configured with
+// AUTH_ADMINS and AUTH_DISPATCHERS; fleet admins are FleetAccountService
+// accounts.
+type AuthService interface {
+	Login(ctx context.Context, req *models.LoginRequest) (*models.TokenResponse, error)
+	Refresh(ctx context.Context, req *models.RefreshTokenRequest) (*models.TokenResponse, error)
+	// SetPassword sets a driver's password. Unless privileged, the driver's
+	// current password must be given when one is set.
+	SetPassword(ctx context.Context, driverID string, req *models.SetPasswordRequest, privileged bool) error
+	AuthenticateAccessToken(ctx context.Context, token string) (*auth.Claims, error)
+}
+
+type AuthConfig struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// Admins and Dispatchers map user names to bcrypt password hashes. A
+	// name in both is an admin.
+	Admins      map[string]string
+	Dispatchers map[string]string
+}
+
+type authService struct {
+	signer      *auth.Signer
+	credentials repository.CredentialRepository
+	fleets      repository.FleetAccountRepository
+	drivers     DriverService
+	cfg         AuthConfig
+	now         func() time.Time
+}
+
+// NewAuthService returns the auth service; with a nil signer logins fail with
+// ErrAuthDisabled.
+func NewAuthService(signer *auth.Signer, credentials repository.CredentialRepository, fleets repository.FleetAccountRepository, drivers DriverService, cfg AuthConfig) AuthService {
+	return &authService{
+		signer:      signer,
+		credentials: credentials,
+		fleets:      fleets,
+		drivers:     drivers,
+		cfg:         cfg,
+		now:         time.Now,
+	}
+}
+
+func (s *authService) Login(ctx context.Context, req *models.LoginRequest) (*models.TokenResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if s.signer == nil {
+		return nil, ErrAuthDisabled
+	}
+
+	if req.Username != "" {
+		role, hash, ok := s.staffAccount(req.Username)
+		if !ok {
+			return s.loginFleetAccount(ctx, req)
+		}
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(req.Password)) != nil {
+			return nil, ErrInvalidCredentials
+		}
+		return s.issue(ctx, auth.Grant{Subject: req.Username, Role: role})
+	}
+
+	credential, err := s.driverCredential(ctx, req.Plate)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil {
+		bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(req.Password))
+		return nil, ErrInvalidCredentials
+	}
+	if bcrypt.CompareHashAndPassword([]byte(credential.PasswordHash), []byte(req.Password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.issue(ctx, auth.Grant{Subject: credential.DriverID.Hex(), Role: auth.RoleDriver})
+}
+
+func (s *authService) loginFleetAccount(ctx context.Context, req *models.LoginRequest) (*models.TokenResponse, error) {
+	account, err := s.fleets.FindByUsername(ctx, req.Username)
+	if err != nil && !errors.Is(err, repository.ErrFleetAccountNotFound) {
+		return nil, err
+	}
+	if account == nil {
+		bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(req.Password))
+		return nil, ErrInvalidCredentials
+	}
+	if bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(req.Password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.issue(ctx, fleetGrant(account))
+}
+
+func fleetGrant(account *models.FleetAccount) auth.Grant {
+	return auth.Grant{
+		Subject:      account.ID.Hex(),
+		Role:         auth.RoleFleetAdmin,
+		FleetID:      account.FleetID,
+		Capabilities: account.GrantedCapabilities(),
+	}
+}
+
+// staffAccount returns the role and password hash of a configured admin or
+// dispatcher.
+func (s *authService) staffAccount(username string) (string, string, bool) {
+	if hash, ok := s.cfg.Admins[username]; ok {
+		return auth.RoleAdmin, hash, true
+	}
+	if hash, ok := s.cfg.Dispatchers[username]; ok {
+		return auth.RoleDispatcher, hash, true
+	}
+	return "", "", false
+}
+
+// driverCredential returns the credential of the driver holding plate, or nil
+// when there is no such driver or they have no password.
+func (s *authService) driverCredential(ctx context.Context, plate string) (*models.DriverCredential, error) {
+	driver, err := s.drivers.GetDriverByPlate(ctx, plate)
+	if err != nil {
+		if errors.Is(err, ErrDriverNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	credential, err := s.credentials.FindByDriver(ctx, driver.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCredentialNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return credential, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair. Driver tokens
+// issued before the last password change, or for a deleted driver, and staff
+// tokens for accounts no longer configured with that role are refused. Fleet
+// admin tokens are reissued with the account's current capabilities.
+func (s *authService) Refresh(ctx context.Context, req *models.RefreshTokenRequest) (*models.TokenResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if s.signer == nil {
+		return nil, ErrAuthDisabled
+	}
+
+	claims, err := s.signer.Verify(req.RefreshToken, auth.TypeRefresh, s.now())
+	if err != nil || claims.TenantID != config.TenantFromContext(ctx) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	switch claims.Role {
+	case auth.RoleAdmin, auth.RoleDispatcher:
+		if role, _, ok := s.staffAccount(claims.Subject); !ok || role != claims.Role {
+			return nil, ErrInvalidRefreshToken
+		}
+	case auth.RoleFleetAdmin:
+		accountID, err := primitive.ObjectIDFromHex(claims.Subject)
+		if err != nil {
+			return nil, ErrInvalidRefreshToken
+		}
+		account, err := s.fleets.FindByID(ctx, accountID)
+		if err != nil {
+			if errors.Is(err, repository.ErrFleetAccountNotFound) {
+				return nil, ErrInvalidRefreshToken
+			}
+			return nil, err
+		}
+		if claims.IssuedAtTime().Before(account.PasswordChangedAt.Truncate(time.Second)) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return s.issue(ctx, fleetGrant(account))
+	case auth.RoleDriver:
+		driverID, err := primitive.ObjectIDFromHex(claims.Subject)
+		if err != nil {
+			return nil, ErrInvalidRefreshToken
+		}
+		credential, err := s.credentials.FindByDriver(ctx, driverID)
+		if err != nil {
+			if errors.Is(err, repository.ErrCredentialNotFound) {
+				return nil, ErrInvalidRefreshToken
+			}
+			return nil, err
+		}
+		if claims.IssuedAtTime().Before(credential.PasswordChangedAt.Truncate(time.Second)) {
+			return nil, ErrInvalidRefreshToken
+		}
+	default:
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return s.issue(ctx, auth.Grant{Subject: claims.Subject, Role: claims.Role})
+}
+
+func (s *authService) SetPassword(ctx context.Context, driverID string, req *models.SetPasswordRequest, privileged bool) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	driver, err := s.drivers.GetDriverByID(ctx, driverID)
+	if err != nil {
+		return err
+	}
+
+	credential, err := s.credentials.FindByDriver(ctx, driver.ID)
+	if err != nil && !errors.Is(err, repository.ErrCredentialNotFound) {
+		return err
+	}
+
+	now := s.now()
+	if credential == nil {
+		credential = &models.DriverCredential{DriverID: driver.ID, CreatedAt: now}
+	} else if !privileged {
+		if req.CurrentPassword == "" {
+			return ErrPasswordRequired
+		}
+		if bcrypt.CompareHashAndPassword([]byte(credential.PasswordHash), []byte(req.CurrentPassword)) != nil {
+			return ErrInvalidCredentials
+		}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	credential.PasswordHash = string(hash)
+	credential.PasswordChangedAt = now
+
+	return s.credentials.Save(ctx, credential)
+}
+
+// AuthenticateAccessToken verifies an access token. Its tenant is left to the
+// caller to compare with the request's.
+func (s *authService) AuthenticateAccessToken(ctx context.Context, token string) (*auth.Claims, error) {
+	if s.signer == nil {
+		return nil, ErrAuthDisabled
+	}
+
+	claims, err := s.signer.Verify(token, auth.TypeAccess, s.now())
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+	return claims, nil
+}
+
+func (s *authService) issue(ctx context.Context, grant auth.Grant) (*models.TokenResponse, error) {
+	now := s.now()
+	grant.TenantID = config.TenantFromContext(ctx)
+
+	accessToken, err := s.signer.Issue(grant, auth.TypeAccess, s.cfg.AccessTokenTTL, now)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := s.signer.Issue(grant, auth.TypeRefresh, s.cfg.RefreshTokenTTL, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenResponse{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(s.cfg.AccessTokenTTL.Seconds()),
+		RefreshExpiresIn: int64(s.cfg.RefreshTokenTTL.Seconds()),
+		Role:             grant.Role,
+		Subject:          grant.Subject,
+		FleetID:          grant.FleetID,
+		Capabilities:     grant.Capabilities,
+	}, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/geohash"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// forecastCurrentDecay is how much of the gap between a zone's current and
+// usual driver count carries over into each following hour.
+const forecastCurrentDecay = 0.5
+
+const week = 7 * 24 * time.Hour
+
+// AvailabilityForecastService forecasts how many drivers each zone will have
+// over the coming hours, so dispatchers can arrange coverage for peaks.
+type AvailabilityForecastService interface {
+	Forecast(ctx context.Context, req models.AvailabilityForecastRequest) (*models.AvailabilityForecast, error)
+}
+
+// ZoneDriverCounter counts the drivers currently inside a geohash cell.
+type ZoneDriverCounter interface {
+	CountInZone(ctx context.Context, zone string) (*models.ZoneDriverCount, error)
+}
+
+type availabilityForecastService struct {
+	drivers    ZoneDriverCounter
+	history    repository.LocationHistoryRepository
+	historyTTL time.Duration
+	now        func() time.Time
+}
+
+// NewAvailabilityForecastService creates the forecast service. historyTTL is
+// how long location history is kept, which bounds the lookback; zero means
+// it is kept forever.
+func NewAvailabilityForecastService(drivers ZoneDriverCounter, history repository.LocationHistoryRepository, historyTTL time.Duration) AvailabilityForecastService {
+	return &availabilityForecastService{
+		drivers:    drivers,
+		history:    history,
+		historyTTL: historyTTL,
+		now:        time.Now,
+	}
+}
+
+// Forecast estimates each hour from the drivers seen in the zone in the same
+// hour of the week over the lookback, then shifts the near hours by the gap
+// between the zone's current online drivers and its usual count for now.
+func (s *availabilityForecastService) Forecast(ctx context.Context, req models.AvailabilityForecastRequest) (*models.AvailabilityForecast, error) {
+	if err := s.validate(&req); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	start := now.Truncate(time.Hour)
+	forecast := &models.AvailabilityForecast{
+		GeneratedAt: now,
+		Weeks:       req.Weeks,
+		Zones:       make([]models.ZoneAvailabilityForecast, 0, len(req.Zones)),
+	}
+
+	for _, zone := range req.Zones {
+		zoneForecast, err := s.forecastZone(ctx, zone, start, req.Hours, req.Weeks)
+		if err != nil {
+			return nil, err
+		}
+		forecast.Zones = append(forecast.Zones, *zoneForecast)
+	}
+
+	return forecast, nil
+}
+
+func (s *availabilityForecastService) validate(req *models.AvailabilityForecastRequest) error {
+	if len(req.Zones) == 0 {
+		return fmt.Errorf("%w: at least one zone is required", ErrValidationFailed)
+	}
+	if len(req.Zones) > models.MaxForecastZones {
+		return fmt.Errorf("%w: at most %d zones are allowed", ErrValidationFailed, models.MaxForecastZones)
+	}
+	for _, zone := range req.Zones {
+		if len(zone) < models.MinForecastZonePrecision || len(zone) > models.MaxForecastZonePrecision || !geohash.Valid(zone) {
+			return fmt.Errorf("%w: zone %q must be a geohash of %d to %d characters", ErrValidationFailed, zone, models.MinForecastZonePrecision, models.MaxForecastZonePrecision)
+		}
+	}
+
+	if req.Hours == 0 {
+		req.Hours = models.DefaultForecastHours
+	}
+	if req.Hours < 1 || req.Hours > models.MaxForecastHours {
+		return fmt.Errorf("%w: hours must be between 1 and %d", ErrValidationFailed, models.MaxForecastHours)
+	}
+
+	maxWeeks := s.maxWeeks()
+	if maxWeeks < 1 {
+		return errors.New("location history is not kept long enough to forecast from")
+	}
+	if req.Weeks == 0 {
+		req.Weeks = models.DefaultForecastWeeks
+		if req.Weeks > maxWeeks {
+			req.Weeks = maxWeeks
+		}
+	}
+	if req.Weeks < 1 || req.Weeks > maxWeeks {
+		return fmt.Errorf("%w: weeks must be between 1 and %d", ErrValidationFailed, maxWeeks)
+	}
+
+	return nil
+}
+
+// maxWeeks is the longest lookback the kept location history covers.
+func (s *availabilityForecastService) maxWeeks() int {
+	if s.historyTTL <= 0 || s.historyTTL >= models.MaxForecastWeeks*week {
+		return models.MaxForecastWeeks
+	}
+	return int(s.historyTTL / week)
+}
+
+func (s *availabilityForecastService) forecastZone(ctx context.Context, zone string, start time.Time, hours, weeks int) (*models.ZoneAvailabilityForecast, error) {
+	current, err := s.drivers.CountInZone(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count drivers in zone %s: %w", zone, err)
+	}
+
+	// The same hours of every past week, from the oldest week to the last
+	box := geohash.Decode(zone)
+	from := start.Add(-time.Duration(weeks) * week)
+	to := start.Add(-week + time.Duration(hours)*time.Hour)
+	counts, err := s.history.CountDriversByHour(ctx, box, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load driver history for zone %s: %w", zone, err)
+	}
+	byHour := make(map[int64]int, len(counts))
+	for _, count := range counts {
+		byHour[count.Hour.Unix()] = count.Drivers
+	}
+
+	lat, lon := box.Center()
+	forecast := &models.ZoneAvailabilityForecast{
+		Zone:    zone,
+		Center:  models.Location{Lat: lat, Lon: lon},
+		Current: *current,
+		Hours:   make([]models.HourlyAvailability, hours),
+	}
+
+	var gap float64
+	for h := 0; h < hours; h++ {
+		startsAt := start.Add(time.Duration(h) * time.Hour)
+		total := 0
+		for k := 1; k <= weeks; k++ {
+			total += byHour[startsAt.Add(-time.Duration(k)*week).Unix()]
+		}
+		historical := float64(total) / float64(weeks)
+		if h == 0 {
+			gap = float64(current.Online) - historical
+		}
+
+		expected := historical + gap*math.Pow(forecastCurrentDecay, float64(h))
+		forecast.Hours[h] = models.HourlyAvailability{
+			StartsAt:   startsAt,
+			Historical: roundTenth(historical),
+			Expected:   roundTenth(math.Max(expected, 0)),
+		}
+	}
+
+	return forecast, nil
+}
+
+func roundTenth(value float64) float64 {
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// ChangeRequestService holds driver edits to regulated fields until an admin
+// approves them. The live driver document only changes on approval.
+type ChangeRequestService interface {
+	Submit(ctx context.Context, driverID string, req *models.SubmitChangeRequest) (*models.DriverChangeRequest, error)
+	ListForDriver(ctx context.Context, driverID string) ([]models.DriverChangeRequest, error)
+	ListByStatus(ctx context.Context, status string, limit int) ([]models.DriverChangeRequest, error)
+	Approve(ctx context.Context, id string, req *models.ReviewChangeRequest) (*models.DriverChangeRequest, error)
+	Reject(ctx context.Context, id string, req *models.ReviewChangeRequest) (*models.DriverChangeRequest, error)
+}
+
+type changeRequestService struct {
+	changeRequestRepo repository.ChangeRequestRepository
+	driverRepo        repository.DriverRepository
+	licensePolicy     *LicenseClassPolicy
+	now               func() time.Time
+}
+
+func NewChangeRequestService(changeRequestRepo repository.ChangeRequestRepository, driverRepo repository.DriverRepository, licensePolicy *LicenseClassPolicy) ChangeRequestService {
+	return &changeRequestService{
+		changeRequestRepo: changeRequestRepo,
+		driverRepo:        driverRepo,
+		licensePolicy:     licensePolicy,
+		now:               time.Now,
+	}
+}
+
+func (s *changeRequestService) Submit(ctx context.Context, driverID string, req *models.SubmitChangeRequest) (*models.DriverChangeRequest, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	driver, err := s.findDriver(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := s.effectiveChanges(driver, req.RegulatedFields)
+	if changes.IsEmpty() {
+		return nil, ErrNoChanges
+	}
+	if changes.Plate != nil {
+		if err := s.checkPlateAvailable(ctx, driverID, *changes.Plate); err != nil {
+			return nil, err
+		}
+	}
+	if changes.TaxiType != nil {
+		// Only admins can override the license check, so fail early
+		candidate := *driver
+		candidate.TaxiType = *changes.TaxiType
+		if err := s.licensePolicy.Check(ctx, &candidate, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	pending, err := s.changeRequestRepo.HasPending(ctx, driver.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, ErrChangeRequestPending
+	}
+
+	request := &models.DriverChangeRequest{
+		DriverID:  driver.ID,
+		Changes:   changes,
+		Previous:  currentValues(driver, changes),
+		Reason:    req.Reason,
+		Status:    models.ChangeRequestPending,
+		CreatedAt: s.now(),
+	}
+	if err := s.changeRequestRepo.Create(ctx, request); err != nil {
+		if errors.Is(err, repository.ErrChangeRequestConflict) {
+			return nil, ErrChangeRequestPending
+		}
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (s *changeRequestService) ListForDriver(ctx context.Context, driverID string) ([]models.DriverChangeRequest, error) {
+	return s.changeRequestRepo.FindByDriver(ctx, driverID)
+}
+
+func (s *changeRequestService) ListByStatus(ctx context.Context, status string, limit int) ([]models.DriverChangeRequest, error) {
+	return s.changeRequestRepo.FindByStatus(ctx, status, limit)
+}
+
+// Approve claims the request first so two admins cannot both apply it, then
+// writes the changes to the driver. If the write fails the request is put
+// back to pending.
+func (s *changeRequestService) Approve(ctx context.Context, id string, req *models.ReviewChangeRequest) (*models.DriverChangeRequest, error) {
+	request, err := s.review(ctx, id, req, models.ChangeRequestApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(ctx, request); err != nil {
+		reverted := *request
+		reverted.Status = models.ChangeRequestPending
+		reverted.ReviewNote, reverted.ReviewedBy, reverted.ReviewedAt = "", "", nil
+		if revertErr := s.changeRequestRepo.Transition(ctx, &reverted, models.ChangeRequestApproved); revertErr != nil {
+			logging.FromContext(ctx).Error("Failed to revert change request to pending", "change_request_id", request.ID.Hex(), "error", revertErr)
+		}
+		return nil, err
+	}
+
+	notifyChangeRequestOutcome(ctx, request)
+	return request, nil
+}
+
+func (s *changeRequestService) Reject(ctx context.Context, id string, req *models.ReviewChangeRequest) (*models.DriverChangeRequest, error) {
+	request, err := s.review(ctx, id, req, models.ChangeRequestRejected)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyChangeRequestOutcome(ctx, request)
+	return request, nil
+}
+
+func (s *changeRequestService) review(ctx context.Context, id string, req *models.ReviewChangeRequest, status string) (*models.DriverChangeRequest, error) {
+	if req == nil {
+		req = &models.ReviewChangeRequest{}
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	request, err := s.changeRequestRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrChangeRequestNotFound) {
+			return nil, ErrChangeRequestNotFound
+		}
+		return nil, err
+	}
+	if request.Status != models.ChangeRequestPending {
+		return nil, ErrChangeRequestNotPending
+	}
+
+	reviewedAt := s.now()
+	request.Status = status
+	request.ReviewNote = req.Note
+	request.ReviewedBy = req.ReviewedBy
+	request.ReviewedAt = &reviewedAt
+
+	if err := s.changeRequestRepo.Transition(ctx, request, models.ChangeRequestPending); err != nil {
+		if errors.Is(err, repository.ErrChangeRequestConflict) {
+			return nil, ErrChangeRequestNotPending
+		}
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (s *changeRequestService) apply(ctx context.Context, request *models.DriverChangeRequest) error {
+	driverID := request.DriverID.Hex()
+	driver, err := s.findDriver(ctx, driverID)
+	if err != nil {
+		return err
+	}
+
+	var fields []models.DriverField
+	if request.Changes.Plate != nil {
+		if err := s.checkPlateAvailable(ctx, driverID, *request.Changes.Plate); err != nil {
+			return err
+		}
+		driver.Plate = *request.Changes.Plate
+		fields = append(fields, models.DriverFieldPlate)
+	}
+	if request.Changes.TaxiType != nil {
+		driver.TaxiType = *request.Changes.TaxiType
+		fields = append(fields, models.DriverFieldTaxiType)
+		if err := s.licensePolicy.Check(ctx, driver, nil); err != nil {
+			return err
+		}
+	}
+	driver.UpdatedAt = s.now()
+
+	if err := s.driverRepo.Update(ctx, driverID, driver, fields...); err != nil {
+		return fmt.Errorf("failed to update driver: %w", err)
+	}
+
+	return nil
+}
+
+func (s *changeRequestService) findDriver(ctx context.Context, driverID string) (*models.Driver, error) {
+	driver, err := s.driverRepo.FindByID(ctx, driverID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDriverNotFound) {
+			return nil, ErrDriverNotFound
+		}
+		return nil, fmt.Errorf("failed to find driver: %w", err)
+	}
+	return driver, nil
+}
+
+func (s *changeRequestService) checkPlateAvailable(ctx context.Context, driverID, plate string) error {
+	existing, err := s.driverRepo.FindByPlate(ctx, plate)
+	if err != nil {
+		if errors.Is(err, repository.ErrDriverNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to check plate: %w", err)
+	}
+	if existing.ID.Hex() != driverID {
+		return ErrPlateTaken
+	}
+	return nil
+}
+
+// effectiveChanges drops requested values that already match the driver.
+func (s *changeRequestService) effectiveChanges(driver *models.Driver, requested models.RegulatedFields) models.RegulatedFields {
+	var changes models.RegulatedFields
+	if requested.Plate != nil && *requested.Plate != driver.Plate {
+		changes.Plate = requested.Plate
+	}
+	if requested.TaxiType != nil && *requested.TaxiType != driver.TaxiType {
+		changes.TaxiType = requested.TaxiType
+	}
+	return changes
+}
+
+func currentValues(driver *models.Driver, changes models.RegulatedFields) models.RegulatedFields {
+	var previous models.RegulatedFields
+	if changes.Plate != nil {
+		plate := driver.Plate
+		previous.Plate = &plate
+	}
+	if changes.TaxiType != nil {
+		taxiType := driver.TaxiType
+		previous.TaxiType = &taxiType
+	}
+	return previous
+}
+
+func notifyChangeRequestOutcome(ctx context.Context, request *models.DriverChangeRequest) {
+	logging.FromContext(ctx).Info("Change request reviewed",
+		"change_request_id", request.ID.Hex(), "driver_id", request.DriverID.Hex(), "status", request.Status, "note", request.ReviewNote)
+}
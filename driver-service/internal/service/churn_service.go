@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/alerting"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ChurnService compares active drivers week over week per fleet and zone,
+// reports who went inactive and alerts on zones losing drivers fast.
+type ChurnService interface {
+	// Report compares the last seven days with the seven before, for one
+	// fleet or, with "", all of them.
+	Report(ctx context.Context, fleetID string, zonePrecision int) (*models.ChurnReport, error)
+	// CheckAlerts raises an alert for each fleet and zone over the
+	// threshold that was not alerted this week yet, and returns how many
+	// it raised.
+	CheckAlerts(ctx context.Context) (int, error)
+	ListAlerts(ctx context.Context, fleetID string, limit int) ([]models.ChurnAlert, error)
+}
+
+// DriverBatchFinder loads drivers by ID, skipping IDs with no driver.
+type DriverBatchFinder interface {
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Driver, error)
+}
+
+// ChurnConfig tunes churn alerts. A fleet and zone is alerted once its
+// active drivers fell by Threshold (0.2 for a fifth) or more, provided it had
+// at least MinDrivers the week before; by twice Threshold the alert is
+// critical.
+type ChurnConfig struct {
+	Threshold     float64
+	MinDrivers    int
+	ZonePrecision int
+}
+
+type churnService struct {
+	alertRepo   repository.ChurnAlertRepository
+	history     repository.LocationHistoryRepository
+	drivers     DriverBatchFinder
+	licenseRepo repository.LicenseRepository
+	notifier    alerting.Notifier
+	cfg         ChurnConfig
+	now         func() time.Time
+}
+
+// NewChurnService creates the churn service. Activity comes from location
+// history, so it needs LOCATION_HISTORY_TTL to cover two weeks.
+func NewChurnService(alertRepo repository.ChurnAlertRepository, history repository.LocationHistoryRepository, drivers DriverBatchFinder, licenseRepo repository.LicenseRepository, notifier alerting.Notifier, cfg ChurnConfig) ChurnService {
+	return &churnService{
+		alertRepo:   alertRepo,
+		history:     history,
+		drivers:     drivers,
+		licenseRepo: licenseRepo,
+		notifier:    notifier,
+		cfg:         cfg,
+		now:         time.Now,
+	}
+}
+
+func (s *churnService) Report(ctx context.Context, fleetID string, zonePrecision int) (*models.ChurnReport, error) {
+	if zonePrecision < models.MinChurnZonePrecision || zonePrecision > models.MaxChurnZonePrecision {
+		return nil, fmt.Errorf("%w: zone precision must be between %d and %d", ErrValidationFailed, models.MinChurnZonePrecision, models.MaxChurnZonePrecision)
+	}
+
+	now := s.now()
+	report := &models.ChurnReport{
+		GeneratedAt:   now,
+		FleetID:       fleetID,
+		ZonePrecision: zonePrecision,
+		PreviousFrom:  now.Add(-2 * week),
+		CurrentFrom:   now.Add(-week),
+		To:            now,
+		Groups:        []models.ChurnGroup{},
+		Drivers:       []models.ChurnedDriver{},
+	}
+
+	previous, err := s.history.LastSeenByDriver(ctx, report.PreviousFrom, report.CurrentFrom)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.history.LastSeenByDriver(ctx, report.CurrentFrom, report.To)
+	if err != nil {
+		return nil, err
+	}
+
+	lastSeen := make(map[primitive.ObjectID]time.Time, len(previous))
+	ids := make([]primitive.ObjectID, 0, len(previous)+len(current))
+	for _, activity := range previous {
+		lastSeen[activity.DriverID] = activity.LastSeen
+		ids = append(ids, activity.DriverID)
+	}
+	active := make(map[primitive.ObjectID]bool, len(current))
+	for _, activity := range current {
+		active[activity.DriverID] = true
+		if _, ok := lastSeen[activity.DriverID]; !ok {
+			ids = append(ids, activity.DriverID)
+		}
+	}
+
+	drivers, err := s.drivers.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[[2]string]*models.ChurnGroup)
+	for i := range drivers {
+		driver := &drivers[i]
+		if fleetID != "" && driver.FleetID != fleetID {
+			continue
+		}
+
+		zone := driver.Geohash
+		if len(zone) > zonePrecision {
+			zone = zone[:zonePrecision]
+		}
+		key := [2]string{driver.FleetID, zone}
+		group, ok := groups[key]
+		if !ok {
+			group = &models.ChurnGroup{FleetID: driver.FleetID, Zone: zone}
+			groups[key] = group
+		}
+
+		seen, wasActive := lastSeen[driver.ID]
+		isActive := active[driver.ID]
+		if wasActive {
+			group.Previous++
+		}
+		if isActive {
+			group.Current++
+		}
+		switch {
+		case wasActive && !isActive:
+			group.Churned++
+			report.Drivers = append(report.Drivers, models.ChurnedDriver{
+				ID:           driver.ID.Hex(),
+				FirstName:    driver.FirstName,
+				LastName:     driver.LastName,
+				FleetID:      driver.FleetID,
+				Zone:         zone,
+				LastSeen:     seen,
+				Status:       driver.Status,
+				ReviewStatus: driver.ReviewStatus,
+				Reason:       s.churnReason(ctx, driver, now),
+			})
+		case isActive && !wasActive:
+			group.Joined++
+		}
+	}
+
+	for _, group := range groups {
+		if group.Previous > 0 {
+			group.ChangeRate = float64(group.Current-group.Previous) / float64(group.Previous)
+		}
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.ChangeRate != b.ChangeRate {
+			return a.ChangeRate < b.ChangeRate
+		}
+		if a.FleetID != b.FleetID {
+			return a.FleetID < b.FleetID
+		}
+		return a.Zone < b.Zone
+	})
+	sort.Slice(report.Drivers, func(i, j int) bool {
+		return report.Drivers[i].LastSeen.After(report.Drivers[j].LastSeen)
+	})
+
+	return report, nil
+}
+
+// churnReason tells why the driver went inactive, if the service knows:
+// they were suspended, lost their verification or their license expired.
+func (s *churnService) churnReason(ctx context.Context, driver *models.Driver, now time.Time) string {
+	if driver.ReviewStatus == models.DriverReviewSuspended {
+		return models.ChurnReasonSuspended
+	}
+	if !driver.Verified {
+		return models.ChurnReasonUnverified
+	}
+
+	license, err := s.licenseRepo.FindByDriver(ctx, driver.ID.Hex())
+	if err != nil {
+		if !errors.Is(err, repository.ErrLicenseNotFound) {
+			logging.FromContext(ctx).Warn("Failed to check license of churned driver", "driver_id", driver.ID.Hex(), "error", err)
+		}
+		return ""
+	}
+	if expiresAt, err := time.Parse("2006-01-02", license.ExpiresAt.Value); err == nil && expiresAt.Before(now) {
+		return models.ChurnReasonLicenseExpired
+	}
+	return ""
+}
+
+func (s *churnService) CheckAlerts(ctx context.Context) (int, error) {
+	report, err := s.Report(ctx, "", s.cfg.ZonePrecision)
+	if err != nil {
+		return 0, err
+	}
+
+	weekOf := startOfWeek(report.GeneratedAt)
+	raised := 0
+	for _, group := range report.Groups {
+		if group.Previous < s.cfg.MinDrivers || group.ChangeRate > -s.cfg.Threshold {
+			continue
+		}
+
+		severity := alerting.SeverityWarning
+		if group.ChangeRate <= -2*s.cfg.Threshold {
+			severity = alerting.SeverityCritical
+		}
+		alert := &models.ChurnAlert{
+			FleetID:    group.FleetID,
+			Zone:       group.Zone,
+			WeekOf:     weekOf,
+			Severity:   severity,
+			Previous:   group.Previous,
+			Current:    group.Current,
+			Churned:    group.Churned,
+			ChangeRate: group.ChangeRate,
+			CreatedAt:  report.GeneratedAt,
+		}
+		if err := s.alertRepo.Create(ctx, alert); err != nil {
+			if errors.Is(err, repository.ErrChurnAlertExists) {
+				continue
+			}
+			return raised, err
+		}
+		raised++
+
+		fleet := group.FleetID
+		if fleet == "" {
+			fleet = "no fleet"
+		}
+		err := s.notifier.Notify(ctx, alerting.Alert{
+			Name:     "driver_churn:" + group.FleetID + ":" + group.Zone,
+			Severity: severity,
+			Message: fmt.Sprintf("active drivers of %s in zone %s fell %.0f%% week over week (%d to %d)",
+				fleet, group.Zone, -group.ChangeRate*100, group.Previous, group.Current),
+			Details: map[string]interface{}{
+				"fleet_id":    group.FleetID,
+				"zone":        group.Zone,
+				"previous":    group.Previous,
+				"current":     group.Current,
+				"churned":     group.Churned,
+				"change_rate": group.ChangeRate,
+			},
+			FiredAt: report.GeneratedAt,
+		})
+		if err != nil {
+			// The alert stays listed even if the webhook missed it
+			logging.FromContext(ctx).Error("Failed to send churn alert", "fleet_id", group.FleetID, "zone", group.Zone, "error", err)
+		}
+	}
+
+	return raised, nil
+}
+
+func (s *churnService) ListAlerts(ctx context.Context, fleetID string, limit int) ([]models.ChurnAlert, error) {
+	if limit < 1 || limit > models.MaxChurnAlertLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidationFailed, models.MaxChurnAlertLimit)
+	}
+	return s.alertRepo.List(ctx, fleetID, limit)
+}
+
+// startOfWeek returns midnight UTC of the Monday starting t's week.
+func startOfWeek(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
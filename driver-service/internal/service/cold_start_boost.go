@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ColdStartBoostConfig bounds the boost: it lasts Duration from activation,
+// covers at most Cap assignments, and only lets a driver jump ahead of
+// candidates at most MaxExtraKm closer to the rider.
+type ColdStartBoostConfig struct {
+	Duration   time.Duration
+	Cap        int
+	MaxExtraKm float64
+}
+
+// ColdStartBoost moves newly activated drivers to the front of the ranking
+// so they get their first rides sooner. A nil boost, or one with a zero
+// Duration or Cap, changes nothing.
+type ColdStartBoost struct {
+	cfg   ColdStartBoostConfig
+	usage repository.BoostRepository
+	now   func() time.Time
+}
+
+func NewColdStartBoost(cfg ColdStartBoostConfig, usage repository.BoostRepository) *ColdStartBoost {
+	return &ColdStartBoost{
+		cfg:   cfg,
+		usage: usage,
+		now:   time.Now,
+	}
+}
+
+func (b *ColdStartBoost) enabled() bool {
+	return b != nil && b.cfg.Duration > 0 && b.cfg.Cap > 0
+}
+
+// Apply moves the best ranked boost-eligible driver to the front and reports
+// whether it did. Failing to read usage only skips the boost, since dispatch
+// must not fail because of it.
+func (b *ColdStartBoost) Apply(ctx context.Context, ranked []models.DriverWithDistance) ([]models.DriverWithDistance, bool) {
+	if !b.enabled() || len(ranked) < 2 {
+		return ranked, false
+	}
+
+	now := b.now()
+	limitKm := ranked[0].DistanceKm + b.cfg.MaxExtraKm
+	var candidates []primitive.ObjectID
+	for _, driver := range ranked[1:] {
+		if driver.DistanceKm <= limitKm && now.Before(driver.ActivatedAt().Add(b.cfg.Duration)) {
+			candidates = append(candidates, driver.ID)
+		}
+	}
+	if len(candidates) == 0 {
+		return ranked, false
+	}
+
+	usage, err := b.usage.FindUsage(ctx, candidates)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Skipping cold-start boost", "error", err)
+		return ranked, false
+	}
+
+	for i, driver := range ranked[1:] {
+		if !containsID(candidates, driver.ID) || usage[driver.ID] >= b.cfg.Cap {
+			continue
+		}
+		boosted := make([]models.DriverWithDistance, 0, len(ranked))
+		boosted = append(boosted, driver)
+		boosted = append(boosted, ranked[:i+1]...)
+		boosted = append(boosted, ranked[i+2:]...)
+		return boosted, true
+	}
+	return ranked, false
+}
+
+// RecordUse counts a boosted assignment towards the driver's cap.
+func (b *ColdStartBoost) RecordUse(ctx context.Context, driverID primitive.ObjectID) {
+	if !b.enabled() {
+		return
+	}
+	if err := b.usage.RecordUse(ctx, driverID, b.now()); err != nil {
+		logging.FromContext(ctx).Error("Failed to record cold-start boost", "driver_id", driverID.Hex(), "error", err)
+	}
+}
+
+func (b *ColdStartBoost) Status(ctx context.Context, driver *models.Driver) (*models.ColdStartBoostStatus, error) {
+	status := &models.ColdStartBoostStatus{
+		DriverID:    driver.ID.Hex(),
+		ActivatedAt: driver.ActivatedAt(),
+	}
+	if !b.enabled() {
+		status.EligibleUntil = status.ActivatedAt
+		return status, nil
+	}
+
+	usage, err := b.usage.FindByDriver(ctx, driver.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	status.EligibleUntil = status.ActivatedAt.Add(b.cfg.Duration)
+	status.Used = usage.Used
+	status.Cap = b.cfg.Cap
+	status.Active = b.now().Before(status.EligibleUntil) && usage.Used < b.cfg.Cap
+	return status, nil
+}
+
+func containsID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// CustomFieldService manages the custom driver fields each fleet defines.
+// Deleting a definition leaves stored values in place; they are dropped the
+// next time the driver's custom fields are written.
+type CustomFieldService interface {
+	Create(ctx context.Context, fleetID string, req *models.CreateCustomFieldRequest) (*models.CustomFieldDefinition, error)
+	Definitions(ctx context.Context, fleetID string) ([]models.CustomFieldDefinition, error)
+	Delete(ctx context.Context, fleetID, key string) error
+}
+
+type customFieldService struct {
+	fieldRepo repository.CustomFieldRepository
+	now       func() time.Time
+}
+
+func NewCustomFieldService(fieldRepo repository.CustomFieldRepository) CustomFieldService {
+	return &customFieldService{
+		fieldRepo: fieldRepo,
+		now:       time.Now,
+	}
+}
+
+func (s *customFieldService) Create(ctx context.Context, fleetID string, req *models.CreateCustomFieldRequest) (*models.CustomFieldDefinition, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Two concurrent creates can both pass the count; the limit keeps
+	// documents small rather than guarding anything exact
+	count, err := s.fieldRepo.CountByFleet(ctx, fleetID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= models.MaxCustomFieldsPerFleet {
+		return nil, ErrTooManyCustomFields
+	}
+
+	definition := &models.CustomFieldDefinition{
+		FleetID:   fleetID,
+		Key:       req.Key,
+		Label:     req.Label,
+		Type:      req.Type,
+		Options:   req.Options,
+		Required:  req.Required,
+		CreatedAt: s.now(),
+	}
+	if err := s.fieldRepo.Create(ctx, definition); err != nil {
+		if errors.Is(err, repository.ErrCustomFieldExists) {
+			return nil, ErrCustomFieldExists
+		}
+		return nil, err
+	}
+	return definition, nil
+}
+
+func (s *customFieldService) Definitions(ctx context.Context, fleetID string) ([]models.CustomFieldDefinition, error) {
+	return s.fieldRepo.FindByFleet(ctx, fleetID)
+}
+
+func (s *customFieldService) Delete(ctx context.Context, fleetID, key string) error {
+	if err := s.fieldRepo.Delete(ctx, fleetID, key); err != nil {
+		if errors.Is(err, repository.ErrCustomFieldNotFound) {
+			return ErrCustomFieldNotFound
+		}
+		return err
+	}
+	return nil
+}
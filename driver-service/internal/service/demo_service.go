@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	demoCenterLat = 41.0082
+	demoCenterLon = 28.9784
+	demoSpreadKm  = 8.0
+	// demoAccuracyM is the accuracy reported for scripted positions.
+	demoAccuracyM = 5
+	// demoBaseTimestamp is the time embedded in demo ObjectIDs, so the IDs
+	// only depend on the seed.
+	demoBaseTimestamp = 1700000000
+)
+
+// Kinds of demo documents, part of their IDs.
+const (
+	demoKindDriver byte = iota + 1
+	demoKindTrip
+	demoKindRating
+)
+
+var (
+	demoFirstNames = []string{"Ahmet", "Mehmet", "Mustafa", "Ali", "Hüseyin", "Hasan", "Ayşe", "Fatma", "Emine", "Zeynep"}
+	demoLastNames  = []string{"Yılmaz", "Kaya", "Demir", "Şahin", "Çelik", "Yıldız", "Aydın", "Öztürk", "Arslan", "Doğan"}
+	demoCars       = [][2]string{{"Fiat", "Egea"}, {"Renault", "Clio"}, {"Hyundai", "i20"}, {"Toyota", "Corolla"}, {"Volkswagen", "Passat"}, {"Mercedes", "Vito"}}
+	demoTaxiTypes  = []string{models.TaxiTypeSari, models.TaxiTypeTurkuaz, models.TaxiTypeSiyah}
+	demoFleets     = []string{"demo-fleet-1", "demo-fleet-2"}
+	demoComments   = []string{"", "", "Clean car", "Friendly driver", "Knew the way", "A bit fast"}
+)
+
+// DemoService seeds a database with a scripted demo scenario and drives its
+// drivers along their routes. It is meant for sales demos and UI work, never
+// for production data.
+type DemoService interface {
+	// Seed empties the database of the tenant in ctx and fills it with the
+	// scenario of the request's seed.
+	Seed(ctx context.Context, req *models.SeedDemoRequest) (*models.DemoSeedResult, error)
+	Scenario(ctx context.Context) (*models.DemoScenario, error)
+	// Move puts every demo driver where their route has them now and
+	// returns the number moved. Without a scenario it does nothing.
+	Move(ctx context.Context) (int, error)
+}
+
+type demoService struct {
+	demoRepo repository.DemoRepository
+	drivers  DriverService
+	now      func() time.Time
+}
+
+// NewDemoService creates the demo service. Drivers are moved through
+// drivers, so location history, live subscribers and the nearby index see
+// them like any other driver.
+func NewDemoService(demoRepo repository.DemoRepository, drivers DriverService) DemoService {
+	return &demoService{
+		demoRepo: demoRepo,
+		drivers:  drivers,
+		now:      time.Now,
+	}
+}
+
+func (s *demoService) Seed(ctx context.Context, req *models.SeedDemoRequest) (*models.DemoSeedResult, error) {
+	if req == nil {
+		req = &models.SeedDemoRequest{}
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+	seed := req.Seed
+	if seed == 0 {
+		seed = models.DefaultDemoSeed
+	}
+	count := req.Drivers
+	if count == 0 {
+		count = models.DefaultDemoDrivers
+	}
+
+	cleared, err := s.demoRepo.Reset(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	scenario, drivers, trips, ratings := buildDemoScenario(seed, count, now)
+	if err := s.demoRepo.Seed(ctx, scenario, drivers, trips, ratings); err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("Seeded demo scenario", "seed", seed, "drivers", len(drivers), "trips", len(trips), "ratings", len(ratings))
+
+	return &models.DemoSeedResult{
+		Seed:               seed,
+		SeededAt:           now,
+		ClearedCollections: cleared,
+		Drivers:            len(drivers),
+		Trips:              len(trips),
+		Ratings:            len(ratings),
+	}, nil
+}
+
+func (s *demoService) Scenario(ctx context.Context) (*models.DemoScenario, error) {
+	scenario, err := s.demoRepo.FindScenario(ctx)
+	if err != nil {
+		if errors.Is(err, repository.ErrDemoScenarioNotFound) {
+			return nil, ErrDemoScenarioNotFound
+		}
+		return nil, err
+	}
+	return scenario, nil
+}
+
+func (s *demoService) Move(ctx context.Context) (int, error) {
+	scenario, err := s.demoRepo.FindScenario(ctx)
+	if err != nil {
+		if errors.Is(err, repository.ErrDemoScenarioNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	elapsed := s.now().Sub(scenario.SeededAt)
+	moved := 0
+	for i := range scenario.Routes {
+		if err := ctx.Err(); err != nil {
+			return moved, err
+		}
+
+		route := &scenario.Routes[i]
+		position := route.PositionAt(elapsed)
+		err := s.drivers.UpdateDriverLocation(ctx, route.DriverID, &models.UpdateLocationRequest{
+			Lat:      position.Lat,
+			Lon:      position.Lon,
+			Accuracy: demoAccuracyM,
+		})
+		if err != nil {
+			logging.FromContext(ctx).Warn("Failed to move demo driver", "driver_id", route.DriverID, "error", err)
+			continue
+		}
+		moved++
+	}
+
+	return moved, nil
+}
+
+// buildDemoScenario generates the scenario of a seed. Every tenth driver is
+// an applicant still in review, who stays parked; the others are approved
+// and drive a loop around their part of town, every fourth of them busy
+// with a ride in progress. Approved drivers have a few past rides, most of
+// them rated. Only the timestamps depend on now.
+func buildDemoScenario(seed int64, count int, now time.Time) (*models.DemoScenario, []models.Driver, []models.Trip, []models.Rating) {
+	rng := rand.New(rand.NewSource(seed))
+	scenario := &models.DemoScenario{
+		Seed:     seed,
+		SeededAt: now,
+		Routes:   []models.DemoRoute{},
+	}
+	drivers := make([]models.Driver, 0, count)
+	var trips []models.Trip
+	var ratings []models.Rating
+
+	center := models.Location{Lat: demoCenterLat, Lon: demoCenterLon}
+	for i := 0; i < count; i++ {
+		car := demoCars[rng.Intn(len(demoCars))]
+		anchor := demoOffset(center, (rng.Float64()*2-1)*demoSpreadKm, (rng.Float64()*2-1)*demoSpreadKm)
+		// At least eight days ago, so approval precedes the past week's rides
+		registeredAt := now.Add(-time.Duration(8*24+rng.Intn(60*24)) * time.Hour)
+		driver := models.Driver{
+			ID:        demoObjectID(seed, demoKindDriver, i),
+			FirstName: demoFirstNames[rng.Intn(len(demoFirstNames))],
+			LastName:  demoLastNames[rng.Intn(len(demoLastNames))],
+			Plate:     fmt.Sprintf("34 DMO %03d", i+1),
+			TaxiType:  demoTaxiTypes[rng.Intn(len(demoTaxiTypes))],
+			FleetID:   demoFleets[i%len(demoFleets)],
+			CarBrand:  car[0],
+			CarModel:  car[1],
+			Location:  anchor,
+			Languages: []string{"tr"},
+			CreatedAt: registeredAt,
+			UpdatedAt: now,
+			Onboarding: map[string]time.Time{
+				models.OnboardingRegistered:       registeredAt,
+				models.OnboardingLicenseSubmitted: registeredAt.Add(2 * time.Hour),
+			},
+		}
+
+		if i%10 == 9 {
+			driver.Status = models.DriverStatusOffline
+			driver.ReviewStatus = models.DriverReviewPending
+			if i%20 == 19 {
+				driver.Onboarding[models.OnboardingPhotoSubmitted] = registeredAt.Add(3 * time.Hour)
+			}
+			drivers = append(drivers, driver)
+			continue
+		}
+
+		verifiedAt := registeredAt.Add(20 * time.Hour)
+		driver.Verified = true
+		driver.VerifiedAt = &verifiedAt
+		driver.ReviewStatus = models.DriverReviewApproved
+		driver.ReviewedAt = &verifiedAt
+		driver.Status = models.DriverStatusAvailable
+		driver.Onboarding[models.OnboardingPhotoSubmitted] = registeredAt.Add(3 * time.Hour)
+		driver.Onboarding[models.OnboardingVerified] = verifiedAt
+		driver.Onboarding[models.OnboardingApproved] = verifiedAt
+
+		route := demoRoute(rng, driver.ID.Hex(), anchor)
+		driver.Location = route.PositionAt(0)
+		scenario.Routes = append(scenario.Routes, route)
+
+		rides := 2 + rng.Intn(4)
+		for j := 0; j < rides; j++ {
+			completedAt := now.Add(-time.Duration(1+rng.Intn(7*24)) * time.Hour)
+			trip := demoTrip(rng, seed, len(trips), &driver, anchor, completedAt)
+			trips = append(trips, trip)
+			if first, ok := driver.Onboarding[models.OnboardingFirstTrip]; !ok || completedAt.Before(first) {
+				driver.Onboarding[models.OnboardingFirstTrip] = completedAt
+			}
+
+			if rng.Float64() < 0.6 {
+				stars := 3 + rng.Intn(3)
+				ratings = append(ratings, models.Rating{
+					ID:        demoObjectID(seed, demoKindRating, len(ratings)),
+					DriverID:  driver.ID,
+					TripID:    trip.ID.Hex(),
+					Stars:     stars,
+					Comment:   demoComments[rng.Intn(len(demoComments))],
+					CreatedAt: completedAt.Add(10 * time.Minute),
+				})
+				driver.RatingSum += stars
+				driver.RatingCount++
+			}
+		}
+		if driver.RatingCount > 0 {
+			driver.AverageRating = math.Round(float64(driver.RatingSum)/float64(driver.RatingCount)*100) / 100
+		}
+
+		if i%4 == 0 {
+			// A ride in progress from where the driver is to the far side of
+			// their loop
+			startedAt := now.Add(-5 * time.Minute)
+			assignedAt := startedAt.Add(-9 * time.Minute)
+			enRouteAt := startedAt.Add(-8 * time.Minute)
+			dropoff := route.Waypoints[len(route.Waypoints)/2]
+			trips = append(trips, models.Trip{
+				ID:         demoObjectID(seed, demoKindTrip, len(trips)),
+				FleetID:    driver.FleetID,
+				TaxiType:   driver.TaxiType,
+				Pickup:     driver.Location,
+				Dropoff:    &dropoff,
+				Status:     models.TripStarted,
+				DriverID:   driver.ID.Hex(),
+				Active:     true,
+				Revision:   3,
+				CreatedBy:  "demo",
+				CreatedAt:  startedAt.Add(-10 * time.Minute),
+				UpdatedAt:  startedAt,
+				AssignedAt: &assignedAt,
+				EnRouteAt:  &enRouteAt,
+				StartedAt:  &startedAt,
+			})
+			driver.Status = models.DriverStatusBusy
+		}
+		statusAt := now
+		driver.StatusUpdatedAt = &statusAt
+
+		drivers = append(drivers, driver)
+	}
+
+	return scenario, drivers, trips, ratings
+}
+
+// demoRoute lays a loop of four to six waypoints around anchor, driven at
+// 20-40 km/h from a random point of it.
+func demoRoute(rng *rand.Rand, driverID string, anchor models.Location) models.DemoRoute {
+	points := 4 + rng.Intn(3)
+	start := rng.Float64() * 2 * math.Pi
+	waypoints := make([]models.Location, points)
+	for k := range waypoints {
+		angle := start + 2*math.Pi*float64(k)/float64(points)
+		radiusKm := 0.5 + rng.Float64()*1.5
+		waypoints[k] = demoOffset(anchor, radiusKm*math.Sin(angle), radiusKm*math.Cos(angle))
+	}
+
+	route := models.DemoRoute{
+		DriverID:  driverID,
+		Waypoints: waypoints,
+		SpeedKmh:  20 + rng.Float64()*20,
+	}
+	route.OffsetKm = rng.Float64() * route.LengthKm()
+	return route
+}
+
+// demoTrip is a completed ride of the driver near anchor.
+func demoTrip(rng *rand.Rand, seed int64, index int, driver *models.Driver, anchor models.Location, completedAt time.Time) models.Trip {
+	pickup := demoOffset(anchor, (rng.Float64()*2-1)*3, (rng.Float64()*2-1)*3)
+	dropoff := demoOffset(anchor, (rng.Float64()*2-1)*3, (rng.Float64()*2-1)*3)
+	at := func(minutes int) *time.Time {
+		t := completedAt.Add(-time.Duration(minutes) * time.Minute)
+		return &t
+	}
+
+	return models.Trip{
+		ID:          demoObjectID(seed, demoKindTrip, index),
+		FleetID:     driver.FleetID,
+		TaxiType:    driver.TaxiType,
+		Pickup:      pickup,
+		Dropoff:     &dropoff,
+		Status:      models.TripCompleted,
+		DriverID:    driver.ID.Hex(),
+		Revision:    4,
+		CreatedBy:   "demo",
+		CreatedAt:   *at(30),
+		UpdatedAt:   completedAt,
+		AssignedAt:  at(29),
+		EnRouteAt:   at(28),
+		StartedAt:   at(20),
+		CompletedAt: at(0),
+	}
+}
+
+// demoOffset moves a location by the given kilometres north and east.
+func demoOffset(from models.Location, northKm, eastKm float64) models.Location {
+	const kmPerDegreeLatitude = 111.32
+	return models.Location{
+		Lat: from.Lat + northKm/kmPerDegreeLatitude,
+		Lon: from.Lon + eastKm/(kmPerDegreeLatitude*math.Cos(from.Lat*math.Pi/180)),
+	}
+}
+
+// demoObjectID derives a stable ObjectID from the seed, kind and index, so
+// demos and UI fixtures can hardcode the IDs of a seed.
+func demoObjectID(seed int64, kind byte, index int) primitive.ObjectID {
+	var id primitive.ObjectID
+	ts := uint32(demoBaseTimestamp)
+	id[0], id[1], id[2], id[3] = byte(ts>>24), byte(ts>>16), byte(ts>>8), byte(ts)
+	for i := 0; i < 4; i++ {
+		id[4+i] = byte(seed >> (8 * i))
+	}
+	id[8] = kind
+	id[9], id[10], id[11] = byte(index>>16), byte(index>>8), byte(index)
+	return id
+}
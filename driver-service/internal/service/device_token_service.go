@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// deviceTokenTouchInterval limits last_used_at writes for devices that push
+// every few seconds.
+const deviceTokenTouchInterval = time.Minute
+
+// DeviceTokenService issues and checks the long-lived tokens used by
+// telematics boxes. Each token is bound to a single driver.
+type DeviceTokenService interface {
+	Issue(ctx context.Context, driverID string, req *models.IssueDeviceTokenRequest) (*models.IssuedDeviceTokenResponse, error)
+	List(ctx context.Context, driverID string) ([]models.DeviceToken, error)
+	Rotate(ctx context.Context, driverID, tokenID string, req *models.RotateDeviceTokenRequest) (*models.IssuedDeviceTokenResponse, error)
+	Revoke(ctx context.Context, driverID, tokenID string) (*models.DeviceToken, error)
+	Authenticate(ctx context.Context, secret string) (*models.DeviceToken, error)
+}
+
+type deviceTokenService struct {
+	tokenRepo  repository.DeviceTokenRepository
+	driverRepo repository.DriverRepository
+	now        func() time.Time
+}
+
+func NewDeviceTokenService(tokenRepo repository.DeviceTokenRepository, driverRepo repository.DriverRepository) DeviceTokenService {
+	return &deviceTokenService{
+		tokenRepo:  tokenRepo,
+		driverRepo: driverRepo,
+		now:        time.Now,
+	}
+}
+
+func (s *deviceTokenService) Issue(ctx context.Context, driverID string, req *models.IssueDeviceTokenRequest) (*models.IssuedDeviceTokenResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	driver, err := s.driverRepo.FindByID(ctx, driverID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDriverNotFound) {
+			return nil, ErrDriverNotFound
+		}
+		return nil, fmt.Errorf("failed to find driver: %w", err)
+	}
+
+	secret, err := newDeviceSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{models.ScopeLocationWrite}
+	}
+
+	now := s.now()
+	token := &models.DeviceToken{
+		DriverID:  driver.ID,
+		Name:      req.Name,
+		Scopes:    scopes,
+		Hint:      secretHint(secret),
+		TokenHash: hashDeviceSecret(secret),
+		CreatedAt: now,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return &models.IssuedDeviceTokenResponse{Token: secret, DeviceToken: token}, nil
+}
+
+func (s *deviceTokenService) List(ctx context.Context, driverID string) ([]models.DeviceToken, error) {
+	return s.tokenRepo.FindByDriver(ctx, driverID)
+}
+
+func (s *deviceTokenService) Rotate(ctx context.Context, driverID, tokenID string, req *models.RotateDeviceTokenRequest) (*models.IssuedDeviceTokenResponse, error) {
+	if req == nil {
+		req = &models.RotateDeviceTokenRequest{}
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	token, err := s.find(ctx, driverID, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	if token.RevokedAt != nil {
+		return nil, ErrDeviceTokenRevoked
+	}
+
+	secret, err := newDeviceSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	token.PreviousTokenHash = ""
+	token.PreviousExpiresAt = nil
+	if req.GraceSeconds > 0 {
+		graceUntil := now.Add(time.Duration(req.GraceSeconds) * time.Second)
+		token.PreviousTokenHash = token.TokenHash
+		token.PreviousExpiresAt = &graceUntil
+	}
+	token.TokenHash = hashDeviceSecret(secret)
+	token.Hint = secretHint(secret)
+	token.RotatedAt = &now
+
+	if err := s.tokenRepo.Update(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return &models.IssuedDeviceTokenResponse{Token: secret, DeviceToken: token}, nil
+}
+
+func (s *deviceTokenService) Revoke(ctx context.Context, driverID, tokenID string) (*models.DeviceToken, error) {
+	token, err := s.find(ctx, driverID, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	if token.RevokedAt != nil {
+		return token, nil
+	}
+
+	now := s.now()
+	token.RevokedAt = &now
+	token.PreviousTokenHash = ""
+	token.PreviousExpiresAt = nil
+
+	if err := s.tokenRepo.Update(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Authenticate resolves a presented secret to its token. Unknown, revoked
+// and expired tokens all fail with ErrInvalidDeviceToken.
+func (s *deviceTokenService) Authenticate(ctx context.Context, secret string) (*models.DeviceToken, error) {
+	if !strings.HasPrefix(secret, models.DeviceTokenPrefix) {
+		return nil, ErrInvalidDeviceToken
+	}
+
+	now := s.now()
+	token, err := s.tokenRepo.FindByHash(ctx, hashDeviceSecret(secret), now)
+	if err != nil {
+		if errors.Is(err, repository.ErrDeviceTokenNotFound) {
+			return nil, ErrInvalidDeviceToken
+		}
+		return nil, err
+	}
+	if token.RevokedAt != nil || (token.ExpiresAt != nil && !now.Before(*token.ExpiresAt)) {
+		return nil, ErrInvalidDeviceToken
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= deviceTokenTouchInterval {
+		if err := s.tokenRepo.TouchLastUsed(ctx, token.ID, now); err != nil {
+			logging.FromContext(ctx).Warn("Failed to record use of device token", "device_token_id", token.ID.Hex(), "error", err)
+		}
+	}
+
+	return token, nil
+}
+
+func (s *deviceTokenService) find(ctx context.Context, driverID, tokenID string) (*models.DeviceToken, error) {
+	token, err := s.tokenRepo.FindByID(ctx, driverID, tokenID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDeviceTokenNotFound) {
+			return nil, ErrDeviceTokenNotFound
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+func newDeviceSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate device token: %w", err)
+	}
+	return models.DeviceTokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashDeviceSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func secretHint(secret string) string {
+	return "…" + secret[len(secret)-4:]
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// DispatchPauseService manages the zones and time windows in which dispatch
+// is paused.
+type DispatchPauseService interface {
+	Create(ctx context.Context, req *models.CreateDispatchPauseRequest) (*models.DispatchPause, error)
+	List(ctx context.Context) ([]models.DispatchPause, error)
+	Cancel(ctx context.Context, id string) error
+	Active(ctx context.Context) ([]models.DispatchPause, error)
+}
+
+// DispatchPausedError is returned when a pickup lies inside a paused zone.
+// It matches ErrDispatchPaused and carries the pause so riders can be told
+// why and until when.
+type DispatchPausedError struct {
+	Pause *models.DispatchPause
+}
+
+func (e *DispatchPausedError) Error() string {
+	return fmt.Sprintf("%s: %s (%s) until %s", ErrDispatchPaused, e.Pause.Name, e.Pause.Reason, e.Pause.EndsAt.Format(time.RFC3339))
+}
+
+func (e *DispatchPausedError) Is(target error) bool {
+	return target == ErrDispatchPaused
+}
+
+type dispatchPauseService struct {
+	pauseRepo repository.DispatchPauseRepository
+	now       func() time.Time
+}
+
+func NewDispatchPauseService(pauseRepo repository.DispatchPauseRepository) DispatchPauseService {
+	return &dispatchPauseService{
+		pauseRepo: pauseRepo,
+		now:       time.Now,
+	}
+}
+
+func (s *dispatchPauseService) Create(ctx context.Context, req *models.CreateDispatchPauseRequest) (*models.DispatchPause, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := s.now()
+	startsAt := now
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	if !req.EndsAt.After(startsAt) || !req.EndsAt.After(now) {
+		return nil, ErrInvalidPauseWindow
+	}
+
+	pause := &models.DispatchPause{
+		Name:      req.Name,
+		Reason:    req.Reason,
+		Center:    models.Location{Lat: req.Lat, Lon: req.Lon},
+		RadiusKm:  req.RadiusKm,
+		StartsAt:  startsAt,
+		EndsAt:    req.EndsAt,
+		CreatedAt: now,
+	}
+	if err := s.pauseRepo.Create(ctx, pause); err != nil {
+		return nil, err
+	}
+
+	return pause, nil
+}
+
+// List returns the pauses that are active or scheduled; ended ones are kept
+// in storage but not listed.
+func (s *dispatchPauseService) List(ctx context.Context) ([]models.DispatchPause, error) {
+	return s.pauseRepo.FindNotEnded(ctx, s.now())
+}
+
+func (s *dispatchPauseService) Cancel(ctx context.Context, id string) error {
+	if err := s.pauseRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrDispatchPauseNotFound) {
+			return ErrDispatchPauseNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *dispatchPauseService) Active(ctx context.Context) ([]models.DispatchPause, error) {
+	return s.pauseRepo.FindActive(ctx, s.now())
+}
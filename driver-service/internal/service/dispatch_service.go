@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/dispatch"
+	"github.com/taxihub/driver-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DispatchService interface {
+	Assign(ctx context.Context, req *models.AssignDriverRequest) (*models.AssignmentResponse, error)
+	BoostStatus(ctx context.Context, driverID string) (*models.ColdStartBoostStatus, error)
+}
+
+// OfferSender pushes a ride offer to a driver's live connection and reports
+// whether it was handed on.
+type OfferSender interface {
+	SendOffer(ctx context.Context, driverID string, offer models.LiveOfferMessage) bool
+}
+
+// GPSQualityChecker tells whether a driver's recent GPS signal is too poor
+// to trust their position.
+type GPSQualityChecker interface {
+	IsPoor(tenantID string, driverID primitive.ObjectID) bool
+}
+
+type dispatchService struct {
+	driverService DriverService
+	dispatcher    *dispatch.Dispatcher
+	pauses        DispatchPauseService
+	boost         *ColdStartBoost
+	gpsQuality    GPSQualityChecker
+	offers        OfferSender
+}
+
+// NewDispatchService creates the dispatch service; boost, gpsQuality and
+// offers may be nil.
+func NewDispatchService(driverService DriverService, dispatcher *dispatch.Dispatcher, pauses DispatchPauseService, boost *ColdStartBoost, gpsQuality GPSQualityChecker, offers OfferSender) DispatchService {
+	return &dispatchService{
+		driverService: driverService,
+		dispatcher:    dispatcher,
+		pauses:        pauses,
+		boost:         boost,
+		gpsQuality:    gpsQuality,
+		offers:        offers,
+	}
+}
+
+// Assign ranks the nearby drivers with the fleet's strategy and records the
+// first one as assigned. Requests for a fleet only consider its own drivers.
+// Pickups inside an active dispatch pause are refused, and drivers inside
+// one are not considered. For ETA-sensitive pickups, drivers with a poor GPS
+// signal go behind the rest, as their distance cannot be trusted.
+func (s *dispatchService) Assign(ctx context.Context, req *models.AssignDriverRequest) (*models.AssignmentResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	pauses, err := s.pauses.Active(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dispatch pauses: %w", err)
+	}
+	pickup := models.Location{Lat: req.Lat, Lon: req.Lon}
+	for i := range pauses {
+		if pauses[i].Contains(pickup) {
+			return nil, &DispatchPausedError{Pause: &pauses[i]}
+		}
+	}
+
+	nearby, err := s.driverService.FindNearbyDrivers(ctx, req.Lat, req.Lon, models.NearbyFilter{TaxiType: req.TaxiType})
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := nearby[:0:0]
+	for _, driver := range nearby {
+		if req.FleetID != "" && driver.FleetID != req.FleetID {
+			continue
+		}
+		if inPausedZone(pauses, driver.Location) {
+			continue
+		}
+		candidates = append(candidates, driver)
+	}
+	if req.Language != "" && req.LanguageMode == models.LanguageModeRequire {
+		speakers := candidates[:0:0]
+		for _, driver := range candidates {
+			if driver.SpeaksLanguage(req.Language) {
+				speakers = append(speakers, driver)
+			}
+		}
+		candidates = speakers
+	}
+	if len(candidates) == 0 {
+		return nil, ErrNoDriversAvailable
+	}
+
+	strategy := s.dispatcher.StrategyFor(req.FleetID)
+	ranked := strategy.Rank(req.FleetID, candidates)
+	ranked, boosted := s.boost.Apply(ctx, ranked)
+	boostedID := ranked[0].ID
+	deprioritized := 0
+	if req.ETASensitive && s.gpsQuality != nil {
+		ranked, deprioritized = s.deprioritizePoorGPS(ctx, ranked)
+	}
+	if req.Language != "" {
+		ranked = preferLanguage(ranked, req.Language)
+	}
+	strategy.Assigned(req.FleetID, ranked[0].ID.Hex())
+
+	// The GPS and language preferences may have pushed the boosted driver back
+	boosted = boosted && ranked[0].ID == boostedID
+	if boosted {
+		s.boost.RecordUse(ctx, boostedID)
+	}
+
+	response := &models.AssignmentResponse{
+		Strategy:   strategy.Name(),
+		FleetID:    req.FleetID,
+		Boosted:    boosted,
+		Candidates: make([]*models.DriverWithDistanceResponse, len(ranked)),
+
+		GPSDeprioritized: deprioritized,
+	}
+	for i, driver := range ranked {
+		response.Candidates[i] = models.NewDriverWithDistanceResponse(driver)
+	}
+	response.Driver = response.Candidates[0]
+	if req.Language != "" {
+		matched := ranked[0].SpeaksLanguage(req.Language)
+		response.LanguageMatched = &matched
+	}
+	if s.offers != nil {
+		response.OfferSent = s.offers.SendOffer(ctx, ranked[0].ID.Hex(), models.LiveOfferMessage{
+			Type:       models.LiveOffer,
+			DriverID:   ranked[0].ID.Hex(),
+			FleetID:    req.FleetID,
+			Pickup:     models.Location{Lat: req.Lat, Lon: req.Lon},
+			DistanceKm: ranked[0].DistanceKm,
+			OfferedAt:  time.Now(),
+		})
+	}
+
+	return response, nil
+}
+
+func (s *dispatchService) BoostStatus(ctx context.Context, driverID string) (*models.ColdStartBoostStatus, error) {
+	driver, err := s.driverService.GetDriverByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	return s.boost.Status(ctx, driver)
+}
+
+func inPausedZone(pauses []models.DispatchPause, location models.Location) bool {
+	for i := range pauses {
+		if pauses[i].Contains(location) {
+			return true
+		}
+	}
+	return false
+}
+
+// deprioritizePoorGPS moves drivers with a poor GPS signal behind the rest,
+// keeping the order within each group, and returns how many it moved.
+func (s *dispatchService) deprioritizePoorGPS(ctx context.Context, ranked []models.DriverWithDistance) ([]models.DriverWithDistance, int) {
+	tenantID := config.TenantFromContext(ctx)
+	trusted := make([]models.DriverWithDistance, 0, len(ranked))
+	var poor []models.DriverWithDistance
+	for _, driver := range ranked {
+		if s.gpsQuality.IsPoor(tenantID, driver.ID) {
+			poor = append(poor, driver)
+		} else {
+			trusted = append(trusted, driver)
+		}
+	}
+	return append(trusted, poor...), len(poor)
+}
+
+// preferLanguage moves drivers who speak the language ahead of the rest,
+// keeping the strategy's order within each group.
+func preferLanguage(ranked []models.DriverWithDistance, language string) []models.DriverWithDistance {
+	preferred := make([]models.DriverWithDistance, 0, len(ranked))
+	var others []models.DriverWithDistance
+	for _, driver := range ranked {
+		if driver.SpeaksLanguage(language) {
+			preferred = append(preferred, driver)
+		} else {
+			others = append(others, driver)
+		}
+	}
+	return append(preferred, others...)
+}
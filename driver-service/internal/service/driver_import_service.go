@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/importer"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DriverImportService onboards drivers from legacy durak systems in two
+// steps: a file is staged into a preview of what it would create and change,
+// and the preview is then committed or discarded.
+type DriverImportService interface {
+	// Stage reads the file with the named adapter and stores the preview.
+	// fleetID fills the fleet of rows without one.
+	Stage(ctx context.Context, adapter string, mapping *importer.Mapping, fileName, fleetID string, file io.Reader) (*models.DriverImport, error)
+	Get(ctx context.Context, id string) (*models.DriverImport, error)
+	List(ctx context.Context, limit int) ([]models.DriverImport, error)
+	// Commit applies a staged import's create and update rows through the
+	// driver service and records each row's result.
+	Commit(ctx context.Context, id string) (*models.DriverImport, error)
+	Discard(ctx context.Context, id string) error
+}
+
+type driverImportService struct {
+	importRepo    repository.DriverImportRepository
+	driverService DriverService
+	maxRows       int
+	stageTTL      time.Duration
+	now           func() time.Time
+}
+
+func NewDriverImportService(importRepo repository.DriverImportRepository, driverService DriverService, maxRows int, stageTTL time.Duration) DriverImportService {
+	return &driverImportService{
+		importRepo:    importRepo,
+		driverService: driverService,
+		maxRows:       maxRows,
+		stageTTL:      stageTTL,
+		now:           time.Now,
+	}
+}
+
+func (s *driverImportService) Stage(ctx context.Context, adapterName string, mapping *importer.Mapping, fileName, fleetID string, file io.Reader) (*models.DriverImport, error) {
+	if len(fleetID) > 64 {
+		return nil, fmt.Errorf("%w: fleet_id must be at most 64 characters", ErrValidationFailed)
+	}
+
+	adapter, err := importer.NewAdapter(adapterName, mapping)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+	rows, err := adapter.Read(file, s.maxRows)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+
+	now := s.now()
+	expiresAt := now.Add(s.stageTTL)
+	driverImport := &models.DriverImport{
+		ID:        primitive.NewObjectID(),
+		Adapter:   adapter.Name(),
+		FileName:  fileName,
+		FleetID:   fleetID,
+		Status:    models.DriverImportStaged,
+		Rows:      make([]models.DriverImportRow, 0, len(rows)),
+		CreatedAt: now,
+		ExpiresAt: &expiresAt,
+	}
+
+	// Plates are compared without spaces, as the registry stores both
+	// spellings
+	seen := make(map[string]int)
+	for _, row := range rows {
+		req, issues := importer.BuildRequest(row, fleetID)
+		result := models.DriverImportRow{
+			Line:  row.Line,
+			Plate: req.Plate,
+		}
+
+		plateKey := strings.Join(strings.Fields(strings.ToUpper(req.Plate)), "")
+		switch {
+		case plateKey == "":
+			result.Action = models.ImportActionInvalid
+			result.Issues = append(issues, "plate: failed required")
+		case seen[plateKey] != 0:
+			result.Action = models.ImportActionDuplicate
+			result.Issues = []string{fmt.Sprintf("plate: already on line %d", seen[plateKey])}
+		case len(issues) > 0:
+			seen[plateKey] = row.Line
+			result.Action = models.ImportActionInvalid
+			result.Issues = issues
+		default:
+			seen[plateKey] = row.Line
+			if err := s.preview(ctx, &result, req); err != nil {
+				return nil, err
+			}
+		}
+
+		driverImport.Rows = append(driverImport.Rows, result)
+	}
+	driverImport.Summary = summarizeImport(driverImport.Rows)
+
+	if err := s.importRepo.Create(ctx, driverImport); err != nil {
+		return nil, fmt.Errorf("failed to stage driver import: %w", err)
+	}
+
+	return driverImport, nil
+}
+
+// preview decides what a row would do: register a driver with an unknown
+// plate, or bring the registered driver in line with the row.
+func (s *driverImportService) preview(ctx context.Context, row *models.DriverImportRow, req *models.CreateDriverRequest) error {
+	existing, err := s.driverService.GetDriverByPlate(ctx, req.Plate)
+	if err != nil && !errors.Is(err, ErrDriverNotFound) {
+		return err
+	}
+
+	if existing == nil {
+		if err := req.Validate(); err != nil {
+			row.Action = models.ImportActionInvalid
+			row.Issues = importer.ValidationIssues(err, req)
+			return nil
+		}
+		row.Action = models.ImportActionCreate
+		row.Request = req
+		return nil
+	}
+
+	row.DriverID = existing.ID.Hex()
+	update, changes := importUpdate(existing, req)
+	if err := update.Validate(); err != nil {
+		row.Action = models.ImportActionInvalid
+		row.Issues = importer.ValidationIssues(err, update)
+		return nil
+	}
+	if len(changes) == 0 {
+		row.Action = models.ImportActionUnchanged
+		return nil
+	}
+	row.Action = models.ImportActionUpdate
+	row.Changes = changes
+	row.Request = req
+	return nil
+}
+
+// importUpdate compares a row with the registered driver. Empty cells leave
+// fields alone, and the location is not imported for registered drivers, as
+// the driver app keeps it current.
+func importUpdate(driver *models.Driver, req *models.CreateDriverRequest) (*models.UpdateDriverRequest, []models.FieldChange) {
+	update := &models.UpdateDriverRequest{}
+	var changes []models.FieldChange
+
+	text := func(field, from, to string, dest **string, masked bool) {
+		if to == "" || to == from {
+			return
+		}
+		value := to
+		*dest = &value
+		if masked {
+			from, to = maskIdentity(from), maskIdentity(to)
+		}
+		changes = append(changes, models.FieldChange{Field: field, From: from, To: to})
+	}
+	list := func(field string, from, to []string, dest **[]string) {
+		if len(to) == 0 || strings.Join(from, ",") == strings.Join(to, ",") {
+			return
+		}
+		value := to
+		*dest = &value
+		changes = append(changes, models.FieldChange{Field: field, From: strings.Join(from, ","), To: strings.Join(to, ",")})
+	}
+
+	taxInfo := driver.TaxInfo
+	if taxInfo == nil {
+		taxInfo = &models.TaxInfo{}
+	}
+
+	text("first_name", driver.FirstName, req.FirstName, &update.FirstName, false)
+	text("last_name", driver.LastName, req.LastName, &update.LastName, false)
+	text("taxi_type", driver.TaxiType, req.TaxiType, &update.TaxiType, false)
+	text("fleet_id", driver.FleetID, req.FleetID, &update.FleetID, false)
+	text("car_brand", driver.CarBrand, req.CarBrand, &update.CarBrand, false)
+	text("car_model", driver.CarModel, req.CarModel, &update.CarModel, false)
+	text("car_color", driver.CarColor, req.CarColor, &update.CarColor, false)
+	text("email", driver.Email, req.Email, &update.Email, false)
+	list("languages", driver.Languages, models.NormalizeLanguages(req.Languages), &update.Languages)
+	list("license_classes", driver.LicenseClasses, models.NormalizeLicenseClasses(req.LicenseClasses), &update.LicenseClasses)
+	text("tckn", taxInfo.TCKN, req.TCKN, &update.TCKN, true)
+	text("tax_number", taxInfo.TaxNumber, req.TaxNumber, &update.TaxNumber, true)
+	text("billing_address", taxInfo.BillingAddress, req.BillingAddress, &update.BillingAddress, false)
+
+	return update, changes
+}
+
+func (s *driverImportService) Get(ctx context.Context, id string) (*models.DriverImport, error) {
+	driverImport, err := s.importRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrDriverImportNotFound) {
+			return nil, ErrDriverImportNotFound
+		}
+		return nil, err
+	}
+	return driverImport, nil
+}
+
+func (s *driverImportService) List(ctx context.Context, limit int) ([]models.DriverImport, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.importRepo.Find(ctx, limit)
+}
+
+func (s *driverImportService) Commit(ctx context.Context, id string) (*models.DriverImport, error) {
+	driverImport, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.importRepo.StartCommit(ctx, driverImport.ID); err != nil {
+		if errors.Is(err, repository.ErrDriverImportConflict) {
+			return nil, ErrDriverImportNotStaged
+		}
+		return nil, err
+	}
+
+	for i := range driverImport.Rows {
+		row := &driverImport.Rows[i]
+		switch row.Action {
+		case models.ImportActionCreate:
+			if _, err := s.driverService.CreateDriver(ctx, row.Request); err != nil {
+				row.Result = models.ImportResultFailed
+				row.Error = err.Error()
+				continue
+			}
+			row.Result = models.ImportResultCreated
+		case models.ImportActionUpdate:
+			updated, err := s.apply(ctx, row)
+			if err != nil {
+				row.Result = models.ImportResultFailed
+				row.Error = err.Error()
+				continue
+			}
+			if updated {
+				row.Result = models.ImportResultUpdated
+			} else {
+				row.Result = models.ImportResultSkipped
+			}
+		default:
+			row.Result = models.ImportResultSkipped
+		}
+	}
+
+	committedAt := s.now()
+	driverImport.Status = models.DriverImportCommitted
+	driverImport.CommittedAt = &committedAt
+	driverImport.ExpiresAt = nil
+	driverImport.Summary = summarizeImport(driverImport.Rows)
+
+	if err := s.importRepo.Save(ctx, driverImport); err != nil {
+		return nil, fmt.Errorf("failed to record driver import results: %w", err)
+	}
+
+	return driverImport, nil
+}
+
+// apply updates a row's driver with the row as it compares now, so changes
+// made since the preview are only overwritten where the row differs. It
+// reports whether there was anything left to change.
+func (s *driverImportService) apply(ctx context.Context, row *models.DriverImportRow) (bool, error) {
+	driver, err := s.driverService.GetDriverByID(ctx, row.DriverID)
+	if err != nil {
+		return false, err
+	}
+
+	update, changes := importUpdate(driver, row.Request)
+	if len(changes) == 0 {
+		return false, nil
+	}
+	if err := s.driverService.UpdateDriver(ctx, row.DriverID, update); err != nil {
+		return false, err
+	}
+	row.Changes = changes
+	return true, nil
+}
+
+func (s *driverImportService) Discard(ctx context.Context, id string) error {
+	if err := s.importRepo.DeleteStaged(ctx, id); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDriverImportNotFound):
+			return ErrDriverImportNotFound
+		case errors.Is(err, repository.ErrDriverImportConflict):
+			return ErrDriverImportNotStaged
+		}
+		return err
+	}
+	return nil
+}
+
+// maskIdentity masks a tax identity number the way driver responses do.
+func maskIdentity(value string) string {
+	if value == "" {
+		return ""
+	}
+	return (&models.TaxInfo{TCKN: value}).Masked().TCKN
+}
+
+func summarizeImport(rows []models.DriverImportRow) models.DriverImportSummary {
+	summary := models.DriverImportSummary{Rows: len(rows)}
+	for _, row := range rows {
+		switch row.Action {
+		case models.ImportActionCreate:
+			summary.Create++
+		case models.ImportActionUpdate:
+			summary.Update++
+		case models.ImportActionUnchanged:
+			summary.Unchanged++
+		case models.ImportActionInvalid:
+			summary.Invalid++
+		case models.ImportActionDuplicate:
+			summary.Duplicate++
+		}
+		switch row.Result {
+		case models.ImportResultCreated:
+			summary.Created++
+		case models.ImportResultUpdated:
+			summary.Updated++
+		case models.ImportResultFailed:
+			summary.Failed++
+		}
+	}
+	return summary
+}
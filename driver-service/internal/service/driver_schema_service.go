@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// DriverSchemaService reports how far driver documents are from the current
+// schema version and backfills the ones reads have not migrated yet.
+type DriverSchemaService interface {
+	Status(ctx context.Context) (*models.DriverSchemaStatus, error)
+	// Backfill migrates every stale driver document in batches.
+	Backfill(ctx context.Context) (*models.DriverSchemaBackfill, error)
+}
+
+const defaultDriverSchemaBatchSize = 500
+
+type driverSchemaService struct {
+	repo      repository.DriverSchemaRepository
+	batchSize int
+	now       func() time.Time
+}
+
+func NewDriverSchemaService(repo repository.DriverSchemaRepository, batchSize int) DriverSchemaService {
+	if batchSize < 1 {
+		batchSize = defaultDriverSchemaBatchSize
+	}
+	return &driverSchemaService{
+		repo:      repo,
+		batchSize: batchSize,
+		now:       time.Now,
+	}
+}
+
+func (s *driverSchemaService) Status(ctx context.Context) (*models.DriverSchemaStatus, error) {
+	counts, err := s.repo.CountBySchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.DriverSchemaStatus{
+		CurrentVersion: models.CurrentDriverSchemaVersion,
+		Versions:       counts,
+		Migrations:     make([]models.DriverSchemaMigration, 0, len(repository.DriverMigrations)),
+	}
+	for version, count := range counts {
+		if version < models.CurrentDriverSchemaVersion {
+			status.Pending += count
+		}
+	}
+	for _, migration := range repository.DriverMigrations {
+		status.Migrations = append(status.Migrations, models.DriverSchemaMigration{
+			Version:     migration.Version,
+			Description: migration.Description,
+		})
+	}
+	return status, nil
+}
+
+func (s *driverSchemaService) Backfill(ctx context.Context) (*models.DriverSchemaBackfill, error) {
+	result := &models.DriverSchemaBackfill{StartedAt: s.now()}
+
+	var batch repository.DriverMigrationBatch
+	for {
+		next, err := s.repo.MigrateBatch(ctx, batch.LastID, s.batchSize)
+		if next != nil {
+			result.Scanned += next.Scanned
+			result.Migrated += next.Migrated
+			result.Failed += next.Failed
+		}
+		if err != nil {
+			result.FinishedAt = s.now()
+			return result, err
+		}
+		if next.Scanned < s.batchSize {
+			break
+		}
+		batch = *next
+	}
+
+	result.FinishedAt = s.now()
+	return result, nil
+}
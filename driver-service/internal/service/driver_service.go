@@ -5,8 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/events"
+	"github.com/taxihub/driver-service/internal/logging"
 	"github.com/taxihub/driver-service/internal/models"
 	"github.com/taxihub/driver-service/internal/repository"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,11 +20,30 @@ type DriverService interface {
 	CreateDriver(ctx context.Context, req *models.CreateDriverRequest) (string, error)
 	UpdateDriver(ctx context.Context, id string, req *models.UpdateDriverRequest) error
 	GetDriverByID(ctx context.Context, id string) (*models.Driver, error)
-	ListDrivers(ctx context.Context, page, pageSize int) (*PaginatedResponse, error)
-	FindNearbyDrivers(ctx context.Context, lat, lon float64, taxiType string) ([]models.DriverWithDistance, error)
+	ListDrivers(ctx context.Context, page, pageSize int, filter models.DriverListFilter) (*PaginatedResponse, error)
+	SearchDrivers(ctx context.Context, query string, page, pageSize int) (*PaginatedResponse, error)
+	FindNearbyDrivers(ctx context.Context, lat, lon float64, filter models.NearbyFilter) ([]models.DriverWithDistance, error)
 	UpdateDriverLocation(ctx context.Context, id string, req *models.UpdateLocationRequest) error
-	DeleteDriver(ctx context.Context, id string) error
+	UpdateDriverLocations(ctx context.Context, id string, req *models.BatchLocationRequest) (*models.BatchLocationResult, error)
+	GetLocationTrace(ctx context.Context, id string, from, to time.Time, limit int) (*models.LocationTrace, error)
+	DeleteDriver(ctx context.Context, id string) (*models.DeletionReport, error)
 	GetDriverByPlate(ctx context.Context, plate string) (*models.Driver, error)
+	SetVerified(ctx context.Context, id string, verified bool) error
+	// ReviewDriver moves the driver to the given review status, if the
+	// approval workflow allows it from the current one.
+	ReviewDriver(ctx context.Context, id string, status string, req *models.ReviewDriverRequest) (*models.Driver, error)
+	SetStatus(ctx context.Context, id string, status string) error
+	VerifyPlate(ctx context.Context, plate string) (*models.PlateVerification, error)
+	ListLicenseOverrides(ctx context.Context, id string) ([]models.LicenseOverrideRecord, error)
+}
+
+const maxTrackedLocationDeltaKm = 50.0
+
+// locationFields are the driver fields a location update writes.
+var locationFields = []models.DriverField{
+	models.DriverFieldLocation,
+	models.DriverFieldTraveledKm,
+	models.DriverFieldLocationRecordedAt,
 }
 
 type PaginatedResponse struct {
@@ -31,14 +54,92 @@ type PaginatedResponse struct {
 	TotalPages int             `json:"total_pages"`
 }
 
+// DriverDeleter removes a driver along with the records that depend on it.
+type DriverDeleter interface {
+	DeleteDriver(ctx context.Context, id string) (*models.DeletionReport, error)
+}
+
+// LocationObserver receives every accepted location update. Observe must not
+// block.
+type LocationObserver interface {
+	Observe(sample models.LocationSample)
+}
+
+// LocationObservers passes every sample on to each observer in turn.
+type LocationObservers []LocationObserver
+
+func (o LocationObservers) Observe(sample models.LocationSample) {
+	for _, observer := range o {
+		observer.Observe(sample)
+	}
+}
+
+// WelcomeMailer greets newly registered drivers.
+type WelcomeMailer interface {
+	SendWelcome(ctx context.Context, driver *models.Driver) error
+}
+
+// CustomFieldSchema gives the custom field definitions of a fleet.
+type CustomFieldSchema interface {
+	Definitions(ctx context.Context, fleetID string) ([]models.CustomFieldDefinition, error)
+}
+
 type driverService struct {
-	driverRepo repository.DriverRepository
+	driverRepo    repository.DriverRepository
+	deleter       DriverDeleter
+	observer      LocationObserver
+	licensePolicy *LicenseClassPolicy
+	reservations  PlateReservationService
+	publisher     events.Producer
+	history       repository.LocationHistoryRepository
+	mailer        WelcomeMailer
+	locations     *LocationPolicy
+	fields        CustomFieldSchema
+	onboarding    OnboardingRecorder
 }
 
-func NewDriverService(driverRepo repository.DriverRepository) DriverService {
+// NewDriverService creates the driver service. deleter may be nil, in which
+// case deletes only remove the driver document. observer, licensePolicy,
+// reservations and publisher may also be nil. Without history, no location
+// trace is kept, without mailer no welcome email is sent, and without
+// locations every in-range fix is accepted. Without fields, custom field
+// values and filters are rejected. Without onboarding, only registration is
+// recorded as an onboarding step.
+func NewDriverService(driverRepo repository.DriverRepository, deleter DriverDeleter, observer LocationObserver, licensePolicy *LicenseClassPolicy, reservations PlateReservationService, publisher events.Producer, history repository.LocationHistoryRepository, mailer WelcomeMailer, locations *LocationPolicy, fields CustomFieldSchema, onboarding OnboardingRecorder) DriverService {
 	return &driverService{
-		driverRepo: driverRepo,
+		driverRepo:    driverRepo,
+		deleter:       deleter,
+		observer:      observer,
+		licensePolicy: licensePolicy,
+		reservations:  reservations,
+		publisher:     publisher,
+		history:       history,
+		mailer:        mailer,
+		locations:     locations,
+		fields:        fields,
+		onboarding:    onboarding,
+	}
+}
+
+// publish sends a lifecycle event. Events are best effort: a failure is
+// logged and never fails the change that caused it.
+func (s *driverService) publish(ctx context.Context, eventType, driverID string, data interface{}) {
+	if s.publisher == nil {
+		return
 	}
+
+	event := events.NewEvent(eventType, config.TenantFromContext(ctx), driverID, data)
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		logging.FromContext(ctx).Error("Failed to publish driver event", "event", eventType, "driver_id", driverID, "error", err)
+	}
+}
+
+// eventDriver copies a driver for an event payload without its tax details,
+// which stay in this service.
+func eventDriver(driver *models.Driver) models.Driver {
+	snapshot := *driver
+	snapshot.TaxInfo = nil
+	return snapshot
 }
 
 func (s *driverService) CreateDriver(ctx context.Context, req *models.CreateDriverRequest) (string, error) {
@@ -47,28 +148,78 @@ func (s *driverService) CreateDriver(ctx context.Context, req *models.CreateDriv
 	}
 
 	if err := req.Validate(); err != nil {
-		return "", fmt.Errorf("validation failed: %w", err)
+		return "", fmt.Errorf("%w: %w", ErrValidationFailed, err)
 	}
 
+	now := time.Now()
 	driver := &models.Driver{
 		ID:        primitive.NewObjectID(),
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		Plate:     req.Plate,
 		TaxiType:  req.TaxiType,
+		FleetID:   req.FleetID,
 		CarBrand:  req.CarBrand,
 		CarModel:  req.CarModel,
 		Location: models.Location{
 			Lat: req.Lat,
 			Lon: req.Lon,
 		},
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CarColor:      req.CarColor,
+		Bio:           req.Bio,
+		PhotoURL:      req.PhotoURL,
+		TaxInfo:       req.GetTaxInfo(),
+		Localizations: models.NormalizeLocalizations(req.Localizations),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+
+		LicenseClasses: models.NormalizeLicenseClasses(req.LicenseClasses),
+
+		Languages: models.NormalizeLanguages(req.Languages),
+
+		Status: models.DriverStatusAvailable,
+
+		ReviewStatus: models.DriverReviewPending,
+
+		Email: req.Email,
+
+		Onboarding: map[string]time.Time{models.OnboardingRegistered: now},
+	}
+
+	customFields, err := s.customFields(ctx, driver.FleetID, nil, req.CustomFields)
+	if err != nil {
+		return "", err
+	}
+	driver.CustomFields = customFields
+
+	if err := s.licensePolicy.Check(ctx, driver, req.LicenseOverride); err != nil {
+		return "", err
+	}
+
+	if s.reservations != nil {
+		if err := s.reservations.Check(ctx, req.Plate, req.PlateReservation); err != nil {
+			return "", err
+		}
 	}
 
 	driverID, err := s.driverRepo.Create(ctx, driver)
 	if err != nil {
-		return "", fmt.Errorf("failed to create driver: %w", err)
+		return "", fromDriverRepository(err)
+	}
+
+	// The unique plate index guards the plate from here on
+	if s.reservations != nil && req.PlateReservation != "" {
+		if err := s.reservations.Release(ctx, req.Plate, req.PlateReservation); err != nil && !errors.Is(err, ErrPlateReservationNotFound) {
+			logging.FromContext(ctx).Warn("Failed to release plate reservation", "driver_id", driverID, "error", err)
+		}
+	}
+
+	s.publish(ctx, events.DriverCreated, driverID, eventDriver(driver))
+
+	if s.mailer != nil {
+		if err := s.mailer.SendWelcome(ctx, driver); err != nil {
+			logging.FromContext(ctx).Error("Failed to queue welcome email", "driver_id", driverID, "error", err)
+		}
 	}
 
 	return driverID, nil
@@ -76,72 +227,189 @@ func (s *driverService) CreateDriver(ctx context.Context, req *models.CreateDriv
 
 func (s *driverService) UpdateDriver(ctx context.Context, id string, req *models.UpdateDriverRequest) error {
 	if id == "" {
-		return errors.New("driver ID cannot be empty")
+		return fmt.Errorf("%w: driver ID cannot be empty", ErrInvalidID)
 	}
 	if req == nil {
 		return errors.New("request cannot be nil")
 	}
 
 	if err := req.Validate(); err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+		return fmt.Errorf("%w: %w", ErrValidationFailed, err)
 	}
 
 	existingDriver, err := s.driverRepo.FindByID(ctx, id)
 	if err != nil {
-		if errors.Is(err, repository.ErrDriverNotFound) {
-			return fmt.Errorf("driver with ID %s not found", id)
-		}
-		return fmt.Errorf("failed to find driver: %w", err)
+		return fromDriverRepository(err)
 	}
 
+	// Only the fields the request changed are written, so a concurrent
+	// location or verification update is not undone by this earlier read.
+	var fields []models.DriverField
 	if req.FirstName != nil {
 		existingDriver.FirstName = *req.FirstName
+		fields = append(fields, models.DriverFieldFirstName)
 	}
 	if req.LastName != nil {
 		existingDriver.LastName = *req.LastName
+		fields = append(fields, models.DriverFieldLastName)
 	}
 	if req.TaxiType != nil {
 		existingDriver.TaxiType = *req.TaxiType
+		fields = append(fields, models.DriverFieldTaxiType)
+	}
+	// Moving to another fleet rechecks the stored values against its
+	// definitions, dropping the ones it does not define
+	fleetChanged := req.FleetID != nil && *req.FleetID != existingDriver.FleetID
+	if req.FleetID != nil {
+		existingDriver.FleetID = *req.FleetID
+		fields = append(fields, models.DriverFieldFleetID)
+	}
+	if req.CustomFields != nil || fleetChanged {
+		customFields, err := s.customFields(ctx, existingDriver.FleetID, existingDriver.CustomFields, req.CustomFields)
+		if err != nil {
+			return err
+		}
+		existingDriver.CustomFields = customFields
+		fields = append(fields, models.DriverFieldCustomFields)
 	}
 	if req.CarBrand != nil {
 		existingDriver.CarBrand = *req.CarBrand
+		fields = append(fields, models.DriverFieldCarBrand)
 	}
 	if req.CarModel != nil {
 		existingDriver.CarModel = *req.CarModel
+		fields = append(fields, models.DriverFieldCarModel)
 	}
 	if req.Lat != nil && req.Lon != nil {
 		existingDriver.Location = models.Location{
 			Lat: *req.Lat,
 			Lon: *req.Lon,
 		}
+		fields = append(fields, models.DriverFieldLocation)
+	}
+	if req.HasTaxInfo() {
+		taxInfo := models.TaxInfo{}
+		if existingDriver.TaxInfo != nil {
+			taxInfo = *existingDriver.TaxInfo
+		}
+		if req.TCKN != nil {
+			taxInfo.TCKN = *req.TCKN
+		}
+		if req.TaxNumber != nil {
+			taxInfo.TaxNumber = *req.TaxNumber
+		}
+		if req.BillingAddress != nil {
+			taxInfo.BillingAddress = *req.BillingAddress
+		}
+		existingDriver.TaxInfo = &taxInfo
+		fields = append(fields, models.DriverFieldTaxInfo)
+	}
+	if req.CarColor != nil {
+		existingDriver.CarColor = *req.CarColor
+		fields = append(fields, models.DriverFieldCarColor)
+	}
+	if req.Bio != nil {
+		existingDriver.Bio = *req.Bio
+		fields = append(fields, models.DriverFieldBio)
+	}
+	if req.PhotoURL != nil {
+		existingDriver.PhotoURL = *req.PhotoURL
+		fields = append(fields, models.DriverFieldPhotoURL)
+	}
+	localizations := models.NormalizeLocalizations(req.Localizations)
+	for locale, localization := range localizations {
+		if localization.IsEmpty() {
+			delete(existingDriver.Localizations, locale)
+			continue
+		}
+		if existingDriver.Localizations == nil {
+			existingDriver.Localizations = make(map[string]models.DriverLocalization)
+		}
+		existingDriver.Localizations[locale] = localization
+	}
+	if len(localizations) > 0 {
+		fields = append(fields, models.DriverFieldLocalizations)
+	}
+
+	if req.Languages != nil {
+		existingDriver.Languages = models.NormalizeLanguages(*req.Languages)
+		fields = append(fields, models.DriverFieldLanguages)
+	}
+	if req.LicenseClasses != nil {
+		existingDriver.LicenseClasses = models.NormalizeLicenseClasses(*req.LicenseClasses)
+		fields = append(fields, models.DriverFieldLicenseClasses)
+	}
+	if req.Email != nil {
+		existingDriver.Email = *req.Email
+		fields = append(fields, models.DriverFieldEmail)
+	}
+	if req.TaxiType != nil || req.LicenseClasses != nil {
+		if err := s.licensePolicy.Check(ctx, existingDriver, req.LicenseOverride); err != nil {
+			return err
+		}
 	}
 
 	existingDriver.UpdatedAt = time.Now()
 
-	if err := s.driverRepo.Update(ctx, id, existingDriver); err != nil {
-		return fmt.Errorf("failed to update driver: %w", err)
+	if err := s.driverRepo.Update(ctx, id, existingDriver, fields...); err != nil {
+		return fromDriverRepository(err)
 	}
 
+	s.publish(ctx, events.DriverUpdated, id, eventDriver(existingDriver))
+
 	return nil
 }
 
+// customFields checks values against the fleet's custom field definitions and
+// merges them into current. Required fields are only enforced when the
+// fleet defines any, so drivers without a fleet skip the lookup.
+func (s *driverService) customFields(ctx context.Context, fleetID string, current, values map[string]interface{}) (map[string]interface{}, error) {
+	if fleetID == "" {
+		if len(values) > 0 {
+			return nil, fmt.Errorf("%w: custom_fields need a fleet_id", ErrValidationFailed)
+		}
+		return nil, nil
+	}
+	if s.fields == nil && len(values) == 0 {
+		return nil, nil
+	}
+
+	definitions, err := s.customFieldDefinitions(ctx, fleetID)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := models.NormalizeCustomFields(definitions, current, values)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+	return merged, nil
+}
+
+func (s *driverService) customFieldDefinitions(ctx context.Context, fleetID string) ([]models.CustomFieldDefinition, error) {
+	if s.fields == nil {
+		return nil, fmt.Errorf("%w: custom fields are not available", ErrValidationFailed)
+	}
+	definitions, err := s.fields.Definitions(ctx, fleetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load custom fields: %w", err)
+	}
+	return definitions, nil
+}
+
 func (s *driverService) GetDriverByID(ctx context.Context, id string) (*models.Driver, error) {
 	if id == "" {
-		return nil, errors.New("driver ID cannot be empty")
+		return nil, fmt.Errorf("%w: driver ID cannot be empty", ErrInvalidID)
 	}
 
 	driver, err := s.driverRepo.FindByID(ctx, id)
 	if err != nil {
-		if errors.Is(err, repository.ErrDriverNotFound) {
-			return nil, fmt.Errorf("driver with ID %s not found", id)
-		}
-		return nil, fmt.Errorf("failed to get driver: %w", err)
+		return nil, fromDriverRepository(err)
 	}
 
 	return driver, nil
 }
 
-func (s *driverService) ListDrivers(ctx context.Context, page, pageSize int) (*PaginatedResponse, error) {
+func (s *driverService) ListDrivers(ctx context.Context, page, pageSize int, filter models.DriverListFilter) (*PaginatedResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -152,9 +420,24 @@ func (s *driverService) ListDrivers(ctx context.Context, page, pageSize int) (*P
 		pageSize = 100
 	}
 
-	drivers, totalCount, err := s.driverRepo.FindAll(ctx, page, pageSize)
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+	if len(filter.CustomFields) > 0 {
+		definitions, err := s.customFieldDefinitions(ctx, filter.FleetID)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := models.ParseCustomFieldFilter(definitions, filter.CustomFields)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+		}
+		filter.CustomFields = parsed
+	}
+
+	drivers, totalCount, err := s.driverRepo.FindAll(ctx, page, pageSize, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list drivers: %w", err)
+		return nil, fromDriverRepository(err)
 	}
 
 	totalPages := int(math.Ceil(float64(totalCount) / float64(pageSize)))
@@ -170,23 +453,72 @@ func (s *driverService) ListDrivers(ctx context.Context, page, pageSize int) (*P
 	return response, nil
 }
 
-func (s *driverService) FindNearbyDrivers(ctx context.Context, lat, lon float64, taxiType string) ([]models.DriverWithDistance, error) {
+// SearchDrivers finds drivers by name or plate words, best matches first.
+func (s *driverService) SearchDrivers(ctx context.Context, query string, page, pageSize int) (*PaginatedResponse, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: q is required", ErrValidationFailed)
+	}
+	if len(query) > models.MaxDriverSearchQueryLength {
+		return nil, fmt.Errorf("%w: q must be at most %d characters", ErrValidationFailed, models.MaxDriverSearchQueryLength)
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	drivers, totalCount, err := s.driverRepo.Search(ctx, models.DriverSearchTerms(query), page, pageSize)
+	if err != nil {
+		return nil, fromDriverRepository(err)
+	}
+
+	return &PaginatedResponse{
+		Data:       drivers,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: totalCount,
+		TotalPages: int(math.Ceil(float64(totalCount) / float64(pageSize))),
+	}, nil
+}
+
+func (s *driverService) FindNearbyDrivers(ctx context.Context, lat, lon float64, filter models.NearbyFilter) ([]models.DriverWithDistance, error) {
 	if lat < -90 || lat > 90 {
-		return nil, errors.New("invalid latitude: must be between -90 and 90")
+		return nil, fmt.Errorf("%w: latitude must be between -90 and 90", ErrInvalidLocation)
 	}
 	if lon < -180 || lon > 180 {
-		return nil, errors.New("invalid longitude: must be between -180 and 180")
+		return nil, fmt.Errorf("%w: longitude must be between -180 and 180", ErrInvalidLocation)
 	}
 
-	if taxiType != "" && !models.IsValidTaxiType(taxiType) {
-		return nil, fmt.Errorf("invalid taxi type: %s (must be one of: sari, turkuaz, siyah)", taxiType)
+	if filter.TaxiType != "" && !models.IsValidTaxiType(filter.TaxiType) {
+		return nil, fmt.Errorf("%w: %s (must be one of: sari, turkuaz, siyah)", ErrInvalidTaxiType, filter.TaxiType)
+	}
+	if filter.MaxETAMinutes < 0 || filter.MaxETAMinutes > models.MaxNearbyETAMinutes {
+		return nil, fmt.Errorf("%w: max ETA must be between 1 and %d minutes", ErrValidationFailed, models.MaxNearbyETAMinutes)
 	}
 
-	radiusKm := 5.0
+	if filter.RadiusKm < 0 {
+		return nil, fmt.Errorf("%w: radius must be positive", ErrValidationFailed)
+	}
+	if filter.Limit < 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", ErrValidationFailed)
+	}
+
+	radiusKm := models.DefaultNearbyRadiusKm
+	if filter.RadiusKm > 0 {
+		radiusKm = filter.RadiusKm
+	}
+	if filter.MaxETAMinutes > 0 {
+		radiusKm = math.Min(radiusKm, models.RadiusForETA(filter.MaxETAMinutes))
+	}
 
-	drivers, err := s.driverRepo.FindNearby(ctx, lat, lon, radiusKm, taxiType)
+	drivers, err := s.driverRepo.FindNearby(ctx, lat, lon, radiusKm, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find nearby drivers: %w", err)
+		return nil, fromDriverRepository(err)
 	}
 
 	return drivers, nil
@@ -194,69 +526,397 @@ func (s *driverService) FindNearbyDrivers(ctx context.Context, lat, lon float64,
 
 func (s *driverService) UpdateDriverLocation(ctx context.Context, id string, req *models.UpdateLocationRequest) error {
 	if id == "" {
-		return errors.New("driver ID cannot be empty")
+		return fmt.Errorf("%w: driver ID cannot be empty", ErrInvalidID)
 	}
 	if req == nil {
 		return errors.New("request cannot be nil")
 	}
 
 	if err := req.Validate(); err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+		return fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+	if reason := s.locations.Check(req.ToLocation(), req.Accuracy, time.Time{}, time.Now()); reason != "" {
+		return fmt.Errorf("%w: %s", ErrLocationRejected, reason)
 	}
 
 	existingDriver, err := s.driverRepo.FindByID(ctx, id)
 	if err != nil {
-		if errors.Is(err, repository.ErrDriverNotFound) {
-			return fmt.Errorf("driver with ID %s not found", id)
-		}
-		return fmt.Errorf("failed to find driver: %w", err)
+		return fromDriverRepository(err)
 	}
 
-	existingDriver.Location = models.Location{
+	newLocation := models.Location{
 		Lat: req.Lat,
 		Lon: req.Lon,
 	}
+	if !existingDriver.Location.IsZero() {
+		// Ignore implausible jumps (GPS glitches, app reinstalls) so they
+		// don't inflate the distance used for maintenance reminders.
+		if delta := existingDriver.Location.DistanceKm(newLocation); delta <= maxTrackedLocationDeltaKm {
+			existingDriver.TraveledKm += delta
+		}
+	}
+	existingDriver.Location = newLocation
 	existingDriver.UpdatedAt = time.Now()
+	recordedAt := existingDriver.UpdatedAt
+	existingDriver.LocationRecordedAt = &recordedAt
+
+	if err := s.driverRepo.Update(ctx, id, existingDriver, locationFields...); err != nil {
+		return fromDriverRepository(err)
+	}
+
+	if s.history != nil {
+		// The trace is a record, not a precondition: the update stands even
+		// if storing it fails
+		if err := s.history.Insert(ctx, &models.LocationHistoryEntry{
+			DriverID:   existingDriver.ID,
+			Location:   newLocation,
+			RecordedAt: recordedAt,
+			ReceivedAt: recordedAt,
+		}); err != nil {
+			logging.FromContext(ctx).Error("Failed to record driver location", "driver_id", id, "error", err)
+		}
+	}
 
-	if err := s.driverRepo.Update(ctx, id, existingDriver); err != nil {
-		return fmt.Errorf("failed to update driver location: %w", err)
+	if s.observer != nil {
+		s.observer.Observe(models.LocationSample{
+			TenantID:   config.TenantFromContext(ctx),
+			DriverID:   existingDriver.ID,
+			FleetID:    existingDriver.FleetID,
+			Location:   newLocation,
+			RecordedAt: existingDriver.UpdatedAt,
+			AccuracyM:  req.Accuracy,
+		})
 	}
 
+	s.publish(ctx, events.DriverLocationUpdated, id, events.LocationData{
+		Lat:        newLocation.Lat,
+		Lon:        newLocation.Lon,
+		FleetID:    existingDriver.FleetID,
+		RecordedAt: existingDriver.UpdatedAt,
+	})
+
 	return nil
 }
 
-func (s *driverService) DeleteDriver(ctx context.Context, id string) error {
+// UpdateDriverLocations applies points a driver app buffered while offline.
+// The newest point becomes the current location unless the driver has
+// reported a newer one since. Every point goes to the location history.
+func (s *driverService) UpdateDriverLocations(ctx context.Context, id string, req *models.BatchLocationRequest) (*models.BatchLocationResult, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: driver ID cannot be empty", ErrInvalidID)
+	}
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	now := time.Now()
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+
+	driver, err := s.driverRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fromDriverRepository(err)
+	}
+
+	result := &models.BatchLocationResult{}
+	points := s.screenPoints(req.SortedPoints(), now, result)
+	if len(points) == 0 {
+		return nil, fmt.Errorf("%w: all %d points failed the location checks", ErrLocationRejected, result.Rejected)
+	}
+	latest := points[len(points)-1]
+	result.Accepted = len(points)
+
+	current := driver.LocationRecordedAt
+	if current == nil || latest.RecordedAt.After(*current) {
+		// Count the distance along the points newer than the current fix
+		previous := driver.Location
+		for _, point := range points {
+			if current != nil && !point.RecordedAt.After(*current) {
+				continue
+			}
+			if !previous.IsZero() {
+				if delta := previous.DistanceKm(point.Location()); delta <= maxTrackedLocationDeltaKm {
+					driver.TraveledKm += delta
+				}
+			}
+			previous = point.Location()
+		}
+
+		recordedAt := latest.RecordedAt
+		driver.Location = latest.Location()
+		driver.LocationRecordedAt = &recordedAt
+		driver.UpdatedAt = now
+		if err := s.driverRepo.Update(ctx, id, driver, locationFields...); err != nil {
+			return nil, fromDriverRepository(err)
+		}
+		result.CurrentUpdated = true
+
+		if s.observer != nil {
+			s.observer.Observe(models.LocationSample{
+				TenantID:   config.TenantFromContext(ctx),
+				DriverID:   driver.ID,
+				FleetID:    driver.FleetID,
+				Location:   driver.Location,
+				RecordedAt: recordedAt,
+				AccuracyM:  latest.Accuracy,
+			})
+		}
+		s.publish(ctx, events.DriverLocationUpdated, id, events.LocationData{
+			Lat:        driver.Location.Lat,
+			Lon:        driver.Location.Lon,
+			FleetID:    driver.FleetID,
+			RecordedAt: recordedAt,
+		})
+	}
+
+	if s.history == nil {
+		return result, nil
+	}
+
+	entries := make([]models.LocationHistoryEntry, len(points))
+	for i, point := range points {
+		entries[i] = models.LocationHistoryEntry{
+			DriverID:   driver.ID,
+			Location:   point.Location(),
+			RecordedAt: point.RecordedAt,
+			ReceivedAt: now,
+		}
+	}
+	stored, err := s.history.InsertMany(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+	result.HistoryStored = stored
+	result.Duplicates = len(entries) - stored
+
+	return result, nil
+}
+
+// screenPoints drops the points the location policy rejects and records
+// them in result.
+func (s *driverService) screenPoints(points []models.LocationPoint, now time.Time, result *models.BatchLocationResult) []models.LocationPoint {
+	accepted := points[:0]
+	for _, point := range points {
+		reason := s.locations.Check(point.Location(), point.Accuracy, point.RecordedAt, now)
+		if reason == "" {
+			accepted = append(accepted, point)
+			continue
+		}
+		if result.RejectedReasons == nil {
+			result.RejectedReasons = make(map[string]int)
+		}
+		result.Rejected++
+		result.RejectedReasons[reason]++
+	}
+	return accepted
+}
+
+// GetLocationTrace returns the driver's recorded locations in [from, to) for
+// playback. The window may span at most models.MaxLocationTraceWindow.
+func (s *driverService) GetLocationTrace(ctx context.Context, id string, from, to time.Time, limit int) (*models.LocationTrace, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrValidationFailed)
+	}
+	if to.Sub(from) > models.MaxLocationTraceWindow {
+		return nil, fmt.Errorf("%w: the window may span at most %s", ErrValidationFailed, models.MaxLocationTraceWindow)
+	}
+	if limit < 1 || limit > models.MaxLocationTraceLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidationFailed, models.MaxLocationTraceLimit)
+	}
+
+	if _, err := s.driverRepo.FindByID(ctx, id); err != nil {
+		return nil, fromDriverRepository(err)
+	}
+
+	trace := &models.LocationTrace{
+		DriverID: id,
+		From:     from,
+		To:       to,
+		Points:   []models.LocationPoint{},
+	}
+	if s.history == nil {
+		return trace, nil
+	}
+
+	// One extra point tells whether the limit cut the trace short
+	entries, err := s.history.FindByDriver(ctx, id, from, to, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		trace.Truncated = true
+	}
+	for _, entry := range entries {
+		trace.Points = append(trace.Points, models.LocationPoint{
+			Lat:        entry.Location.Lat,
+			Lon:        entry.Location.Lon,
+			RecordedAt: entry.RecordedAt,
+		})
+	}
+
+	return trace, nil
+}
+
+func (s *driverService) DeleteDriver(ctx context.Context, id string) (*models.DeletionReport, error) {
 	if id == "" {
-		return errors.New("driver ID cannot be empty")
+		return nil, fmt.Errorf("%w: driver ID cannot be empty", ErrInvalidID)
 	}
 
 	_, err := s.driverRepo.FindByID(ctx, id)
 	if err != nil {
-		if errors.Is(err, repository.ErrDriverNotFound) {
-			return fmt.Errorf("driver with ID %s not found", id)
+		return nil, fromDriverRepository(err)
+	}
+
+	if s.deleter != nil {
+		report, err := s.deleter.DeleteDriver(ctx, id)
+		if err != nil {
+			return nil, fromDriverRepository(err)
 		}
-		return fmt.Errorf("failed to find driver: %w", err)
+		s.publish(ctx, events.DriverDeleted, id, nil)
+		return report, nil
 	}
 
 	if err := s.driverRepo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete driver: %w", err)
+		return nil, fromDriverRepository(err)
 	}
+	s.publish(ctx, events.DriverDeleted, id, nil)
 
-	return nil
+	return &models.DeletionReport{
+		DriverID:  id,
+		Archived:  map[string]int64{},
+		DeletedAt: time.Now(),
+	}, nil
 }
 
+// GetDriverByPlate looks a driver up by plate. Lookups go by the plate's
+// key, so "34abc123" and "34 ABC 123" find the same driver.
 func (s *driverService) GetDriverByPlate(ctx context.Context, plate string) (*models.Driver, error) {
-	if plate == "" {
+	if models.PlateKey(plate) == "" {
 		return nil, errors.New("plate cannot be empty")
 	}
 
+	driver, err := s.driverRepo.FindByPlate(ctx, plate)
+	if err != nil {
+		return nil, fromDriverRepository(err)
+	}
+	return driver, nil
+}
+
+func (s *driverService) SetVerified(ctx context.Context, id string, verified bool) error {
+	driver, err := s.GetDriverByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	driver.Verified = verified
+	driver.VerifiedAt = nil
+	if verified {
+		driver.VerifiedAt = &now
+	}
+	driver.UpdatedAt = now
+
+	if err := s.driverRepo.Update(ctx, id, driver, models.DriverFieldVerified); err != nil {
+		return fromDriverRepository(err)
+	}
+	if verified && s.onboarding != nil {
+		s.onboarding.Record(ctx, id, models.OnboardingVerified)
+	}
+
+	s.publish(ctx, events.DriverUpdated, id, eventDriver(driver))
+
+	return nil
+}
+
+func (s *driverService) ReviewDriver(ctx context.Context, id string, status string, req *models.ReviewDriverRequest) (*models.Driver, error) {
+	if req == nil {
+		req = &models.ReviewDriverRequest{}
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+
+	driver, err := s.GetDriverByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	current := driver.EffectiveReviewStatus()
+	if !models.CanTransitionReview(current, status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidReviewTransition, current, status)
+	}
+
+	now := time.Now()
+	if err := s.driverRepo.SetReviewStatus(ctx, id, current, status, req.Note, now); err != nil {
+		return nil, fromDriverRepository(err)
+	}
+	driver.ReviewStatus = status
+	driver.ReviewNote = req.Note
+	driver.ReviewedAt = &now
+	driver.UpdatedAt = now
+
+	logging.FromContext(ctx).Info("Driver review status changed", "driver_id", id, "from", current, "to", status, "note", req.Note)
+	if status == models.DriverReviewApproved && s.onboarding != nil {
+		s.onboarding.Record(ctx, id, models.OnboardingApproved)
+	}
+	s.publish(ctx, events.DriverUpdated, id, eventDriver(driver))
+
+	return driver, nil
+}
+
+func (s *driverService) SetStatus(ctx context.Context, id string, status string) error {
+	if !models.IsValidDriverStatus(status) {
+		return fmt.Errorf("%w: unknown status %q", ErrValidationFailed, status)
+	}
+
+	driver, err := s.GetDriverByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.driverRepo.SetStatus(ctx, id, status, now); err != nil {
+		return fromDriverRepository(err)
+	}
+	driver.Status = status
+	driver.StatusUpdatedAt = &now
+	driver.UpdatedAt = now
+
+	s.publish(ctx, events.DriverUpdated, id, eventDriver(driver))
+
+	return nil
+}
+
+// VerifyPlate tells whether a plate belongs to a verified driver. Unknown and
+// unverified plates get the same answer so the endpoint reveals nothing about
+// drivers still under review.
+func (s *driverService) VerifyPlate(ctx context.Context, plate string) (*models.PlateVerification, error) {
+	variants := models.PlateVariants(plate)
+	if len(variants) == 0 {
+		return nil, errors.New("plate cannot be empty")
+	}
+
+	result := &models.PlateVerification{Plate: variants[0]}
 	driver, err := s.driverRepo.FindByPlate(ctx, plate)
 	if err != nil {
 		if errors.Is(err, repository.ErrDriverNotFound) {
-			return nil, fmt.Errorf("driver with plate %s not found", plate)
+			return result, nil
 		}
-		return nil, fmt.Errorf("failed to get driver by plate: %w", err)
+		return nil, fromDriverRepository(err)
 	}
 
-	return driver, nil
+	if driver.Verified && driver.IsApproved() {
+		result.Plate = driver.Plate
+		result.Verified = true
+		result.TaxiType = driver.TaxiType
+		result.PhotoURL = driver.PhotoURL
+	}
+
+	return result, nil
+}
+
+func (s *driverService) ListLicenseOverrides(ctx context.Context, id string) ([]models.LicenseOverrideRecord, error) {
+	return s.licensePolicy.History(ctx, id)
 }
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// EarningsRecorder books the fare of a completed trip to its driver's
+// earnings. Booking is best effort and never fails the completion.
+type EarningsRecorder interface {
+	RecordTrip(ctx context.Context, trip *models.Trip)
+}
+
+// EarningsSender pushes an earnings update to a driver's live connection
+// and reports whether it was handed on.
+type EarningsSender interface {
+	SendEarnings(ctx context.Context, driverID string, update models.EarningsUpdate) bool
+}
+
+// EarningsService keeps drivers' earnings: the fares of their rides less
+// the commission, and the bonuses admins grant them. Every entry updates
+// the running total of its day and is handed to the sender, so the driver
+// app need not poll.
+type EarningsService interface {
+	EarningsRecorder
+	// RecordRide books the fare of a ride and the commission on it. A ride
+	// booked before fails with ErrRideAlreadyRecorded.
+	RecordRide(ctx context.Context, driverID string, req *models.RecordRideRequest) ([]models.EarningsEntry, error)
+	GrantBonus(ctx context.Context, driverID string, req *models.GrantBonusRequest) (*models.EarningsEntry, error)
+	// Day returns the driver's total and entries of a day, formatted as
+	// models.EarningsDayLayout; "" is today.
+	Day(ctx context.Context, driverID, day string) (*models.DailyEarningsResponse, error)
+}
+
+// EarningsConfig sets the commission deducted from fares, as a fraction,
+// the currency amounts are in, and the time zone whose days the totals
+// follow.
+type EarningsConfig struct {
+	CommissionRate float64
+	Currency       string
+	Location       *time.Location
+}
+
+type earningsService struct {
+	earningsRepo repository.EarningsRepository
+	driverRepo   repository.DriverRepository
+	sender       EarningsSender
+	cfg          EarningsConfig
+	now          func() time.Time
+}
+
+// NewEarningsService creates the earnings service. Without sender, updates
+// are not pushed.
+func NewEarningsService(earningsRepo repository.EarningsRepository, driverRepo repository.DriverRepository, sender EarningsSender, cfg EarningsConfig) EarningsService {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	return &earningsService{
+		earningsRepo: earningsRepo,
+		driverRepo:   driverRepo,
+		sender:       sender,
+		cfg:          cfg,
+		now:          time.Now,
+	}
+}
+
+func (s *earningsService) RecordRide(ctx context.Context, driverID string, req *models.RecordRideRequest) ([]models.EarningsEntry, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+	if err := s.checkDriver(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.recordRide(ctx, driverID, req.TripID, req.Fare, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrRideAlreadyRecorded
+	}
+	return entries, nil
+}
+
+// RecordTrip books the fare of a completed trip like RecordRide. Trips
+// completed without a fare earn nothing.
+func (s *earningsService) RecordTrip(ctx context.Context, trip *models.Trip) {
+	if trip.DriverID == "" || trip.Fare == nil {
+		return
+	}
+
+	at := s.now()
+	if trip.CompletedAt != nil {
+		at = *trip.CompletedAt
+	}
+	if _, err := s.recordRide(ctx, trip.DriverID, trip.ID.Hex(), *trip.Fare, at); err != nil {
+		logging.FromContext(ctx).Error("Failed to record trip earnings", "trip_id", trip.ID.Hex(), "error", err)
+	}
+}
+
+func (s *earningsService) GrantBonus(ctx context.Context, driverID string, req *models.GrantBonusRequest) (*models.EarningsEntry, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+	if err := s.checkDriver(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	entry := &models.EarningsEntry{
+		DriverID:  driverID,
+		Kind:      models.EarningsBonus,
+		Amount:    models.RoundAmount(req.Amount),
+		Note:      req.Note,
+		CreatedBy: req.GrantedBy,
+		CreatedAt: s.now(),
+	}
+	if err := s.record(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *earningsService) Day(ctx context.Context, driverID, day string) (*models.DailyEarningsResponse, error) {
+	if day == "" {
+		day = s.now().In(s.cfg.Location).Format(models.EarningsDayLayout)
+	} else if _, err := time.Parse(models.EarningsDayLayout, day); err != nil {
+		return nil, fmt.Errorf("%w: date must be formatted as YYYY-MM-DD", ErrValidationFailed)
+	}
+
+	total, entries, err := s.earningsRepo.FindDay(ctx, driverID, day, models.MaxEarningsEntries)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DailyEarningsResponse{Totals: s.totals(total), Entries: entries}, nil
+}
+
+func (s *earningsService) checkDriver(ctx context.Context, driverID string) error {
+	if _, err := s.driverRepo.FindByID(ctx, driverID); err != nil {
+		return fromDriverRepository(err)
+	}
+	return nil
+}
+
+// recordRide books the fare and the commission on it at the given time. It
+// skips the entries of the trip booked before, so an interrupted booking
+// can be retried, and returns the entries it booked.
+func (s *earningsService) recordRide(ctx context.Context, driverID, tripID string, fare float64, at time.Time) ([]models.EarningsEntry, error) {
+	entries := []models.EarningsEntry{{Kind: models.EarningsRide, Amount: models.RoundAmount(fare)}}
+	if commission := models.RoundAmount(fare * s.cfg.CommissionRate); commission > 0 {
+		entries = append(entries, models.EarningsEntry{Kind: models.EarningsCommission, Amount: -commission})
+	}
+
+	recorded := make([]models.EarningsEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.DriverID = driverID
+		entry.TripID = tripID
+		entry.CreatedAt = at
+		if err := s.record(ctx, &entry); err != nil {
+			if errors.Is(err, repository.ErrEarningsEntryExists) {
+				continue
+			}
+			return nil, err
+		}
+		recorded = append(recorded, entry)
+	}
+	return recorded, nil
+}
+
+// record stores the entry on the day it was made and sends it, with the
+// day's new total, to the driver.
+func (s *earningsService) record(ctx context.Context, entry *models.EarningsEntry) error {
+	entry.Day = entry.CreatedAt.In(s.cfg.Location).Format(models.EarningsDayLayout)
+	total, err := s.earningsRepo.Record(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	if s.sender != nil {
+		s.sender.SendEarnings(ctx, entry.DriverID, models.EarningsUpdate{Entry: *entry, Totals: s.totals(total)})
+	}
+	return nil
+}
+
+func (s *earningsService) totals(total *models.DailyEarnings) models.DailyEarnings {
+	rounded := total.Rounded()
+	rounded.Currency = s.cfg.Currency
+	return rounded
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/mail"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// emailLease is how long a claimed email may take to send before another
+	// worker may claim it again.
+	emailLease = 2 * time.Minute
+	// emailBatchSize caps the emails one delivery run sends, so a backlog
+	// drains over several runs instead of stalling one.
+	emailBatchSize       = 100
+	maxEmailRetryBackoff = time.Hour
+)
+
+// EmailService renders transactional emails into a send queue and delivers
+// them through the configured provider.
+type EmailService interface {
+	Send(ctx context.Context, req *models.SendEmailRequest) (*models.Email, error)
+	// SendWelcome queues the welcome email for a newly registered driver.
+	SendWelcome(ctx context.Context, driver *models.Driver) error
+	Get(ctx context.Context, id string) (*models.Email, error)
+	List(ctx context.Context, status string, limit int) ([]models.Email, error)
+	// DeliverDue sends the queued emails that are due and returns the number
+	// sent.
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+type EmailConfig struct {
+	// MaxAttempts is how many times an email is tried before it is marked
+	// failed.
+	MaxAttempts int
+	// RetryBackoff is the wait after the first failed attempt; it doubles
+	// with each further one, up to an hour.
+	RetryBackoff time.Duration
+}
+
+type emailService struct {
+	emailRepo  repository.EmailRepository
+	driverRepo repository.DriverRepository
+	templates  *mail.Templates
+	provider   mail.Provider
+	cfg        EmailConfig
+	now        func() time.Time
+}
+
+// NewEmailService creates the email service. With a nil provider emails are
+// queued but stay queued until one is configured.
+func NewEmailService(emailRepo repository.EmailRepository, driverRepo repository.DriverRepository, templates *mail.Templates, provider mail.Provider, cfg EmailConfig) EmailService {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = time.Minute
+	}
+	return &emailService{
+		emailRepo:  emailRepo,
+		driverRepo: driverRepo,
+		templates:  templates,
+		provider:   provider,
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+func (s *emailService) Send(ctx context.Context, req *models.SendEmailRequest) (*models.Email, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+
+	to := req.To
+	data := make(map[string]string, len(req.Data)+2)
+	for key, value := range req.Data {
+		data[key] = value
+	}
+	var locales []string
+	if req.Locale != "" {
+		locales = []string{req.Locale}
+	}
+
+	var driverID *primitive.ObjectID
+	if req.DriverID != "" {
+		driver, err := s.driverRepo.FindByID(ctx, req.DriverID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDriverNotFound) {
+				return nil, ErrDriverNotFound
+			}
+			return nil, fmt.Errorf("failed to find driver: %w", err)
+		}
+
+		driverID = &driver.ID
+		if to == "" {
+			if driver.Email == "" {
+				return nil, ErrNoEmailAddress
+			}
+			to = driver.Email
+		}
+		if data["first_name"] == "" {
+			data["first_name"] = driver.FirstName
+		}
+		if data["plate"] == "" {
+			data["plate"] = driver.Plate
+		}
+		if len(locales) == 0 {
+			locales = driver.Languages
+		}
+	}
+
+	return s.queue(ctx, req.Template, to, driverID, locales, data)
+}
+
+func (s *emailService) SendWelcome(ctx context.Context, driver *models.Driver) error {
+	if driver.Email == "" {
+		return nil
+	}
+
+	_, err := s.queue(ctx, mail.TemplateWelcome, driver.Email, &driver.ID, driver.Languages, map[string]string{
+		"first_name": driver.FirstName,
+		"plate":      driver.Plate,
+	})
+	return err
+}
+
+// queue renders the template and stores the email, due now.
+func (s *emailService) queue(ctx context.Context, template, to string, driverID *primitive.ObjectID, locales []string, data map[string]string) (*models.Email, error) {
+	msg, locale, err := s.templates.Render(template, locales, data)
+	if err != nil {
+		if errors.Is(err, mail.ErrUnknownTemplate) || errors.Is(err, mail.ErrMissingData) {
+			return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+		}
+		return nil, err
+	}
+
+	now := s.now()
+	email := &models.Email{
+		ID:            primitive.NewObjectID(),
+		Template:      template,
+		Locale:        locale,
+		To:            to,
+		DriverID:      driverID,
+		Subject:       msg.Subject,
+		Text:          msg.Text,
+		HTML:          msg.HTML,
+		Status:        models.EmailStatusQueued,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.emailRepo.Create(ctx, email); err != nil {
+		return nil, fmt.Errorf("failed to queue email: %w", err)
+	}
+
+	return email, nil
+}
+
+func (s *emailService) Get(ctx context.Context, id string) (*models.Email, error) {
+	email, err := s.emailRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailNotFound) {
+			return nil, ErrEmailNotFound
+		}
+		return nil, err
+	}
+	return email, nil
+}
+
+func (s *emailService) List(ctx context.Context, status string, limit int) ([]models.Email, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.emailRepo.Find(ctx, status, limit)
+}
+
+func (s *emailService) DeliverDue(ctx context.Context) (int, error) {
+	if s.provider == nil {
+		return 0, nil
+	}
+
+	sent := 0
+	for i := 0; i < emailBatchSize; i++ {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+
+		now := s.now()
+		email, err := s.emailRepo.ClaimDue(ctx, now, now.Add(emailLease))
+		if err != nil {
+			if errors.Is(err, repository.ErrEmailNotFound) {
+				return sent, nil
+			}
+			return sent, err
+		}
+
+		if s.deliver(ctx, email) {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// deliver makes one attempt at a claimed email and records the outcome. It
+// reports whether the email was sent.
+func (s *emailService) deliver(ctx context.Context, email *models.Email) bool {
+	err := s.provider.Send(ctx, mail.Message{
+		To:      email.To,
+		Subject: email.Subject,
+		Text:    email.Text,
+		HTML:    email.HTML,
+	})
+
+	now := s.now()
+	email.Provider = s.provider.Name()
+	email.UpdatedAt = now
+	switch {
+	case err == nil:
+		email.Status = models.EmailStatusSent
+		email.LastError = ""
+		email.SentAt = &now
+	case mail.IsPermanent(err) || email.Attempts >= s.cfg.MaxAttempts:
+		email.Status = models.EmailStatusFailed
+		email.LastError = err.Error()
+		logging.FromContext(ctx).Error("Email delivery failed", "email_id", email.ID.Hex(), "to", email.To, "attempts", email.Attempts, "error", err)
+	default:
+		email.Status = models.EmailStatusQueued
+		email.LastError = err.Error()
+		email.NextAttemptAt = now.Add(s.retryBackoff(email.Attempts))
+	}
+
+	if recordErr := s.emailRepo.RecordAttempt(ctx, email); recordErr != nil {
+		logging.FromContext(ctx).Error("Failed to record email delivery", "email_id", email.ID.Hex(), "error", recordErr)
+	}
+	return err == nil
+}
+
+func (s *emailService) retryBackoff(attempts int) time.Duration {
+	backoff := s.cfg.RetryBackoff
+	for i := 1; i < attempts && backoff < maxEmailRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxEmailRetryBackoff {
+		backoff = maxEmailRetryBackoff
+	}
+	return backoff
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"errors"
+
+	"github.com/taxihub/driver-service/internal/repository"
 )
 
 var (
@@ -13,4 +15,122 @@ var (
 	ErrInvalidTaxiType     = errors.New("invalid taxi type")
 	ErrValidationFailed    = errors.New("validation failed")
 	ErrRepositoryError     = errors.New("repository error")
+	ErrFeatureFlagNotFound = errors.New("feature flag not found")
+	ErrInvalidFlagKey      = errors.New("invalid feature flag key")
+
+	ErrChangeRequestNotFound   = errors.New("change request not found")
+	ErrChangeRequestPending    = errors.New("driver already has a pending change request")
+	ErrChangeRequestNotPending = errors.New("change request is no longer pending")
+	ErrNoChanges               = errors.New("no regulated field changes requested")
+	ErrPlateTaken              = errors.New("plate is already registered to another driver")
+
+	ErrLicenseClassNotPermitted = errors.New("license class does not permit this taxi type")
+
+	ErrNoDriversAvailable = errors.New("no drivers available")
+
+	ErrLicenseNotFound      = errors.New("license document not found")
+	ErrOCRUnavailable       = errors.New("license OCR is not configured")
+	ErrUnsupportedImageType = errors.New("unsupported image type")
+
+	ErrAnomalyNotFound = errors.New("anomaly not found")
+	ErrAnomalyNotOpen  = errors.New("anomaly has already been reviewed")
+
+	ErrDeviceTokenNotFound = errors.New("device token not found")
+	ErrDeviceTokenRevoked  = errors.New("device token has been revoked")
+	ErrInvalidDeviceToken  = errors.New("invalid device token")
+
+	ErrPhotoNotFound   = errors.New("photo not found")
+	ErrPhotoNotPending = errors.New("photo has already been reviewed")
+
+	ErrDispatchPauseNotFound = errors.New("dispatch pause not found")
+	ErrInvalidPauseWindow    = errors.New("dispatch pause must end after it starts and in the future")
+	ErrDispatchPaused        = errors.New("dispatch is paused in this zone")
+
+	ErrPlateReserved            = errors.New("plate is reserved by another registration")
+	ErrPlateReservationNotFound = errors.New("plate reservation not found")
+
+	ErrAuthDisabled        = errors.New("authentication is not configured")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrInvalidAccessToken  = errors.New("invalid access token")
+	ErrPasswordRequired    = errors.New("current password is required")
+
+	ErrFleetAccountNotFound = errors.New("fleet account not found")
+	ErrFleetAccountExists   = errors.New("fleet account username is taken")
+	ErrFleetOwnerProtected  = errors.New("only admins may create or change fleet owners")
+
+	ErrEmailNotFound  = errors.New("email not found")
+	ErrNoEmailAddress = errors.New("driver has no email address")
+
+	ErrDriverImportNotFound  = errors.New("driver import not found")
+	ErrDriverImportNotStaged = errors.New("driver import has already been committed")
+
+	ErrLocationRejected = errors.New("location rejected")
+
+	ErrInvalidReviewTransition = errors.New("driver review status cannot change this way")
+
+	ErrCustomFieldNotFound = errors.New("custom field not found")
+	ErrCustomFieldExists   = errors.New("custom field key is already defined for this fleet")
+	ErrTooManyCustomFields = errors.New("fleet has reached the custom field limit")
+
+	ErrTripNotFound          = errors.New("trip not found")
+	ErrInvalidTripTransition = errors.New("trip status cannot change this way")
+	ErrDriverUnavailable     = errors.New("driver is not available")
+	ErrDriverOnTrip          = errors.New("driver already has an active trip")
+	ErrNoPendingOffer        = errors.New("trip has no pending offer for this driver")
+	ErrMatchingDisabled      = errors.New("automatic trip matching is not configured")
+	ErrTripRated             = errors.New("trip has already been rated")
+
+	ErrRatingPromptNotFound         = errors.New("rating prompt not found")
+	ErrRatingPromptSettingsNotFound = errors.New("fleet has no rating prompt settings of its own")
+
+	ErrDemoScenarioNotFound = errors.New("no demo scenario has been seeded")
+
+	ErrRiderPreferencesNotFound = errors.New("rider has no saved preferences")
+	ErrTripNotAssigned          = errors.New("trip has no assigned driver")
+	ErrTripPreferencesLocked    = errors.New("trip preferences cannot change once a driver is assigned")
+
+	ErrRideAlreadyRecorded = errors.New("ride earnings already recorded")
 )
+
+// driverRepositoryErrors maps the driver repository's sentinels to the
+// service's.
+var driverRepositoryErrors = []struct {
+	repository error
+	service    error
+}{
+	{repository.ErrDriverNotFound, ErrDriverNotFound},
+	{repository.ErrDriverAlreadyExists, ErrDriverAlreadyExists},
+	{repository.ErrDriverReviewChanged, ErrInvalidReviewTransition},
+	{repository.ErrInvalidID, ErrInvalidID},
+	{repository.ErrInvalidCoordinates, ErrInvalidLocation},
+	{repository.ErrInvalidRadius, ErrValidationFailed},
+	{repository.ErrDatabaseError, ErrRepositoryError},
+}
+
+// repositoryError is a repository error tagged with the service sentinel it
+// maps to. errors.Is matches either; the message stays the repository's.
+type repositoryError struct {
+	sentinel error
+	err      error
+}
+
+func (e *repositoryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *repositoryError) Unwrap() []error {
+	return []error{e.sentinel, e.err}
+}
+
+// fromDriverRepository tags an error of the driver repository with the
+// matching service sentinel, so handlers only need to know the service's.
+// Errors without a repository sentinel are returned as they are.
+func fromDriverRepository(err error) error {
+	for _, mapping := range driverRepositoryErrors {
+		if errors.Is(err, mapping.repository) {
+			return &repositoryError{sentinel: mapping.service, err: err}
+		}
+	}
+	return err
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+var flagKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{1,63}$`)
+
+type FeatureService interface {
+	ListFlags(ctx context.Context) ([]models.FeatureFlag, error)
+	GetFlag(ctx context.Context, key string) (*models.FeatureFlag, error)
+	UpsertFlag(ctx context.Context, key string, req *models.UpsertFeatureFlagRequest) (*models.FeatureFlag, error)
+	DeleteFlag(ctx context.Context, key string) error
+	Evaluate(ctx context.Context, target models.TargetingContext) map[string]bool
+	IsEnabled(ctx context.Context, key string, target models.TargetingContext) bool
+}
+
+// featureService keeps flags cached in memory for cacheTTL so that gating
+// API requests does not cost a database round trip each time.
+type featureService struct {
+	flagRepo repository.FeatureFlagRepository
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	cached    []models.FeatureFlag
+	expiresAt time.Time
+}
+
+func NewFeatureService(flagRepo repository.FeatureFlagRepository, cacheTTL time.Duration) FeatureService {
+	return &featureService{
+		flagRepo: flagRepo,
+		cacheTTL: cacheTTL,
+		now:      time.Now,
+	}
+}
+
+func (s *featureService) ListFlags(ctx context.Context) ([]models.FeatureFlag, error) {
+	return s.flagRepo.FindAll(ctx)
+}
+
+func (s *featureService) GetFlag(ctx context.Context, key string) (*models.FeatureFlag, error) {
+	flag, err := s.flagRepo.FindByKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrFeatureFlagNotFound) {
+			return nil, ErrFeatureFlagNotFound
+		}
+		return nil, err
+	}
+	return flag, nil
+}
+
+func (s *featureService) UpsertFlag(ctx context.Context, key string, req *models.UpsertFeatureFlagRequest) (*models.FeatureFlag, error) {
+	if !flagKeyPattern.MatchString(key) {
+		return nil, ErrInvalidFlagKey
+	}
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := s.now()
+	flag := &models.FeatureFlag{
+		Key:         key,
+		Description: req.Description,
+		Enabled:     req.Enabled,
+		Rules:       req.Rules,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if flag.Rules == nil {
+		flag.Rules = []models.TargetingRule{}
+	}
+
+	existing, err := s.flagRepo.FindByKey(ctx, key)
+	if err != nil && !errors.Is(err, repository.ErrFeatureFlagNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		flag.CreatedAt = existing.CreatedAt
+	}
+
+	if err := s.flagRepo.Upsert(ctx, flag); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	return flag, nil
+}
+
+func (s *featureService) DeleteFlag(ctx context.Context, key string) error {
+	if err := s.flagRepo.Delete(ctx, key); err != nil {
+		if errors.Is(err, repository.ErrFeatureFlagNotFound) {
+			return ErrFeatureFlagNotFound
+		}
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Evaluate returns the state of every flag for the target, as served to the
+// driver app.
+func (s *featureService) Evaluate(ctx context.Context, target models.TargetingContext) map[string]bool {
+	result := make(map[string]bool)
+	for _, flag := range s.flags(ctx) {
+		result[flag.Key] = flag.IsEnabledFor(target)
+	}
+	return result
+}
+
+// IsEnabled reports whether a single flag is on for the target. Unknown flags
+// are off.
+func (s *featureService) IsEnabled(ctx context.Context, key string, target models.TargetingContext) bool {
+	for _, flag := range s.flags(ctx) {
+		if flag.Key == key {
+			return flag.IsEnabledFor(target)
+		}
+	}
+	return false
+}
+
+// flags returns the cached flags, reloading them when stale. If reloading
+// fails the previous copy keeps being served.
+func (s *featureService) flags(ctx context.Context) []models.FeatureFlag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && s.now().Before(s.expiresAt) {
+		return s.cached
+	}
+
+	flags, err := s.flagRepo.FindAll(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to load feature flags", "error", err)
+		return s.cached
+	}
+
+	s.cached = flags
+	s.expiresAt = s.now().Add(s.cacheTTL)
+	return s.cached
+}
+
+func (s *featureService) invalidate() {
+	s.mu.Lock()
+	s.expiresAt = time.Time{}
+	s.mu.Unlock()
+}
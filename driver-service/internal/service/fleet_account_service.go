@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// FleetAccountService manages the fleet owner and sub-admin accounts that log
+// in as fleet admins. Admins create a fleet's owners; owners create
+// sub-admins with a subset of the delegable capabilities. Unless privileged,
+// owner accounts cannot be created, changed or deleted.
+type FleetAccountService interface {
+	Create(ctx context.Context, fleetID, createdBy string, req *models.CreateFleetAccountRequest, privileged bool) (*models.FleetAccount, error)
+	List(ctx context.Context, fleetID string) ([]models.FleetAccount, error)
+	Update(ctx context.Context, fleetID, accountID string, req *models.UpdateFleetAccountRequest, privileged bool) (*models.FleetAccount, error)
+	Delete(ctx context.Context, fleetID, accountID string, privileged bool) error
+}
+
+type fleetAccountService struct {
+	accountRepo repository.FleetAccountRepository
+	now         func() time.Time
+}
+
+func NewFleetAccountService(accountRepo repository.FleetAccountRepository) FleetAccountService {
+	return &fleetAccountService{
+		accountRepo: accountRepo,
+		now:         time.Now,
+	}
+}
+
+func (s *fleetAccountService) Create(ctx context.Context, fleetID, createdBy string, req *models.CreateFleetAccountRequest, privileged bool) (*models.FleetAccount, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if req.Owner && !privileged {
+		return nil, ErrFleetOwnerProtected
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	now := s.now()
+	account := &models.FleetAccount{
+		FleetID:           fleetID,
+		Username:          req.Username,
+		PasswordHash:      string(hash),
+		Owner:             req.Owner,
+		Capabilities:      req.Capabilities,
+		CreatedBy:         createdBy,
+		PasswordChangedAt: now,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if account.Capabilities == nil {
+		account.Capabilities = []string{}
+	}
+
+	if err := s.accountRepo.Create(ctx, account); err != nil {
+		if errors.Is(err, repository.ErrFleetAccountExists) {
+			return nil, ErrFleetAccountExists
+		}
+		return nil, err
+	}
+	return account, nil
+}
+
+func (s *fleetAccountService) List(ctx context.Context, fleetID string) ([]models.FleetAccount, error) {
+	return s.accountRepo.FindByFleet(ctx, fleetID)
+}
+
+// Update replaces a sub-admin's capabilities and resets passwords. A password
+// reset invalidates the account's refresh tokens.
+func (s *fleetAccountService) Update(ctx context.Context, fleetID, accountID string, req *models.UpdateFleetAccountRequest, privileged bool) (*models.FleetAccount, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	account, err := s.find(ctx, fleetID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Owner && !privileged {
+		return nil, ErrFleetOwnerProtected
+	}
+
+	now := s.now()
+	if req.Capabilities != nil {
+		if account.Owner {
+			return nil, fmt.Errorf("%w: owners hold every capability", ErrValidationFailed)
+		}
+		account.Capabilities = *req.Capabilities
+	}
+	if req.Password != nil {
+		hash, err := bcrypt.GenerateFromPassword([]byte(*req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+		account.PasswordHash = string(hash)
+		account.PasswordChangedAt = now
+	}
+	account.UpdatedAt = now
+
+	if err := s.accountRepo.Update(ctx, account); err != nil {
+		if errors.Is(err, repository.ErrFleetAccountNotFound) {
+			return nil, ErrFleetAccountNotFound
+		}
+		return nil, err
+	}
+	return account, nil
+}
+
+func (s *fleetAccountService) Delete(ctx context.Context, fleetID, accountID string, privileged bool) error {
+	account, err := s.find(ctx, fleetID, accountID)
+	if err != nil {
+		return err
+	}
+	if account.Owner && !privileged {
+		return ErrFleetOwnerProtected
+	}
+
+	if err := s.accountRepo.Delete(ctx, account.ID); err != nil {
+		if errors.Is(err, repository.ErrFleetAccountNotFound) {
+			return ErrFleetAccountNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// find returns the account if it belongs to the fleet; accounts of other
+// fleets are reported as not found.
+func (s *fleetAccountService) find(ctx context.Context, fleetID, accountID string) (*models.FleetAccount, error) {
+	id, err := primitive.ObjectIDFromHex(accountID)
+	if err != nil {
+		return nil, ErrFleetAccountNotFound
+	}
+
+	account, err := s.accountRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrFleetAccountNotFound) {
+			return nil, ErrFleetAccountNotFound
+		}
+		return nil, err
+	}
+	if account.FleetID != fleetID {
+		return nil, ErrFleetAccountNotFound
+	}
+	return account, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// LicenseClassPolicy keeps drivers off taxi types their license classes do
+// not permit, unless an admin overrides the check. Drivers without recorded
+// classes are not checked.
+type LicenseClassPolicy struct {
+	requirements models.LicenseClassRequirements
+	overrides    repository.LicenseOverrideRepository
+	now          func() time.Time
+}
+
+func NewLicenseClassPolicy(requirements models.LicenseClassRequirements, overrides repository.LicenseOverrideRepository) *LicenseClassPolicy {
+	return &LicenseClassPolicy{
+		requirements: requirements,
+		overrides:    overrides,
+		now:          time.Now,
+	}
+}
+
+// Check validates the driver as it is about to be written. A needed override
+// is recorded in the audit trail before returning, so no override is ever
+// applied without a record. A nil policy permits everything.
+func (p *LicenseClassPolicy) Check(ctx context.Context, driver *models.Driver, override *models.LicenseOverride) error {
+	if p == nil {
+		return nil
+	}
+
+	required, ok := p.requirements[driver.TaxiType]
+	if !ok || len(driver.LicenseClasses) == 0 || models.LicensePermits(driver.LicenseClasses, required) {
+		return nil
+	}
+
+	if override == nil {
+		return fmt.Errorf("%w: taxi type %s requires a %s license, driver holds %s",
+			ErrLicenseClassNotPermitted, driver.TaxiType, required, strings.Join(driver.LicenseClasses, ", "))
+	}
+
+	record := &models.LicenseOverrideRecord{
+		DriverID:       driver.ID,
+		TaxiType:       driver.TaxiType,
+		RequiredClass:  required,
+		LicenseClasses: driver.LicenseClasses,
+		Reason:         override.Reason,
+		ApprovedBy:     override.ApprovedBy,
+		CreatedAt:      p.now(),
+	}
+	if err := p.overrides.Create(ctx, record); err != nil {
+		return fmt.Errorf("failed to record license override: %w", err)
+	}
+
+	return nil
+}
+
+func (p *LicenseClassPolicy) History(ctx context.Context, driverID string) ([]models.LicenseOverrideRecord, error) {
+	if p == nil {
+		return []models.LicenseOverrideRecord{}, nil
+	}
+	return p.overrides.FindByDriver(ctx, driverID)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/ocr"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+var uploadImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+}
+
+// expiryLayouts are the date formats printed on Turkish licenses and
+// returned by OCR engines; values are stored as YYYY-MM-DD.
+var expiryLayouts = []string{"2006-01-02", "02.01.2006", "02/01/2006", "02-01-2006"}
+
+// LicenseService pre-fills license details from an uploaded image for the
+// verification workflow. Fields the OCR provider is unsure about are flagged
+// for manual correction.
+type LicenseService interface {
+	Upload(ctx context.Context, driverID string, image []byte, contentType string) (*models.LicenseDocument, error)
+	Get(ctx context.Context, driverID string) (*models.LicenseDocument, error)
+	Correct(ctx context.Context, driverID string, req *models.CorrectLicenseRequest) (*models.LicenseDocument, error)
+}
+
+type licenseService struct {
+	licenseRepo repository.LicenseRepository
+	driverRepo  repository.DriverRepository
+	provider    ocr.Provider
+	threshold   float64
+	onboarding  OnboardingRecorder
+	now         func() time.Time
+}
+
+// NewLicenseService creates the license service; onboarding may be nil.
+func NewLicenseService(licenseRepo repository.LicenseRepository, driverRepo repository.DriverRepository, provider ocr.Provider, threshold float64, onboarding OnboardingRecorder) LicenseService {
+	return &licenseService{
+		licenseRepo: licenseRepo,
+		driverRepo:  driverRepo,
+		provider:    provider,
+		threshold:   threshold,
+		onboarding:  onboarding,
+		now:         time.Now,
+	}
+}
+
+func (s *licenseService) Upload(ctx context.Context, driverID string, image []byte, contentType string) (*models.LicenseDocument, error) {
+	if s.provider == nil {
+		return nil, ErrOCRUnavailable
+	}
+	if !uploadImageTypes[contentType] {
+		return nil, ErrUnsupportedImageType
+	}
+
+	driver, err := s.driverRepo.FindByID(ctx, driverID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDriverNotFound) {
+			return nil, ErrDriverNotFound
+		}
+		return nil, fmt.Errorf("failed to find driver: %w", err)
+	}
+
+	extraction, err := s.provider.ExtractLicense(ctx, image, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read license: %w", err)
+	}
+
+	sum := sha256.Sum256(image)
+	now := s.now()
+	document := &models.LicenseDocument{
+		DriverID:      driver.ID,
+		FullName:      s.review(extraction.FullName),
+		LicenseNumber: s.review(normalizeLicenseNumber(extraction.LicenseNumber)),
+		ExpiresAt:     s.review(normalizeExpiry(extraction.ExpiresAt)),
+		ImageSHA256:   hex.EncodeToString(sum[:]),
+		ImageSize:     len(image),
+		ContentType:   contentType,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if existing, err := s.licenseRepo.FindByDriver(ctx, driverID); err == nil {
+		document.ID = existing.ID
+		document.CreatedAt = existing.CreatedAt
+	}
+	document.RefreshStatus()
+
+	if err := s.licenseRepo.Save(ctx, document); err != nil {
+		return nil, err
+	}
+	if s.onboarding != nil {
+		s.onboarding.Record(ctx, driverID, models.OnboardingLicenseSubmitted)
+	}
+
+	return document, nil
+}
+
+func (s *licenseService) Get(ctx context.Context, driverID string) (*models.LicenseDocument, error) {
+	document, err := s.licenseRepo.FindByDriver(ctx, driverID)
+	if err != nil {
+		if errors.Is(err, repository.ErrLicenseNotFound) {
+			return nil, ErrLicenseNotFound
+		}
+		return nil, err
+	}
+	return document, nil
+}
+
+func (s *licenseService) Correct(ctx context.Context, driverID string, req *models.CorrectLicenseRequest) (*models.LicenseDocument, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	document, err := s.Get(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.FullName != nil {
+		document.FullName = manualField(strings.TrimSpace(*req.FullName))
+	}
+	if req.LicenseNumber != nil {
+		document.LicenseNumber = manualField(normalizeLicenseNumber(models.ExtractedField{Value: *req.LicenseNumber}).Value)
+	}
+	if req.ExpiresAt != nil {
+		document.ExpiresAt = manualField(*req.ExpiresAt)
+	}
+	document.UpdatedAt = s.now()
+	document.RefreshStatus()
+
+	if err := s.licenseRepo.Save(ctx, document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// review flags fields that are empty or below the confidence threshold.
+func (s *licenseService) review(field models.ExtractedField) models.ExtractedField {
+	field.NeedsReview = field.Value == "" || field.Confidence < s.threshold
+	return field
+}
+
+func manualField(value string) models.ExtractedField {
+	return models.ExtractedField{
+		Value:      value,
+		Confidence: 1,
+		Source:     models.FieldSourceManual,
+	}
+}
+
+func normalizeLicenseNumber(field models.ExtractedField) models.ExtractedField {
+	field.Value = strings.ToUpper(strings.Join(strings.Fields(field.Value), ""))
+	return field
+}
+
+// normalizeExpiry rewrites recognised dates as YYYY-MM-DD. Dates that cannot
+// be parsed keep their raw value and lose their confidence so they are sent
+// for review.
+func normalizeExpiry(field models.ExtractedField) models.ExtractedField {
+	value := strings.TrimSpace(field.Value)
+	for _, layout := range expiryLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			field.Value = parsed.Format("2006-01-02")
+			return field
+		}
+	}
+	field.Confidence = 0
+	return field
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+// Reasons a location fix is rejected.
+const (
+	LocationRejectNullIsland  = "null_island"
+	LocationRejectLowAccuracy = "low_accuracy"
+	LocationRejectStale       = "stale"
+	LocationRejectFuture      = "future"
+)
+
+// nullIslandRadiusKm is how close to (0, 0) a fix must be to count as the
+// null island, where devices without a fix report themselves.
+const nullIslandRadiusKm = 1.0
+
+// LocationPolicyConfig tunes the checks. Zero limits disable their check.
+type LocationPolicyConfig struct {
+	// Strict rejects failing fixes; otherwise they are only counted.
+	Strict bool
+	// MaxAccuracyM is the largest accuracy radius accepted, in meters.
+	MaxAccuracyM float64
+	// MaxAge is how old a buffered fix may be when it arrives.
+	MaxAge time.Duration
+}
+
+// LocationRejectionCounter counts fixes failing the policy by reason, and
+// whether they were rejected or only counted.
+type LocationRejectionCounter interface {
+	ObserveLocationRejection(reason string, enforced bool)
+}
+
+// LocationPolicy screens location fixes before they are stored: the null
+// island, fixes with a wide accuracy radius and buffered fixes that are too
+// old or dated in the future.
+type LocationPolicy struct {
+	config  LocationPolicyConfig
+	counter LocationRejectionCounter
+}
+
+// NewLocationPolicy creates the policy; counter may be nil.
+func NewLocationPolicy(config LocationPolicyConfig, counter LocationRejectionCounter) *LocationPolicy {
+	return &LocationPolicy{
+		config:  config,
+		counter: counter,
+	}
+}
+
+// Check returns why the fix fails the policy, or "" if it passes or the
+// policy is permissive; failures are counted either way, so permissive mode
+// shows what strict mode would reject. An accuracy of zero is unknown and a
+// zero recordedAt means the fix is live, so neither is checked.
+//
+// Fixes dated more than models.MaxLocationClockSkew in the future are
+// rejected in either mode, and by a nil policy too: they would become the
+// driver's current fix and hide every real one until their time came. A nil
+// policy accepts everything else.
+func (p *LocationPolicy) Check(location models.Location, accuracyM float64, recordedAt, now time.Time) string {
+	if recordedAt.After(now.Add(models.MaxLocationClockSkew)) {
+		if p != nil && p.counter != nil {
+			p.counter.ObserveLocationRejection(LocationRejectFuture, true)
+		}
+		return LocationRejectFuture
+	}
+	if p == nil {
+		return ""
+	}
+
+	reason := ""
+	switch {
+	case location.DistanceKm(models.Location{}) <= nullIslandRadiusKm:
+		reason = LocationRejectNullIsland
+	case p.config.MaxAccuracyM > 0 && accuracyM > p.config.MaxAccuracyM:
+		reason = LocationRejectLowAccuracy
+	case p.config.MaxAge > 0 && !recordedAt.IsZero() && now.Sub(recordedAt) > p.config.MaxAge:
+		reason = LocationRejectStale
+	}
+	if reason == "" {
+		return ""
+	}
+
+	if p.counter != nil {
+		p.counter.ObserveLocationRejection(reason, p.config.Strict)
+	}
+	if !p.config.Strict {
+		return ""
+	}
+	return reason
+}
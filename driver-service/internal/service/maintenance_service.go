@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MaintenanceService interface {
+	LogMaintenance(ctx context.Context, driverID string, req *models.CreateMaintenanceRequest) (*models.MaintenanceRecord, error)
+	ListMaintenance(ctx context.Context, driverID string) ([]models.MaintenanceRecord, error)
+	DueMaintenance(ctx context.Context, driverID string) ([]models.MaintenanceDue, error)
+	SendDueReminders(ctx context.Context) (int, error)
+}
+
+type maintenanceService struct {
+	maintenanceRepo repository.MaintenanceRepository
+	driverRepo      repository.DriverRepository
+	now             func() time.Time
+}
+
+func NewMaintenanceService(maintenanceRepo repository.MaintenanceRepository, driverRepo repository.DriverRepository) MaintenanceService {
+	return &maintenanceService{
+		maintenanceRepo: maintenanceRepo,
+		driverRepo:      driverRepo,
+		now:             time.Now,
+	}
+}
+
+func (s *maintenanceService) LogMaintenance(ctx context.Context, driverID string, req *models.CreateMaintenanceRequest) (*models.MaintenanceRecord, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	driver, err := s.driverRepo.FindByID(ctx, driverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find driver: %w", err)
+	}
+
+	performedAt := s.now()
+	if req.PerformedAt != nil {
+		performedAt = *req.PerformedAt
+	}
+
+	record := &models.MaintenanceRecord{
+		ID:                  primitive.NewObjectID(),
+		DriverID:            driver.ID,
+		Plate:               driver.Plate,
+		Type:                req.Type,
+		OdometerKm:          req.OdometerKm,
+		TraveledKmAtService: driver.TraveledKm,
+		Notes:               req.Notes,
+		PerformedAt:         performedAt,
+	}
+
+	if _, err := s.maintenanceRepo.Create(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to log maintenance: %w", err)
+	}
+
+	return record, nil
+}
+
+func (s *maintenanceService) ListMaintenance(ctx context.Context, driverID string) ([]models.MaintenanceRecord, error) {
+	records, err := s.maintenanceRepo.FindByDriver(ctx, driverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance: %w", err)
+	}
+
+	return records, nil
+}
+
+func (s *maintenanceService) DueMaintenance(ctx context.Context, driverID string) ([]models.MaintenanceDue, error) {
+	driver, err := s.driverRepo.FindByID(ctx, driverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find driver: %w", err)
+	}
+
+	records, err := s.maintenanceRepo.FindByDriver(ctx, driverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance: %w", err)
+	}
+
+	var due []models.MaintenanceDue
+	for _, record := range latestByType(records) {
+		if item, ok := s.dueItem(driver, record); ok {
+			due = append(due, item)
+		}
+	}
+
+	return due, nil
+}
+
+// SendDueReminders checks every driver with a maintenance history and emits
+// one reminder per overdue service entry. It returns the number of reminders
+// sent.
+func (s *maintenanceService) SendDueReminders(ctx context.Context) (int, error) {
+	driverIDs, err := s.maintenanceRepo.FindDriverIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, driverID := range driverIDs {
+		driver, err := s.driverRepo.FindByID(ctx, driverID)
+		if err != nil {
+			continue
+		}
+
+		records, err := s.maintenanceRepo.FindByDriver(ctx, driverID)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, record := range latestByType(records) {
+			item, ok := s.dueItem(driver, record)
+			if !ok || record.ReminderSentAt != nil {
+				continue
+			}
+
+			notifyMaintenanceDue(ctx, driver, item)
+			if err := s.maintenanceRepo.MarkReminderSent(ctx, record.ID, s.now()); err != nil {
+				return sent, err
+			}
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+func (s *maintenanceService) dueItem(driver *models.Driver, record models.MaintenanceRecord) (models.MaintenanceDue, bool) {
+	interval, ok := models.MaintenanceIntervals[record.Type]
+	if !ok {
+		return models.MaintenanceDue{}, false
+	}
+
+	kmSince := driver.TraveledKm - record.TraveledKmAtService
+	item := models.MaintenanceDue{
+		Type:           record.Type,
+		LastServicedAt: record.PerformedAt,
+		KmSinceService: kmSince,
+		DueByKm:        interval.Km > 0 && kmSince >= interval.Km,
+		DueByTime:      interval.Duration > 0 && s.now().Sub(record.PerformedAt) >= interval.Duration,
+	}
+
+	return item, item.DueByKm || item.DueByTime
+}
+
+// latestByType keeps the most recent record per maintenance type; records
+// are expected newest first.
+func latestByType(records []models.MaintenanceRecord) []models.MaintenanceRecord {
+	seen := make(map[string]bool)
+	var latest []models.MaintenanceRecord
+	for _, record := range records {
+		if seen[record.Type] {
+			continue
+		}
+		seen[record.Type] = true
+		latest = append(latest, record)
+	}
+	return latest
+}
+
+func notifyMaintenanceDue(ctx context.Context, driver *models.Driver, item models.MaintenanceDue) {
+	logging.FromContext(ctx).Info("Maintenance reminder",
+		"driver_id", driver.ID.Hex(), "plate", driver.Plate, "type", item.Type, "km_since_service", item.KmSinceService, "last_serviced_at", item.LastServicedAt.Format("2006-01-02"))
+}
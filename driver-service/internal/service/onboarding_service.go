@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// OnboardingRecorder notes when a driver reaches an onboarding step.
+// Recording is best effort and never fails the change that got them there.
+type OnboardingRecorder interface {
+	Record(ctx context.Context, driverID, step string)
+}
+
+// OnboardingService tracks drivers through the onboarding steps and reports
+// where applicants stall.
+type OnboardingService interface {
+	OnboardingRecorder
+	Funnel(ctx context.Context, fleetID string, from, to time.Time) (*models.OnboardingFunnel, error)
+	// Stalled lists the drivers who got up to the step before the given
+	// one and have not reached it for at least olderThan.
+	Stalled(ctx context.Context, fleetID, step string, olderThan time.Duration, limit int) ([]models.StalledDriver, error)
+}
+
+type onboardingService struct {
+	onboardingRepo repository.OnboardingRepository
+	now            func() time.Time
+}
+
+func NewOnboardingService(onboardingRepo repository.OnboardingRepository) OnboardingService {
+	return &onboardingService{
+		onboardingRepo: onboardingRepo,
+		now:            time.Now,
+	}
+}
+
+func (s *onboardingService) Record(ctx context.Context, driverID, step string) {
+	if err := s.onboardingRepo.MarkStep(ctx, driverID, step, s.now()); err != nil {
+		logging.FromContext(ctx).Warn("Failed to record onboarding step", "step", step, "driver_id", driverID, "error", err)
+	}
+}
+
+func (s *onboardingService) Funnel(ctx context.Context, fleetID string, from, to time.Time) (*models.OnboardingFunnel, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrValidationFailed)
+	}
+
+	counts, err := s.onboardingRepo.CountSteps(ctx, fleetID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	byIndex := make(map[int]models.OnboardingStepCount, len(counts))
+	for _, count := range counts {
+		byIndex[count.Index] = count
+	}
+
+	funnel := &models.OnboardingFunnel{
+		FleetID: fleetID,
+		From:    from,
+		To:      to,
+		Steps:   make([]models.OnboardingFunnelStep, len(models.OnboardingSteps)),
+	}
+	for i, step := range models.OnboardingSteps {
+		count := byIndex[i]
+		funnelStep := models.OnboardingFunnelStep{
+			Step:    step,
+			Reached: count.Reached,
+		}
+		if count.Reached > 0 {
+			avgHours := count.AvgHours
+			funnelStep.AvgHours = &avgHours
+		}
+		if i == 0 {
+			if count.Reached > 0 {
+				funnelStep.Conversion = 1
+			}
+		} else if previous := funnel.Steps[i-1].Reached; previous > 0 {
+			funnelStep.DroppedOff = previous - count.Reached
+			funnelStep.Conversion = float64(count.Reached) / float64(previous)
+		}
+		funnel.Steps[i] = funnelStep
+	}
+
+	return funnel, nil
+}
+
+func (s *onboardingService) Stalled(ctx context.Context, fleetID, step string, olderThan time.Duration, limit int) ([]models.StalledDriver, error) {
+	index := models.OnboardingStepIndex(step)
+	if index < 1 {
+		return nil, fmt.Errorf("%w: step must be one of the steps after %s", ErrValidationFailed, models.OnboardingRegistered)
+	}
+	if limit < 1 || limit > models.MaxStalledOnboardingList {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidationFailed, models.MaxStalledOnboardingList)
+	}
+
+	return s.onboardingRepo.FindStalled(ctx, fleetID, index, s.now().Add(-olderThan), limit)
+}
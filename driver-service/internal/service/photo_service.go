@@ -0,0 +1,338 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/facedetect"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+	"github.com/taxihub/driver-service/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// driverPhotoPath is where an approved photo is served; it becomes the
+// driver's photo_url on approval.
+const driverPhotoPath = "/api/v1/drivers/%s/photo"
+
+// maxPhotoAspectRatio is how much longer one side of a profile photo may be
+// than the other.
+const maxPhotoAspectRatio = 2.0
+
+// PhotoService runs uploaded driver photos through automated checks and an
+// admin approval queue. Riders only ever see the approved photo.
+type PhotoService interface {
+	Upload(ctx context.Context, driverID string, image []byte, contentType string) (*models.DriverPhoto, error)
+	ListForDriver(ctx context.Context, driverID string) ([]models.DriverPhoto, error)
+	ListByStatus(ctx context.Context, status string, limit int) ([]models.DriverPhoto, error)
+	// GetApproved returns the photo riders see, with its image.
+	GetApproved(ctx context.Context, driverID string) (*models.DriverPhoto, error)
+	// Get returns any photo with its image, for reviewers.
+	Get(ctx context.Context, id string) (*models.DriverPhoto, error)
+	// OpenImage opens a photo's image, wherever it is kept; the caller
+	// closes it.
+	OpenImage(ctx context.Context, photo *models.DriverPhoto) (io.ReadCloser, error)
+	Approve(ctx context.Context, id string, req *models.ReviewPhotoRequest) (*models.DriverPhoto, error)
+	Reject(ctx context.Context, id string, req *models.ReviewPhotoRequest) (*models.DriverPhoto, error)
+}
+
+type photoService struct {
+	photoRepo    repository.PhotoRepository
+	driverRepo   repository.DriverRepository
+	detector     facedetect.Detector
+	store        storage.Store
+	minDimension int
+	onboarding   OnboardingRecorder
+	now          func() time.Time
+}
+
+// NewPhotoService creates the photo service. Images go to store when it is
+// set and are kept in MongoDB otherwise. onboarding may be nil.
+func NewPhotoService(photoRepo repository.PhotoRepository, driverRepo repository.DriverRepository, detector facedetect.Detector, store storage.Store, minDimension int, onboarding OnboardingRecorder) PhotoService {
+	return &photoService{
+		photoRepo:    photoRepo,
+		driverRepo:   driverRepo,
+		detector:     detector,
+		store:        store,
+		minDimension: minDimension,
+		onboarding:   onboarding,
+		now:          time.Now,
+	}
+}
+
+// Upload stores the photo with the results of the automated checks. A photo
+// failing any check is rejected straight away; otherwise it waits for an
+// admin and replaces the driver's previous pending photo.
+func (s *photoService) Upload(ctx context.Context, driverID string, data []byte, contentType string) (*models.DriverPhoto, error) {
+	if !uploadImageTypes[contentType] {
+		return nil, ErrUnsupportedImageType
+	}
+
+	driver, err := s.driverRepo.FindByID(ctx, driverID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDriverNotFound) {
+			return nil, ErrDriverNotFound
+		}
+		return nil, fmt.Errorf("failed to find driver: %w", err)
+	}
+
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImageType
+	}
+
+	now := s.now()
+	photo := &models.DriverPhoto{
+		DriverID:    driver.ID,
+		ContentType: contentType,
+		Size:        len(data),
+		Width:       imageConfig.Width,
+		Height:      imageConfig.Height,
+		Image:       data,
+		Checks: []models.PhotoCheck{
+			s.checkDimensions(imageConfig.Width, imageConfig.Height),
+			checkAspectRatio(imageConfig.Width, imageConfig.Height),
+			s.checkFace(ctx, data, contentType),
+		},
+		Status:    models.PhotoPending,
+		CreatedAt: now,
+	}
+	if failed := photo.FailedChecks(); len(failed) > 0 {
+		photo.Status = models.PhotoRejected
+		photo.ReviewNote = "failed automated checks: " + strings.Join(failed, ", ")
+		photo.ReviewedBy = models.AutomatedReviewer
+		photo.ReviewedAt = &now
+	}
+
+	if s.store != nil {
+		photo.ID = primitive.NewObjectID()
+		photo.StorageKey = path.Join("photos", config.TenantFromContext(ctx), photo.DriverID.Hex(), photo.ID.Hex())
+		if err := s.store.Put(ctx, photo.StorageKey, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+			return nil, fmt.Errorf("failed to store photo: %w", err)
+		}
+		photo.Image = nil
+	}
+
+	if err := s.photoRepo.Create(ctx, photo); err != nil {
+		if photo.StorageKey != "" {
+			if deleteErr := s.store.Delete(ctx, photo.StorageKey); deleteErr != nil {
+				logging.FromContext(ctx).Error("Failed to delete orphaned photo", "storage_key", photo.StorageKey, "error", deleteErr)
+			}
+		}
+		return nil, err
+	}
+
+	if photo.Status == models.PhotoPending {
+		if err := s.photoRepo.Supersede(ctx, photo.DriverID, photo.ID, models.PhotoPending); err != nil {
+			logging.FromContext(ctx).Error("Failed to supersede pending photos", "driver_id", driverID, "error", err)
+		}
+		// Photos rejected by the checks do not move onboarding on
+		if s.onboarding != nil {
+			s.onboarding.Record(ctx, driverID, models.OnboardingPhotoSubmitted)
+		}
+	}
+
+	return photo, nil
+}
+
+func (s *photoService) checkDimensions(width, height int) models.PhotoCheck {
+	check := models.PhotoCheck{Name: "dimensions", Result: models.PhotoCheckPassed}
+	if width < s.minDimension || height < s.minDimension {
+		check.Result = models.PhotoCheckFailed
+		check.Detail = fmt.Sprintf("%dx%d is smaller than %dx%d", width, height, s.minDimension, s.minDimension)
+	}
+	return check
+}
+
+func checkAspectRatio(width, height int) models.PhotoCheck {
+	check := models.PhotoCheck{Name: "aspect_ratio", Result: models.PhotoCheckPassed}
+	long, short := width, height
+	if short > long {
+		long, short = short, long
+	}
+	if short == 0 || float64(long)/float64(short) > maxPhotoAspectRatio {
+		check.Result = models.PhotoCheckFailed
+		check.Detail = fmt.Sprintf("%dx%d has an aspect ratio above 2:1", width, height)
+	}
+	return check
+}
+
+// checkFace requires exactly one face. Without a detector, or when it is
+// unavailable, the check is skipped and left to the reviewer.
+func (s *photoService) checkFace(ctx context.Context, data []byte, contentType string) models.PhotoCheck {
+	check := models.PhotoCheck{Name: "face"}
+	if s.detector == nil {
+		check.Result = models.PhotoCheckSkipped
+		check.Detail = "face detection is not configured"
+		return check
+	}
+
+	faces, err := s.detector.CountFaces(ctx, data, contentType)
+	switch {
+	case err != nil:
+		check.Result = models.PhotoCheckSkipped
+		check.Detail = err.Error()
+	case faces != 1:
+		check.Result = models.PhotoCheckFailed
+		check.Detail = fmt.Sprintf("found %d faces, expected 1", faces)
+	default:
+		check.Result = models.PhotoCheckPassed
+	}
+	return check
+}
+
+func (s *photoService) ListForDriver(ctx context.Context, driverID string) ([]models.DriverPhoto, error) {
+	return s.photoRepo.FindByDriver(ctx, driverID)
+}
+
+func (s *photoService) ListByStatus(ctx context.Context, status string, limit int) ([]models.DriverPhoto, error) {
+	return s.photoRepo.FindByStatus(ctx, status, limit)
+}
+
+func (s *photoService) GetApproved(ctx context.Context, driverID string) (*models.DriverPhoto, error) {
+	photo, err := s.photoRepo.FindApproved(ctx, driverID)
+	if err != nil {
+		if errors.Is(err, repository.ErrPhotoNotFound) {
+			return nil, ErrPhotoNotFound
+		}
+		return nil, err
+	}
+	return photo, nil
+}
+
+func (s *photoService) Get(ctx context.Context, id string) (*models.DriverPhoto, error) {
+	photo, err := s.photoRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrPhotoNotFound) {
+			return nil, ErrPhotoNotFound
+		}
+		return nil, err
+	}
+	return photo, nil
+}
+
+func (s *photoService) OpenImage(ctx context.Context, photo *models.DriverPhoto) (io.ReadCloser, error) {
+	if photo.StorageKey == "" {
+		return io.NopCloser(bytes.NewReader(photo.Image)), nil
+	}
+	if s.store == nil {
+		return nil, errors.New("photo is in object storage, but STORAGE_BACKEND is not configured")
+	}
+
+	image, err := s.store.Get(ctx, photo.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrPhotoNotFound
+		}
+		return nil, err
+	}
+	return image, nil
+}
+
+// Approve claims the photo first so two admins cannot both decide it, then
+// points the driver's photo_url at it. If that fails the photo is put back to
+// pending.
+func (s *photoService) Approve(ctx context.Context, id string, req *models.ReviewPhotoRequest) (*models.DriverPhoto, error) {
+	photo, err := s.review(ctx, id, req, models.PhotoApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.publish(ctx, photo); err != nil {
+		reverted := *photo
+		reverted.Status = models.PhotoPending
+		reverted.ReviewNote, reverted.ReviewedBy, reverted.ReviewedAt = "", "", nil
+		if revertErr := s.photoRepo.Transition(ctx, &reverted, models.PhotoApproved); revertErr != nil {
+			logging.FromContext(ctx).Error("Failed to revert photo to pending", "photo_id", photo.ID.Hex(), "error", revertErr)
+		}
+		return nil, err
+	}
+
+	if err := s.photoRepo.Supersede(ctx, photo.DriverID, photo.ID, models.PhotoApproved); err != nil {
+		logging.FromContext(ctx).Error("Failed to supersede approved photos", "driver_id", photo.DriverID.Hex(), "error", err)
+	}
+
+	notifyPhotoOutcome(ctx, photo)
+	return photo, nil
+}
+
+func (s *photoService) Reject(ctx context.Context, id string, req *models.ReviewPhotoRequest) (*models.DriverPhoto, error) {
+	photo, err := s.review(ctx, id, req, models.PhotoRejected)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyPhotoOutcome(ctx, photo)
+	return photo, nil
+}
+
+func (s *photoService) review(ctx context.Context, id string, req *models.ReviewPhotoRequest, status string) (*models.DriverPhoto, error) {
+	if req == nil {
+		req = &models.ReviewPhotoRequest{}
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	photo, err := s.photoRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrPhotoNotFound) {
+			return nil, ErrPhotoNotFound
+		}
+		return nil, err
+	}
+	if photo.Status != models.PhotoPending {
+		return nil, ErrPhotoNotPending
+	}
+
+	reviewedAt := s.now()
+	photo.Status = status
+	photo.ReviewNote = req.Note
+	photo.ReviewedBy = req.ReviewedBy
+	photo.ReviewedAt = &reviewedAt
+
+	if err := s.photoRepo.Transition(ctx, photo, models.PhotoPending); err != nil {
+		if errors.Is(err, repository.ErrPhotoConflict) {
+			return nil, ErrPhotoNotPending
+		}
+		return nil, err
+	}
+
+	return photo, nil
+}
+
+func (s *photoService) publish(ctx context.Context, photo *models.DriverPhoto) error {
+	driverID := photo.DriverID.Hex()
+	driver, err := s.driverRepo.FindByID(ctx, driverID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDriverNotFound) {
+			return ErrDriverNotFound
+		}
+		return fmt.Errorf("failed to find driver: %w", err)
+	}
+
+	driver.PhotoURL = fmt.Sprintf(driverPhotoPath, driverID)
+	driver.UpdatedAt = s.now()
+
+	if err := s.driverRepo.Update(ctx, driverID, driver, models.DriverFieldPhotoURL); err != nil {
+		return fmt.Errorf("failed to update driver: %w", err)
+	}
+
+	return nil
+}
+
+func notifyPhotoOutcome(ctx context.Context, photo *models.DriverPhoto) {
+	logging.FromContext(ctx).Info("Photo reviewed",
+		"photo_id", photo.ID.Hex(), "driver_id", photo.DriverID.Hex(), "status", photo.Status, "note", photo.ReviewNote)
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// PlateReservationService holds plates for multi-step onboarding. A client
+// reserves the plate when the flow starts and passes the token with the
+// final create, which fails for anyone else while the reservation lasts.
+type PlateReservationService interface {
+	Reserve(ctx context.Context, req *models.ReservePlateRequest) (*models.PlateReservation, error)
+	Release(ctx context.Context, plate, token string) error
+	// Check fails with ErrPlateReserved if another token holds the plate.
+	Check(ctx context.Context, plate, token string) error
+}
+
+type plateReservationService struct {
+	reservationRepo repository.PlateReservationRepository
+	driverRepo      repository.DriverRepository
+	ttl             time.Duration
+	now             func() time.Time
+}
+
+func NewPlateReservationService(reservationRepo repository.PlateReservationRepository, driverRepo repository.DriverRepository, ttl time.Duration) PlateReservationService {
+	return &plateReservationService{
+		reservationRepo: reservationRepo,
+		driverRepo:      driverRepo,
+		ttl:             ttl,
+		now:             time.Now,
+	}
+}
+
+func (s *plateReservationService) Reserve(ctx context.Context, req *models.ReservePlateRequest) (*models.PlateReservation, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	_, err := s.driverRepo.FindByPlate(ctx, req.Plate)
+	if err == nil {
+		return nil, ErrPlateTaken
+	}
+	if !errors.Is(err, repository.ErrDriverNotFound) {
+		return nil, fmt.Errorf("failed to check plate: %w", err)
+	}
+
+	token := req.Token
+	if token == "" {
+		if token, err = newReservationToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	now := s.now()
+	reservation := &models.PlateReservation{
+		ID:        models.PlateKey(req.Plate),
+		Token:     token,
+		ExpiresAt: now.Add(s.ttl),
+		CreatedAt: now,
+	}
+	if err := s.reservationRepo.Reserve(ctx, reservation, now); err != nil {
+		if errors.Is(err, repository.ErrPlateReserved) {
+			return nil, ErrPlateReserved
+		}
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func (s *plateReservationService) Release(ctx context.Context, plate, token string) error {
+	if err := s.reservationRepo.Release(ctx, models.PlateKey(plate), token); err != nil {
+		if errors.Is(err, repository.ErrPlateReservationNotFound) {
+			return ErrPlateReservationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *plateReservationService) Check(ctx context.Context, plate, token string) error {
+	reservation, err := s.reservationRepo.FindByKey(ctx, models.PlateKey(plate))
+	if err != nil {
+		if errors.Is(err, repository.ErrPlateReservationNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to check plate reservation: %w", err)
+	}
+
+	if reservation.Token != token && s.now().Before(reservation.ExpiresAt) {
+		return ErrPlateReserved
+	}
+	return nil
+}
+
+func newReservationToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate reservation token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
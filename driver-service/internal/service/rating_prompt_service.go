@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/events"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+const (
+	// ratingPromptLease is how long a claimed prompt may take to send
+	// before another worker may claim it again.
+	ratingPromptLease = 2 * time.Minute
+	// ratingPromptBatchSize caps the prompts one run sends.
+	ratingPromptBatchSize = 100
+)
+
+// RatingPromptService asks riders to rate their completed trips. Each
+// completed trip gets a prompt, reminded with growing gaps per its fleet's
+// settings until the trip is rated or the reminders run out. Prompts are
+// delivered as trip.rating_prompted events for the rider apps.
+type RatingPromptService interface {
+	// Schedule plans the prompts of a completed trip. A trip whose fleet
+	// has prompts turned off, or that has prompts already, is left alone.
+	Schedule(ctx context.Context, trip *models.Trip) error
+	// Rated stops the prompts of a trip that has been rated and counts it
+	// as converted if it was prompted.
+	Rated(ctx context.Context, tripID string, at time.Time) error
+	Get(ctx context.Context, tripID string) (*models.RatingPrompt, error)
+	// SendDue sends the prompts that are due and returns the number sent.
+	SendDue(ctx context.Context) (int, error)
+
+	// Settings returns the fleet's settings, or the defaults if it has
+	// none of its own.
+	Settings(ctx context.Context, fleetID string) (*models.RatingPromptSettings, error)
+	ListSettings(ctx context.Context) ([]models.RatingPromptSettings, error)
+	UpdateSettings(ctx context.Context, fleetID string, req *models.UpdateRatingPromptSettingsRequest) (*models.RatingPromptSettings, error)
+	// ResetSettings puts the fleet back on the defaults.
+	ResetSettings(ctx context.Context, fleetID string) error
+	Stats(ctx context.Context, fleetID string, since time.Time) (*models.RatingPromptStats, error)
+}
+
+// RatingPromptConfig is the prompt timing of fleets without settings of
+// their own. A MaxPrompts of 0 turns prompts off for them.
+type RatingPromptConfig struct {
+	Delay      time.Duration
+	Reminder   time.Duration
+	MaxPrompts int
+}
+
+type ratingPromptService struct {
+	promptRepo repository.RatingPromptRepository
+	ratingRepo repository.RatingRepository
+	publisher  events.Producer
+	defaults   models.RatingPromptSettings
+	now        func() time.Time
+}
+
+// NewRatingPromptService creates the prompt service. Without a publisher
+// prompts are scheduled but stay pending until one is configured.
+func NewRatingPromptService(promptRepo repository.RatingPromptRepository, ratingRepo repository.RatingRepository, publisher events.Producer, cfg RatingPromptConfig) RatingPromptService {
+	return &ratingPromptService{
+		promptRepo: promptRepo,
+		ratingRepo: ratingRepo,
+		publisher:  publisher,
+		defaults: models.RatingPromptSettings{
+			Enabled:         cfg.MaxPrompts > 0,
+			DelaySeconds:    int(cfg.Delay / time.Second),
+			ReminderSeconds: int(cfg.Reminder / time.Second),
+			MaxPrompts:      cfg.MaxPrompts,
+			Default:         true,
+		},
+		now: time.Now,
+	}
+}
+
+func (s *ratingPromptService) Schedule(ctx context.Context, trip *models.Trip) error {
+	settings, err := s.Settings(ctx, trip.FleetID)
+	if err != nil {
+		return err
+	}
+	if !settings.Enabled {
+		return nil
+	}
+
+	now := s.now()
+	completedAt := now
+	if trip.CompletedAt != nil {
+		completedAt = *trip.CompletedAt
+	}
+	next, ok := settings.NextPromptAt(0, completedAt)
+	if !ok {
+		return nil
+	}
+
+	err = s.promptRepo.Create(ctx, &models.RatingPrompt{
+		TripID:    trip.ID.Hex(),
+		DriverID:  trip.DriverID,
+		FleetID:   trip.FleetID,
+		Status:    models.RatingPromptPending,
+		NextAt:    &next,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil && !errors.Is(err, repository.ErrRatingPromptExists) {
+		return err
+	}
+	return nil
+}
+
+func (s *ratingPromptService) Rated(ctx context.Context, tripID string, at time.Time) error {
+	return s.promptRepo.MarkRated(ctx, tripID, at)
+}
+
+func (s *ratingPromptService) Get(ctx context.Context, tripID string) (*models.RatingPrompt, error) {
+	prompt, err := s.promptRepo.FindByTrip(ctx, tripID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRatingPromptNotFound) {
+			return nil, ErrRatingPromptNotFound
+		}
+		return nil, err
+	}
+	return prompt, nil
+}
+
+func (s *ratingPromptService) SendDue(ctx context.Context) (int, error) {
+	if s.publisher == nil {
+		return 0, nil
+	}
+
+	sent := 0
+	for i := 0; i < ratingPromptBatchSize; i++ {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+
+		now := s.now()
+		prompt, err := s.promptRepo.ClaimDue(ctx, now, now.Add(ratingPromptLease))
+		if err != nil {
+			if errors.Is(err, repository.ErrRatingPromptNotFound) {
+				return sent, nil
+			}
+			return sent, err
+		}
+
+		if s.send(ctx, prompt) {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// send sends a claimed prompt unless the trip has been rated or its fleet
+// turned prompts off, and schedules the next reminder. It reports whether
+// the prompt was sent. On failure the prompt is left claimed, so it is
+// tried again once the lease runs out.
+func (s *ratingPromptService) send(ctx context.Context, prompt *models.RatingPrompt) bool {
+	rated, err := s.ratingRepo.ExistsForTrip(ctx, prompt.TripID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to check trip rating before prompting", "trip_id", prompt.TripID, "error", err)
+		return false
+	}
+	settings, err := s.Settings(ctx, prompt.FleetID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to load rating prompt settings", "trip_id", prompt.TripID, "error", err)
+		return false
+	}
+
+	now := s.now()
+	prompt.UpdatedAt = now
+	prompt.NextAt = nil
+	sent := false
+	switch {
+	case rated:
+		// Missed by Rated, which only logs its failures
+		prompt.Status = models.RatingPromptRated
+		prompt.RatedAt = &now
+	case !settings.Enabled:
+		prompt.Status = models.RatingPromptCancelled
+	default:
+		event := events.NewEvent(events.TripRatingPrompted, config.TenantFromContext(ctx), prompt.DriverID, events.RatingPromptData{
+			TripID:  prompt.TripID,
+			FleetID: prompt.FleetID,
+			Prompt:  prompt.Sent + 1,
+			Final:   prompt.Sent+1 >= settings.MaxPrompts,
+		})
+		if err := s.publisher.Publish(ctx, event); err != nil {
+			logging.FromContext(ctx).Error("Failed to publish rating prompt", "trip_id", prompt.TripID, "error", err)
+			return false
+		}
+
+		sent = true
+		prompt.Sent++
+		prompt.LastSentAt = &now
+		if next, ok := settings.NextPromptAt(prompt.Sent, now); ok {
+			prompt.NextAt = &next
+		} else {
+			prompt.Status = models.RatingPromptExhausted
+		}
+	}
+
+	if err := s.promptRepo.Update(ctx, prompt); err != nil {
+		logging.FromContext(ctx).Error("Failed to update rating prompt", "trip_id", prompt.TripID, "error", err)
+	}
+	return sent
+}
+
+func (s *ratingPromptService) Settings(ctx context.Context, fleetID string) (*models.RatingPromptSettings, error) {
+	defaults := s.defaults
+	defaults.FleetID = fleetID
+	if fleetID == "" {
+		return &defaults, nil
+	}
+
+	settings, err := s.promptRepo.FindSettings(ctx, fleetID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRatingPromptSettingsNotFound) {
+			return &defaults, nil
+		}
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (s *ratingPromptService) ListSettings(ctx context.Context) ([]models.RatingPromptSettings, error) {
+	return s.promptRepo.FindAllSettings(ctx)
+}
+
+func (s *ratingPromptService) UpdateSettings(ctx context.Context, fleetID string, req *models.UpdateRatingPromptSettingsRequest) (*models.RatingPromptSettings, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+	if fleetID == "" || len(fleetID) > 64 {
+		return nil, fmt.Errorf("%w: fleet ID must be 1 to 64 characters", ErrValidationFailed)
+	}
+
+	now := s.now()
+	settings := &models.RatingPromptSettings{
+		FleetID:         fleetID,
+		Enabled:         *req.Enabled,
+		DelaySeconds:    req.DelaySeconds,
+		ReminderSeconds: req.ReminderSeconds,
+		MaxPrompts:      req.MaxPrompts,
+		UpdatedAt:       &now,
+	}
+	if err := s.promptRepo.UpsertSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (s *ratingPromptService) ResetSettings(ctx context.Context, fleetID string) error {
+	if err := s.promptRepo.DeleteSettings(ctx, fleetID); err != nil {
+		if errors.Is(err, repository.ErrRatingPromptSettingsNotFound) {
+			return ErrRatingPromptSettingsNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *ratingPromptService) Stats(ctx context.Context, fleetID string, since time.Time) (*models.RatingPromptStats, error) {
+	counts, err := s.promptRepo.CountByStatus(ctx, fleetID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.RatingPromptStats{
+		FleetID:  fleetID,
+		Since:    since,
+		ByPrompt: []models.RatingPromptStep{},
+	}
+	for _, count := range counts {
+		stats.Scheduled += count.Count
+		if count.Sent > 0 {
+			stats.Prompted += count.Count
+		}
+		switch count.Status {
+		case models.RatingPromptRated:
+			if count.Sent > 0 {
+				stats.Converted += count.Count
+			} else {
+				stats.RatedUnprompted += count.Count
+			}
+		case models.RatingPromptPending:
+			stats.Pending += count.Count
+		case models.RatingPromptExhausted:
+			stats.Exhausted += count.Count
+		case models.RatingPromptCancelled:
+			stats.Cancelled += count.Count
+		}
+
+		// A prompt that got n prompts reached steps 1 to n, and a rated one
+		// converted at its last
+		for len(stats.ByPrompt) < count.Sent {
+			stats.ByPrompt = append(stats.ByPrompt, models.RatingPromptStep{Prompt: len(stats.ByPrompt) + 1})
+		}
+		for i := 0; i < count.Sent; i++ {
+			stats.ByPrompt[i].Reached += count.Count
+		}
+		if count.Status == models.RatingPromptRated && count.Sent > 0 {
+			stats.ByPrompt[count.Sent-1].Converted += count.Count
+		}
+	}
+	if stats.Prompted > 0 {
+		stats.ConversionRate = float64(stats.Converted) / float64(stats.Prompted)
+	}
+
+	return stats, nil
+}
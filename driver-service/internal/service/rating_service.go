@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RatingService records riders' ratings of drivers and keeps each driver's
+// average up to date. A rating of a trip stops the trip's rating prompts.
+type RatingService interface {
+	Rate(ctx context.Context, driverID string, req *models.CreateRatingRequest) (*models.RatingResult, error)
+	List(ctx context.Context, driverID string, limit int) ([]models.Rating, error)
+}
+
+// RatingPromptTracker learns which trips have been rated.
+type RatingPromptTracker interface {
+	Rated(ctx context.Context, tripID string, at time.Time) error
+}
+
+type ratingService struct {
+	ratingRepo repository.RatingRepository
+	tripRepo   repository.TripRepository
+	prompts    RatingPromptTracker
+}
+
+// NewRatingService creates the rating service; prompts may be nil.
+func NewRatingService(ratingRepo repository.RatingRepository, tripRepo repository.TripRepository, prompts RatingPromptTracker) RatingService {
+	return &ratingService{
+		ratingRepo: ratingRepo,
+		tripRepo:   tripRepo,
+		prompts:    prompts,
+	}
+}
+
+func (s *ratingService) Rate(ctx context.Context, driverID string, req *models.CreateRatingRequest) (*models.RatingResult, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid driver ID format", ErrValidationFailed)
+	}
+
+	if req.TripID != "" {
+		if err := s.checkTrip(ctx, req.TripID, driverID); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := s.ratingRepo.Create(ctx, &models.Rating{
+		DriverID: objectID,
+		TripID:   req.TripID,
+		Stars:    req.Stars,
+		Comment:  req.Comment,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDriverNotFound):
+			return nil, ErrDriverNotFound
+		case errors.Is(err, repository.ErrTripRated):
+			return nil, ErrTripRated
+		}
+		return nil, fmt.Errorf("failed to rate driver: %w", err)
+	}
+
+	// The prompt sender checks for the rating too, so a miss here only
+	// delays the prompt's settling until it is next due
+	if req.TripID != "" && s.prompts != nil {
+		if err := s.prompts.Rated(ctx, req.TripID, result.Rating.CreatedAt); err != nil {
+			logging.FromContext(ctx).Warn("Failed to settle rating prompt", "trip_id", req.TripID, "error", err)
+		}
+	}
+
+	return result, nil
+}
+
+// checkTrip makes sure the rated trip was completed by the rated driver.
+func (s *ratingService) checkTrip(ctx context.Context, tripID, driverID string) error {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTripNotFound) {
+			return ErrTripNotFound
+		}
+		return fmt.Errorf("failed to find trip: %w", err)
+	}
+
+	switch {
+	case trip.DriverID != driverID:
+		return fmt.Errorf("%w: trip %s was not driven by this driver", ErrValidationFailed, tripID)
+	case trip.Status != models.TripCompleted:
+		return fmt.Errorf("%w: trip %s is %s, not completed", ErrValidationFailed, tripID, trip.Status)
+	}
+	return nil
+}
+
+func (s *ratingService) List(ctx context.Context, driverID string, limit int) ([]models.Rating, error) {
+	if limit < 1 || limit > models.MaxRatingListLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidationFailed, models.MaxRatingListLimit)
+	}
+
+	ratings, err := s.ratingRepo.FindByDriver(ctx, driverID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ratings: %w", err)
+	}
+
+	return ratings, nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// RiderPreferencesService keeps riders' saved ride preferences, which new
+// trips for the rider start with.
+type RiderPreferencesService interface {
+	Get(ctx context.Context, riderID string) (*models.RiderPreferences, error)
+	Update(ctx context.Context, riderID string, req *models.RidePreferences) (*models.RiderPreferences, error)
+	Delete(ctx context.Context, riderID string) error
+}
+
+type riderPreferencesService struct {
+	repo repository.RiderPreferencesRepository
+	now  func() time.Time
+}
+
+func NewRiderPreferencesService(repo repository.RiderPreferencesRepository) RiderPreferencesService {
+	return &riderPreferencesService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+func (s *riderPreferencesService) Get(ctx context.Context, riderID string) (*models.RiderPreferences, error) {
+	preferences, err := s.repo.Find(ctx, riderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRiderPreferencesNotFound) {
+			return nil, ErrRiderPreferencesNotFound
+		}
+		return nil, err
+	}
+	return preferences, nil
+}
+
+func (s *riderPreferencesService) Update(ctx context.Context, riderID string, req *models.RidePreferences) (*models.RiderPreferences, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+	if riderID == "" || len(riderID) > models.MaxRiderIDLength {
+		return nil, fmt.Errorf("%w: rider ID must be 1 to %d characters", ErrValidationFailed, models.MaxRiderIDLength)
+	}
+
+	preferences := &models.RiderPreferences{
+		RiderID:         riderID,
+		RidePreferences: *req,
+		UpdatedAt:       s.now(),
+	}
+	if err := s.repo.Upsert(ctx, preferences); err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+func (s *riderPreferencesService) Delete(ctx context.Context, riderID string) error {
+	if err := s.repo.Delete(ctx, riderID); err != nil {
+		if errors.Is(err, repository.ErrRiderPreferencesNotFound) {
+			return ErrRiderPreferencesNotFound
+		}
+		return err
+	}
+	return nil
+}
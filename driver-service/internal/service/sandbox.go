@@ -0,0 +1,40 @@
+package service
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// SandboxServices hands out one isolated synthetic dataset per sandbox API
+// key. Datasets are created lazily and seeded from the key, so the same key
+// always starts from the same drivers.
+type SandboxServices struct {
+	seed     int64
+	mu       sync.Mutex
+	services map[string]DriverService
+}
+
+func NewSandboxServices(seed int64) *SandboxServices {
+	return &SandboxServices{
+		seed:     seed,
+		services: make(map[string]DriverService),
+	}
+}
+
+func (s *SandboxServices) For(apiKey string) DriverService {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if svc, ok := s.services[apiKey]; ok {
+		return svc
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(apiKey))
+	svc := NewDriverService(repository.NewSandboxDriverRepository(s.seed^int64(hash.Sum64())), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	s.services[apiKey] = svc
+
+	return svc
+}
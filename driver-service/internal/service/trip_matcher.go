@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/config"
+	"github.com/taxihub/driver-service/internal/dispatch"
+	"github.com/taxihub/driver-service/internal/events"
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+)
+
+// TripMatcherConfig bounds automatic matching. OfferTimeout is how long a
+// driver has to accept an offer; MaxAttempts is how many drivers a trip is
+// offered to before it is left for a dispatcher.
+type TripMatcherConfig struct {
+	OfferTimeout time.Duration
+	MaxAttempts  int
+}
+
+// TripMatcher picks the drivers a trip is offered to and sends the offers.
+// Candidates are nearby available drivers of the trip's fleet and taxi type,
+// ranked with the fleet's dispatch strategy; drivers who already declined
+// the trip and drivers inside an active dispatch pause are skipped.
+type TripMatcher struct {
+	drivers    DriverService
+	dispatcher *dispatch.Dispatcher
+	pauses     DispatchPauseService
+	offers     OfferSender
+	publisher  events.Producer
+	config     TripMatcherConfig
+}
+
+// NewTripMatcher creates the matcher; offers and publisher may be nil, in
+// which case offers are only visible on the trip.
+func NewTripMatcher(drivers DriverService, dispatcher *dispatch.Dispatcher, pauses DispatchPauseService, offers OfferSender, publisher events.Producer, cfg TripMatcherConfig) *TripMatcher {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	return &TripMatcher{
+		drivers:    drivers,
+		dispatcher: dispatcher,
+		pauses:     pauses,
+		offers:     offers,
+		publisher:  publisher,
+		config:     cfg,
+	}
+}
+
+// Candidates returns the drivers to offer the trip to, best first, with the
+// strategy that ranked them. A pickup inside a dispatch pause fails with a
+// DispatchPausedError.
+func (m *TripMatcher) Candidates(ctx context.Context, trip *models.Trip) ([]models.DriverWithDistance, dispatch.Strategy, error) {
+	pauses, err := m.pauses.Active(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load dispatch pauses: %w", err)
+	}
+	for i := range pauses {
+		if pauses[i].Contains(trip.Pickup) {
+			return nil, nil, &DispatchPausedError{Pause: &pauses[i]}
+		}
+	}
+
+	nearby, err := m.drivers.FindNearbyDrivers(ctx, trip.Pickup.Lat, trip.Pickup.Lon, models.NearbyFilter{TaxiType: trip.TaxiType})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	declined := make(map[string]bool, len(trip.DeclinedBy))
+	for _, driverID := range trip.DeclinedBy {
+		declined[driverID] = true
+	}
+	candidates := nearby[:0:0]
+	for _, driver := range nearby {
+		if trip.FleetID != "" && driver.FleetID != trip.FleetID {
+			continue
+		}
+		if declined[driver.ID.Hex()] || inPausedZone(pauses, driver.Location) {
+			continue
+		}
+		candidates = append(candidates, driver)
+	}
+
+	strategy := m.dispatcher.StrategyFor(trip.FleetID)
+	return strategy.Rank(trip.FleetID, candidates), strategy, nil
+}
+
+// Exhausted reports whether the trip has been offered to as many drivers
+// as allowed.
+func (m *TripMatcher) Exhausted(trip *models.Trip) bool {
+	return len(trip.DeclinedBy) >= m.config.MaxAttempts
+}
+
+// NewOffer returns the offer of the trip to a candidate, made now.
+func (m *TripMatcher) NewOffer(candidate models.DriverWithDistance, strategy dispatch.Strategy, now time.Time) *models.TripOffer {
+	return &models.TripOffer{
+		DriverID:   candidate.ID.Hex(),
+		DistanceKm: candidate.DistanceKm,
+		Strategy:   strategy.Name(),
+		OfferedAt:  now,
+		ExpiresAt:  now.Add(m.config.OfferTimeout),
+	}
+}
+
+// Offered tells the strategy and the driver about a stored offer. The live
+// message and the trip.offered event are best effort: a driver who misses
+// both lets the offer expire.
+func (m *TripMatcher) Offered(ctx context.Context, trip *models.Trip, strategy dispatch.Strategy) {
+	offer := trip.Offer
+	strategy.Assigned(trip.FleetID, offer.DriverID)
+
+	if m.offers != nil {
+		m.offers.SendOffer(ctx, offer.DriverID, models.LiveOfferMessage{
+			Type:        models.LiveOffer,
+			DriverID:    offer.DriverID,
+			FleetID:     trip.FleetID,
+			TripID:      trip.ID.Hex(),
+			Pickup:      trip.Pickup,
+			DistanceKm:  offer.DistanceKm,
+			OfferedAt:   offer.OfferedAt,
+			ExpiresAt:   &offer.ExpiresAt,
+			Preferences: trip.Preferences,
+		})
+	}
+
+	if m.publisher != nil {
+		data := events.TripOfferData{
+			TripID:     trip.ID.Hex(),
+			FleetID:    trip.FleetID,
+			PickupLat:  trip.Pickup.Lat,
+			PickupLon:  trip.Pickup.Lon,
+			DistanceKm: offer.DistanceKm,
+			ExpiresAt:  offer.ExpiresAt,
+		}
+		if preferences := trip.Preferences; preferences != nil {
+			data.QuietRide = preferences.QuietRide
+			data.Temperature = preferences.Temperature
+			data.Music = preferences.Music
+		}
+		event := events.NewEvent(events.TripOffered, config.TenantFromContext(ctx), offer.DriverID, data)
+		if err := m.publisher.Publish(ctx, event); err != nil {
+			logging.FromContext(ctx).Error("Failed to publish trip event", "event", events.TripOffered, "trip_id", trip.ID.Hex(), "error", err)
+		}
+	}
+}
@@ -0,0 +1,507 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/logging"
+	"github.com/taxihub/driver-service/internal/models"
+	"github.com/taxihub/driver-service/internal/repository"
+)
+
+// TripService runs ride requests through their lifecycle: created, driver
+// assigned, en route, started, and completed or cancelled. Assigning a
+// driver makes them busy; completing or cancelling the trip makes them
+// available again.
+//
+// Drivers are assigned by a dispatcher, or matched automatically: Dispatch
+// offers the trip to the best candidate, reserving them as the assigned
+// driver until they accept. A declined or expired offer releases the driver
+// and goes to the next candidate.
+type TripService interface {
+	Create(ctx context.Context, createdBy string, req *models.CreateTripRequest) (*models.Trip, error)
+	Get(ctx context.Context, id string) (*models.Trip, error)
+	List(ctx context.Context, driverID, status string, limit int) ([]models.Trip, error)
+	Assign(ctx context.Context, id string, req *models.AssignTripRequest) (*models.Trip, error)
+	UpdateStatus(ctx context.Context, id string, req *models.UpdateTripStatusRequest) (*models.Trip, error)
+	Dispatch(ctx context.Context, id string) (*models.Trip, error)
+	// AcceptOffer and DeclineOffer answer the pending offer of the trip. A
+	// driverID restricts them to that driver's offer; staff pass "".
+	AcceptOffer(ctx context.Context, id, driverID string) (*models.Trip, error)
+	DeclineOffer(ctx context.Context, id, driverID string) (*models.Trip, error)
+	// ExpireOffers withdraws the offers nobody answered in time and offers
+	// their trips to the next candidates, returning how many expired.
+	ExpireOffers(ctx context.Context) (int, error)
+	// UpdatePreferences replaces the preferences of a trip still waiting
+	// for a driver; a matched driver has already been shown them.
+	UpdatePreferences(ctx context.Context, id string, req *models.RidePreferences) (*models.Trip, error)
+	// AcknowledgePreferences records that the assigned driver has seen the
+	// trip's preferences. A driverID restricts it to that driver's trip;
+	// staff pass "".
+	AcknowledgePreferences(ctx context.Context, id, driverID string) (*models.Trip, error)
+}
+
+// DriverStatusSetter changes a driver's availability.
+type DriverStatusSetter interface {
+	SetStatus(ctx context.Context, id string, status string) error
+}
+
+// RatingPromptScheduler plans the rating prompts of completed trips.
+type RatingPromptScheduler interface {
+	Schedule(ctx context.Context, trip *models.Trip) error
+}
+
+// RiderPreferencesFinder loads a rider's saved ride preferences.
+type RiderPreferencesFinder interface {
+	Get(ctx context.Context, riderID string) (*models.RiderPreferences, error)
+}
+
+// expiredOfferBatch is how many expired offers one ExpireOffers run handles.
+const expiredOfferBatch = 100
+
+type tripService struct {
+	tripRepo   repository.TripRepository
+	driverRepo repository.DriverRepository
+	statuses   DriverStatusSetter
+	matcher    *TripMatcher
+	prompts    RatingPromptScheduler
+	onboarding OnboardingRecorder
+	riders     RiderPreferencesFinder
+	earnings   EarningsRecorder
+	now        func() time.Time
+}
+
+// NewTripService creates the trip service. Without matcher, trips can only
+// be assigned by hand; without prompts, riders are not asked for ratings.
+// onboarding, which learns of drivers' first trips, and riders, which holds
+// riders' saved preferences, may be nil.
+// Without earnings, the fares of completed trips are not booked.
+func NewTripService(tripRepo repository.TripRepository, driverRepo repository.DriverRepository, statuses DriverStatusSetter, matcher *TripMatcher, prompts RatingPromptScheduler, onboarding OnboardingRecorder, riders RiderPreferencesFinder, earnings EarningsRecorder) TripService {
+	return &tripService{
+		tripRepo:   tripRepo,
+		driverRepo: driverRepo,
+		statuses:   statuses,
+		matcher:    matcher,
+		prompts:    prompts,
+		onboarding: onboarding,
+		riders:     riders,
+		earnings:   earnings,
+		now:        time.Now,
+	}
+}
+
+func (s *tripService) Create(ctx context.Context, createdBy string, req *models.CreateTripRequest) (*models.Trip, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+
+	now := s.now()
+	trip := &models.Trip{
+		FleetID:   req.FleetID,
+		TaxiType:  req.TaxiType,
+		Pickup:    models.Location{Lat: req.PickupLat, Lon: req.PickupLon},
+		Notes:     req.Notes,
+		Status:    models.TripCreated,
+		RiderID:   req.RiderID,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if req.DropoffLat != nil && req.DropoffLon != nil {
+		trip.Dropoff = &models.Location{Lat: *req.DropoffLat, Lon: *req.DropoffLon}
+	}
+	switch {
+	case req.Preferences != nil:
+		trip.Preferences = ridePreferences(req.Preferences)
+	case req.RiderID != "" && s.riders != nil:
+		trip.Preferences = s.savedPreferences(ctx, req.RiderID)
+	}
+
+	if err := s.tripRepo.Create(ctx, trip); err != nil {
+		return nil, err
+	}
+	return trip, nil
+}
+
+func (s *tripService) Get(ctx context.Context, id string) (*models.Trip, error) {
+	trip, err := s.tripRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrTripNotFound) {
+			return nil, ErrTripNotFound
+		}
+		return nil, err
+	}
+	return trip, nil
+}
+
+func (s *tripService) List(ctx context.Context, driverID, status string, limit int) ([]models.Trip, error) {
+	return s.tripRepo.Find(ctx, driverID, status, limit)
+}
+
+// Assign gives a created trip to an approved, available driver of the
+// trip's fleet and taxi type, and marks the driver busy.
+func (s *tripService) Assign(ctx context.Context, id string, req *models.AssignTripRequest) (*models.Trip, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+
+	trip, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if trip.Status != models.TripCreated {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTripTransition, trip.Status, models.TripDriverAssigned)
+	}
+
+	driver, err := s.driverRepo.FindByID(ctx, req.DriverID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDriverNotFound) {
+			return nil, ErrDriverNotFound
+		}
+		return nil, fmt.Errorf("failed to find driver: %w", err)
+	}
+	if err := checkAssignable(trip, driver); err != nil {
+		return nil, err
+	}
+
+	trip.DriverID = req.DriverID
+	trip.MarkStatus(models.TripDriverAssigned, s.now())
+	if err := s.tripRepo.Update(ctx, trip); err != nil {
+		return nil, s.updateError(err, models.TripDriverAssigned)
+	}
+	s.reserveDriver(ctx, trip)
+
+	return trip, nil
+}
+
+// checkAssignable tells why the driver cannot take the trip, if they cannot.
+func checkAssignable(trip *models.Trip, driver *models.Driver) error {
+	switch {
+	case !driver.IsApproved():
+		return fmt.Errorf("%w: driver is %s", ErrDriverUnavailable, driver.EffectiveReviewStatus())
+	case !driver.IsAvailable():
+		return fmt.Errorf("%w: driver is %s", ErrDriverUnavailable, driver.EffectiveStatus())
+	case trip.FleetID != "" && driver.FleetID != trip.FleetID:
+		return fmt.Errorf("%w: driver is not in fleet %s", ErrValidationFailed, trip.FleetID)
+	case trip.TaxiType != "" && driver.TaxiType != trip.TaxiType:
+		return fmt.Errorf("%w: trip needs a %s taxi", ErrValidationFailed, trip.TaxiType)
+	}
+	return nil
+}
+
+// UpdateStatus moves the trip along its lifecycle. When it completes or is
+// cancelled with a driver assigned, the driver becomes available again,
+// unless they have gone offline meanwhile. A completed trip gets its rating
+// prompts scheduled.
+// A completed trip's fare is booked to the driver's earnings.
+func (s *tripService) UpdateStatus(ctx context.Context, id string, req *models.UpdateTripStatusRequest) (*models.Trip, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+
+	trip, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !models.CanTransitionTrip(trip.Status, req.Status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTripTransition, trip.Status, req.Status)
+	}
+
+	// Moving on from a pending offer accepts it
+	trip.MarkStatus(req.Status, s.now())
+	switch req.Status {
+	case models.TripCancelled:
+		trip.CancelReason = req.Reason
+	case models.TripCompleted:
+		trip.Fare = req.Fare
+	}
+	if err := s.tripRepo.Update(ctx, trip); err != nil {
+		return nil, s.updateError(err, req.Status)
+	}
+
+	if trip.DriverID != "" && !trip.Active {
+		s.releaseDriver(ctx, trip.DriverID, trip)
+	}
+	if trip.Status == models.TripCompleted && s.onboarding != nil {
+		s.onboarding.Record(ctx, trip.DriverID, models.OnboardingFirstTrip)
+	}
+	if trip.Status == models.TripCompleted && s.prompts != nil {
+		if err := s.prompts.Schedule(ctx, trip); err != nil {
+			logging.FromContext(ctx).Error("Failed to schedule rating prompts", "trip_id", trip.ID.Hex(), "error", err)
+		}
+	}
+
+	if trip.Status == models.TripCompleted && s.earnings != nil {
+		s.earnings.RecordTrip(ctx, trip)
+	}
+
+	return trip, nil
+}
+
+// Dispatch offers a created trip to the best candidate. If no candidate can
+// be reserved, it fails with ErrNoDriversAvailable and the trip stays
+// created.
+func (s *tripService) Dispatch(ctx context.Context, id string) (*models.Trip, error) {
+	if s.matcher == nil {
+		return nil, ErrMatchingDisabled
+	}
+
+	trip, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if trip.Status != models.TripCreated {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTripTransition, trip.Status, models.TripDriverAssigned)
+	}
+
+	return s.offerNext(ctx, trip)
+}
+
+// offerNext reserves the best candidate who can still be reserved for the
+// created trip and sends them the offer. Candidates taken by another trip
+// meanwhile are skipped.
+func (s *tripService) offerNext(ctx context.Context, trip *models.Trip) (*models.Trip, error) {
+	if s.matcher.Exhausted(trip) {
+		return nil, fmt.Errorf("%w: trip was declined by %d drivers", ErrNoDriversAvailable, len(trip.DeclinedBy))
+	}
+
+	candidates, strategy, err := s.matcher.Candidates(ctx, trip)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		if checkAssignable(trip, &candidate.Driver) != nil {
+			continue
+		}
+
+		now := s.now()
+		offered := *trip
+		offered.DriverID = candidate.ID.Hex()
+		offered.Offer = s.matcher.NewOffer(candidate, strategy, now)
+		offered.MarkStatus(models.TripDriverAssigned, now)
+		err := s.tripRepo.Update(ctx, &offered)
+		if errors.Is(err, repository.ErrTripDriverBusy) {
+			continue
+		}
+		if err != nil {
+			return nil, s.updateError(err, models.TripDriverAssigned)
+		}
+
+		*trip = offered
+		s.reserveDriver(ctx, trip)
+		s.matcher.Offered(ctx, trip, strategy)
+		return trip, nil
+	}
+
+	return nil, ErrNoDriversAvailable
+}
+
+func (s *tripService) AcceptOffer(ctx context.Context, id, driverID string) (*models.Trip, error) {
+	trip, err := s.pendingOffer(ctx, id, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	trip.Offer = nil
+	trip.UpdatedAt = s.now()
+	if err := s.tripRepo.Update(ctx, trip); err != nil {
+		return nil, s.updateError(err, models.TripDriverAssigned)
+	}
+
+	return trip, nil
+}
+
+// DeclineOffer releases the driver and offers the trip to the next
+// candidate. The trip is returned as it is afterwards: offered to someone
+// else, or created again if nobody was left.
+func (s *tripService) DeclineOffer(ctx context.Context, id, driverID string) (*models.Trip, error) {
+	trip, err := s.pendingOffer(ctx, id, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.withdrawOffer(ctx, trip); err != nil {
+		return nil, err
+	}
+	return trip, nil
+}
+
+func (s *tripService) ExpireOffers(ctx context.Context) (int, error) {
+	trips, err := s.tripRepo.FindExpiredOffers(ctx, s.now(), expiredOfferBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for i := range trips {
+		trip := &trips[i]
+		if err := s.withdrawOffer(ctx, trip); err != nil {
+			// The driver accepted or was released meanwhile
+			if errors.Is(err, ErrInvalidTripTransition) {
+				continue
+			}
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+func (s *tripService) UpdatePreferences(ctx context.Context, id string, req *models.RidePreferences) (*models.Trip, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+	}
+
+	trip, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if trip.Status != models.TripCreated {
+		return nil, fmt.Errorf("%w: trip is %s", ErrTripPreferencesLocked, trip.Status)
+	}
+
+	trip.Preferences = ridePreferences(req)
+	trip.UpdatedAt = s.now()
+	if err := s.tripRepo.Update(ctx, trip); err != nil {
+		if errors.Is(err, repository.ErrTripConflict) {
+			return nil, fmt.Errorf("%w: trip changed meanwhile", ErrTripPreferencesLocked)
+		}
+		return nil, err
+	}
+	return trip, nil
+}
+
+// AcknowledgePreferences is idempotent: acknowledging again keeps the
+// first acknowledgement.
+func (s *tripService) AcknowledgePreferences(ctx context.Context, id, driverID string) (*models.Trip, error) {
+	trip, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if trip.DriverID == "" || !models.IsTripActive(trip.Status) {
+		return nil, fmt.Errorf("%w: trip is %s", ErrTripNotAssigned, trip.Status)
+	}
+	if driverID != "" && trip.DriverID != driverID {
+		return nil, ErrTripNotFound
+	}
+	if trip.PreferencesAck != nil && trip.PreferencesAck.DriverID == trip.DriverID {
+		return trip, nil
+	}
+
+	now := s.now()
+	trip.PreferencesAck = &models.PreferencesAck{DriverID: trip.DriverID, AcknowledgedAt: now}
+	trip.UpdatedAt = now
+	if err := s.tripRepo.Update(ctx, trip); err != nil {
+		return nil, s.updateError(err, trip.Status)
+	}
+	return trip, nil
+}
+
+// savedPreferences returns the rider's saved preferences, or nil if they
+// have none. A failed lookup is logged rather than failing the booking.
+func (s *tripService) savedPreferences(ctx context.Context, riderID string) *models.RidePreferences {
+	saved, err := s.riders.Get(ctx, riderID)
+	if err != nil {
+		if !errors.Is(err, ErrRiderPreferencesNotFound) {
+			logging.FromContext(ctx).Warn("Failed to load rider preferences", "rider_id", riderID, "error", err)
+		}
+		return nil
+	}
+	return ridePreferences(&saved.RidePreferences)
+}
+
+// ridePreferences copies preferences onto a trip, leaving out empty ones.
+func ridePreferences(preferences *models.RidePreferences) *models.RidePreferences {
+	if preferences.IsZero() {
+		return nil
+	}
+	copied := *preferences
+	return &copied
+}
+
+// pendingOffer loads the trip with its pending offer, made to driverID
+// unless that is empty. Offers past their expiry can no longer be answered.
+func (s *tripService) pendingOffer(ctx context.Context, id, driverID string) (*models.Trip, error) {
+	trip, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !trip.HasPendingOffer() || (driverID != "" && trip.Offer.DriverID != driverID) {
+		return nil, ErrNoPendingOffer
+	}
+	if !s.now().Before(trip.Offer.ExpiresAt) {
+		return nil, fmt.Errorf("%w: offer expired at %s", ErrNoPendingOffer, trip.Offer.ExpiresAt.Format(time.RFC3339))
+	}
+	return trip, nil
+}
+
+// withdrawOffer puts the trip back to created without its driver, who is
+// released and not offered the trip again, then offers it to the next
+// candidate. Running out of candidates leaves the trip created.
+func (s *tripService) withdrawOffer(ctx context.Context, trip *models.Trip) error {
+	driverID := trip.DriverID
+	trip.DeclinedBy = append(trip.DeclinedBy, driverID)
+	trip.DriverID = ""
+	trip.AssignedAt = nil
+	trip.PreferencesAck = nil
+	trip.MarkStatus(models.TripCreated, s.now())
+	if err := s.tripRepo.Update(ctx, trip); err != nil {
+		return s.updateError(err, models.TripCreated)
+	}
+	s.releaseDriver(ctx, driverID, trip)
+
+	if _, err := s.offerNext(ctx, trip); err != nil {
+		logging.FromContext(ctx).Warn("Trip was not offered to another driver", "trip_id", trip.ID.Hex(), "error", err)
+	}
+	return nil
+}
+
+// reserveDriver marks the trip's driver busy. The trip holds the driver
+// already; a failed status change only leaves them listed as available
+// until it is corrected.
+func (s *tripService) reserveDriver(ctx context.Context, trip *models.Trip) {
+	if err := s.statuses.SetStatus(ctx, trip.DriverID, models.DriverStatusBusy); err != nil {
+		logging.FromContext(ctx).Error("Failed to mark driver busy", "driver_id", trip.DriverID, "trip_id", trip.ID.Hex(), "error", err)
+	}
+}
+
+// releaseDriver makes a driver available again after the trip if they are
+// still busy. Failures are logged, as the trip has already been stored.
+func (s *tripService) releaseDriver(ctx context.Context, driverID string, trip *models.Trip) {
+	driver, err := s.driverRepo.FindByID(ctx, driverID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to find driver to release from trip", "driver_id", driverID, "trip_id", trip.ID.Hex(), "error", err)
+		return
+	}
+	if driver.EffectiveStatus() != models.DriverStatusBusy {
+		return
+	}
+	if err := s.statuses.SetStatus(ctx, driverID, models.DriverStatusAvailable); err != nil {
+		logging.FromContext(ctx).Error("Failed to mark driver available after trip", "driver_id", driverID, "trip_id", trip.ID.Hex(), "error", err)
+	}
+}
+
+func (s *tripService) updateError(err error, to string) error {
+	switch {
+	case errors.Is(err, repository.ErrTripConflict):
+		return fmt.Errorf("%w: trip changed before it could move to %s", ErrInvalidTripTransition, to)
+	case errors.Is(err, repository.ErrTripDriverBusy):
+		return ErrDriverOnTrip
+	default:
+		return err
+	}
+}
@@ -0,0 +1,48 @@
+package slo
+
+import "time"
+
+type bucket struct {
+	start time.Time
+	total int64
+	bad   int64
+}
+
+// ring is a fixed-size set of time buckets; a bucket is reused once its
+// slot comes around again, so only the most recent size*width is retained.
+type ring struct {
+	width   time.Duration
+	buckets []bucket
+}
+
+func newRing(width time.Duration, size int) *ring {
+	return &ring{
+		width:   width,
+		buckets: make([]bucket, size),
+	}
+}
+
+func (r *ring) add(at time.Time, bad bool) {
+	start := at.Truncate(r.width)
+	b := &r.buckets[int(start.UnixNano()/int64(r.width))%len(r.buckets)]
+	if !b.start.Equal(start) {
+		*b = bucket{start: start}
+	}
+
+	b.total++
+	if bad {
+		b.bad++
+	}
+}
+
+func (r *ring) sum(now time.Time, window time.Duration) (total, bad int64) {
+	cutoff := now.Add(-window)
+	for _, b := range r.buckets {
+		if b.total == 0 || !b.start.Add(r.width).After(cutoff) {
+			continue
+		}
+		total += b.total
+		bad += b.bad
+	}
+	return total, bad
+}
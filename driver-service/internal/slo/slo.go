@@ -0,0 +1,196 @@
+package slo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/alerting"
+)
+
+const (
+	KindAvailability = "availability"
+	KindLatency      = "latency"
+
+	burnRateWindow = time.Hour
+	alertCooldown  = time.Hour
+)
+
+type Objective struct {
+	Name   string
+	Kind   string
+	Target float64
+	// Route limits the objective to one route pattern; empty means all.
+	Route string
+	// LatencyThreshold is the slowest response still counted as good for
+	// latency objectives.
+	LatencyThreshold time.Duration
+}
+
+type Status struct {
+	Name                 string     `json:"name"`
+	Kind                 string     `json:"kind"`
+	Route                string     `json:"route,omitempty"`
+	Target               float64    `json:"target"`
+	Window               string     `json:"window"`
+	TotalEvents          int64      `json:"total_events"`
+	BadEvents            int64      `json:"bad_events"`
+	Compliance           float64    `json:"compliance"`
+	ErrorBudgetRemaining float64    `json:"error_budget_remaining"`
+	BurnRate             float64    `json:"burn_rate_1h"`
+	ProjectedExhaustion  *time.Time `json:"projected_exhaustion,omitempty"`
+}
+
+type objectiveState struct {
+	objective Objective
+	long      *ring
+	short     *ring
+	lastAlert time.Time
+}
+
+// Tracker records request outcomes against the configured objectives and
+// derives compliance, remaining error budget and burn rate from them.
+type Tracker struct {
+	window time.Duration
+	states []*objectiveState
+	mu     sync.Mutex
+	now    func() time.Time
+}
+
+func NewTracker(window time.Duration, objectives []Objective) *Tracker {
+	hours := int(window/time.Hour) + 1
+	tracker := &Tracker{
+		window: window,
+		now:    time.Now,
+	}
+	for _, objective := range objectives {
+		tracker.states = append(tracker.states, &objectiveState{
+			objective: objective,
+			long:      newRing(time.Hour, hours),
+			short:     newRing(time.Minute, int(burnRateWindow/time.Minute)+1),
+		})
+	}
+	return tracker
+}
+
+func (t *Tracker) Record(route string, status int, latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	for _, state := range t.states {
+		objective := state.objective
+		if objective.Route != "" && objective.Route != route {
+			continue
+		}
+
+		var bad bool
+		switch objective.Kind {
+		case KindAvailability:
+			bad = status >= 500
+		case KindLatency:
+			bad = latency > objective.LatencyThreshold
+		}
+
+		state.long.add(now, bad)
+		state.short.add(now, bad)
+	}
+}
+
+func (t *Tracker) Report() []Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	report := make([]Status, 0, len(t.states))
+	for _, state := range t.states {
+		report = append(report, t.status(state, now))
+	}
+	return report
+}
+
+// EvaluateAlerts fires an alert for every objective whose error budget is
+// projected to run out within horizon at the current burn rate.
+func (t *Tracker) EvaluateAlerts(ctx context.Context, notifier alerting.Notifier, horizon time.Duration) error {
+	t.mu.Lock()
+	now := t.now()
+	var alerts []alerting.Alert
+	for _, state := range t.states {
+		status := t.status(state, now)
+		if status.ProjectedExhaustion == nil || status.ProjectedExhaustion.Sub(now) > horizon {
+			continue
+		}
+		if now.Sub(state.lastAlert) < alertCooldown {
+			continue
+		}
+		state.lastAlert = now
+
+		severity := alerting.SeverityWarning
+		if status.ErrorBudgetRemaining <= 0 {
+			severity = alerting.SeverityCritical
+		}
+		alerts = append(alerts, alerting.Alert{
+			Name:     "slo_budget_burn:" + status.Name,
+			Severity: severity,
+			Message: fmt.Sprintf("error budget for %s projected to exhaust at %s (burn rate %.1fx)",
+				status.Name, status.ProjectedExhaustion.Format(time.RFC3339), status.BurnRate),
+			Details: map[string]interface{}{
+				"target":                 status.Target,
+				"compliance":             status.Compliance,
+				"error_budget_remaining": status.ErrorBudgetRemaining,
+				"burn_rate_1h":           status.BurnRate,
+			},
+			FiredAt: now,
+		})
+	}
+	t.mu.Unlock()
+
+	for _, alert := range alerts {
+		if err := notifier.Notify(ctx, alert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Tracker) status(state *objectiveState, now time.Time) Status {
+	objective := state.objective
+	allowedBadRatio := 1 - objective.Target
+
+	total, bad := state.long.sum(now, t.window)
+	status := Status{
+		Name:                 objective.Name,
+		Kind:                 objective.Kind,
+		Route:                objective.Route,
+		Target:               objective.Target,
+		Window:               t.window.String(),
+		TotalEvents:          total,
+		BadEvents:            bad,
+		Compliance:           1,
+		ErrorBudgetRemaining: 1,
+	}
+	if total > 0 {
+		status.Compliance = 1 - float64(bad)/float64(total)
+		if allowedBadRatio > 0 {
+			status.ErrorBudgetRemaining = 1 - (float64(bad)/float64(total))/allowedBadRatio
+		}
+	}
+
+	shortTotal, shortBad := state.short.sum(now, burnRateWindow)
+	if shortTotal > 0 && allowedBadRatio > 0 {
+		status.BurnRate = (float64(shortBad) / float64(shortTotal)) / allowedBadRatio
+	}
+
+	// A burn rate of 1 spends the whole budget in exactly one window.
+	if status.BurnRate > 0 {
+		remaining := status.ErrorBudgetRemaining
+		if remaining < 0 {
+			remaining = 0
+		}
+		exhaustion := now.Add(time.Duration(remaining / status.BurnRate * float64(t.window)))
+		status.ProjectedExhaustion = &exhaustion
+	}
+
+	return status
+}
@@ -0,0 +1,99 @@
+// Package storage keeps binary objects such as photos outside MongoDB, on
+// local disk or in an S3-compatible bucket (AWS S3, MinIO, or Google Cloud
+// Storage through its S3-compatible XML API).
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound           = errors.New("object not found")
+	ErrInvalidKey         = errors.New("invalid object key")
+	ErrPresignUnsupported = errors.New("backend does not support presigned URLs")
+)
+
+// Store holds objects under slash-separated keys such as
+// "photos/<driver>/<photo>".
+type Store interface {
+	// Put streams size bytes from body to key, replacing any object there.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get opens the object; the caller closes it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// PresignGet returns a URL that fetches the object without credentials
+	// until ttl passes.
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+type Config struct {
+	// Backend is none, local, s3, minio or gcs.
+	Backend  string
+	LocalDir string
+
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle addresses objects as endpoint/bucket/key instead of
+	// bucket.endpoint/key. MinIO and GCS always use it.
+	PathStyle bool
+}
+
+// New returns the configured store, or nil when Backend is none.
+func New(cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case "", "none":
+		return nil, nil
+	case "local":
+		if cfg.LocalDir == "" {
+			return nil, errors.New("local storage backend requires STORAGE_LOCAL_DIR")
+		}
+		return NewLocalStore(cfg.LocalDir)
+	case "s3":
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+		}
+		return newS3Store(cfg)
+	case "minio":
+		if cfg.Endpoint == "" {
+			return nil, errors.New("minio storage backend requires STORAGE_ENDPOINT")
+		}
+		cfg.PathStyle = true
+		return newS3Store(cfg)
+	case "gcs":
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = "https://storage.googleapis.com"
+		}
+		// GCS accepts any region in the signature scope; "auto" is what its
+		// documentation uses.
+		if cfg.Region == "" || cfg.Region == "us-east-1" {
+			cfg.Region = "auto"
+		}
+		cfg.PathStyle = true
+		return newS3Store(cfg)
+	default:
+		return nil, errors.New("unknown storage backend: " + cfg.Backend)
+	}
+}
+
+// validateKey rejects keys that could escape the bucket prefix or the local
+// root directory.
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
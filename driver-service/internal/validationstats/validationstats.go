@@ -0,0 +1,206 @@
+// Package validationstats counts rejected request fields by endpoint and
+// validation rule, so client bugs (an app that always sends plates in the
+// wrong format) and confusing API fields show up without digging through
+// logs.
+package validationstats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// RuleOther is recorded for failures that do not come from a validation tag,
+// such as a malformed body or a check done in code.
+const RuleOther = "other"
+
+// Failure is one field failing one rule.
+type Failure struct {
+	Field string
+	Rule  string
+}
+
+var sliceIndex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// Failures lists the field and rule behind each validator error in err. Other
+// errors yield a single failure with no field and RuleOther.
+func Failures(err error) []Failure {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return []Failure{{Rule: RuleOther}}
+	}
+
+	failures := make([]Failure, 0, len(validationErrs))
+	for _, e := range validationErrs {
+		failures = append(failures, Failure{Field: fieldPath(e), Rule: e.Tag()})
+	}
+	return failures
+}
+
+// fieldPath turns "BatchLocationRequest.Points[3].Lat" into "points.lat": the
+// struct name is dropped and slice indexes are collapsed so every element
+// counts towards the same field.
+func fieldPath(e validator.FieldError) string {
+	path := e.Namespace()
+	if _, rest, ok := strings.Cut(path, "."); ok {
+		path = rest
+	}
+	return strings.ToLower(sliceIndex.ReplaceAllString(path, ""))
+}
+
+// Count is how often a field failed a rule on one endpoint.
+type Count struct {
+	Method   string    `json:"method"`
+	Route    string    `json:"route"`
+	Field    string    `json:"field"`
+	Rule     string    `json:"rule"`
+	Count    int64     `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Endpoint counts the requests an endpoint rejected as invalid.
+type Endpoint struct {
+	Method   string    `json:"method"`
+	Route    string    `json:"route"`
+	Requests int64     `json:"requests"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+type endpointKey struct {
+	method string
+	route  string
+}
+
+type countKey struct {
+	endpointKey
+	field string
+	rule  string
+}
+
+// Tracker holds the validation failures seen since startup. Routes are the
+// registered patterns and fields come from request structs, so the number of
+// counters stays bounded.
+type Tracker struct {
+	mu        sync.Mutex
+	counts    map[countKey]*Count
+	endpoints map[endpointKey]*Endpoint
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		counts:    make(map[countKey]*Count),
+		endpoints: make(map[endpointKey]*Endpoint),
+	}
+}
+
+// Record counts one rejected request and each of its failures.
+func (t *Tracker) Record(method, route string, failures []Failure, at time.Time) {
+	endpoint := endpointKey{method: method, route: route}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	summary, ok := t.endpoints[endpoint]
+	if !ok {
+		summary = &Endpoint{Method: method, Route: route}
+		t.endpoints[endpoint] = summary
+	}
+	summary.Requests++
+	summary.LastSeen = at
+
+	for _, failure := range failures {
+		key := countKey{endpointKey: endpoint, field: failure.Field, rule: failure.Rule}
+		count, ok := t.counts[key]
+		if !ok {
+			count = &Count{Method: method, Route: route, Field: failure.Field, Rule: failure.Rule}
+			t.counts[key] = count
+		}
+		count.Count++
+		count.LastSeen = at
+	}
+}
+
+// Report returns the failure counts, most frequent first.
+func (t *Tracker) Report() []Count {
+	t.mu.Lock()
+	report := make([]Count, 0, len(t.counts))
+	for _, count := range t.counts {
+		report = append(report, *count)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		return a.Rule < b.Rule
+	})
+	return report
+}
+
+// Endpoints returns the rejected request totals, most rejected first.
+func (t *Tracker) Endpoints() []Endpoint {
+	t.mu.Lock()
+	endpoints := make([]Endpoint, 0, len(t.endpoints))
+	for _, endpoint := range t.endpoints {
+		endpoints = append(endpoints, *endpoint)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		a, b := endpoints[i], endpoints[j]
+		if a.Requests != b.Requests {
+			return a.Requests > b.Requests
+		}
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		return a.Method < b.Method
+	})
+	return endpoints
+}
+
+// WriteMetrics writes the counters in the Prometheus text exposition format.
+func (t *Tracker) WriteMetrics(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# HELP driver_service_validation_rejected_requests_total Requests rejected by validation.\n")
+	b.WriteString("# TYPE driver_service_validation_rejected_requests_total counter\n")
+	for _, endpoint := range t.Endpoints() {
+		fmt.Fprintf(&b, "driver_service_validation_rejected_requests_total{method=%s,route=%s} %d\n",
+			labelValue(endpoint.Method), labelValue(endpoint.Route), endpoint.Requests)
+	}
+
+	b.WriteString("# HELP driver_service_validation_failures_total Request fields that failed a validation rule.\n")
+	b.WriteString("# TYPE driver_service_validation_failures_total counter\n")
+	for _, count := range t.Report() {
+		fmt.Fprintf(&b, "driver_service_validation_failures_total{method=%s,route=%s,field=%s,rule=%s} %d\n",
+			labelValue(count.Method), labelValue(count.Route), labelValue(count.Field), labelValue(count.Rule), count.Count)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labelValue(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,205 @@
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/taxihub/driver-service/internal/alerting"
+)
+
+// Worker is a long-running goroutine supervised by the watchdog. It must call
+// heartbeat regularly and return once ctx is cancelled.
+type Worker func(ctx context.Context, heartbeat func())
+
+type WorkerStatus struct {
+	Name          string     `json:"name"`
+	Healthy       bool       `json:"healthy"`
+	LastHeartbeat time.Time  `json:"last_heartbeat"`
+	Timeout       string     `json:"timeout"`
+	Restarts      int        `json:"restarts"`
+	LastRestartAt *time.Time `json:"last_restart_at,omitempty"`
+	LastIncident  string     `json:"last_incident,omitempty"`
+}
+
+type worker struct {
+	name          string
+	run           Worker
+	timeout       time.Duration
+	generation    int
+	cancel        context.CancelFunc
+	lastHeartbeat time.Time
+	restarts      int
+	lastRestartAt *time.Time
+	lastIncident  string
+}
+
+// Watchdog restarts workers that stop heartbeating or exit unexpectedly.
+// A stuck goroutine cannot be killed, so a restart cancels its context and
+// starts a fresh instance; heartbeats from the abandoned instance are ignored.
+type Watchdog struct {
+	notifier      alerting.Notifier
+	checkInterval time.Duration
+	now           func() time.Time
+
+	mu      sync.Mutex
+	ctx     context.Context
+	workers map[string]*worker
+}
+
+func New(notifier alerting.Notifier, checkInterval time.Duration) *Watchdog {
+	return &Watchdog{
+		notifier:      notifier,
+		checkInterval: checkInterval,
+		now:           time.Now,
+		workers:       make(map[string]*worker),
+	}
+}
+
+// Register adds a worker that is considered stuck when it has not sent a
+// heartbeat for timeout. Workers registered after Run are started right away.
+func (w *Watchdog) Register(name string, timeout time.Duration, run Worker) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wk := &worker{
+		name:    name,
+		run:     run,
+		timeout: timeout,
+	}
+	w.workers[name] = wk
+	if w.ctx != nil {
+		w.start(wk)
+	}
+}
+
+// Run starts all registered workers and supervises them until ctx is
+// cancelled.
+func (w *Watchdog) Run(ctx context.Context) {
+	w.mu.Lock()
+	w.ctx = ctx
+	for _, wk := range w.workers {
+		w.start(wk)
+	}
+	w.mu.Unlock()
+
+	ticker := time.NewTicker(w.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+func (w *Watchdog) Status() []WorkerStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	statuses := make([]WorkerStatus, 0, len(w.workers))
+	for _, wk := range w.workers {
+		statuses = append(statuses, WorkerStatus{
+			Name:          wk.name,
+			Healthy:       now.Sub(wk.lastHeartbeat) <= wk.timeout,
+			LastHeartbeat: wk.lastHeartbeat,
+			Timeout:       wk.timeout.String(),
+			Restarts:      wk.restarts,
+			LastRestartAt: wk.lastRestartAt,
+			LastIncident:  wk.lastIncident,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// start launches a new instance of wk. Callers must hold w.mu.
+func (w *Watchdog) start(wk *worker) {
+	ctx, cancel := context.WithCancel(w.ctx)
+	wk.generation++
+	wk.cancel = cancel
+	wk.lastHeartbeat = w.now()
+
+	generation := wk.generation
+	heartbeat := func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if wk.generation == generation {
+			wk.lastHeartbeat = w.now()
+		}
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				w.exited(wk, generation, fmt.Sprintf("panic: %v", r))
+				return
+			}
+			if ctx.Err() == nil {
+				w.exited(wk, generation, "exited unexpectedly")
+			}
+		}()
+		wk.run(ctx, heartbeat)
+	}()
+}
+
+func (w *Watchdog) exited(wk *worker, generation int, reason string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if wk.generation != generation || w.ctx.Err() != nil {
+		return
+	}
+	w.restart(wk, reason)
+}
+
+func (w *Watchdog) check() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	for _, wk := range w.workers {
+		if silence := now.Sub(wk.lastHeartbeat); silence > wk.timeout {
+			w.restart(wk, fmt.Sprintf("no heartbeat for %s", silence.Round(time.Second)))
+		}
+	}
+}
+
+// restart replaces wk with a fresh instance. Callers must hold w.mu.
+func (w *Watchdog) restart(wk *worker, reason string) {
+	wk.cancel()
+
+	now := w.now()
+	wk.restarts++
+	wk.lastRestartAt = &now
+	wk.lastIncident = reason
+	slog.Warn("Watchdog: restarting worker", "worker", wk.name, "reason", reason, "restarts", wk.restarts)
+
+	alert := alerting.Alert{
+		Name:     "watchdog_restart:" + wk.name,
+		Severity: alerting.SeverityWarning,
+		Message:  fmt.Sprintf("%s restarted by watchdog: %s", wk.name, reason),
+		Details: map[string]interface{}{
+			"restarts": wk.restarts,
+		},
+		FiredAt: now,
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := w.notifier.Notify(ctx, alert); err != nil {
+			slog.Error("Watchdog: failed to send alert", "alert", alert.Name, "error", err)
+		}
+	}()
+
+	w.start(wk)
+}